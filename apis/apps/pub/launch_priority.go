@@ -33,4 +33,9 @@ const (
 	// ContainerLaunchPriorityCompletedKey is the annotation indicates the pod has all its priorities
 	// patched into its barrier configmap.
 	ContainerLaunchPriorityCompletedKey = "apps.kruise.io/container-launch-priority-completed"
+
+	// ContainerLaunchPriorityTimeoutSecondsKey is the annotation key that users could define in pod annotation
+	// to release the containers of the next priority, if the containers released before are not ready within
+	// the timeout seconds. Zero or absent waits for them to be ready forever.
+	ContainerLaunchPriorityTimeoutSecondsKey = "apps.kruise.io/container-launch-priority-timeout-seconds"
 )
@@ -37,6 +37,7 @@ func (acj *AdvancedCronJob) ConvertTo(dst conversion.Hub) error {
 			StartingDeadlineSeconds:    acj.Spec.StartingDeadlineSeconds,
 			ConcurrencyPolicy:          v1beta1.ConcurrencyPolicy(acj.Spec.ConcurrencyPolicy),
 			Paused:                     acj.Spec.Paused,
+			SuspendUntil:               acj.Spec.SuspendUntil,
 			SuccessfulJobsHistoryLimit: acj.Spec.SuccessfulJobsHistoryLimit,
 			FailedJobsHistoryLimit:     acj.Spec.FailedJobsHistoryLimit,
 			Template: v1beta1.CronJobTemplate{
@@ -76,6 +77,7 @@ func (acj *AdvancedCronJob) ConvertFrom(src conversion.Hub) error {
 			StartingDeadlineSeconds:    acjv1beta1.Spec.StartingDeadlineSeconds,
 			ConcurrencyPolicy:          ConcurrencyPolicy(acjv1beta1.Spec.ConcurrencyPolicy),
 			Paused:                     acjv1beta1.Spec.Paused,
+			SuspendUntil:               acjv1beta1.Spec.SuspendUntil,
 			SuccessfulJobsHistoryLimit: acjv1beta1.Spec.SuccessfulJobsHistoryLimit,
 			FailedJobsHistoryLimit:     acjv1beta1.Spec.FailedJobsHistoryLimit,
 			Template: CronJobTemplate{
@@ -125,6 +127,8 @@ func convertBroadcastJobSpecToV1Beta1(spec BroadcastJobSpec) v1beta1.BroadcastJo
 			Type:                    v1beta1.CompletionPolicyType(spec.CompletionPolicy.Type),
 			ActiveDeadlineSeconds:   spec.CompletionPolicy.ActiveDeadlineSeconds,
 			TTLSecondsAfterFinished: spec.CompletionPolicy.TTLSecondsAfterFinished,
+			FailedNodeLimit:         spec.CompletionPolicy.FailedNodeLimit,
+			FailedImageLimit:        spec.CompletionPolicy.FailedImageLimit,
 		},
 		Paused: spec.Paused,
 		FailurePolicy: v1beta1.FailurePolicy{
@@ -142,6 +146,8 @@ func convertBroadcastJobSpecToV1Alpha1(spec v1beta1.BroadcastJobSpec) BroadcastJ
 			Type:                    CompletionPolicyType(spec.CompletionPolicy.Type),
 			ActiveDeadlineSeconds:   spec.CompletionPolicy.ActiveDeadlineSeconds,
 			TTLSecondsAfterFinished: spec.CompletionPolicy.TTLSecondsAfterFinished,
+			FailedNodeLimit:         spec.CompletionPolicy.FailedNodeLimit,
+			FailedImageLimit:        spec.CompletionPolicy.FailedImageLimit,
 		},
 		Paused: spec.Paused,
 		FailurePolicy: FailurePolicy{
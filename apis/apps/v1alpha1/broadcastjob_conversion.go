@@ -38,6 +38,8 @@ func (bj *BroadcastJob) ConvertTo(dst conversion.Hub) error {
 				Type:                    v1beta1.CompletionPolicyType(bj.Spec.CompletionPolicy.Type),
 				ActiveDeadlineSeconds:   bj.Spec.CompletionPolicy.ActiveDeadlineSeconds,
 				TTLSecondsAfterFinished: bj.Spec.CompletionPolicy.TTLSecondsAfterFinished,
+				FailedNodeLimit:         bj.Spec.CompletionPolicy.FailedNodeLimit,
+				FailedImageLimit:        bj.Spec.CompletionPolicy.FailedImageLimit,
 			},
 			Paused: bj.Spec.Paused,
 			FailurePolicy: v1beta1.FailurePolicy{
@@ -48,14 +50,15 @@ func (bj *BroadcastJob) ConvertTo(dst conversion.Hub) error {
 
 		// status
 		bjv1beta1.Status = v1beta1.BroadcastJobStatus{
-			Conditions:     convertJobConditionsToV1Beta1(bj.Status.Conditions),
-			StartTime:      bj.Status.StartTime,
-			CompletionTime: bj.Status.CompletionTime,
-			Active:         bj.Status.Active,
-			Succeeded:      bj.Status.Succeeded,
-			Failed:         bj.Status.Failed,
-			Desired:        bj.Status.Desired,
-			Phase:          v1beta1.BroadcastJobPhase(bj.Status.Phase),
+			Conditions:      convertJobConditionsToV1Beta1(bj.Status.Conditions),
+			StartTime:       bj.Status.StartTime,
+			CompletionTime:  bj.Status.CompletionTime,
+			Active:          bj.Status.Active,
+			Succeeded:       bj.Status.Succeeded,
+			Failed:          bj.Status.Failed,
+			Desired:         bj.Status.Desired,
+			ProgressPercent: bj.Status.ProgressPercent,
+			Phase:           v1beta1.BroadcastJobPhase(bj.Status.Phase),
 		}
 
 		return nil
@@ -79,6 +82,8 @@ func (bj *BroadcastJob) ConvertFrom(src conversion.Hub) error {
 				Type:                    CompletionPolicyType(bjv1beta1.Spec.CompletionPolicy.Type),
 				ActiveDeadlineSeconds:   bjv1beta1.Spec.CompletionPolicy.ActiveDeadlineSeconds,
 				TTLSecondsAfterFinished: bjv1beta1.Spec.CompletionPolicy.TTLSecondsAfterFinished,
+				FailedNodeLimit:         bjv1beta1.Spec.CompletionPolicy.FailedNodeLimit,
+				FailedImageLimit:        bjv1beta1.Spec.CompletionPolicy.FailedImageLimit,
 			},
 			Paused: bjv1beta1.Spec.Paused,
 			FailurePolicy: FailurePolicy{
@@ -89,14 +94,15 @@ func (bj *BroadcastJob) ConvertFrom(src conversion.Hub) error {
 
 		// status
 		bj.Status = BroadcastJobStatus{
-			Conditions:     convertJobConditionsToV1Alpha1(bjv1beta1.Status.Conditions),
-			StartTime:      bjv1beta1.Status.StartTime,
-			CompletionTime: bjv1beta1.Status.CompletionTime,
-			Active:         bjv1beta1.Status.Active,
-			Succeeded:      bjv1beta1.Status.Succeeded,
-			Failed:         bjv1beta1.Status.Failed,
-			Desired:        bjv1beta1.Status.Desired,
-			Phase:          BroadcastJobPhase(bjv1beta1.Status.Phase),
+			Conditions:      convertJobConditionsToV1Alpha1(bjv1beta1.Status.Conditions),
+			StartTime:       bjv1beta1.Status.StartTime,
+			CompletionTime:  bjv1beta1.Status.CompletionTime,
+			Active:          bjv1beta1.Status.Active,
+			Succeeded:       bjv1beta1.Status.Succeeded,
+			Failed:          bjv1beta1.Status.Failed,
+			Desired:         bjv1beta1.Status.Desired,
+			ProgressPercent: bjv1beta1.Status.ProgressPercent,
+			Phase:           BroadcastJobPhase(bjv1beta1.Status.Phase),
 		}
 
 		return nil
@@ -50,7 +50,9 @@ func (cs *CloneSet) ConvertTo(dst conversion.Hub) error {
 			PodsToDelete:   cs.Spec.ScaleStrategy.PodsToDelete,
 			MaxUnavailable: cs.Spec.ScaleStrategy.MaxUnavailable,
 			// Convert DisablePVCReuse (v1alpha1) to EnablePVCReuse (v1beta1) with inverted logic
-			EnablePVCReuse: !cs.Spec.ScaleStrategy.DisablePVCReuse,
+			EnablePVCReuse:    !cs.Spec.ScaleStrategy.DisablePVCReuse,
+			QuarantineSeconds: cs.Spec.ScaleStrategy.QuarantineSeconds,
+			Decommission:      convertCloneSetDecommissionToV1beta1(cs.Spec.ScaleStrategy.Decommission),
 		}
 
 		// Convert label to spec field for v1beta1
@@ -84,7 +86,15 @@ func (cs *CloneSet) ConvertTo(dst conversion.Hub) error {
 		}
 
 		csv1beta1.Spec.UpdateStrategy = v1beta1.CloneSetUpdateStrategy{
-			Type: strategyType,
+			Type:            strategyType,
+			HPACoordination: v1beta1.CloneSetHPACoordinationType(cs.Spec.UpdateStrategy.HPACoordination),
+
+			MinKubeletVersionForInPlaceUpdate: cs.Spec.UpdateStrategy.MinKubeletVersionForInPlaceUpdate,
+		}
+		if cs.Spec.UpdateStrategy.ReportPolicy != nil {
+			csv1beta1.Spec.UpdateStrategy.ReportPolicy = &v1beta1.CloneSetReportPolicy{
+				ConfigMapName: cs.Spec.UpdateStrategy.ReportPolicy.ConfigMapName,
+			}
 		}
 
 		// Only set RollingUpdate if it's not OnDelete
@@ -106,19 +116,24 @@ func (cs *CloneSet) ConvertTo(dst conversion.Hub) error {
 
 		// status
 		csv1beta1.Status = v1beta1.CloneSetStatus{
-			ObservedGeneration:       cs.Status.ObservedGeneration,
-			Replicas:                 cs.Status.Replicas,
-			ReadyReplicas:            cs.Status.ReadyReplicas,
-			AvailableReplicas:        cs.Status.AvailableReplicas,
-			UpdatedReplicas:          cs.Status.UpdatedReplicas,
-			UpdatedReadyReplicas:     cs.Status.UpdatedReadyReplicas,
-			UpdatedAvailableReplicas: cs.Status.UpdatedAvailableReplicas,
-			ExpectedUpdatedReplicas:  cs.Status.ExpectedUpdatedReplicas,
-			UpdateRevision:           cs.Status.UpdateRevision,
-			CurrentRevision:          cs.Status.CurrentRevision,
-			CollisionCount:           cs.Status.CollisionCount,
-			Conditions:               convertCloneSetConditionsToV1beta1(cs.Status.Conditions),
-			LabelSelector:            cs.Status.LabelSelector,
+			ObservedGeneration:           cs.Status.ObservedGeneration,
+			Replicas:                     cs.Status.Replicas,
+			ReadyReplicas:                cs.Status.ReadyReplicas,
+			AvailableReplicas:            cs.Status.AvailableReplicas,
+			UpdatedReplicas:              cs.Status.UpdatedReplicas,
+			UpdatedReadyReplicas:         cs.Status.UpdatedReadyReplicas,
+			UpdatedAvailableReplicas:     cs.Status.UpdatedAvailableReplicas,
+			ExpectedUpdatedReplicas:      cs.Status.ExpectedUpdatedReplicas,
+			ExpectedSurgeReplicas:        cs.Status.ExpectedSurgeReplicas,
+			KubeletVersionSkewedReplicas: cs.Status.KubeletVersionSkewedReplicas,
+			UpdateRevision:               cs.Status.UpdateRevision,
+			CurrentRevision:              cs.Status.CurrentRevision,
+			CollisionCount:               cs.Status.CollisionCount,
+			Conditions:                   convertCloneSetConditionsToV1beta1(cs.Status.Conditions),
+			LabelSelector:                cs.Status.LabelSelector,
+			RecreateReasons:              cs.Status.RecreateReasons,
+			DecommissionStatuses:         convertCloneSetDecommissionStatusesToV1beta1(cs.Status.DecommissionStatuses),
+			RolloutState:                 v1beta1.CloneSetRolloutState(cs.Status.RolloutState),
 		}
 
 		return nil
@@ -151,7 +166,9 @@ func (cs *CloneSet) ConvertFrom(src conversion.Hub) error {
 			PodsToDelete:   csv1beta1.Spec.ScaleStrategy.PodsToDelete,
 			MaxUnavailable: csv1beta1.Spec.ScaleStrategy.MaxUnavailable,
 			// Convert EnablePVCReuse (v1beta1) to DisablePVCReuse (v1alpha1) with inverted logic
-			DisablePVCReuse: !csv1beta1.Spec.ScaleStrategy.EnablePVCReuse,
+			DisablePVCReuse:   !csv1beta1.Spec.ScaleStrategy.EnablePVCReuse,
+			QuarantineSeconds: csv1beta1.Spec.ScaleStrategy.QuarantineSeconds,
+			Decommission:      convertCloneSetDecommissionFromV1beta1(csv1beta1.Spec.ScaleStrategy.Decommission),
 			// Note: v1beta1's ExcludePreparingDelete field is not converted back to v1alpha1
 			// because v1alpha1 uses label-based configuration only
 		}
@@ -181,7 +198,15 @@ func (cs *CloneSet) ConvertFrom(src conversion.Hub) error {
 		}
 
 		cs.Spec.UpdateStrategy = CloneSetUpdateStrategy{
-			Type: updateStrategyType,
+			Type:            updateStrategyType,
+			HPACoordination: CloneSetHPACoordinationType(csv1beta1.Spec.UpdateStrategy.HPACoordination),
+
+			MinKubeletVersionForInPlaceUpdate: csv1beta1.Spec.UpdateStrategy.MinKubeletVersionForInPlaceUpdate,
+		}
+		if csv1beta1.Spec.UpdateStrategy.ReportPolicy != nil {
+			cs.Spec.UpdateStrategy.ReportPolicy = &CloneSetReportPolicy{
+				ConfigMapName: csv1beta1.Spec.UpdateStrategy.ReportPolicy.ConfigMapName,
+			}
 		}
 
 		// Copy RollingUpdate fields if present
@@ -202,19 +227,24 @@ func (cs *CloneSet) ConvertFrom(src conversion.Hub) error {
 
 		// status
 		cs.Status = CloneSetStatus{
-			ObservedGeneration:       csv1beta1.Status.ObservedGeneration,
-			Replicas:                 csv1beta1.Status.Replicas,
-			ReadyReplicas:            csv1beta1.Status.ReadyReplicas,
-			AvailableReplicas:        csv1beta1.Status.AvailableReplicas,
-			UpdatedReplicas:          csv1beta1.Status.UpdatedReplicas,
-			UpdatedReadyReplicas:     csv1beta1.Status.UpdatedReadyReplicas,
-			UpdatedAvailableReplicas: csv1beta1.Status.UpdatedAvailableReplicas,
-			ExpectedUpdatedReplicas:  csv1beta1.Status.ExpectedUpdatedReplicas,
-			UpdateRevision:           csv1beta1.Status.UpdateRevision,
-			CurrentRevision:          csv1beta1.Status.CurrentRevision,
-			CollisionCount:           csv1beta1.Status.CollisionCount,
-			Conditions:               convertCloneSetConditionsFromV1beta1(csv1beta1.Status.Conditions),
-			LabelSelector:            csv1beta1.Status.LabelSelector,
+			ObservedGeneration:           csv1beta1.Status.ObservedGeneration,
+			Replicas:                     csv1beta1.Status.Replicas,
+			ReadyReplicas:                csv1beta1.Status.ReadyReplicas,
+			AvailableReplicas:            csv1beta1.Status.AvailableReplicas,
+			UpdatedReplicas:              csv1beta1.Status.UpdatedReplicas,
+			UpdatedReadyReplicas:         csv1beta1.Status.UpdatedReadyReplicas,
+			UpdatedAvailableReplicas:     csv1beta1.Status.UpdatedAvailableReplicas,
+			ExpectedUpdatedReplicas:      csv1beta1.Status.ExpectedUpdatedReplicas,
+			ExpectedSurgeReplicas:        csv1beta1.Status.ExpectedSurgeReplicas,
+			KubeletVersionSkewedReplicas: csv1beta1.Status.KubeletVersionSkewedReplicas,
+			UpdateRevision:               csv1beta1.Status.UpdateRevision,
+			CurrentRevision:              csv1beta1.Status.CurrentRevision,
+			CollisionCount:               csv1beta1.Status.CollisionCount,
+			Conditions:                   convertCloneSetConditionsFromV1beta1(csv1beta1.Status.Conditions),
+			LabelSelector:                csv1beta1.Status.LabelSelector,
+			RecreateReasons:              csv1beta1.Status.RecreateReasons,
+			DecommissionStatuses:         convertCloneSetDecommissionStatusesFromV1beta1(csv1beta1.Status.DecommissionStatuses),
+			RolloutState:                 CloneSetRolloutState(csv1beta1.Status.RolloutState),
 		}
 
 		return nil
@@ -288,6 +318,68 @@ func convertCloneSetConditionsFromV1beta1(src []v1beta1.CloneSetCondition) []Clo
 	return dst
 }
 
+func convertCloneSetDecommissionToV1beta1(src []CloneSetDecommission) []v1beta1.CloneSetDecommission {
+	if src == nil {
+		return nil
+	}
+	dst := make([]v1beta1.CloneSetDecommission, len(src))
+	for i, d := range src {
+		dst[i] = v1beta1.CloneSetDecommission{
+			PodName:                 d.PodName,
+			RequireFinalizerRemoval: d.RequireFinalizerRemoval,
+			TimeoutSeconds:          d.TimeoutSeconds,
+		}
+	}
+	return dst
+}
+
+func convertCloneSetDecommissionFromV1beta1(src []v1beta1.CloneSetDecommission) []CloneSetDecommission {
+	if src == nil {
+		return nil
+	}
+	dst := make([]CloneSetDecommission, len(src))
+	for i, d := range src {
+		dst[i] = CloneSetDecommission{
+			PodName:                 d.PodName,
+			RequireFinalizerRemoval: d.RequireFinalizerRemoval,
+			TimeoutSeconds:          d.TimeoutSeconds,
+		}
+	}
+	return dst
+}
+
+func convertCloneSetDecommissionStatusesToV1beta1(src []CloneSetDecommissionStatus) []v1beta1.CloneSetDecommissionStatus {
+	if src == nil {
+		return nil
+	}
+	dst := make([]v1beta1.CloneSetDecommissionStatus, len(src))
+	for i, s := range src {
+		dst[i] = v1beta1.CloneSetDecommissionStatus{
+			PodName:   s.PodName,
+			Phase:     v1beta1.CloneSetDecommissionPhase(s.Phase),
+			StartTime: s.StartTime,
+			Message:   s.Message,
+		}
+	}
+	return dst
+}
+
+func convertCloneSetDecommissionStatusesFromV1beta1(src []v1beta1.CloneSetDecommissionStatus) []CloneSetDecommissionStatus {
+	if src == nil {
+		return nil
+	}
+	dst := make([]CloneSetDecommissionStatus, len(src))
+	for i, s := range src {
+		dst[i] = CloneSetDecommissionStatus{
+			PodName:   s.PodName,
+			Phase:     CloneSetDecommissionPhase(s.Phase),
+			StartTime: s.StartTime,
+			Message:   s.Message,
+		}
+	}
+	return dst
+}
+
 // convertInPlaceUpdateStrategyToV1beta1 converts InPlaceUpdateStrategy from v1alpha1 to v1beta1,
 // reading image pre-download configuration from annotations and converting them to spec fields
 func convertInPlaceUpdateStrategyToV1beta1(src *appspub.InPlaceUpdateStrategy, annotations map[string]string) *appspub.InPlaceUpdateStrategy {
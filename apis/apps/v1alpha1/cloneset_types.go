@@ -35,6 +35,10 @@ const (
 	// CloneSetScalingExcludePreparingDeleteKey is the label key that enables scalingExcludePreparingDelete
 	// only for this CloneSet, which means it will calculate scale number excluding Pods in PreparingDelete state.
 	CloneSetScalingExcludePreparingDeleteKey = "apps.kruise.io/cloneset-scaling-exclude-preparing-delete"
+
+	// CloneSetExpectedSurgeReplicasAnnotation is the annotation written on CloneSet when updateStrategy.hpaCoordination
+	// is AnnotateDesired, its value is the number of surge Pods above the desired replicas during rollout.
+	CloneSetExpectedSurgeReplicasAnnotation = "apps.kruise.io/cloneset-expected-surge-replicas"
 )
 
 // CloneSetSpec defines the desired state of CloneSet
@@ -104,6 +108,42 @@ type CloneSetScaleStrategy struct {
 	// Indicate if cloneSet will reuse already existed pvc to
 	// rebuild a new pod
 	DisablePVCReuse bool `json:"disablePVCReuse,omitempty"`
+
+	// QuarantineSeconds is the duration that the pods chosen to scale in are quarantined before deleted.
+	// A quarantined pod is marked not ready and unavailable but kept running, so that its in-flight work
+	// can be drained. Quarantined pods are recovered first if the CloneSet scales out during the quarantine.
+	// Defaults to 0, which means the pods are deleted directly.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	QuarantineSeconds int32 `json:"quarantineSeconds,omitempty"`
+
+	// Decommission is the list of pods to be decommissioned before deleted. A decommissioned pod is marked
+	// not ready, goes through the PreDelete lifecycle hook, and waits for the finalizer set by the requester
+	// to be removed if required, then it is deleted. The pod is replaced by a new one once its decommission
+	// starts, so that the number of serving pods keeps the replicas.
+	// The progress of each entry can be found in status.decommissionStatuses.
+	// +optional
+	Decommission []CloneSetDecommission `json:"decommission,omitempty"`
+}
+
+// CloneSetDecommission is the request to decommission a specific pod before it is deleted.
+type CloneSetDecommission struct {
+	// PodName is the name of the pod to decommission.
+	PodName string `json:"podName"`
+
+	// RequireFinalizerRemoval indicates whether to wait for the finalizer apps.kruise.io/cloneset-decommission,
+	// which is set on the pod by the requester, to be removed before deleting the pod.
+	// It is usually removed by the requester after the data on the pod has been offloaded.
+	// +optional
+	RequireFinalizerRemoval bool `json:"requireFinalizerRemoval,omitempty"`
+
+	// TimeoutSeconds is the max duration to wait for the finalizer removal since the waiting started,
+	// which is after the PreDelete lifecycle hook if any. Once timed out, the finalizer is removed by
+	// the controller and the pod is deleted, as its replacement has been created already.
+	// Defaults to wait without timeout.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
 }
 
 // CloneSetUpdateStrategy defines strategies for pods update.
@@ -141,8 +181,43 @@ type CloneSetUpdateStrategy struct {
 	ScatterStrategy UpdateScatterStrategy `json:"scatterStrategy,omitempty"`
 	// InPlaceUpdateStrategy contains strategies for in-place update.
 	InPlaceUpdateStrategy *appspub.InPlaceUpdateStrategy `json:"inPlaceUpdateStrategy,omitempty"`
+
+	// HPACoordination indicates how the controller cooperates with external autoscalers during surge rollouts.
+	// If it is AnnotateDesired, the number of surge Pods will be exposed in the apps.kruise.io/cloneset-expected-surge-replicas
+	// annotation and status.expectedSurgeReplicas, so that autoscalers can subtract them.
+	// Default value is None.
+	// +optional
+	HPACoordination CloneSetHPACoordinationType `json:"hpaCoordination,omitempty"`
+
+	// MinKubeletVersionForInPlaceUpdate is the minimum kubelet version of nodes that Pods on them can be updated in-place.
+	// Pods on nodes with older kubelet will be updated by recreation, even if the changes can be in-place updated.
+	// It overrides the --cloneset-inplace-update-min-kubelet-version flag of kruise-manager.
+	// +optional
+	MinKubeletVersionForInPlaceUpdate string `json:"minKubeletVersionForInPlaceUpdate,omitempty"`
+
+	// ReportPolicy indicates the controller to write a rollout report when all Pods have been updated.
+	// +optional
+	ReportPolicy *CloneSetReportPolicy `json:"reportPolicy,omitempty"`
+}
+
+// CloneSetReportPolicy defines where the rollout report of CloneSet is written.
+type CloneSetReportPolicy struct {
+	// ConfigMapName is the name of ConfigMap in the namespace of CloneSet, which the rollout report is written into.
+	// Each report is a JSON value keyed by the update revision, and only the last 3 reports of the CloneSet are retained.
+	ConfigMapName string `json:"configMapName"`
 }
 
+// CloneSetHPACoordinationType defines how CloneSet cooperates with external autoscalers during rollouts.
+type CloneSetHPACoordinationType string
+
+const (
+	// NoneCloneSetHPACoordinationType indicates CloneSet does not expose anything for external autoscalers.
+	NoneCloneSetHPACoordinationType CloneSetHPACoordinationType = "None"
+	// AnnotateDesiredCloneSetHPACoordinationType indicates CloneSet exposes the number of surge Pods
+	// in annotation and status during rollouts.
+	AnnotateDesiredCloneSetHPACoordinationType CloneSetHPACoordinationType = "AnnotateDesired"
+)
+
 // CloneSetUpdateStrategyType defines strategies for pods in-place update.
 type CloneSetUpdateStrategyType string
 
@@ -197,6 +272,14 @@ type CloneSetStatus struct {
 	// This field is calculated via Replicas - Partition.
 	ExpectedUpdatedReplicas int32 `json:"expectedUpdatedReplicas,omitempty"`
 
+	// ExpectedSurgeReplicas is the number of Pods that are expected to be above the desired replicas
+	// because of maxSurge during rollout. It is only calculated when updateStrategy.hpaCoordination is AnnotateDesired.
+	ExpectedSurgeReplicas int32 `json:"expectedSurgeReplicas,omitempty"`
+
+	// KubeletVersionSkewedReplicas is the number of Pods not updated to updateRevision, which are located on nodes
+	// with kubelet older than the minimum version for in-place update, so that they will be updated by recreation.
+	KubeletVersionSkewedReplicas int32 `json:"kubeletVersionSkewedReplicas,omitempty"`
+
 	// UpdateRevision, if not empty, indicates the latest revision of the CloneSet.
 	UpdateRevision string `json:"updateRevision,omitempty"`
 
@@ -213,6 +296,75 @@ type CloneSetStatus struct {
 
 	// LabelSelector is label selectors for query over pods that should match the replica count used by HPA.
 	LabelSelector string `json:"labelSelector,omitempty"`
+
+	// RecreateReasons are the first field paths of the pod template, changed from currentRevision to updateRevision,
+	// which can not be updated in-place and force the pods to be recreated.
+	// It is only set when the update strategy type is InPlaceIfPossible or InPlaceOnly.
+	// +optional
+	RecreateReasons []string `json:"recreateReasons,omitempty"`
+
+	// DecommissionStatuses is the progress of the pods in scaleStrategy.decommission.
+	// +optional
+	DecommissionStatuses []CloneSetDecommissionStatus `json:"decommissionStatuses,omitempty"`
+
+	// RolloutState is the machine-readable state of the rollout, which is a stable contract for external
+	// progressive delivery and CI gates. See GetCloneSetRolloutState in v1beta1 for how it is computed.
+	// +optional
+	RolloutState CloneSetRolloutState `json:"rolloutState,omitempty"`
+}
+
+// CloneSetRolloutState is the state of the rollout of a CloneSet.
+// +kubebuilder:validation:Enum=Idle;Progressing;Paused;Blocked;Degraded;Completed
+type CloneSetRolloutState string
+
+const (
+	// CloneSetRolloutIdle means there is nothing to roll out, with no replicas desired and no pods left.
+	CloneSetRolloutIdle CloneSetRolloutState = "Idle"
+	// CloneSetRolloutProgressing means the pods are being scaled or updated, or the latest spec has not been observed.
+	CloneSetRolloutProgressing CloneSetRolloutState = "Progressing"
+	// CloneSetRolloutPaused means the rollout is paused by updateStrategy.paused, or held by the partition
+	// after the expected pods have been updated and available.
+	CloneSetRolloutPaused CloneSetRolloutState = "Paused"
+	// CloneSetRolloutBlocked means the controller failed to scale or update pods in the latest reconcile.
+	CloneSetRolloutBlocked CloneSetRolloutState = "Blocked"
+	// CloneSetRolloutDegraded means the rollout has failed to make progress within progressDeadlineSeconds.
+	CloneSetRolloutDegraded CloneSetRolloutState = "Degraded"
+	// CloneSetRolloutCompleted means all the desired pods have been updated to the update revision and are available.
+	CloneSetRolloutCompleted CloneSetRolloutState = "Completed"
+)
+
+// CloneSetDecommissionPhase is the phase of decommissioning a pod.
+type CloneSetDecommissionPhase string
+
+const (
+	// CloneSetDecommissionPending means the decommission has not started.
+	CloneSetDecommissionPending CloneSetDecommissionPhase = "Pending"
+	// CloneSetDecommissionDraining means the pod is marked not ready and waiting for the PreDelete hook.
+	CloneSetDecommissionDraining CloneSetDecommissionPhase = "Draining"
+	// CloneSetDecommissionOffloading means the pod is waiting for the finalizer to be removed by the requester.
+	CloneSetDecommissionOffloading CloneSetDecommissionPhase = "Offloading"
+	// CloneSetDecommissionDeleting means the pod is being deleted.
+	CloneSetDecommissionDeleting CloneSetDecommissionPhase = "Deleting"
+	// CloneSetDecommissionCompleted means the pod has been deleted.
+	CloneSetDecommissionCompleted CloneSetDecommissionPhase = "Completed"
+	// CloneSetDecommissionTimeout means the finalizer has not been removed within timeoutSeconds, and the pod is being force deleted.
+	CloneSetDecommissionTimeout CloneSetDecommissionPhase = "Timeout"
+	// CloneSetDecommissionAborted means the pod has disappeared before the decommission completed.
+	CloneSetDecommissionAborted CloneSetDecommissionPhase = "Aborted"
+)
+
+// CloneSetDecommissionStatus is the progress of decommissioning a pod.
+type CloneSetDecommissionStatus struct {
+	// PodName is the name of the pod to decommission.
+	PodName string `json:"podName"`
+	// Phase of the decommission.
+	Phase CloneSetDecommissionPhase `json:"phase"`
+	// StartTime is the time when the decommission started.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// A human readable message indicating details about the decommission.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // CloneSetConditionReason is type for CloneSet reasons.
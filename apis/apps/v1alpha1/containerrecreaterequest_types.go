@@ -37,6 +37,9 @@ const (
 	// ContainerRecreateRequestUnreadyAcquiredKey indicates the Pod has been forced to not-ready.
 	// It is required if the unreadyGracePeriodSeconds is set in ContainerRecreateRequests.
 	ContainerRecreateRequestUnreadyAcquiredKey = "crr.apps.kruise.io/unready-acquired"
+	// ContainerRecreateRequestPUBAdmittedKey indicates the recreation has been admitted by the PodUnavailableBudget of Pod.
+	// kruise-daemon will not stop any container until it is set, unless the ignorePodUnavailableBudget is true.
+	ContainerRecreateRequestPUBAdmittedKey = "crr.apps.kruise.io/pub-admitted"
 )
 
 // ContainerRecreateRequestSpec defines the desired state of ContainerRecreateRequest
@@ -58,12 +61,22 @@ type ContainerRecreateRequestSpec struct {
 // ContainerRecreateRequestContainer defines the container that need to recreate.
 type ContainerRecreateRequestContainer struct {
 	// Name of the container that need to recreate.
-	// It must be existing in the real pod.Spec.Containers.
+	// It must be existing in the real pod.Spec.Containers, or "*" which means all containers in pod.Spec.Containers.
+	// +optional
 	Name string `json:"name"`
+	// NameRegexp is the regular expression of names of the containers that need to recreate,
+	// which must match at least one container in pod.Spec.Containers.
+	// Entries with "*" name or nameRegexp are expanded to the matched containers during this ContainerRecreateRequest creating.
+	// +optional
+	NameRegexp string `json:"nameRegexp,omitempty"`
 	// PreStop is synced from the real container in Pod spec during this ContainerRecreateRequest creating.
 	// Populated by the system.
 	// Read-only.
 	PreStop *ProbeHandler `json:"preStop,omitempty"`
+	// PreStopHook is executed by kruise-daemon before stopping the container, e.g., to drain the in-flight requests.
+	// It is independent of the preStop hook of the container, which is still executed when the container is stopped.
+	// +optional
+	PreStopHook *ContainerRecreateRequestPreStopHook `json:"preStopHook,omitempty"`
 	// Ports is synced from the real container in Pod spec during this ContainerRecreateRequest creating.
 	// Populated by the system.
 	// Read-only.
@@ -91,6 +104,26 @@ type ProbeHandler struct {
 	TCPSocket *v1.TCPSocketAction `json:"tcpSocket,omitempty" protobuf:"bytes,3,opt,name=tcpSocket"`
 }
 
+// ContainerRecreateRequestPreStopHook defines the hook executed by kruise-daemon before stopping the container.
+type ContainerRecreateRequestPreStopHook struct {
+	// One and only one of the following should be specified.
+	// Exec specifies the command to execute in the container.
+	// +optional
+	Exec *v1.ExecAction `json:"exec,omitempty"`
+	// HTTPGet specifies the http request to perform, the host defaults to the Pod IP.
+	// +optional
+	HTTPGet *v1.HTTPGetAction `json:"httpGet,omitempty"`
+	// TimeoutSeconds is the number of seconds after which the hook times out.
+	// Defaults to 30 seconds.
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+	// FailurePolicy decides whether to stop the container if the hook fails.
+	// Fail means the recreation of the container fails without stopping it, and Ignore means to stop it anyway.
+	// Defaults to Fail.
+	// +optional
+	FailurePolicy ContainerRecreateRequestFailurePolicyType `json:"failurePolicy,omitempty"`
+}
+
 // ContainerRecreateRequestContainerContext contains context status of the container that need to recreate.
 type ContainerRecreateRequestContainerContext struct {
 	// Container's ID in the format 'docker://<container_id>'.
@@ -121,6 +154,13 @@ type ContainerRecreateRequestStrategy struct {
 	// without any of its container crashing, for it to be considered Succeeded.
 	// Defaults to 0 (container will be considered Succeeded as soon as it is started and ready)
 	MinStartedSeconds int32 `json:"minStartedSeconds,omitempty"`
+	// RecreateIntervalSeconds is the number of seconds to wait after the previous container has been recreated
+	// and ready, before stopping the next container. It only works when orderedRecreate is true.
+	// Defaults to 0 (the next container will be stopped as soon as the previous one is ready)
+	RecreateIntervalSeconds int32 `json:"recreateIntervalSeconds,omitempty"`
+	// IgnorePodUnavailableBudget indicates whether to recreate containers without checking the PodUnavailableBudget of Pod.
+	// It should only be used in emergency, for the Pod will become unready during the recreation.
+	IgnorePodUnavailableBudget bool `json:"ignorePodUnavailableBudget,omitempty"`
 }
 
 type ContainerRecreateRequestFailurePolicyType string
@@ -152,6 +192,9 @@ const (
 	ContainerRecreateRequestSucceeded  ContainerRecreateRequestPhase = "Succeeded"
 	ContainerRecreateRequestFailed     ContainerRecreateRequestPhase = "Failed"
 	ContainerRecreateRequestCompleted  ContainerRecreateRequestPhase = "Completed"
+	// ContainerRecreateRequestPendingPUB means the recreation is held for it is not allowed by the PodUnavailableBudget,
+	// and it will be retried later.
+	ContainerRecreateRequestPendingPUB ContainerRecreateRequestPhase = "PendingPUB"
 )
 
 // ContainerRecreateRequestContainerRecreateState contains the recreation state of the container.
@@ -164,6 +207,19 @@ type ContainerRecreateRequestContainerRecreateState struct {
 	Message string `json:"message,omitempty"`
 	// Containers are killed by kruise daemon
 	IsKilled bool `json:"isKilled,omitempty"`
+	// Represents time when the container was found to be recreating.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Represents time when the container was found to be recreated and ready, or failed to recreate.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// ExitCode of the old container that has been stopped.
+	// A value above 128 usually means it was terminated by the signal (exitCode - 128).
+	ExitCode *int32 `json:"exitCode,omitempty"`
+	// Represents time when the old container stopped.
+	StoppedAt *metav1.Time `json:"stoppedAt,omitempty"`
+	// NewContainerID is the ID of the new container in the format '<type>://<container_id>'.
+	NewContainerID string `json:"newContainerID,omitempty"`
+	// PreStopHookMessage is the error message of the preStopHook, if it has failed.
+	PreStopHookMessage string `json:"preStopHookMessage,omitempty"`
 }
 
 // ContainerRecreateRequestSyncContainerStatus only uses in the annotation `crr.apps.kruise.io/sync-container-statuses`.
@@ -0,0 +1,106 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// ContainerRecreateRequestSetNameKey is the label of ContainerRecreateRequests created by a ContainerRecreateRequestSet,
+	// whose value is the name of the ContainerRecreateRequestSet.
+	ContainerRecreateRequestSetNameKey = "crr.apps.kruise.io/set-name"
+)
+
+// ContainerRecreateRequestSetSpec defines the desired state of ContainerRecreateRequestSet
+type ContainerRecreateRequestSetSpec struct {
+	// PodSelector is a label query over the Pods in the same namespace to recreate containers.
+	// Only the Pods created before this ContainerRecreateRequestSet are selected.
+	PodSelector *metav1.LabelSelector `json:"podSelector"`
+	// Containers contains the containers that need to recreate in each Pod.
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	Containers []ContainerRecreateRequestContainer `json:"containers" patchStrategy:"merge" patchMergeKey:"name"`
+	// Strategy defines strategies for containers recreation in each Pod.
+	Strategy *ContainerRecreateRequestStrategy `json:"strategy,omitempty"`
+	// ActiveDeadlineSeconds is the deadline duration of the ContainerRecreateRequest for each Pod.
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+	// Concurrency is the max number of Pods recreating containers at the same time.
+	// Value can be an absolute number (ex: 5) or a percentage of the selected Pods (ex: 10%).
+	// Defaults to 1.
+	Concurrency *intstr.IntOrString `json:"concurrency,omitempty"`
+	// RespectPodUnavailableBudget indicates whether to check the PodUnavailableBudget of each Pod
+	// before recreating its containers. If it is not allowed, the Pod will be retried later.
+	RespectPodUnavailableBudget bool `json:"respectPodUnavailableBudget,omitempty"`
+	// TTLSecondsAfterFinished is the TTL duration after this ContainerRecreateRequestSet has completed.
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+}
+
+// ContainerRecreateRequestSetStatus defines the observed state of ContainerRecreateRequestSet
+type ContainerRecreateRequestSetStatus struct {
+	// Phase of this ContainerRecreateRequestSet, e.g. Recreating, Completed
+	Phase ContainerRecreateRequestPhase `json:"phase,omitempty"`
+	// Total is the number of Pods selected to recreate containers.
+	Total int32 `json:"total"`
+	// Active is the number of Pods recreating containers.
+	Active int32 `json:"active"`
+	// Succeeded is the number of Pods whose containers have recreated successfully.
+	Succeeded int32 `json:"succeeded"`
+	// Failed is the number of Pods whose containers have failed to recreate.
+	Failed int32 `json:"failed"`
+	// Represents time when the ContainerRecreateRequestSet was completed, which means all Pods are done.
+	// It is represented in RFC3339 form and is in UTC.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// A human readable message indicating details about this ContainerRecreateRequestSet.
+	Message string `json:"message,omitempty"`
+}
+
+// +genclient
+// +k8s:openapi-gen=true
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=crrset
+// +kubebuilder:printcolumn:name="PHASE",type="string",JSONPath=".status.phase",description="Phase of this ContainerRecreateRequestSet."
+// +kubebuilder:printcolumn:name="TOTAL",type="integer",JSONPath=".status.total",description="The number of Pods selected."
+// +kubebuilder:printcolumn:name="ACTIVE",type="integer",JSONPath=".status.active",description="The number of Pods recreating containers."
+// +kubebuilder:printcolumn:name="SUCCEEDED",type="integer",JSONPath=".status.succeeded",description="The number of Pods recreated successfully."
+// +kubebuilder:printcolumn:name="FAILED",type="integer",JSONPath=".status.failed",description="The number of Pods failed to recreate."
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp",description="CreationTimestamp is a timestamp representing the server time when this object was created. It is not guaranteed to be set in happens-before order across separate operations. Clients may not set this value. It is represented in RFC3339 form and is in UTC."
+
+// ContainerRecreateRequestSet is the Schema for the containerrecreaterequestsets API,
+// which recreates containers in the selected Pods by creating a ContainerRecreateRequest for each of them.
+type ContainerRecreateRequestSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ContainerRecreateRequestSetSpec   `json:"spec,omitempty"`
+	Status ContainerRecreateRequestSetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ContainerRecreateRequestSetList contains a list of ContainerRecreateRequestSet
+type ContainerRecreateRequestSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ContainerRecreateRequestSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ContainerRecreateRequestSet{}, &ContainerRecreateRequestSetList{})
+}
@@ -57,12 +57,13 @@ func (ds *DaemonSet) ConvertTo(dst conversion.Hub) error {
 			}
 
 			dsv1beta1.Spec.UpdateStrategy.RollingUpdate = &v1beta1.RollingUpdateDaemonSet{
-				Type:           v1beta1.RollingUpdateType(rollingUpdateType),
-				MaxUnavailable: ds.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable,
-				MaxSurge:       ds.Spec.UpdateStrategy.RollingUpdate.MaxSurge,
-				Selector:       ds.Spec.UpdateStrategy.RollingUpdate.Selector,
-				Partition:      ds.Spec.UpdateStrategy.RollingUpdate.Partition,
-				Paused:         ds.Spec.UpdateStrategy.RollingUpdate.Paused,
+				Type:                     v1beta1.RollingUpdateType(rollingUpdateType),
+				MaxUnavailable:           ds.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable,
+				MaxSurge:                 ds.Spec.UpdateStrategy.RollingUpdate.MaxSurge,
+				Selector:                 ds.Spec.UpdateStrategy.RollingUpdate.Selector,
+				Partition:                ds.Spec.UpdateStrategy.RollingUpdate.Partition,
+				Paused:                   ds.Spec.UpdateStrategy.RollingUpdate.Paused,
+				WaitNodeReadyBeforePatch: ds.Spec.UpdateStrategy.RollingUpdate.WaitNodeReadyBeforePatch,
 			}
 		}
 
@@ -86,7 +87,9 @@ func (ds *DaemonSet) ConvertTo(dst conversion.Hub) error {
 			CollisionCount:         ds.Status.CollisionCount,
 			Conditions:             ds.Status.Conditions,
 			// Map DaemonSetHash to UpdateRevision for backward compatibility
-			UpdateRevision: ds.Status.DaemonSetHash,
+			UpdateRevision:     ds.Status.DaemonSetHash,
+			SlowestNodeClasses: convertNodeClassAvailabilityToV1beta1(ds.Status.SlowestNodeClasses),
+			PatchStatuses:      convertPatchStatusesToV1beta1(ds.Status.PatchStatuses),
 		}
 
 		return nil
@@ -117,12 +120,13 @@ func (ds *DaemonSet) ConvertFrom(src conversion.Hub) error {
 
 		if dsv1beta1.Spec.UpdateStrategy.RollingUpdate != nil {
 			ds.Spec.UpdateStrategy.RollingUpdate = &RollingUpdateDaemonSet{
-				Type:           RollingUpdateType(dsv1beta1.Spec.UpdateStrategy.RollingUpdate.Type),
-				MaxUnavailable: dsv1beta1.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable,
-				MaxSurge:       dsv1beta1.Spec.UpdateStrategy.RollingUpdate.MaxSurge,
-				Selector:       dsv1beta1.Spec.UpdateStrategy.RollingUpdate.Selector,
-				Partition:      dsv1beta1.Spec.UpdateStrategy.RollingUpdate.Partition,
-				Paused:         dsv1beta1.Spec.UpdateStrategy.RollingUpdate.Paused,
+				Type:                     RollingUpdateType(dsv1beta1.Spec.UpdateStrategy.RollingUpdate.Type),
+				MaxUnavailable:           dsv1beta1.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable,
+				MaxSurge:                 dsv1beta1.Spec.UpdateStrategy.RollingUpdate.MaxSurge,
+				Selector:                 dsv1beta1.Spec.UpdateStrategy.RollingUpdate.Selector,
+				Partition:                dsv1beta1.Spec.UpdateStrategy.RollingUpdate.Partition,
+				Paused:                   dsv1beta1.Spec.UpdateStrategy.RollingUpdate.Paused,
+				WaitNodeReadyBeforePatch: dsv1beta1.Spec.UpdateStrategy.RollingUpdate.WaitNodeReadyBeforePatch,
 			}
 		}
 
@@ -147,7 +151,9 @@ func (ds *DaemonSet) ConvertFrom(src conversion.Hub) error {
 			CollisionCount:         dsv1beta1.Status.CollisionCount,
 			Conditions:             dsv1beta1.Status.Conditions,
 			// Map UpdateRevision to DaemonSetHash for backward compatibility
-			DaemonSetHash: dsv1beta1.Status.UpdateRevision,
+			DaemonSetHash:      dsv1beta1.Status.UpdateRevision,
+			SlowestNodeClasses: convertNodeClassAvailabilityFromV1beta1(dsv1beta1.Status.SlowestNodeClasses),
+			PatchStatuses:      convertPatchStatusesFromV1beta1(dsv1beta1.Status.PatchStatuses),
 		}
 
 		return nil
@@ -155,3 +161,67 @@ func (ds *DaemonSet) ConvertFrom(src conversion.Hub) error {
 		return fmt.Errorf("unsupported type %v", t)
 	}
 }
+
+func convertNodeClassAvailabilityToV1beta1(in []DaemonSetNodeClassAvailability) []v1beta1.DaemonSetNodeClassAvailability {
+	if in == nil {
+		return nil
+	}
+	out := make([]v1beta1.DaemonSetNodeClassAvailability, len(in))
+	for i := range in {
+		out[i] = v1beta1.DaemonSetNodeClassAvailability{
+			NodeClass:      in[i].NodeClass,
+			Nodes:          in[i].Nodes,
+			AverageSeconds: in[i].AverageSeconds,
+			MaxSeconds:     in[i].MaxSeconds,
+		}
+	}
+	return out
+}
+
+func convertNodeClassAvailabilityFromV1beta1(in []v1beta1.DaemonSetNodeClassAvailability) []DaemonSetNodeClassAvailability {
+	if in == nil {
+		return nil
+	}
+	out := make([]DaemonSetNodeClassAvailability, len(in))
+	for i := range in {
+		out[i] = DaemonSetNodeClassAvailability{
+			NodeClass:      in[i].NodeClass,
+			Nodes:          in[i].Nodes,
+			AverageSeconds: in[i].AverageSeconds,
+			MaxSeconds:     in[i].MaxSeconds,
+		}
+	}
+	return out
+}
+
+func convertPatchStatusesToV1beta1(in []DaemonSetPatchStatus) []v1beta1.DaemonSetPatchStatus {
+	if in == nil {
+		return nil
+	}
+	out := make([]v1beta1.DaemonSetPatchStatus, len(in))
+	for i := range in {
+		out[i] = v1beta1.DaemonSetPatchStatus{
+			MatchedNodes:     in[i].MatchedNodes,
+			LastRenderedTime: in[i].LastRenderedTime,
+			Description:      in[i].Description,
+			Owner:            in[i].Owner,
+		}
+	}
+	return out
+}
+
+func convertPatchStatusesFromV1beta1(in []v1beta1.DaemonSetPatchStatus) []DaemonSetPatchStatus {
+	if in == nil {
+		return nil
+	}
+	out := make([]DaemonSetPatchStatus, len(in))
+	for i := range in {
+		out[i] = DaemonSetPatchStatus{
+			MatchedNodes:     in[i].MatchedNodes,
+			LastRenderedTime: in[i].LastRenderedTime,
+			Description:      in[i].Description,
+			Owner:            in[i].Owner,
+		}
+	}
+	return out
+}
@@ -26,6 +26,10 @@ import (
 
 const (
 	EphemeralContainerEnvKey = "KRUISE_EJOB_ID"
+
+	// EphemeralContainersToStopAnnotation is added into pods by ephemeraljob-controller, which contains a json list of
+	// ephemeral container IDs that have exceeded the target active deadline and should be stopped by kruise-daemon.
+	EphemeralContainersToStopAnnotation = "apps.kruise.io/ephemeral-containers-to-stop"
 )
 
 // EphemeralJobSpec defines the desired state of EphemeralJob
@@ -35,6 +39,12 @@ type EphemeralJobSpec struct {
 	// Selector is a label query over pods that should match the pod labels.
 	Selector *metav1.LabelSelector `json:"selector"`
 
+	// PodNames is an explicit list of pod names in the same namespace of the job to target.
+	// If set, only the listed pods which are also matched by selector will be targeted,
+	// and replicas will not work.
+	// +optional
+	PodNames []string `json:"podNames,omitempty"`
+
 	// Replicas indicates a part of the quantity from matched pods by selector.
 	// Usually it is used for gray scale working.
 	// if Replicas exceeded the matched number by selector or not be set, replicas will not work.
@@ -57,6 +67,14 @@ type EphemeralJobSpec struct {
 	// +optional
 	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty" protobuf:"varint,2,opt,name=activeDeadlineSeconds"`
 
+	// TargetActiveDeadlineSeconds specifies the duration in seconds relative to the time the ephemeral containers
+	// started in each target, or the job started if none of them has started, that they may be active; value must be
+	// positive integer.
+	// Targets exceeded the deadline will be considered as Timeout, and their ephemeral containers will be stopped
+	// by kruise-daemon if the container runtime supports it.
+	// +optional
+	TargetActiveDeadlineSeconds *int64 `json:"targetActiveDeadlineSeconds,omitempty"`
+
 	// ttlSecondsAfterFinished limits the lifetime of a Job that has finished
 	// execution (either Complete or Failed). If this field is set,
 	// ttlSecondsAfterFinished after the eJob finishes, it is eligible to be
@@ -124,8 +142,53 @@ type EphemeralJobStatus struct {
 	// The number of pods which reached phase Failed.
 	// +optional
 	Failed int32 `json:"failed" protobuf:"varint,6,opt,name=failed"`
+
+	// The number of pods which exceeded the target active deadline.
+	// +optional
+	Timeout int32 `json:"timeout,omitempty"`
+
+	// PodStatuses reports the injection state of each pod listed in spec.podNames.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	PodStatuses []EphemeralJobPodStatus `json:"podStatuses,omitempty"`
+}
+
+// EphemeralJobPodStatus describes the injection state of ephemeral containers in a target pod.
+type EphemeralJobPodStatus struct {
+	// Name of the target pod.
+	Name string `json:"name"`
+
+	// State of the ephemeral containers injected into the pod.
+	State EphemeralJobPodState `json:"state"`
 }
 
+// EphemeralJobPodState indicates the injection state of ephemeral containers in a target pod.
+type EphemeralJobPodState string
+
+const (
+	// EphemeralJobPodNotFound means the pod does not exist, is inactive or is not matched by selector.
+	EphemeralJobPodNotFound EphemeralJobPodState = "NotFound"
+
+	// EphemeralJobPodNotInjected means the ephemeral containers have not been injected into the pod yet.
+	EphemeralJobPodNotInjected EphemeralJobPodState = "NotInjected"
+
+	// EphemeralJobPodWaiting means the injected ephemeral containers are waiting.
+	EphemeralJobPodWaiting EphemeralJobPodState = "Waiting"
+
+	// EphemeralJobPodRunning means the injected ephemeral containers are running.
+	EphemeralJobPodRunning EphemeralJobPodState = "Running"
+
+	// EphemeralJobPodSucceeded means the injected ephemeral containers have succeeded.
+	EphemeralJobPodSucceeded EphemeralJobPodState = "Succeeded"
+
+	// EphemeralJobPodFailed means the injected ephemeral containers have failed.
+	EphemeralJobPodFailed EphemeralJobPodState = "Failed"
+
+	// EphemeralJobPodTimeout means the pod exceeded the target active deadline.
+	EphemeralJobPodTimeout EphemeralJobPodState = "Timeout"
+)
+
 // JobCondition describes current state of a job.
 type EphemeralJobCondition struct {
 	// Type of job condition, Complete or Failed.
@@ -166,6 +229,9 @@ const (
 
 	// EJobMatchedEmpty means the ephemeral job has not matched the target pods.
 	EJobMatchedEmpty EphemeralJobConditionType = "MatchedEmpty"
+
+	// EJobTimeout means some targets of the ephemeral job exceeded the target active deadline.
+	EJobTimeout EphemeralJobConditionType = "JobTimeout"
 )
 
 // EphemeralJobPhase indicates the type of EphemeralJobPhase.
@@ -178,6 +244,9 @@ const (
 	// EphemeralJobFailed means the job has failed.
 	EphemeralJobFailed EphemeralJobPhase = "Failed"
 
+	// EphemeralJobTimeout means the job has finished and some targets exceeded the target active deadline.
+	EphemeralJobTimeout EphemeralJobPhase = "Timeout"
+
 	// EphemeralJobWaiting means the job is waiting.
 	EphemeralJobWaiting EphemeralJobPhase = "Waiting"
 
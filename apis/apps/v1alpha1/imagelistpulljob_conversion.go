@@ -32,17 +32,22 @@ func (o *ImageListPullJob) ConvertTo(dst conversion.Hub) error {
 		v.Spec = v1beta1.ImageListPullJobSpec{
 			Images: o.Spec.Images,
 			ImagePullJobTemplate: v1beta1.ImagePullJobTemplate{
-				PullSecrets: o.Spec.PullSecrets,
-				Selector:    convertNodeSelectorToV1Beta1(o.Spec.Selector),
-				PodSelector: convertPodSelectorToV1Beta1(o.Spec.PodSelector),
-				Parallelism: o.Spec.Parallelism,
-				PullPolicy:  convertPullPolicyToV1Beta1(o.Spec.PullPolicy),
+				PullSecrets:            o.Spec.PullSecrets,
+				ServiceAccountName:     o.Spec.ServiceAccountName,
+				Selector:               convertNodeSelectorToV1Beta1(o.Spec.Selector),
+				PodSelector:            convertPodSelectorToV1Beta1(o.Spec.PodSelector),
+				SkipUnschedulableNodes: o.Spec.SkipUnschedulableNodes,
+				Parallelism:            o.Spec.Parallelism,
+				PullPolicy:             convertPullPolicyToV1Beta1(o.Spec.PullPolicy),
 				CompletionPolicy: v1beta1.CompletionPolicy{
 					Type:                    v1beta1.CompletionPolicyType(o.Spec.CompletionPolicy.Type),
 					ActiveDeadlineSeconds:   o.Spec.CompletionPolicy.ActiveDeadlineSeconds,
 					TTLSecondsAfterFinished: o.Spec.CompletionPolicy.TTLSecondsAfterFinished,
+					FailedNodeLimit:         o.Spec.CompletionPolicy.FailedNodeLimit,
+					FailedImageLimit:        o.Spec.CompletionPolicy.FailedImageLimit,
 				},
 				SandboxConfig:   convertSandboxConfigToV1Beta1(o.Spec.SandboxConfig),
+				Platform:        convertImagePlatformToV1Beta1(o.Spec.Platform),
 				ImagePullPolicy: v1beta1.ImagePullPolicy(o.Spec.ImagePullPolicy),
 			},
 		}
@@ -53,6 +58,8 @@ func (o *ImageListPullJob) ConvertTo(dst conversion.Hub) error {
 			Active:              o.Status.Active,
 			Completed:           o.Status.Completed,
 			Succeeded:           o.Status.Succeeded,
+			Failed:              o.Status.Failed,
+			Phase:               v1beta1.ImageListPullJobPhase(o.Status.Phase),
 			FailedImageStatuses: convertFailedImageStatusesToV1Beta1(o.Status.FailedImageStatuses),
 		}
 		return nil
@@ -69,17 +76,22 @@ func (o *ImageListPullJob) ConvertFrom(src conversion.Hub) error {
 		o.Spec = ImageListPullJobSpec{
 			Images: v.Spec.Images,
 			ImagePullJobTemplate: ImagePullJobTemplate{
-				PullSecrets: v.Spec.PullSecrets,
-				Selector:    convertNodeSelectorToV1Alpha1(v.Spec.Selector),
-				PodSelector: convertPodSelectorToV1Alpha1(v.Spec.PodSelector),
-				Parallelism: v.Spec.Parallelism,
-				PullPolicy:  convertPullPolicyToV1Alpha1(v.Spec.PullPolicy),
+				PullSecrets:            v.Spec.PullSecrets,
+				ServiceAccountName:     v.Spec.ServiceAccountName,
+				Selector:               convertNodeSelectorToV1Alpha1(v.Spec.Selector),
+				PodSelector:            convertPodSelectorToV1Alpha1(v.Spec.PodSelector),
+				SkipUnschedulableNodes: v.Spec.SkipUnschedulableNodes,
+				Parallelism:            v.Spec.Parallelism,
+				PullPolicy:             convertPullPolicyToV1Alpha1(v.Spec.PullPolicy),
 				CompletionPolicy: CompletionPolicy{
 					Type:                    CompletionPolicyType(v.Spec.CompletionPolicy.Type),
 					ActiveDeadlineSeconds:   v.Spec.CompletionPolicy.ActiveDeadlineSeconds,
 					TTLSecondsAfterFinished: v.Spec.CompletionPolicy.TTLSecondsAfterFinished,
+					FailedNodeLimit:         v.Spec.CompletionPolicy.FailedNodeLimit,
+					FailedImageLimit:        v.Spec.CompletionPolicy.FailedImageLimit,
 				},
 				SandboxConfig:   convertSandboxConfigToV1Alpha1(v.Spec.SandboxConfig),
+				Platform:        convertImagePlatformToV1Alpha1(v.Spec.Platform),
 				ImagePullPolicy: ImagePullPolicy(v.Spec.ImagePullPolicy),
 			},
 		}
@@ -90,6 +102,8 @@ func (o *ImageListPullJob) ConvertFrom(src conversion.Hub) error {
 			Active:              v.Status.Active,
 			Completed:           v.Status.Completed,
 			Succeeded:           v.Status.Succeeded,
+			Failed:              v.Status.Failed,
+			Phase:               ImageListPullJobPhase(v.Status.Phase),
 			FailedImageStatuses: convertFailedImageStatusesToV1Alpha1(v.Status.FailedImageStatuses),
 		}
 		return nil
@@ -111,6 +125,8 @@ func convertFailedImageStatusesToV1Beta1(in []*FailedImageStatus) []*v1beta1.Fai
 			ImagePullJob: s.ImagePullJob,
 			Name:         s.Name,
 			Message:      s.Message,
+			FailedNodes:  s.FailedNodes,
+			Tolerated:    s.Tolerated,
 		})
 	}
 	return out
@@ -129,6 +145,8 @@ func convertFailedImageStatusesToV1Alpha1(in []*v1beta1.FailedImageStatus) []*Fa
 			ImagePullJob: s.ImagePullJob,
 			Name:         s.Name,
 			Message:      s.Message,
+			FailedNodes:  s.FailedNodes,
+			Tolerated:    s.Tolerated,
 		})
 	}
 	return out
@@ -31,19 +31,25 @@ func (ipj *ImagePullJob) ConvertTo(dst conversion.Hub) error {
 		v.ObjectMeta = ipj.ObjectMeta
 
 		v.Spec = v1beta1.ImagePullJobSpec{
-			Image: ipj.Spec.Image,
+			Image:  ipj.Spec.Image,
+			Images: ipj.Spec.Images,
 			ImagePullJobTemplate: v1beta1.ImagePullJobTemplate{
-				PullSecrets: ipj.Spec.PullSecrets,
-				Selector:    convertNodeSelectorToV1Beta1(ipj.Spec.Selector),
-				PodSelector: convertPodSelectorToV1Beta1(ipj.Spec.PodSelector),
-				Parallelism: ipj.Spec.Parallelism,
-				PullPolicy:  convertPullPolicyToV1Beta1(ipj.Spec.PullPolicy),
+				PullSecrets:            ipj.Spec.PullSecrets,
+				ServiceAccountName:     ipj.Spec.ServiceAccountName,
+				Selector:               convertNodeSelectorToV1Beta1(ipj.Spec.Selector),
+				PodSelector:            convertPodSelectorToV1Beta1(ipj.Spec.PodSelector),
+				SkipUnschedulableNodes: ipj.Spec.SkipUnschedulableNodes,
+				Parallelism:            ipj.Spec.Parallelism,
+				PullPolicy:             convertPullPolicyToV1Beta1(ipj.Spec.PullPolicy),
 				CompletionPolicy: v1beta1.CompletionPolicy{
 					Type:                    v1beta1.CompletionPolicyType(ipj.Spec.CompletionPolicy.Type),
 					ActiveDeadlineSeconds:   ipj.Spec.CompletionPolicy.ActiveDeadlineSeconds,
 					TTLSecondsAfterFinished: ipj.Spec.CompletionPolicy.TTLSecondsAfterFinished,
+					FailedNodeLimit:         ipj.Spec.CompletionPolicy.FailedNodeLimit,
+					FailedImageLimit:        ipj.Spec.CompletionPolicy.FailedImageLimit,
 				},
 				SandboxConfig:   convertSandboxConfigToV1Beta1(ipj.Spec.SandboxConfig),
+				Platform:        convertImagePlatformToV1Beta1(ipj.Spec.Platform),
 				ImagePullPolicy: v1beta1.ImagePullPolicy(ipj.Spec.ImagePullPolicy),
 			},
 		}
@@ -55,8 +61,11 @@ func (ipj *ImagePullJob) ConvertTo(dst conversion.Hub) error {
 			Active:         ipj.Status.Active,
 			Succeeded:      ipj.Status.Succeeded,
 			Failed:         ipj.Status.Failed,
+			Progress:       ipj.Status.Progress,
 			Message:        ipj.Status.Message,
 			FailedNodes:    ipj.Status.FailedNodes,
+			ImageStatuses:  convertImagePullJobImageStatusesToV1Beta1(ipj.Status.ImageStatuses),
+			NodeStates:     convertImagePullJobNodeStatesToV1Beta1(ipj.Status.NodeStates),
 		}
 		return nil
 	default:
@@ -71,19 +80,25 @@ func (ipj *ImagePullJob) ConvertFrom(src conversion.Hub) error {
 		ipj.ObjectMeta = v.ObjectMeta
 
 		ipj.Spec = ImagePullJobSpec{
-			Image: v.Spec.Image,
+			Image:  v.Spec.Image,
+			Images: v.Spec.Images,
 			ImagePullJobTemplate: ImagePullJobTemplate{
-				PullSecrets: v.Spec.PullSecrets,
-				Selector:    convertNodeSelectorToV1Alpha1(v.Spec.Selector),
-				PodSelector: convertPodSelectorToV1Alpha1(v.Spec.PodSelector),
-				Parallelism: v.Spec.Parallelism,
-				PullPolicy:  convertPullPolicyToV1Alpha1(v.Spec.PullPolicy),
+				PullSecrets:            v.Spec.PullSecrets,
+				ServiceAccountName:     v.Spec.ServiceAccountName,
+				Selector:               convertNodeSelectorToV1Alpha1(v.Spec.Selector),
+				PodSelector:            convertPodSelectorToV1Alpha1(v.Spec.PodSelector),
+				SkipUnschedulableNodes: v.Spec.SkipUnschedulableNodes,
+				Parallelism:            v.Spec.Parallelism,
+				PullPolicy:             convertPullPolicyToV1Alpha1(v.Spec.PullPolicy),
 				CompletionPolicy: CompletionPolicy{
 					Type:                    CompletionPolicyType(v.Spec.CompletionPolicy.Type),
 					ActiveDeadlineSeconds:   v.Spec.CompletionPolicy.ActiveDeadlineSeconds,
 					TTLSecondsAfterFinished: v.Spec.CompletionPolicy.TTLSecondsAfterFinished,
+					FailedNodeLimit:         v.Spec.CompletionPolicy.FailedNodeLimit,
+					FailedImageLimit:        v.Spec.CompletionPolicy.FailedImageLimit,
 				},
 				SandboxConfig:   convertSandboxConfigToV1Alpha1(v.Spec.SandboxConfig),
+				Platform:        convertImagePlatformToV1Alpha1(v.Spec.Platform),
 				ImagePullPolicy: ImagePullPolicy(v.Spec.ImagePullPolicy),
 			},
 		}
@@ -95,8 +110,11 @@ func (ipj *ImagePullJob) ConvertFrom(src conversion.Hub) error {
 			Active:         v.Status.Active,
 			Succeeded:      v.Status.Succeeded,
 			Failed:         v.Status.Failed,
+			Progress:       v.Status.Progress,
 			Message:        v.Status.Message,
 			FailedNodes:    v.Status.FailedNodes,
+			ImageStatuses:  convertImagePullJobImageStatusesToV1Alpha1(v.Status.ImageStatuses),
+			NodeStates:     convertImagePullJobNodeStatesToV1Alpha1(v.Status.NodeStates),
 		}
 		return nil
 	default:
@@ -104,6 +122,88 @@ func (ipj *ImagePullJob) ConvertFrom(src conversion.Hub) error {
 	}
 }
 
+func convertImagePullJobImageStatusesToV1Beta1(in []ImagePullJobImageStatus) []v1beta1.ImagePullJobImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := make([]v1beta1.ImagePullJobImageStatus, len(in))
+	for i := range in {
+		out[i] = v1beta1.ImagePullJobImageStatus{
+			Image:     in[i].Image,
+			Desired:   in[i].Desired,
+			Active:    in[i].Active,
+			Succeeded: in[i].Succeeded,
+			Failed:    in[i].Failed,
+		}
+	}
+	return out
+}
+
+func convertImagePullJobImageStatusesToV1Alpha1(in []v1beta1.ImagePullJobImageStatus) []ImagePullJobImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := make([]ImagePullJobImageStatus, len(in))
+	for i := range in {
+		out[i] = ImagePullJobImageStatus{
+			Image:     in[i].Image,
+			Desired:   in[i].Desired,
+			Active:    in[i].Active,
+			Succeeded: in[i].Succeeded,
+			Failed:    in[i].Failed,
+		}
+	}
+	return out
+}
+
+func convertImagePullJobNodeStatesToV1Beta1(in *ImagePullJobNodeStates) *v1beta1.ImagePullJobNodeStates {
+	if in == nil {
+		return nil
+	}
+	out := &v1beta1.ImagePullJobNodeStates{
+		Waiting:            in.Waiting,
+		Pulling:            in.Pulling,
+		Succeeded:          in.Succeeded,
+		Failed:             in.Failed,
+		AveragePullSeconds: in.AveragePullSeconds,
+	}
+	if in.WaitingNodes != nil {
+		out.WaitingNodes = make([]v1beta1.ImagePullJobWaitingNode, len(in.WaitingNodes))
+		for i := range in.WaitingNodes {
+			out.WaitingNodes[i] = v1beta1.ImagePullJobWaitingNode{
+				Name:               in.WaitingNodes[i].Name,
+				QueueAhead:         in.WaitingNodes[i].QueueAhead,
+				EstimatedStartTime: in.WaitingNodes[i].EstimatedStartTime,
+			}
+		}
+	}
+	return out
+}
+
+func convertImagePullJobNodeStatesToV1Alpha1(in *v1beta1.ImagePullJobNodeStates) *ImagePullJobNodeStates {
+	if in == nil {
+		return nil
+	}
+	out := &ImagePullJobNodeStates{
+		Waiting:            in.Waiting,
+		Pulling:            in.Pulling,
+		Succeeded:          in.Succeeded,
+		Failed:             in.Failed,
+		AveragePullSeconds: in.AveragePullSeconds,
+	}
+	if in.WaitingNodes != nil {
+		out.WaitingNodes = make([]ImagePullJobWaitingNode, len(in.WaitingNodes))
+		for i := range in.WaitingNodes {
+			out.WaitingNodes[i] = ImagePullJobWaitingNode{
+				Name:               in.WaitingNodes[i].Name,
+				QueueAhead:         in.WaitingNodes[i].QueueAhead,
+				EstimatedStartTime: in.WaitingNodes[i].EstimatedStartTime,
+			}
+		}
+	}
+	return out
+}
+
 func convertPodSelectorToV1Beta1(in *ImagePullJobPodSelector) *v1beta1.ImagePullJobPodSelector {
 	if in == nil {
 		return nil
@@ -129,6 +229,7 @@ func convertNodeSelectorToV1Beta1(in *ImagePullJobNodeSelector) *v1beta1.ImagePu
 	out := &v1beta1.ImagePullJobNodeSelector{}
 	out.Names = in.Names
 	out.LabelSelector = in.LabelSelector
+	out.MatchFields = in.MatchFields
 	return out
 }
 
@@ -139,6 +240,7 @@ func convertNodeSelectorToV1Alpha1(in *v1beta1.ImagePullJobNodeSelector) *ImageP
 	out := &ImagePullJobNodeSelector{}
 	out.Names = in.Names
 	out.LabelSelector = in.LabelSelector
+	out.MatchFields = in.MatchFields
 	return out
 }
 
@@ -147,8 +249,9 @@ func convertPullPolicyToV1Beta1(in *PullPolicy) *v1beta1.PullPolicy {
 		return nil
 	}
 	return &v1beta1.PullPolicy{
-		TimeoutSeconds: in.TimeoutSeconds,
-		BackoffLimit:   in.BackoffLimit,
+		TimeoutSeconds:            in.TimeoutSeconds,
+		BackoffLimit:              in.BackoffLimit,
+		MaxConcurrentPullsPerNode: in.MaxConcurrentPullsPerNode,
 	}
 }
 
@@ -157,8 +260,9 @@ func convertPullPolicyToV1Alpha1(in *v1beta1.PullPolicy) *PullPolicy {
 		return nil
 	}
 	return &PullPolicy{
-		TimeoutSeconds: in.TimeoutSeconds,
-		BackoffLimit:   in.BackoffLimit,
+		TimeoutSeconds:            in.TimeoutSeconds,
+		BackoffLimit:              in.BackoffLimit,
+		MaxConcurrentPullsPerNode: in.MaxConcurrentPullsPerNode,
 	}
 }
 
@@ -201,3 +305,25 @@ func convertSandboxConfigToV1Alpha1(in *v1beta1.SandboxConfig) *SandboxConfig {
 	}
 	return out
 }
+
+func convertImagePlatformToV1Beta1(in *ImagePlatform) *v1beta1.ImagePlatform {
+	if in == nil {
+		return nil
+	}
+	return &v1beta1.ImagePlatform{
+		Architecture: in.Architecture,
+		OS:           in.OS,
+		Variant:      in.Variant,
+	}
+}
+
+func convertImagePlatformToV1Alpha1(in *v1beta1.ImagePlatform) *ImagePlatform {
+	if in == nil {
+		return nil
+	}
+	return &ImagePlatform{
+		Architecture: in.Architecture,
+		OS:           in.OS,
+		Variant:      in.Variant,
+	}
+}
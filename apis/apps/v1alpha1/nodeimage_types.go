@@ -90,6 +90,16 @@ type ImageTagSpec struct {
 	// One of Always, IfNotPresent. Defaults to IfNotPresent.
 	// +optional
 	ImagePullPolicy ImagePullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// ExpectedDigest is the digest that the pulled image is expected to have, e.g., sha256:xxx.
+	// If it does not match the digest of the image pulled, the pulling task is marked Failed.
+	// +optional
+	ExpectedDigest string `json:"expectedDigest,omitempty"`
+
+	// Platform specifies the platform of the image to be pulled from a multi-arch manifest.
+	// If the runtime is not able to pull the image of the platform, the pulling task is marked Failed.
+	// +optional
+	Platform *ImagePlatform `json:"platform,omitempty"`
 }
 
 // ImageTagPullPolicy defines the policy of the pulling task
@@ -100,10 +110,16 @@ type ImageTagPullPolicy struct {
 	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
 
 	// Specifies the number of retries before marking the pulling task failed.
+	// Failed pulls are retried with exponential backoff.
 	// Defaults to 3
 	// +optional
 	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
 
+	// MaxConcurrentPulls limits the number of images pulled concurrently on the node
+	// while this task is being pulled. If not specified, there is no limit except the worker pool size.
+	// +optional
+	MaxConcurrentPulls *int32 `json:"maxConcurrentPulls,omitempty"`
+
 	// TTLSecondsAfterFinished limits the lifetime of a pulling task that has finished execution (either Complete or Failed).
 	// If this field is set, ttlSecondsAfterFinished after the task finishes, it is eligible to be automatically deleted.
 	// If this field is unset, the task won't be automatically deleted.
@@ -146,6 +162,69 @@ type NodeImageStatus struct {
 	// the time when the node's image pulling is completed, and use it to trigger the operation of the upper system.
 	// +optional
 	FirstSyncStatus *SyncStatus `json:"firstSyncStatus,omitempty"`
+
+	// Capabilities of kruise-daemon on this node, which is reported by the daemon on startup.
+	// Controllers should not assign operations that are not supported by the node.
+	// +optional
+	Capabilities *NodeCapabilities `json:"capabilities,omitempty"`
+}
+
+// NodeCapabilities describes the container runtime and the features supported by kruise-daemon on the node.
+type NodeCapabilities struct {
+	// Name of the container runtime, such as containerd.
+	// +optional
+	RuntimeName string `json:"runtimeName,omitempty"`
+
+	// Version of the container runtime.
+	// +optional
+	RuntimeVersion string `json:"runtimeVersion,omitempty"`
+
+	// Endpoint of the container runtime that kruise-daemon connects to.
+	// +optional
+	RuntimeEndpoint string `json:"runtimeEndpoint,omitempty"`
+
+	// Namespace of containerd that kruise-daemon uses, which is only reported for containerd.
+	// +optional
+	ContainerdNamespace string `json:"containerdNamespace,omitempty"`
+
+	// Version of kruise-daemon.
+	// +optional
+	DaemonVersion string `json:"daemonVersion,omitempty"`
+
+	// Features supported by kruise-daemon on the node.
+	// +optional
+	// +listType=set
+	Features []NodeFeature `json:"features,omitempty"`
+}
+
+// NodeFeature is a feature that kruise-daemon may support on the node
+type NodeFeature string
+
+const (
+	// NodeFeatureImagePull means the daemon is able to pull images for NodeImage.
+	NodeFeatureImagePull NodeFeature = "ImagePull"
+	// NodeFeatureImagePullProgress means the daemon is able to report the progress of pulling images.
+	NodeFeatureImagePullProgress NodeFeature = "ImagePullProgress"
+	// NodeFeatureContainerRecreate means the daemon is able to recreate containers for ContainerRecreateRequest.
+	NodeFeatureContainerRecreate NodeFeature = "ContainerRecreate"
+	// NodeFeatureNativeSidecarRestart means the daemon is able to restart native sidecar containers,
+	// which are init containers with restartPolicy Always.
+	NodeFeatureNativeSidecarRestart NodeFeature = "NativeSidecarRestart"
+)
+
+// IsFeatureSupported returns whether the feature is supported by the node.
+// Nodes that have not reported capabilities are considered to support all features,
+// for compatibility with kruise-daemon of old versions.
+func (c *NodeCapabilities) IsFeatureSupported(feature NodeFeature) bool {
+	if c == nil {
+		return true
+	}
+	for _, f := range c.Features {
+		if f == feature {
+			return true
+		}
+	}
+	return false
 }
 
 // ImageStatus defines the pulling status of an image
@@ -186,6 +265,14 @@ type ImageTagStatus struct {
 	// +optional
 	ImageID string `json:"imageID,omitempty"`
 
+	// Represents the digest of this image in the repository, e.g., sha256:xxx.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+
+	// Represents the size in bytes of this image taking disk space.
+	// +optional
+	Size int64 `json:"size,omitempty"`
+
 	// Represents the summary information of this node
 	// +optional
 	Message string `json:"message,omitempty"`
@@ -281,6 +368,7 @@ func (src *NodeImage) ConvertTo(dstRaw conversion.Hub) error {
 			Waiting:         src.Status.Waiting,
 			ImageStatuses:   make(map[string]v1beta1.ImageStatus),
 			FirstSyncStatus: convertSyncStatusToV1Beta1(src.Status.FirstSyncStatus),
+			Capabilities:    convertNodeCapabilitiesToV1Beta1(src.Status.Capabilities),
 		}
 		for name, imageStatus := range src.Status.ImageStatuses {
 			dst.Status.ImageStatuses[name] = convertImageStatusToV1Beta1(imageStatus)
@@ -316,6 +404,7 @@ func (dst *NodeImage) ConvertFrom(srcRaw conversion.Hub) error {
 			Waiting:         src.Status.Waiting,
 			ImageStatuses:   make(map[string]ImageStatus),
 			FirstSyncStatus: convertSyncStatusFromV1Beta1(src.Status.FirstSyncStatus),
+			Capabilities:    convertNodeCapabilitiesFromV1Beta1(src.Status.Capabilities),
 		}
 		for name, imageStatus := range src.Status.ImageStatuses {
 			dst.Status.ImageStatuses[name] = convertImageStatusFromV1Beta1(imageStatus)
@@ -391,6 +480,7 @@ func convertImageTagPullPolicyToV1Beta1(src *ImageTagPullPolicy) *v1beta1.ImageT
 	return &v1beta1.ImageTagPullPolicy{
 		TimeoutSeconds:          src.TimeoutSeconds,
 		BackoffLimit:            src.BackoffLimit,
+		MaxConcurrentPulls:      src.MaxConcurrentPulls,
 		TTLSecondsAfterFinished: src.TTLSecondsAfterFinished,
 		ActiveDeadlineSeconds:   src.ActiveDeadlineSeconds,
 	}
@@ -403,6 +493,7 @@ func convertImageTagPullPolicyFromV1Beta1(src *v1beta1.ImageTagPullPolicy) *Imag
 	return &ImageTagPullPolicy{
 		TimeoutSeconds:          src.TimeoutSeconds,
 		BackoffLimit:            src.BackoffLimit,
+		MaxConcurrentPulls:      src.MaxConcurrentPulls,
 		TTLSecondsAfterFinished: src.TTLSecondsAfterFinished,
 		ActiveDeadlineSeconds:   src.ActiveDeadlineSeconds,
 	}
@@ -476,6 +567,36 @@ func convertSyncStatusFromV1Beta1(src *v1beta1.SyncStatus) *SyncStatus {
 	}
 }
 
+func convertNodeCapabilitiesToV1Beta1(src *NodeCapabilities) *v1beta1.NodeCapabilities {
+	if src == nil {
+		return nil
+	}
+	dst := &v1beta1.NodeCapabilities{
+		RuntimeName:    src.RuntimeName,
+		RuntimeVersion: src.RuntimeVersion,
+		DaemonVersion:  src.DaemonVersion,
+	}
+	for _, f := range src.Features {
+		dst.Features = append(dst.Features, v1beta1.NodeFeature(f))
+	}
+	return dst
+}
+
+func convertNodeCapabilitiesFromV1Beta1(src *v1beta1.NodeCapabilities) *NodeCapabilities {
+	if src == nil {
+		return nil
+	}
+	dst := &NodeCapabilities{
+		RuntimeName:    src.RuntimeName,
+		RuntimeVersion: src.RuntimeVersion,
+		DaemonVersion:  src.DaemonVersion,
+	}
+	for _, f := range src.Features {
+		dst.Features = append(dst.Features, NodeFeature(f))
+	}
+	return dst
+}
+
 func convertSandboxConfigFromV1Beta1(in *v1beta1.SandboxConfig) *SandboxConfig {
 	if in == nil {
 		return nil
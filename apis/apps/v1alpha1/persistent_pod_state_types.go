@@ -26,6 +26,7 @@ import (
 const (
 	// AnnotationAutoGeneratePersistentPodState indicates kruise will auto generate PersistentPodState object
 	// Need to work with AnnotationRequiredPersistentTopology and AnnotationPreferredPersistentTopology
+	// It works on StatefulSet, Advanced StatefulSet and CloneSet, pods of CloneSet are identified by the instance id
 	AnnotationAutoGeneratePersistentPodState = "kruise.io/auto-generate-persistent-pod-state"
 	// AnnotationRequiredPersistentTopology Pod rebuilt topology required for node labels
 	// for example kruise.io/required-persistent-topology: topology.kubernetes.io/zone[,xxx]
@@ -45,7 +46,7 @@ const (
 type PersistentPodStateSpec struct {
 	// TargetReference contains enough information to let you identify a workload for PersistentPodState
 	// Selector and TargetReference are mutually exclusive, TargetReference is priority to take effect
-	// current only support StatefulSet
+	// current only support StatefulSet and CloneSet
 	TargetReference TargetReference `json:"targetRef"`
 
 	// Persist the annotations information of the pods that need to be saved
@@ -59,16 +60,67 @@ type PersistentPodStateSpec struct {
 	// for example  kubernetes.io/hostname, failure-domain.beta.kubernetes.io/zone
 	PreferredPersistentTopology []PreferredTopologyTerm `json:"preferredPersistentTopology,omitempty"`
 
+	// PersistentTopologies persists the node topologies per term, and each term is either required or preferred,
+	// e.g., a required term of topology.kubernetes.io/zone with a preferred term of kubernetes.io/hostname
+	// keeps the rebuilt pod in the same zone and prefers the same node.
+	// Required terms are injected into the requiredDuringSchedulingIgnoredDuringExecution node affinity of pod,
+	// and preferred terms into the preferredDuringSchedulingIgnoredDuringExecution one with their weights.
+	// At least one Required term is needed unless persistentPodStateRetentionPolicy is WhenDeleted.
+	// +optional
+	PersistentTopologies []PersistentTopologyTerm `json:"persistentTopologies,omitempty"`
+
 	// PersistentPodStateRetentionPolicy describes the policy used for PodState.
 	// The default policy of 'WhenScaled' causes when scale down statefulSet, deleting it.
 	// +optional
 	PersistentPodStateRetentionPolicy PersistentPodStateRetentionPolicyType `json:"persistentPodStateRetentionPolicy,omitempty"`
+
+	// PersistentPodStateRetention bounds the pod states kept for the pods that no longer exist,
+	// so that the states of the pods scaled away long ago do not accumulate in the object.
+	// +optional
+	PersistentPodStateRetention *PersistentPodStateRetention `json:"persistentPodStateRetention,omitempty"`
+}
+
+// PersistentPodStateRetention describes how long and how many pod states of the deleted pods are kept.
+// The states of the existing pods are never pruned.
+type PersistentPodStateRetention struct {
+	// TTLSecondsAfterDeleted is how long the state of a pod is kept after the pod is deleted.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TTLSecondsAfterDeleted *int32 `json:"ttlSecondsAfterDeleted,omitempty"`
+	// MaxEntries is the maximum number of pod states kept, the states of the deleted pods beyond it
+	// are pruned with the earliest deleted first.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxEntries *int32 `json:"maxEntries,omitempty"`
 }
 
 type PreferredTopologyTerm struct {
 	Weight     int32            `json:"weight"`
 	Preference NodeTopologyTerm `json:"preference"`
 }
+
+// PersistentTopologyType is the type of PersistentTopologyTerm.
+type PersistentTopologyType string
+
+const (
+	// PersistentTopologyRequired requires the rebuilt pod to be scheduled to the nodes with the same topology.
+	PersistentTopologyRequired PersistentTopologyType = "Required"
+	// PersistentTopologyPreferred prefers the nodes with the same topology for the rebuilt pod.
+	PersistentTopologyPreferred PersistentTopologyType = "Preferred"
+)
+
+type PersistentTopologyTerm struct {
+	// A list of node selector requirements by node's labels.
+	NodeTopologyKeys []string `json:"nodeTopologyKeys"`
+	// Type is Required or Preferred.
+	// +kubebuilder:validation:Enum=Required;Preferred
+	Type PersistentTopologyType `json:"type"`
+	// Weight of the Preferred term in the range 1-100, defaults to 100.
+	// It must not be set for the Required term.
+	// +optional
+	Weight int32 `json:"weight,omitempty"`
+}
+
 type NodeTopologyTerm struct {
 	// A list of node selector requirements by node's labels.
 	NodeTopologyKeys []string `json:"nodeTopologyKeys"`
@@ -94,6 +146,9 @@ type PersistentPodStateStatus struct {
 	// When the pod is ready, record some status information of the pod, such as: labels, annotations, topologies, etc.
 	// map[string]PodState -> map[Pod.Name]PodState
 	PodStates map[string]PodState `json:"podStates,omitempty"`
+	// PrunedPodStates is the number of pod states pruned by persistentPodStateRetention.
+	// +optional
+	PrunedPodStates int64 `json:"prunedPodStates,omitempty"`
 }
 
 type PodState struct {
@@ -104,6 +159,9 @@ type PodState struct {
 	NodeTopologyLabels map[string]string `json:"nodeTopologyLabels,omitempty"`
 	// pod persistent annotations
 	Annotations map[string]string `json:"annotations,omitempty"`
+	// DeletedTime is the time when the pod was found deleted, which is recorded only if persistentPodStateRetention is set.
+	// +optional
+	DeletedTime *metav1.Time `json:"deletedTime,omitempty"`
 }
 
 // +genclient
@@ -81,6 +81,9 @@ type PodContainerProbe struct {
 	PodConditionType string `json:"podConditionType,omitempty"`
 }
 
+// ContainerProbeSpec is executed by kruise-daemon on the node of the pod, with exactly one of exec, httpGet and tcpSocket.
+// The httpGet and tcpSocket probes dial the pod IP from the host network namespace, so they work for the containers
+// without a shell, and their host can not be specified.
 type ContainerProbeSpec struct {
 	v1.Probe `json:",inline"`
 }
@@ -95,6 +98,18 @@ type ProbeMarkerPolicy struct {
 	Labels map[string]string `json:"labels,omitempty"`
 	// Patch annotations pod.annotations
 	Annotations map[string]string `json:"annotations,omitempty"`
+	// RemoveOnOpposite removes the labels and annotations of this policy from the pod when the probe result is
+	// the opposite of State, so that the markers of the previous result do not linger after the probe flips.
+	// Defaults to true, set it to false to keep the markers until they are overwritten.
+	// +optional
+	// +kubebuilder:default=true
+	RemoveOnOpposite *bool `json:"removeOnOpposite,omitempty"`
+}
+
+// ShouldRemoveOnOpposite returns true if the markers of the policy should be removed when the probe result is
+// the opposite of its state.
+func (p *ProbeMarkerPolicy) ShouldRemoveOnOpposite() bool {
+	return p.RemoveOnOpposite == nil || *p.RemoveOnOpposite
 }
 
 type PodProbeMarkerStatus struct {
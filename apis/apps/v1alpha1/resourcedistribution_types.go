@@ -33,15 +33,42 @@ type ResourceDistributionSpec struct {
 
 	// Targets defines the namespaces that users want to distribute to.
 	Targets ResourceDistributionTargets `json:"targets"`
+
+	// ConflictPolicy defines what to do when a target namespace already has a resource with the same
+	// name which is not distributed by this ResourceDistribution. Defaults to Fail.
+	// +kubebuilder:validation:Enum=Fail;Ignore;Overwrite
+	// +optional
+	ConflictPolicy ResourceDistributionConflictPolicyType `json:"conflictPolicy,omitempty"`
+
+	// ResourceTemplating enables substituting the target namespace into the string data values of the resource,
+	// i.e., data of ConfigMap and stringData of Secret, where "${NAMESPACE}" and the Go template "{{ .Namespace }}"
+	// are replaced with the namespace of each distributed copy. Binary data is never substituted.
+	// +optional
+	ResourceTemplating bool `json:"resourceTemplating,omitempty"`
 }
 
+// ResourceDistributionConflictPolicyType defines how to handle the conflict with existing resources.
+type ResourceDistributionConflictPolicyType string
+
+const (
+	// ResourceDistributionConflictPolicyFail reports the conflicting namespace as failed in status.
+	ResourceDistributionConflictPolicyFail ResourceDistributionConflictPolicyType = "Fail"
+
+	// ResourceDistributionConflictPolicyIgnore skips the conflicting namespace and records it in status.skippedNamespaces.
+	ResourceDistributionConflictPolicyIgnore ResourceDistributionConflictPolicyType = "Ignore"
+
+	// ResourceDistributionConflictPolicyOverwrite adopts and overwrites the existing resource,
+	// unless it is controlled by another controller.
+	ResourceDistributionConflictPolicyOverwrite ResourceDistributionConflictPolicyType = "Overwrite"
+)
+
 // ResourceDistributionTargets defines the targets of Resource.
 // Four options are provided to select target namespaces.
 type ResourceDistributionTargets struct {
-	// Priority: ExcludedNamespaces > AllNamespaces = IncludedNamespaces = NamespaceLabelSelector.
+	// Priority: ExcludedNamespaces = ExcludedNamespacesSelector > AllNamespaces = IncludedNamespaces = NamespaceLabelSelector.
 	// Firstly, ResourceDistributionTargets will parse AllNamespaces, IncludedNamespaces, and NamespaceLabelSelector,
 	// then calculate their union,
-	// At last ExcludedNamespaces will act on the union to remove and exclude the designated namespaces from it.
+	// At last ExcludedNamespaces and ExcludedNamespacesSelector will act on the union to remove and exclude the designated namespaces from it.
 
 	// If AllNamespaces is true, Resource will be distributed to the all namespaces
 	// (except some forbidden namespaces, such as "kube-system" and "kube-public").
@@ -49,10 +76,16 @@ type ResourceDistributionTargets struct {
 	AllNamespaces bool `json:"allNamespaces,omitempty"`
 
 	// If ExcludedNamespaces is not empty, Resource will never be distributed to the listed namespaces.
+	// The names may contain wildcard "*", e.g., "kube-*" excludes all namespaces prefixed with "kube-".
 	// ExcludedNamespaces has the highest priority.
 	// +optional
 	ExcludedNamespaces ResourceDistributionTargetNamespaces `json:"excludedNamespaces,omitempty"`
 
+	// If ExcludedNamespacesSelector is not empty, Resource will never be distributed to the matched namespaces.
+	// ExcludedNamespacesSelector has the same priority as ExcludedNamespaces.
+	// +optional
+	ExcludedNamespacesSelector metav1.LabelSelector `json:"excludedNamespacesSelector,omitempty"`
+
 	// If IncludedNamespaces is not empty, Resource will be distributed to the listed namespaces.
 	// +optional
 	IncludedNamespaces ResourceDistributionTargetNamespaces `json:"includedNamespaces,omitempty"`
@@ -101,6 +134,47 @@ type ResourceDistributionStatus struct {
 
 	// Conditions describe the condition when Resource creating, updating and deleting.
 	Conditions []ResourceDistributionCondition `json:"conditions,omitempty"`
+
+	// SkippedNamespaces describe the target namespaces skipped on purpose, which are not counted as failed,
+	// e.g., the namespaces with conflicting resources when spec.conflictPolicy is Ignore.
+	// +optional
+	SkippedNamespaces []ResourceDistributionSkippedNamespace `json:"skippedNamespaces,omitempty"`
+
+	// FailedNamespaces describe the target namespaces that the resource failed to be distributed to,
+	// each of them is retried with exponential backoff independently.
+	// +optional
+	FailedNamespaces []ResourceDistributionFailedNamespace `json:"failedNamespaces,omitempty"`
+}
+
+// ResourceDistributionSkippedNamespace contains a skipped namespace and the reason.
+type ResourceDistributionSkippedNamespace struct {
+	// Name of the namespace.
+	Name string `json:"name"`
+
+	// Reason why the namespace is skipped, e.g., Conflict.
+	Reason string `json:"reason"`
+}
+
+// ResourceDistributionSkippedReasonConflict means the namespace has a conflicting resource.
+const ResourceDistributionSkippedReasonConflict = "Conflict"
+
+// ResourceDistributionFailedNamespace contains the state of a namespace that the resource failed to be distributed to.
+type ResourceDistributionFailedNamespace struct {
+	// Name of the namespace.
+	Name string `json:"name"`
+
+	// ConditionType is the type of the condition that the failure is summarized in.
+	ConditionType ResourceDistributionConditionType `json:"conditionType"`
+
+	// LastError is the message of the last error occurred when distributing to the namespace.
+	LastError string `json:"lastError,omitempty"`
+
+	// Attempts is the number of consecutive failed attempts.
+	Attempts int32 `json:"attempts,omitempty"`
+
+	// NextRetryTime is the time before which the distribution to the namespace will not be retried,
+	// unless the spec of ResourceDistribution is changed.
+	NextRetryTime metav1.Time `json:"nextRetryTime,omitempty"`
 }
 
 // ResourceDistributionCondition allows a row to be marked with additional information.
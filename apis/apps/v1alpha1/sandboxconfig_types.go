@@ -23,3 +23,16 @@ type SandboxConfig struct {
 	// +optional
 	Annotations map[string]string `json:"annotations,omitempty"`
 }
+
+// ImagePlatform specifies the platform of the image to be pulled from a multi-arch manifest.
+type ImagePlatform struct {
+	// Architecture of the image, such as amd64 or arm64.
+	Architecture string `json:"architecture"`
+
+	// OS of the image, such as linux or windows.
+	OS string `json:"os"`
+
+	// Variant of the architecture, such as v8 for arm64.
+	// +optional
+	Variant string `json:"variant,omitempty"`
+}
@@ -82,6 +82,7 @@ func (scs *SidecarSet) ConvertTo(dst conversion.Hub) error {
 			UpdatedReadyPods:   scs.Status.UpdatedReadyPods,
 			LatestRevision:     scs.Status.LatestRevision,
 			CollisionCount:     scs.Status.CollisionCount,
+			BackfillStatus:     convertBackfillStatusToV1Beta1(scs.Status.BackfillStatus),
 		}
 
 		return nil
@@ -131,6 +132,7 @@ func (scs *SidecarSet) ConvertFrom(src conversion.Hub) error {
 			UpdatedReadyPods:   scsv1beta1.Status.UpdatedReadyPods,
 			LatestRevision:     scsv1beta1.Status.LatestRevision,
 			CollisionCount:     scsv1beta1.Status.CollisionCount,
+			BackfillStatus:     convertBackfillStatusToV1Alpha1(scsv1beta1.Status.BackfillStatus),
 		}
 
 		return nil
@@ -153,6 +155,7 @@ func convertSidecarContainersToV1Beta1(containers []SidecarContainer) []v1beta1.
 			ShareVolumeDevicePolicy: convertShareVolumePolicyPtrToV1Beta1(container.ShareVolumeDevicePolicy),
 			TransferEnv:             convertTransferEnvVarsToV1Beta1(container.TransferEnv),
 			ResourcesPolicy:         convertResourcesPolicyToV1Beta1(container.ResourcesPolicy),
+			ConfigHashFrom:          convertConfigHashSourcesToV1Beta1(container.ConfigHashFrom),
 		}
 	}
 	return result
@@ -172,6 +175,41 @@ func convertSidecarContainersToV1Alpha1(containers []v1beta1.SidecarContainer) [
 			ShareVolumeDevicePolicy: convertShareVolumePolicyPtrToV1Alpha1(container.ShareVolumeDevicePolicy),
 			TransferEnv:             convertTransferEnvVarsToV1Alpha1(container.TransferEnv),
 			ResourcesPolicy:         convertResourcesPolicyToV1Alpha1(container.ResourcesPolicy),
+			ConfigHashFrom:          convertConfigHashSourcesToV1Alpha1(container.ConfigHashFrom),
+		}
+	}
+	return result
+}
+
+func convertConfigHashSourcesToV1Beta1(sources []SidecarConfigHashSource) []v1beta1.SidecarConfigHashSource {
+	if sources == nil {
+		return nil
+	}
+	result := make([]v1beta1.SidecarConfigHashSource, len(sources))
+	for i, source := range sources {
+		result[i] = v1beta1.SidecarConfigHashSource{Keys: source.Keys}
+		if source.ConfigMapRef != nil {
+			result[i].ConfigMapRef = &v1beta1.SidecarConfigObjectReference{Namespace: source.ConfigMapRef.Namespace, Name: source.ConfigMapRef.Name}
+		}
+		if source.SecretRef != nil {
+			result[i].SecretRef = &v1beta1.SidecarConfigObjectReference{Namespace: source.SecretRef.Namespace, Name: source.SecretRef.Name}
+		}
+	}
+	return result
+}
+
+func convertConfigHashSourcesToV1Alpha1(sources []v1beta1.SidecarConfigHashSource) []SidecarConfigHashSource {
+	if sources == nil {
+		return nil
+	}
+	result := make([]SidecarConfigHashSource, len(sources))
+	for i, source := range sources {
+		result[i] = SidecarConfigHashSource{Keys: source.Keys}
+		if source.ConfigMapRef != nil {
+			result[i].ConfigMapRef = &SidecarConfigObjectReference{Namespace: source.ConfigMapRef.Namespace, Name: source.ConfigMapRef.Name}
+		}
+		if source.SecretRef != nil {
+			result[i].SecretRef = &SidecarConfigObjectReference{Namespace: source.SecretRef.Namespace, Name: source.SecretRef.Name}
 		}
 	}
 	return result
@@ -271,15 +309,19 @@ func convertSourceContainerNameSourceToV1Alpha1(source *v1beta1.SourceContainerN
 
 func convertInjectionStrategyToV1Beta1(strategy SidecarSetInjectionStrategy) v1beta1.SidecarSetInjectionStrategy {
 	return v1beta1.SidecarSetInjectionStrategy{
-		Paused:   strategy.Paused,
-		Revision: convertInjectRevisionToV1Beta1(strategy.Revision),
+		Paused:    strategy.Paused,
+		Revision:  convertInjectRevisionToV1Beta1(strategy.Revision),
+		Backfill:  convertBackfillStrategyToV1Beta1(strategy.Backfill),
+		QoSPolicy: convertQoSPolicyToV1Beta1(strategy.QoSPolicy),
 	}
 }
 
 func convertInjectionStrategyToV1Alpha1(strategy v1beta1.SidecarSetInjectionStrategy) SidecarSetInjectionStrategy {
 	return SidecarSetInjectionStrategy{
-		Paused:   strategy.Paused,
-		Revision: convertInjectRevisionToV1Alpha1(strategy.Revision),
+		Paused:    strategy.Paused,
+		Revision:  convertInjectRevisionToV1Alpha1(strategy.Revision),
+		Backfill:  convertBackfillStrategyToV1Alpha1(strategy.Backfill),
+		QoSPolicy: convertQoSPolicyToV1Alpha1(strategy.QoSPolicy),
 	}
 }
 
@@ -305,6 +347,70 @@ func convertInjectRevisionToV1Alpha1(revision *v1beta1.SidecarSetInjectRevision)
 	}
 }
 
+func convertBackfillStrategyToV1Beta1(strategy *SidecarSetBackfillStrategy) *v1beta1.SidecarSetBackfillStrategy {
+	if strategy == nil {
+		return nil
+	}
+	return &v1beta1.SidecarSetBackfillStrategy{
+		Enabled:                   strategy.Enabled,
+		MaxUnavailablePerWorkload: strategy.MaxUnavailablePerWorkload,
+		Deadline:                  strategy.Deadline,
+	}
+}
+
+func convertBackfillStrategyToV1Alpha1(strategy *v1beta1.SidecarSetBackfillStrategy) *SidecarSetBackfillStrategy {
+	if strategy == nil {
+		return nil
+	}
+	return &SidecarSetBackfillStrategy{
+		Enabled:                   strategy.Enabled,
+		MaxUnavailablePerWorkload: strategy.MaxUnavailablePerWorkload,
+		Deadline:                  strategy.Deadline,
+	}
+}
+
+func convertQoSPolicyToV1Beta1(policy *SidecarSetQoSPolicy) *v1beta1.SidecarSetQoSPolicy {
+	if policy == nil {
+		return nil
+	}
+	return &v1beta1.SidecarSetQoSPolicy{
+		Type:             v1beta1.SidecarSetQoSPolicyType(policy.Type),
+		DefaultResources: policy.DefaultResources,
+	}
+}
+
+func convertQoSPolicyToV1Alpha1(policy *v1beta1.SidecarSetQoSPolicy) *SidecarSetQoSPolicy {
+	if policy == nil {
+		return nil
+	}
+	return &SidecarSetQoSPolicy{
+		Type:             SidecarSetQoSPolicyType(policy.Type),
+		DefaultResources: policy.DefaultResources,
+	}
+}
+
+func convertBackfillStatusToV1Beta1(status *SidecarSetBackfillStatus) *v1beta1.SidecarSetBackfillStatus {
+	if status == nil {
+		return nil
+	}
+	out := &v1beta1.SidecarSetBackfillStatus{Phase: v1beta1.SidecarSetBackfillPhase(status.Phase)}
+	for _, w := range status.Workloads {
+		out.Workloads = append(out.Workloads, v1beta1.SidecarSetBackfillWorkloadStatus(w))
+	}
+	return out
+}
+
+func convertBackfillStatusToV1Alpha1(status *v1beta1.SidecarSetBackfillStatus) *SidecarSetBackfillStatus {
+	if status == nil {
+		return nil
+	}
+	out := &SidecarSetBackfillStatus{Phase: SidecarSetBackfillPhase(status.Phase)}
+	for _, w := range status.Workloads {
+		out.Workloads = append(out.Workloads, SidecarSetBackfillWorkloadStatus(w))
+	}
+	return out
+}
+
 func convertUpdateStrategyToV1Beta1(strategy SidecarSetUpdateStrategy) v1beta1.SidecarSetUpdateStrategy {
 	return v1beta1.SidecarSetUpdateStrategy{
 		Type:             v1beta1.SidecarSetUpdateStrategyType(strategy.Type),
@@ -149,6 +149,34 @@ type SidecarContainer struct {
 	// Validation webhook will reject pod creation request if both resourcesPolicy and resources are configured.
 	// +optional
 	ResourcesPolicy *ResourcesPolicy `json:"resourcesPolicy,omitempty"`
+
+	// ConfigHashFrom is the list of ConfigMaps and Secrets referenced by the sidecar container.
+	// When their data change, the SidecarSet produces a new revision and restarts the sidecar container
+	// in the matched pods under the update strategy, without changing its image.
+	// Not supported in initContainers and hot upgrade containers.
+	// +optional
+	ConfigHashFrom []SidecarConfigHashSource `json:"configHashFrom,omitempty"`
+}
+
+// SidecarConfigHashSource is a ConfigMap or Secret to be hashed, one and only one of them should be specified.
+type SidecarConfigHashSource struct {
+	// ConfigMapRef is the ConfigMap to be hashed.
+	// +optional
+	ConfigMapRef *SidecarConfigObjectReference `json:"configMapRef,omitempty"`
+	// SecretRef is the Secret to be hashed.
+	// +optional
+	SecretRef *SidecarConfigObjectReference `json:"secretRef,omitempty"`
+	// Keys are the keys in data to be hashed, empty means all keys.
+	// +optional
+	Keys []string `json:"keys,omitempty"`
+}
+
+// SidecarConfigObjectReference references a ConfigMap or Secret.
+type SidecarConfigObjectReference struct {
+	// Namespace of the object.
+	Namespace string `json:"namespace"`
+	// Name of the object.
+	Name string `json:"name"`
 }
 
 type ShareVolumePolicy struct {
@@ -292,6 +320,59 @@ type SidecarSetInjectionStrategy struct {
 	// this filed, SidecarSet will try to inject specific revision according to
 	// different policies.
 	Revision *SidecarSetInjectRevision `json:"revision,omitempty"`
+
+	// Backfill evicts the matched pods which are not injected, e.g. pods created before the SidecarSet,
+	// so that their workloads recreate them with the sidecar containers injected.
+	// Default is nil, which means the pods are not backfilled.
+	Backfill *SidecarSetBackfillStrategy `json:"backfill,omitempty"`
+
+	// QoSPolicy guards Guaranteed pods from being downgraded to Burstable by the injected sidecar containers
+	// which have no full resources, i.e., no cpu and memory limits equal to their requests.
+	// It is only checked when pods are created. Default is nil, which means the sidecar containers are always injected.
+	// +optional
+	QoSPolicy *SidecarSetQoSPolicy `json:"qosPolicy,omitempty"`
+}
+
+// SidecarSetQoSPolicy indicates what to do if injecting the sidecar containers would downgrade a Guaranteed pod.
+type SidecarSetQoSPolicy struct {
+	// Type is the action taken for the pod, one of Reject, SkipInjection and InheritLimits.
+	Type SidecarSetQoSPolicyType `json:"type"`
+
+	// DefaultResources is set as both the requests and limits of the sidecar containers which have no full resources.
+	// It is required by InheritLimits, and must contain both cpu and memory.
+	// +optional
+	DefaultResources corev1.ResourceList `json:"defaultResources,omitempty"`
+}
+
+type SidecarSetQoSPolicyType string
+
+const (
+	// SidecarSetQoSPolicyReject rejects the creation of the pod.
+	SidecarSetQoSPolicyReject SidecarSetQoSPolicyType = "Reject"
+	// SidecarSetQoSPolicySkipInjection creates the pod without injecting the sidecar containers of the SidecarSet.
+	SidecarSetQoSPolicySkipInjection SidecarSetQoSPolicyType = "SkipInjection"
+	// SidecarSetQoSPolicyInheritLimits sets the defaultResources to the sidecar containers which have no full resources.
+	SidecarSetQoSPolicyInheritLimits SidecarSetQoSPolicyType = "InheritLimits"
+)
+
+// SidecarSetBackfillStrategy indicates how the SidecarSet backfills the pods which are not injected.
+// The pods are always evicted through the eviction API, which honors PodDisruptionBudget and PodUnavailableBudget,
+// and only the pods controlled by workloads which recreate them, i.e., ReplicaSet, ReplicationController, StatefulSet,
+// DaemonSet and CloneSet, are evicted, except the ones that the sidecar containers would not be injected into when
+// recreated, e.g. skipped by qosPolicy, injected with a canary SidecarSet instead, or conflicting with the containers.
+type SidecarSetBackfillStrategy struct {
+	// Enabled indicates whether to evict the pods which are not injected. Default is false.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// MaxUnavailablePerWorkload is the maximum number of unavailable pods of each workload during backfill,
+	// including the pods evicted and not recreated yet.
+	// Value can be an absolute number (ex: 5) or a percentage of the matched pods of the workload (ex: 10%).
+	// Default is 1.
+	MaxUnavailablePerWorkload *intstr.IntOrString `json:"maxUnavailablePerWorkload,omitempty"`
+
+	// Deadline is the time after which no more pods will be evicted.
+	// +optional
+	Deadline *metav1.Time `json:"deadline,omitempty"`
 }
 
 type SidecarSetInjectRevision struct {
@@ -402,6 +483,52 @@ type SidecarSetStatus struct {
 	// uses this field as a collision avoidance mechanism when it needs to create the name for the
 	// newest ControllerRevision.
 	CollisionCount *int32 `json:"collisionCount,omitempty"`
+
+	// BackfillStatus is the progress of backfilling the sidecar containers into the pods which are not injected.
+	BackfillStatus *SidecarSetBackfillStatus `json:"backfillStatus,omitempty"`
+}
+
+type SidecarSetBackfillPhase string
+
+const (
+	// SidecarSetBackfillRunning means there are pods of workloads still waiting for backfill.
+	SidecarSetBackfillRunning SidecarSetBackfillPhase = "Running"
+	// SidecarSetBackfillCompleted means all matched pods controlled by workloads are injected, except the skipped ones.
+	SidecarSetBackfillCompleted SidecarSetBackfillPhase = "Completed"
+	// SidecarSetBackfillDeadlineExceeded means the deadline has passed and no more pods will be evicted.
+	SidecarSetBackfillDeadlineExceeded SidecarSetBackfillPhase = "DeadlineExceeded"
+)
+
+// SidecarSetBackfillStatus is the progress of SidecarSet backfill.
+type SidecarSetBackfillStatus struct {
+	// Phase is the phase of backfill.
+	Phase SidecarSetBackfillPhase `json:"phase,omitempty"`
+
+	// Workloads is the backfill progress of each workload owning pods which are not injected.
+	Workloads []SidecarSetBackfillWorkloadStatus `json:"workloads,omitempty"`
+}
+
+// SidecarSetBackfillWorkloadStatus is the backfill progress of a workload.
+type SidecarSetBackfillWorkloadStatus struct {
+	// APIVersion, Kind and Name of the workload which controls the pods.
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	// Namespace of the workload.
+	Namespace string `json:"namespace"`
+
+	// UninjectedPods is the number of the active pods of the workload which are not injected.
+	UninjectedPods int32 `json:"uninjectedPods"`
+
+	// SkippedPods is the number of the uninjected pods which are not evicted, because the sidecar containers
+	// would not be injected into them when recreated.
+	SkippedPods int32 `json:"skippedPods,omitempty"`
+
+	// EvictedPods is the number of the pods of the workload that have been evicted by backfill.
+	EvictedPods int32 `json:"evictedPods,omitempty"`
+
+	// Message is the reason why the last eviction was rejected, e.g. by PodDisruptionBudget.
+	Message string `json:"message,omitempty"`
 }
 
 // +genclient
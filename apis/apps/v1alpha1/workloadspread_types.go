@@ -41,6 +41,22 @@ type WorkloadSpreadSpec struct {
 	// ScheduleStrategy indicates the strategy the WorkloadSpread used to preform the schedule between each of subsets.
 	// +optional
 	ScheduleStrategy WorkloadSpreadScheduleStrategy `json:"scheduleStrategy,omitempty"`
+
+	// ProtectionPolicy indicates the policy to protect the pods of each subset from disruption.
+	// +optional
+	ProtectionPolicy *WorkloadSpreadProtectionPolicy `json:"protectionPolicy,omitempty"`
+}
+
+// WorkloadSpreadProtectionPolicy defines the disruption protection for the pods of each subset.
+type WorkloadSpreadProtectionPolicy struct {
+	// PerSubsetMaxUnavailable is the maximum number of pods in one subset that can be unavailable
+	// when disrupted by the operations protected by PodUnavailableBudget.
+	// It is enforced in addition to the PodUnavailableBudget matching the pods, so that the unavailability
+	// allowed by the PodUnavailableBudget can not be concentrated in one subset.
+	// Value can be an absolute number (ex: 1) or a percentage of the pods in the subset (ex: 10%).
+	// Percentage is calculated by rounding up.
+	// +optional
+	PerSubsetMaxUnavailable *intstr.IntOrString `json:"perSubsetMaxUnavailable,omitempty"`
 }
 
 // TargetReference contains enough information to let you identify a workload
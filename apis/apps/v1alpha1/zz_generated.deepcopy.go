@@ -152,6 +152,10 @@ func (in *AdvancedCronJobSpec) DeepCopyInto(out *AdvancedCronJobSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.SuspendUntil != nil {
+		in, out := &in.SuspendUntil, &out.SuspendUntil
+		*out = (*in).DeepCopy()
+	}
 	if in.SuccessfulJobsHistoryLimit != nil {
 		in, out := &in.SuccessfulJobsHistoryLimit, &out.SuccessfulJobsHistoryLimit
 		*out = new(int32)
@@ -389,6 +393,45 @@ func (in *CloneSetCondition) DeepCopy() *CloneSetCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSetDecommission) DeepCopyInto(out *CloneSetDecommission) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetDecommission.
+func (in *CloneSetDecommission) DeepCopy() *CloneSetDecommission {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSetDecommission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSetDecommissionStatus) DeepCopyInto(out *CloneSetDecommissionStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetDecommissionStatus.
+func (in *CloneSetDecommissionStatus) DeepCopy() *CloneSetDecommissionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSetDecommissionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloneSetList) DeepCopyInto(out *CloneSetList) {
 	*out = *in
@@ -421,6 +464,21 @@ func (in *CloneSetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSetReportPolicy) DeepCopyInto(out *CloneSetReportPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetReportPolicy.
+func (in *CloneSetReportPolicy) DeepCopy() *CloneSetReportPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSetReportPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloneSetScaleStrategy) DeepCopyInto(out *CloneSetScaleStrategy) {
 	*out = *in
@@ -434,6 +492,13 @@ func (in *CloneSetScaleStrategy) DeepCopyInto(out *CloneSetScaleStrategy) {
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.Decommission != nil {
+		in, out := &in.Decommission, &out.Decommission
+		*out = make([]CloneSetDecommission, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetScaleStrategy.
@@ -511,6 +576,18 @@ func (in *CloneSetStatus) DeepCopyInto(out *CloneSetStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RecreateReasons != nil {
+		in, out := &in.RecreateReasons, &out.RecreateReasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DecommissionStatuses != nil {
+		in, out := &in.DecommissionStatuses, &out.DecommissionStatuses
+		*out = make([]CloneSetDecommissionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetStatus.
@@ -573,6 +650,11 @@ func (in *CloneSetUpdateStrategy) DeepCopyInto(out *CloneSetUpdateStrategy) {
 		*out = new(pub.InPlaceUpdateStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReportPolicy != nil {
+		in, out := &in.ReportPolicy, &out.ReportPolicy
+		*out = new(CloneSetReportPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetUpdateStrategy.
@@ -598,6 +680,16 @@ func (in *CompletionPolicy) DeepCopyInto(out *CompletionPolicy) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.FailedNodeLimit != nil {
+		in, out := &in.FailedNodeLimit, &out.FailedNodeLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedImageLimit != nil {
+		in, out := &in.FailedImageLimit, &out.FailedImageLimit
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompletionPolicy.
@@ -694,6 +786,11 @@ func (in *ContainerRecreateRequestContainer) DeepCopyInto(out *ContainerRecreate
 		*out = new(ProbeHandler)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PreStopHook != nil {
+		in, out := &in.PreStopHook, &out.PreStopHook
+		*out = new(ContainerRecreateRequestPreStopHook)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Ports != nil {
 		in, out := &in.Ports, &out.Ports
 		*out = make([]corev1.ContainerPort, len(*in))
@@ -734,6 +831,23 @@ func (in *ContainerRecreateRequestContainerContext) DeepCopy() *ContainerRecreat
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerRecreateRequestContainerRecreateState) DeepCopyInto(out *ContainerRecreateRequestContainerRecreateState) {
 	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ExitCode != nil {
+		in, out := &in.ExitCode, &out.ExitCode
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StoppedAt != nil {
+		in, out := &in.StoppedAt, &out.StoppedAt
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerRecreateRequestContainerRecreateState.
@@ -778,6 +892,156 @@ func (in *ContainerRecreateRequestList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerRecreateRequestSet) DeepCopyInto(out *ContainerRecreateRequestSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerRecreateRequestSet.
+func (in *ContainerRecreateRequestSet) DeepCopy() *ContainerRecreateRequestSet {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerRecreateRequestSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContainerRecreateRequestSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerRecreateRequestSetList) DeepCopyInto(out *ContainerRecreateRequestSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ContainerRecreateRequestSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerRecreateRequestSetList.
+func (in *ContainerRecreateRequestSetList) DeepCopy() *ContainerRecreateRequestSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerRecreateRequestSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ContainerRecreateRequestSetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerRecreateRequestSetSpec) DeepCopyInto(out *ContainerRecreateRequestSetSpec) {
+	*out = *in
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]ContainerRecreateRequestContainer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(ContainerRecreateRequestStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerRecreateRequestSetSpec.
+func (in *ContainerRecreateRequestSetSpec) DeepCopy() *ContainerRecreateRequestSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerRecreateRequestSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerRecreateRequestSetStatus) DeepCopyInto(out *ContainerRecreateRequestSetStatus) {
+	*out = *in
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerRecreateRequestSetStatus.
+func (in *ContainerRecreateRequestSetStatus) DeepCopy() *ContainerRecreateRequestSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerRecreateRequestSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerRecreateRequestPreStopHook) DeepCopyInto(out *ContainerRecreateRequestPreStopHook) {
+	*out = *in
+	if in.Exec != nil {
+		in, out := &in.Exec, &out.Exec
+		*out = new(corev1.ExecAction)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPGet != nil {
+		in, out := &in.HTTPGet, &out.HTTPGet
+		*out = new(corev1.HTTPGetAction)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerRecreateRequestPreStopHook.
+func (in *ContainerRecreateRequestPreStopHook) DeepCopy() *ContainerRecreateRequestPreStopHook {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerRecreateRequestPreStopHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerRecreateRequestSpec) DeepCopyInto(out *ContainerRecreateRequestSpec) {
 	*out = *in
@@ -825,7 +1089,9 @@ func (in *ContainerRecreateRequestStatus) DeepCopyInto(out *ContainerRecreateReq
 	if in.ContainerRecreateStates != nil {
 		in, out := &in.ContainerRecreateStates, &out.ContainerRecreateStates
 		*out = make([]ContainerRecreateRequestContainerRecreateState, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -963,6 +1229,21 @@ func (in *DaemonSetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonSetNodeClassAvailability) DeepCopyInto(out *DaemonSetNodeClassAvailability) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonSetNodeClassAvailability.
+func (in *DaemonSetNodeClassAvailability) DeepCopy() *DaemonSetNodeClassAvailability {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonSetNodeClassAvailability)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DaemonSetPatch) DeepCopyInto(out *DaemonSetPatch) {
 	*out = *in
@@ -984,6 +1265,25 @@ func (in *DaemonSetPatch) DeepCopy() *DaemonSetPatch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonSetPatchStatus) DeepCopyInto(out *DaemonSetPatchStatus) {
+	*out = *in
+	if in.LastRenderedTime != nil {
+		in, out := &in.LastRenderedTime, &out.LastRenderedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonSetPatchStatus.
+func (in *DaemonSetPatchStatus) DeepCopy() *DaemonSetPatchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonSetPatchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DaemonSetSpec) DeepCopyInto(out *DaemonSetSpec) {
 	*out = *in
@@ -1043,6 +1343,18 @@ func (in *DaemonSetStatus) DeepCopyInto(out *DaemonSetStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SlowestNodeClasses != nil {
+		in, out := &in.SlowestNodeClasses, &out.SlowestNodeClasses
+		*out = make([]DaemonSetNodeClassAvailability, len(*in))
+		copy(*out, *in)
+	}
+	if in.PatchStatuses != nil {
+		in, out := &in.PatchStatuses, &out.PatchStatuses
+		*out = make([]DaemonSetPatchStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonSetStatus.
@@ -1190,6 +1502,21 @@ func (in *EphemeralJobList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EphemeralJobPodStatus) DeepCopyInto(out *EphemeralJobPodStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EphemeralJobPodStatus.
+func (in *EphemeralJobPodStatus) DeepCopy() *EphemeralJobPodStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EphemeralJobPodStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EphemeralJobSpec) DeepCopyInto(out *EphemeralJobSpec) {
 	*out = *in
@@ -1198,6 +1525,11 @@ func (in *EphemeralJobSpec) DeepCopyInto(out *EphemeralJobSpec) {
 		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PodNames != nil {
+		in, out := &in.PodNames, &out.PodNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Replicas != nil {
 		in, out := &in.Replicas, &out.Replicas
 		*out = new(int32)
@@ -1214,6 +1546,11 @@ func (in *EphemeralJobSpec) DeepCopyInto(out *EphemeralJobSpec) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.TargetActiveDeadlineSeconds != nil {
+		in, out := &in.TargetActiveDeadlineSeconds, &out.TargetActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
 	if in.TTLSecondsAfterFinished != nil {
 		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
 		*out = new(int32)
@@ -1249,6 +1586,11 @@ func (in *EphemeralJobStatus) DeepCopyInto(out *EphemeralJobStatus) {
 		in, out := &in.CompletionTime, &out.CompletionTime
 		*out = (*in).DeepCopy()
 	}
+	if in.PodStatuses != nil {
+		in, out := &in.PodStatuses, &out.PodStatuses
+		*out = make([]EphemeralJobPodStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EphemeralJobStatus.
@@ -1264,6 +1606,11 @@ func (in *EphemeralJobStatus) DeepCopy() *EphemeralJobStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FailedImageStatus) DeepCopyInto(out *FailedImageStatus) {
 	*out = *in
+	if in.FailedNodes != nil {
+		in, out := &in.FailedNodes, &out.FailedNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailedImageStatus.
@@ -1389,7 +1736,7 @@ func (in *ImageListPullJobStatus) DeepCopyInto(out *ImageListPullJobStatus) {
 			if (*in)[i] != nil {
 				in, out := &(*in)[i], &(*out)[i]
 				*out = new(FailedImageStatus)
-				**out = **in
+				(*in).DeepCopyInto(*out)
 			}
 		}
 	}
@@ -1405,6 +1752,21 @@ func (in *ImageListPullJobStatus) DeepCopy() *ImageListPullJobStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePlatform) DeepCopyInto(out *ImagePlatform) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePlatform.
+func (in *ImagePlatform) DeepCopy() *ImagePlatform {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePlatform)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImagePullJob) DeepCopyInto(out *ImagePullJob) {
 	*out = *in
@@ -1429,7 +1791,22 @@ func (in *ImagePullJob) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
-	return nil
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePullJobImageStatus) DeepCopyInto(out *ImagePullJobImageStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullJobImageStatus.
+func (in *ImagePullJobImageStatus) DeepCopy() *ImagePullJobImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePullJobImageStatus)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -1473,6 +1850,13 @@ func (in *ImagePullJobNodeSelector) DeepCopyInto(out *ImagePullJobNodeSelector)
 		copy(*out, *in)
 	}
 	in.LabelSelector.DeepCopyInto(&out.LabelSelector)
+	if in.MatchFields != nil {
+		in, out := &in.MatchFields, &out.MatchFields
+		*out = make([]corev1.NodeSelectorRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullJobNodeSelector.
@@ -1485,6 +1869,28 @@ func (in *ImagePullJobNodeSelector) DeepCopy() *ImagePullJobNodeSelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePullJobNodeStates) DeepCopyInto(out *ImagePullJobNodeStates) {
+	*out = *in
+	if in.WaitingNodes != nil {
+		in, out := &in.WaitingNodes, &out.WaitingNodes
+		*out = make([]ImagePullJobWaitingNode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullJobNodeStates.
+func (in *ImagePullJobNodeStates) DeepCopy() *ImagePullJobNodeStates {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePullJobNodeStates)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImagePullJobPodSelector) DeepCopyInto(out *ImagePullJobPodSelector) {
 	*out = *in
@@ -1504,6 +1910,11 @@ func (in *ImagePullJobPodSelector) DeepCopy() *ImagePullJobPodSelector {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImagePullJobSpec) DeepCopyInto(out *ImagePullJobSpec) {
 	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.ImagePullJobTemplate.DeepCopyInto(&out.ImagePullJobTemplate)
 }
 
@@ -1533,6 +1944,16 @@ func (in *ImagePullJobStatus) DeepCopyInto(out *ImagePullJobStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ImageStatuses != nil {
+		in, out := &in.ImageStatuses, &out.ImageStatuses
+		*out = make([]ImagePullJobImageStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeStates != nil {
+		in, out := &in.NodeStates, &out.NodeStates
+		*out = new(ImagePullJobNodeStates)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullJobStatus.
@@ -1579,6 +2000,11 @@ func (in *ImagePullJobTemplate) DeepCopyInto(out *ImagePullJobTemplate) {
 		*out = new(SandboxConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Platform != nil {
+		in, out := &in.Platform, &out.Platform
+		*out = new(ImagePlatform)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullJobTemplate.
@@ -1591,6 +2017,25 @@ func (in *ImagePullJobTemplate) DeepCopy() *ImagePullJobTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePullJobWaitingNode) DeepCopyInto(out *ImagePullJobWaitingNode) {
+	*out = *in
+	if in.EstimatedStartTime != nil {
+		in, out := &in.EstimatedStartTime, &out.EstimatedStartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullJobWaitingNode.
+func (in *ImagePullJobWaitingNode) DeepCopy() *ImagePullJobWaitingNode {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePullJobWaitingNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageSpec) DeepCopyInto(out *ImageSpec) {
 	*out = *in
@@ -1658,6 +2103,11 @@ func (in *ImageTagPullPolicy) DeepCopyInto(out *ImageTagPullPolicy) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.MaxConcurrentPulls != nil {
+		in, out := &in.MaxConcurrentPulls, &out.MaxConcurrentPulls
+		*out = new(int32)
+		**out = **in
+	}
 	if in.TTLSecondsAfterFinished != nil {
 		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
 		*out = new(int32)
@@ -1697,6 +2147,11 @@ func (in *ImageTagSpec) DeepCopyInto(out *ImageTagSpec) {
 		*out = make([]corev1.ObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.Platform != nil {
+		in, out := &in.Platform, &out.Platform
+		*out = new(ImagePlatform)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageTagSpec.
@@ -1771,6 +2226,26 @@ func (in *ManualUpdate) DeepCopy() *ManualUpdate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeCapabilities) DeepCopyInto(out *NodeCapabilities) {
+	*out = *in
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = make([]NodeFeature, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeCapabilities.
+func (in *NodeCapabilities) DeepCopy() *NodeCapabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeCapabilities)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeImage) DeepCopyInto(out *NodeImage) {
 	*out = *in
@@ -1867,6 +2342,11 @@ func (in *NodeImageStatus) DeepCopyInto(out *NodeImageStatus) {
 		*out = new(SyncStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = new(NodeCapabilities)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeImageStatus.
@@ -2076,6 +2556,31 @@ func (in *PersistentPodStateList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistentPodStateRetention) DeepCopyInto(out *PersistentPodStateRetention) {
+	*out = *in
+	if in.TTLSecondsAfterDeleted != nil {
+		in, out := &in.TTLSecondsAfterDeleted, &out.TTLSecondsAfterDeleted
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxEntries != nil {
+		in, out := &in.MaxEntries, &out.MaxEntries
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentPodStateRetention.
+func (in *PersistentPodStateRetention) DeepCopy() *PersistentPodStateRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistentPodStateRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PersistentPodStateSpec) DeepCopyInto(out *PersistentPodStateSpec) {
 	*out = *in
@@ -2097,6 +2602,18 @@ func (in *PersistentPodStateSpec) DeepCopyInto(out *PersistentPodStateSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PersistentTopologies != nil {
+		in, out := &in.PersistentTopologies, &out.PersistentTopologies
+		*out = make([]PersistentTopologyTerm, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PersistentPodStateRetention != nil {
+		in, out := &in.PersistentPodStateRetention, &out.PersistentPodStateRetention
+		*out = new(PersistentPodStateRetention)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentPodStateSpec.
@@ -2131,6 +2648,26 @@ func (in *PersistentPodStateStatus) DeepCopy() *PersistentPodStateStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistentTopologyTerm) DeepCopyInto(out *PersistentTopologyTerm) {
+	*out = *in
+	if in.NodeTopologyKeys != nil {
+		in, out := &in.NodeTopologyKeys, &out.NodeTopologyKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentTopologyTerm.
+func (in *PersistentTopologyTerm) DeepCopy() *PersistentTopologyTerm {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistentTopologyTerm)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodContainerProbe) DeepCopyInto(out *PodContainerProbe) {
 	*out = *in
@@ -2316,6 +2853,10 @@ func (in *PodState) DeepCopyInto(out *PodState) {
 			(*out)[key] = val
 		}
 	}
+	if in.DeletedTime != nil {
+		in, out := &in.DeletedTime, &out.DeletedTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodState.
@@ -2391,6 +2932,11 @@ func (in *ProbeMarkerPolicy) DeepCopyInto(out *ProbeMarkerPolicy) {
 			(*out)[key] = val
 		}
 	}
+	if in.RemoveOnOpposite != nil {
+		in, out := &in.RemoveOnOpposite, &out.RemoveOnOpposite
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbeMarkerPolicy.
@@ -2416,6 +2962,11 @@ func (in *PullPolicy) DeepCopyInto(out *PullPolicy) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.MaxConcurrentPullsPerNode != nil {
+		in, out := &in.MaxConcurrentPullsPerNode, &out.MaxConcurrentPullsPerNode
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullPolicy.
@@ -2491,6 +3042,22 @@ func (in *ResourceDistributionCondition) DeepCopy() *ResourceDistributionConditi
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceDistributionFailedNamespace) DeepCopyInto(out *ResourceDistributionFailedNamespace) {
+	*out = *in
+	in.NextRetryTime.DeepCopyInto(&out.NextRetryTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceDistributionFailedNamespace.
+func (in *ResourceDistributionFailedNamespace) DeepCopy() *ResourceDistributionFailedNamespace {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceDistributionFailedNamespace)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceDistributionList) DeepCopyInto(out *ResourceDistributionList) {
 	*out = *in
@@ -2538,6 +3105,21 @@ func (in *ResourceDistributionNamespace) DeepCopy() *ResourceDistributionNamespa
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceDistributionSkippedNamespace) DeepCopyInto(out *ResourceDistributionSkippedNamespace) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceDistributionSkippedNamespace.
+func (in *ResourceDistributionSkippedNamespace) DeepCopy() *ResourceDistributionSkippedNamespace {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceDistributionSkippedNamespace)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceDistributionSpec) DeepCopyInto(out *ResourceDistributionSpec) {
 	*out = *in
@@ -2565,6 +3147,18 @@ func (in *ResourceDistributionStatus) DeepCopyInto(out *ResourceDistributionStat
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SkippedNamespaces != nil {
+		in, out := &in.SkippedNamespaces, &out.SkippedNamespaces
+		*out = make([]ResourceDistributionSkippedNamespace, len(*in))
+		copy(*out, *in)
+	}
+	if in.FailedNamespaces != nil {
+		in, out := &in.FailedNamespaces, &out.FailedNamespaces
+		*out = make([]ResourceDistributionFailedNamespace, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceDistributionStatus.
@@ -2601,6 +3195,7 @@ func (in *ResourceDistributionTargetNamespaces) DeepCopy() *ResourceDistribution
 func (in *ResourceDistributionTargets) DeepCopyInto(out *ResourceDistributionTargets) {
 	*out = *in
 	in.ExcludedNamespaces.DeepCopyInto(&out.ExcludedNamespaces)
+	in.ExcludedNamespacesSelector.DeepCopyInto(&out.ExcludedNamespacesSelector)
 	in.IncludedNamespaces.DeepCopyInto(&out.IncludedNamespaces)
 	in.NamespaceLabelSelector.DeepCopyInto(&out.NamespaceLabelSelector)
 }
@@ -2810,6 +3405,51 @@ func (in *ShareVolumePolicy) DeepCopy() *ShareVolumePolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarConfigHashSource) DeepCopyInto(out *SidecarConfigHashSource) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(SidecarConfigObjectReference)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(SidecarConfigObjectReference)
+		**out = **in
+	}
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarConfigHashSource.
+func (in *SidecarConfigHashSource) DeepCopy() *SidecarConfigHashSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarConfigHashSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarConfigObjectReference) DeepCopyInto(out *SidecarConfigObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarConfigObjectReference.
+func (in *SidecarConfigObjectReference) DeepCopy() *SidecarConfigObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarConfigObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SidecarContainer) DeepCopyInto(out *SidecarContainer) {
 	*out = *in
@@ -2833,6 +3473,13 @@ func (in *SidecarContainer) DeepCopyInto(out *SidecarContainer) {
 		*out = new(ResourcesPolicy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ConfigHashFrom != nil {
+		in, out := &in.ConfigHashFrom, &out.ConfigHashFrom
+		*out = make([]SidecarConfigHashSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarContainer.
@@ -2887,6 +3534,65 @@ func (in *SidecarSet) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarSetBackfillStatus) DeepCopyInto(out *SidecarSetBackfillStatus) {
+	*out = *in
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]SidecarSetBackfillWorkloadStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetBackfillStatus.
+func (in *SidecarSetBackfillStatus) DeepCopy() *SidecarSetBackfillStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarSetBackfillStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarSetBackfillStrategy) DeepCopyInto(out *SidecarSetBackfillStrategy) {
+	*out = *in
+	if in.MaxUnavailablePerWorkload != nil {
+		in, out := &in.MaxUnavailablePerWorkload, &out.MaxUnavailablePerWorkload
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Deadline != nil {
+		in, out := &in.Deadline, &out.Deadline
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetBackfillStrategy.
+func (in *SidecarSetBackfillStrategy) DeepCopy() *SidecarSetBackfillStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarSetBackfillStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarSetBackfillWorkloadStatus) DeepCopyInto(out *SidecarSetBackfillWorkloadStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetBackfillWorkloadStatus.
+func (in *SidecarSetBackfillWorkloadStatus) DeepCopy() *SidecarSetBackfillWorkloadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarSetBackfillWorkloadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SidecarSetInjectRevision) DeepCopyInto(out *SidecarSetInjectRevision) {
 	*out = *in
@@ -2920,6 +3626,16 @@ func (in *SidecarSetInjectionStrategy) DeepCopyInto(out *SidecarSetInjectionStra
 		*out = new(SidecarSetInjectRevision)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Backfill != nil {
+		in, out := &in.Backfill, &out.Backfill
+		*out = new(SidecarSetBackfillStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QoSPolicy != nil {
+		in, out := &in.QoSPolicy, &out.QoSPolicy
+		*out = new(SidecarSetQoSPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetInjectionStrategy.
@@ -2986,6 +3702,28 @@ func (in *SidecarSetPatchPodMetadata) DeepCopy() *SidecarSetPatchPodMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarSetQoSPolicy) DeepCopyInto(out *SidecarSetQoSPolicy) {
+	*out = *in
+	if in.DefaultResources != nil {
+		in, out := &in.DefaultResources, &out.DefaultResources
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetQoSPolicy.
+func (in *SidecarSetQoSPolicy) DeepCopy() *SidecarSetQoSPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarSetQoSPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SidecarSetSpec) DeepCopyInto(out *SidecarSetSpec) {
 	*out = *in
@@ -3059,6 +3797,11 @@ func (in *SidecarSetStatus) DeepCopyInto(out *SidecarSetStatus) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.BackfillStatus != nil {
+		in, out := &in.BackfillStatus, &out.BackfillStatus
+		*out = new(SidecarSetBackfillStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetStatus.
@@ -3838,6 +4581,26 @@ func (in *WorkloadSpreadList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadSpreadProtectionPolicy) DeepCopyInto(out *WorkloadSpreadProtectionPolicy) {
+	*out = *in
+	if in.PerSubsetMaxUnavailable != nil {
+		in, out := &in.PerSubsetMaxUnavailable, &out.PerSubsetMaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadSpreadProtectionPolicy.
+func (in *WorkloadSpreadProtectionPolicy) DeepCopy() *WorkloadSpreadProtectionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadSpreadProtectionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkloadSpreadScheduleStrategy) DeepCopyInto(out *WorkloadSpreadScheduleStrategy) {
 	*out = *in
@@ -3879,6 +4642,11 @@ func (in *WorkloadSpreadSpec) DeepCopyInto(out *WorkloadSpreadSpec) {
 		}
 	}
 	in.ScheduleStrategy.DeepCopyInto(&out.ScheduleStrategy)
+	if in.ProtectionPolicy != nil {
+		in, out := &in.ProtectionPolicy, &out.ProtectionPolicy
+		*out = new(WorkloadSpreadProtectionPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadSpreadSpec.
@@ -53,6 +53,12 @@ type AdvancedCronJobSpec struct {
 	// +optional
 	Paused *bool `json:"paused,omitempty" protobuf:"bytes,4,opt,name=paused"`
 
+	// SuspendUntil suspends the cron job until the given time. No job will be
+	// scheduled while now is before suspendUntil, and then the cron job resumes
+	// automatically without replaying the runs missed during the suspension.
+	// +optional
+	SuspendUntil *metav1.Time `json:"suspendUntil,omitempty" protobuf:"bytes,9,opt,name=suspendUntil"`
+
 	// The number of successful finished jobs to retain.
 	// This is a pointer to distinguish between explicit zero and not specified.
 	// +optional
@@ -74,6 +74,20 @@ type CompletionPolicy struct {
 	// Only works for Always type
 	// +optional
 	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty" protobuf:"varint,4,opt,name=ttlSecondsAfterFinished"`
+
+	// FailedNodeLimit is the number of nodes allowed to fail pulling an image, the image is still regarded as
+	// succeeded if its failed nodes do not exceed the limit. Defaults to 0.
+	// Only works for ImageListPullJob, and it is propagated to the ImagePullJobs created for the images.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	FailedNodeLimit *int32 `json:"failedNodeLimit,omitempty" protobuf:"varint,5,opt,name=failedNodeLimit"`
+
+	// FailedImageLimit is the number of images allowed to fail, the job is still completed as Succeeded
+	// if its failed images do not exceed the limit. Defaults to 0.
+	// Only works for ImageListPullJob.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	FailedImageLimit *int32 `json:"failedImageLimit,omitempty" protobuf:"varint,6,opt,name=failedImageLimit"`
 }
 
 // CompletionPolicyType indicates the type of completion policy
@@ -128,6 +142,10 @@ type BroadcastJobStatus struct {
 	// +optional
 	Desired int32 `json:"desired" protobuf:"varint,7,opt,name=desired"`
 
+	// The percentage of desired pods which reached phase Succeeded, rounded down.
+	// +optional
+	ProgressPercent int32 `json:"progressPercent,omitempty"`
+
 	// The phase of the job.
 	// +optional
 	Phase BroadcastJobPhase `json:"phase" protobuf:"varint,8,opt,name=phase"`
@@ -189,6 +207,10 @@ const (
 	// JobFailed means the job has failed its execution. A failed job means the job has either exceeded the
 	// ActiveDeadlineSeconds limit, or the aggregated number of container restarts for all pods have exceeded the RestartLimit.
 	JobFailed JobConditionType = "Failed"
+
+	// JobProgress reports the progress of the job, e.g. "3120/4000 (78%) succeeded, 12 failed, 3 excluded".
+	// Its message is refreshed at most every 30 seconds until the job is finished.
+	JobProgress JobConditionType = "Progress"
 )
 
 // JobCondition describes current state of a job.
@@ -221,6 +243,7 @@ type JobCondition struct {
 // +kubebuilder:printcolumn:name="Active",type="integer",JSONPath=".status.active",description="The number of actively running pods."
 // +kubebuilder:printcolumn:name="Succeeded",type="integer",JSONPath=".status.succeeded",description="The number of pods which reached phase Succeeded."
 // +kubebuilder:printcolumn:name="Failed",type="integer",JSONPath=".status.failed",description="The number of pods which reached phase Failed."
+// +kubebuilder:printcolumn:name="Progress",type="integer",JSONPath=".status.progressPercent",description="The percentage of desired pods which reached phase Succeeded."
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp",description="CreationTimestamp is a timestamp representing the server time when this object was created. It is not guaranteed to be set in happens-before order across separate operations. Clients may not set this value. It is represented in RFC3339 form and is in UTC."
 
 // BroadcastJob is the Schema for the broadcastjobs API
@@ -0,0 +1,78 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	v1 "k8s.io/api/core/v1"
+)
+
+// GetCloneSetRolloutState computes the rollout state of the CloneSet from its spec and the given status,
+// which is usually &cs.Status. It is the single source of status.rolloutState, and it is exported so that
+// kubectl plugins and operators can share the exact logic with the controller.
+//
+// The state is a stable contract, decided by the first matched rule below:
+//  1. Progressing, if the status has not observed the latest generation of the CloneSet.
+//  2. Idle, if spec.replicas is 0 and there is no pod left.
+//  3. Completed, if all the desired pods have been updated to the update revision and are available.
+//  4. Degraded, if the Progressing condition reports ProgressDeadlineExceeded.
+//  5. Blocked, if the FailedScale or FailedUpdate condition is true.
+//  6. Paused, if updateStrategy.rollingUpdate.paused is true, or the pods expected by the partition have been
+//     updated and available.
+//  7. Progressing, otherwise.
+//
+// Any change of these rules must be reflected in its contract tests.
+func GetCloneSetRolloutState(cs *CloneSet, status *CloneSetStatus) CloneSetRolloutState {
+	replicas := int32(1)
+	if cs.Spec.Replicas != nil {
+		replicas = *cs.Spec.Replicas
+	}
+
+	if status.ObservedGeneration < cs.Generation {
+		return CloneSetRolloutProgressing
+	}
+	if replicas == 0 && status.Replicas == 0 {
+		return CloneSetRolloutIdle
+	}
+	if status.CurrentRevision == status.UpdateRevision &&
+		status.Replicas == replicas &&
+		status.UpdatedReplicas == replicas &&
+		status.UpdatedAvailableReplicas == replicas {
+		return CloneSetRolloutCompleted
+	}
+
+	for _, c := range status.Conditions {
+		if c.Type == CloneSetConditionTypeProgressing && c.Reason == string(CloneSetProgressDeadlineExceeded) {
+			return CloneSetRolloutDegraded
+		}
+	}
+	for _, c := range status.Conditions {
+		if (c.Type == CloneSetConditionFailedScale || c.Type == CloneSetConditionFailedUpdate) && c.Status == v1.ConditionTrue {
+			return CloneSetRolloutBlocked
+		}
+	}
+
+	if rollingUpdate := cs.Spec.UpdateStrategy.RollingUpdate; rollingUpdate != nil {
+		if rollingUpdate.Paused {
+			return CloneSetRolloutPaused
+		}
+		if rollingUpdate.Partition != nil && status.ExpectedUpdatedReplicas < replicas &&
+			status.UpdatedAvailableReplicas >= status.ExpectedUpdatedReplicas {
+			return CloneSetRolloutPaused
+		}
+	}
+	return CloneSetRolloutProgressing
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"fmt"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+// The tests below are the contract of GetCloneSetRolloutState. Do not change the expectations
+// without documenting the change of the rules for the external consumers of status.rolloutState.
+
+type rolloutReplicasClass string
+
+const (
+	replicasZeroWithoutPods rolloutReplicasClass = "zeroWithoutPods"
+	replicasZeroWithPods    rolloutReplicasClass = "zeroWithPods"
+	replicasNonZero         rolloutReplicasClass = "nonZero"
+)
+
+type rolloutFailureClass string
+
+const (
+	failureNone        rolloutFailureClass = "none"
+	failureScale       rolloutFailureClass = "scale"
+	failureUpdate      rolloutFailureClass = "update"
+	failureScaleFalse  rolloutFailureClass = "scaleFalse"
+	failureUpdateFalse rolloutFailureClass = "updateFalse"
+)
+
+type rolloutPartitionClass string
+
+const (
+	partitionNone       rolloutPartitionClass = "none"
+	partitionReached    rolloutPartitionClass = "reached"
+	partitionNotReached rolloutPartitionClass = "notReached"
+)
+
+// rolloutInputClass is a class of inputs to GetCloneSetRolloutState.
+type rolloutInputClass struct {
+	stale     bool
+	replicas  rolloutReplicasClass
+	updated   bool
+	deadline  bool
+	failure   rolloutFailureClass
+	paused    bool
+	partition rolloutPartitionClass
+}
+
+func (c rolloutInputClass) String() string {
+	return fmt.Sprintf("stale=%v,replicas=%s,updated=%v,deadline=%v,failure=%s,paused=%v,partition=%s",
+		c.stale, c.replicas, c.updated, c.deadline, c.failure, c.paused, c.partition)
+}
+
+// build returns a CloneSet and its status of the input class.
+func (c rolloutInputClass) build() (*CloneSet, *CloneSetStatus) {
+	cs := &CloneSet{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		Spec: CloneSetSpec{
+			Replicas:       ptr.To[int32](4),
+			UpdateStrategy: CloneSetUpdateStrategy{RollingUpdate: &RollingUpdateCloneSetStrategy{Paused: c.paused}},
+		},
+	}
+	status := &CloneSetStatus{
+		ObservedGeneration:       2,
+		Replicas:                 4,
+		UpdateRevision:           "v2",
+		CurrentRevision:          "v1",
+		UpdatedReplicas:          2,
+		UpdatedAvailableReplicas: 2,
+		ExpectedUpdatedReplicas:  4,
+	}
+	if c.stale {
+		status.ObservedGeneration = 1
+	}
+
+	switch c.partition {
+	case partitionReached:
+		cs.Spec.UpdateStrategy.RollingUpdate.Partition = ptr.To(intstr.FromInt32(2))
+		status.ExpectedUpdatedReplicas = 2
+	case partitionNotReached:
+		cs.Spec.UpdateStrategy.RollingUpdate.Partition = ptr.To(intstr.FromInt32(2))
+		status.ExpectedUpdatedReplicas = 2
+		status.UpdatedAvailableReplicas = 1
+	}
+	if c.updated {
+		status.CurrentRevision = status.UpdateRevision
+		status.UpdatedReplicas = 4
+		status.UpdatedAvailableReplicas = 4
+	}
+
+	switch c.replicas {
+	case replicasZeroWithoutPods:
+		cs.Spec.Replicas = ptr.To[int32](0)
+		status.Replicas, status.UpdatedReplicas, status.UpdatedAvailableReplicas, status.ExpectedUpdatedReplicas = 0, 0, 0, 0
+	case replicasZeroWithPods:
+		cs.Spec.Replicas = ptr.To[int32](0)
+		status.ExpectedUpdatedReplicas = 0
+	}
+
+	if c.deadline {
+		status.Conditions = append(status.Conditions, CloneSetCondition{
+			Type: CloneSetConditionTypeProgressing, Status: v1.ConditionFalse, Reason: string(CloneSetProgressDeadlineExceeded)})
+	} else {
+		status.Conditions = append(status.Conditions, CloneSetCondition{
+			Type: CloneSetConditionTypeProgressing, Status: v1.ConditionTrue, Reason: string(CloneSetProgressUpdated)})
+	}
+	switch c.failure {
+	case failureScale:
+		status.Conditions = append(status.Conditions, CloneSetCondition{Type: CloneSetConditionFailedScale, Status: v1.ConditionTrue})
+	case failureUpdate:
+		status.Conditions = append(status.Conditions, CloneSetCondition{Type: CloneSetConditionFailedUpdate, Status: v1.ConditionTrue})
+	case failureScaleFalse:
+		status.Conditions = append(status.Conditions, CloneSetCondition{Type: CloneSetConditionFailedScale, Status: v1.ConditionFalse})
+	case failureUpdateFalse:
+		status.Conditions = append(status.Conditions, CloneSetCondition{Type: CloneSetConditionFailedUpdate, Status: v1.ConditionFalse})
+	}
+	return cs, status
+}
+
+// expectedRolloutState is the contract in terms of the input classes.
+func expectedRolloutState(c rolloutInputClass) CloneSetRolloutState {
+	switch {
+	case c.stale:
+		return CloneSetRolloutProgressing
+	case c.replicas == replicasZeroWithoutPods:
+		return CloneSetRolloutIdle
+	case c.updated && c.replicas == replicasNonZero:
+		return CloneSetRolloutCompleted
+	case c.deadline:
+		return CloneSetRolloutDegraded
+	case c.failure == failureScale || c.failure == failureUpdate:
+		return CloneSetRolloutBlocked
+	case c.paused:
+		return CloneSetRolloutPaused
+	case c.partition == partitionReached && c.replicas == replicasNonZero:
+		return CloneSetRolloutPaused
+	default:
+		return CloneSetRolloutProgressing
+	}
+}
+
+func TestGetCloneSetRolloutStateContract(t *testing.T) {
+	seen := map[CloneSetRolloutState]int{}
+	for _, stale := range []bool{false, true} {
+		for _, replicas := range []rolloutReplicasClass{replicasZeroWithoutPods, replicasZeroWithPods, replicasNonZero} {
+			for _, updated := range []bool{false, true} {
+				for _, deadline := range []bool{false, true} {
+					for _, failure := range []rolloutFailureClass{failureNone, failureScale, failureUpdate, failureScaleFalse, failureUpdateFalse} {
+						for _, paused := range []bool{false, true} {
+							for _, partition := range []rolloutPartitionClass{partitionNone, partitionReached, partitionNotReached} {
+								c := rolloutInputClass{stale: stale, replicas: replicas, updated: updated, deadline: deadline,
+									failure: failure, paused: paused, partition: partition}
+								cs, status := c.build()
+								expected := expectedRolloutState(c)
+								if got := GetCloneSetRolloutState(cs, status); got != expected {
+									t.Errorf("%s: expected %s, got %s", c, expected, got)
+								}
+								seen[expected]++
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	for _, state := range []CloneSetRolloutState{CloneSetRolloutIdle, CloneSetRolloutProgressing, CloneSetRolloutPaused,
+		CloneSetRolloutBlocked, CloneSetRolloutDegraded, CloneSetRolloutCompleted} {
+		if seen[state] == 0 {
+			t.Errorf("expected state %s covered by the contract", state)
+		}
+	}
+}
+
+func TestGetCloneSetRolloutState(t *testing.T) {
+	tests := []struct {
+		name     string
+		class    rolloutInputClass
+		expected CloneSetRolloutState
+	}{
+		{
+			name:     "rolling out",
+			class:    rolloutInputClass{replicas: replicasNonZero, failure: failureNone, partition: partitionNone},
+			expected: CloneSetRolloutProgressing,
+		},
+		{
+			name:     "spec not observed after completed",
+			class:    rolloutInputClass{stale: true, replicas: replicasNonZero, updated: true, failure: failureNone, partition: partitionNone},
+			expected: CloneSetRolloutProgressing,
+		},
+		{
+			name:     "scaled to zero",
+			class:    rolloutInputClass{replicas: replicasZeroWithoutPods, failure: failureScale, partition: partitionNone},
+			expected: CloneSetRolloutIdle,
+		},
+		{
+			name:     "scaling in to zero",
+			class:    rolloutInputClass{replicas: replicasZeroWithPods, updated: true, failure: failureNone, partition: partitionNone},
+			expected: CloneSetRolloutProgressing,
+		},
+		{
+			name:     "completed with a stale failure condition",
+			class:    rolloutInputClass{replicas: replicasNonZero, updated: true, deadline: true, failure: failureUpdate, paused: true, partition: partitionNone},
+			expected: CloneSetRolloutCompleted,
+		},
+		{
+			name:     "deadline exceeded while failing to update",
+			class:    rolloutInputClass{replicas: replicasNonZero, deadline: true, failure: failureUpdate, partition: partitionNone},
+			expected: CloneSetRolloutDegraded,
+		},
+		{
+			name:     "failed to scale while paused",
+			class:    rolloutInputClass{replicas: replicasNonZero, failure: failureScale, paused: true, partition: partitionNone},
+			expected: CloneSetRolloutBlocked,
+		},
+		{
+			name:     "recovered from failure",
+			class:    rolloutInputClass{replicas: replicasNonZero, failure: failureScaleFalse, partition: partitionNone},
+			expected: CloneSetRolloutProgressing,
+		},
+		{
+			name:     "paused",
+			class:    rolloutInputClass{replicas: replicasNonZero, failure: failureNone, paused: true, partition: partitionNotReached},
+			expected: CloneSetRolloutPaused,
+		},
+		{
+			name:     "held by partition",
+			class:    rolloutInputClass{replicas: replicasNonZero, failure: failureNone, partition: partitionReached},
+			expected: CloneSetRolloutPaused,
+		},
+		{
+			name:     "rolling out to partition",
+			class:    rolloutInputClass{replicas: replicasNonZero, failure: failureNone, partition: partitionNotReached},
+			expected: CloneSetRolloutProgressing,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cs, status := tt.class.build()
+			if got := GetCloneSetRolloutState(cs, status); got != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
@@ -26,6 +26,13 @@ import (
 	appspub "github.com/openkruise/kruise/apis/apps/pub"
 )
 
+const (
+	// DaemonSetPatchTargetVersionsAnnotation is the comma-separated Kubernetes versions of the nodes targeted by the
+	// DaemonSet, e.g. "v1.26,v1.30". If set, the webhook warns about the fields in patches which are not supported
+	// on the oldest targeted version.
+	DaemonSetPatchTargetVersionsAnnotation = "apps.kruise.io/daemonset-patch-target-versions"
+)
+
 // DaemonSetUpdateStrategy is a struct used to control the update strategy for a DaemonSet.
 type DaemonSetUpdateStrategy struct {
 	// Type of daemon set update. Can be "RollingUpdate" or "OnDelete". Default is RollingUpdate.
@@ -113,6 +120,12 @@ type RollingUpdateDaemonSet struct {
 	// daemon set controller.
 	// +optional
 	Paused *bool `json:"paused,omitempty"`
+
+	// WaitNodeReadyBeforePatch indicates that the daemon pods on the nodes matched by spec.patches
+	// will not be recreated for update until the nodes are Ready and schedulable, to avoid churning
+	// pods on the nodes in maintenance. Pods that can be updated in-place are not affected.
+	// +optional
+	WaitNodeReadyBeforePatch bool `json:"waitNodeReadyBeforePatch,omitempty"`
 }
 
 // DaemonSetSpec defines the desired state of DaemonSet
@@ -170,8 +183,33 @@ type DaemonSetSpec struct {
 	// based on node label matching
 	// +optional
 	Patches []DaemonSetPatch `json:"patches,omitempty"`
+
+	// PatchOrderBy decides the order to apply the matched patches, which is "priority" by default.
+	// +optional
+	PatchOrderBy DaemonSetPatchOrderByType `json:"patchOrderBy,omitempty"`
 }
 
+// DaemonSetPatchOrderByType is the sort key of patches to decide the order to apply them.
+// +kubebuilder:validation:Enum=priority;order
+type DaemonSetPatchOrderByType string
+
+const (
+	// PriorityDaemonSetPatchOrderBy applies the patches in the ascending order of priority,
+	// so that the patches with higher priority override the lower ones.
+	PriorityDaemonSetPatchOrderBy DaemonSetPatchOrderByType = "priority"
+	// OrderDaemonSetPatchOrderBy applies the patches in the ascending order of order, regardless of their priority.
+	OrderDaemonSetPatchOrderBy DaemonSetPatchOrderByType = "order"
+)
+
+// DaemonSetPatchEncodingType is the encoding of the patch body.
+// +kubebuilder:validation:Enum=gzip
+type DaemonSetPatchEncodingType string
+
+const (
+	// GzipDaemonSetPatchEncoding means the patch is a string of the base64 encoded gzip of the patch.
+	GzipDaemonSetPatchEncoding DaemonSetPatchEncodingType = "gzip"
+)
+
 // DaemonSetScaleStrategy defines strategies for DaemonSet scaling.
 type DaemonSetScaleStrategy struct {
 	// PartitionedScaling indicates daemon pods created in manage phase will be controlled by partition.
@@ -189,12 +227,66 @@ type DaemonSetPatch struct {
 
 	// Patch contains the patch to apply to the pod template
 	// The patch follows Kubernetes strategic merge patch format
+	// Lists are merged by their patch merge keys, e.g., topologySpreadConstraints are merged by topologyKey,
+	// use "$patch: replace" to replace the whole list.
+	// Structs such as probes are merged field by field, e.g., patching timeoutSeconds of a readinessProbe keeps its handler,
+	// use "$patch: replace" in the probe to replace it with another handler.
+	// The patch can also be a string of YAML document, e.g., written as a block scalar in manifests.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
 	Patch runtime.RawExtension `json:"patch"`
 
+	// PatchEncoding is the encoding of the patch. If it is "gzip", the patch is a string of the base64 encoded
+	// gzip of the patch in JSON or YAML, which reduces the size of the object for large and repetitive patches.
+	// +optional
+	PatchEncoding DaemonSetPatchEncodingType `json:"patchEncoding,omitempty"`
+
 	// Priority defines the order of patch application when multiple patches match
-	// Higher values have higher priority
+	// Higher values have higher priority: patches are applied in ascending order of priority,
+	// so the fields set by a patch with higher priority override the ones set by lower priorities.
+	// It must be non-negative, and patches with the same priority are applied in the order they are listed.
+	// +kubebuilder:validation:Minimum=0
 	// +optional
 	Priority int32 `json:"priority,omitempty"`
+
+	// Order defines the sequence of patch application when patchOrderBy is "order", lower values are applied first.
+	// It must be non-negative, and patches with the same order are applied in the order they are listed.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Order int32 `json:"order,omitempty"`
+
+	// ApplyToNewPodsOnly indicates that adding, changing or removing this patch does not recreate the existing pods,
+	// it only takes effect on the pods created afterwards. So the pods on the matched nodes are eventually consistent
+	// with the patch, until they are recreated for other reasons, e.g., evicted, deleted manually, or updated by
+	// changes of the template or other patches.
+	// +optional
+	ApplyToNewPodsOnly bool `json:"applyToNewPodsOnly,omitempty"`
+
+	// Shadow indicates that the patch is rendered but not applied to the daemon pods, so that a new patch can be
+	// validated against the nodes it matches before promoted. The hash of the pod template rendered with the
+	// shadow patches and its diff from the live template are recorded in the annotations of the pods on the
+	// matched nodes, for comparison with the live render hash.
+	// +optional
+	Shadow bool `json:"shadow,omitempty"`
+
+	// UpdateStrategyType overrides the type of spec.updateStrategy for the daemon pods on the matched nodes, e.g.,
+	// OnDelete for a node pool whose pods should only be replaced when deleted manually, or RollingUpdate for a node
+	// pool in a DaemonSet of OnDelete. If multiple patches with it match a node, the one applied last wins.
+	// The nodes overridden to RollingUpdate are rolled out with spec.updateStrategy.rollingUpdate, which defaults
+	// to one unavailable node at a time if it is not set.
+	// +kubebuilder:validation:Enum=RollingUpdate;OnDelete
+	// +optional
+	UpdateStrategyType DaemonSetUpdateStrategyType `json:"updateStrategyType,omitempty"`
+
+	// Description describes what the patch is for. It is surfaced in events and status.
+	// +kubebuilder:validation:MaxLength=256
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Owner is who to ask about the patch, e.g., a team or an email address. It is surfaced in events and status.
+	// +kubebuilder:validation:MaxLength=128
+	// +optional
+	Owner string `json:"owner,omitempty"`
 }
 
 // DaemonSetStatus defines the observed state of DaemonSet
@@ -254,6 +346,56 @@ type DaemonSetStatus struct {
 
 	// UpdateRevision is the controller-revision-hash, which represents the latest version of the DaemonSet.
 	UpdateRevision string `json:"updateRevision,omitempty"`
+
+	// SlowestNodeClasses are the node classes whose daemon pods took the longest time from creation to
+	// availability during the last completed rollout, at most 5 classes ordered from the slowest.
+	// +optional
+	SlowestNodeClasses []DaemonSetNodeClassAvailability `json:"slowestNodeClasses,omitempty"`
+
+	// PatchStatuses are the observations of spec.patches, in the same order as spec.patches.
+	// +optional
+	PatchStatuses []DaemonSetPatchStatus `json:"patchStatuses,omitempty"`
+}
+
+const (
+	// DaemonSetPatchRenderCircuitOpen means the controller paused creating and recreating the daemon pods on
+	// the nodes matched by spec.patches, because rendering the patches failed on too many nodes in a row.
+	DaemonSetPatchRenderCircuitOpen appsv1.DaemonSetConditionType = "PatchRenderCircuitOpen"
+)
+
+// DaemonSetPatchStatus describes the nodes that a patch in spec.patches is applied to.
+type DaemonSetPatchStatus struct {
+	// MatchedNodes is the number of nodes which should run the daemon pod and match the selector of the patch.
+	MatchedNodes int32 `json:"matchedNodes"`
+
+	// LastRenderedTime is the latest time when a daemon pod of the update revision was created
+	// on the matched nodes with the patch rendered into its template.
+	// +optional
+	LastRenderedTime *metav1.Time `json:"lastRenderedTime,omitempty"`
+
+	// Description of the patch.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Owner of the patch.
+	// +optional
+	Owner string `json:"owner,omitempty"`
+}
+
+// DaemonSetNodeClassAvailability describes how long the daemon pods on one class of nodes took to become available.
+type DaemonSetNodeClassAvailability struct {
+	// NodeClass is the value of the node class label on the nodes, the label key is configured
+	// by the controller flag daemonset-node-class-label.
+	NodeClass string `json:"nodeClass"`
+
+	// Nodes is the number of nodes of this class observed during the rollout.
+	Nodes int32 `json:"nodes"`
+
+	// AverageSeconds is the average duration from creation to availability of the daemon pods.
+	AverageSeconds int32 `json:"averageSeconds"`
+
+	// MaxSeconds is the longest duration from creation to availability of the daemon pods.
+	MaxSeconds int32 `json:"maxSeconds"`
 }
 
 // +genclient
@@ -50,10 +50,21 @@ type ImageListPullJobStatus struct {
 	// +optional
 	Completed int32 `json:"completed"`
 
-	// The number of image pull job which are finished and status.Succeeded==status.Desired.
+	// The number of image pull job which are finished and the failed nodes of which do not exceed
+	// completionPolicy.failedNodeLimit.
 	// +optional
 	Succeeded int32 `json:"succeeded"`
 
+	// The number of image pull job which are finished and the failed nodes of which exceed
+	// completionPolicy.failedNodeLimit.
+	// +optional
+	Failed int32 `json:"failed,omitempty"`
+
+	// Phase is the terminal state of the job once it is completed, it is Failed if the failed images
+	// exceed completionPolicy.failedImageLimit, otherwise Succeeded.
+	// +optional
+	Phase ImageListPullJobPhase `json:"phase,omitempty"`
+
 	// The status of ImagePullJob which has the failed nodes(status.Failed>0) .
 	// +optional
 	FailedImageStatuses []*FailedImageStatus `json:"failedImageStatuses,omitempty"`
@@ -72,8 +83,26 @@ type FailedImageStatus struct {
 	// The text prompt for job running status.
 	// +optional
 	Message string `json:"message,omitempty"`
+
+	// The nodes that failed to pull the image.
+	// +optional
+	FailedNodes []string `json:"failedNodes,omitempty"`
+
+	// Tolerated indicates the failed nodes do not exceed completionPolicy.failedNodeLimit.
+	// +optional
+	Tolerated bool `json:"tolerated,omitempty"`
 }
 
+// ImageListPullJobPhase is the terminal state of ImageListPullJob.
+type ImageListPullJobPhase string
+
+const (
+	// ImageListPullJobSucceeded means the job has completed with the failed images within completionPolicy.failedImageLimit.
+	ImageListPullJobSucceeded ImageListPullJobPhase = "Succeeded"
+	// ImageListPullJobFailed means the job has completed with the failed images beyond completionPolicy.failedImageLimit.
+	ImageListPullJobFailed ImageListPullJobPhase = "Failed"
+)
+
 // +genclient
 // +k8s:openapi-gen=true
 // +kubebuilder:object:root=true
@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -47,6 +48,19 @@ type SandboxConfig struct {
 	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
+// ImagePlatform specifies the platform of the image to be pulled from a multi-arch manifest.
+type ImagePlatform struct {
+	// Architecture of the image, such as amd64 or arm64.
+	Architecture string `json:"architecture"`
+
+	// OS of the image, such as linux or windows.
+	OS string `json:"os"`
+
+	// Variant of the architecture, such as v8 for arm64.
+	// +optional
+	Variant string `json:"variant,omitempty"`
+}
+
 // PullPolicy defines the policy of the pulling task
 type PullPolicy struct {
 	// Specifies the timeout of the pulling task.
@@ -55,9 +69,16 @@ type PullPolicy struct {
 	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
 
 	// Specifies the number of retries before marking the pulling task failed.
+	// Failed pulls are retried with exponential backoff.
 	// Defaults to 3
 	// +optional
 	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// MaxConcurrentPullsPerNode limits the number of images pulled concurrently on each node
+	// while the image of this job is being pulled, to avoid saturating the registry and node network.
+	// If not specified, only the worker pool size of kruise-daemon limits the concurrency.
+	// +optional
+	MaxConcurrentPullsPerNode *int32 `json:"maxConcurrentPullsPerNode,omitempty"`
 }
 
 type ImagePullJobTemplate struct {
@@ -67,6 +88,11 @@ type ImagePullJobTemplate struct {
 	// +optional
 	PullSecrets []string `json:"pullSecrets,omitempty"`
 
+	// ServiceAccountName is the name of a ServiceAccount in the same namespace,
+	// whose imagePullSecrets will also be used for pulling the image in addition to PullSecrets.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
 	// Selector is a query over nodes that should match the job.
 	// nil to match all nodes.
 	// +optional
@@ -77,6 +103,11 @@ type ImagePullJobTemplate struct {
 	// +optional
 	PodSelector *ImagePullJobPodSelector `json:"podSelector,omitempty"`
 
+	// SkipUnschedulableNodes indicates whether to exclude the unschedulable (cordoned) nodes
+	// from the nodes selected by Selector or PodSelector.
+	// +optional
+	SkipUnschedulableNodes bool `json:"skipUnschedulableNodes,omitempty"`
+
 	// Parallelism is the requested parallelism, it can be set to any non-negative value. If it is unspecified,
 	// it defaults to 1. If it is specified as 0, then the Job is effectively paused until it is increased.
 	// +optional
@@ -95,6 +126,12 @@ type ImagePullJobTemplate struct {
 	// +optional
 	SandboxConfig *SandboxConfig `json:"sandboxConfig,omitempty"`
 
+	// Platform specifies the platform of images to be pulled from multi-arch manifests.
+	// If not specified, the platform of each node is pulled.
+	// Nodes whose runtime is not able to pull the image of the platform will fail the pulling task.
+	// +optional
+	Platform *ImagePlatform `json:"platform,omitempty"`
+
 	// Image pull policy.
 	// One of Always, IfNotPresent. Defaults to IfNotPresent.
 	// +optional
@@ -117,14 +154,28 @@ type ImagePullJobNodeSelector struct {
 	// LabelSelector is a label query over nodes that should match the job.
 	// +optional
 	metav1.LabelSelector `json:",inline"`
+
+	// MatchFields is a list of requirements over node fields, which are ANDed with the label selector.
+	// Supported fields are metadata.name, status.nodeInfo.architecture and status.nodeInfo.operatingSystem,
+	// and supported operators are In and NotIn.
+	// +optional
+	MatchFields []corev1.NodeSelectorRequirement `json:"matchFields,omitempty"`
 }
 
 // Reuse CompletionPolicy and CompletionPolicyType defined in broadcastjob_types.go within this package
 
 // ImagePullJobSpec defines the desired state of ImagePullJob
 type ImagePullJobSpec struct {
-	// Image is the image to be pulled by the job
-	Image                string `json:"image"`
+	// Image is the image to be pulled by the job.
+	// Exactly one of Image and Images should be specified.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// Images is a list of images to be pulled by the job.
+	// Exactly one of Image and Images should be specified.
+	// +optional
+	Images []string `json:"images,omitempty"`
+
 	ImagePullJobTemplate `json:",inline"`
 }
 
@@ -153,6 +204,11 @@ type ImagePullJobStatus struct {
 	// +optional
 	Failed int32 `json:"failed"`
 
+	// Progress is the average pulling progress of the images on the desired nodes, which is between 0-100.
+	// The nodes succeeded or failed are regarded as 100, and the nodes not synced yet are regarded as 0.
+	// +optional
+	Progress int32 `json:"progress,omitempty"`
+
 	// The text prompt for job running status.
 	// +optional
 	Message string `json:"message,omitempty"`
@@ -160,6 +216,73 @@ type ImagePullJobStatus struct {
 	// The nodes that failed to pull the image.
 	// +optional
 	FailedNodes []string `json:"failedNodes,omitempty"`
+
+	// ImageStatuses is the aggregated pulling status of each image, only set when spec.images is specified.
+	// +optional
+	ImageStatuses []ImagePullJobImageStatus `json:"imageStatuses,omitempty"`
+
+	// NodeStates is the breakdown of the nodes by pulling state, with the queue positions of the waiting nodes.
+	// +optional
+	NodeStates *ImagePullJobNodeStates `json:"nodeStates,omitempty"`
+}
+
+// ImagePullJobNodeStates is the breakdown of the nodes of the job by pulling state.
+type ImagePullJobNodeStates struct {
+	// The number of nodes waiting for the pulling tasks to be synced, which are limited by parallelism.
+	Waiting int32 `json:"waiting"`
+
+	// The number of nodes pulling the images.
+	Pulling int32 `json:"pulling"`
+
+	// The number of nodes which have pulled all the images.
+	Succeeded int32 `json:"succeeded"`
+
+	// The number of nodes which failed to pull the images.
+	Failed int32 `json:"failed"`
+
+	// AveragePullSeconds is the average duration observed from the pulling tasks synced to the nodes
+	// till they succeeded, it is not set before any node succeeded.
+	// +optional
+	AveragePullSeconds int32 `json:"averagePullSeconds,omitempty"`
+
+	// WaitingNodes are the first nodes in the waiting queue, in the order they will be synced.
+	// +optional
+	WaitingNodes []ImagePullJobWaitingNode `json:"waitingNodes,omitempty"`
+}
+
+// ImagePullJobWaitingNode is the queue position of a node waiting for the pulling tasks.
+type ImagePullJobWaitingNode struct {
+	// Name of the node.
+	Name string `json:"name"`
+
+	// QueueAhead is the number of waiting nodes ahead of this node.
+	QueueAhead int32 `json:"queueAhead"`
+
+	// EstimatedStartTime is the best-effort estimation of when the pulling tasks will be synced to this node,
+	// derived from AveragePullSeconds. It is not set if no pulling duration has been observed.
+	// +optional
+	EstimatedStartTime *metav1.Time `json:"estimatedStartTime,omitempty"`
+}
+
+// ImagePullJobImageStatus is the aggregated pulling status of an image in the job
+type ImagePullJobImageStatus struct {
+	// Image is the image to be pulled
+	Image string `json:"image"`
+
+	// The desired number of pulling tasks of this image.
+	Desired int32 `json:"desired"`
+
+	// The number of actively running pulling tasks of this image.
+	// +optional
+	Active int32 `json:"active"`
+
+	// The number of pulling tasks of this image which reached phase Succeeded.
+	// +optional
+	Succeeded int32 `json:"succeeded"`
+
+	// The number of pulling tasks of this image which reached phase Failed.
+	// +optional
+	Failed int32 `json:"failed"`
 }
 
 // +genclient
@@ -200,8 +323,8 @@ func (in *ImagePullJob) DeepCopyInto(out *ImagePullJob) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 func (in *ImagePullJob) DeepCopy() *ImagePullJob {
@@ -227,7 +350,9 @@ func (in *ImagePullJobList) DeepCopyInto(out *ImagePullJobList) {
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
 		*out = make([]ImagePullJob, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
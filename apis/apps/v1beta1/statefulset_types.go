@@ -298,6 +298,57 @@ type StatefulSetSpec struct {
 	// enabled, which is beta.
 	// +optional
 	Ordinals *StatefulSetOrdinals `json:"ordinals,omitempty"`
+
+	// ordinalAffinity pins the Pods of specific ordinals to specific nodes, e.g., the nodes with
+	// the local volumes for the ordinals. The nodeSelectorTerm of the entry matching the ordinal of
+	// a Pod is merged into the required node affinity of the Pod when it is created.
+	// Ordinals in different entries must not overlap. Changing the mapping only recreates the Pods
+	// whose expected nodeSelectorTerm has been changed.
+	// +optional
+	OrdinalAffinity []StatefulSetOrdinalAffinity `json:"ordinalAffinity,omitempty"`
+
+	// hibernation makes scaling the StatefulSet to zero replicas a hibernation, which preserves the placement
+	// of the Pods and their PVCs for the resume.
+	// +optional
+	Hibernation *StatefulSetHibernation `json:"hibernation,omitempty"`
+}
+
+// StatefulSetHibernation defines the hibernation mode of StatefulSet.
+type StatefulSetHibernation struct {
+	// Enabled indicates the StatefulSet hibernates when it is scaled to zero replicas. The node and PVCs of each
+	// ordinal are recorded into status.hibernatedReplicas, and the PVCs are retained regardless of
+	// persistentVolumeClaimRetentionPolicy. When it is scaled up again, the Pods are recreated with the preferred
+	// node affinity to their recorded nodes.
+	Enabled bool `json:"enabled"`
+}
+
+// StatefulSetHibernatedReplica is the record of an ordinal of the hibernated StatefulSet.
+type StatefulSetHibernatedReplica struct {
+	// Ordinal of the Pod.
+	Ordinal int32 `json:"ordinal"`
+	// NodeName is the last node of the Pod, empty if it was not scheduled or the node has disappeared.
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+	// PersistentVolumeClaims are the PVCs of the Pod created from volumeClaimTemplates.
+	// +optional
+	PersistentVolumeClaims []StatefulSetHibernatedClaim `json:"persistentVolumeClaims,omitempty"`
+}
+
+// StatefulSetHibernatedClaim is the PVC of a hibernated Pod with the volume bound to it.
+type StatefulSetHibernatedClaim struct {
+	// Name of the PVC.
+	Name string `json:"name"`
+	// VolumeName is the PersistentVolume bound to the PVC, empty if not bound.
+	// +optional
+	VolumeName string `json:"volumeName,omitempty"`
+}
+
+// StatefulSetOrdinalAffinity defines the node affinity for Pods of the ordinals.
+type StatefulSetOrdinalAffinity struct {
+	// Ordinals of the Pods, which can be numbers or ranges, such as [1, 3-5].
+	Ordinals []intstr.IntOrString `json:"ordinals"`
+	// NodeSelectorTerm is the term that nodes of the Pods must match.
+	NodeSelectorTerm v1.NodeSelectorTerm `json:"nodeSelectorTerm"`
 }
 
 // StatefulSetScaleStrategy defines strategies for pods scale.
@@ -369,12 +420,20 @@ type StatefulSetStatus struct {
 	// to match any changes made to the volumeClaimTemplates, ensuring synchronization
 	// between the defined templates and the actual PersistentVolumeClaims in use.
 	VolumeClaims []VolumeClaimStatus `json:"volumeClaims,omitempty"`
+
+	// HibernatedReplicas records the node and PVCs of each ordinal when the StatefulSet hibernates.
+	// The record of an ordinal is removed once its Pod is scheduled after the resume.
+	// +optional
+	HibernatedReplicas []StatefulSetHibernatedReplica `json:"hibernatedReplicas,omitempty"`
 }
 
 // These are valid conditions of a statefulset.
 const (
 	FailedCreatePod apps.StatefulSetConditionType = "FailedCreatePod"
 	FailedUpdatePod apps.StatefulSetConditionType = "FailedUpdatePod"
+	// StatefulSetHibernated is true when the StatefulSet with hibernation enabled has been scaled to zero
+	// and all of its Pods have been deleted.
+	StatefulSetHibernated apps.StatefulSetConditionType = "Hibernated"
 )
 
 // +genclient
@@ -107,6 +107,10 @@ func (in *AdvancedCronJobSpec) DeepCopyInto(out *AdvancedCronJobSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.SuspendUntil != nil {
+		in, out := &in.SuspendUntil, &out.SuspendUntil
+		*out = (*in).DeepCopy()
+	}
 	if in.SuccessfulJobsHistoryLimit != nil {
 		in, out := &in.SuccessfulJobsHistoryLimit, &out.SuccessfulJobsHistoryLimit
 		*out = new(int32)
@@ -327,6 +331,45 @@ func (in *CloneSetCondition) DeepCopy() *CloneSetCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSetDecommission) DeepCopyInto(out *CloneSetDecommission) {
+	*out = *in
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetDecommission.
+func (in *CloneSetDecommission) DeepCopy() *CloneSetDecommission {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSetDecommission)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSetDecommissionStatus) DeepCopyInto(out *CloneSetDecommissionStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetDecommissionStatus.
+func (in *CloneSetDecommissionStatus) DeepCopy() *CloneSetDecommissionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSetDecommissionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloneSetList) DeepCopyInto(out *CloneSetList) {
 	*out = *in
@@ -359,6 +402,21 @@ func (in *CloneSetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneSetReportPolicy) DeepCopyInto(out *CloneSetReportPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetReportPolicy.
+func (in *CloneSetReportPolicy) DeepCopy() *CloneSetReportPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneSetReportPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CloneSetScaleStrategy) DeepCopyInto(out *CloneSetScaleStrategy) {
 	*out = *in
@@ -372,6 +430,13 @@ func (in *CloneSetScaleStrategy) DeepCopyInto(out *CloneSetScaleStrategy) {
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.Decommission != nil {
+		in, out := &in.Decommission, &out.Decommission
+		*out = make([]CloneSetDecommission, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetScaleStrategy.
@@ -449,6 +514,18 @@ func (in *CloneSetStatus) DeepCopyInto(out *CloneSetStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RecreateReasons != nil {
+		in, out := &in.RecreateReasons, &out.RecreateReasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DecommissionStatuses != nil {
+		in, out := &in.DecommissionStatuses, &out.DecommissionStatuses
+		*out = make([]CloneSetDecommissionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetStatus.
@@ -469,6 +546,11 @@ func (in *CloneSetUpdateStrategy) DeepCopyInto(out *CloneSetUpdateStrategy) {
 		*out = new(RollingUpdateCloneSetStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReportPolicy != nil {
+		in, out := &in.ReportPolicy, &out.ReportPolicy
+		*out = new(CloneSetReportPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneSetUpdateStrategy.
@@ -494,6 +576,16 @@ func (in *CompletionPolicy) DeepCopyInto(out *CompletionPolicy) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.FailedNodeLimit != nil {
+		in, out := &in.FailedNodeLimit, &out.FailedNodeLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedImageLimit != nil {
+		in, out := &in.FailedImageLimit, &out.FailedImageLimit
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompletionPolicy.
@@ -595,6 +687,21 @@ func (in *DaemonSetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonSetNodeClassAvailability) DeepCopyInto(out *DaemonSetNodeClassAvailability) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonSetNodeClassAvailability.
+func (in *DaemonSetNodeClassAvailability) DeepCopy() *DaemonSetNodeClassAvailability {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonSetNodeClassAvailability)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DaemonSetPatch) DeepCopyInto(out *DaemonSetPatch) {
 	*out = *in
@@ -616,6 +723,25 @@ func (in *DaemonSetPatch) DeepCopy() *DaemonSetPatch {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonSetPatchStatus) DeepCopyInto(out *DaemonSetPatchStatus) {
+	*out = *in
+	if in.LastRenderedTime != nil {
+		in, out := &in.LastRenderedTime, &out.LastRenderedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonSetPatchStatus.
+func (in *DaemonSetPatchStatus) DeepCopy() *DaemonSetPatchStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DaemonSetPatchStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DaemonSetScaleStrategy) DeepCopyInto(out *DaemonSetScaleStrategy) {
 	*out = *in
@@ -695,6 +821,18 @@ func (in *DaemonSetStatus) DeepCopyInto(out *DaemonSetStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SlowestNodeClasses != nil {
+		in, out := &in.SlowestNodeClasses, &out.SlowestNodeClasses
+		*out = make([]DaemonSetNodeClassAvailability, len(*in))
+		copy(*out, *in)
+	}
+	if in.PatchStatuses != nil {
+		in, out := &in.PatchStatuses, &out.PatchStatuses
+		*out = make([]DaemonSetPatchStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaemonSetStatus.
@@ -730,6 +868,11 @@ func (in *DaemonSetUpdateStrategy) DeepCopy() *DaemonSetUpdateStrategy {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FailedImageStatus) DeepCopyInto(out *FailedImageStatus) {
 	*out = *in
+	if in.FailedNodes != nil {
+		in, out := &in.FailedNodes, &out.FailedNodes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailedImageStatus.
@@ -796,7 +939,7 @@ func (in *ImageListPullJobStatus) DeepCopyInto(out *ImageListPullJobStatus) {
 			if (*in)[i] != nil {
 				in, out := &(*in)[i], &(*out)[i]
 				*out = new(FailedImageStatus)
-				**out = **in
+				(*in).DeepCopyInto(*out)
 			}
 		}
 	}
@@ -829,6 +972,36 @@ func (in *ImageListPullJobTemplateSpec) DeepCopy() *ImageListPullJobTemplateSpec
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePlatform) DeepCopyInto(out *ImagePlatform) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePlatform.
+func (in *ImagePlatform) DeepCopy() *ImagePlatform {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePlatform)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePullJobImageStatus) DeepCopyInto(out *ImagePullJobImageStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullJobImageStatus.
+func (in *ImagePullJobImageStatus) DeepCopy() *ImagePullJobImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePullJobImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImagePullJobNodeSelector) DeepCopyInto(out *ImagePullJobNodeSelector) {
 	*out = *in
@@ -838,6 +1011,13 @@ func (in *ImagePullJobNodeSelector) DeepCopyInto(out *ImagePullJobNodeSelector)
 		copy(*out, *in)
 	}
 	in.LabelSelector.DeepCopyInto(&out.LabelSelector)
+	if in.MatchFields != nil {
+		in, out := &in.MatchFields, &out.MatchFields
+		*out = make([]corev1.NodeSelectorRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullJobNodeSelector.
@@ -850,6 +1030,28 @@ func (in *ImagePullJobNodeSelector) DeepCopy() *ImagePullJobNodeSelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePullJobNodeStates) DeepCopyInto(out *ImagePullJobNodeStates) {
+	*out = *in
+	if in.WaitingNodes != nil {
+		in, out := &in.WaitingNodes, &out.WaitingNodes
+		*out = make([]ImagePullJobWaitingNode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullJobNodeStates.
+func (in *ImagePullJobNodeStates) DeepCopy() *ImagePullJobNodeStates {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePullJobNodeStates)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImagePullJobPodSelector) DeepCopyInto(out *ImagePullJobPodSelector) {
 	*out = *in
@@ -869,6 +1071,11 @@ func (in *ImagePullJobPodSelector) DeepCopy() *ImagePullJobPodSelector {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImagePullJobSpec) DeepCopyInto(out *ImagePullJobSpec) {
 	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.ImagePullJobTemplate.DeepCopyInto(&out.ImagePullJobTemplate)
 }
 
@@ -898,6 +1105,16 @@ func (in *ImagePullJobStatus) DeepCopyInto(out *ImagePullJobStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ImageStatuses != nil {
+		in, out := &in.ImageStatuses, &out.ImageStatuses
+		*out = make([]ImagePullJobImageStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.NodeStates != nil {
+		in, out := &in.NodeStates, &out.NodeStates
+		*out = new(ImagePullJobNodeStates)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullJobStatus.
@@ -944,6 +1161,11 @@ func (in *ImagePullJobTemplate) DeepCopyInto(out *ImagePullJobTemplate) {
 		*out = new(SandboxConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Platform != nil {
+		in, out := &in.Platform, &out.Platform
+		*out = new(ImagePlatform)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullJobTemplate.
@@ -956,6 +1178,25 @@ func (in *ImagePullJobTemplate) DeepCopy() *ImagePullJobTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImagePullJobWaitingNode) DeepCopyInto(out *ImagePullJobWaitingNode) {
+	*out = *in
+	if in.EstimatedStartTime != nil {
+		in, out := &in.EstimatedStartTime, &out.EstimatedStartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullJobWaitingNode.
+func (in *ImagePullJobWaitingNode) DeepCopy() *ImagePullJobWaitingNode {
+	if in == nil {
+		return nil
+	}
+	out := new(ImagePullJobWaitingNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageSpec) DeepCopyInto(out *ImageSpec) {
 	*out = *in
@@ -1023,6 +1264,11 @@ func (in *ImageTagPullPolicy) DeepCopyInto(out *ImageTagPullPolicy) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.MaxConcurrentPulls != nil {
+		in, out := &in.MaxConcurrentPulls, &out.MaxConcurrentPulls
+		*out = new(int32)
+		**out = **in
+	}
 	if in.TTLSecondsAfterFinished != nil {
 		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
 		*out = new(int32)
@@ -1062,6 +1308,11 @@ func (in *ImageTagSpec) DeepCopyInto(out *ImageTagSpec) {
 		*out = make([]corev1.ObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.Platform != nil {
+		in, out := &in.Platform, &out.Platform
+		*out = new(ImagePlatform)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageTagSpec.
@@ -1114,6 +1365,26 @@ func (in *JobCondition) DeepCopy() *JobCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeCapabilities) DeepCopyInto(out *NodeCapabilities) {
+	*out = *in
+	if in.Features != nil {
+		in, out := &in.Features, &out.Features
+		*out = make([]NodeFeature, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeCapabilities.
+func (in *NodeCapabilities) DeepCopy() *NodeCapabilities {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeCapabilities)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeImage) DeepCopyInto(out *NodeImage) {
 	*out = *in
@@ -1210,6 +1481,11 @@ func (in *NodeImageStatus) DeepCopyInto(out *NodeImageStatus) {
 		*out = new(SyncStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = new(NodeCapabilities)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeImageStatus.
@@ -1235,6 +1511,11 @@ func (in *PullPolicy) DeepCopyInto(out *PullPolicy) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.MaxConcurrentPullsPerNode != nil {
+		in, out := &in.MaxConcurrentPullsPerNode, &out.MaxConcurrentPullsPerNode
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PullPolicy.
@@ -1502,6 +1783,51 @@ func (in *ShareVolumePolicy) DeepCopy() *ShareVolumePolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarConfigHashSource) DeepCopyInto(out *SidecarConfigHashSource) {
+	*out = *in
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(SidecarConfigObjectReference)
+		**out = **in
+	}
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(SidecarConfigObjectReference)
+		**out = **in
+	}
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarConfigHashSource.
+func (in *SidecarConfigHashSource) DeepCopy() *SidecarConfigHashSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarConfigHashSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarConfigObjectReference) DeepCopyInto(out *SidecarConfigObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarConfigObjectReference.
+func (in *SidecarConfigObjectReference) DeepCopy() *SidecarConfigObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarConfigObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SidecarContainer) DeepCopyInto(out *SidecarContainer) {
 	*out = *in
@@ -1525,6 +1851,13 @@ func (in *SidecarContainer) DeepCopyInto(out *SidecarContainer) {
 		*out = new(ResourcesPolicy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ConfigHashFrom != nil {
+		in, out := &in.ConfigHashFrom, &out.ConfigHashFrom
+		*out = make([]SidecarConfigHashSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarContainer.
@@ -1579,6 +1912,65 @@ func (in *SidecarSet) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarSetBackfillStatus) DeepCopyInto(out *SidecarSetBackfillStatus) {
+	*out = *in
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]SidecarSetBackfillWorkloadStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetBackfillStatus.
+func (in *SidecarSetBackfillStatus) DeepCopy() *SidecarSetBackfillStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarSetBackfillStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarSetBackfillStrategy) DeepCopyInto(out *SidecarSetBackfillStrategy) {
+	*out = *in
+	if in.MaxUnavailablePerWorkload != nil {
+		in, out := &in.MaxUnavailablePerWorkload, &out.MaxUnavailablePerWorkload
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.Deadline != nil {
+		in, out := &in.Deadline, &out.Deadline
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetBackfillStrategy.
+func (in *SidecarSetBackfillStrategy) DeepCopy() *SidecarSetBackfillStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarSetBackfillStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarSetBackfillWorkloadStatus) DeepCopyInto(out *SidecarSetBackfillWorkloadStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetBackfillWorkloadStatus.
+func (in *SidecarSetBackfillWorkloadStatus) DeepCopy() *SidecarSetBackfillWorkloadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarSetBackfillWorkloadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SidecarSetInjectRevision) DeepCopyInto(out *SidecarSetInjectRevision) {
 	*out = *in
@@ -1612,6 +2004,16 @@ func (in *SidecarSetInjectionStrategy) DeepCopyInto(out *SidecarSetInjectionStra
 		*out = new(SidecarSetInjectRevision)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Backfill != nil {
+		in, out := &in.Backfill, &out.Backfill
+		*out = new(SidecarSetBackfillStrategy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QoSPolicy != nil {
+		in, out := &in.QoSPolicy, &out.QoSPolicy
+		*out = new(SidecarSetQoSPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetInjectionStrategy.
@@ -1678,6 +2080,28 @@ func (in *SidecarSetPatchPodMetadata) DeepCopy() *SidecarSetPatchPodMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarSetQoSPolicy) DeepCopyInto(out *SidecarSetQoSPolicy) {
+	*out = *in
+	if in.DefaultResources != nil {
+		in, out := &in.DefaultResources, &out.DefaultResources
+		*out = make(corev1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetQoSPolicy.
+func (in *SidecarSetQoSPolicy) DeepCopy() *SidecarSetQoSPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarSetQoSPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SidecarSetSpec) DeepCopyInto(out *SidecarSetSpec) {
 	*out = *in
@@ -1751,6 +2175,11 @@ func (in *SidecarSetStatus) DeepCopyInto(out *SidecarSetStatus) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.BackfillStatus != nil {
+		in, out := &in.BackfillStatus, &out.BackfillStatus
+		*out = new(SidecarSetBackfillStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarSetStatus.
@@ -1850,6 +2279,56 @@ func (in *StatefulSet) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatefulSetHibernatedClaim) DeepCopyInto(out *StatefulSetHibernatedClaim) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulSetHibernatedClaim.
+func (in *StatefulSetHibernatedClaim) DeepCopy() *StatefulSetHibernatedClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(StatefulSetHibernatedClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatefulSetHibernatedReplica) DeepCopyInto(out *StatefulSetHibernatedReplica) {
+	*out = *in
+	if in.PersistentVolumeClaims != nil {
+		in, out := &in.PersistentVolumeClaims, &out.PersistentVolumeClaims
+		*out = make([]StatefulSetHibernatedClaim, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulSetHibernatedReplica.
+func (in *StatefulSetHibernatedReplica) DeepCopy() *StatefulSetHibernatedReplica {
+	if in == nil {
+		return nil
+	}
+	out := new(StatefulSetHibernatedReplica)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatefulSetHibernation) DeepCopyInto(out *StatefulSetHibernation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulSetHibernation.
+func (in *StatefulSetHibernation) DeepCopy() *StatefulSetHibernation {
+	if in == nil {
+		return nil
+	}
+	out := new(StatefulSetHibernation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatefulSetList) DeepCopyInto(out *StatefulSetList) {
 	*out = *in
@@ -1882,6 +2361,27 @@ func (in *StatefulSetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatefulSetOrdinalAffinity) DeepCopyInto(out *StatefulSetOrdinalAffinity) {
+	*out = *in
+	if in.Ordinals != nil {
+		in, out := &in.Ordinals, &out.Ordinals
+		*out = make([]intstr.IntOrString, len(*in))
+		copy(*out, *in)
+	}
+	in.NodeSelectorTerm.DeepCopyInto(&out.NodeSelectorTerm)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulSetOrdinalAffinity.
+func (in *StatefulSetOrdinalAffinity) DeepCopy() *StatefulSetOrdinalAffinity {
+	if in == nil {
+		return nil
+	}
+	out := new(StatefulSetOrdinalAffinity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatefulSetOrdinals) DeepCopyInto(out *StatefulSetOrdinals) {
 	*out = *in
@@ -1985,6 +2485,18 @@ func (in *StatefulSetSpec) DeepCopyInto(out *StatefulSetSpec) {
 		*out = new(StatefulSetOrdinals)
 		**out = **in
 	}
+	if in.OrdinalAffinity != nil {
+		in, out := &in.OrdinalAffinity, &out.OrdinalAffinity
+		*out = make([]StatefulSetOrdinalAffinity, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Hibernation != nil {
+		in, out := &in.Hibernation, &out.Hibernation
+		*out = new(StatefulSetHibernation)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulSetSpec.
@@ -2017,6 +2529,13 @@ func (in *StatefulSetStatus) DeepCopyInto(out *StatefulSetStatus) {
 		*out = make([]VolumeClaimStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.HibernatedReplicas != nil {
+		in, out := &in.HibernatedReplicas, &out.HibernatedReplicas
+		*out = make([]StatefulSetHibernatedReplica, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulSetStatus.
@@ -70,6 +70,14 @@ type PodUnavailableBudgetSpec struct {
 	// Delete pod, evict pod or update pod specification is allowed if at least "minAvailable" pods selected by
 	// "selector" or "targetRef" will still be available after the above operation for pod.
 	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// ProtectedOperations is the list of operations[DELETE,UPDATE,EVICT,RESIZE] protected by this PUB,
+	// which takes precedence over the annotation kruise.io/pub-protect-operations.
+	// If it is empty, the operations in the annotation, or DELETE,UPDATE,EVICT by default, are protected.
+	// Note that the operations are protected only if the feature-gate PodUnavailableBudgetDeleteGate or
+	// PodUnavailableBudgetUpdateGate of them is enabled.
+	// +optional
+	ProtectedOperations []PubOperation `json:"protectedOperations,omitempty"`
 }
 
 // TargetReference contains enough information to let you identify a workload for PodUnavailableBudget
@@ -110,6 +118,31 @@ type PodUnavailableBudgetStatus struct {
 
 	// TotalReplicas total number of pods counted by this unavailable budget
 	TotalReplicas int32 `json:"totalReplicas"`
+
+	// BlockedOperations contains the latest pod operations rejected by this unavailable budget, the oldest first.
+	// Only a limited number of them are kept, and the older ones are dropped.
+	// They are recorded by the controller asynchronously after the rejections.
+	// +optional
+	BlockedOperations []PubBlockedOperation `json:"blockedOperations,omitempty"`
+
+	// TotalBlockedCount is the total number of pod operations rejected by this unavailable budget for each operation.
+	// +optional
+	TotalBlockedCount map[PubOperation]int64 `json:"totalBlockedCount,omitempty"`
+}
+
+// PubBlockedOperation is a pod operation rejected by PodUnavailableBudget.
+type PubBlockedOperation struct {
+	// Operation is the rejected operation, such as DELETE, UPDATE, EVICT and RESIZE.
+	Operation PubOperation `json:"operation"`
+	// PodName is the name of the pod.
+	PodName string `json:"podName"`
+	// Timestamp is the time when the operation was rejected.
+	Timestamp metav1.Time `json:"timestamp"`
+	// UnavailableAllowed is the number of pod unavailable that were allowed when the operation was rejected.
+	UnavailableAllowed int32 `json:"unavailableAllowed"`
+	// Reason is why the operation was rejected.
+	// +optional
+	Reason string `json:"reason,omitempty"`
 }
 
 // +genclient
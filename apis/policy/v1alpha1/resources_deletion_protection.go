@@ -18,9 +18,17 @@ package v1alpha1
 
 const (
 	// DeletionProtectionKey is a key in object labels and its value can be Always and Cascading.
-	// Currently supports Namespace, CustomResourcesDefinition, Deployment, StatefulSet, ReplicaSet, CloneSet, Advanced StatefulSet, UnitedDeployment.
+	// Currently supports Namespace, CustomResourcesDefinition, Deployment, StatefulSet, ReplicaSet, CloneSet, Advanced StatefulSet, UnitedDeployment,
+	// and the resources configured by --deletion-protection-resources of kruise-manager, e.g. PersistentVolumeClaim and custom resources.
 	DeletionProtectionKey = "policy.kruise.io/delete-protection"
 
+	// DeletionProtectionExpiresAtKey is a key in object annotations and its value is a RFC3339 timestamp, after which the
+	// object is no longer protected by DeletionProtectionKey. A missing or invalid timestamp means the protection never expires.
+	// It can't be set more than --deletion-protection-max-expiry of kruise-manager in the future, which is validated on
+	// deletion, and on creation and update of CloneSet, Advanced StatefulSet, UnitedDeployment and the resources configured
+	// by --deletion-protection-resources as well.
+	DeletionProtectionExpiresAtKey = "policy.kruise.io/delete-protection-expires-at"
+
 	// DeletionProtectionTypeAlways indicates this object will always be forbidden to be deleted, unless the label is removed.
 	DeletionProtectionTypeAlways = "Always"
 	// DeletionProtectionTypeCascading indicates this object will be forbidden to be deleted, if it has active resources owned.
@@ -108,6 +108,11 @@ func (in *PodUnavailableBudgetSpec) DeepCopyInto(out *PodUnavailableBudgetSpec)
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.ProtectedOperations != nil {
+		in, out := &in.ProtectedOperations, &out.ProtectedOperations
+		*out = make([]PubOperation, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodUnavailableBudgetSpec.
@@ -137,6 +142,20 @@ func (in *PodUnavailableBudgetStatus) DeepCopyInto(out *PodUnavailableBudgetStat
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.BlockedOperations != nil {
+		in, out := &in.BlockedOperations, &out.BlockedOperations
+		*out = make([]PubBlockedOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TotalBlockedCount != nil {
+		in, out := &in.TotalBlockedCount, &out.TotalBlockedCount
+		*out = make(map[PubOperation]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodUnavailableBudgetStatus.
@@ -149,6 +168,22 @@ func (in *PodUnavailableBudgetStatus) DeepCopy() *PodUnavailableBudgetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PubBlockedOperation) DeepCopyInto(out *PubBlockedOperation) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PubBlockedOperation.
+func (in *PubBlockedOperation) DeepCopy() *PubBlockedOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(PubBlockedOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetReference) DeepCopyInto(out *TargetReference) {
 	*out = *in
@@ -31,6 +31,7 @@ type AppsV1alpha1Interface interface {
 	BroadcastJobsGetter
 	CloneSetsGetter
 	ContainerRecreateRequestsGetter
+	ContainerRecreateRequestSetsGetter
 	DaemonSetsGetter
 	EphemeralJobsGetter
 	ImageListPullJobsGetter
@@ -67,6 +68,10 @@ func (c *AppsV1alpha1Client) ContainerRecreateRequests(namespace string) Contain
 	return newContainerRecreateRequests(c, namespace)
 }
 
+func (c *AppsV1alpha1Client) ContainerRecreateRequestSets(namespace string) ContainerRecreateRequestSetInterface {
+	return newContainerRecreateRequestSets(c, namespace)
+}
+
 func (c *AppsV1alpha1Client) DaemonSets(namespace string) DaemonSetInterface {
 	return newDaemonSets(c, namespace)
 }
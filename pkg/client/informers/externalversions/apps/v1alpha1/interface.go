@@ -31,6 +31,8 @@ type Interface interface {
 	CloneSets() CloneSetInformer
 	// ContainerRecreateRequests returns a ContainerRecreateRequestInformer.
 	ContainerRecreateRequests() ContainerRecreateRequestInformer
+	// ContainerRecreateRequestSets returns a ContainerRecreateRequestSetInformer.
+	ContainerRecreateRequestSets() ContainerRecreateRequestSetInformer
 	// DaemonSets returns a DaemonSetInformer.
 	DaemonSets() DaemonSetInformer
 	// EphemeralJobs returns a EphemeralJobInformer.
@@ -90,6 +92,11 @@ func (v *version) ContainerRecreateRequests() ContainerRecreateRequestInformer {
 	return &containerRecreateRequestInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// ContainerRecreateRequestSets returns a ContainerRecreateRequestSetInformer.
+func (v *version) ContainerRecreateRequestSets() ContainerRecreateRequestSetInformer {
+	return &containerRecreateRequestSetInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // DaemonSets returns a DaemonSetInformer.
 func (v *version) DaemonSets() DaemonSetInformer {
 	return &daemonSetInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
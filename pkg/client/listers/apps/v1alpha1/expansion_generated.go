@@ -49,6 +49,14 @@ type ContainerRecreateRequestListerExpansion interface{}
 // ContainerRecreateRequestNamespaceLister.
 type ContainerRecreateRequestNamespaceListerExpansion interface{}
 
+// ContainerRecreateRequestSetListerExpansion allows custom methods to be added to
+// ContainerRecreateRequestSetLister.
+type ContainerRecreateRequestSetListerExpansion interface{}
+
+// ContainerRecreateRequestSetNamespaceListerExpansion allows custom methods to be added to
+// ContainerRecreateRequestSetNamespaceLister.
+type ContainerRecreateRequestSetNamespaceListerExpansion interface{}
+
 // DaemonSetListerExpansion allows custom methods to be added to
 // DaemonSetLister.
 type DaemonSetListerExpansion interface{}
@@ -0,0 +1,141 @@
+/*
+Copyright 2026 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pubcontrol
+
+import (
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
+)
+
+// The pod operations rejected by the webhook are aggregated in memory instead of written into pub status during
+// the admission, and the pub controller, which is the only writer of status.blockedOperations and
+// status.totalBlockedCount, flushes them into pub status.
+// Note that each replica aggregates the operations rejected by itself, which are flushed once its pub controller runs.
+
+// BlockedOperationEvents notifies the pub controller of the pubs with blocked operations to flush.
+var BlockedOperationEvents = make(chan event.TypedGenericEvent[*policyv1alpha1.PodUnavailableBudget], 1024)
+
+// BlockedOperations is the pod operations rejected by a pub that are not flushed into its status yet.
+type BlockedOperations struct {
+	uid        types.UID
+	Operations []policyv1alpha1.PubBlockedOperation
+	Counts     map[policyv1alpha1.PubOperation]int64
+}
+
+var (
+	blockedOperationsLock    sync.Mutex
+	pendingBlockedOperations = map[types.NamespacedName]*BlockedOperations{}
+)
+
+// recordBlockedOperation aggregates the rejected operation of the pub, and notifies the pub controller to flush it.
+func recordBlockedOperation(pub *policyv1alpha1.PodUnavailableBudget, podName string, operation policyv1alpha1.PubOperation, reason string) {
+	blockedOperationsLock.Lock()
+	key := types.NamespacedName{Namespace: pub.Namespace, Name: pub.Name}
+	pending := pendingBlockedOperations[key]
+	notify := pending == nil
+	if pending == nil || pending.uid != pub.UID {
+		pending = &BlockedOperations{uid: pub.UID, Counts: map[policyv1alpha1.PubOperation]int64{}}
+		pendingBlockedOperations[key] = pending
+	}
+	pending.Operations = limitBlockedOperations(append(pending.Operations, policyv1alpha1.PubBlockedOperation{
+		Operation:          operation,
+		PodName:            podName,
+		Timestamp:          metav1.Now(),
+		UnavailableAllowed: pub.Status.UnavailableAllowed,
+		Reason:             reason,
+	}))
+	pending.Counts[operation]++
+	blockedOperationsLock.Unlock()
+
+	if !notify {
+		return
+	}
+	// never block the admission, the operations are flushed in the next reconcile if the notification is dropped
+	select {
+	case BlockedOperationEvents <- event.TypedGenericEvent[*policyv1alpha1.PodUnavailableBudget]{
+		Object: &policyv1alpha1.PodUnavailableBudget{ObjectMeta: metav1.ObjectMeta{Namespace: pub.Namespace, Name: pub.Name}},
+	}:
+	default:
+	}
+}
+
+// TakeBlockedOperations removes and returns the operations aggregated for the pub, or nil if there is none.
+func TakeBlockedOperations(pub *policyv1alpha1.PodUnavailableBudget) *BlockedOperations {
+	blockedOperationsLock.Lock()
+	defer blockedOperationsLock.Unlock()
+	key := types.NamespacedName{Namespace: pub.Namespace, Name: pub.Name}
+	pending := pendingBlockedOperations[key]
+	delete(pendingBlockedOperations, key)
+	// drop the operations of the pub deleted and recreated with the same name
+	if pending == nil || pending.uid != pub.UID {
+		return nil
+	}
+	return pending
+}
+
+// RestoreBlockedOperations puts back the operations taken if they failed to be flushed into pub status.
+func RestoreBlockedOperations(pub *policyv1alpha1.PodUnavailableBudget, taken *BlockedOperations) {
+	if taken == nil {
+		return
+	}
+	blockedOperationsLock.Lock()
+	defer blockedOperationsLock.Unlock()
+	key := types.NamespacedName{Namespace: pub.Namespace, Name: pub.Name}
+	if pending := pendingBlockedOperations[key]; pending != nil && pending.uid == taken.uid {
+		taken.Operations = limitBlockedOperations(append(taken.Operations, pending.Operations...))
+		for operation, count := range pending.Counts {
+			taken.Counts[operation] += count
+		}
+	}
+	pendingBlockedOperations[key] = taken
+}
+
+// ForgetBlockedOperations drops the operations aggregated for the deleted pub.
+func ForgetBlockedOperations(key types.NamespacedName) {
+	blockedOperationsLock.Lock()
+	defer blockedOperationsLock.Unlock()
+	delete(pendingBlockedOperations, key)
+}
+
+// MergeInto appends the operations to the blocked operations of pub status, which keeps the latest
+// MaxBlockedOperationsSize ones only, and increases the counters of them.
+func (b *BlockedOperations) MergeInto(status *policyv1alpha1.PodUnavailableBudgetStatus) {
+	if b == nil {
+		return
+	}
+	status.BlockedOperations = limitBlockedOperations(append(append([]policyv1alpha1.PubBlockedOperation{}, status.BlockedOperations...), b.Operations...))
+	counts := make(map[policyv1alpha1.PubOperation]int64, len(status.TotalBlockedCount)+len(b.Counts))
+	for operation, count := range status.TotalBlockedCount {
+		counts[operation] = count
+	}
+	for operation, count := range b.Counts {
+		counts[operation] += count
+	}
+	status.TotalBlockedCount = counts
+}
+
+func limitBlockedOperations(operations []policyv1alpha1.PubBlockedOperation) []policyv1alpha1.PubBlockedOperation {
+	if len(operations) > MaxBlockedOperationsSize {
+		return append([]policyv1alpha1.PubBlockedOperation{}, operations[len(operations)-MaxBlockedOperationsSize:]...)
+	}
+	return operations
+}
@@ -18,6 +18,7 @@ package pubcontrol
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -28,21 +29,29 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
 	kubeClient "github.com/openkruise/kruise/pkg/client"
 	"github.com/openkruise/kruise/pkg/features"
 	"github.com/openkruise/kruise/pkg/util"
+	utilclient "github.com/openkruise/kruise/pkg/util/client"
 	"github.com/openkruise/kruise/pkg/util/feature"
+	"github.com/openkruise/kruise/pkg/util/fieldindex"
+	wsutil "github.com/openkruise/kruise/pkg/util/workloadspread"
 )
 
 const (
 	// MaxUnavailablePodSize is the max size of PUB.DisruptedPods + PUB.UnavailablePods.
 	MaxUnavailablePodSize = 2000
+	// MaxBlockedOperationsSize is the max size of PUB.Status.BlockedOperations.
+	MaxBlockedOperationsSize = 20
 )
 
 var ConflictRetry = wait.Backoff{
@@ -138,8 +147,16 @@ func PodUnavailableBudgetValidatePod(pod *corev1.Pod, operation policyv1alpha1.P
 
 		// Try to verify-and-decrement
 		// If it was false already, or if it becomes false during the course of our retries,
-		err = checkAndDecrement(pod.Name, pubClone, operation)
-		if err != nil {
+		// the per-subset budget of WorkloadSpread is checked in addition to the pub quota.
+		err = checkWorkloadSpreadSubsetBudget(pod, pubClone)
+		if err == nil {
+			err = checkAndDecrement(pod.Name, pubClone, operation)
+		}
+		if err != nil && dryRun {
+			// a dry run only answers whether the operation is allowed now, so it is neither reported nor recorded
+			klog.V(3).InfoS("Pod operation for pub would be rejected in dry run", "pod", klog.KObj(pod), "operation", operation, "pub", klog.KObj(pubClone))
+			return err
+		} else if err != nil {
 			var kind, namespace, name string
 			if ref := PubControl.GetPodControllerOf(pod); ref != nil {
 				kind = ref.Kind
@@ -155,6 +172,8 @@ func PodUnavailableBudgetValidatePod(pod *corev1.Pod, operation policyv1alpha1.P
 			PodUnavailableBudgetMetrics.WithLabelValues(fmt.Sprintf("%s_%s_%s", kind, namespace, name), username).Add(1)
 			recorder.Eventf(pod, corev1.EventTypeWarning, "PubPreventPodDeletion", "openkruise pub prevents pod deletion")
 			util.LoggerProtectionInfo(util.ProtectionEventPub, kind, namespace, name, username)
+			// the pub controller flushes it into pub status, so that the rejection never writes pub status
+			recordBlockedOperation(pubClone, pod.Name, operation, err.Error())
 			return err
 		}
 
@@ -228,6 +247,60 @@ func checkAndDecrement(podName string, pub *policyv1alpha1.PodUnavailableBudget,
 	return nil
 }
 
+// checkWorkloadSpreadSubsetBudget checks spec.protectionPolicy.perSubsetMaxUnavailable of the WorkloadSpread which the pod
+// was injected into, the pods recorded in pub status are regarded as unavailable.
+func checkWorkloadSpreadSubsetBudget(pod *corev1.Pod, pub *policyv1alpha1.PodUnavailableBudget) error {
+	str, ok := pod.Annotations[wsutil.MatchedWorkloadSpreadSubsetAnnotations]
+	if !ok || str == "" {
+		return nil
+	}
+	injectWS := &wsutil.InjectWorkloadSpread{}
+	if err := json.Unmarshal([]byte(str), injectWS); err != nil || injectWS.Name == "" {
+		return nil
+	}
+	ws := &appsv1alpha1.WorkloadSpread{}
+	if err := kclient.Get(context.TODO(), types.NamespacedName{Namespace: pod.Namespace, Name: injectWS.Name}, ws); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if ws.Spec.ProtectionPolicy == nil || ws.Spec.ProtectionPolicy.PerSubsetMaxUnavailable == nil {
+		return nil
+	}
+
+	podList := &corev1.PodList{}
+	if err := kclient.List(context.TODO(), podList, client.InNamespace(pod.Namespace), client.MatchingFields{
+		fieldindex.IndexNameForPodWorkloadSpread: fieldindex.WorkloadSpreadSubsetIndexValue(injectWS.Name, injectWS.Subset),
+	}, utilclient.DisableDeepCopy); err != nil {
+		return err
+	}
+	var total, unavailable int
+	for i := range podList.Items {
+		subsetPod := &podList.Items[i]
+		if subsetPod.Status.Phase == corev1.PodSucceeded || subsetPod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		total++
+		if subsetPod.Name == pod.Name {
+			continue
+		}
+		if subsetPod.DeletionTimestamp != nil || !PubControl.IsPodReady(subsetPod) ||
+			!PubControl.IsPodStateConsistent(subsetPod) || isPodRecordedInPub(subsetPod.Name, pub) {
+			unavailable++
+		}
+	}
+	maxUnavailable, err := intstrutil.GetScaledValueFromIntOrPercent(ws.Spec.ProtectionPolicy.PerSubsetMaxUnavailable, total, true)
+	if err != nil {
+		return err
+	}
+	if unavailable >= maxUnavailable {
+		return errors.NewForbidden(policyv1alpha1.Resource("podunavailablebudget"), pub.Name,
+			fmt.Errorf("unavailable pods of workloadSpread %s subset %s reached perSubsetMaxUnavailable %d", ws.Name, injectWS.Subset, maxUnavailable))
+	}
+	return nil
+}
+
 func isPodRecordedInPub(podName string, pub *policyv1alpha1.PodUnavailableBudget) bool {
 	if _, ok := pub.Status.UnavailablePods[podName]; ok {
 		return true
@@ -251,21 +324,24 @@ func IsReferenceEqual(ref1, ref2 *policyv1alpha1.TargetReference) bool {
 	return gv1.Group == gv2.Group && ref1.Kind == ref2.Kind && ref1.Name == ref2.Name
 }
 
+// isNeedPubProtection returns whether the operation is protected by the pub, which is determined by
+// spec.protectedOperations if it is set, otherwise by the annotation kruise.io/pub-protect-operations.
 func isNeedPubProtection(pub *policyv1alpha1.PodUnavailableBudget, operation policyv1alpha1.PubOperation) bool {
-	operationValue, ok := pub.Annotations[policyv1alpha1.PubProtectOperationAnnotation]
 	enableInPlacePodVerticalScaling := feature.DefaultFeatureGate.Enabled(features.InPlacePodVerticalScaling)
 	operations := sets.New[policyv1alpha1.PubOperation]()
-	if !ok || operationValue == "" {
+	if len(pub.Spec.ProtectedOperations) > 0 {
+		operations.Insert(pub.Spec.ProtectedOperations...)
+	} else if operationValue := pub.Annotations[policyv1alpha1.PubProtectOperationAnnotation]; operationValue == "" {
 		// by default, protect delete, update, evict
 		operations.Insert(
 			policyv1alpha1.PubDeleteOperation,
 			policyv1alpha1.PubUpdateOperation,
 			policyv1alpha1.PubEvictOperation,
 		)
-	}
-
-	for _, action := range strings.Split(operationValue, ",") {
-		operations.Insert(policyv1alpha1.PubOperation(action))
+	} else {
+		for _, action := range strings.Split(operationValue, ",") {
+			operations.Insert(policyv1alpha1.PubOperation(action))
+		}
 	}
 
 	// if featureGate InPlacePodVerticalScaling is disabled, resize will be treat as update
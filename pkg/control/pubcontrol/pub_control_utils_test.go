@@ -17,7 +17,9 @@ limitations under the License.
 package pubcontrol
 
 import (
+	"context"
 	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
@@ -31,14 +33,19 @@ import (
 	"k8s.io/client-go/tools/record"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/openkruise/kruise/apis/apps/pub"
 	appspub "github.com/openkruise/kruise/apis/apps/pub"
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
 	"github.com/openkruise/kruise/pkg/features"
+	"github.com/openkruise/kruise/pkg/util"
 	"github.com/openkruise/kruise/pkg/util/controllerfinder"
 	"github.com/openkruise/kruise/pkg/util/feature"
+	"github.com/openkruise/kruise/pkg/util/fieldindex"
+	wsutil "github.com/openkruise/kruise/pkg/util/workloadspread"
 )
 
 func init() {
@@ -46,6 +53,7 @@ func init() {
 	utilruntime.Must(policyv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(corev1.AddToScheme(scheme))
 	utilruntime.Must(apps.AddToScheme(scheme))
+	utilruntime.Must(appsv1alpha1.AddToScheme(scheme))
 }
 
 var (
@@ -325,6 +333,177 @@ func TestPodUnavailableBudgetValidatePod(t *testing.T) {
 	}
 }
 
+func TestPodUnavailableBudgetValidatePodWithWorkloadSpread(t *testing.T) {
+	newSubsetPod := func(name, subset string) *corev1.Pod {
+		pod := podDemo.DeepCopy()
+		pod.Name = name
+		pod.Annotations[wsutil.MatchedWorkloadSpreadSubsetAnnotations] = fmt.Sprintf(`{"name":"test-ws","subset":"%s"}`, subset)
+		return pod
+	}
+	newWorkloadSpread := func(maxUnavailable *intstr.IntOrString) *appsv1alpha1.WorkloadSpread {
+		ws := &appsv1alpha1.WorkloadSpread{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "test-ws"},
+			Spec: appsv1alpha1.WorkloadSpreadSpec{
+				Subsets: []appsv1alpha1.WorkloadSpreadSubset{{Name: "subset-a"}, {Name: "subset-b"}},
+			},
+		}
+		if maxUnavailable != nil {
+			ws.Spec.ProtectionPolicy = &appsv1alpha1.WorkloadSpreadProtectionPolicy{PerSubsetMaxUnavailable: maxUnavailable}
+		}
+		return ws
+	}
+
+	cases := []struct {
+		name           string
+		pod            *corev1.Pod
+		workloadSpread *appsv1alpha1.WorkloadSpread
+		expectAllow    bool
+	}{
+		{
+			name:           "subset budget exhausted while pub still has headroom, reject",
+			pod:            newSubsetPod("pod-a-1", "subset-a"),
+			workloadSpread: newWorkloadSpread(ptr.To(intstr.FromInt32(1))),
+			expectAllow:    false,
+		},
+		{
+			name:           "subset budget is available, allow",
+			pod:            newSubsetPod("pod-b-1", "subset-b"),
+			workloadSpread: newWorkloadSpread(ptr.To(intstr.FromInt32(1))),
+			expectAllow:    true,
+		},
+		{
+			name:           "subset budget in percentage is available, allow",
+			pod:            newSubsetPod("pod-a-1", "subset-a"),
+			workloadSpread: newWorkloadSpread(ptr.To(intstr.FromString("50%"))),
+			expectAllow:    true,
+		},
+		{
+			name:           "workloadSpread without protectionPolicy, allow",
+			pod:            newSubsetPod("pod-a-1", "subset-a"),
+			workloadSpread: newWorkloadSpread(nil),
+			expectAllow:    true,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			pub := pubDemo.DeepCopy()
+			pub.Status.UnavailableAllowed = 3
+			pub.Status.DisruptedPods = map[string]metav1.Time{"pod-a-2": metav1.Now()}
+			// drop the pub cached by the previous case
+			_ = util.GlobalCache.Delete(pub)
+			objs := []client.Object{pub, cs.workloadSpread,
+				newSubsetPod("pod-a-1", "subset-a"), newSubsetPod("pod-a-2", "subset-a"), newSubsetPod("pod-a-3", "subset-a"),
+				newSubsetPod("pod-b-1", "subset-b"), newSubsetPod("pod-b-2", "subset-b"), newSubsetPod("pod-b-3", "subset-b")}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+				WithIndex(&corev1.Pod{}, fieldindex.IndexNameForPodWorkloadSpread, fieldindex.IndexPodWorkloadSpread).
+				WithStatusSubresource(&policyv1alpha1.PodUnavailableBudget{}).Build()
+			finder := &controllerfinder.ControllerFinder{Client: fakeClient}
+			InitPubControl(fakeClient, finder, record.NewFakeRecorder(10))
+			allow, reason, err := PodUnavailableBudgetValidatePod(cs.pod, policyv1alpha1.PubDeleteOperation, "fake-user", false)
+			if err != nil {
+				t.Fatalf("PodUnavailableBudgetValidatePod failed: %s", err.Error())
+			}
+			if cs.expectAllow != allow {
+				t.Fatalf("expect allow %v, but got %v, reason: %s", cs.expectAllow, allow, reason)
+			}
+		})
+	}
+}
+
+func TestPodUnavailableBudgetValidatePodRecordBlocked(t *testing.T) {
+	pub := pubDemo.DeepCopy()
+	// drop the pub cached and the operations recorded by the previous cases
+	_ = util.GlobalCache.Delete(pub)
+	_ = TakeBlockedOperations(pub)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pub).
+		WithStatusSubresource(&policyv1alpha1.PodUnavailableBudget{}).Build()
+	finder := &controllerfinder.ControllerFinder{Client: fakeClient}
+	fakeRecorder := record.NewFakeRecorder(10)
+	InitPubControl(fakeClient, finder, fakeRecorder)
+
+	// dry run is neither recorded nor reported
+	if allow, _, err := PodUnavailableBudgetValidatePod(podDemo.DeepCopy(), policyv1alpha1.PubDeleteOperation, "fake-user", true); err != nil || allow {
+		t.Fatalf("expect rejected, but got allow %v, err %v", allow, err)
+	}
+	if len(fakeRecorder.Events) != 0 {
+		t.Fatalf("expect no event for dry run, but got %d", len(fakeRecorder.Events))
+	}
+	if blocked := TakeBlockedOperations(pub); blocked != nil {
+		t.Fatalf("expect no blocked operation for dry run, but got %+v", blocked)
+	}
+	if allow, _, err := PodUnavailableBudgetValidatePod(podDemo.DeepCopy(), policyv1alpha1.PubDeleteOperation, "fake-user", false); err != nil || allow {
+		t.Fatalf("expect rejected, but got allow %v, err %v", allow, err)
+	}
+	if len(fakeRecorder.Events) != 1 {
+		t.Fatalf("expect 1 event for rejection, but got %d", len(fakeRecorder.Events))
+	}
+
+	// the rejection never writes pub status
+	newPub := &policyv1alpha1.PodUnavailableBudget{}
+	if err := fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(pub), newPub); err != nil {
+		t.Fatal(err)
+	}
+	if len(newPub.Status.BlockedOperations) != 0 || len(newPub.Status.TotalBlockedCount) != 0 {
+		t.Fatalf("expect pub status unchanged, but got %+v", newPub.Status)
+	}
+	blocked := TakeBlockedOperations(pub)
+	if blocked == nil || len(blocked.Operations) != 1 {
+		t.Fatalf("expect 1 blocked operation, but got %+v", blocked)
+	}
+	last := blocked.Operations[0]
+	if last.PodName != podDemo.Name || last.Operation != policyv1alpha1.PubDeleteOperation || last.UnavailableAllowed != 0 || last.Reason == "" {
+		t.Fatalf("unexpected blocked operation %+v", last)
+	}
+}
+
+func TestBlockedOperations(t *testing.T) {
+	pub := pubDemo.DeepCopy()
+	pub.UID = "pub-uid"
+	_ = TakeBlockedOperations(pub)
+	for i := 0; i < MaxBlockedOperationsSize; i++ {
+		pub.Status.BlockedOperations = append(pub.Status.BlockedOperations, policyv1alpha1.PubBlockedOperation{
+			Operation: policyv1alpha1.PubUpdateOperation,
+			PodName:   fmt.Sprintf("pod-%d", i),
+			Timestamp: metav1.Now(),
+		})
+	}
+	pub.Status.TotalBlockedCount = map[policyv1alpha1.PubOperation]int64{policyv1alpha1.PubUpdateOperation: MaxBlockedOperationsSize}
+
+	recordBlockedOperation(pub, "pod-a", policyv1alpha1.PubDeleteOperation, "rejected")
+	// failed to flush, then put it back before the next rejection
+	taken := TakeBlockedOperations(pub)
+	recordBlockedOperation(pub, "pod-b", policyv1alpha1.PubEvictOperation, "rejected")
+	RestoreBlockedOperations(pub, taken)
+	TakeBlockedOperations(pub).MergeInto(&pub.Status)
+	blocked := pub.Status.BlockedOperations
+	if len(blocked) != MaxBlockedOperationsSize {
+		t.Fatalf("expect %d blocked operations, but got %d", MaxBlockedOperationsSize, len(blocked))
+	}
+	if blocked[0].PodName != "pod-2" {
+		t.Fatalf("expect the oldest blocked operations dropped, but got %s first", blocked[0].PodName)
+	}
+	if blocked[len(blocked)-2].PodName != "pod-a" || blocked[len(blocked)-1].PodName != "pod-b" {
+		t.Fatalf("unexpected latest blocked operations %+v", blocked[len(blocked)-2:])
+	}
+	expectCount := map[policyv1alpha1.PubOperation]int64{
+		policyv1alpha1.PubUpdateOperation: MaxBlockedOperationsSize,
+		policyv1alpha1.PubDeleteOperation: 1,
+		policyv1alpha1.PubEvictOperation:  1,
+	}
+	if !reflect.DeepEqual(pub.Status.TotalBlockedCount, expectCount) {
+		t.Fatalf("expect total blocked count %v, but got %v", expectCount, pub.Status.TotalBlockedCount)
+	}
+
+	// the operations of the pub recreated with the same name are dropped
+	recreated := pub.DeepCopy()
+	recreated.UID = "new-uid"
+	recordBlockedOperation(recreated, "pod-c", policyv1alpha1.PubDeleteOperation, "rejected")
+	if blocked := TakeBlockedOperations(pub); blocked != nil {
+		t.Fatalf("expect the operations of the recreated pub dropped, but got %+v", blocked)
+	}
+}
+
 func TestGetPodUnavailableBudgetForPod(t *testing.T) {
 	cases := []struct {
 		name          string
@@ -592,6 +771,50 @@ func TestIsNeedPubProtection(t *testing.T) {
 			operation:     policyv1alpha1.PubResizeOperation,
 			expectProtect: false,
 		},
+		{
+			name: "pub protect evict in spec.protectedOperations",
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.ProtectedOperations = []policyv1alpha1.PubOperation{policyv1alpha1.PubEvictOperation, policyv1alpha1.PubDeleteOperation}
+				return pub
+			},
+			operation:     policyv1alpha1.PubEvictOperation,
+			expectProtect: true,
+		},
+		{
+			name: "pub not protect update not in spec.protectedOperations",
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.ProtectedOperations = []policyv1alpha1.PubOperation{policyv1alpha1.PubEvictOperation, policyv1alpha1.PubDeleteOperation}
+				return pub
+			},
+			operation:     policyv1alpha1.PubUpdateOperation,
+			expectProtect: false,
+		},
+		{
+			name: "spec.protectedOperations takes precedence over annotation",
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Annotations = map[string]string{
+					policyv1alpha1.PubProtectOperationAnnotation: string(policyv1alpha1.PubDeleteOperation),
+				}
+				pub.Spec.ProtectedOperations = []policyv1alpha1.PubOperation{policyv1alpha1.PubUpdateOperation}
+				return pub
+			},
+			operation:     policyv1alpha1.PubDeleteOperation,
+			expectProtect: false,
+		},
+		{
+			name: "pub protect resize when update in spec.protectedOperations and featureGate InPlacePodVerticalScaling is disabled",
+			getPub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.ProtectedOperations = []policyv1alpha1.PubOperation{policyv1alpha1.PubUpdateOperation}
+				return pub
+			},
+			enableInplace: false,
+			operation:     policyv1alpha1.PubResizeOperation,
+			expectProtect: true,
+		},
 	}
 	for _, cs := range cases {
 		t.Run(cs.name, func(t *testing.T) {
@@ -48,6 +48,8 @@ func SidecarSetHashWithoutImage(sidecarSet *appsv1alpha1.SidecarSet) (string, er
 	for i := range ss.Spec.InitContainers {
 		ss.Spec.InitContainers[i].Image = ""
 	}
+	// the changes of config are applied by restarting the containers, which is the same as image
+	delete(ss.Annotations, SidecarSetConfigHashAnnotation)
 	encoded, err := encodeSidecarSet(ss)
 	if err != nil {
 		return "", err
@@ -69,6 +71,10 @@ func encodeSidecarSet(sidecarSet *appsv1alpha1.SidecarSet) (string, error) {
 	if len(initContainer) > 0 {
 		m["initContainers"] = sidecarSet.Spec.InitContainers
 	}
+	// the hashes of config referenced by configHashFrom, so that the changes of config produce a new hash
+	if configHash := sidecarSet.Annotations[SidecarSetConfigHashAnnotation]; configHash != "" {
+		m["configHash"] = configHash
+	}
 	data, err := json.Marshal(m)
 	if err != nil {
 		return "", err
@@ -97,6 +103,8 @@ func SidecarSetHashWithoutImageV1beta1(sidecarSet *appsv1beta1.SidecarSet) (stri
 	for i := range ss.Spec.InitContainers {
 		ss.Spec.InitContainers[i].Image = ""
 	}
+	// the changes of config are applied by restarting the containers, which is the same as image
+	delete(ss.Annotations, SidecarSetConfigHashAnnotation)
 	encoded, err := encodeSidecarSetV1beta1(ss)
 	if err != nil {
 		return "", err
@@ -118,6 +126,10 @@ func encodeSidecarSetV1beta1(sidecarSet *appsv1beta1.SidecarSet) (string, error)
 	if len(initContainer) > 0 {
 		m["initContainers"] = sidecarSet.Spec.InitContainers
 	}
+	// the hashes of config referenced by configHashFrom, so that the changes of config produce a new hash
+	if configHash := sidecarSet.Annotations[SidecarSetConfigHashAnnotation]; configHash != "" {
+		m["configHash"] = configHash
+	}
 	data, err := json.Marshal(m)
 	if err != nil {
 		return "", err
@@ -124,6 +124,12 @@ func (r *realControl) getPatch(s *appsv1beta1.SidecarSet) ([]byte, error) {
 	copySidecarSetSpecRevision(specCopy, spec)
 
 	objCopy["spec"] = specCopy
+	// the config referenced by configHashFrom is also a part of the revision
+	if configHash := s.Annotations[SidecarSetConfigHashAnnotation]; configHash != "" {
+		objCopy["metadata"] = map[string]interface{}{
+			"annotations": map[string]interface{}{SidecarSetConfigHashAnnotation: configHash},
+		}
+	}
 	return json.Marshal(objCopy)
 }
 
@@ -149,6 +149,10 @@ func (c *commonControl) IsPodStateConsistent(pod *v1.Pod, sidecarContainers sets
 	if sidecarContainers.Len() == 0 {
 		sidecarContainers = GetSidecarContainersInPod(sidecarset)
 	}
+	// the containers requested to restart for the new config have not been restarted
+	if !IsSidecarConfigRestartCompleted(pod, sidecarset.Name, sidecarContainers) {
+		return false
+	}
 
 	allDigestImage := true
 	cImageIDs := util.GetPodContainerImageIDs(pod)
@@ -32,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
+	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
 	kubecontroller "k8s.io/kubernetes/pkg/controller"
 	"k8s.io/kubernetes/pkg/fieldpath"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -57,6 +58,25 @@ const (
 	// SidecarSetListAnnotation represent sidecarset list that injected pods
 	SidecarSetListAnnotation = "kruise.io/sidecarset-injected-list"
 
+	// SidecarSetQoSDecisionAnnotation records the decisions made by qosPolicy of sidecarSets for the pod,
+	// which is a map from the name of sidecarSet to the type of qosPolicy applied.
+	SidecarSetQoSDecisionAnnotation = "kruise.io/sidecarset-qos-decisions"
+
+	// SidecarSetSkippedListAnnotation represents the sidecarSets skipped to inject into the pod,
+	// since injecting them would exceed the footprint limits of the pod.
+	SidecarSetSkippedListAnnotation = "kruise.io/sidecarset-skipped-list"
+	// SidecarSetMaxInjectedContainersAnnotation is the annotation of namespace, which limits the max number of
+	// sidecar containers injected into a pod in the namespace, 0 means no limit.
+	SidecarSetMaxInjectedContainersAnnotation = "kruise.io/sidecarset-max-injected-containers"
+	// SidecarSetMaxInjectedResourcesAnnotation is the annotation of namespace, which limits the max total resource
+	// requests of sidecar containers injected into a pod in the namespace, e.g. cpu=1,memory=2Gi.
+	SidecarSetMaxInjectedResourcesAnnotation = "kruise.io/sidecarset-max-injected-resources"
+
+	// SidecarSetConfigHashAnnotation records the hashes of the ConfigMaps and Secrets in configHashFrom of sidecar containers.
+	// In SidecarSet, it is a map from the name of sidecar container to the hash, which is maintained by the sidecarSet controller.
+	// In Pod, it is a map from the name of sidecarSet to the SidecarConfigHashState of its sidecar containers.
+	SidecarSetConfigHashAnnotation = "kruise.io/sidecarset-config-hash"
+
 	// SidecarEnvKey specifies the environment variable which record a container as injected
 	SidecarEnvKey = "IS_INJECTED"
 
@@ -630,3 +650,20 @@ func IsCanarySidecarSet(sidecarSet *appsv1beta1.SidecarSet) (bool, string) {
 
 	return isCanary, sidecarSet.Annotations[appsv1beta1.SidecarSetBaseAnnotation]
 }
+
+// AppendSidecarContainers appends the containers and initContainers of the sidecarSet to the pod as they are.
+func AppendSidecarContainers(pod *corev1.Pod, sidecarSet *appsv1beta1.SidecarSet) {
+	for i := range sidecarSet.Spec.InitContainers {
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, sidecarSet.Spec.InitContainers[i].Container)
+	}
+	for i := range sidecarSet.Spec.Containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, sidecarSet.Spec.Containers[i].Container)
+	}
+}
+
+// IsPodGuaranteedWithSidecarSet returns whether the pod is still Guaranteed with the containers of the sidecarSet.
+func IsPodGuaranteedWithSidecarSet(pod *corev1.Pod, sidecarSet *appsv1beta1.SidecarSet) bool {
+	clone := pod.DeepCopy()
+	AppendSidecarContainers(clone, sidecarSet)
+	return v1qos.GetPodQOS(clone) == corev1.PodQOSGuaranteed
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecarcontrol
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+)
+
+// SidecarConfigHashState is the config hash of a sidecar container in pod.
+type SidecarConfigHashState struct {
+	// Hash is the config hash that the container is running with.
+	Hash string `json:"hash"`
+	// LastContainerID is the ID of the container which is requested to restart for the config hash,
+	// the restart is completed once the container ID in pod status has been changed.
+	LastContainerID string `json:"lastContainerID,omitempty"`
+}
+
+// GetSidecarSetConfigHashes returns the config hashes of sidecar containers in sidecarSet annotations.
+func GetSidecarSetConfigHashes(sidecarSet *appsv1beta1.SidecarSet) map[string]string {
+	hashes := make(map[string]string)
+	if str := sidecarSet.Annotations[SidecarSetConfigHashAnnotation]; str != "" {
+		if err := json.Unmarshal([]byte(str), &hashes); err != nil {
+			klog.ErrorS(err, "Failed to parse sidecarSet annotation", "sidecarSet", klog.KObj(sidecarSet),
+				"annotation", SidecarSetConfigHashAnnotation, "value", str)
+		}
+	}
+	return hashes
+}
+
+// GetPodSidecarConfigHashStates returns the config hash states of sidecar containers in pod annotations,
+// which is a map from the name of sidecarSet to the states of its containers.
+func GetPodSidecarConfigHashStates(pod *corev1.Pod) map[string]map[string]SidecarConfigHashState {
+	states := make(map[string]map[string]SidecarConfigHashState)
+	if str := pod.Annotations[SidecarSetConfigHashAnnotation]; str != "" {
+		if err := json.Unmarshal([]byte(str), &states); err != nil {
+			klog.ErrorS(err, "Failed to parse pod annotation", "pod", klog.KObj(pod),
+				"annotation", SidecarSetConfigHashAnnotation, "value", str)
+		}
+	}
+	return states
+}
+
+// GetSidecarConfigChangedContainers returns the sidecar containers whose config hash in pod is different from the sidecarSet.
+func GetSidecarConfigChangedContainers(sidecarSet *appsv1beta1.SidecarSet, pod *corev1.Pod) []string {
+	hashes := GetSidecarSetConfigHashes(sidecarSet)
+	if len(hashes) == 0 {
+		return nil
+	}
+	podStates := GetPodSidecarConfigHashStates(pod)[sidecarSet.Name]
+	var changed []string
+	for i := range sidecarSet.Spec.Containers {
+		name := sidecarSet.Spec.Containers[i].Name
+		if hash, ok := hashes[name]; ok && podStates[name].Hash != hash {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+// UpdatePodSidecarConfigHashes records the config hashes of sidecarSet in pod annotations,
+// and the IDs of the containers requested to restart for the new config.
+func UpdatePodSidecarConfigHashes(pod *corev1.Pod, sidecarSet *appsv1beta1.SidecarSet, restartContainers []string) {
+	hashes := GetSidecarSetConfigHashes(sidecarSet)
+	states := GetPodSidecarConfigHashStates(pod)
+	if len(hashes) == 0 {
+		if _, ok := states[sidecarSet.Name]; !ok {
+			return
+		}
+		delete(states, sidecarSet.Name)
+	} else {
+		containerIDs := make(map[string]string, len(pod.Status.ContainerStatuses))
+		for i := range pod.Status.ContainerStatuses {
+			containerIDs[pod.Status.ContainerStatuses[i].Name] = pod.Status.ContainerStatuses[i].ContainerID
+		}
+		restartSet := sets.NewString(restartContainers...)
+		setStates := make(map[string]SidecarConfigHashState, len(hashes))
+		for name, hash := range hashes {
+			state := SidecarConfigHashState{Hash: hash}
+			if restartSet.Has(name) {
+				state.LastContainerID = containerIDs[name]
+			}
+			setStates[name] = state
+		}
+		states[sidecarSet.Name] = setStates
+	}
+
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	if len(states) == 0 {
+		delete(pod.Annotations, SidecarSetConfigHashAnnotation)
+		return
+	}
+	by, _ := json.Marshal(states)
+	pod.Annotations[SidecarSetConfigHashAnnotation] = string(by)
+}
+
+// GetSidecarConfigRestartingContainers returns the sidecar containers of sidecarSet which are requested to restart
+// for the new config but have not been restarted yet.
+func GetSidecarConfigRestartingContainers(pod *corev1.Pod, sidecarSetName string) []string {
+	podStates := GetPodSidecarConfigHashStates(pod)[sidecarSetName]
+	if len(podStates) == 0 {
+		return nil
+	}
+	var containers []string
+	for i := range pod.Status.ContainerStatuses {
+		cs := &pod.Status.ContainerStatuses[i]
+		if state, ok := podStates[cs.Name]; ok && state.LastContainerID != "" && state.LastContainerID == cs.ContainerID {
+			containers = append(containers, cs.Name)
+		}
+	}
+	return containers
+}
+
+// IsSidecarConfigRestartCompleted checks whether the containers requested to restart for the new config have been restarted.
+func IsSidecarConfigRestartCompleted(pod *corev1.Pod, sidecarSetName string, containers sets.String) bool {
+	podStates := GetPodSidecarConfigHashStates(pod)[sidecarSetName]
+	if len(podStates) == 0 {
+		return true
+	}
+	for i := range pod.Status.ContainerStatuses {
+		cs := &pod.Status.ContainerStatuses[i]
+		if !containers.Has(cs.Name) {
+			continue
+		}
+		if state, ok := podStates[cs.Name]; ok && state.LastContainerID != "" && state.LastContainerID == cs.ContainerID {
+			return false
+		}
+	}
+	return true
+}
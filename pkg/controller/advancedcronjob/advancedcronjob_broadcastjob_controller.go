@@ -203,6 +203,11 @@ func (r *ReconcileAdvancedCronJob) reconcileBroadcastJob(ctx context.Context, re
 		return ctrl.Result{}, nil
 	}
 
+	if remaining := getSuspendRemaining(&advancedCronJob, realClock{}.Now()); remaining > 0 {
+		klog.V(1).InfoS("AdvancedCronJob suspended until deadline, skipping", "suspendUntil", advancedCronJob.Spec.SuspendUntil, "advancedCronJob", req)
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+
 	/*
 		### 5: Get the next scheduled run
 		If we're not paused, we'll need to calculate the next scheduled run, and whether
@@ -227,12 +232,7 @@ func (r *ReconcileAdvancedCronJob) reconcileBroadcastJob(ctx context.Context, re
 		// for optimization purposes, cheat a bit and start from our last observed run time
 		// we could reconstitute this here, but there's not much point, since we've
 		// just updated it.
-		var earliestTime time.Time
-		if cronJob.Status.LastScheduleTime != nil {
-			earliestTime = cronJob.Status.LastScheduleTime.Time
-		} else {
-			earliestTime = cronJob.ObjectMeta.CreationTimestamp.Time
-		}
+		earliestTime := getScheduleEarliestTime(cronJob)
 		if cronJob.Spec.StartingDeadlineSeconds != nil {
 			// controller is not going to schedule anything below this point
 			schedulingDeadline := now.Add(-time.Second * time.Duration(*cronJob.Spec.StartingDeadlineSeconds))
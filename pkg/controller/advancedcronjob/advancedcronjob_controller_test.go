@@ -459,3 +459,55 @@ func createReconcileJobWithImageListPullJobIndex(scheme *runtime.Scheme, initObj
 	}
 	return reconcileJob
 }
+
+func TestReconcileAdvancedJobSuspendUntil(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(appsv1beta1.AddToScheme(scheme))
+	utilruntime.Must(v1.AddToScheme(scheme))
+
+	job1 := createJob("job-suspend", imageListPullJobTemplate())
+	suspendUntil := metav1.NewTime(fakeClock.Now().Add(30 * time.Minute))
+	job1.Spec.SuspendUntil = &suspendUntil
+
+	reconcileJob := createReconcileJobWithImageListPullJobIndex(scheme, job1)
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "job-suspend",
+			Namespace: "default",
+		},
+	}
+
+	// still suspended, should requeue at suspendUntil without creating any job
+	fakeClock.Step(5 * time.Minute)
+	result, err := reconcileJob.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+	assert.Equal(t, 25*time.Minute, result.RequeueAfter)
+	jobList := &appsv1beta1.ImageListPullJobList{}
+	assert.NoError(t, reconcileJob.List(context.TODO(), jobList, client.InNamespace(request.Namespace)))
+	assert.Equal(t, 0, len(jobList.Items))
+
+	// resumed, only the run after suspendUntil should be scheduled
+	fakeClock.Step(30 * time.Minute)
+	_, err = reconcileJob.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+	assert.NoError(t, reconcileJob.List(context.TODO(), jobList, client.InNamespace(request.Namespace)))
+	assert.Equal(t, 1, len(jobList.Items))
+	assert.Equal(t, suspendUntil.Add(5*time.Minute).Format(time.RFC3339), jobList.Items[0].Annotations[scheduledTimeAnnotation])
+}
+
+func TestGetScheduleEarliestTime(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	lastSchedule := metav1.NewTime(created.Add(time.Hour))
+	suspendUntil := metav1.NewTime(created.Add(48 * time.Hour))
+
+	acj := &appsv1beta1.AdvancedCronJob{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)}}
+	assert.Equal(t, created, getScheduleEarliestTime(acj))
+
+	acj.Status.LastScheduleTime = &lastSchedule
+	assert.Equal(t, lastSchedule.Time, getScheduleEarliestTime(acj))
+
+	acj.Spec.SuspendUntil = &suspendUntil
+	assert.Equal(t, suspendUntil.Time, getScheduleEarliestTime(acj))
+	assert.Equal(t, 47*time.Hour, getSuspendRemaining(acj, created.Add(time.Hour)))
+	assert.Equal(t, time.Duration(0), getSuspendRemaining(acj, suspendUntil.Add(time.Second)))
+}
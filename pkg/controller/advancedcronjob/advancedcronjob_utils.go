@@ -35,3 +35,27 @@ func formatSchedule(acj *appsv1beta1.AdvancedCronJob) string {
 	}
 	return acj.Spec.Schedule
 }
+
+// getSuspendRemaining returns how long the advancedCronJob is still suspended by
+// spec.suspendUntil, zero means it is not suspended at now.
+func getSuspendRemaining(acj *appsv1beta1.AdvancedCronJob, now time.Time) time.Duration {
+	if acj.Spec.SuspendUntil == nil || !now.Before(acj.Spec.SuspendUntil.Time) {
+		return 0
+	}
+	return acj.Spec.SuspendUntil.Sub(now)
+}
+
+// getScheduleEarliestTime returns the time from which missed runs should be counted.
+// Runs missed before spec.suspendUntil are skipped instead of being replayed on resume.
+func getScheduleEarliestTime(acj *appsv1beta1.AdvancedCronJob) time.Time {
+	var earliestTime time.Time
+	if acj.Status.LastScheduleTime != nil {
+		earliestTime = acj.Status.LastScheduleTime.Time
+	} else {
+		earliestTime = acj.ObjectMeta.CreationTimestamp.Time
+	}
+	if acj.Spec.SuspendUntil != nil && acj.Spec.SuspendUntil.After(earliestTime) {
+		earliestTime = acj.Spec.SuspendUntil.Time
+	}
+	return earliestTime
+}
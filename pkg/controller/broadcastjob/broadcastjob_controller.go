@@ -25,6 +25,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -59,6 +60,7 @@ import (
 	utildiscovery "github.com/openkruise/kruise/pkg/util/discovery"
 	"github.com/openkruise/kruise/pkg/util/expectations"
 	"github.com/openkruise/kruise/pkg/util/ratelimiter"
+	"github.com/openkruise/kruise/pkg/util/requeueduration"
 )
 
 func init() {
@@ -69,6 +71,9 @@ func init() {
 const (
 	JobNameLabelKey       = "broadcastjob-name"
 	ControllerUIDLabelKey = "broadcastjob-controller-uid"
+
+	// progressConditionInterval is the min interval to refresh the message of Progress condition
+	progressConditionInterval = 30 * time.Second
 )
 
 var (
@@ -201,6 +206,8 @@ func (r *ReconcileBroadcastJob) Reconcile(_ context.Context, request reconcile.R
 		}
 		return reconcile.Result{}, nil
 	}
+	// the status before this reconcile, to skip updating the status if nothing changed
+	oldStatus := job.Status.DeepCopy()
 	// requeueAfter is zero, meaning no requeue
 	requeueAfter := time.Duration(0)
 	// set the job startTime
@@ -266,14 +273,16 @@ func (r *ReconcileBroadcastJob) Reconcile(_ context.Context, request reconcile.R
 	job.Status.Failed = failed
 	job.Status.Succeeded = succeeded
 	job.Status.Desired = desired
+	excluded := int32(len(nodes.Items)) - desired
+	progressRequeueAfter := setJobProgress(job, excluded, time.Now())
 
 	if job.Status.Phase == appsv1beta1.PhaseFailed {
-		return reconcile.Result{RequeueAfter: requeueAfter}, r.updateJobStatus(request, job)
+		return r.updateJobStatusAndRequeue(request, job, oldStatus, requeueAfter, progressRequeueAfter)
 	}
 
 	if job.Spec.Paused && (job.Status.Phase == appsv1beta1.PhaseRunning || job.Status.Phase == appsv1beta1.PhasePaused) {
 		job.Status.Phase = appsv1beta1.PhasePaused
-		return reconcile.Result{RequeueAfter: requeueAfter}, r.updateJobStatus(request, job)
+		return r.updateJobStatusAndRequeue(request, job, oldStatus, requeueAfter, progressRequeueAfter)
 	}
 	if !job.Spec.Paused && job.Status.Phase == appsv1beta1.PhasePaused {
 		job.Status.Phase = appsv1beta1.PhaseRunning
@@ -288,7 +297,7 @@ func (r *ReconcileBroadcastJob) Reconcile(_ context.Context, request reconcile.R
 			r.recorder.Event(job, corev1.EventTypeWarning, "Paused", "job is paused, due to failed pod")
 			job.Spec.Paused = true
 			job.Status.Phase = appsv1beta1.PhasePaused
-			return reconcile.Result{RequeueAfter: requeueAfter}, r.updateJobStatus(request, job)
+			return r.updateJobStatusAndRequeue(request, job, oldStatus, requeueAfter, progressRequeueAfter)
 		case appsv1beta1.FailurePolicyTypeFailFast:
 			// mark the job is failed
 			jobFailed, failureReason, failureMessage = true, "failed pod is found", "failure policy is FailurePolicyTypeFailFast and failed pod is found"
@@ -343,11 +352,28 @@ func (r *ReconcileBroadcastJob) Reconcile(_ context.Context, request reconcile.R
 	// update the status
 	job.Status.Failed = failed
 	job.Status.Active = active
-	if err := r.updateJobStatus(request, job); err != nil {
-		klog.ErrorS(err, "Failed to update BroadcastJob", "broadcastJob", klog.KObj(job))
+	progressRequeueAfter = setJobProgress(job, excluded, time.Now())
+	res, updateErr := r.updateJobStatusAndRequeue(request, job, oldStatus, requeueAfter, progressRequeueAfter)
+	if updateErr != nil {
+		klog.ErrorS(updateErr, "Failed to update BroadcastJob", "broadcastJob", klog.KObj(job))
 	}
 
-	return reconcile.Result{RequeueAfter: requeueAfter}, err
+	return res, err
+}
+
+// updateJobStatusAndRequeue updates the status of job if it has changed, and requeues the job after the earlier one
+// of requeueAfter and progressRequeueAfter, unless both are zero.
+func (r *ReconcileBroadcastJob) updateJobStatusAndRequeue(request reconcile.Request, job *appsv1beta1.BroadcastJob, oldStatus *appsv1beta1.BroadcastJobStatus,
+	requeueAfter, progressRequeueAfter time.Duration) (reconcile.Result, error) {
+	duration := requeueduration.Duration{}
+	duration.Update(requeueAfter)
+	duration.Update(progressRequeueAfter)
+	res := reconcile.Result{RequeueAfter: duration.Get()}
+	if apiequality.Semantic.DeepEqual(oldStatus, &job.Status) {
+		klog.V(4).InfoS("BroadcastJob status unchanged, skip updating", "broadcastJob", klog.KObj(job))
+		return res, nil
+	}
+	return res, r.updateJobStatus(request, job)
 }
 
 func (r *ReconcileBroadcastJob) updateJobStatus(request reconcile.Request, job *appsv1beta1.BroadcastJob) error {
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
@@ -604,6 +605,119 @@ func TestJobFailedAfterActiveDeadline(t *testing.T) {
 	assert.Equal(t, 0, len(podList.Items))
 }
 
+func TestProgressPercent(t *testing.T) {
+	cases := []struct {
+		succeeded, desired, expected int32
+	}{
+		{0, 0, 0},
+		{5, 0, 0},
+		{0, 4000, 0},
+		{3120, 4000, 78},
+		{3999, 4000, 99},
+		{4000, 4000, 100},
+		{1, 3, 33},
+		{2, 3, 66},
+		{5, 4, 100},
+	}
+	for _, cs := range cases {
+		if got := progressPercent(cs.succeeded, cs.desired); got != cs.expected {
+			t.Fatalf("progressPercent(%d, %d) expected %d, got %d", cs.succeeded, cs.desired, cs.expected, got)
+		}
+	}
+}
+
+func TestSetJobProgress(t *testing.T) {
+	getProgress := func(job *appsv1beta1.BroadcastJob) *appsv1beta1.JobCondition {
+		for i := range job.Status.Conditions {
+			if job.Status.Conditions[i].Type == appsv1beta1.JobProgress {
+				return &job.Status.Conditions[i]
+			}
+		}
+		return nil
+	}
+	now := time.Now()
+	job := createJob("job1", intstr.FromInt(100))
+	job.Status.Desired = 4000
+	job.Status.Succeeded = 3120
+	job.Status.Failed = 12
+
+	// 4003 nodes, 3 of which are excluded from desired
+	assert.Equal(t, time.Duration(0), setJobProgress(job, 3, now))
+	assert.Equal(t, int32(78), job.Status.ProgressPercent)
+	condition := getProgress(job)
+	assert.NotNil(t, condition)
+	assert.Equal(t, "3120/4000 (78%) succeeded, 12 failed, 3 excluded", condition.Message)
+
+	// nothing changed, the condition is untouched
+	before := job.Status.DeepCopy()
+	assert.Equal(t, time.Duration(0), setJobProgress(job, 3, now.Add(time.Minute)))
+	assert.Equal(t, before, &job.Status)
+
+	// the message is throttled within the interval, but the percentage is always up-to-date
+	job.Status.Succeeded = 3200
+	assert.Equal(t, 20*time.Second, setJobProgress(job, 3, now.Add(10*time.Second)))
+	assert.Equal(t, int32(80), job.Status.ProgressPercent)
+	assert.Equal(t, "3120/4000 (78%) succeeded, 12 failed, 3 excluded", getProgress(job).Message)
+
+	// refreshed after the interval
+	assert.Equal(t, time.Duration(0), setJobProgress(job, 3, now.Add(progressConditionInterval)))
+	assert.Equal(t, "3200/4000 (80%) succeeded, 12 failed, 3 excluded", getProgress(job).Message)
+
+	// not throttled once the job is finished
+	job.Status.Succeeded = 3988
+	job.Status.CompletionTime = &metav1.Time{Time: now.Add(progressConditionInterval + time.Second)}
+	assert.Equal(t, time.Duration(0), setJobProgress(job, 3, now.Add(progressConditionInterval+time.Second)))
+	assert.Equal(t, "3988/4000 (99%) succeeded, 12 failed, 3 excluded", getProgress(job).Message)
+	assert.Equal(t, 1, len(job.Status.Conditions))
+}
+
+// Test scenario:
+// 3 nodes, 1 of which is unschedulable
+// reconciling again without any change does not update the job
+func TestReconcileJobProgress(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(appsv1beta1.AddToScheme(scheme))
+	utilruntime.Must(v1.AddToScheme(scheme))
+
+	job1 := createJob("job-progress", intstr.FromInt(10))
+	node1 := createNode("node1")
+	node2 := createNode("node2")
+	node3 := createNode("node3")
+	node3.Spec.Unschedulable = true
+	job1Pod1onNode1 := createPod(job1, "job1pod1node1", "node1", v1.PodSucceeded)
+	job1Pod2onNode2 := createPod(job1, "job1pod2node2", "node2", v1.PodRunning)
+	reconcileJob := createReconcileJob(scheme, job1, job1Pod1onNode1, job1Pod2onNode2, node1, node2, node3)
+
+	request := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "job-progress",
+			Namespace: "default",
+		},
+	}
+
+	_, err := reconcileJob.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+	retrievedJob := &appsv1beta1.BroadcastJob{}
+	err = reconcileJob.Get(context.TODO(), request.NamespacedName, retrievedJob)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), retrievedJob.Status.Desired)
+	assert.Equal(t, int32(50), retrievedJob.Status.ProgressPercent)
+	var message string
+	for _, condition := range retrievedJob.Status.Conditions {
+		if condition.Type == appsv1beta1.JobProgress {
+			message = condition.Message
+		}
+	}
+	assert.Equal(t, "1/2 (50%) succeeded, 0 failed, 1 excluded", message)
+
+	_, err = reconcileJob.Reconcile(context.TODO(), request)
+	assert.NoError(t, err)
+	retrievedJob2 := &appsv1beta1.BroadcastJob{}
+	err = reconcileJob.Get(context.TODO(), request.NamespacedName, retrievedJob2)
+	assert.NoError(t, err)
+	assert.Equal(t, retrievedJob.ResourceVersion, retrievedJob2.ResourceVersion)
+}
+
 func createReconcileJob(scheme *runtime.Scheme, initObjs ...client.Object) ReconcileBroadcastJob {
 	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
 		WithObjects(initObjs...).WithStatusSubresource(&appsv1beta1.BroadcastJob{}).Build()
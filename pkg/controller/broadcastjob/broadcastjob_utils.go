@@ -109,6 +109,51 @@ func pastTTLDeadline(job *appsv1beta1.BroadcastJob) (bool, time.Duration) {
 	return duration >= allowedDuration, allowedDuration - duration
 }
 
+// setJobProgress sets the progressPercent and the Progress condition of job. The message of Progress condition is
+// refreshed at most every progressConditionInterval until the job is finished, so that the status of a job over
+// thousands of nodes is not updated on every pod change. It returns the duration after which the throttled message
+// should be refreshed, or zero if it is up-to-date.
+func setJobProgress(job *appsv1beta1.BroadcastJob, excluded int32, now time.Time) time.Duration {
+	status := &job.Status
+	status.ProgressPercent = progressPercent(status.Succeeded, status.Desired)
+	message := fmt.Sprintf("%d/%d (%d%%) succeeded, %d failed, %d excluded",
+		status.Succeeded, status.Desired, status.ProgressPercent, status.Failed, excluded)
+
+	for i := range status.Conditions {
+		condition := &status.Conditions[i]
+		if condition.Type != appsv1beta1.JobProgress {
+			continue
+		}
+		if condition.Message == message {
+			return 0
+		}
+		if elapsed := now.Sub(condition.LastProbeTime.Time); status.CompletionTime == nil && elapsed < progressConditionInterval {
+			return progressConditionInterval - elapsed
+		}
+		condition.Message = message
+		condition.LastProbeTime = metav1.NewTime(now)
+		return 0
+	}
+
+	condition := newCondition(appsv1beta1.JobProgress, string(appsv1beta1.JobProgress), message)
+	condition.LastProbeTime = metav1.NewTime(now)
+	condition.LastTransitionTime = metav1.NewTime(now)
+	status.Conditions = append(status.Conditions, condition)
+	return 0
+}
+
+// progressPercent returns the percentage of succeeded in desired, rounded down.
+func progressPercent(succeeded, desired int32) int32 {
+	if desired <= 0 {
+		return 0
+	}
+	percent := int64(succeeded) * 100 / int64(desired)
+	if percent > 100 {
+		percent = 100
+	}
+	return int32(percent)
+}
+
 func newCondition(conditionType appsv1beta1.JobConditionType, reason, message string) appsv1beta1.JobCondition {
 	return appsv1beta1.JobCondition{
 		Type:               conditionType,
@@ -68,6 +68,8 @@ import (
 
 func init() {
 	flag.IntVar(&concurrentReconciles, "cloneset-workers", concurrentReconciles, "Max concurrent workers for CloneSet controller.")
+	flag.StringVar(&clonesetutils.DefaultMinKubeletVersionForInPlaceUpdate, "cloneset-inplace-update-min-kubelet-version", "",
+		"Minimum kubelet version of nodes that CloneSet Pods on them can be updated in-place, Pods on older nodes will be recreated instead.")
 	// register prometheus
 	metrics.Registry.MustRegister(CloneSetScaleExpectationLeakageMetrics)
 }
@@ -346,6 +348,7 @@ func (r *ReconcileCloneSet) doReconcile(request reconcile.Request) (res reconcil
 		Conditions:         instance.Status.Conditions,
 	}
 	*newStatus.CollisionCount = collisionCount
+	r.calculateRecreateReasons(instance, &newStatus, currentRevision, updateRevision)
 	if !isPreDownloadDisabled {
 		if currentRevision.Name != updateRevision.Name {
 			// Get minUpdatedReadyPods
@@ -433,6 +436,8 @@ func (r *ReconcileCloneSet) syncCloneSet(
 		}
 		clonesetutils.SetCloneSetCondition(newStatus, cond)
 		err = podsScaleErr
+	} else {
+		clonesetutils.RemoveCloneSetCondition(newStatus, appsv1beta1.CloneSetConditionFailedScale)
 	}
 	if scaling {
 		return podsScaleErr
@@ -450,6 +455,8 @@ func (r *ReconcileCloneSet) syncCloneSet(
 		if err == nil {
 			err = podsUpdateErr
 		}
+	} else {
+		clonesetutils.RemoveCloneSetCondition(newStatus, appsv1beta1.CloneSetConditionFailedUpdate)
 	}
 
 	return err
@@ -530,6 +537,30 @@ func (r *ReconcileCloneSet) getActiveRevisions(cs *appsv1beta1.CloneSet, revisio
 	return currentRevision, updateRevision, collisionCount, nil
 }
 
+// calculateRecreateReasons records the field paths which force pods to be recreated from currentRevision
+// to updateRevision into status, and emits an event when they are calculated for a new revision pair.
+func (r *ReconcileCloneSet) calculateRecreateReasons(cs *appsv1beta1.CloneSet, newStatus *appsv1beta1.CloneSetStatus,
+	currentRevision, updateRevision *apps.ControllerRevision) {
+	if currentRevision.Name == updateRevision.Name || cs.Spec.UpdateStrategy.RollingUpdate == nil {
+		return
+	}
+	if policy := cs.Spec.UpdateStrategy.RollingUpdate.PodUpdatePolicy; policy != appsv1beta1.InPlaceIfPossibleCloneSetPodUpdateStrategyType &&
+		policy != appsv1beta1.InPlaceOnlyCloneSetPodUpdateStrategyType {
+		return
+	}
+
+	reasons, calculated := clonesetutils.GetRecreateReasons(currentRevision, updateRevision, clonesetcore.New(cs).GetUpdateOptions())
+	if len(reasons) == 0 {
+		return
+	}
+	newStatus.RecreateReasons = reasons
+	if calculated {
+		r.recorder.Eventf(cs, v1.EventTypeNormal, "ReCreateReasons",
+			"pods can not be updated in-place from revision %s to %s because of the changes of %v",
+			currentRevision.Name, updateRevision.Name, reasons)
+	}
+}
+
 func (r *ReconcileCloneSet) getOwnedPods(cs *appsv1beta1.CloneSet) ([]*v1.Pod, []*v1.Pod, error) {
 	opts := &client.ListOptions{
 		Namespace:     cs.Namespace,
@@ -621,6 +652,7 @@ func (r *ReconcileCloneSet) truncateHistory(
 		historyLimit = int(*cs.Spec.RevisionHistoryLimit)
 	}
 	if historyLen <= historyLimit {
+		historyutil.RecordRevisionCleanup(clonesetutils.ControllerKind.Kind, len(revisions), 0)
 		return nil
 	}
 	// delete any non-live history to maintain the revision limit.
@@ -630,6 +662,7 @@ func (r *ReconcileCloneSet) truncateHistory(
 			return err
 		}
 	}
+	historyutil.RecordRevisionCleanup(clonesetutils.ControllerKind.Kind, len(revisions)-len(noLiveRevisions), len(noLiveRevisions))
 	return nil
 }
 
@@ -0,0 +1,210 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloneset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+
+	appspub "github.com/openkruise/kruise/apis/apps/pub"
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
+)
+
+const (
+	// maxRolloutReportsRetained is the number of rollout reports retained for each CloneSet in the ConfigMap.
+	maxRolloutReportsRetained = 3
+	// maxRolloutReportSize is the max bytes of a rollout report, so that the ConfigMap can hold all retained reports.
+	maxRolloutReportSize = 200 * 1024
+
+	rolloutReportUpdateTypeInPlace  = "InPlace"
+	rolloutReportUpdateTypeRecreate = "Recreate"
+)
+
+// rolloutReport is the report of a rollout, which is written into the ConfigMap of reportPolicy.
+type rolloutReport struct {
+	CloneSet       string      `json:"cloneSet"`
+	OldRevision    string      `json:"oldRevision"`
+	NewRevision    string      `json:"newRevision"`
+	CompletionTime metav1.Time `json:"completionTime"`
+	Pods           []podReport `json:"pods"`
+	// Truncated indicates some pods are omitted to bound the size of report.
+	Truncated   bool `json:"truncated,omitempty"`
+	OmittedPods int  `json:"omittedPods,omitempty"`
+}
+
+type podReport struct {
+	Name        string       `json:"name"`
+	OldRevision string       `json:"oldRevision"`
+	NewRevision string       `json:"newRevision"`
+	UpdateType  string       `json:"updateType"`
+	StartTime   *metav1.Time `json:"startTime,omitempty"`
+	EndTime     *metav1.Time `json:"endTime,omitempty"`
+	Failures    []string     `json:"failures,omitempty"`
+}
+
+// isRolloutCompleted returns true if the current revision turns to the update revision in the new status,
+// which means all Pods have been updated.
+func isRolloutCompleted(cs *appsv1beta1.CloneSet, newStatus *appsv1beta1.CloneSetStatus) bool {
+	return cs.Status.CurrentRevision != "" &&
+		cs.Status.CurrentRevision != newStatus.CurrentRevision &&
+		newStatus.CurrentRevision == newStatus.UpdateRevision
+}
+
+func (r *realStatusUpdater) writeRolloutReport(cs *appsv1beta1.CloneSet, newStatus *appsv1beta1.CloneSetStatus, pods []*v1.Pod) error {
+	policy := cs.Spec.UpdateStrategy.ReportPolicy
+	if policy == nil || !isRolloutCompleted(cs, newStatus) {
+		return nil
+	}
+
+	report := newRolloutReport(cs, cs.Status.CurrentRevision, newStatus.UpdateRevision, pods)
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	cmName := types.NamespacedName{Namespace: cs.Namespace, Name: policy.ConfigMapName}
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		cm := &v1.ConfigMap{}
+		if err := r.Get(context.TODO(), cmName, cm); err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+			cm = &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: cmName.Namespace, Name: cmName.Name},
+				Data:       map[string]string{report.NewRevision: string(reportJSON)},
+			}
+			return r.Create(context.TODO(), cm)
+		}
+
+		cm = cm.DeepCopy()
+		if cm.Data == nil {
+			cm.Data = make(map[string]string)
+		}
+		cm.Data[report.NewRevision] = string(reportJSON)
+		rotateRolloutReports(cm, cs.Name)
+		return r.Update(context.TODO(), cm)
+	})
+	if err != nil {
+		return err
+	}
+	klog.InfoS("Wrote rollout report for CloneSet", "cloneSet", klog.KObj(cs), "configMap", cmName,
+		"oldRevision", report.OldRevision, "newRevision", report.NewRevision, "pods", len(report.Pods), "truncated", report.Truncated)
+	return nil
+}
+
+func newRolloutReport(cs *appsv1beta1.CloneSet, oldRevision, newRevision string, pods []*v1.Pod) *rolloutReport {
+	report := &rolloutReport{
+		CloneSet:       cs.Name,
+		OldRevision:    oldRevision,
+		NewRevision:    newRevision,
+		CompletionTime: metav1.NewTime(timer.Now()),
+	}
+
+	var podReports []podReport
+	for _, pod := range pods {
+		if clonesetutils.EqualToRevisionHash("", pod, newRevision) {
+			podReports = append(podReports, newPodReport(pod, oldRevision, newRevision))
+		}
+	}
+	sort.Slice(podReports, func(i, j int) bool { return podReports[i].Name < podReports[j].Name })
+
+	// the size of pods is bounded, and the rest fields of report are small enough
+	size := 0
+	for i := range podReports {
+		b, _ := json.Marshal(podReports[i])
+		if size += len(b) + 1; size > maxRolloutReportSize {
+			report.Truncated = true
+			report.OmittedPods = len(podReports) - i
+			podReports = podReports[:i]
+			break
+		}
+	}
+	report.Pods = podReports
+	return report
+}
+
+func newPodReport(pod *v1.Pod, oldRevision, newRevision string) podReport {
+	record := podReport{
+		Name:        pod.Name,
+		OldRevision: oldRevision,
+		NewRevision: newRevision,
+		UpdateType:  rolloutReportUpdateTypeRecreate,
+		StartTime:   pod.CreationTimestamp.DeepCopy(),
+	}
+
+	// the in-place update state is kept in the pod after it has been updated in-place
+	if stateStr, ok := pod.Annotations[appspub.InPlaceUpdateStateKey]; ok {
+		state := appspub.InPlaceUpdateState{}
+		if err := json.Unmarshal([]byte(stateStr), &state); err == nil &&
+			clonesetutils.GetShortHash(state.Revision) == clonesetutils.GetShortHash(newRevision) {
+			record.UpdateType = rolloutReportUpdateTypeInPlace
+			record.StartTime = state.UpdateTimestamp.DeepCopy()
+		}
+	}
+
+	if cond := podutil.GetPodReadyCondition(pod.Status); cond != nil && cond.Status == v1.ConditionTrue &&
+		!cond.LastTransitionTime.Before(record.StartTime) {
+		record.EndTime = cond.LastTransitionTime.DeepCopy()
+	}
+
+	if _, cond := podutil.GetPodCondition(&pod.Status, appspub.InPlaceUpdateReady); cond != nil && cond.Status == v1.ConditionFalse && cond.Message != "" {
+		record.Failures = append(record.Failures, fmt.Sprintf("%s: %s", appspub.InPlaceUpdateReady, cond.Message))
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if waiting := status.State.Waiting; waiting != nil && waiting.Reason != "" && waiting.Reason != "ContainerCreating" && waiting.Reason != "PodInitializing" {
+			record.Failures = append(record.Failures, fmt.Sprintf("container %s waiting: %s", status.Name, waiting.Reason))
+		}
+		if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.ExitCode != 0 &&
+			!terminated.FinishedAt.Before(record.StartTime) {
+			record.Failures = append(record.Failures, fmt.Sprintf("container %s terminated: %s (exit code %d)", status.Name, terminated.Reason, terminated.ExitCode))
+		}
+	}
+	return record
+}
+
+// rotateRolloutReports removes the oldest reports of the CloneSet, to retain the last maxRolloutReportsRetained reports.
+func rotateRolloutReports(cm *v1.ConfigMap, cloneSetName string) {
+	var reports []*rolloutReport
+	for key, value := range cm.Data {
+		report := &rolloutReport{}
+		if err := json.Unmarshal([]byte(value), report); err != nil || report.CloneSet != cloneSetName || report.NewRevision != key {
+			continue
+		}
+		reports = append(reports, report)
+	}
+	if len(reports) <= maxRolloutReportsRetained {
+		return
+	}
+
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[j].CompletionTime.Before(&reports[i].CompletionTime)
+	})
+	for _, report := range reports[maxRolloutReportsRetained:] {
+		delete(cm.Data, report.NewRevision)
+	}
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloneset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appspub "github.com/openkruise/kruise/apis/apps/pub"
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+)
+
+func newReportTestPod(name, revision string, created time.Time, ready *time.Time) *v1.Pod {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         "default",
+			Name:              name,
+			Labels:            map[string]string{apps.ControllerRevisionHashLabelKey: revision},
+			CreationTimestamp: metav1.NewTime(created),
+		},
+	}
+	if ready != nil {
+		pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(*ready)}}
+	}
+	return pod
+}
+
+func TestWriteRolloutReport(t *testing.T) {
+	base := time.Date(2025, 7, 20, 11, 0, 0, 0, time.UTC)
+	at := func(minutes int) *time.Time {
+		t := base.Add(time.Duration(minutes) * time.Minute)
+		return &t
+	}
+
+	cs := &appsv1beta1.CloneSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cs"},
+		Spec: appsv1beta1.CloneSetSpec{
+			Replicas: ptr.To(int32(3)),
+			UpdateStrategy: appsv1beta1.CloneSetUpdateStrategy{
+				ReportPolicy: &appsv1beta1.CloneSetReportPolicy{ConfigMapName: "rollout-reports"},
+			},
+		},
+		Status: appsv1beta1.CloneSetStatus{CurrentRevision: "cs-old", UpdateRevision: "cs-new"},
+	}
+
+	// pod-a has been updated in-place
+	podA := newReportTestPod("pod-a", "cs-new", base, at(12))
+	stateJSON, _ := json.Marshal(appspub.InPlaceUpdateState{Revision: "cs-new", UpdateTimestamp: metav1.NewTime(*at(10))})
+	podA.Annotations = map[string]string{appspub.InPlaceUpdateStateKey: string(stateJSON)}
+	// pod-b has been recreated
+	podB := newReportTestPod("pod-b", "cs-new", *at(11), at(13))
+	// pod-c has been recreated but crashed
+	podC := newReportTestPod("pod-c", "cs-new", *at(11), nil)
+	podC.Status.ContainerStatuses = []v1.ContainerStatus{{
+		Name:                 "main",
+		State:                v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Error", ExitCode: 1, FinishedAt: metav1.NewTime(*at(12))}},
+	}}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(testscheme).WithObjects(cs).WithStatusSubresource(&appsv1beta1.CloneSet{}).Build()
+	r := &realStatusUpdater{Client: fakeClient}
+
+	// no report until all pods have been updated
+	newStatus := &appsv1beta1.CloneSetStatus{CurrentRevision: "cs-old", UpdateRevision: "cs-new"}
+	if err := r.UpdateCloneSetStatus(cs, newStatus, []*v1.Pod{podA, podB, newReportTestPod("pod-d", "cs-old", base, at(1))}); err != nil {
+		t.Fatalf("failed to update status: %v", err)
+	}
+	cm := &v1.ConfigMap{}
+	if err := fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "rollout-reports"}, cm); err == nil {
+		t.Fatalf("expect no report written during rollout, got %v", cm.Data)
+	}
+
+	newStatus = &appsv1beta1.CloneSetStatus{CurrentRevision: "cs-old", UpdateRevision: "cs-new"}
+	if err := r.UpdateCloneSetStatus(cs, newStatus, []*v1.Pod{podC, podB, podA}); err != nil {
+		t.Fatalf("failed to update status: %v", err)
+	}
+	if err := fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "rollout-reports"}, cm); err != nil {
+		t.Fatalf("failed to get report: %v", err)
+	}
+	report := rolloutReport{}
+	if err := json.Unmarshal([]byte(cm.Data["cs-new"]), &report); err != nil {
+		t.Fatalf("failed to unmarshal report: %v", err)
+	}
+	if report.CloneSet != "cs" || report.OldRevision != "cs-old" || report.NewRevision != "cs-new" || report.Truncated {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if len(report.Pods) != 3 {
+		t.Fatalf("expect 3 pods in report, got %+v", report.Pods)
+	}
+
+	expected := []struct {
+		name       string
+		updateType string
+		start      *time.Time
+		end        *time.Time
+		failures   int
+	}{
+		{name: "pod-a", updateType: rolloutReportUpdateTypeInPlace, start: at(10), end: at(12)},
+		{name: "pod-b", updateType: rolloutReportUpdateTypeRecreate, start: at(11), end: at(13)},
+		{name: "pod-c", updateType: rolloutReportUpdateTypeRecreate, start: at(11), failures: 2},
+	}
+	for i, exp := range expected {
+		got := report.Pods[i]
+		if got.Name != exp.name || got.UpdateType != exp.updateType || got.OldRevision != "cs-old" || got.NewRevision != "cs-new" {
+			t.Fatalf("unexpected report of %s: %+v", exp.name, got)
+		}
+		if got.StartTime == nil || !got.StartTime.Time.Equal(*exp.start) {
+			t.Fatalf("expect start time of %s %v, got %v", exp.name, exp.start, got.StartTime)
+		}
+		if (exp.end == nil) != (got.EndTime == nil) || (exp.end != nil && !got.EndTime.Time.Equal(*exp.end)) {
+			t.Fatalf("expect end time of %s %v, got %v", exp.name, exp.end, got.EndTime)
+		}
+		if len(got.Failures) != exp.failures {
+			t.Fatalf("expect %d failures of %s, got %v", exp.failures, exp.name, got.Failures)
+		}
+	}
+}
+
+func TestRotateRolloutReports(t *testing.T) {
+	base := time.Date(2025, 7, 20, 11, 0, 0, 0, time.UTC)
+	newReport := func(cloneSet, revision string, hours int) string {
+		b, _ := json.Marshal(rolloutReport{CloneSet: cloneSet, NewRevision: revision, CompletionTime: metav1.NewTime(base.Add(time.Duration(hours) * time.Hour))})
+		return string(b)
+	}
+
+	cm := &v1.ConfigMap{Data: map[string]string{
+		"cs-1":    newReport("cs", "cs-1", 1),
+		"cs-2":    newReport("cs", "cs-2", 2),
+		"cs-3":    newReport("cs", "cs-3", 3),
+		"other-1": newReport("other", "other-1", 0),
+		"foo":     "bar",
+	}}
+	rotateRolloutReports(cm, "cs")
+	if len(cm.Data) != 5 {
+		t.Fatalf("expect nothing rotated, got %v", cm.Data)
+	}
+
+	cm.Data["cs-4"] = newReport("cs", "cs-4", 4)
+	rotateRolloutReports(cm, "cs")
+	for _, key := range []string{"cs-2", "cs-3", "cs-4", "other-1", "foo"} {
+		if _, ok := cm.Data[key]; !ok {
+			t.Fatalf("expect %s retained, got %v", key, cm.Data)
+		}
+	}
+	if _, ok := cm.Data["cs-1"]; ok {
+		t.Fatalf("expect the oldest report cs-1 rotated, got %v", cm.Data)
+	}
+
+	// the report of an existing revision is overwritten as the latest one
+	cm.Data["cs-2"] = newReport("cs", "cs-2", 5)
+	cm.Data["cs-5"] = newReport("cs", "cs-5", 6)
+	rotateRolloutReports(cm, "cs")
+	if _, ok := cm.Data["cs-3"]; ok || len(cm.Data) != 5 {
+		t.Fatalf("expect cs-3 rotated, got %v", cm.Data)
+	}
+}
+
+func TestRolloutReportTruncated(t *testing.T) {
+	cs := &appsv1beta1.CloneSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cs"}}
+	var pods []*v1.Pod
+	for i := 0; i < 5000; i++ {
+		pods = append(pods, newReportTestPod(fmt.Sprintf("pod-%04d", i), "cs-new", time.Now(), nil))
+	}
+
+	report := newRolloutReport(cs, "cs-old", "cs-new", pods)
+	if !report.Truncated || report.OmittedPods == 0 || len(report.Pods)+report.OmittedPods != len(pods) {
+		t.Fatalf("expect report truncated, got %d pods with %d omitted", len(report.Pods), report.OmittedPods)
+	}
+	b, _ := json.Marshal(report)
+	if len(b) > maxRolloutReportSize+1024 {
+		t.Fatalf("expect report size bounded, got %d bytes", len(b))
+	}
+}
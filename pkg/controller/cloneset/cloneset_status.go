@@ -19,9 +19,12 @@ package cloneset
 import (
 	"context"
 	"fmt"
+	"reflect"
+	"strconv"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
@@ -57,6 +60,13 @@ func (r *realStatusUpdater) UpdateCloneSetStatus(cs *appsv1beta1.CloneSet, newSt
 	if err := clonesetcore.New(cs).ExtraStatusCalculation(newStatus, pods); err != nil {
 		return fmt.Errorf("failed to calculate extra status for cloneSet %s/%s: %v", cs.Namespace, cs.Name, err)
 	}
+	if err := r.updateSurgeAnnotation(cs, newStatus); err != nil {
+		return fmt.Errorf("failed to update surge annotation for cloneSet %s/%s: %v", cs.Namespace, cs.Name, err)
+	}
+	// write the report before updating status, so that it will be retried if failed
+	if err := r.writeRolloutReport(cs, newStatus, pods); err != nil {
+		return fmt.Errorf("failed to write rollout report for cloneSet %s/%s: %v", cs.Namespace, cs.Name, err)
+	}
 	if !r.inconsistentStatus(cs, newStatus) {
 		return nil
 	}
@@ -76,6 +86,27 @@ func (r *realStatusUpdater) updateStatus(cs *appsv1beta1.CloneSet, newStatus *ap
 	})
 }
 
+// updateSurgeAnnotation keeps the expected-surge-replicas annotation consistent with status,
+// so that external autoscalers can subtract the surge Pods during rollout.
+func (r *realStatusUpdater) updateSurgeAnnotation(cs *appsv1beta1.CloneSet, newStatus *appsv1beta1.CloneSetStatus) error {
+	oldValue, exists := cs.Annotations[appsv1beta1.CloneSetExpectedSurgeReplicasAnnotation]
+	var body string
+	if cs.Spec.UpdateStrategy.HPACoordination == appsv1beta1.AnnotateDesiredCloneSetHPACoordinationType {
+		value := strconv.Itoa(int(newStatus.ExpectedSurgeReplicas))
+		if exists && oldValue == value {
+			return nil
+		}
+		body = fmt.Sprintf(`{"metadata":{"annotations":{"%s":"%s"}}}`, appsv1beta1.CloneSetExpectedSurgeReplicasAnnotation, value)
+	} else {
+		if !exists {
+			return nil
+		}
+		body = fmt.Sprintf(`{"metadata":{"annotations":{"%s":null}}}`, appsv1beta1.CloneSetExpectedSurgeReplicasAnnotation)
+	}
+	clone := &appsv1beta1.CloneSet{ObjectMeta: metav1.ObjectMeta{Namespace: cs.Namespace, Name: cs.Name}}
+	return r.Patch(context.TODO(), clone, client.RawPatch(types.MergePatchType, []byte(body)))
+}
+
 func (r *realStatusUpdater) inconsistentStatus(cs *appsv1beta1.CloneSet, newStatus *appsv1beta1.CloneSetStatus) bool {
 	oldStatus := cs.Status
 	return newStatus.ObservedGeneration > oldStatus.ObservedGeneration ||
@@ -86,14 +117,20 @@ func (r *realStatusUpdater) inconsistentStatus(cs *appsv1beta1.CloneSet, newStat
 		newStatus.UpdatedReplicas != oldStatus.UpdatedReplicas ||
 		newStatus.UpdatedAvailableReplicas != oldStatus.UpdatedAvailableReplicas ||
 		newStatus.ExpectedUpdatedReplicas != oldStatus.ExpectedUpdatedReplicas ||
+		newStatus.ExpectedSurgeReplicas != oldStatus.ExpectedSurgeReplicas ||
+		newStatus.KubeletVersionSkewedReplicas != oldStatus.KubeletVersionSkewedReplicas ||
 		newStatus.UpdateRevision != oldStatus.UpdateRevision ||
 		newStatus.CurrentRevision != oldStatus.CurrentRevision ||
 		newStatus.LabelSelector != oldStatus.LabelSelector ||
+		!reflect.DeepEqual(newStatus.RecreateReasons, oldStatus.RecreateReasons) ||
+		!reflect.DeepEqual(newStatus.DecommissionStatuses, oldStatus.DecommissionStatuses) ||
+		newStatus.RolloutState != oldStatus.RolloutState ||
 		hasProgressingConditionChanged(cs.Status, *newStatus)
 }
 
 func (r *realStatusUpdater) calculateStatus(cs *appsv1beta1.CloneSet, newStatus *appsv1beta1.CloneSetStatus, pods []*v1.Pod) {
 	coreControl := clonesetcore.New(cs)
+	inPlaceUpdatePolicy := clonesetutils.IsInPlaceUpdatePolicy(cs)
 	for _, pod := range pods {
 		newStatus.Replicas++
 		if coreControl.IsPodUpdateReady(pod, 0) {
@@ -111,6 +148,13 @@ func (r *realStatusUpdater) calculateStatus(cs *appsv1beta1.CloneSet, newStatus
 		if clonesetutils.EqualToRevisionHash("", pod, newStatus.UpdateRevision) && sync.IsPodAvailable(coreControl, pod, cs.Spec.MinReadySeconds) {
 			newStatus.UpdatedAvailableReplicas++
 		}
+		if inPlaceUpdatePolicy && !clonesetutils.EqualToRevisionHash("", pod, newStatus.UpdateRevision) {
+			if skewed, err := clonesetutils.IsPodOnKubeletVersionSkewedNode(r.Client, cs, pod); err != nil {
+				klog.ErrorS(err, "Failed to check kubelet version of node for CloneSet Pod", "cloneSet", klog.KObj(cs), "pod", klog.KObj(pod))
+			} else if skewed {
+				newStatus.KubeletVersionSkewedReplicas++
+			}
+		}
 	}
 	// Consider the update revision as stable if revisions of all pods are consistent to it and have the expected number of replicas, no need to wait all of them ready
 	if newStatus.UpdatedReplicas == newStatus.Replicas && newStatus.Replicas == *cs.Spec.Replicas {
@@ -124,8 +168,13 @@ func (r *realStatusUpdater) calculateStatus(cs *appsv1beta1.CloneSet, newStatus
 	} else {
 		newStatus.ExpectedUpdatedReplicas = *cs.Spec.Replicas
 	}
+	if cs.Spec.UpdateStrategy.HPACoordination == appsv1beta1.AnnotateDesiredCloneSetHPACoordinationType {
+		newStatus.ExpectedSurgeReplicas = sync.CalculateSurgeReplicas(cs, pods, newStatus.CurrentRevision, newStatus.UpdateRevision)
+	}
+	newStatus.DecommissionStatuses = sync.CalculateDecommissionStatuses(cs, pods)
 	duration := r.calculateProgressingStatus(cs, newStatus)
 	clonesetutils.DurationStore.Push(clonesetutils.GetControllerKey(cs), duration)
+	newStatus.RolloutState = appsv1beta1.GetCloneSetRolloutState(cs, newStatus)
 }
 
 func (r *realStatusUpdater) calculateProgressingStatus(cs *appsv1beta1.CloneSet, newStatus *appsv1beta1.CloneSetStatus) time.Duration {
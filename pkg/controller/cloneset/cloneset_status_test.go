@@ -1,16 +1,20 @@
 package cloneset
 
 import (
+	"context"
+	"fmt"
 	"math"
 	"testing"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/clock"
 	testingclock "k8s.io/utils/clock/testing"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
 	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
@@ -1545,3 +1549,103 @@ func TestGetRequeueSecondsFromCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateSurgeAnnotation(t *testing.T) {
+	maxSurge := intstr.FromInt32(2)
+	cs := &appsv1beta1.CloneSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cs"},
+		Spec: appsv1beta1.CloneSetSpec{
+			Replicas: ptr.To(int32(3)),
+			UpdateStrategy: appsv1beta1.CloneSetUpdateStrategy{
+				HPACoordination: appsv1beta1.AnnotateDesiredCloneSetHPACoordinationType,
+				RollingUpdate:   &appsv1beta1.RollingUpdateCloneSetStrategy{MaxSurge: &maxSurge},
+			},
+		},
+	}
+	var pods []*v1.Pod
+	for i := 0; i < 5; i++ {
+		pods = append(pods, &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: fmt.Sprintf("pod-%d", i)}})
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(testscheme).WithObjects(cs).WithStatusSubresource(&appsv1beta1.CloneSet{}).Build()
+	r := &realStatusUpdater{Client: fakeClient}
+	newStatus := &appsv1beta1.CloneSetStatus{}
+	if err := r.UpdateCloneSetStatus(cs, newStatus, pods); err != nil {
+		t.Fatalf("failed to update status: %v", err)
+	}
+	if newStatus.ExpectedSurgeReplicas != 2 {
+		t.Fatalf("expect expectedSurgeReplicas 2, got %d", newStatus.ExpectedSurgeReplicas)
+	}
+	got := &appsv1beta1.CloneSet{}
+	if err := fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "cs"}, got); err != nil {
+		t.Fatalf("failed to get cloneset: %v", err)
+	}
+	if v := got.Annotations[appsv1beta1.CloneSetExpectedSurgeReplicasAnnotation]; v != "2" {
+		t.Fatalf("expect surge annotation 2, got %q", v)
+	}
+
+	// annotation should be removed once hpaCoordination is disabled
+	got.Spec.UpdateStrategy.HPACoordination = appsv1beta1.NoneCloneSetHPACoordinationType
+	newStatus = &appsv1beta1.CloneSetStatus{}
+	if err := r.UpdateCloneSetStatus(got, newStatus, pods); err != nil {
+		t.Fatalf("failed to update status: %v", err)
+	}
+	if newStatus.ExpectedSurgeReplicas != 0 {
+		t.Fatalf("expect expectedSurgeReplicas 0, got %d", newStatus.ExpectedSurgeReplicas)
+	}
+	if err := fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "cs"}, got); err != nil {
+		t.Fatalf("failed to get cloneset: %v", err)
+	}
+	if _, ok := got.Annotations[appsv1beta1.CloneSetExpectedSurgeReplicasAnnotation]; ok {
+		t.Fatalf("expect surge annotation removed, got %v", got.Annotations)
+	}
+}
+
+func TestCalculateKubeletVersionSkewedReplicas(t *testing.T) {
+	cs := &appsv1beta1.CloneSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "cs"},
+		Spec: appsv1beta1.CloneSetSpec{
+			Replicas: ptr.To(int32(4)),
+			UpdateStrategy: appsv1beta1.CloneSetUpdateStrategy{
+				RollingUpdate: &appsv1beta1.RollingUpdateCloneSetStrategy{
+					PodUpdatePolicy: appsv1beta1.InPlaceIfPossibleCloneSetPodUpdateStrategyType,
+				},
+				MinKubeletVersionForInPlaceUpdate: "v1.25.0",
+			},
+		},
+	}
+	nodes := []*v1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-new"}, Status: v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{KubeletVersion: "v1.28.3"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-old"}, Status: v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{KubeletVersion: "v1.20.15"}}},
+	}
+	newPod := func(name, nodeName, revision string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: map[string]string{"controller-revision-hash": revision}},
+			Spec:       v1.PodSpec{NodeName: nodeName},
+		}
+	}
+	pods := []*v1.Pod{
+		newPod("pod-0", "node-new", "rev_old"),
+		newPod("pod-1", "node-old", "rev_old"),
+		newPod("pod-2", "node-old", "rev_new"),
+		newPod("pod-3", "node-old", "rev_old"),
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(testscheme).WithObjects(nodes[0], nodes[1]).Build()
+	r := &realStatusUpdater{Client: fakeClient}
+	newStatus := &appsv1beta1.CloneSetStatus{UpdateRevision: "rev_new"}
+	r.calculateStatus(cs, newStatus, pods)
+	if newStatus.KubeletVersionSkewedReplicas != 2 {
+		t.Fatalf("expect kubeletVersionSkewedReplicas 2, got %d", newStatus.KubeletVersionSkewedReplicas)
+	}
+	if newStatus.RolloutState != appsv1beta1.CloneSetRolloutProgressing {
+		t.Fatalf("expect rolloutState Progressing, got %s", newStatus.RolloutState)
+	}
+
+	cs.Spec.UpdateStrategy.RollingUpdate.PodUpdatePolicy = appsv1beta1.RecreateCloneSetPodUpdateStrategyType
+	newStatus = &appsv1beta1.CloneSetStatus{UpdateRevision: "rev_new"}
+	r.calculateStatus(cs, newStatus, pods)
+	if newStatus.KubeletVersionSkewedReplicas != 0 {
+		t.Fatalf("expect kubeletVersionSkewedReplicas 0 for ReCreate policy, got %d", newStatus.KubeletVersionSkewedReplicas)
+	}
+}
@@ -27,6 +27,8 @@ import (
 	"github.com/openkruise/kruise/pkg/util/controllerfinder"
 	"github.com/openkruise/kruise/pkg/util/inplaceupdate"
 	"github.com/openkruise/kruise/pkg/util/lifecycle"
+	"github.com/openkruise/kruise/pkg/util/podadapter"
+	utilpodreadiness "github.com/openkruise/kruise/pkg/util/podreadiness"
 )
 
 // Interface for managing pods scaling and updating.
@@ -49,6 +51,8 @@ type realControl struct {
 	inplaceControl   inplaceupdate.Interface
 	recorder         record.EventRecorder
 	controllerFinder *controllerfinder.ControllerFinder
+
+	podReadinessControl utilpodreadiness.Interface
 }
 
 func New(c client.Client, recorder record.EventRecorder) Interface {
@@ -58,5 +62,7 @@ func New(c client.Client, recorder record.EventRecorder) Interface {
 		lifecycleControl: lifecycle.New(c),
 		recorder:         recorder,
 		controllerFinder: controllerfinder.Finder,
+
+		podReadinessControl: utilpodreadiness.NewForAdapter(&podadapter.AdapterRuntimeClient{Client: c}),
 	}
 }
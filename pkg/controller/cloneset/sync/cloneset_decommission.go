@@ -0,0 +1,283 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sync
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	appspub "github.com/openkruise/kruise/apis/apps/pub"
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
+	"github.com/openkruise/kruise/pkg/util/lifecycle"
+	utilpodreadiness "github.com/openkruise/kruise/pkg/util/podreadiness"
+)
+
+var decommissionReadinessMessage = utilpodreadiness.Message{UserAgent: "CloneSet", Key: "decommission"}
+
+// getDecommission returns the decommission request for the pod in scaleStrategy.decommission, or nil if not requested.
+func getDecommission(cs *appsv1beta1.CloneSet, pod *v1.Pod) *appsv1beta1.CloneSetDecommission {
+	for i := range cs.Spec.ScaleStrategy.Decommission {
+		if cs.Spec.ScaleStrategy.Decommission[i].PodName == pod.Name {
+			return &cs.Spec.ScaleStrategy.Decommission[i]
+		}
+	}
+	return nil
+}
+
+func isPodDecommissionStarted(pod *v1.Pod) bool {
+	_, ok := pod.Labels[appsv1beta1.CloneSetPodDecommissioningKey]
+	return ok
+}
+
+// excludeDecommissioningPods returns the pods not requested to decommission. The decommissioning pods are
+// managed by manageDecommission only, and they are replaced by the new pods as soon as requested.
+func excludeDecommissioningPods(cs *appsv1beta1.CloneSet, pods []*v1.Pod) []*v1.Pod {
+	if len(cs.Spec.ScaleStrategy.Decommission) == 0 {
+		return pods
+	}
+	filtered := make([]*v1.Pod, 0, len(pods))
+	for _, pod := range pods {
+		if getDecommission(cs, pod) == nil {
+			filtered = append(filtered, pod)
+		}
+	}
+	return filtered
+}
+
+// getPodDecommissionPhase returns the phase of the pod requested to decommission with a message,
+// and the duration after which it times out waiting for the finalizer removal.
+func getPodDecommissionPhase(cs *appsv1beta1.CloneSet, d *appsv1beta1.CloneSetDecommission, pod *v1.Pod) (appsv1beta1.CloneSetDecommissionPhase, string, time.Duration) {
+	if !isPodDecommissionStarted(pod) {
+		return appsv1beta1.CloneSetDecommissionPending, "", 0
+	}
+	if cs.Spec.Lifecycle != nil && lifecycle.IsPodHooked(cs.Spec.Lifecycle.PreDelete, pod) {
+		return appsv1beta1.CloneSetDecommissionDraining, "waiting for the PreDelete hook", 0
+	}
+	if d.RequireFinalizerRemoval && controllerutil.ContainsFinalizer(pod, appsv1beta1.CloneSetDecommissionFinalizer) {
+		if d.TimeoutSeconds == nil {
+			return appsv1beta1.CloneSetDecommissionOffloading, fmt.Sprintf("waiting for finalizer %s to be removed", appsv1beta1.CloneSetDecommissionFinalizer), 0
+		}
+		timeout := time.Duration(*d.TimeoutSeconds) * time.Second
+		// the waiting has not been recorded to start yet, so the time spent in the PreDelete hook is not counted
+		value, ok := pod.Annotations[appsv1beta1.CloneSetPodDecommissionOffloadTimeKey]
+		if !ok {
+			return appsv1beta1.CloneSetDecommissionOffloading, fmt.Sprintf("waiting for finalizer %s to be removed", appsv1beta1.CloneSetDecommissionFinalizer), timeout
+		}
+		// the start time is invalid, regard it as timed out
+		startTime, err := time.Parse(time.RFC3339, value)
+		if err == nil {
+			if left := startTime.Add(timeout).Sub(timer.Now()); left > 0 {
+				return appsv1beta1.CloneSetDecommissionOffloading, fmt.Sprintf("waiting for finalizer %s to be removed", appsv1beta1.CloneSetDecommissionFinalizer), left
+			}
+		}
+		return appsv1beta1.CloneSetDecommissionTimeout, fmt.Sprintf("finalizer %s has not been removed in %ds", appsv1beta1.CloneSetDecommissionFinalizer, *d.TimeoutSeconds), 0
+	}
+	return appsv1beta1.CloneSetDecommissionDeleting, "", 0
+}
+
+// manageDecommission decommissions the pods requested in scaleStrategy.decommission in sequence: mark the pod not ready,
+// wait for the PreDelete hook, wait for the finalizer to be removed by the requester, and then delete it. If the finalizer
+// is not removed within timeoutSeconds, the controller removes it and deletes the pod, whose replacement already exists.
+// The pods whose requests have been removed are recovered unless they are already in PreparingDelete.
+func (r *realControl) manageDecommission(cs *appsv1beta1.CloneSet, pods []*v1.Pod, pvcs []*v1.PersistentVolumeClaim) (bool, error) {
+	var modified bool
+	var podsToDelete []*v1.Pod
+	for _, pod := range pods {
+		d := getDecommission(cs, pod)
+		if d == nil {
+			if isPodDecommissionStarted(pod) && lifecycle.GetPodLifecycleState(pod) != appspub.LifecycleStatePreparingDelete {
+				klog.V(3).InfoS("CloneSet canceled decommission of pod", "cloneSet", klog.KObj(cs), "pod", klog.KObj(pod))
+				if err := r.undecommissionPod(cs, pod); err != nil {
+					return modified, err
+				}
+				modified = true
+			}
+			continue
+		}
+
+		if !isPodDecommissionStarted(pod) {
+			if err := r.decommissionPod(cs, pod); err != nil {
+				return modified, err
+			}
+			modified = true
+			continue
+		}
+
+		phase, _, timeoutLeft := getPodDecommissionPhase(cs, d, pod)
+		switch phase {
+		case appsv1beta1.CloneSetDecommissionDraining:
+			if lifecycle.GetPodLifecycleState(pod) == appspub.LifecycleStatePreparingDelete {
+				continue
+			}
+			if updated, gotPod, err := r.lifecycleControl.UpdatePodLifecycle(pod, appspub.LifecycleStatePreparingDelete, cs.Spec.Lifecycle.PreDelete.MarkPodNotReady); err != nil {
+				return modified, err
+			} else if updated {
+				klog.V(3).InfoS("CloneSet decommission update pod lifecycle to PreparingDelete", "cloneSet", klog.KObj(cs), "pod", klog.KObj(pod))
+				modified = true
+				clonesetutils.ResourceVersionExpectations.Expect(gotPod)
+			}
+		case appsv1beta1.CloneSetDecommissionOffloading:
+			if _, ok := pod.Annotations[appsv1beta1.CloneSetPodDecommissionOffloadTimeKey]; !ok && d.TimeoutSeconds != nil {
+				if err := r.startDecommissionOffload(cs, pod); err != nil {
+					return modified, err
+				}
+				modified = true
+			}
+			if timeoutLeft > 0 {
+				clonesetutils.DurationStore.Push(clonesetutils.GetControllerKey(cs), timeoutLeft)
+			}
+		case appsv1beta1.CloneSetDecommissionTimeout:
+			gotPod, err := r.removeDecommissionFinalizer(cs, pod)
+			if err != nil {
+				return modified, err
+			}
+			modified = true
+			podsToDelete = append(podsToDelete, gotPod)
+		case appsv1beta1.CloneSetDecommissionDeleting:
+			podsToDelete = append(podsToDelete, pod)
+		}
+	}
+
+	if len(podsToDelete) > 0 {
+		deleted, err := r.deletePods(cs, podsToDelete, pvcs)
+		return modified || deleted, err
+	}
+	return modified, nil
+}
+
+func (r *realControl) decommissionPod(cs *appsv1beta1.CloneSet, pod *v1.Pod) error {
+	newPod := pod.DeepCopy()
+	if newPod.Labels == nil {
+		newPod.Labels = map[string]string{}
+	}
+	if newPod.Annotations == nil {
+		newPod.Annotations = map[string]string{}
+	}
+	newPod.Labels[appsv1beta1.CloneSetPodDecommissioningKey] = "true"
+	newPod.Annotations[appsv1beta1.CloneSetPodDecommissionTimeKey] = timer.Now().Format(time.RFC3339)
+	if err := r.Patch(context.TODO(), newPod, client.MergeFrom(pod)); err != nil {
+		r.recorder.Eventf(cs, v1.EventTypeWarning, "FailedDecommission", "failed to decommission pod %s: %v", pod.Name, err)
+		return err
+	}
+	clonesetutils.ResourceVersionExpectations.Expect(newPod)
+
+	if err := r.podReadinessControl.AddNotReadyKey(newPod, decommissionReadinessMessage); err != nil {
+		return err
+	}
+	r.recorder.Eventf(cs, v1.EventTypeNormal, "SuccessfulDecommission", "succeed to start decommission of pod %s", pod.Name)
+	return nil
+}
+
+func (r *realControl) startDecommissionOffload(cs *appsv1beta1.CloneSet, pod *v1.Pod) error {
+	newPod := pod.DeepCopy()
+	if newPod.Annotations == nil {
+		newPod.Annotations = map[string]string{}
+	}
+	newPod.Annotations[appsv1beta1.CloneSetPodDecommissionOffloadTimeKey] = timer.Now().Format(time.RFC3339)
+	if err := r.Patch(context.TODO(), newPod, client.MergeFrom(pod)); err != nil {
+		return err
+	}
+	clonesetutils.ResourceVersionExpectations.Expect(newPod)
+	return nil
+}
+
+// removeDecommissionFinalizer removes the finalizer of the pod that has timed out waiting for the requester to remove it.
+func (r *realControl) removeDecommissionFinalizer(cs *appsv1beta1.CloneSet, pod *v1.Pod) (*v1.Pod, error) {
+	newPod := pod.DeepCopy()
+	controllerutil.RemoveFinalizer(newPod, appsv1beta1.CloneSetDecommissionFinalizer)
+	if err := r.Patch(context.TODO(), newPod, client.MergeFrom(pod)); err != nil {
+		r.recorder.Eventf(cs, v1.EventTypeWarning, "FailedDecommission", "failed to remove finalizer %s of pod %s: %v", appsv1beta1.CloneSetDecommissionFinalizer, pod.Name, err)
+		return nil, err
+	}
+	clonesetutils.ResourceVersionExpectations.Expect(newPod)
+	r.recorder.Eventf(cs, v1.EventTypeWarning, "DecommissionTimeout", "finalizer %s of pod %s has not been removed in time, force delete it", appsv1beta1.CloneSetDecommissionFinalizer, pod.Name)
+	return newPod, nil
+}
+
+func (r *realControl) undecommissionPod(cs *appsv1beta1.CloneSet, pod *v1.Pod) error {
+	newPod := pod.DeepCopy()
+	delete(newPod.Labels, appsv1beta1.CloneSetPodDecommissioningKey)
+	delete(newPod.Annotations, appsv1beta1.CloneSetPodDecommissionTimeKey)
+	delete(newPod.Annotations, appsv1beta1.CloneSetPodDecommissionOffloadTimeKey)
+	if err := r.Patch(context.TODO(), newPod, client.MergeFrom(pod)); err != nil {
+		r.recorder.Eventf(cs, v1.EventTypeWarning, "FailedUndecommission", "failed to cancel decommission of pod %s: %v", pod.Name, err)
+		return err
+	}
+	clonesetutils.ResourceVersionExpectations.Expect(newPod)
+
+	if err := r.podReadinessControl.RemoveNotReadyKey(newPod, decommissionReadinessMessage); err != nil {
+		return err
+	}
+	r.recorder.Eventf(cs, v1.EventTypeNormal, "SuccessfulUndecommission", "succeed to cancel decommission of pod %s", pod.Name)
+	return nil
+}
+
+// CalculateDecommissionStatuses returns the progress of the pods in scaleStrategy.decommission.
+// A pod that has disappeared is regarded as completed only if it was being deleted for the decommission.
+func CalculateDecommissionStatuses(cs *appsv1beta1.CloneSet, pods []*v1.Pod) []appsv1beta1.CloneSetDecommissionStatus {
+	if len(cs.Spec.ScaleStrategy.Decommission) == 0 {
+		return nil
+	}
+	podMap := make(map[string]*v1.Pod, len(pods))
+	for _, pod := range pods {
+		podMap[pod.Name] = pod
+	}
+	oldStatuses := make(map[string]*appsv1beta1.CloneSetDecommissionStatus, len(cs.Status.DecommissionStatuses))
+	for i := range cs.Status.DecommissionStatuses {
+		oldStatuses[cs.Status.DecommissionStatuses[i].PodName] = &cs.Status.DecommissionStatuses[i]
+	}
+
+	statuses := make([]appsv1beta1.CloneSetDecommissionStatus, 0, len(cs.Spec.ScaleStrategy.Decommission))
+	for i := range cs.Spec.ScaleStrategy.Decommission {
+		d := &cs.Spec.ScaleStrategy.Decommission[i]
+		status := appsv1beta1.CloneSetDecommissionStatus{PodName: d.PodName}
+		oldStatus := oldStatuses[d.PodName]
+
+		if pod, ok := podMap[d.PodName]; ok {
+			status.Phase, status.Message, _ = getPodDecommissionPhase(cs, d, pod)
+			if startTime, err := time.Parse(time.RFC3339, pod.Annotations[appsv1beta1.CloneSetPodDecommissionTimeKey]); err == nil {
+				// keep consistent with the time decoded from the status, to avoid updating it every time
+				t := metav1.NewTime(startTime.Local())
+				status.StartTime = &t
+			}
+		} else if oldStatus == nil {
+			status.Phase = appsv1beta1.CloneSetDecommissionAborted
+			status.Message = "pod not found"
+		} else {
+			status.StartTime = oldStatus.StartTime
+			switch oldStatus.Phase {
+			case appsv1beta1.CloneSetDecommissionDeleting, appsv1beta1.CloneSetDecommissionTimeout, appsv1beta1.CloneSetDecommissionCompleted:
+				status.Phase = appsv1beta1.CloneSetDecommissionCompleted
+			case appsv1beta1.CloneSetDecommissionAborted:
+				status.Phase, status.Message = oldStatus.Phase, oldStatus.Message
+			default:
+				status.Phase = appsv1beta1.CloneSetDecommissionAborted
+				status.Message = fmt.Sprintf("pod disappeared during %s", oldStatus.Phase)
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
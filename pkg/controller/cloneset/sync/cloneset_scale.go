@@ -22,11 +22,14 @@ import (
 	"sort"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appspub "github.com/openkruise/kruise/apis/apps/pub"
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
@@ -35,6 +38,7 @@ import (
 	"github.com/openkruise/kruise/pkg/util"
 	"github.com/openkruise/kruise/pkg/util/expectations"
 	"github.com/openkruise/kruise/pkg/util/lifecycle"
+	utilpodreadiness "github.com/openkruise/kruise/pkg/util/podreadiness"
 	"github.com/openkruise/kruise/pkg/util/revision"
 )
 
@@ -46,6 +50,12 @@ const (
 	initialBatchSize = 1
 )
 
+var (
+	timer clock.Clock = clock.RealClock{}
+
+	quarantineReadinessMessage = utilpodreadiness.Message{UserAgent: "CloneSet", Key: "quarantine"}
+)
+
 func (r *realControl) Scale(
 	currentCS, updateCS *appsv1beta1.CloneSet,
 	currentRevision, updateRevision string,
@@ -61,13 +71,23 @@ func (r *realControl) Scale(
 		return false, nil
 	}
 
-	// 1. manage pods to delete and in preDelete
-	podsSpecifiedToDelete, podsInPreDelete, numToDelete := getPlannedDeletedPods(updateCS, pods)
+	// 1. manage pods to decommission, which are excluded from the replicas to be replaced immediately
+	if modified, err := r.manageDecommission(updateCS, pods, pvcs); err != nil || modified {
+		return modified, err
+	}
+	allPods := pods
+	pods = excludeDecommissioningPods(updateCS, pods)
+
+	// 2. manage pods to delete, in preDelete and quarantined
+	podsSpecifiedToDelete, podsInPreDelete, podsQuarantined, numToDelete := getPlannedDeletedPods(updateCS, pods)
 	if modified, err := r.managePreparingDelete(updateCS, pods, podsInPreDelete, numToDelete); err != nil || modified {
 		return modified, err
 	}
+	if modified, err := r.manageQuarantine(updateCS, pods, podsQuarantined, numToDelete); err != nil || modified {
+		return modified, err
+	}
 
-	// 2. calculate scale numbers
+	// 3. calculate scale numbers
 	diffRes := calculateDiffsWithExpectation(updateCS, pods, currentRevision, updateRevision, revision.IsPodUpdate)
 	updatedPods, notUpdatedPods := clonesetutils.GroupUpdateAndNotUpdatePods(pods, updateRevision)
 
@@ -75,7 +95,7 @@ func (r *realControl) Scale(
 		r.recorder.Event(updateCS, v1.EventTypeWarning, "ScaleUpLimited", fmt.Sprintf("scaleUp is limited because of scaleStrategy.maxUnavailable, limit: %d", diffRes.scaleUpLimit))
 	}
 
-	// 3. scale out
+	// 4. scale out
 	if diffRes.scaleUpNum > 0 {
 		// total number of this creation
 		expectedCreations := diffRes.scaleUpLimit
@@ -86,7 +106,7 @@ func (r *realControl) Scale(
 			"cloneSet", klog.KObj(updateCS), "expectedCreations", expectedCreations, "expectedCurrentCreations", expectedCurrentCreations)
 
 		// available instance-id come from free pvc
-		availableIDs := getOrGenAvailableIDs(expectedCreations, pods, pvcs)
+		availableIDs := getOrGenAvailableIDs(expectedCreations, allPods, pvcs)
 		// existing pvc names
 		existingPVCNames := sets.NewString()
 		for _, pvc := range pvcs {
@@ -97,7 +117,7 @@ func (r *realControl) Scale(
 			currentCS, updateCS, currentRevision, updateRevision, availableIDs.List(), existingPVCNames)
 	}
 
-	// 4. try to delete pods already in pre-delete
+	// 5. try to delete pods already in pre-delete
 	if len(podsInPreDelete) > 0 {
 		klog.V(3).InfoS("CloneSet tried to delete pods in preDelete", "cloneSet", klog.KObj(updateCS), "pods", util.GetPodNames(podsInPreDelete).List())
 		if modified, err := r.deletePods(updateCS, podsInPreDelete, pvcs); err != nil || modified {
@@ -105,7 +125,7 @@ func (r *realControl) Scale(
 		}
 	}
 
-	// 5. specified delete
+	// 6. specified delete
 	if podsToDelete := util.DiffPods(podsSpecifiedToDelete, podsInPreDelete); len(podsToDelete) > 0 {
 		newPodsToDelete, oldPodsToDelete := clonesetutils.GroupUpdateAndNotUpdatePods(podsToDelete, updateRevision)
 		klog.V(3).InfoS("CloneSet tried to delete pods specified", "cloneSet", klog.KObj(updateCS), "deleteReadyLimit", diffRes.deleteReadyLimit,
@@ -126,9 +146,24 @@ func (r *realControl) Scale(
 		}
 	}
 
-	// 6. scale in
+	// 7. delete pods whose quarantine has expired
+	if len(podsQuarantined) > 0 {
+		podsExpired, requeueDuration := getQuarantineExpiredPods(updateCS, podsQuarantined)
+		if requeueDuration > 0 {
+			clonesetutils.DurationStore.Push(clonesetutils.GetControllerKey(updateCS), requeueDuration)
+		}
+		if len(podsExpired) > 0 {
+			klog.V(3).InfoS("CloneSet tried to delete pods quarantine expired", "cloneSet", klog.KObj(updateCS), "pods", util.GetPodNames(podsExpired).List())
+			if modified, err := r.deletePods(updateCS, podsExpired, pvcs); err != nil || modified {
+				return modified, err
+			}
+		}
+	}
+
+	// 8. scale in
 	if diffRes.scaleDownNum > 0 {
-		if numToDelete > 0 {
+		// quarantined pods are already excluded from the scale down number
+		if numToDelete-len(podsQuarantined) > 0 {
 			klog.V(3).InfoS("CloneSet skipped to scale in for deletion", "cloneSet", klog.KObj(updateCS), "scaleDownNum", diffRes.scaleDownNum,
 				"numToDelete", numToDelete, "specifiedToDelete", len(podsSpecifiedToDelete), "preDelete", len(podsInPreDelete))
 			return false, nil
@@ -137,18 +172,33 @@ func (r *realControl) Scale(
 		klog.V(3).InfoS("CloneSet began to scale in", "cloneSet", klog.KObj(updateCS), "scaleDownNum", diffRes.scaleDownNum,
 			"oldRevision", diffRes.scaleDownNumOldRevision, "deleteReadyLimit", diffRes.deleteReadyLimit)
 
+		if len(podsQuarantined) > 0 {
+			updatedPods = util.DiffPods(updatedPods, podsQuarantined)
+			notUpdatedPods = util.DiffPods(notUpdatedPods, podsQuarantined)
+		}
 		podsPreparingToDelete := r.choosePodsToDelete(updateCS, diffRes.scaleDownNum, diffRes.scaleDownNumOldRevision, notUpdatedPods, updatedPods)
 		podsToDelete := make([]*v1.Pod, 0, len(podsPreparingToDelete))
+		podsToQuarantine := make([]*v1.Pod, 0, len(podsPreparingToDelete))
 		for _, pod := range podsPreparingToDelete {
 			if !isPodReady(coreControl, pod) {
 				podsToDelete = append(podsToDelete, pod)
 			} else if diffRes.deleteReadyLimit > 0 {
-				podsToDelete = append(podsToDelete, pod)
+				// only ready pods are worth quarantining, the unready ones can be deleted directly
+				if updateCS.Spec.ScaleStrategy.QuarantineSeconds > 0 {
+					podsToQuarantine = append(podsToQuarantine, pod)
+				} else {
+					podsToDelete = append(podsToDelete, pod)
+				}
 				diffRes.deleteReadyLimit--
 			}
 		}
 
-		return r.deletePods(updateCS, podsToDelete, pvcs)
+		modified, err := r.quarantinePods(updateCS, podsToQuarantine)
+		if err != nil {
+			return modified, err
+		}
+		deleted, err := r.deletePods(updateCS, podsToDelete, pvcs)
+		return modified || deleted, err
 	}
 
 	return false, nil
@@ -180,12 +230,82 @@ func (r *realControl) managePreparingDelete(cs *appsv1beta1.CloneSet, pods, pods
 		} else if updated {
 			modified = true
 			clonesetutils.ResourceVersionExpectations.Expect(gotPod)
+			if isPodQuarantined(gotPod) {
+				if err := r.unquarantinePod(cs, gotPod); err != nil {
+					return modified, err
+				}
+			}
+		}
+		diff--
+	}
+	return modified, nil
+}
+
+// manageQuarantine recovers the quarantined pods if the CloneSet scales out again during their quarantine,
+// which is preferred to creating new pods.
+func (r *realControl) manageQuarantine(cs *appsv1beta1.CloneSet, pods, podsQuarantined []*v1.Pod, numToDelete int) (bool, error) {
+	diff := int(*cs.Spec.Replicas) - len(pods) + numToDelete
+	var modified bool
+	for _, pod := range podsQuarantined {
+		if diff <= 0 {
+			return modified, nil
 		}
+
+		klog.V(3).InfoS("CloneSet canceled quarantine of pod for scaling out", "cloneSet", klog.KObj(cs), "pod", klog.KObj(pod))
+		if err := r.unquarantinePod(cs, pod); err != nil {
+			return modified, err
+		}
+		modified = true
 		diff--
 	}
 	return modified, nil
 }
 
+func (r *realControl) quarantinePods(cs *appsv1beta1.CloneSet, podsToQuarantine []*v1.Pod) (bool, error) {
+	var modified bool
+	for _, pod := range podsToQuarantine {
+		newPod := pod.DeepCopy()
+		if newPod.Labels == nil {
+			newPod.Labels = map[string]string{}
+		}
+		if newPod.Annotations == nil {
+			newPod.Annotations = map[string]string{}
+		}
+		newPod.Labels[appsv1beta1.CloneSetPodQuarantinedKey] = "true"
+		newPod.Annotations[appsv1beta1.CloneSetPodQuarantineTimeKey] = timer.Now().Format(time.RFC3339)
+		if err := r.Patch(context.TODO(), newPod, client.MergeFrom(pod)); err != nil {
+			r.recorder.Eventf(cs, v1.EventTypeWarning, "FailedQuarantine", "failed to quarantine pod %s: %v", pod.Name, err)
+			return modified, err
+		}
+		modified = true
+		clonesetutils.ResourceVersionExpectations.Expect(newPod)
+
+		if err := r.podReadinessControl.AddNotReadyKey(newPod, quarantineReadinessMessage); err != nil {
+			return modified, err
+		}
+		r.recorder.Eventf(cs, v1.EventTypeNormal, "SuccessfulQuarantine", "succeed to quarantine pod %s for %ds before deleted",
+			pod.Name, cs.Spec.ScaleStrategy.QuarantineSeconds)
+	}
+	return modified, nil
+}
+
+func (r *realControl) unquarantinePod(cs *appsv1beta1.CloneSet, pod *v1.Pod) error {
+	newPod := pod.DeepCopy()
+	delete(newPod.Labels, appsv1beta1.CloneSetPodQuarantinedKey)
+	delete(newPod.Annotations, appsv1beta1.CloneSetPodQuarantineTimeKey)
+	if err := r.Patch(context.TODO(), newPod, client.MergeFrom(pod)); err != nil {
+		r.recorder.Eventf(cs, v1.EventTypeWarning, "FailedUnquarantine", "failed to unquarantine pod %s: %v", pod.Name, err)
+		return err
+	}
+	clonesetutils.ResourceVersionExpectations.Expect(newPod)
+
+	if err := r.podReadinessControl.RemoveNotReadyKey(newPod, quarantineReadinessMessage); err != nil {
+		return err
+	}
+	r.recorder.Eventf(cs, v1.EventTypeNormal, "SuccessfulUnquarantine", "succeed to unquarantine pod %s", pod.Name)
+	return nil
+}
+
 func (r *realControl) createPods(
 	expectedCreations, expectedCurrentCreations int,
 	currentCS, updateCS *appsv1beta1.CloneSet,
@@ -307,9 +427,10 @@ func (r *realControl) deletePods(cs *appsv1beta1.CloneSet, podsToDelete []*v1.Po
 	return modified, nil
 }
 
-func getPlannedDeletedPods(cs *appsv1beta1.CloneSet, pods []*v1.Pod) ([]*v1.Pod, []*v1.Pod, int) {
+func getPlannedDeletedPods(cs *appsv1beta1.CloneSet, pods []*v1.Pod) ([]*v1.Pod, []*v1.Pod, []*v1.Pod, int) {
 	var podsSpecifiedToDelete []*v1.Pod
 	var podsInPreDelete []*v1.Pod
+	var podsQuarantined []*v1.Pod
 	names := sets.NewString()
 	for _, pod := range pods {
 		if isSpecifiedDelete(cs, pod) {
@@ -321,7 +442,43 @@ func getPlannedDeletedPods(cs *appsv1beta1.CloneSet, pods []*v1.Pod) ([]*v1.Pod,
 			podsInPreDelete = append(podsInPreDelete, pod)
 		}
 	}
-	return podsSpecifiedToDelete, podsInPreDelete, names.Len()
+	// pods specified to delete or in preDelete are managed as above, even if they are quarantined
+	for _, pod := range pods {
+		if isPodQuarantined(pod) && !names.Has(pod.Name) {
+			podsQuarantined = append(podsQuarantined, pod)
+		}
+	}
+	return podsSpecifiedToDelete, podsInPreDelete, podsQuarantined, names.Len() + len(podsQuarantined)
+}
+
+// getQuarantineExpiredPods returns the quarantined pods that should be deleted now,
+// and the duration after which the next one expires.
+func getQuarantineExpiredPods(cs *appsv1beta1.CloneSet, podsQuarantined []*v1.Pod) ([]*v1.Pod, time.Duration) {
+	var podsExpired []*v1.Pod
+	var requeueDuration time.Duration
+	quarantineDuration := time.Duration(cs.Spec.ScaleStrategy.QuarantineSeconds) * time.Second
+	now := timer.Now()
+	for _, pod := range podsQuarantined {
+		quarantineTime, err := time.Parse(time.RFC3339, pod.Annotations[appsv1beta1.CloneSetPodQuarantineTimeKey])
+		if err != nil {
+			// the quarantine time is unknown, delete it as expired
+			podsExpired = append(podsExpired, pod)
+			continue
+		}
+		if left := quarantineTime.Add(quarantineDuration).Sub(now); left > 0 {
+			if requeueDuration == 0 || left < requeueDuration {
+				requeueDuration = left
+			}
+		} else {
+			podsExpired = append(podsExpired, pod)
+		}
+	}
+	return podsExpired, requeueDuration
+}
+
+func isPodQuarantined(pod *v1.Pod) bool {
+	_, ok := pod.Labels[appsv1beta1.CloneSetPodQuarantinedKey]
+	return ok
 }
 
 // Get available IDs, if the a PVC exists but the corresponding pod does not exist, then reusing the ID, i.e., reuse the pvc.
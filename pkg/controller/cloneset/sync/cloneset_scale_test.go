@@ -36,16 +36,22 @@ import (
 	"k8s.io/client-go/tools/record"
 	corev1 "k8s.io/kubernetes/pkg/apis/core/v1"
 	kubecontroller "k8s.io/kubernetes/pkg/controller"
+	"k8s.io/utils/clock"
+	testingclock "k8s.io/utils/clock/testing"
 	utilpointer "k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	appspub "github.com/openkruise/kruise/apis/apps/pub"
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	clonesetcore "github.com/openkruise/kruise/pkg/controller/cloneset/core"
 	clonesettest "github.com/openkruise/kruise/pkg/controller/cloneset/test"
 	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
 	"github.com/openkruise/kruise/pkg/util"
 	"github.com/openkruise/kruise/pkg/util/expectations"
+	"github.com/openkruise/kruise/pkg/util/lifecycle"
+	"github.com/openkruise/kruise/pkg/util/podadapter"
+	utilpodreadiness "github.com/openkruise/kruise/pkg/util/podreadiness"
 )
 
 var (
@@ -781,3 +787,378 @@ func generatePods(base *v1.Pod, replicas int) []*v1.Pod {
 	}
 	return objs
 }
+
+func TestScaleWithQuarantine(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	fakeClock := testingclock.NewFakeClock(now)
+	defer func(c clock.Clock) { timer = c }(timer)
+	timer = fakeClock
+
+	newCloneSet := func(replicas int32) *appsv1beta1.CloneSet {
+		return &appsv1beta1.CloneSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sample"},
+			Spec: appsv1beta1.CloneSetSpec{
+				Replicas: utilpointer.Int32(replicas),
+				ScaleStrategy: appsv1beta1.CloneSetScaleStrategy{
+					QuarantineSeconds: 60,
+				},
+			},
+		}
+	}
+	basePod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "sample",
+			Labels:    map[string]string{apps.ControllerRevisionHashLabelKey: "sample-b976d4544"},
+		},
+		Spec: v1.PodSpec{
+			Containers:     []v1.Container{{Name: "main", Image: "sample:v1"}},
+			ReadinessGates: []v1.PodReadinessGate{{ConditionType: appspub.KruisePodReadyConditionType}},
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodRunning,
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodReady, Status: v1.ConditionTrue, LastTransitionTime: metav1.Time{Time: now.Add(-time.Minute)}},
+				{Type: v1.ContainersReady, Status: v1.ConditionTrue},
+				{Type: appspub.KruisePodReadyConditionType, Status: v1.ConditionTrue},
+			},
+		},
+	}
+
+	newControl := func() (*realControl, client.Client) {
+		fClient := fake.NewClientBuilder().WithScheme(kscheme).Build()
+		for _, pod := range generatePods(basePod, 5) {
+			if err := fClient.Create(context.TODO(), pod); err != nil {
+				t.Fatalf("failed to create pod: %v", err)
+			}
+		}
+		return &realControl{
+			Client:              fClient,
+			recorder:            record.NewFakeRecorder(10),
+			podReadinessControl: utilpodreadiness.NewForAdapter(&podadapter.AdapterRuntimeClient{Client: fClient}),
+		}, fClient
+	}
+	listPods := func(c client.Client) (pods, quarantined []*v1.Pod) {
+		podList := &v1.PodList{}
+		if err := c.List(context.TODO(), podList); err != nil {
+			t.Fatalf("failed to list pods: %v", err)
+		}
+		for i := range podList.Items {
+			pod := &podList.Items[i]
+			pods = append(pods, pod)
+			if isPodQuarantined(pod) {
+				quarantined = append(quarantined, pod)
+			}
+		}
+		return
+	}
+	scale := func(ctrl *realControl, cs *appsv1beta1.CloneSet, pods []*v1.Pod) bool {
+		modified, err := ctrl.Scale(cs, cs, "sample-b976d4544", "sample-b976d4544", pods, nil)
+		if err != nil {
+			t.Fatalf("failed to scale: %v", err)
+		}
+		return modified
+	}
+
+	t.Run("quarantine and delete after expired", func(t *testing.T) {
+		ctrl, c := newControl()
+		pods, _ := listPods(c)
+		if !scale(ctrl, newCloneSet(3), pods) {
+			t.Fatalf("expected modified when quarantining pods")
+		}
+		pods, quarantined := listPods(c)
+		if len(pods) != 5 || len(quarantined) != 2 {
+			t.Fatalf("expected 2 of 5 pods quarantined, got %d of %d", len(quarantined), len(pods))
+		}
+		for _, pod := range quarantined {
+			if pod.Annotations[appsv1beta1.CloneSetPodQuarantineTimeKey] != now.Format(time.RFC3339) {
+				t.Fatalf("unexpected quarantine time of pod %s: %v", pod.Name, pod.Annotations)
+			}
+			if isPodReady(clonesetcore.New(newCloneSet(3)), pod) {
+				t.Fatalf("expected quarantined pod %s not ready", pod.Name)
+			}
+		}
+
+		// pods are kept until the quarantine expires
+		fakeClock.Step(30 * time.Second)
+		_ = clonesetutils.DurationStore.Pop("default/sample")
+		if scale(ctrl, newCloneSet(3), pods) {
+			t.Fatalf("expected not modified during quarantine")
+		}
+		if pods, _ = listPods(c); len(pods) != 5 {
+			t.Fatalf("expected 5 pods during quarantine, got %d", len(pods))
+		}
+		if d := clonesetutils.DurationStore.Pop("default/sample"); d != 30*time.Second {
+			t.Fatalf("expected requeue after 30s, got %v", d)
+		}
+
+		fakeClock.Step(30 * time.Second)
+		if !scale(ctrl, newCloneSet(3), pods) {
+			t.Fatalf("expected modified when deleting quarantined pods")
+		}
+		pods, quarantined = listPods(c)
+		if len(pods) != 3 || len(quarantined) != 0 {
+			t.Fatalf("expected 3 pods left without quarantined, got %d with %d quarantined", len(pods), len(quarantined))
+		}
+	})
+
+	t.Run("unquarantine when scaling out", func(t *testing.T) {
+		fakeClock.SetTime(now)
+		ctrl, c := newControl()
+		pods, _ := listPods(c)
+		scale(ctrl, newCloneSet(3), pods)
+		pods, quarantined := listPods(c)
+		if len(quarantined) != 2 {
+			t.Fatalf("expected 2 pods quarantined, got %d", len(quarantined))
+		}
+
+		if !scale(ctrl, newCloneSet(4), pods) {
+			t.Fatalf("expected modified when unquarantining pods")
+		}
+		pods, quarantined = listPods(c)
+		if len(pods) != 5 || len(quarantined) != 1 {
+			t.Fatalf("expected 1 of 5 pods quarantined, got %d of %d", len(quarantined), len(pods))
+		}
+		for _, pod := range pods {
+			if cond := utilpodreadiness.GetReadinessCondition(pod); !isPodQuarantined(pod) && cond.Status != v1.ConditionTrue {
+				t.Fatalf("expected readiness condition of unquarantined pod %s true, got %v", pod.Name, cond)
+			}
+		}
+
+		// the rest quarantined pod is recovered and no pod is created
+		if !scale(ctrl, newCloneSet(5), pods) {
+			t.Fatalf("expected modified when unquarantining pods")
+		}
+		pods, quarantined = listPods(c)
+		if len(pods) != 5 || len(quarantined) != 0 {
+			t.Fatalf("expected 5 pods without quarantined, got %d with %d quarantined", len(pods), len(quarantined))
+		}
+		_ = clonesetutils.DurationStore.Pop("default/sample")
+	})
+}
+
+func TestScaleWithDecommission(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	fakeClock := testingclock.NewFakeClock(now)
+	defer func(c clock.Clock) { timer = c }(timer)
+	timer = fakeClock
+
+	newCloneSet := func(decommission ...appsv1beta1.CloneSetDecommission) *appsv1beta1.CloneSet {
+		return &appsv1beta1.CloneSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sample"},
+			Spec: appsv1beta1.CloneSetSpec{
+				Replicas:      utilpointer.Int32(4),
+				ScaleStrategy: appsv1beta1.CloneSetScaleStrategy{Decommission: decommission},
+				Lifecycle: &appspub.Lifecycle{
+					PreDelete: &appspub.LifecycleHook{LabelsHandler: map[string]string{"hook": "true"}},
+				},
+			},
+		}
+	}
+	basePod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "default",
+			Name:       "sample",
+			Labels:     map[string]string{apps.ControllerRevisionHashLabelKey: "sample-b976d4544", "hook": "true"},
+			Finalizers: []string{appsv1beta1.CloneSetDecommissionFinalizer},
+		},
+		Spec: v1.PodSpec{
+			Containers:     []v1.Container{{Name: "main", Image: "sample:v1"}},
+			ReadinessGates: []v1.PodReadinessGate{{ConditionType: appspub.KruisePodReadyConditionType}},
+		},
+		Status: v1.PodStatus{
+			Phase: v1.PodRunning,
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodReady, Status: v1.ConditionTrue, LastTransitionTime: metav1.Time{Time: now.Add(-time.Minute)}},
+				{Type: v1.ContainersReady, Status: v1.ConditionTrue},
+				{Type: appspub.KruisePodReadyConditionType, Status: v1.ConditionTrue},
+			},
+		},
+	}
+
+	newControl := func() (*realControl, client.Client) {
+		fClient := fake.NewClientBuilder().WithScheme(kscheme).Build()
+		for _, pod := range generatePods(basePod, 5) {
+			if err := fClient.Create(context.TODO(), pod); err != nil {
+				t.Fatalf("failed to create pod: %v", err)
+			}
+		}
+		return &realControl{
+			Client:              fClient,
+			recorder:            record.NewFakeRecorder(10),
+			lifecycleControl:    lifecycle.New(fClient),
+			podReadinessControl: utilpodreadiness.NewForAdapter(&podadapter.AdapterRuntimeClient{Client: fClient}),
+		}, fClient
+	}
+	listPods := func(c client.Client) []*v1.Pod {
+		podList := &v1.PodList{}
+		if err := c.List(context.TODO(), podList); err != nil {
+			t.Fatalf("failed to list pods: %v", err)
+		}
+		var pods []*v1.Pod
+		for i := range podList.Items {
+			if podList.Items[i].DeletionTimestamp == nil {
+				pods = append(pods, &podList.Items[i])
+			}
+		}
+		return pods
+	}
+	getPod := func(c client.Client, name string) *v1.Pod {
+		pod := &v1.Pod{}
+		if err := c.Get(context.TODO(), client.ObjectKey{Namespace: "default", Name: name}, pod); err != nil {
+			t.Fatalf("failed to get pod %s: %v", name, err)
+		}
+		return pod
+	}
+	patchPod := func(c client.Client, name string, fn func(pod *v1.Pod)) {
+		pod := getPod(c, name)
+		fn(pod)
+		if err := c.Update(context.TODO(), pod); err != nil {
+			t.Fatalf("failed to update pod %s: %v", name, err)
+		}
+	}
+	// the statuses are calculated with the pods listed before scaling, the same as the controller
+	scale := func(ctrl *realControl, cs *appsv1beta1.CloneSet, c client.Client) (bool, []appsv1beta1.CloneSetDecommissionStatus) {
+		pods := listPods(c)
+		modified, err := ctrl.Scale(cs, cs, "sample-b976d4544", "sample-b976d4544", pods, nil)
+		if err != nil {
+			t.Fatalf("failed to scale: %v", err)
+		}
+		cs.Status.DecommissionStatuses = CalculateDecommissionStatuses(cs, pods)
+		return modified, cs.Status.DecommissionStatuses
+	}
+	expectPhase := func(statuses []appsv1beta1.CloneSetDecommissionStatus, phase appsv1beta1.CloneSetDecommissionPhase) {
+		t.Helper()
+		if len(statuses) != 1 || statuses[0].Phase != phase {
+			t.Fatalf("expected decommission phase %s, got %v", phase, statuses)
+		}
+	}
+
+	t.Run("decommission in sequence", func(t *testing.T) {
+		fakeClock.SetTime(now)
+		ctrl, c := newControl()
+		cs := newCloneSet(appsv1beta1.CloneSetDecommission{PodName: "sample-0", RequireFinalizerRemoval: true, TimeoutSeconds: utilpointer.Int32(60)})
+
+		if modified, statuses := scale(ctrl, cs, c); !modified {
+			t.Fatalf("expected modified when starting decommission")
+		} else {
+			expectPhase(statuses, appsv1beta1.CloneSetDecommissionPending)
+		}
+		pod := getPod(c, "sample-0")
+		if !isPodDecommissionStarted(pod) || pod.Annotations[appsv1beta1.CloneSetPodDecommissionTimeKey] != now.Format(time.RFC3339) {
+			t.Fatalf("expected pod decommission started, got %v %v", pod.Labels, pod.Annotations)
+		}
+		if cond := utilpodreadiness.GetReadinessCondition(pod); cond == nil || cond.Status != v1.ConditionFalse {
+			t.Fatalf("expected decommissioning pod not ready, got %v", cond)
+		}
+
+		// the decommissioning pod is excluded from replicas, so the others are not scaled in
+		if modified, statuses := scale(ctrl, cs, c); !modified {
+			t.Fatalf("expected modified when updating lifecycle to PreparingDelete")
+		} else {
+			expectPhase(statuses, appsv1beta1.CloneSetDecommissionDraining)
+		}
+		if state := lifecycle.GetPodLifecycleState(getPod(c, "sample-0")); state != appspub.LifecycleStatePreparingDelete {
+			t.Fatalf("expected pod PreparingDelete, got %s", state)
+		}
+		if modified, statuses := scale(ctrl, cs, c); modified {
+			t.Fatalf("expected not modified when waiting for PreDelete hook")
+		} else {
+			expectPhase(statuses, appsv1beta1.CloneSetDecommissionDraining)
+		}
+
+		// the time spent in the PreDelete hook is not counted in the timeout
+		fakeClock.Step(time.Minute)
+		patchPod(c, "sample-0", func(pod *v1.Pod) { delete(pod.Labels, "hook") })
+		_ = clonesetutils.DurationStore.Pop("default/sample")
+		if modified, statuses := scale(ctrl, cs, c); !modified {
+			t.Fatalf("expected modified when starting to wait for finalizer removal")
+		} else {
+			expectPhase(statuses, appsv1beta1.CloneSetDecommissionOffloading)
+		}
+		if value := getPod(c, "sample-0").Annotations[appsv1beta1.CloneSetPodDecommissionOffloadTimeKey]; value != fakeClock.Now().Format(time.RFC3339) {
+			t.Fatalf("expected offload time recorded, got %q", value)
+		}
+		if d := clonesetutils.DurationStore.Pop("default/sample"); d != time.Minute {
+			t.Fatalf("expected requeue after 1m, got %v", d)
+		}
+		fakeClock.Step(20 * time.Second)
+		if modified, statuses := scale(ctrl, cs, c); modified {
+			t.Fatalf("expected not modified when waiting for finalizer removal")
+		} else {
+			expectPhase(statuses, appsv1beta1.CloneSetDecommissionOffloading)
+		}
+		if d := clonesetutils.DurationStore.Pop("default/sample"); d != 40*time.Second {
+			t.Fatalf("expected requeue after 40s, got %v", d)
+		}
+
+		patchPod(c, "sample-0", func(pod *v1.Pod) { pod.Finalizers = nil })
+		if modified, statuses := scale(ctrl, cs, c); !modified {
+			t.Fatalf("expected modified when deleting pod")
+		} else {
+			expectPhase(statuses, appsv1beta1.CloneSetDecommissionDeleting)
+		}
+		pods := listPods(c)
+		if len(pods) != 4 {
+			t.Fatalf("expected 4 pods left, got %d", len(pods))
+		}
+		expectPhase(CalculateDecommissionStatuses(cs, pods), appsv1beta1.CloneSetDecommissionCompleted)
+	})
+
+	t.Run("timeout waiting for finalizer removal", func(t *testing.T) {
+		fakeClock.SetTime(now)
+		ctrl, c := newControl()
+		cs := newCloneSet(appsv1beta1.CloneSetDecommission{PodName: "sample-0", RequireFinalizerRemoval: true, TimeoutSeconds: utilpointer.Int32(60)})
+		patchPod(c, "sample-0", func(pod *v1.Pod) { delete(pod.Labels, "hook") })
+
+		scale(ctrl, cs, c)
+		scale(ctrl, cs, c)
+		fakeClock.Step(time.Minute)
+		if modified, statuses := scale(ctrl, cs, c); !modified {
+			t.Fatalf("expected modified when force deleting timed out pod")
+		} else {
+			expectPhase(statuses, appsv1beta1.CloneSetDecommissionTimeout)
+		}
+		pods := listPods(c)
+		if len(pods) != 4 {
+			t.Fatalf("expected timed out pod deleted, got %d pods", len(pods))
+		}
+		expectPhase(CalculateDecommissionStatuses(cs, pods), appsv1beta1.CloneSetDecommissionCompleted)
+		_ = clonesetutils.DurationStore.Pop("default/sample")
+	})
+
+	t.Run("cancel before PreparingDelete", func(t *testing.T) {
+		fakeClock.SetTime(now)
+		ctrl, c := newControl()
+		cs := newCloneSet(appsv1beta1.CloneSetDecommission{PodName: "sample-0"})
+		scale(ctrl, cs, c)
+
+		cs.Spec.ScaleStrategy.Decommission = nil
+		if modified, statuses := scale(ctrl, cs, c); !modified || statuses != nil {
+			t.Fatalf("expected modified without statuses when canceling decommission, got %v %v", modified, statuses)
+		}
+		pod := getPod(c, "sample-0")
+		if isPodDecommissionStarted(pod) {
+			t.Fatalf("expected decommission canceled, got %v", pod.Labels)
+		}
+		if cond := utilpodreadiness.GetReadinessCondition(pod); cond.Status != v1.ConditionTrue {
+			t.Fatalf("expected pod ready again, got %v", cond)
+		}
+	})
+
+	t.Run("pod disappeared during decommission", func(t *testing.T) {
+		fakeClock.SetTime(now)
+		ctrl, c := newControl()
+		cs := newCloneSet(appsv1beta1.CloneSetDecommission{PodName: "sample-0", RequireFinalizerRemoval: true}, appsv1beta1.CloneSetDecommission{PodName: "missing"})
+		scale(ctrl, cs, c)
+		_, statuses := scale(ctrl, cs, c)
+		if len(statuses) != 2 || statuses[1].Phase != appsv1beta1.CloneSetDecommissionAborted {
+			t.Fatalf("expected missing pod aborted, got %v", statuses)
+		}
+
+		statuses = CalculateDecommissionStatuses(cs, util.DiffPods(listPods(c), []*v1.Pod{getPod(c, "sample-0")}))
+		if statuses[0].Phase != appsv1beta1.CloneSetDecommissionAborted || statuses[0].StartTime == nil {
+			t.Fatalf("expected pod aborted with start time, got %v", statuses[0])
+		}
+	})
+}
@@ -131,6 +131,7 @@ func calculateDiffsWithExpectation(cs *appsv1beta1.CloneSet, pods []*v1.Pod, cur
 	var newRevisionCount, newRevisionActiveCount, oldRevisionCount, oldRevisionActiveCount int
 	var unavailableNewRevisionCount, unavailableOldRevisionCount int
 	var toDeleteNewRevisionCount, toDeleteOldRevisionCount, preDeletingNewRevisionCount, preDeletingOldRevisionCount int
+	var quarantinedNewRevisionCount, quarantinedOldRevisionCount int
 	defer func() {
 		if res.isEmpty() {
 			return
@@ -141,6 +142,7 @@ func calculateDiffsWithExpectation(cs *appsv1beta1.CloneSet, pods []*v1.Pod, cur
 			"unavailableNewRevisionCount", unavailableNewRevisionCount, "unavailableOldRevisionCount", unavailableOldRevisionCount,
 			"preDeletingNewRevisionCount", preDeletingNewRevisionCount, "preDeletingOldRevisionCount", preDeletingOldRevisionCount,
 			"toDeleteNewRevisionCount", toDeleteNewRevisionCount, "toDeleteOldRevisionCount", toDeleteOldRevisionCount,
+			"quarantinedNewRevisionCount", quarantinedNewRevisionCount, "quarantinedOldRevisionCount", quarantinedOldRevisionCount,
 			"enabledPreparingUpdateAsUpdate", utilfeature.DefaultFeatureGate.Enabled(features.PreparingUpdateAsUpdate), "useDefaultIsPodUpdate", isPodUpdate == nil,
 			"result", res)
 	}()
@@ -161,9 +163,11 @@ func calculateDiffsWithExpectation(cs *appsv1beta1.CloneSet, pods []*v1.Pod, cur
 
 			newRevisionCount++
 
-			switch state := lifecycle.GetPodLifecycleState(p); state {
-			case appspub.LifecycleStatePreparingDelete:
+			switch state := lifecycle.GetPodLifecycleState(p); {
+			case state == appspub.LifecycleStatePreparingDelete:
 				preDeletingNewRevisionCount++
+			case isPodQuarantined(p) && !isSpecifiedDelete(cs, p):
+				quarantinedNewRevisionCount++
 			default:
 				newRevisionActiveCount++
 
@@ -177,9 +181,11 @@ func calculateDiffsWithExpectation(cs *appsv1beta1.CloneSet, pods []*v1.Pod, cur
 		} else {
 			oldRevisionCount++
 
-			switch state := lifecycle.GetPodLifecycleState(p); state {
-			case appspub.LifecycleStatePreparingDelete:
+			switch state := lifecycle.GetPodLifecycleState(p); {
+			case state == appspub.LifecycleStatePreparingDelete:
 				preDeletingOldRevisionCount++
+			case isPodQuarantined(p) && !isSpecifiedDelete(cs, p):
+				quarantinedOldRevisionCount++
 			default:
 				oldRevisionActiveCount++
 
@@ -194,7 +200,8 @@ func calculateDiffsWithExpectation(cs *appsv1beta1.CloneSet, pods []*v1.Pod, cur
 
 	updateOldDiff := oldRevisionActiveCount - partition
 	updateNewDiff := newRevisionActiveCount - (replicas - partition)
-	totalUnavailable := preDeletingNewRevisionCount + preDeletingOldRevisionCount + unavailableNewRevisionCount + unavailableOldRevisionCount
+	totalUnavailable := preDeletingNewRevisionCount + preDeletingOldRevisionCount + unavailableNewRevisionCount + unavailableOldRevisionCount +
+		quarantinedNewRevisionCount + quarantinedOldRevisionCount
 	// If the currentRevision and updateRevision are consistent, Pods can only update to this revision
 	// If the CloneSetPartitionRollback is not enabled, Pods can only update to the new revision
 	if updateRevision == currentRevision || !utilfeature.DefaultFeatureGate.Enabled(features.CloneSetPartitionRollback) {
@@ -259,10 +266,12 @@ func calculateDiffsWithExpectation(cs *appsv1beta1.CloneSet, pods []*v1.Pod, cur
 	}
 
 	// scale down
-	// Note that this should exclude the number of Pods that are already specified to delete.
-	if num := currentTotalCount - toDeleteOldRevisionCount - toDeleteNewRevisionCount - expectedTotalCount; num > 0 {
+	// Note that this should exclude the number of Pods that are already specified to delete or quarantined.
+	toDeleteOldCount := toDeleteOldRevisionCount + quarantinedOldRevisionCount
+	toDeleteTotalCount := toDeleteOldCount + toDeleteNewRevisionCount + quarantinedNewRevisionCount
+	if num := currentTotalCount - toDeleteTotalCount - expectedTotalCount; num > 0 {
 		res.scaleDownNum = num
-		res.scaleDownNumOldRevision = integer.IntMax(currentTotalOldCount-toDeleteOldRevisionCount-expectedTotalOldCount, 0)
+		res.scaleDownNumOldRevision = integer.IntMax(currentTotalOldCount-toDeleteOldCount-expectedTotalOldCount, 0)
 	}
 	if toDeleteNewRevisionCount > 0 || toDeleteOldRevisionCount > 0 || res.scaleDownNum > 0 {
 		res.deleteReadyLimit = integer.IntMax(maxUnavailable+(len(pods)-replicas)-totalUnavailable, 0)
@@ -308,3 +317,17 @@ func IsPodAvailable(coreControl clonesetcore.Control, pod *v1.Pod, minReadySecon
 func shouldScalingExcludePreparingDelete(cs *appsv1beta1.CloneSet) bool {
 	return scalingExcludePreparingDelete || cs.Spec.ScaleStrategy.ExcludePreparingDelete
 }
+
+// CalculateSurgeReplicas returns the number of Pods that are above the desired replicas because of maxSurge.
+// It is bounded by the surge that the controller expects to use for current rollout, so it is 0 when no update
+// is in progress and Pods above the desired replicas are regarded as being scaled down.
+func CalculateSurgeReplicas(cs *appsv1beta1.CloneSet, pods []*v1.Pod, currentRevision, updateRevision string) int32 {
+	if cs.Spec.UpdateStrategy.RollingUpdate == nil || cs.Spec.UpdateStrategy.RollingUpdate.MaxSurge == nil {
+		return 0
+	}
+	diffRes := calculateDiffsWithExpectation(cs, pods, currentRevision, updateRevision, nil)
+	if diffRes.useSurge <= 0 {
+		return 0
+	}
+	return int32(integer.IntMin(integer.IntMax(len(pods)-int(*cs.Spec.Replicas), 0), diffRes.useSurge))
+}
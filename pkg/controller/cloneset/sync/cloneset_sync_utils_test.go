@@ -1030,3 +1030,80 @@ func setExcludePreparingDelete(cs *appsv1beta1.CloneSet, exclude bool) *appsv1be
 	cs.Spec.ScaleStrategy.ExcludePreparingDelete = exclude
 	return cs
 }
+
+func TestCalculateSurgeReplicas(t *testing.T) {
+	newPods := func(oldNum, newNum int) []*v1.Pod {
+		var pods []*v1.Pod
+		for i := 0; i < oldNum; i++ {
+			pods = append(pods, createTestPod("old_rev", appspub.LifecycleStateNormal, true, false))
+		}
+		for i := 0; i < newNum; i++ {
+			pods = append(pods, createTestPod("new_rev", appspub.LifecycleStateNormal, true, false))
+		}
+		return pods
+	}
+
+	cases := []struct {
+		name   string
+		set    *appsv1beta1.CloneSet
+		pods   []*v1.Pod
+		expect int32
+	}{
+		{
+			name:   "no maxSurge",
+			set:    &appsv1beta1.CloneSet{Spec: appsv1beta1.CloneSetSpec{Replicas: getInt32Pointer(5)}},
+			pods:   newPods(5, 1),
+			expect: 0,
+		},
+		{
+			name:   "rollout not started",
+			set:    createTestCloneSet(5, intstr.FromInt32(0), intstr.FromInt32(0), intstr.FromInt32(2)),
+			pods:   newPods(5, 0),
+			expect: 0,
+		},
+		{
+			name:   "surge pods partly created",
+			set:    createTestCloneSet(5, intstr.FromInt32(0), intstr.FromInt32(0), intstr.FromInt32(2)),
+			pods:   newPods(5, 1),
+			expect: 1,
+		},
+		{
+			name:   "surge pods all created",
+			set:    createTestCloneSet(5, intstr.FromInt32(0), intstr.FromInt32(0), intstr.FromInt32(2)),
+			pods:   newPods(5, 2),
+			expect: 2,
+		},
+		{
+			name:   "pods above maxSurge are scaling down",
+			set:    createTestCloneSet(5, intstr.FromInt32(0), intstr.FromInt32(0), intstr.FromInt32(2)),
+			pods:   newPods(0, 10),
+			expect: 0,
+		},
+		{
+			name:   "update completed",
+			set:    createTestCloneSet(5, intstr.FromInt32(0), intstr.FromInt32(0), intstr.FromInt32(2)),
+			pods:   newPods(0, 7),
+			expect: 0,
+		},
+		{
+			name:   "surge pods being deleted",
+			set:    createTestCloneSet(5, intstr.FromInt32(0), intstr.FromInt32(0), intstr.FromInt32(2)),
+			pods:   newPods(2, 2),
+			expect: 0,
+		},
+		{
+			name:   "percentage maxSurge",
+			set:    createTestCloneSet(10, intstr.FromInt32(0), intstr.FromInt32(0), intstr.FromString("20%")),
+			pods:   newPods(10, 3),
+			expect: 2,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := CalculateSurgeReplicas(tc.set, tc.pods, "old_rev", "new_rev"); got != tc.expect {
+				t.Errorf("expect %d, got %d", tc.expect, got)
+			}
+		})
+	}
+}
@@ -73,6 +73,8 @@ func (c *realControl) Update(cs *appsv1beta1.CloneSet,
 	if modified {
 		return nil
 	}
+	// pods to decommission will be deleted, no need to update them
+	pods = excludeDecommissioningPods(cs, pods)
 
 	if cs.Spec.UpdateStrategy.Type == appsv1beta1.OnDeleteCloneSetUpdateStrategyType {
 		klog.V(3).InfoS("CloneSet UpdateStrategy is OnDelete", "cloneSet", klog.KObj(cs))
@@ -96,7 +98,7 @@ func (c *realControl) Update(cs *appsv1beta1.CloneSet,
 	}
 	var waitUpdateIndexes []int
 	for i, pod := range pods {
-		if coreControl.IsPodUpdatePaused(pod) {
+		if coreControl.IsPodUpdatePaused(pod) || isPodQuarantined(pod) {
 			continue
 		}
 
@@ -256,8 +258,7 @@ func (c *realControl) updatePod(cs *appsv1beta1.CloneSet, coreControl clonesetco
 	pod *v1.Pod, pvcs []*v1.PersistentVolumeClaim,
 ) (time.Duration, error) {
 
-	if cs.Spec.UpdateStrategy.RollingUpdate != nil && (cs.Spec.UpdateStrategy.RollingUpdate.PodUpdatePolicy == appsv1beta1.InPlaceIfPossibleCloneSetPodUpdateStrategyType ||
-		cs.Spec.UpdateStrategy.RollingUpdate.PodUpdatePolicy == appsv1beta1.InPlaceOnlyCloneSetPodUpdateStrategyType) {
+	if clonesetutils.IsInPlaceUpdatePolicy(cs) {
 		var oldRevision *apps.ControllerRevision
 		for _, r := range revisions {
 			if clonesetutils.EqualToRevisionHash("", pod, r.Name) {
@@ -265,7 +266,19 @@ func (c *realControl) updatePod(cs *appsv1beta1.CloneSet, coreControl clonesetco
 				break
 			}
 		}
-		if c.inplaceControl.CanUpdateInPlace(oldRevision, updateRevision, coreControl.GetUpdateOptions()) {
+		canUpdateInPlace := c.inplaceControl.CanUpdateInPlace(oldRevision, updateRevision, coreControl.GetUpdateOptions())
+		// the kubelet version only matters if the pod could be updated in-place
+		var kubeletSkewed bool
+		if canUpdateInPlace && lifecycle.GetPodLifecycleState(pod) != appspub.LifecycleStateUpdating {
+			var err error
+			if kubeletSkewed, err = clonesetutils.IsPodOnKubeletVersionSkewedNode(c.Client, cs, pod); err != nil {
+				return 0, err
+			}
+		}
+		if kubeletSkewed {
+			c.recorder.Eventf(cs, v1.EventTypeWarning, "KubeletVersionSkewed",
+				"can not update pod %s in-place because kubelet of node %s is older than the minimum version", pod.Name, pod.Spec.NodeName)
+		} else if canUpdateInPlace {
 			switch state := lifecycle.GetPodLifecycleState(pod); state {
 			case "", appspub.LifecycleStatePreparingNormal, appspub.LifecycleStateNormal:
 				var err error
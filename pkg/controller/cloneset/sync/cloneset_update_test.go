@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -48,6 +49,8 @@ import (
 	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
 	"github.com/openkruise/kruise/pkg/util/inplaceupdate"
 	"github.com/openkruise/kruise/pkg/util/lifecycle"
+	"github.com/openkruise/kruise/pkg/util/podadapter"
+	utilpodreadiness "github.com/openkruise/kruise/pkg/util/podreadiness"
 )
 
 type manageCase struct {
@@ -1078,6 +1081,7 @@ func TestUpdate(t *testing.T) {
 				inplaceupdate.New(fakeClient, clonesetutils.RevisionAdapterImpl),
 				record.NewFakeRecorder(10),
 				&controllerfinder.ControllerFinder{Client: fakeClient},
+				utilpodreadiness.NewForAdapter(&podadapter.AdapterRuntimeClient{Client: fakeClient}),
 			}
 			currentRevision := mc.updateRevision
 			if len(mc.revisions) > 0 {
@@ -1437,3 +1441,177 @@ func TestCalculateUpdateCount(t *testing.T) {
 		}
 	}
 }
+
+func TestUpdatePodWithKubeletVersionSkew(t *testing.T) {
+	utilruntime.Must(apis.AddToScheme(scheme.Scheme))
+	updateRevision := &apps.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "rev_new"},
+		Data:       runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"$patch":"replace","spec":{"containers":[{"name":"c1","image":"foo2"}]}}}}`)},
+	}
+	recreateRevision := &apps.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: "rev_new"},
+		Data:       runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"$patch":"replace","spec":{"containers":[{"name":"c1","image":"foo1","command":["sleep"]}]}}}}`)},
+	}
+	revisions := []*apps.ControllerRevision{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "rev_old"},
+			Data:       runtime.RawExtension{Raw: []byte(`{"spec":{"template":{"$patch":"replace","spec":{"containers":[{"name":"c1","image":"foo1"}]}}}}`)},
+		},
+	}
+	newNode := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-new"}, Status: v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{KubeletVersion: "v1.28.3"}}}
+	oldNode := &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-old"}, Status: v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{KubeletVersion: "v1.20.15"}}}
+	newPod := func(name, nodeName string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{
+				apps.ControllerRevisionHashLabelKey:  "rev_old",
+				apps.DefaultDeploymentUniqueLabelKey: "rev_old",
+			}},
+			Spec: v1.PodSpec{
+				NodeName:       nodeName,
+				ReadinessGates: []v1.PodReadinessGate{{ConditionType: appspub.InPlaceUpdateReady}},
+				Containers:     []v1.Container{{Name: "c1", Image: "foo1"}},
+			},
+			Status: v1.PodStatus{
+				Phase: v1.PodRunning,
+				Conditions: []v1.PodCondition{
+					{Type: v1.PodReady, Status: v1.ConditionTrue},
+					{Type: appspub.InPlaceUpdateReady, Status: v1.ConditionTrue},
+				},
+				ContainerStatuses: []v1.ContainerStatus{{Name: "c1", ImageID: "image-id-xyz"}},
+			},
+		}
+	}
+
+	cases := []struct {
+		name           string
+		defaultVersion string
+		minVersion     string
+		policy         appsv1beta1.CloneSetPodUpdateStrategyType
+		nodeName       string
+		notInPlaceable bool
+		expectInPlace  bool
+		expectRecreate bool
+		expectErr      bool
+		expectWarning  bool
+	}{
+		{
+			name:          "no minimum version",
+			policy:        appsv1beta1.InPlaceIfPossibleCloneSetPodUpdateStrategyType,
+			nodeName:      "node-old",
+			expectInPlace: true,
+		},
+		{
+			name:           "pod on new node with flag",
+			defaultVersion: "v1.25.0",
+			policy:         appsv1beta1.InPlaceIfPossibleCloneSetPodUpdateStrategyType,
+			nodeName:       "node-new",
+			expectInPlace:  true,
+		},
+		{
+			name:           "pod on old node with flag",
+			defaultVersion: "v1.25.0",
+			policy:         appsv1beta1.InPlaceIfPossibleCloneSetPodUpdateStrategyType,
+			nodeName:       "node-old",
+			expectRecreate: true,
+			expectWarning:  true,
+		},
+		{
+			name:           "cloneset overrides flag",
+			defaultVersion: "v1.25.0",
+			minVersion:     "v1.20.0",
+			policy:         appsv1beta1.InPlaceIfPossibleCloneSetPodUpdateStrategyType,
+			nodeName:       "node-old",
+			expectInPlace:  true,
+		},
+		{
+			name:           "pod on new node below cloneset minimum",
+			minVersion:     "v1.30.0",
+			policy:         appsv1beta1.InPlaceIfPossibleCloneSetPodUpdateStrategyType,
+			nodeName:       "node-new",
+			expectRecreate: true,
+			expectWarning:  true,
+		},
+		{
+			name:          "pod on old node with InPlaceOnly",
+			minVersion:    "v1.25.0",
+			policy:        appsv1beta1.InPlaceOnlyCloneSetPodUpdateStrategyType,
+			nodeName:      "node-old",
+			expectErr:     true,
+			expectWarning: true,
+		},
+		{
+			name:           "pod on old node with ReCreate",
+			minVersion:     "v1.25.0",
+			policy:         appsv1beta1.RecreateCloneSetPodUpdateStrategyType,
+			nodeName:       "node-old",
+			expectRecreate: true,
+		},
+		{
+			name:           "pod on old node with changes not in-place-able",
+			minVersion:     "v1.25.0",
+			policy:         appsv1beta1.InPlaceIfPossibleCloneSetPodUpdateStrategyType,
+			nodeName:       "node-old",
+			notInPlaceable: true,
+			expectRecreate: true,
+		},
+	}
+
+	inplaceupdate.Clock = testingclock.NewFakeClock(time.Now())
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			clonesetutils.DefaultMinKubeletVersionForInPlaceUpdate = tc.defaultVersion
+			defer func() { clonesetutils.DefaultMinKubeletVersionForInPlaceUpdate = "" }()
+
+			cs := &appsv1beta1.CloneSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "clone-test"},
+				Spec: appsv1beta1.CloneSetSpec{
+					Replicas: getInt32Pointer(1),
+					UpdateStrategy: appsv1beta1.CloneSetUpdateStrategy{
+						Type:                              appsv1beta1.RollingUpdateCloneSetUpdateStrategyType,
+						RollingUpdate:                     &appsv1beta1.RollingUpdateCloneSetStrategy{PodUpdatePolicy: tc.policy},
+						MinKubeletVersionForInPlaceUpdate: tc.minVersion,
+					},
+				},
+			}
+			pod := newPod("pod-0", tc.nodeName)
+			fakeClient := fake.NewClientBuilder().WithObjects(cs, pod, newNode, oldNode).Build()
+			recorder := record.NewFakeRecorder(10)
+			ctrl := &realControl{
+				fakeClient,
+				lifecycle.New(fakeClient),
+				inplaceupdate.New(fakeClient, clonesetutils.RevisionAdapterImpl),
+				recorder,
+				&controllerfinder.ControllerFinder{Client: fakeClient},
+				utilpodreadiness.NewForAdapter(&podadapter.AdapterRuntimeClient{Client: fakeClient}),
+			}
+			targetRevision := updateRevision
+			if tc.notInPlaceable {
+				targetRevision = recreateRevision
+			}
+			_, err := ctrl.updatePod(cs, clonesetcore.New(cs), targetRevision, revisions, pod, nil)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("expected error %v, got %v", tc.expectErr, err)
+			}
+
+			gotPod := &v1.Pod{}
+			if err := fakeClient.Get(context.TODO(), types.NamespacedName{Name: pod.Name}, gotPod); err != nil {
+				t.Fatalf("failed to get pod: %v", err)
+			}
+			if inPlace := gotPod.Spec.Containers[0].Image == "foo2"; inPlace != tc.expectInPlace {
+				t.Fatalf("expected in-place updated %v, got image %s", tc.expectInPlace, gotPod.Spec.Containers[0].Image)
+			}
+			if recreate := gotPod.Labels[appsv1beta1.SpecifiedDeleteKey] == "true"; recreate != tc.expectRecreate {
+				t.Fatalf("expected recreate %v, got labels %v", tc.expectRecreate, gotPod.Labels)
+			}
+			var warned bool
+			for len(recorder.Events) > 0 {
+				if strings.Contains(<-recorder.Events, "KubeletVersionSkewed") {
+					warned = true
+				}
+			}
+			if warned != tc.expectWarning {
+				t.Fatalf("expected KubeletVersionSkewed warning %v, got %v", tc.expectWarning, warned)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+
+	apps "k8s.io/api/apps/v1"
+	"k8s.io/utils/lru"
+
+	"github.com/openkruise/kruise/pkg/util/inplaceupdate"
+)
+
+const (
+	// MaxRecreateReasons is the number of field paths reported in status.recreateReasons.
+	MaxRecreateReasons = 3
+
+	recreateReasonsCacheSize = 1024
+)
+
+// recreateReasonsCache caches the recreate reasons of revision pairs, because the diff of revisions
+// is expensive to be calculated in every reconcile.
+var recreateReasonsCache = lru.New(recreateReasonsCacheSize)
+
+// GetRecreateReasons returns the first field paths changed from oldRevision to newRevision which can not be
+// updated in-place. The result is calculated once for each revision pair, and calculated is true only if
+// the result is not from cache.
+func GetRecreateReasons(oldRevision, newRevision *apps.ControllerRevision, opts *inplaceupdate.UpdateOptions) (reasons []string, calculated bool) {
+	opts = inplaceupdate.SetOptionsDefaults(opts)
+	key := fmt.Sprintf("%s/%s/%s/%s/%t", newRevision.Namespace, oldRevision.Name, newRevision.Name, newRevision.UID,
+		opts.IgnoreVolumeClaimTemplatesHashDiff)
+	if value, ok := recreateReasonsCache.Get(key); ok {
+		return value.([]string), false
+	}
+	reasons = inplaceupdate.GetNotInPlaceUpdatablePaths(oldRevision, newRevision, opts, MaxRecreateReasons)
+	recreateReasonsCache.Add(key, reasons)
+	return reasons, true
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestGetRecreateReasons(t *testing.T) {
+	newRevision := func(name, data string) *apps.ControllerRevision {
+		return &apps.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID("uid-" + name)},
+			Data:       runtime.RawExtension{Raw: []byte(data)},
+		}
+	}
+	oldRevision := newRevision("cs-old", `{"spec":{"template":{"$patch":"replace","spec":{"containers":[{"name":"main","image":"nginx:1.0"}]}}}}`)
+	imageRevision := newRevision("cs-image", `{"spec":{"template":{"$patch":"replace","spec":{"containers":[{"name":"main","image":"nginx:2.0"}]}}}}`)
+	envRevision := newRevision("cs-env", `{"spec":{"template":{"$patch":"replace","spec":{"containers":[{"name":"main","image":"nginx:2.0","env":[{"name":"A","value":"a"}],"command":["run"]}],"hostNetwork":true,"nodeName":"node-1"}}}}`)
+
+	reasons, calculated := GetRecreateReasons(oldRevision, imageRevision, nil)
+	if len(reasons) != 0 || !calculated {
+		t.Fatalf("expected no reasons calculated, got %v, %v", reasons, calculated)
+	}
+
+	expected := []string{"/spec/containers/0/command", "/spec/containers/0/env", "/spec/hostNetwork"}
+	reasons, calculated = GetRecreateReasons(oldRevision, envRevision, nil)
+	if !reflect.DeepEqual(reasons, expected) || !calculated {
+		t.Fatalf("expected reasons %v calculated, got %v, %v", expected, reasons, calculated)
+	}
+	// the reasons of the same revision pair are cached
+	reasons, calculated = GetRecreateReasons(oldRevision, envRevision, nil)
+	if !reflect.DeepEqual(reasons, expected) || calculated {
+		t.Fatalf("expected reasons %v from cache, got %v, %v", expected, reasons, calculated)
+	}
+	// another revision pair is calculated again
+	if _, calculated = GetRecreateReasons(imageRevision, envRevision, nil); !calculated {
+		t.Fatalf("expected reasons of a new revision pair calculated")
+	}
+}
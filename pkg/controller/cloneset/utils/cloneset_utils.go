@@ -26,8 +26,10 @@ import (
 
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	kubecontroller "k8s.io/kubernetes/pkg/controller"
 	"k8s.io/utils/integer"
@@ -55,6 +57,10 @@ var (
 
 	// DurationStore is a short cut for any sub-functions to notify the reconcile how long to wait to requeue
 	DurationStore = requeueduration.DurationStore{}
+
+	// DefaultMinKubeletVersionForInPlaceUpdate is the minimum kubelet version of nodes that Pods on them can be updated in-place,
+	// which can be overridden by spec.updateStrategy.minKubeletVersionForInPlaceUpdate of each CloneSet.
+	DefaultMinKubeletVersionForInPlaceUpdate string
 )
 
 type revisionAdapterImpl struct {
@@ -158,6 +164,43 @@ func GroupUpdateAndNotUpdatePods(pods []*v1.Pod, updateRevision string) (update,
 	return
 }
 
+// IsInPlaceUpdatePolicy returns true if the CloneSet updates pods in-place, i.e., with InPlaceIfPossible or InPlaceOnly.
+func IsInPlaceUpdatePolicy(cs *appsv1beta1.CloneSet) bool {
+	return cs.Spec.UpdateStrategy.RollingUpdate != nil &&
+		(cs.Spec.UpdateStrategy.RollingUpdate.PodUpdatePolicy == appsv1beta1.InPlaceIfPossibleCloneSetPodUpdateStrategyType ||
+			cs.Spec.UpdateStrategy.RollingUpdate.PodUpdatePolicy == appsv1beta1.InPlaceOnlyCloneSetPodUpdateStrategyType)
+}
+
+// IsPodOnKubeletVersionSkewedNode returns true if the Pod is located on a node whose kubelet is older than
+// the minimum version for in-place update of the CloneSet.
+func IsPodOnKubeletVersionSkewedNode(reader client.Reader, cs *appsv1beta1.CloneSet, pod *v1.Pod) (bool, error) {
+	minVersionStr := cs.Spec.UpdateStrategy.MinKubeletVersionForInPlaceUpdate
+	if minVersionStr == "" {
+		minVersionStr = DefaultMinKubeletVersionForInPlaceUpdate
+	}
+	if minVersionStr == "" || pod.Spec.NodeName == "" {
+		return false, nil
+	}
+	minVersion, err := utilversion.ParseGeneric(minVersionStr)
+	if err != nil {
+		return false, fmt.Errorf("invalid minimum kubelet version %q for in-place update: %v", minVersionStr, err)
+	}
+
+	node := &v1.Node{}
+	if err := reader.Get(context.TODO(), types.NamespacedName{Name: pod.Spec.NodeName}, node); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	kubeletVersion, err := utilversion.ParseGeneric(node.Status.NodeInfo.KubeletVersion)
+	if err != nil {
+		// the kubelet version is unknown, so we can not ensure it supports in-place update
+		return true, nil
+	}
+	return kubeletVersion.LessThan(minVersion), nil
+}
+
 // UpdateStorage insert volumes generated by cs.Spec.VolumeClaimTemplates into Pod.
 func UpdateStorage(cs *appsv1beta1.CloneSet, pod *v1.Pod) {
 	currentVolumes := pod.Spec.Volumes
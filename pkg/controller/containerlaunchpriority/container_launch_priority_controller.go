@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strconv"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
@@ -47,6 +48,12 @@ import (
 
 const (
 	concurrentReconciles = 4
+
+	// releasedAtKeyPrefix is the prefix of the barrier annotations recording when each priority was released.
+	releasedAtKeyPrefix = "apps.kruise.io/container-launch-priority-released-"
+
+	// EventForceReleasedPriority is the reason of the event when a priority is released after the timeout.
+	EventForceReleasedPriority = "ForceReleasedLaunchPriority"
 )
 
 func Add(mgr manager.Manager) error {
@@ -114,6 +121,10 @@ func shouldEnqueue(pod *v1.Pod, r client.Reader) bool {
 	if err := r.Get(context.TODO(), barrierNamespacedName, barrier); err != nil {
 		return true
 	}
+	if getLaunchPriorityTimeout(pod) > 0 {
+		// the priorities might be released before the higher ones ready, so enqueue until all of them released
+		return !isExistsInBarrier(nextPriorities[0], barrier)
+	}
 	return !isExistsInBarrier(nextPriorities[len(nextPriorities)-1], barrier)
 }
 
@@ -179,7 +190,12 @@ func (r *ReconcileContainerLaunchPriority) Reconcile(_ context.Context, request
 		return reconcile.Result{}, err
 	}
 
-	return reconcile.Result{}, nil
+	// Advanced: release the next priority if the containers released are not ready within the timeout
+	requeueAfter, err := r.releaseTimedOutPriority(pod, barrier)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *ReconcileContainerLaunchPriority) handle(pod *v1.Pod, barrier *v1.ConfigMap) error {
@@ -204,9 +220,48 @@ func (r *ReconcileContainerLaunchPriority) handle(pod *v1.Pod, barrier *v1.Confi
 	return nil
 }
 
+// releaseTimedOutPriority releases the highest priority not in barrier yet, if the containers of the lowest priority
+// released are still not ready after the timeout in pod annotation. It returns the time to check again.
+func (r *ReconcileContainerLaunchPriority) releaseTimedOutPriority(pod *v1.Pod, barrier *v1.ConfigMap) (time.Duration, error) {
+	timeout := getLaunchPriorityTimeout(pod)
+	if timeout <= 0 {
+		return 0, nil
+	}
+
+	// The priorities are released from the highest one, so the released ones are on the top of the not ready ones.
+	nextPriorities := findNextPriorities(pod)
+	released, pending := -1, -1
+	for i := len(nextPriorities) - 1; i >= 0; i-- {
+		if !isExistsInBarrier(nextPriorities[i], barrier) {
+			pending = i
+			break
+		}
+		released = i
+	}
+	if pending < 0 || released < 0 {
+		return 0, nil
+	}
+
+	releasedPriority, pendingPriority := nextPriorities[released], nextPriorities[pending]
+	if elapsed := time.Since(getReleasedTime(barrier, releasedPriority)); elapsed < timeout {
+		return timeout - elapsed, nil
+	}
+	if err := r.addPriorityIntoBarrier(barrier, pendingPriority); err != nil {
+		return 0, err
+	}
+	r.recorder.Eventf(pod, v1.EventTypeWarning, EventForceReleasedPriority,
+		"Force released containers of priority %d, as containers of priority %d were not ready within %v", pendingPriority, releasedPriority, timeout)
+	if pendingPriority == nextPriorities[0] {
+		return 0, r.patchCompleted(pod)
+	}
+	return timeout, nil
+}
+
 func (r *ReconcileContainerLaunchPriority) addPriorityIntoBarrier(barrier *v1.ConfigMap, priority int) error {
 	klog.V(3).InfoS("Adding priority into barrier", "priority", priority, "barrier", klog.KObj(barrier))
-	body := fmt.Sprintf(`{"data":{"%s":"true"}}`, utilcontainerlaunchpriority.GetKey(priority))
+	key := utilcontainerlaunchpriority.GetKey(priority)
+	body := fmt.Sprintf(`{"metadata":{"annotations":{"%s":"%s"}},"data":{"%s":"true"}}`,
+		releasedAtKeyPrefix+key, time.Now().Format(time.RFC3339), key)
 	return r.Client.Patch(context.TODO(), barrier, client.RawPatch(types.StrategicMergePatchType, []byte(body)))
 }
 
@@ -216,14 +271,28 @@ func (r *ReconcileContainerLaunchPriority) patchCompleted(pod *v1.Pod) error {
 	return r.Client.Patch(context.TODO(), pod, client.RawPatch(types.StrategicMergePatchType, []byte(body)))
 }
 
+// findNextPriorities returns the priorities of the containers not ready yet in ascending order, including the
+// restartable init containers, which are ordered together with the containers.
 func findNextPriorities(pod *v1.Pod) (priorities []int) {
 	containerReadySet := sets.NewString()
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.Ready {
+			containerReadySet.Insert(status.Name)
+		}
+	}
 	for _, status := range pod.Status.ContainerStatuses {
 		if status.Ready {
 			containerReadySet.Insert(status.Name)
 		}
 	}
-	for _, c := range pod.Spec.Containers {
+	var containers []v1.Container
+	for _, c := range pod.Spec.InitContainers {
+		if util.IsRestartableInitContainer(&c) {
+			containers = append(containers, c)
+		}
+	}
+	containers = append(containers, pod.Spec.Containers...)
+	for _, c := range containers {
 		if containerReadySet.Has(c.Name) {
 			continue
 		}
@@ -240,6 +309,31 @@ func findNextPriorities(pod *v1.Pod) (priorities []int) {
 	return
 }
 
+// getLaunchPriorityTimeout returns the timeout in pod annotation, or 0 if it is absent or invalid.
+func getLaunchPriorityTimeout(pod *v1.Pod) time.Duration {
+	value, ok := pod.Annotations[appspub.ContainerLaunchPriorityTimeoutSecondsKey]
+	if !ok {
+		return 0
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		klog.InfoS("Ignored invalid container launch priority timeout", "pod", klog.KObj(pod), "timeout", value)
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// getReleasedTime returns when the priority was released, or when the barrier was created if it was released
+// before the time recorded.
+func getReleasedTime(barrier *v1.ConfigMap, priority int) time.Time {
+	if value, ok := barrier.Annotations[releasedAtKeyPrefix+utilcontainerlaunchpriority.GetKey(priority)]; ok {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t
+		}
+	}
+	return barrier.CreationTimestamp.Time
+}
+
 func isExistsInBarrier(priority int, barrier *v1.ConfigMap) bool {
 	_, exists := barrier.Data[utilcontainerlaunchpriority.GetKey(priority)]
 	return exists
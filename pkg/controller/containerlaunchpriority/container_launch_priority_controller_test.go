@@ -21,11 +21,13 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -148,6 +150,7 @@ func TestReconcile(t *testing.T) {
 
 func TestFindNextPriorities(t *testing.T) {
 	podName := "fake"
+	always := v1.ContainerRestartPolicyAlways
 	cases := []struct {
 		pod      *v1.Pod
 		expected []int
@@ -187,6 +190,32 @@ func TestFindNextPriorities(t *testing.T) {
 			},
 			expected: []int{-1, 0, 3},
 		},
+		{
+			pod: &v1.Pod{
+				Spec: v1.PodSpec{
+					InitContainers: []v1.Container{
+						{Name: "init", Env: []v1.EnvVar{utilcontainerlaunchpriority.GeneratePriorityEnv(9, podName)}},
+						{Name: "sidecar-1", RestartPolicy: &always, Env: []v1.EnvVar{utilcontainerlaunchpriority.GeneratePriorityEnv(6, podName)}},
+						{Name: "sidecar-2", RestartPolicy: &always, Env: []v1.EnvVar{utilcontainerlaunchpriority.GeneratePriorityEnv(5, podName)}},
+					},
+					Containers: []v1.Container{
+						{Name: "a", Env: []v1.EnvVar{utilcontainerlaunchpriority.GeneratePriorityEnv(3, podName)}},
+						{Name: "b", Env: []v1.EnvVar{utilcontainerlaunchpriority.GeneratePriorityEnv(0, podName)}},
+					},
+				},
+				Status: v1.PodStatus{
+					InitContainerStatuses: []v1.ContainerStatus{
+						{Name: "init", Ready: false},
+						{Name: "sidecar-1", Ready: true},
+						{Name: "sidecar-2", Ready: false},
+					},
+					ContainerStatuses: []v1.ContainerStatus{
+						{Name: "a", Ready: false},
+					},
+				},
+			},
+			expected: []int{0, 3, 5},
+		},
 	}
 
 	for i, tc := range cases {
@@ -521,3 +550,140 @@ func TestShouldEnqueue(t *testing.T) {
 		})
 	}
 }
+
+func TestReleaseTimedOutPriority(t *testing.T) {
+	namespace := "default"
+	podName := "fake-pod"
+	configMapName := "fake-pod-barrier"
+	newPod := func(timeout string) *v1.Pod {
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: podName},
+			Spec: v1.PodSpec{Containers: []v1.Container{
+				{Name: "a", Env: []v1.EnvVar{utilcontainerlaunchpriority.GeneratePriorityEnv(5, podName)}},
+				{Name: "b", Env: []v1.EnvVar{utilcontainerlaunchpriority.GeneratePriorityEnv(3, podName)}},
+				{Name: "c", Env: []v1.EnvVar{utilcontainerlaunchpriority.GeneratePriorityEnv(0, podName)}},
+			}},
+			Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{
+				{Name: "a", Ready: false},
+				{Name: "b", Ready: false},
+				{Name: "c", Ready: false},
+			}},
+		}
+		if timeout != "" {
+			pod.Annotations = map[string]string{appspub.ContainerLaunchPriorityTimeoutSecondsKey: timeout}
+		}
+		return pod
+	}
+	releasedAt := func(ago time.Duration) string {
+		return time.Now().Add(-ago).Format(time.RFC3339)
+	}
+
+	cases := []struct {
+		name               string
+		pod                *v1.Pod
+		releasedAgo        map[int]time.Duration
+		expectedPriorities []int
+		expectedRequeue    bool
+		expectedCompleted  bool
+		expectedEvent      bool
+		expectedEnqueue    bool
+	}{
+		{
+			name:               "no timeout",
+			pod:                newPod(""),
+			releasedAgo:        map[int]time.Duration{5: time.Hour},
+			expectedPriorities: []int{5},
+		},
+		{
+			name:               "invalid timeout",
+			pod:                newPod("never"),
+			releasedAgo:        map[int]time.Duration{5: time.Hour},
+			expectedPriorities: []int{5},
+		},
+		{
+			name:               "not timed out",
+			pod:                newPod("60"),
+			releasedAgo:        map[int]time.Duration{5: 30 * time.Second},
+			expectedPriorities: []int{5},
+			expectedRequeue:    true,
+			expectedEnqueue:    true,
+		},
+		{
+			name:               "timed out",
+			pod:                newPod("60"),
+			releasedAgo:        map[int]time.Duration{5: 2 * time.Minute},
+			expectedPriorities: []int{5, 3},
+			expectedRequeue:    true,
+			expectedEvent:      true,
+			expectedEnqueue:    true,
+		},
+		{
+			name:               "forced release just now",
+			pod:                newPod("60"),
+			releasedAgo:        map[int]time.Duration{5: 3 * time.Minute, 3: time.Second},
+			expectedPriorities: []int{5, 3},
+			expectedRequeue:    true,
+			expectedEnqueue:    true,
+		},
+		{
+			name:               "timed out for the lowest priority",
+			pod:                newPod("60"),
+			releasedAgo:        map[int]time.Duration{5: 3 * time.Minute, 3: 2 * time.Minute},
+			expectedPriorities: []int{5, 3, 0},
+			expectedCompleted:  true,
+			expectedEvent:      true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			barrier := &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: configMapName, Annotations: map[string]string{}},
+				Data:       map[string]string{},
+			}
+			for priority, ago := range tc.releasedAgo {
+				key := utilcontainerlaunchpriority.GetKey(priority)
+				barrier.Data[key] = "true"
+				barrier.Annotations[releasedAtKeyPrefix+key] = releasedAt(ago)
+			}
+			cli := fake.NewClientBuilder().WithObjects(tc.pod, barrier).Build()
+			recorder := record.NewFakeRecorder(10)
+			r := &ReconcileContainerLaunchPriority{Client: cli, recorder: recorder}
+
+			res, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: podName}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (res.RequeueAfter > 0) != tc.expectedRequeue {
+				t.Fatalf("expected requeue %v, got %v", tc.expectedRequeue, res.RequeueAfter)
+			}
+
+			if err = cli.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: configMapName}, barrier); err != nil {
+				t.Fatal(err)
+			}
+			if len(barrier.Data) != len(tc.expectedPriorities) {
+				t.Fatalf("expected %v, got %v", tc.expectedPriorities, barrier.Data)
+			}
+			for _, priority := range tc.expectedPriorities {
+				key := utilcontainerlaunchpriority.GetKey(priority)
+				if barrier.Data[key] != "true" || barrier.Annotations[releasedAtKeyPrefix+key] == "" {
+					t.Fatalf("expected %v released, got %v %v", tc.expectedPriorities, barrier.Data, barrier.Annotations)
+				}
+			}
+
+			gotPod := &v1.Pod{}
+			if err = cli.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: podName}, gotPod); err != nil {
+				t.Fatal(err)
+			}
+			if gotCompleted := gotPod.Annotations[appspub.ContainerLaunchPriorityCompletedKey] == "true"; gotCompleted != tc.expectedCompleted {
+				t.Fatalf("expected completed %v, got %v", tc.expectedCompleted, gotCompleted)
+			}
+			if gotEvent := len(recorder.Events) > 0; gotEvent != tc.expectedEvent {
+				t.Fatalf("expected event %v, got %v", tc.expectedEvent, gotEvent)
+			}
+			if gotEnqueue := shouldEnqueue(gotPod, cli); gotEnqueue != tc.expectedEnqueue {
+				t.Fatalf("expected enqueue %v, got %v", tc.expectedEnqueue, gotEnqueue)
+			}
+		})
+	}
+}
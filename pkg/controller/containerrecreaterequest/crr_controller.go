@@ -20,12 +20,14 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"sort"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	"k8s.io/kubernetes/pkg/util/slice"
@@ -39,6 +41,9 @@ import (
 
 	appspub "github.com/openkruise/kruise/apis/apps/pub"
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
+	"github.com/openkruise/kruise/pkg/control/pubcontrol"
 	"github.com/openkruise/kruise/pkg/features"
 	"github.com/openkruise/kruise/pkg/util"
 	utilclient "github.com/openkruise/kruise/pkg/util/client"
@@ -50,16 +55,35 @@ import (
 )
 
 const (
-	responseTimeout = time.Minute
+	responseTimeout  = time.Minute
+	pubRetryDuration = 5 * time.Second
 )
 
 func init() {
 	flag.IntVar(&concurrentReconciles, "crr-workers", concurrentReconciles, "Max concurrent workers for ContainerRecreateRequest controller.")
+	flag.IntVar(&maxFinishedCRRsPerPod, "crr-max-finished-per-pod", maxFinishedCRRsPerPod,
+		"The max number of finished ContainerRecreateRequests to keep for each Pod, the oldest ones beyond it are deleted. 0 means no limit.")
 }
 
 var (
 	concurrentReconciles = 3
-	controllerKind       = appsv1alpha1.SchemeGroupVersion.WithKind("ContainerRecreateRequest")
+	// maxFinishedCRRsPerPod is the max number of finished CRRs to keep for each Pod, which composes with ttlSecondsAfterFinished.
+	maxFinishedCRRsPerPod = 0
+	controllerKind        = appsv1alpha1.SchemeGroupVersion.WithKind("ContainerRecreateRequest")
+
+	// podUnavailableBudgetValidatePod checks whether the PodUnavailableBudget allows to recreate containers in the Pod,
+	// and returns the name of the PodUnavailableBudget if not allowed.
+	podUnavailableBudgetValidatePod = func(pod *v1.Pod) (bool, string, error) {
+		allowed, _, err := pubcontrol.PodUnavailableBudgetValidatePod(pod, policyv1alpha1.PubUpdateOperation, "kruise-manager", false)
+		if err != nil || allowed {
+			return allowed, "", err
+		}
+		pub, err := pubcontrol.PubControl.GetPubForPod(pod)
+		if err != nil || pub == nil {
+			return false, "", err
+		}
+		return false, pub.Name, nil
+	}
 )
 
 // Add creates a new ContainerRecreateRequest Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
@@ -77,6 +101,7 @@ func newReconciler(mgr manager.Manager) *ReconcileContainerRecreateRequest {
 	return &ReconcileContainerRecreateRequest{
 		Client:              cli,
 		clock:               clock.RealClock{},
+		recorder:            mgr.GetEventRecorderFor("containerrecreaterequest-controller"),
 		podReadinessControl: utilpodreadiness.NewForAdapter(&podadapter.AdapterRuntimeClient{Client: cli}),
 	}
 }
@@ -112,12 +137,14 @@ var _ reconcile.Reconciler = &ReconcileContainerRecreateRequest{}
 type ReconcileContainerRecreateRequest struct {
 	client.Client
 	clock               clock.Clock
+	recorder            record.EventRecorder
 	podReadinessControl utilpodreadiness.Interface
 }
 
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=containerrecreaterequests,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=containerrecreaterequests/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=containerrecreaterequests/finalizers,verbs=update
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=nodeimages,verbs=get;list;watch
 
 // Reconcile reads that state of the cluster for a ContainerRecreateRequest object and makes changes based on the state read
 // and what is in the ContainerRecreateRequest.Spec
@@ -179,6 +206,9 @@ func (r *ReconcileContainerRecreateRequest) Reconcile(_ context.Context, request
 				return reconcile.Result{}, nil
 			}
 		}
+		if deleted, err := r.sweepFinishedCRRs(crr); err != nil || deleted {
+			return reconcile.Result{}, err
+		}
 		return reconcile.Result{RequeueAfter: leftTime}, nil
 	}
 
@@ -192,6 +222,14 @@ func (r *ReconcileContainerRecreateRequest) Reconcile(_ context.Context, request
 
 	// daemon has not responded over a 1min
 	if crr.Status.Phase == "" {
+		// fail fast if the daemon on the node is not able to recreate the containers
+		if msg, err := r.checkNodeCapabilities(crr, pod); err != nil {
+			return reconcile.Result{}, err
+		} else if msg != "" {
+			klog.InfoS("Completed CRR as failure for node not supported", "containerRecreateRequest", klog.KObj(crr), "nodeName", pod.Spec.NodeName, "message", msg)
+			return reconcile.Result{}, r.completeCRR(crr, msg)
+		}
+
 		leftTime := responseTimeout - time.Since(crr.CreationTimestamp.Time)
 		if leftTime <= 0 {
 			klog.InfoS("Completed CRR as failure for daemon has not responded for a long time", "containerRecreateRequest", klog.KObj(crr))
@@ -210,6 +248,22 @@ func (r *ReconcileContainerRecreateRequest) Reconcile(_ context.Context, request
 		duration.Update(leftTime)
 	}
 
+	if crr.Status.Phase != appsv1alpha1.ContainerRecreateRequestRecreating && crr.Status.Phase != appsv1alpha1.ContainerRecreateRequestPendingPUB {
+		return reconcile.Result{RequeueAfter: duration.Get()}, nil
+	}
+
+	// check PodUnavailableBudget before the daemon stops any container
+	if !crr.Spec.Strategy.IgnorePodUnavailableBudget && crr.Annotations[appsv1alpha1.ContainerRecreateRequestPUBAdmittedKey] == "" {
+		admitted, err := r.admitPodUnavailableBudget(crr, pod)
+		if err != nil {
+			return reconcile.Result{}, err
+		} else if !admitted {
+			duration.Update(pubRetryDuration)
+		}
+		return reconcile.Result{RequeueAfter: duration.Get()}, nil
+	}
+
+	// the daemon will update it to Recreating after admitted
 	if crr.Status.Phase != appsv1alpha1.ContainerRecreateRequestRecreating {
 		return reconcile.Result{RequeueAfter: duration.Get()}, nil
 	}
@@ -229,6 +283,67 @@ func (r *ReconcileContainerRecreateRequest) Reconcile(_ context.Context, request
 	return reconcile.Result{RequeueAfter: duration.Get()}, nil
 }
 
+// admitPodUnavailableBudget marks the CRR admitted if the PodUnavailableBudget of Pod allows to recreate its containers,
+// otherwise holds the CRR in PendingPUB phase.
+func (r *ReconcileContainerRecreateRequest) admitPodUnavailableBudget(crr *appsv1alpha1.ContainerRecreateRequest, pod *v1.Pod) (bool, error) {
+	allowed, pubName, err := podUnavailableBudgetValidatePod(pod)
+	if err != nil {
+		return false, fmt.Errorf("failed to validate PodUnavailableBudget: %v", err)
+	}
+	if allowed {
+		body := fmt.Sprintf(`{"metadata":{"annotations":{"%s":"%s"}}}`, appsv1alpha1.ContainerRecreateRequestPUBAdmittedKey, r.clock.Now().Format(time.RFC3339))
+		return true, r.Patch(context.TODO(), crr, client.RawPatch(types.MergePatchType, []byte(body)))
+	}
+
+	klog.InfoS("CRR is waiting for PodUnavailableBudget to allow recreating Pod", "containerRecreateRequest", klog.KObj(crr), "pod", klog.KObj(pod), "podUnavailableBudget", pubName)
+	if crr.Status.Phase == appsv1alpha1.ContainerRecreateRequestPendingPUB {
+		return false, nil
+	}
+	r.recorder.Eventf(crr, v1.EventTypeWarning, "PodUnavailableBudgetBlocked",
+		"recreating containers of Pod %s is not allowed by PodUnavailableBudget %s, will retry later", pod.Name, pubName)
+	crr.Status.Phase = appsv1alpha1.ContainerRecreateRequestPendingPUB
+	crr.Status.Message = fmt.Sprintf("not allowed by PodUnavailableBudget %s", pubName)
+	return false, r.Status().Update(context.TODO(), crr)
+}
+
+// checkNodeCapabilities returns a message if the features required by the CRR are not supported by the daemon on the node of Pod.
+func (r *ReconcileContainerRecreateRequest) checkNodeCapabilities(crr *appsv1alpha1.ContainerRecreateRequest, pod *v1.Pod) (string, error) {
+	if pod.Spec.NodeName == "" {
+		return "", nil
+	}
+	nodeImage := &appsv1beta1.NodeImage{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Name: pod.Spec.NodeName}, nodeImage); err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get NodeImage %s: %v", pod.Spec.NodeName, err)
+	}
+
+	capabilities := nodeImage.Status.Capabilities
+	if !capabilities.IsFeatureSupported(appsv1beta1.NodeFeatureContainerRecreate) {
+		return fmt.Sprintf("node %s does not support %s", pod.Spec.NodeName, appsv1beta1.NodeFeatureContainerRecreate), nil
+	}
+	for i := range crr.Spec.Containers {
+		if !isNativeSidecar(crr.Spec.Containers[i].Name, pod) {
+			continue
+		}
+		if !capabilities.IsFeatureSupported(appsv1beta1.NodeFeatureNativeSidecarRestart) {
+			return fmt.Sprintf("node %s does not support %s for container %s", pod.Spec.NodeName, appsv1beta1.NodeFeatureNativeSidecarRestart, crr.Spec.Containers[i].Name), nil
+		}
+	}
+	return "", nil
+}
+
+func isNativeSidecar(name string, pod *v1.Pod) bool {
+	for i := range pod.Spec.InitContainers {
+		c := &pod.Spec.InitContainers[i]
+		if c.Name == name {
+			return c.RestartPolicy != nil && *c.RestartPolicy == v1.ContainerRestartPolicyAlways
+		}
+	}
+	return false
+}
+
 func (r *ReconcileContainerRecreateRequest) syncContainerStatuses(crr *appsv1alpha1.ContainerRecreateRequest, pod *v1.Pod) error {
 	syncContainerStatuses := make([]appsv1alpha1.ContainerRecreateRequestSyncContainerStatus, 0, len(crr.Spec.Containers))
 	for i := range crr.Spec.Containers {
@@ -330,3 +445,47 @@ func (r *ReconcileContainerRecreateRequest) completeCRR(crr *appsv1alpha1.Contai
 func getReadinessMessage(crr *appsv1alpha1.ContainerRecreateRequest) utilpodreadiness.Message {
 	return utilpodreadiness.Message{UserAgent: "ContainerRecreateRequest", Key: fmt.Sprintf("%s/%s", crr.Namespace, crr.Name)}
 }
+
+// sweepFinishedCRRs deletes the oldest finished CRRs of the same Pod beyond maxFinishedCRRsPerPod,
+// and returns whether the given CRR itself has been deleted.
+func (r *ReconcileContainerRecreateRequest) sweepFinishedCRRs(crr *appsv1alpha1.ContainerRecreateRequest) (bool, error) {
+	podUID := crr.Labels[appsv1alpha1.ContainerRecreateRequestPodUIDKey]
+	if maxFinishedCRRsPerPod <= 0 || podUID == "" {
+		return false, nil
+	}
+
+	crrList := &appsv1alpha1.ContainerRecreateRequestList{}
+	if err := r.List(context.TODO(), crrList, client.InNamespace(crr.Namespace),
+		client.MatchingLabels{appsv1alpha1.ContainerRecreateRequestPodUIDKey: podUID}); err != nil {
+		return false, fmt.Errorf("failed to list CRRs of Pod: %v", err)
+	}
+	var finished []*appsv1alpha1.ContainerRecreateRequest
+	for i := range crrList.Items {
+		if c := &crrList.Items[i]; c.DeletionTimestamp == nil && c.Status.CompletionTime != nil {
+			finished = append(finished, c)
+		}
+	}
+	if len(finished) <= maxFinishedCRRsPerPod {
+		return false, nil
+	}
+
+	// the most recently finished ones first
+	sort.SliceStable(finished, func(i, j int) bool {
+		if !finished[i].Status.CompletionTime.Equal(finished[j].Status.CompletionTime) {
+			return finished[j].Status.CompletionTime.Before(finished[i].Status.CompletionTime)
+		}
+		return finished[j].CreationTimestamp.Before(&finished[i].CreationTimestamp)
+	})
+	var deleted bool
+	for _, c := range finished[maxFinishedCRRsPerPod:] {
+		klog.InfoS("Deleting CRR for exceeding the max finished CRRs of Pod", "containerRecreateRequest", klog.KObj(c),
+			"podName", c.Spec.PodName, "maxFinishedCRRsPerPod", maxFinishedCRRsPerPod)
+		if err := r.Delete(context.TODO(), c); err != nil && !errors.IsNotFound(err) {
+			return deleted, fmt.Errorf("delete CRR error: %v", err)
+		}
+		if c.UID == crr.UID {
+			deleted = true
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,313 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerrecreaterequest
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+)
+
+func TestReconcileWithNodeCapabilities(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = appsv1alpha1.AddToScheme(scheme)
+	_ = appsv1beta1.AddToScheme(scheme)
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-0", UID: "pod-uid"},
+		Spec: v1.PodSpec{
+			NodeName: "node-0",
+			InitContainers: []v1.Container{
+				{Name: "sidecar", RestartPolicy: ptr.To(v1.ContainerRestartPolicyAlways)},
+			},
+			Containers: []v1.Container{{Name: "main"}},
+		},
+	}
+
+	cases := []struct {
+		name         string
+		containers   []string
+		capabilities *appsv1beta1.NodeCapabilities
+		noNodeImage  bool
+		expectedMsg  string
+	}{
+		{
+			name:        "no NodeImage for node",
+			containers:  []string{"main"},
+			noNodeImage: true,
+		},
+		{
+			name:       "capabilities not reported",
+			containers: []string{"main", "sidecar"},
+		},
+		{
+			name:         "container recreate supported",
+			containers:   []string{"main"},
+			capabilities: &appsv1beta1.NodeCapabilities{Features: []appsv1beta1.NodeFeature{appsv1beta1.NodeFeatureContainerRecreate}},
+		},
+		{
+			name:         "container recreate not supported",
+			containers:   []string{"main"},
+			capabilities: &appsv1beta1.NodeCapabilities{Features: []appsv1beta1.NodeFeature{appsv1beta1.NodeFeatureImagePull}},
+			expectedMsg:  "node node-0 does not support ContainerRecreate",
+		},
+		{
+			name:         "native sidecar restart not supported",
+			containers:   []string{"main", "sidecar"},
+			capabilities: &appsv1beta1.NodeCapabilities{Features: []appsv1beta1.NodeFeature{appsv1beta1.NodeFeatureContainerRecreate}},
+			expectedMsg:  "node node-0 does not support NativeSidecarRestart for container sidecar",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			crr := &appsv1alpha1.ContainerRecreateRequest{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:         "default",
+					Name:              "crr-0",
+					Labels:            map[string]string{appsv1alpha1.ContainerRecreateRequestPodUIDKey: string(pod.UID)},
+					CreationTimestamp: metav1.Now(),
+				},
+				Spec: appsv1alpha1.ContainerRecreateRequestSpec{PodName: pod.Name},
+			}
+			for _, name := range tc.containers {
+				crr.Spec.Containers = append(crr.Spec.Containers, appsv1alpha1.ContainerRecreateRequestContainer{Name: name})
+			}
+			objects := []client.Object{pod.DeepCopy(), crr}
+			if !tc.noNodeImage {
+				objects = append(objects, &appsv1beta1.NodeImage{
+					ObjectMeta: metav1.ObjectMeta{Name: pod.Spec.NodeName},
+					Status:     appsv1beta1.NodeImageStatus{Capabilities: tc.capabilities},
+				})
+			}
+			r := &ReconcileContainerRecreateRequest{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+					WithStatusSubresource(&appsv1alpha1.ContainerRecreateRequest{}).Build(),
+				clock: clock.RealClock{},
+			}
+
+			if _, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: crr.Namespace, Name: crr.Name}}); err != nil {
+				t.Fatalf("failed to reconcile: %v", err)
+			}
+			newCRR := &appsv1alpha1.ContainerRecreateRequest{}
+			if err := r.Get(context.TODO(), types.NamespacedName{Namespace: crr.Namespace, Name: crr.Name}, newCRR); err != nil {
+				t.Fatalf("failed to get CRR: %v", err)
+			}
+			if tc.expectedMsg == "" {
+				if newCRR.Status.Phase != "" {
+					t.Fatalf("expected CRR not completed, got %+v", newCRR.Status)
+				}
+				return
+			}
+			if newCRR.Status.Phase != appsv1alpha1.ContainerRecreateRequestCompleted || newCRR.Status.Message != tc.expectedMsg {
+				t.Fatalf("expected CRR completed with %q, got %+v", tc.expectedMsg, newCRR.Status)
+			}
+		})
+	}
+}
+
+func TestReconcileWithPodUnavailableBudget(t *testing.T) {
+	defer func(fn func(*v1.Pod) (bool, string, error)) { podUnavailableBudgetValidatePod = fn }(podUnavailableBudgetValidatePod)
+	var allowed bool
+	var validated int
+	podUnavailableBudgetValidatePod = func(pod *v1.Pod) (bool, string, error) {
+		validated++
+		if allowed {
+			return true, "", nil
+		}
+		return false, "pub-demo", nil
+	}
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = appsv1alpha1.AddToScheme(scheme)
+	_ = appsv1beta1.AddToScheme(scheme)
+
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "pod-0", UID: "pod-uid"},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "main"}}},
+	}
+	newCRR := func(ignore bool) *appsv1alpha1.ContainerRecreateRequest {
+		return &appsv1alpha1.ContainerRecreateRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         "default",
+				Name:              "crr-0",
+				Labels:            map[string]string{appsv1alpha1.ContainerRecreateRequestPodUIDKey: string(pod.UID)},
+				CreationTimestamp: metav1.Now(),
+			},
+			Spec: appsv1alpha1.ContainerRecreateRequestSpec{
+				PodName:    pod.Name,
+				Containers: []appsv1alpha1.ContainerRecreateRequestContainer{{Name: "main"}},
+				Strategy:   &appsv1alpha1.ContainerRecreateRequestStrategy{IgnorePodUnavailableBudget: ignore},
+			},
+			Status: appsv1alpha1.ContainerRecreateRequestStatus{Phase: appsv1alpha1.ContainerRecreateRequestRecreating},
+		}
+	}
+	newReconciler := func(crr *appsv1alpha1.ContainerRecreateRequest) (*ReconcileContainerRecreateRequest, *record.FakeRecorder) {
+		recorder := record.NewFakeRecorder(10)
+		return &ReconcileContainerRecreateRequest{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod.DeepCopy(), crr).
+				WithStatusSubresource(&appsv1alpha1.ContainerRecreateRequest{}).Build(),
+			clock:    clock.RealClock{},
+			recorder: recorder,
+		}, recorder
+	}
+	reconcileCRR := func(r *ReconcileContainerRecreateRequest) (reconcile.Result, *appsv1alpha1.ContainerRecreateRequest) {
+		key := types.NamespacedName{Namespace: "default", Name: "crr-0"}
+		res, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+		if err != nil {
+			t.Fatalf("failed to reconcile: %v", err)
+		}
+		crr := &appsv1alpha1.ContainerRecreateRequest{}
+		if err := r.Get(context.TODO(), key, crr); err != nil {
+			t.Fatalf("failed to get CRR: %v", err)
+		}
+		return res, crr
+	}
+
+	r, recorder := newReconciler(newCRR(false))
+	res, crr := reconcileCRR(r)
+	if crr.Status.Phase != appsv1alpha1.ContainerRecreateRequestPendingPUB || crr.Status.Message != "not allowed by PodUnavailableBudget pub-demo" {
+		t.Fatalf("expected CRR pending for PUB, got %+v", crr.Status)
+	}
+	if _, ok := crr.Annotations[appsv1alpha1.ContainerRecreateRequestPUBAdmittedKey]; ok {
+		t.Fatalf("expected CRR not admitted")
+	}
+	if res.RequeueAfter != pubRetryDuration {
+		t.Fatalf("expected requeue after %v, got %v", pubRetryDuration, res.RequeueAfter)
+	}
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(recorder.Events))
+	}
+
+	// no more event when retrying
+	reconcileCRR(r)
+	if len(recorder.Events) != 1 {
+		t.Fatalf("expected no more event, got %d", len(recorder.Events))
+	}
+
+	allowed = true
+	_, crr = reconcileCRR(r)
+	if _, ok := crr.Annotations[appsv1alpha1.ContainerRecreateRequestPUBAdmittedKey]; !ok {
+		t.Fatalf("expected CRR admitted, got %v", crr.Annotations)
+	}
+
+	// the PodUnavailableBudget is not checked if ignored
+	allowed, validated = false, 0
+	r, _ = newReconciler(newCRR(true))
+	if _, crr = reconcileCRR(r); crr.Status.Phase != appsv1alpha1.ContainerRecreateRequestRecreating || validated != 0 {
+		t.Fatalf("expected PUB ignored, got phase %s and validated %d times", crr.Status.Phase, validated)
+	}
+}
+
+func TestReconcileWithMaxFinishedCRRsPerPod(t *testing.T) {
+	defer func(n int) { maxFinishedCRRsPerPod = n }(maxFinishedCRRsPerPod)
+	maxFinishedCRRsPerPod = 2
+
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = appsv1alpha1.AddToScheme(scheme)
+
+	now := time.Now()
+	newCRR := func(name, podUID string, completedBefore time.Duration) *appsv1alpha1.ContainerRecreateRequest {
+		crr := &appsv1alpha1.ContainerRecreateRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:         "default",
+				Name:              name,
+				UID:               types.UID(name),
+				Labels:            map[string]string{appsv1alpha1.ContainerRecreateRequestPodUIDKey: podUID},
+				CreationTimestamp: metav1.NewTime(now.Add(-time.Hour)),
+			},
+			Spec: appsv1alpha1.ContainerRecreateRequestSpec{PodName: "pod-0", TTLSecondsAfterFinished: ptr.To[int32](600)},
+		}
+		if completedBefore > 0 {
+			crr.Status.Phase = appsv1alpha1.ContainerRecreateRequestCompleted
+			crr.Status.CompletionTime = &metav1.Time{Time: now.Add(-completedBefore)}
+		}
+		return crr
+	}
+	r := &ReconcileContainerRecreateRequest{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			newCRR("crr-1", "pod-uid", 4*time.Minute),
+			newCRR("crr-2", "pod-uid", 3*time.Minute),
+			newCRR("crr-3", "pod-uid", 2*time.Minute),
+			newCRR("crr-4", "pod-uid", time.Minute),
+			newCRR("crr-active", "pod-uid", 0),
+			newCRR("crr-other-pod", "other-uid", 5*time.Minute),
+		).WithStatusSubresource(&appsv1alpha1.ContainerRecreateRequest{}).Build(),
+		clock: clock.RealClock{},
+	}
+	getCRRNames := func() []string {
+		crrList := &appsv1alpha1.ContainerRecreateRequestList{}
+		if err := r.List(context.TODO(), crrList); err != nil {
+			t.Fatalf("failed to list CRRs: %v", err)
+		}
+		var names []string
+		for i := range crrList.Items {
+			names = append(names, crrList.Items[i].Name)
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	res, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "crr-4"}})
+	if err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+	if res.RequeueAfter <= 0 {
+		t.Fatalf("expected requeue for ttlSecondsAfterFinished, got %v", res)
+	}
+	expected := []string{"crr-3", "crr-4", "crr-active", "crr-other-pod"}
+	if names := getCRRNames(); !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected CRRs %v, got %v", expected, names)
+	}
+
+	// ttlSecondsAfterFinished still works under the max count
+	crr := &appsv1alpha1.ContainerRecreateRequest{}
+	if err = r.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "crr-other-pod"}, crr); err != nil {
+		t.Fatal(err)
+	}
+	crr.Spec.TTLSecondsAfterFinished = ptr.To[int32](60)
+	if err = r.Update(context.TODO(), crr); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "crr-other-pod"}}); err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+	expected = []string{"crr-3", "crr-4", "crr-active"}
+	if names := getCRRNames(); !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected CRRs %v, got %v", expected, names)
+	}
+}
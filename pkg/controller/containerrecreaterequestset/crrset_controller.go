@@ -0,0 +1,449 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerrecreaterequestset
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	kubecontroller "k8s.io/kubernetes/pkg/controller"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
+	"github.com/openkruise/kruise/pkg/control/pubcontrol"
+	"github.com/openkruise/kruise/pkg/features"
+	"github.com/openkruise/kruise/pkg/util"
+	utilclient "github.com/openkruise/kruise/pkg/util/client"
+	utildiscovery "github.com/openkruise/kruise/pkg/util/discovery"
+	"github.com/openkruise/kruise/pkg/util/expectations"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+)
+
+const (
+	controllerName = "containerrecreaterequestset-controller"
+
+	// pubRetryDuration is the duration to retry if the PodUnavailableBudget does not allow to recreate containers.
+	pubRetryDuration = time.Second
+)
+
+func init() {
+	flag.IntVar(&concurrentReconciles, "crrset-workers", concurrentReconciles, "Max concurrent workers for ContainerRecreateRequestSet controller.")
+}
+
+var (
+	concurrentReconciles = 3
+	controllerKind       = appsv1alpha1.SchemeGroupVersion.WithKind("ContainerRecreateRequestSet")
+	scaleExpectations    = expectations.NewScaleExpectations()
+
+	// podUnavailableBudgetValidatePod checks whether the PodUnavailableBudget allows to recreate containers in the Pod.
+	podUnavailableBudgetValidatePod = func(pod *v1.Pod) (bool, error) {
+		allowed, _, err := pubcontrol.PodUnavailableBudgetValidatePod(pod, policyv1alpha1.PubUpdateOperation, "kruise-manager", false)
+		return allowed, err
+	}
+)
+
+// Add creates a new ContainerRecreateRequestSet Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
+// and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	if !utildiscovery.DiscoverGVK(controllerKind) || !utilfeature.DefaultFeatureGate.Enabled(features.KruiseDaemon) {
+		return nil
+	}
+	return add(mgr, newReconciler(mgr))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager) *ReconcileContainerRecreateRequestSet {
+	return &ReconcileContainerRecreateRequestSet{
+		Client: utilclient.NewClientFromManager(mgr, controllerName),
+		clock:  clock.RealClock{},
+	}
+}
+
+// add adds a new Controller to mgr with r as the reconcile.Reconciler
+func add(mgr manager.Manager, r *ReconcileContainerRecreateRequestSet) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r,
+		MaxConcurrentReconciles: concurrentReconciles, CacheSyncTimeout: util.GetControllerCacheSyncTimeout()})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to ContainerRecreateRequestSet
+	err = c.Watch(source.Kind(mgr.GetCache(), &appsv1alpha1.ContainerRecreateRequestSet{}, &handler.TypedEnqueueRequestForObject[*appsv1alpha1.ContainerRecreateRequestSet]{}))
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to ContainerRecreateRequest owned by ContainerRecreateRequestSet
+	err = c.Watch(source.Kind(mgr.GetCache(), &appsv1alpha1.ContainerRecreateRequest{},
+		&crrEventHandler{
+			enqueueHandler: handler.TypedEnqueueRequestForOwner[*appsv1alpha1.ContainerRecreateRequest](mgr.GetScheme(), mgr.GetRESTMapper(),
+				&appsv1alpha1.ContainerRecreateRequestSet{}, handler.OnlyControllerOwner()),
+		}))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileContainerRecreateRequestSet{}
+
+// ReconcileContainerRecreateRequestSet reconciles a ContainerRecreateRequestSet object
+type ReconcileContainerRecreateRequestSet struct {
+	client.Client
+	clock clock.Clock
+}
+
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=containerrecreaterequestsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=containerrecreaterequestsets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=containerrecreaterequestsets/finalizers,verbs=update
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=containerrecreaterequests,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile reads that state of the cluster for a ContainerRecreateRequestSet object and makes changes based on the state read
+// and what is in the ContainerRecreateRequestSet.Spec
+func (r *ReconcileContainerRecreateRequestSet) Reconcile(_ context.Context, request reconcile.Request) (res reconcile.Result, err error) {
+	start := time.Now()
+	klog.V(3).InfoS("Starting to process CRRSet", "containerRecreateRequestSet", request)
+	defer func() {
+		if err != nil {
+			klog.ErrorS(err, "Failed to process CRRSet", "containerRecreateRequestSet", request, "elapsedTime", time.Since(start))
+		} else if res.RequeueAfter > 0 {
+			klog.InfoS("Finished processing CRRSet with scheduled retry", "containerRecreateRequestSet", request, "elapsedTime", time.Since(start), "retryAfter", res.RequeueAfter)
+		} else {
+			klog.InfoS("Finished processing CRRSet", "containerRecreateRequestSet", request, "elapsedTime", time.Since(start))
+		}
+	}()
+
+	crrSet := &appsv1alpha1.ContainerRecreateRequestSet{}
+	err = r.Get(context.TODO(), request.NamespacedName, crrSet)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			scaleExpectations.DeleteExpectations(request.String())
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if crrSet.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	// The CRRSet has been finished
+	if crrSet.Status.CompletionTime != nil {
+		var leftTime time.Duration
+		if crrSet.Spec.TTLSecondsAfterFinished != nil {
+			leftTime = time.Duration(*crrSet.Spec.TTLSecondsAfterFinished)*time.Second - r.clock.Since(crrSet.Status.CompletionTime.Time)
+			if leftTime <= 0 {
+				klog.InfoS("Deleting CRRSet for ttlSecondsAfterFinished", "containerRecreateRequestSet", klog.KObj(crrSet))
+				if err = r.Delete(context.TODO(), crrSet); err != nil {
+					return reconcile.Result{}, fmt.Errorf("delete CRRSet error: %v", err)
+				}
+				return reconcile.Result{}, nil
+			}
+		}
+		return reconcile.Result{RequeueAfter: leftTime}, nil
+	}
+
+	if scaleSatisfied, unsatisfiedDuration, dirtyPods := scaleExpectations.SatisfiedExpectations(request.String()); !scaleSatisfied {
+		if unsatisfiedDuration >= expectations.ExpectationTimeout {
+			klog.InfoS("Expectation unsatisfied overtime for CRRSet", "containerRecreateRequestSet", request, "dirtyPods", dirtyPods, "overtime", unsatisfiedDuration)
+			return reconcile.Result{}, nil
+		}
+		klog.V(4).InfoS("Not satisfied scale for CRRSet", "containerRecreateRequestSet", request, "dirtyPods", dirtyPods)
+		return reconcile.Result{RequeueAfter: expectations.ExpectationTimeout - unsatisfiedDuration}, nil
+	}
+
+	crrs, err := r.getOwnedCRRs(crrSet)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to get CRRs: %v", err)
+	}
+	pods, err := r.getTargetPods(crrSet)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to get Pods: %v", err)
+	}
+	conflictedPods, err := r.getConflictedPods(crrSet, crrs, pods)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("failed to get conflicted CRRs: %v", err)
+	}
+
+	newStatus, waitingPods := calculateStatus(crrSet, crrs, pods, conflictedPods)
+
+	var requeueAfter time.Duration
+	if newStatus.CompletionTime == nil {
+		maxActive, err := intstr.GetScaledValueFromIntOrPercent(getConcurrency(crrSet), int(newStatus.Total), true)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if maxActive < 1 {
+			maxActive = 1
+		}
+
+		for i := 0; i < len(waitingPods) && int(newStatus.Active) < maxActive; i++ {
+			pod := waitingPods[i]
+			if crrSet.Spec.RespectPodUnavailableBudget {
+				allowed, err := podUnavailableBudgetValidatePod(pod)
+				if err != nil {
+					return reconcile.Result{}, err
+				} else if !allowed {
+					// pub check does not pass, try again in seconds
+					klog.V(3).InfoS("CRRSet waiting for PodUnavailableBudget to allow recreating Pod", "containerRecreateRequestSet", klog.KObj(crrSet), "pod", klog.KObj(pod))
+					requeueAfter = pubRetryDuration
+					break
+				}
+			}
+
+			if err := r.createCRR(crrSet, pod); err != nil {
+				return reconcile.Result{}, err
+			}
+			newStatus.Active++
+		}
+	} else {
+		klog.InfoS("CRRSet has completed", "containerRecreateRequestSet", klog.KObj(crrSet),
+			"total", newStatus.Total, "succeeded", newStatus.Succeeded, "failed", newStatus.Failed)
+	}
+
+	if !util.IsJSONObjectEqual(&crrSet.Status, newStatus) {
+		if err = r.updateStatus(crrSet, newStatus); err != nil {
+			return reconcile.Result{}, fmt.Errorf("update CRRSet status error: %v", err)
+		}
+	}
+
+	if newStatus.CompletionTime != nil && crrSet.Spec.TTLSecondsAfterFinished != nil {
+		requeueAfter = time.Duration(*crrSet.Spec.TTLSecondsAfterFinished) * time.Second
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// getOwnedCRRs returns the CRRs labeled with the name of the CRRSet and controlled by it, keyed by the Pod names.
+func (r *ReconcileContainerRecreateRequestSet) getOwnedCRRs(crrSet *appsv1alpha1.ContainerRecreateRequestSet) (map[string]*appsv1alpha1.ContainerRecreateRequest, error) {
+	opts := &client.ListOptions{
+		Namespace:     crrSet.Namespace,
+		LabelSelector: labels.SelectorFromSet(labels.Set{appsv1alpha1.ContainerRecreateRequestSetNameKey: crrSet.Name}),
+	}
+	crrList := &appsv1alpha1.ContainerRecreateRequestList{}
+	if err := r.List(context.TODO(), crrList, opts, utilclient.DisableDeepCopy); err != nil {
+		return nil, err
+	}
+	crrs := make(map[string]*appsv1alpha1.ContainerRecreateRequest, len(crrList.Items))
+	for i := range crrList.Items {
+		crr := &crrList.Items[i]
+		if metav1.IsControlledBy(crr, crrSet) {
+			crrs[crr.Spec.PodName] = crr
+		}
+	}
+	return crrs, nil
+}
+
+// getConflictedPods returns the target Pods without CRRs owned by the CRRSet, whose CRR names have been taken by
+// the CRRs not owned by it. The owned CRRs found by names, which were created without the label, are added into crrs.
+func (r *ReconcileContainerRecreateRequestSet) getConflictedPods(crrSet *appsv1alpha1.ContainerRecreateRequestSet,
+	crrs map[string]*appsv1alpha1.ContainerRecreateRequest, pods []*v1.Pod) (sets.String, error) {
+
+	conflictedPods := sets.NewString()
+	for _, pod := range pods {
+		if _, ok := crrs[pod.Name]; ok {
+			continue
+		}
+		crr := &appsv1alpha1.ContainerRecreateRequest{}
+		err := r.Get(context.TODO(), types.NamespacedName{Namespace: crrSet.Namespace, Name: getCRRName(crrSet, pod)}, crr)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if metav1.IsControlledBy(crr, crrSet) && crr.Spec.PodName == pod.Name {
+			crrs[pod.Name] = crr
+			continue
+		}
+		klog.InfoS("CRRSet found the CRR of Pod not owned by it", "containerRecreateRequestSet", klog.KObj(crrSet), "containerRecreateRequest", klog.KObj(crr), "pod", klog.KObj(pod))
+		conflictedPods.Insert(pod.Name)
+	}
+	return conflictedPods, nil
+}
+
+// getTargetPods returns the active and scheduled Pods selected by the CRRSet,
+// which were created before the CRRSet.
+func (r *ReconcileContainerRecreateRequestSet) getTargetPods(crrSet *appsv1alpha1.ContainerRecreateRequestSet) ([]*v1.Pod, error) {
+	selector, err := util.ValidatedLabelSelectorAsSelector(crrSet.Spec.PodSelector)
+	if err != nil {
+		return nil, err
+	}
+	podList := &v1.PodList{}
+	if err := r.List(context.TODO(), podList, &client.ListOptions{Namespace: crrSet.Namespace, LabelSelector: selector}, utilclient.DisableDeepCopy); err != nil {
+		return nil, err
+	}
+	var pods []*v1.Pod
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if !kubecontroller.IsPodActive(pod) || pod.Spec.NodeName == "" || pod.CreationTimestamp.After(crrSet.CreationTimestamp.Time) {
+			continue
+		}
+		pods = append(pods, pod)
+	}
+	return pods, nil
+}
+
+// calculateStatus calculates the status of CRRSet, and returns the target Pods that are waiting to recreate containers.
+// The conflicted Pods are counted as failed, because their CRRs can not be created.
+func calculateStatus(crrSet *appsv1alpha1.ContainerRecreateRequestSet, crrs map[string]*appsv1alpha1.ContainerRecreateRequest,
+	pods []*v1.Pod, conflictedPods sets.String) (*appsv1alpha1.ContainerRecreateRequestSetStatus, []*v1.Pod) {
+	newStatus := crrSet.Status.DeepCopy()
+	newStatus.Phase = appsv1alpha1.ContainerRecreateRequestRecreating
+	newStatus.Active, newStatus.Succeeded, newStatus.Failed = 0, 0, 0
+
+	for _, crr := range crrs {
+		switch {
+		case crr.Status.CompletionTime == nil:
+			newStatus.Active++
+		case isCRRFailed(crr):
+			newStatus.Failed++
+		default:
+			newStatus.Succeeded++
+		}
+	}
+
+	var waitingPods []*v1.Pod
+	var conflicted int32
+	for _, pod := range pods {
+		if _, ok := crrs[pod.Name]; ok {
+			continue
+		}
+		if conflictedPods.Has(pod.Name) {
+			conflicted++
+			continue
+		}
+		waitingPods = append(waitingPods, pod)
+	}
+	sort.SliceStable(waitingPods, func(i, j int) bool { return waitingPods[i].Name < waitingPods[j].Name })
+
+	newStatus.Failed += conflicted
+	newStatus.Total = int32(len(crrs)+len(waitingPods)) + conflicted
+	if len(waitingPods) == 0 && newStatus.Active == 0 {
+		now := metav1.Now()
+		newStatus.Phase = appsv1alpha1.ContainerRecreateRequestCompleted
+		newStatus.CompletionTime = &now
+		if newStatus.Total == 0 {
+			newStatus.Message = "no Pods selected"
+		} else if conflicted > 0 {
+			newStatus.Message = fmt.Sprintf("%d Pods failed to recreate containers, %d of them conflicted with the existing ContainerRecreateRequests not owned by this set",
+				newStatus.Failed, conflicted)
+		} else if newStatus.Failed > 0 {
+			newStatus.Message = fmt.Sprintf("%d Pods failed to recreate containers", newStatus.Failed)
+		}
+	}
+	return newStatus, waitingPods
+}
+
+// isCRRFailed returns true if the completed CRR has not recreated all the containers successfully.
+func isCRRFailed(crr *appsv1alpha1.ContainerRecreateRequest) bool {
+	if crr.Status.Message != "" {
+		return true
+	}
+	for i := range crr.Status.ContainerRecreateStates {
+		if crr.Status.ContainerRecreateStates[i].Phase == appsv1alpha1.ContainerRecreateRequestFailed {
+			return true
+		}
+	}
+	return false
+}
+
+func getConcurrency(crrSet *appsv1alpha1.ContainerRecreateRequestSet) *intstr.IntOrString {
+	if crrSet.Spec.Concurrency != nil {
+		return crrSet.Spec.Concurrency
+	}
+	concurrency := intstr.FromInt32(1)
+	return &concurrency
+}
+
+func getCRRName(crrSet *appsv1alpha1.ContainerRecreateRequestSet, pod *v1.Pod) string {
+	return util.GetBoundedObjectName(fmt.Sprintf("%s-%s", crrSet.Name, pod.Name))
+}
+
+func (r *ReconcileContainerRecreateRequestSet) createCRR(crrSet *appsv1alpha1.ContainerRecreateRequestSet, pod *v1.Pod) error {
+	crr := &appsv1alpha1.ContainerRecreateRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       crrSet.Namespace,
+			Name:            getCRRName(crrSet, pod),
+			Labels:          map[string]string{appsv1alpha1.ContainerRecreateRequestSetNameKey: crrSet.Name},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(crrSet, controllerKind)},
+		},
+		Spec: appsv1alpha1.ContainerRecreateRequestSpec{
+			PodName:               pod.Name,
+			Containers:            make([]appsv1alpha1.ContainerRecreateRequestContainer, len(crrSet.Spec.Containers)),
+			Strategy:              crrSet.Spec.Strategy.DeepCopy(),
+			ActiveDeadlineSeconds: crrSet.Spec.ActiveDeadlineSeconds,
+		},
+	}
+	for i := range crrSet.Spec.Containers {
+		crr.Spec.Containers[i] = appsv1alpha1.ContainerRecreateRequestContainer{
+			Name:        crrSet.Spec.Containers[i].Name,
+			NameRegexp:  crrSet.Spec.Containers[i].NameRegexp,
+			PreStopHook: crrSet.Spec.Containers[i].PreStopHook.DeepCopy(),
+		}
+	}
+
+	key := types.NamespacedName{Namespace: crrSet.Namespace, Name: crrSet.Name}.String()
+	scaleExpectations.ExpectScale(key, expectations.Create, pod.Name)
+	if err := r.Create(context.TODO(), crr); err != nil {
+		scaleExpectations.ObserveScale(key, expectations.Create, pod.Name)
+		if errors.IsAlreadyExists(err) {
+			existing := &appsv1alpha1.ContainerRecreateRequest{}
+			if err := r.Get(context.TODO(), types.NamespacedName{Namespace: crr.Namespace, Name: crr.Name}, existing); err != nil {
+				return fmt.Errorf("failed to get existing CRR %s for Pod %s: %v", crr.Name, pod.Name, err)
+			}
+			if metav1.IsControlledBy(existing, crrSet) && existing.Spec.PodName == pod.Name {
+				return nil
+			}
+			// requeue to count the Pod as conflicted once the cache has observed the CRR
+			return fmt.Errorf("CRR %s for Pod %s already exists and is not owned by the CRRSet", crr.Name, pod.Name)
+		}
+		return fmt.Errorf("failed to create CRR for Pod %s: %v", pod.Name, err)
+	}
+	klog.InfoS("CRRSet created CRR for Pod", "containerRecreateRequestSet", klog.KObj(crrSet), "containerRecreateRequest", klog.KObj(crr), "pod", klog.KObj(pod))
+	return nil
+}
+
+func (r *ReconcileContainerRecreateRequestSet) updateStatus(crrSet *appsv1alpha1.ContainerRecreateRequestSet, newStatus *appsv1alpha1.ContainerRecreateRequestSetStatus) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		clone := &appsv1alpha1.ContainerRecreateRequestSet{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Namespace: crrSet.Namespace, Name: crrSet.Name}, clone); err != nil {
+			return err
+		}
+		clone.Status = *newStatus
+		return r.Status().Update(context.TODO(), clone)
+	})
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerrecreaterequestset
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+var setCreationTime = metav1.NewTime(time.Now().Add(-time.Minute))
+
+func newTestCRRSet(concurrency int32, respectPub bool) *appsv1alpha1.ContainerRecreateRequestSet {
+	c := intstr.FromInt32(concurrency)
+	return &appsv1alpha1.ContainerRecreateRequestSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "restart-sidecar", UID: "set-uid", CreationTimestamp: setCreationTime},
+		Spec: appsv1alpha1.ContainerRecreateRequestSetSpec{
+			PodSelector:                 &metav1.LabelSelector{MatchLabels: map[string]string{"app": "demo"}},
+			Containers:                  []appsv1alpha1.ContainerRecreateRequestContainer{{Name: "sidecar"}},
+			Strategy:                    &appsv1alpha1.ContainerRecreateRequestStrategy{OrderedRecreate: true},
+			Concurrency:                 &c,
+			RespectPodUnavailableBudget: respectPub,
+		},
+	}
+}
+
+func newTestPod(name, nodeName string, created metav1.Time) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Labels: map[string]string{"app": "demo"}, CreationTimestamp: created},
+		Spec:       v1.PodSpec{NodeName: nodeName, Containers: []v1.Container{{Name: "main"}, {Name: "sidecar"}}},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+}
+
+func newTestReconciler(objs ...client.Object) *ReconcileContainerRecreateRequestSet {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = appsv1alpha1.AddToScheme(scheme)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+		WithStatusSubresource(&appsv1alpha1.ContainerRecreateRequestSet{}, &appsv1alpha1.ContainerRecreateRequest{}).Build()
+	return &ReconcileContainerRecreateRequestSet{Client: fakeClient, clock: clock.RealClock{}}
+}
+
+func reconcileCRRSet(t *testing.T, r *ReconcileContainerRecreateRequestSet) (reconcile.Result, *appsv1alpha1.ContainerRecreateRequestSet, []appsv1alpha1.ContainerRecreateRequest) {
+	key := types.NamespacedName{Namespace: "default", Name: "restart-sidecar"}
+	res, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("failed to reconcile: %v", err)
+	}
+	// the fake client does not deliver create events to observe the expectations
+	scaleExpectations.DeleteExpectations(key.String())
+
+	crrSet := &appsv1alpha1.ContainerRecreateRequestSet{}
+	if err := r.Get(context.TODO(), key, crrSet); err != nil {
+		t.Fatalf("failed to get CRRSet: %v", err)
+	}
+	crrList := &appsv1alpha1.ContainerRecreateRequestList{}
+	if err := r.List(context.TODO(), crrList, client.InNamespace("default")); err != nil {
+		t.Fatalf("failed to list CRRs: %v", err)
+	}
+	return res, crrSet, crrList.Items
+}
+
+func completeCRR(t *testing.T, r *ReconcileContainerRecreateRequestSet, name, message string) {
+	crr := &appsv1alpha1.ContainerRecreateRequest{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: name}, crr); err != nil {
+		t.Fatalf("failed to get CRR %s: %v", name, err)
+	}
+	now := metav1.Now()
+	crr.Status.Phase = appsv1alpha1.ContainerRecreateRequestCompleted
+	crr.Status.CompletionTime = &now
+	crr.Status.Message = message
+	if err := r.Status().Update(context.TODO(), crr); err != nil {
+		t.Fatalf("failed to update CRR %s: %v", name, err)
+	}
+}
+
+func expectStatus(t *testing.T, status appsv1alpha1.ContainerRecreateRequestSetStatus, total, active, succeeded, failed int32, completed bool) {
+	t.Helper()
+	if status.Total != total || status.Active != active || status.Succeeded != succeeded || status.Failed != failed {
+		t.Fatalf("expected total/active/succeeded/failed %d/%d/%d/%d, got %d/%d/%d/%d",
+			total, active, succeeded, failed, status.Total, status.Active, status.Succeeded, status.Failed)
+	}
+	if completed != (status.CompletionTime != nil) || completed != (status.Phase == appsv1alpha1.ContainerRecreateRequestCompleted) {
+		t.Fatalf("expected completed %v, got phase %s completionTime %v", completed, status.Phase, status.CompletionTime)
+	}
+}
+
+func TestReconcileCRRSetWithConcurrency(t *testing.T) {
+	r := newTestReconciler(
+		newTestCRRSet(2, false),
+		newTestPod("pod-a", "node-0", setCreationTime),
+		newTestPod("pod-b", "node-0", setCreationTime),
+		newTestPod("pod-c", "node-1", setCreationTime),
+		newTestPod("pod-pending", "", setCreationTime),
+		newTestPod("pod-new", "node-1", metav1.NewTime(setCreationTime.Add(time.Second))),
+	)
+
+	_, crrSet, crrs := reconcileCRRSet(t, r)
+	expectStatus(t, crrSet.Status, 3, 2, 0, 0, false)
+	if len(crrs) != 2 || crrs[0].Spec.PodName != "pod-a" || crrs[1].Spec.PodName != "pod-b" {
+		t.Fatalf("expected CRRs for pod-a and pod-b, got %v", crrs)
+	}
+	if crrs[0].Name != "restart-sidecar-pod-a" || !crrs[0].Spec.Strategy.OrderedRecreate || crrs[0].Spec.Containers[0].Name != "sidecar" {
+		t.Fatalf("unexpected CRR %v", crrs[0])
+	}
+	if ref := metav1.GetControllerOf(&crrs[0]); ref == nil || ref.UID != crrSet.UID {
+		t.Fatalf("expected CRR controlled by CRRSet, got %v", crrs[0].OwnerReferences)
+	}
+	if crrs[0].Labels[appsv1alpha1.ContainerRecreateRequestSetNameKey] != crrSet.Name {
+		t.Fatalf("expected CRR labeled with CRRSet name, got %v", crrs[0].Labels)
+	}
+
+	// nothing changes until a CRR completes
+	_, crrSet, crrs = reconcileCRRSet(t, r)
+	expectStatus(t, crrSet.Status, 3, 2, 0, 0, false)
+	if len(crrs) != 2 {
+		t.Fatalf("expected 2 CRRs, got %d", len(crrs))
+	}
+
+	completeCRR(t, r, "restart-sidecar-pod-a", "")
+	_, crrSet, crrs = reconcileCRRSet(t, r)
+	expectStatus(t, crrSet.Status, 3, 2, 1, 0, false)
+	if len(crrs) != 3 {
+		t.Fatalf("expected 3 CRRs, got %d", len(crrs))
+	}
+
+	completeCRR(t, r, "restart-sidecar-pod-b", "recreating has exceeded the activeDeadlineSeconds")
+	completeCRR(t, r, "restart-sidecar-pod-c", "")
+	_, crrSet, _ = reconcileCRRSet(t, r)
+	expectStatus(t, crrSet.Status, 3, 0, 2, 1, true)
+	if crrSet.Status.Message == "" {
+		t.Fatalf("expected message for failed Pods")
+	}
+}
+
+func TestReconcileCRRSetWithPodUnavailableBudget(t *testing.T) {
+	defer func(fn func(*v1.Pod) (bool, error)) { podUnavailableBudgetValidatePod = fn }(podUnavailableBudgetValidatePod)
+	allowed := map[string]bool{"pod-a": true}
+	podUnavailableBudgetValidatePod = func(pod *v1.Pod) (bool, error) {
+		return allowed[pod.Name], nil
+	}
+
+	r := newTestReconciler(
+		newTestCRRSet(3, true),
+		newTestPod("pod-a", "node-0", setCreationTime),
+		newTestPod("pod-b", "node-0", setCreationTime),
+		newTestPod("pod-c", "node-1", setCreationTime),
+	)
+
+	res, crrSet, crrs := reconcileCRRSet(t, r)
+	expectStatus(t, crrSet.Status, 3, 1, 0, 0, false)
+	if len(crrs) != 1 || crrs[0].Spec.PodName != "pod-a" {
+		t.Fatalf("expected CRR only for pod-a, got %v", crrs)
+	}
+	if res.RequeueAfter != pubRetryDuration {
+		t.Fatalf("expected requeue after %v, got %v", pubRetryDuration, res.RequeueAfter)
+	}
+
+	allowed["pod-b"], allowed["pod-c"] = true, true
+	res, crrSet, crrs = reconcileCRRSet(t, r)
+	expectStatus(t, crrSet.Status, 3, 3, 0, 0, false)
+	if len(crrs) != 3 || res.RequeueAfter != 0 {
+		t.Fatalf("expected 3 CRRs without requeue, got %d CRRs and requeue after %v", len(crrs), res.RequeueAfter)
+	}
+}
+
+func TestReconcileCRRSetWithoutPods(t *testing.T) {
+	crrSet := newTestCRRSet(1, false)
+	crrSet.Spec.TTLSecondsAfterFinished = ptr.To[int32](30)
+	r := newTestReconciler(crrSet)
+
+	res, crrSet, crrs := reconcileCRRSet(t, r)
+	expectStatus(t, crrSet.Status, 0, 0, 0, 0, true)
+	if len(crrs) != 0 {
+		t.Fatalf("expected no CRRs, got %d", len(crrs))
+	}
+	if res.RequeueAfter != 30*time.Second {
+		t.Fatalf("expected requeue after ttl, got %v", res.RequeueAfter)
+	}
+}
+
+func TestReconcileCRRSetWithLongPodName(t *testing.T) {
+	podName := strings.Repeat("a", validation.DNS1123SubdomainMaxLength)
+	r := newTestReconciler(newTestCRRSet(1, false), newTestPod(podName, "node-0", setCreationTime))
+
+	_, crrSet, crrs := reconcileCRRSet(t, r)
+	expectStatus(t, crrSet.Status, 1, 1, 0, 0, false)
+	if len(crrs) != 1 || crrs[0].Spec.PodName != podName {
+		t.Fatalf("expected CRR for the Pod with long name, got %v", crrs)
+	}
+	if errs := validation.IsDNS1123Subdomain(crrs[0].Name); len(errs) > 0 {
+		t.Fatalf("expected valid CRR name, got %v", errs)
+	}
+
+	// the same CRR is found for the Pod on the next reconcile
+	_, crrSet, crrs = reconcileCRRSet(t, r)
+	expectStatus(t, crrSet.Status, 1, 1, 0, 0, false)
+	if len(crrs) != 1 {
+		t.Fatalf("expected 1 CRR, got %d", len(crrs))
+	}
+}
+
+func TestReconcileCRRSetWithExistingCRRs(t *testing.T) {
+	crrSet := newTestCRRSet(3, false)
+	// the CRR created by another client takes the name of pod-a
+	conflicted := &appsv1alpha1.ContainerRecreateRequest{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "restart-sidecar-pod-a"},
+		Spec:       appsv1alpha1.ContainerRecreateRequestSpec{PodName: "pod-a"},
+	}
+	// the CRR of pod-b created by the CRRSet without the label
+	unlabeled := &appsv1alpha1.ContainerRecreateRequest{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "restart-sidecar-pod-b",
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(crrSet, controllerKind)}},
+		Spec: appsv1alpha1.ContainerRecreateRequestSpec{PodName: "pod-b"},
+	}
+	r := newTestReconciler(
+		crrSet, conflicted, unlabeled,
+		newTestPod("pod-a", "node-0", setCreationTime),
+		newTestPod("pod-b", "node-0", setCreationTime),
+		newTestPod("pod-c", "node-1", setCreationTime),
+	)
+
+	_, crrSet, crrs := reconcileCRRSet(t, r)
+	expectStatus(t, crrSet.Status, 3, 2, 0, 1, false)
+	if len(crrs) != 3 {
+		t.Fatalf("expected only CRR for pod-c created, got %v", crrs)
+	}
+
+	completeCRR(t, r, "restart-sidecar-pod-b", "")
+	completeCRR(t, r, "restart-sidecar-pod-c", "")
+	_, crrSet, _ = reconcileCRRSet(t, r)
+	expectStatus(t, crrSet.Status, 3, 0, 2, 1, true)
+	if !strings.Contains(crrSet.Status.Message, "not owned by this set") {
+		t.Fatalf("expected message for conflicted Pods, got %q", crrSet.Status.Message)
+	}
+}
+
+func TestCreateCRRConflicted(t *testing.T) {
+	crrSet := newTestCRRSet(1, false)
+	pod := newTestPod("pod-a", "node-0", setCreationTime)
+	conflicted := &appsv1alpha1.ContainerRecreateRequest{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "restart-sidecar-pod-a"},
+		Spec:       appsv1alpha1.ContainerRecreateRequestSpec{PodName: "pod-a"},
+	}
+	r := newTestReconciler(crrSet, pod, conflicted)
+	defer scaleExpectations.DeleteExpectations("default/restart-sidecar")
+	if err := r.createCRR(crrSet, pod); err == nil {
+		t.Fatalf("expected error for the conflicted CRR")
+	}
+
+	if err := r.Delete(context.TODO(), conflicted); err != nil {
+		t.Fatalf("failed to delete CRR: %v", err)
+	}
+	if err := r.createCRR(crrSet, pod); err != nil {
+		t.Fatalf("failed to create CRR: %v", err)
+	}
+	// the CRR owned by the CRRSet already exists
+	if err := r.createCRR(crrSet, pod); err != nil {
+		t.Fatalf("expected no error for the owned CRR, got %v", err)
+	}
+}
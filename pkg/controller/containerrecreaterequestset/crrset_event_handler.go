@@ -0,0 +1,71 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerrecreaterequestset
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/util/expectations"
+)
+
+var _ handler.TypedEventHandler[*appsv1alpha1.ContainerRecreateRequest, reconcile.Request] = &crrEventHandler{}
+
+type crrEventHandler struct {
+	enqueueHandler handler.TypedEventHandler[*appsv1alpha1.ContainerRecreateRequest, reconcile.Request]
+}
+
+func isCRRSetController(controllerRef *metav1.OwnerReference) bool {
+	refGV, err := schema.ParseGroupVersion(controllerRef.APIVersion)
+	if err != nil {
+		klog.ErrorS(err, "Could not parse APIVersion in OwnerReference", "ownerReference", controllerRef)
+		return false
+	}
+	return controllerRef.Kind == controllerKind.Kind && refGV.Group == controllerKind.Group
+}
+
+func (e *crrEventHandler) Create(ctx context.Context, evt event.TypedCreateEvent[*appsv1alpha1.ContainerRecreateRequest], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	crr := evt.Object
+	if controllerRef := metav1.GetControllerOf(crr); controllerRef != nil && isCRRSetController(controllerRef) {
+		key := types.NamespacedName{Namespace: crr.Namespace, Name: controllerRef.Name}.String()
+		scaleExpectations.ObserveScale(key, expectations.Create, crr.Spec.PodName)
+		e.enqueueHandler.Create(ctx, evt, q)
+	}
+}
+
+func (e *crrEventHandler) Update(ctx context.Context, evt event.TypedUpdateEvent[*appsv1alpha1.ContainerRecreateRequest], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	// only the completion of CRR changes the status of CRRSet
+	if evt.ObjectOld.Status.CompletionTime == nil && evt.ObjectNew.Status.CompletionTime != nil {
+		e.enqueueHandler.Update(ctx, evt, q)
+	}
+}
+
+func (e *crrEventHandler) Delete(ctx context.Context, evt event.TypedDeleteEvent[*appsv1alpha1.ContainerRecreateRequest], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	e.enqueueHandler.Delete(ctx, evt, q)
+}
+
+func (e *crrEventHandler) Generic(ctx context.Context, evt event.TypedGenericEvent[*appsv1alpha1.ContainerRecreateRequest], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+}
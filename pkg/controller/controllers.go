@@ -26,6 +26,7 @@ import (
 	"github.com/openkruise/kruise/pkg/controller/cloneset"
 	containerlauchpriority "github.com/openkruise/kruise/pkg/controller/containerlaunchpriority"
 	"github.com/openkruise/kruise/pkg/controller/containerrecreaterequest"
+	"github.com/openkruise/kruise/pkg/controller/containerrecreaterequestset"
 	"github.com/openkruise/kruise/pkg/controller/daemonset"
 	"github.com/openkruise/kruise/pkg/controller/ephemeraljob"
 	"github.com/openkruise/kruise/pkg/controller/imagelistpulljob"
@@ -51,6 +52,7 @@ func init() {
 	controllerAddFuncs = append(controllerAddFuncs, broadcastjob.Add)
 	controllerAddFuncs = append(controllerAddFuncs, cloneset.Add)
 	controllerAddFuncs = append(controllerAddFuncs, containerrecreaterequest.Add)
+	controllerAddFuncs = append(controllerAddFuncs, containerrecreaterequestset.Add)
 	controllerAddFuncs = append(controllerAddFuncs, daemonset.Add)
 	controllerAddFuncs = append(controllerAddFuncs, nodeimage.Add)
 	controllerAddFuncs = append(controllerAddFuncs, imagepulljob.Add)
@@ -0,0 +1,186 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemonset
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+)
+
+const (
+	// unknownNodeClass is the node class of nodes without the node class label.
+	unknownNodeClass = "unknown"
+	// otherNodeClass is the node class of nodes beyond maxNodeClassesPerDaemonSet.
+	otherNodeClass = "other"
+	// maxNodeClassesPerDaemonSet bounds the node classes tracked for each DaemonSet.
+	maxNodeClassesPerDaemonSet = 64
+	// maxSlowestNodeClasses is the number of node classes reported in status.slowestNodeClasses.
+	maxSlowestNodeClasses = 5
+)
+
+var (
+	// nodeClassLabelKey is the node label to group nodes into classes, node name is not used to bound the cardinality.
+	nodeClassLabelKey = corev1.LabelInstanceTypeStable
+
+	DaemonPodAvailableDurationMetrics = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "daemonset_pod_available_duration_seconds",
+			Help:    "Duration from creation to availability of the updated daemon pods of Advanced DaemonSet",
+			Buckets: []float64{5, 10, 20, 30, 60, 90, 120, 180, 240, 300, 600, 1200},
+		}, []string{"namespace", "name", "node_class"},
+	)
+
+	availabilityTracker = newNodeClassAvailabilityTracker()
+)
+
+type nodeClassAvailability struct {
+	nodes        int32
+	totalSeconds float64
+	maxSeconds   float64
+}
+
+type daemonSetAvailability struct {
+	revision     string
+	observedPods sets.Set[types.UID]
+	nodeClasses  map[string]*nodeClassAvailability
+}
+
+// nodeClassAvailabilityTracker records, for the update revision of each DaemonSet, the duration from creation to
+// availability of the daemon pods grouped by node class.
+type nodeClassAvailabilityTracker struct {
+	sync.Mutex
+	daemonSets map[string]*daemonSetAvailability
+}
+
+func newNodeClassAvailabilityTracker() *nodeClassAvailabilityTracker {
+	return &nodeClassAvailabilityTracker{daemonSets: map[string]*daemonSetAvailability{}}
+}
+
+// observe records the available daemon pod of the update revision on the node, each pod is recorded only once.
+// The records of the previous revision are dropped once a new revision is observed.
+func (t *nodeClassAvailabilityTracker) observe(ds *appsv1beta1.DaemonSet, revision string, node *corev1.Node, pod *corev1.Pod) {
+	availableTime, ok := getDaemonPodAvailableTime(pod, ds.Spec.MinReadySeconds)
+	if !ok {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+	key := keyFunc(ds)
+	record := t.daemonSets[key]
+	if record == nil || record.revision != revision {
+		record = &daemonSetAvailability{
+			revision:     revision,
+			observedPods: sets.New[types.UID](),
+			nodeClasses:  map[string]*nodeClassAvailability{},
+		}
+		t.daemonSets[key] = record
+	}
+	if record.observedPods.Has(pod.UID) {
+		return
+	}
+	record.observedPods.Insert(pod.UID)
+
+	nodeClass := getNodeClass(node)
+	stat := record.nodeClasses[nodeClass]
+	if stat == nil {
+		if len(record.nodeClasses) >= maxNodeClassesPerDaemonSet {
+			nodeClass = otherNodeClass
+			stat = record.nodeClasses[nodeClass]
+		}
+		if stat == nil {
+			stat = &nodeClassAvailability{}
+			record.nodeClasses[nodeClass] = stat
+		}
+	}
+	seconds := math.Max(availableTime.Sub(pod.CreationTimestamp.Time).Seconds(), 0)
+	stat.nodes++
+	stat.totalSeconds += seconds
+	stat.maxSeconds = math.Max(stat.maxSeconds, seconds)
+	DaemonPodAvailableDurationMetrics.WithLabelValues(ds.Namespace, ds.Name, nodeClass).Observe(seconds)
+}
+
+// slowestNodeClasses returns the node classes of the revision ordered by the average duration, at most maxSlowestNodeClasses.
+func (t *nodeClassAvailabilityTracker) slowestNodeClasses(ds *appsv1beta1.DaemonSet, revision string) []appsv1beta1.DaemonSetNodeClassAvailability {
+	t.Lock()
+	defer t.Unlock()
+	record := t.daemonSets[keyFunc(ds)]
+	if record == nil || record.revision != revision || len(record.nodeClasses) == 0 {
+		return nil
+	}
+
+	result := make([]appsv1beta1.DaemonSetNodeClassAvailability, 0, len(record.nodeClasses))
+	for nodeClass, stat := range record.nodeClasses {
+		result = append(result, appsv1beta1.DaemonSetNodeClassAvailability{
+			NodeClass:      nodeClass,
+			Nodes:          stat.nodes,
+			AverageSeconds: int32(math.Round(stat.totalSeconds / float64(stat.nodes))),
+			MaxSeconds:     int32(math.Round(stat.maxSeconds)),
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].AverageSeconds != result[j].AverageSeconds {
+			return result[i].AverageSeconds > result[j].AverageSeconds
+		}
+		if result[i].MaxSeconds != result[j].MaxSeconds {
+			return result[i].MaxSeconds > result[j].MaxSeconds
+		}
+		return result[i].NodeClass < result[j].NodeClass
+	})
+	if len(result) > maxSlowestNodeClasses {
+		result = result[:maxSlowestNodeClasses]
+	}
+	return result
+}
+
+// delete drops the records and metrics of a deleted DaemonSet.
+func (t *nodeClassAvailabilityTracker) delete(namespace, name string) {
+	t.Lock()
+	defer t.Unlock()
+	delete(t.daemonSets, types.NamespacedName{Namespace: namespace, Name: name}.String())
+	DaemonPodAvailableDurationMetrics.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "name": name})
+}
+
+func getNodeClass(node *corev1.Node) string {
+	if nodeClass := node.Labels[nodeClassLabelKey]; nodeClass != "" {
+		return nodeClass
+	}
+	return unknownNodeClass
+}
+
+// getDaemonPodAvailableTime returns the time when the pod became available, which is minReadySeconds
+// after the last transition of its Ready condition.
+func getDaemonPodAvailableTime(pod *corev1.Pod, minReadySeconds int32) (time.Time, bool) {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			if c.Status != corev1.ConditionTrue || c.LastTransitionTime.IsZero() {
+				return time.Time{}, false
+			}
+			return c.LastTransitionTime.Add(time.Duration(minReadySeconds) * time.Second), true
+		}
+	}
+	return time.Time{}, false
+}
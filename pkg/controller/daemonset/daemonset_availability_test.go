@@ -0,0 +1,204 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemonset
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+)
+
+// newPodWithTimeline returns a daemon pod created at createdAt and became ready readyAfter later.
+func newPodWithTimeline(name string, createdAt time.Time, readyAfter time.Duration) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         metav1.NamespaceDefault,
+			UID:               types.UID(name),
+			CreationTimestamp: metav1.NewTime(createdAt),
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			Conditions: []corev1.PodCondition{
+				{
+					Type:               corev1.PodReady,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: metav1.NewTime(createdAt.Add(readyAfter)),
+				},
+			},
+		},
+	}
+}
+
+func newNodeWithClass(name, nodeClass string) *corev1.Node {
+	node := newNode(name, nil)
+	if nodeClass != "" {
+		node.Labels = map[string]string{nodeClassLabelKey: nodeClass}
+	}
+	return node
+}
+
+func TestNodeClassAvailabilityTracker(t *testing.T) {
+	DaemonPodAvailableDurationMetrics.Reset()
+	ds := newDaemonSet("availability")
+	ds.Spec.MinReadySeconds = 10
+	start := time.Now().Add(-time.Hour)
+
+	timelines := []struct {
+		node       *corev1.Node
+		readyAfter time.Duration
+	}{
+		{node: newNodeWithClass("node-1", "ssd"), readyAfter: 20 * time.Second},
+		{node: newNodeWithClass("node-2", "ssd"), readyAfter: 30 * time.Second},
+		{node: newNodeWithClass("node-3", "hdd"), readyAfter: 230 * time.Second},
+		{node: newNodeWithClass("node-4", "hdd"), readyAfter: 290 * time.Second},
+		{node: newNodeWithClass("node-5", ""), readyAfter: 50 * time.Second},
+	}
+
+	tracker := newNodeClassAvailabilityTracker()
+	defer tracker.delete(ds.Namespace, ds.Name)
+	for i := range timelines {
+		pod := newPodWithTimeline(fmt.Sprintf("pod-%d", i), start, timelines[i].readyAfter)
+		tracker.observe(ds, "rev-1", timelines[i].node, pod)
+		// the same pod is recorded only once
+		tracker.observe(ds, "rev-1", timelines[i].node, pod)
+	}
+	// pod not ready yet is ignored
+	notReady := newPodWithTimeline("pod-not-ready", start, 0)
+	notReady.Status.Conditions[0].Status = corev1.ConditionFalse
+	tracker.observe(ds, "rev-1", newNodeWithClass("node-6", "ssd"), notReady)
+
+	expected := []appsv1beta1.DaemonSetNodeClassAvailability{
+		{NodeClass: "hdd", Nodes: 2, AverageSeconds: 270, MaxSeconds: 300},
+		{NodeClass: unknownNodeClass, Nodes: 1, AverageSeconds: 60, MaxSeconds: 60},
+		{NodeClass: "ssd", Nodes: 2, AverageSeconds: 35, MaxSeconds: 40},
+	}
+	if got := tracker.slowestNodeClasses(ds, "rev-1"); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected slowest node classes %+v, got %+v", expected, got)
+	}
+	if got := testutil.CollectAndCount(DaemonPodAvailableDurationMetrics); got != 3 {
+		t.Fatalf("expected 3 histograms, got %d", got)
+	}
+
+	// records of the old revision are not reported
+	if got := tracker.slowestNodeClasses(ds, "rev-2"); got != nil {
+		t.Fatalf("expected no node classes for a new revision, got %+v", got)
+	}
+	tracker.observe(ds, "rev-2", newNodeWithClass("node-1", "ssd"), newPodWithTimeline("pod-new", start, 5*time.Second))
+	expected = []appsv1beta1.DaemonSetNodeClassAvailability{
+		{NodeClass: "ssd", Nodes: 1, AverageSeconds: 15, MaxSeconds: 15},
+	}
+	if got := tracker.slowestNodeClasses(ds, "rev-2"); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected slowest node classes %+v, got %+v", expected, got)
+	}
+
+	tracker.delete(ds.Namespace, ds.Name)
+	if got := tracker.slowestNodeClasses(ds, "rev-2"); got != nil {
+		t.Fatalf("expected no node classes after deletion, got %+v", got)
+	}
+	if got := testutil.CollectAndCount(DaemonPodAvailableDurationMetrics); got != 0 {
+		t.Fatalf("expected no histograms after deletion, got %d", got)
+	}
+}
+
+func TestNodeClassAvailabilityTrackerBounded(t *testing.T) {
+	DaemonPodAvailableDurationMetrics.Reset()
+	ds := newDaemonSet("availability-bounded")
+	start := time.Now().Add(-time.Hour)
+
+	tracker := newNodeClassAvailabilityTracker()
+	defer tracker.delete(ds.Namespace, ds.Name)
+	for i := 0; i < maxNodeClassesPerDaemonSet+10; i++ {
+		node := newNodeWithClass(fmt.Sprintf("node-%d", i), fmt.Sprintf("class-%03d", i))
+		pod := newPodWithTimeline(fmt.Sprintf("pod-%d", i), start, time.Duration(i+1)*time.Second)
+		tracker.observe(ds, "rev-1", node, pod)
+	}
+
+	got := tracker.slowestNodeClasses(ds, "rev-1")
+	if len(got) != maxSlowestNodeClasses {
+		t.Fatalf("expected %d node classes, got %+v", maxSlowestNodeClasses, got)
+	}
+	// the nodes beyond the bound are folded into one class
+	if got[0].NodeClass != otherNodeClass || got[0].Nodes != 10 {
+		t.Fatalf("expected the slowest node class is %s with 10 nodes, got %+v", otherNodeClass, got[0])
+	}
+	if got[1].NodeClass != fmt.Sprintf("class-%03d", maxNodeClassesPerDaemonSet-1) {
+		t.Fatalf("unexpected second slowest node class %+v", got[1])
+	}
+	if count := testutil.CollectAndCount(DaemonPodAvailableDurationMetrics); count != maxNodeClassesPerDaemonSet+1 {
+		t.Fatalf("expected %d histograms, got %d", maxNodeClassesPerDaemonSet+1, count)
+	}
+}
+
+func TestUpdateDaemonSetStatusSlowestNodeClasses(t *testing.T) {
+	ds := newDaemonSet("foo")
+	manager, _, _, err := newTestController(ds)
+	if err != nil {
+		t.Fatalf("error creating DaemonSets controller: %v", err)
+	}
+	defer availabilityTracker.delete(ds.Namespace, ds.Name)
+	if err = manager.dsStore.Add(ds); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().Add(-time.Hour)
+	var nodeList []*corev1.Node
+	for i, readyAfter := range []time.Duration{10 * time.Second, 20 * time.Second, 300 * time.Second} {
+		nodeClass := "ssd"
+		if i == 2 {
+			nodeClass = "hdd"
+		}
+		node := newNodeWithClass(fmt.Sprintf("node-%d", i), nodeClass)
+		nodeList = append(nodeList, node)
+		if err = manager.nodeStore.Add(node); err != nil {
+			t.Fatal(err)
+		}
+		pod := newPod("foo-", node.Name, simpleDaemonSetLabel, ds)
+		pod.UID = types.UID(pod.Name)
+		pod.CreationTimestamp = metav1.NewTime(start)
+		pod.Status = newPodWithTimeline(pod.Name, start, readyAfter).Status
+		if err = manager.podStore.Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hash := newPod("foo-", "", simpleDaemonSetLabel, ds).Labels[apps.DefaultDaemonSetUniqueLabelKey]
+	if err = manager.updateDaemonSetStatus(context.TODO(), ds, nodeList, hash, true); err != nil {
+		t.Fatalf("failed to update status: %v", err)
+	}
+	updated, err := manager.kruiseClient.AppsV1beta1().DaemonSets(ds.Namespace).Get(context.TODO(), ds.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []appsv1beta1.DaemonSetNodeClassAvailability{
+		{NodeClass: "hdd", Nodes: 1, AverageSeconds: 300, MaxSeconds: 300},
+		{NodeClass: "ssd", Nodes: 2, AverageSeconds: 15, MaxSeconds: 20},
+	}
+	if !reflect.DeepEqual(updated.Status.SlowestNodeClasses, expected) {
+		t.Fatalf("expected slowest node classes %+v, got %+v", expected, updated.Status.SlowestNodeClasses)
+	}
+}
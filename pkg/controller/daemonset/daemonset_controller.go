@@ -18,10 +18,14 @@ limitations under the License.
 package daemonset
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"reflect"
 	"sort"
 	"sync"
@@ -36,8 +40,10 @@ import (
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/apimachinery/pkg/util/wait"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
 	clientset "k8s.io/client-go/kubernetes"
 	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
 	appslisters "k8s.io/client-go/listers/apps/v1"
@@ -58,6 +64,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -75,6 +82,7 @@ import (
 	utildiscovery "github.com/openkruise/kruise/pkg/util/discovery"
 	kruiseExpectations "github.com/openkruise/kruise/pkg/util/expectations"
 	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+	historyutil "github.com/openkruise/kruise/pkg/util/history"
 	imagejobutilfunc "github.com/openkruise/kruise/pkg/util/imagejob/utilfunction"
 	"github.com/openkruise/kruise/pkg/util/inplaceupdate"
 	"github.com/openkruise/kruise/pkg/util/lifecycle"
@@ -86,11 +94,25 @@ import (
 func init() {
 	flag.BoolVar(&scheduleDaemonSetPods, "assign-pods-by-scheduler", true, "Use scheduler to assign pod to node.")
 	flag.IntVar(&concurrentReconciles, "daemonset-workers", concurrentReconciles, "Max concurrent workers for DaemonSet controller.")
+	flag.StringVar(&nodeClassLabelKey, "daemonset-node-class-label", nodeClassLabelKey,
+		"The node label to group nodes into classes for the availability duration metrics of daemon pods.")
+	flag.IntVar(&nodeWorkers, "daemonset-node-workers", nodeWorkers, "Max concurrent workers to evaluate the nodes within a sync of DaemonSet.")
+	flag.IntVar(&renderFailureThreshold, "daemonset-patch-render-failure-threshold", renderFailureThreshold,
+		"The number of consecutive failures to render spec.patches across nodes that pauses creating the patched pods of DaemonSet, 0 to disable.")
+	flag.DurationVar(&renderBreakerInitialBackoff, "daemonset-patch-render-initial-backoff", renderBreakerInitialBackoff,
+		"The initial backoff to resume creating the patched pods of DaemonSet after paused for failures to render spec.patches.")
+	flag.DurationVar(&renderBreakerMaxBackoff, "daemonset-patch-render-max-backoff", renderBreakerMaxBackoff,
+		"The max backoff to resume creating the patched pods of DaemonSet after paused for failures to render spec.patches.")
+	// register prometheus
+	metrics.Registry.MustRegister(DaemonPodAvailableDurationMetrics)
+	metrics.Registry.MustRegister(DaemonSetPatchRenderErrorsMetrics)
 }
 
 var (
 	concurrentReconciles  = 3
 	scheduleDaemonSetPods bool
+	// nodeWorkers is the max number of goroutines to evaluate the nodes within a sync of one DaemonSet.
+	nodeWorkers = 16
 
 	// controllerKind contains the schema.GroupVersionKind for this controller type.
 	controllerKind = appsv1beta1.SchemeGroupVersion.WithKind("DaemonSet")
@@ -112,6 +134,21 @@ const (
 	// This annotation will be added to DaemonSet when it is created, and removed if partition is set to 0.
 	ProgressiveCreatePod = "daemonset.kruise.io/progressive-create-pod"
 
+	// RenderHashAnnotation records the hash of pod template rendered with patches for the node of daemon pod.
+	// It is recorded even if the DaemonSet has no patches, and the pods without it are regarded as rendered
+	// from spec.template of their revision without any patches.
+	RenderHashAnnotation = "daemonset.kruise.io/render-hash"
+	// ShadowRenderHashAnnotation records the hash of pod template rendered with the shadow patches as well for the
+	// node of daemon pod, which can be compared with the render hash before the shadow patches promoted.
+	ShadowRenderHashAnnotation = "daemonset.kruise.io/shadow-render-hash"
+	// ShadowRenderDiffAnnotation records the strategic merge patch from the pod template rendered for the node of
+	// daemon pod to the one rendered with the shadow patches as well.
+	ShadowRenderDiffAnnotation = "daemonset.kruise.io/shadow-render-diff"
+	// RevisionPatchesHashAnnotation records the hash of the DaemonSet patches that the pods of a ControllerRevision
+	// are rendered with. The revisions recorded before the patches were mutable don't record the patches in their
+	// data, and they are annotated with the patches of the DaemonSet when adopted, so that their pods are kept.
+	RevisionPatchesHashAnnotation = "daemonset.kruise.io/revision-patches-hash"
+
 	// BackoffGCInterval is the time that has to pass before next iteration of backoff GC is run
 	BackoffGCInterval = 1 * time.Minute
 )
@@ -124,6 +161,10 @@ const (
 	FailedPlacementReason = "FailedPlacement"
 	// FailedDaemonPodReason is added to an event when the status of a Pod of a DaemonSet is 'Failed'.
 	FailedDaemonPodReason = "FailedDaemonPod"
+	// FailedRenderPatchesReason is added to an event when spec.patches can not be applied to the pod template.
+	FailedRenderPatchesReason = "FailedRenderPatches"
+	// PatchAppliedReason is added to an event when a daemon pod is created with spec.patches rendered into its template.
+	PatchAppliedReason = "PatchApplied"
 )
 
 /**
@@ -201,6 +242,8 @@ func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
 		podLister:                   podLister,
 		nodeLister:                  nodeLister,
 		failedPodsBackoff:           failedPodsBackoff,
+		renderRetryBackoff:          flowcontrol.NewBackOff(renderRetryInitialBackoff, maxRenderRetryBackoff),
+		renderBreaker:               newRenderCircuitBreaker(flowcontrol.NewBackOff(renderBreakerInitialBackoff, renderBreakerMaxBackoff)),
 		inplaceControl:              inplaceupdate.New(cli, revisionAdapter),
 		revisionAdapter:             revisionAdapter,
 	}
@@ -293,6 +336,10 @@ type ReconcileDaemonSet struct {
 	nodeLister corelisters.NodeLister
 
 	failedPodsBackoff *flowcontrol.Backoff
+	// renderRetryBackoff limits the retries of rendering pod template for nodes after transient errors
+	renderRetryBackoff *flowcontrol.Backoff
+	// renderBreaker pauses creating the patched pods after rendering spec.patches failed on too many nodes
+	renderBreaker *renderCircuitBreaker
 
 	inplaceControl  inplaceupdate.Interface
 	revisionAdapter revisionadapter.Interface
@@ -313,6 +360,8 @@ type ReconcileDaemonSet struct {
 func (dsc *ReconcileDaemonSet) Reconcile(ctx context.Context, request reconcile.Request) (res reconcile.Result, retErr error) {
 	onceBackoffGC.Do(func() {
 		go wait.Until(dsc.failedPodsBackoff.GC, BackoffGCInterval, ctx.Done())
+		go wait.Until(dsc.renderRetryBackoff.GC, BackoffGCInterval, ctx.Done())
+		go wait.Until(dsc.renderBreaker.backoff.GC, BackoffGCInterval, ctx.Done())
 	})
 	startTime := time.Now()
 	defer func() {
@@ -376,6 +425,8 @@ func (dsc *ReconcileDaemonSet) syncDaemonSet(ctx context.Context, request reconc
 		if errors.IsNotFound(err) {
 			klog.V(4).InfoS("DaemonSet has been deleted", "daemonSet", request)
 			dsc.expectations.DeleteExpectations(logger, dsKey)
+			availabilityTracker.delete(request.Namespace, request.Name)
+			dsc.renderBreaker.forget(dsKey)
 			return nil
 		}
 		return fmt.Errorf("unable to retrieve DaemonSet %s from store: %v", dsKey, err)
@@ -455,6 +506,11 @@ func (dsc *ReconcileDaemonSet) syncDaemonSet(ctx context.Context, request reconc
 	if !isDaemonSetPaused(ds) {
 		switch ds.Spec.UpdateStrategy.Type {
 		case appsv1beta1.OnDeleteDaemonSetStrategyType:
+			// Advanced: roll out the nodes whose update strategy is overridden to RollingUpdate by patches
+			if !hasPatchUpdateStrategyType(ds, appsv1beta1.RollingUpdateDaemonSetStrategyType) {
+				break
+			}
+			fallthrough
 		case appsv1beta1.RollingUpdateDaemonSetStrategyType:
 			err = dsc.rollingUpdate(ctx, ds, nodeList, cur, old)
 			if err != nil {
@@ -513,13 +569,13 @@ func NewPod(ds *appsv1beta1.DaemonSet, nodeName string, node *corev1.Node) *core
 		Spec:       ds.Spec.Template.Spec,
 	}
 
-	// Apply patches if node information is available
-	if node != nil && len(ds.Spec.Patches) > 0 {
-		patchedTemplate, err := applyPatchesToPodTemplate(ds, node, template)
+	// Apply patches and the registered renderer if node information is available
+	if node != nil {
+		renderedTemplate, err := renderPodTemplate(ds, node, template)
 		if err != nil {
-			klog.ErrorS(err, "Failed to apply patches to pod template", "daemonSet", klog.KObj(ds), "nodeName", nodeName)
+			klog.ErrorS(err, "Failed to render pod template", "daemonSet", klog.KObj(ds), "nodeName", nodeName)
 		} else {
-			template = patchedTemplate
+			template = renderedTemplate
 		}
 	}
 
@@ -535,39 +591,159 @@ func NewPod(ds *appsv1beta1.DaemonSet, nodeName string, node *corev1.Node) *core
 	return newPod
 }
 
-// applyPatchesToPodTemplate applies node label patches to the pod template
+// applyPatchesToPodTemplate applies node label patches to the pod template, except the shadow patches.
 func applyPatchesToPodTemplate(
 	ds *appsv1beta1.DaemonSet,
 	node *corev1.Node,
 	template *corev1.PodTemplateSpec,
 ) (*corev1.PodTemplateSpec, error) {
-	if len(ds.Spec.Patches) == 0 {
+	return applyPatchesToPodTemplateWithShadow(ds, node, template, false)
+}
+
+// applyPatchesToPodTemplateWithShadow applies node label patches to the pod template, and the shadow patches
+// are applied as well if shadow is true.
+func applyPatchesToPodTemplateWithShadow(
+	ds *appsv1beta1.DaemonSet,
+	node *corev1.Node,
+	template *corev1.PodTemplateSpec,
+	shadow bool,
+) (*corev1.PodTemplateSpec, error) {
+	if len(ds.Spec.Patches) == 0 || !utilfeature.DefaultFeatureGate.Enabled(features.DaemonSetPatches) {
 		return template, nil
 	}
 
-	// Sort patches by priority (higher priority first)
-	patches := make([]appsv1beta1.DaemonSetPatch, len(ds.Spec.Patches))
-	copy(patches, ds.Spec.Patches)
-	sort.Slice(patches, func(i, j int) bool {
-		return patches[i].Priority > patches[j].Priority
-	})
+	// Each patch is applied strictly on top of the result of the ones before it.
+	indexes := sortedPatchIndexes(ds)
 
 	patchedTemplate := template.DeepCopy()
 
 	// Apply matching patches
-	for _, patch := range patches {
-		if matchesNodeSelector(node, patch.Selector) {
-			patched, err := applyStrategicMergePatch(patchedTemplate, patch.Patch.Raw)
-			if err != nil {
-				return nil, err
-			}
-			patchedTemplate = patched
+	var applied bool
+	for _, i := range indexes {
+		patch := &ds.Spec.Patches[i]
+		if patch.Shadow && !shadow {
+			continue
+		}
+		patched, matched, err := ApplySinglePatch(patchedTemplate, patch, node)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply spec.patches[%d] with %s %d: %w", i, getPatchOrderBy(ds), patchSortKey(ds, patch), err)
 		}
+		if !matched {
+			continue
+		}
+		patchedTemplate = patched
+		applied = true
 	}
 
+	if applied {
+		if err := validatePatchedPodTemplate(ds, patchedTemplate); err != nil {
+			return nil, &patchRenderError{reason: patchRenderErrorValidation, err: fmt.Errorf("invalid pod template after applying spec.patches: %v", err)}
+		}
+	}
 	return patchedTemplate, nil
 }
 
+// ApplySinglePatch applies one patch of spec.patches to the pod template if the node matches its selector,
+// and returns the patched template with whether the node matches. The template is returned as is if the node
+// does not match. It is what the controller applies for each of spec.patches in order, so that a patch can be
+// tested in isolation, regardless of its priority, whether it is shadow, and the DaemonSetPatches feature gate.
+func ApplySinglePatch(template *corev1.PodTemplateSpec, patch *appsv1beta1.DaemonSetPatch, node *corev1.Node) (*corev1.PodTemplateSpec, bool, error) {
+	if result := ExplainMatch(patch, node); !result.Matched {
+		klog.V(6).InfoS("Node does not match patch", "node", node.Name, "requirement", result.FailedRequirement, "reason", result.Reason)
+		return template, false, nil
+	}
+	patchData, err := decompressPatch(patch.Patch.Raw, patch.PatchEncoding)
+	if err != nil {
+		return nil, true, &patchRenderError{reason: patchRenderErrorDecode, err: err}
+	}
+	patched, err := applyStrategicMergePatch(template, patchData)
+	if err != nil {
+		return nil, true, err
+	}
+	return patched, true, nil
+}
+
+// sortedPatchIndexes returns the indexes of spec.patches in the order they are applied. Patches are sorted
+// by priority (lower priority first) by default, so that patches with higher priority are applied later and
+// override the lower ones, or by order if specified.
+func sortedPatchIndexes(ds *appsv1beta1.DaemonSet) []int {
+	indexes := make([]int, len(ds.Spec.Patches))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return patchSortKey(ds, &ds.Spec.Patches[indexes[i]]) < patchSortKey(ds, &ds.Spec.Patches[indexes[j]])
+	})
+	return indexes
+}
+
+func patchSortKey(ds *appsv1beta1.DaemonSet, patch *appsv1beta1.DaemonSetPatch) int32 {
+	if ds.Spec.PatchOrderBy == appsv1beta1.OrderDaemonSetPatchOrderBy {
+		return patch.Order
+	}
+	return patch.Priority
+}
+
+func getPatchOrderBy(ds *appsv1beta1.DaemonSet) appsv1beta1.DaemonSetPatchOrderByType {
+	if ds.Spec.PatchOrderBy == "" {
+		return appsv1beta1.PriorityDaemonSetPatchOrderBy
+	}
+	return ds.Spec.PatchOrderBy
+}
+
+// validatePatchedPodTemplate checks the pod template is still valid for the DaemonSet after the patches applied,
+// which the patches are able to break.
+func validatePatchedPodTemplate(ds *appsv1beta1.DaemonSet, template *corev1.PodTemplateSpec) error {
+	if len(template.Spec.Containers) == 0 {
+		return fmt.Errorf("no container left")
+	}
+	names := sets.New[string]()
+	for _, c := range append(template.Spec.InitContainers, template.Spec.Containers...) {
+		if c.Name == "" {
+			return fmt.Errorf("container name is empty")
+		} else if names.Has(c.Name) {
+			return fmt.Errorf("duplicated container name %s", c.Name)
+		} else if c.Image == "" {
+			return fmt.Errorf("container %s has no image", c.Name)
+		}
+		names.Insert(c.Name)
+	}
+	if ds.Spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(ds.Spec.Selector)
+		if err != nil {
+			return err
+		}
+		if !selector.Matches(labels.Set(template.Labels)) {
+			return fmt.Errorf("labels %v do not match the selector", template.Labels)
+		}
+	}
+	return nil
+}
+
+// RenderPodTemplateForNode returns spec.template of the DaemonSet with the patches matching the node applied
+// and post-processed by the registered TemplateRenderer, which is the pod template that the daemon pod on
+// this node will be created with.
+func RenderPodTemplateForNode(ds *appsv1beta1.DaemonSet, node *corev1.Node) (*corev1.PodTemplateSpec, error) {
+	patchedTemplate, err := applyPatchesToPodTemplate(ds, node, &ds.Spec.Template)
+	if err != nil {
+		return nil, err
+	}
+	return renderPatchedPodTemplate(node, patchedTemplate)
+}
+
+// PodTemplateShouldRunOnNode returns true if the daemon pod can be scheduled to the node, according to the nodeName,
+// nodeSelector, affinity and tolerations of the pod template rendered for the node. Unlike NewPod, the pod is not
+// cached, so that it can be called with a DaemonSet that has not been persisted, e.g., in webhooks.
+func PodTemplateShouldRunOnNode(template *corev1.PodTemplateSpec, node *corev1.Node) bool {
+	if !(template.Spec.NodeName == "" || template.Spec.NodeName == node.Name) {
+		return false
+	}
+	pod := &corev1.Pod{ObjectMeta: *template.ObjectMeta.DeepCopy(), Spec: *template.Spec.DeepCopy()}
+	util.AddOrUpdateDaemonPodTolerations(&pod.Spec)
+	fitsNodeName, fitsNodeAffinity, fitsTaints := Predicates(pod, node, node.Spec.Taints)
+	return fitsNodeName && fitsNodeAffinity && fitsTaints
+}
+
 // matchesNodeSelector checks if node labels match the selector
 func matchesNodeSelector(node *corev1.Node, selector *metav1.LabelSelector) bool {
 	if selector == nil {
@@ -582,82 +758,240 @@ func matchesNodeSelector(node *corev1.Node, selector *metav1.LabelSelector) bool
 	return selectorInstance.Matches(labels.Set(node.Labels))
 }
 
+// DecodePatch returns the JSON of the patch in spec.patches. Besides a JSON object, the patch can also be
+// a string of YAML document, which is convenient to write in manifests as a block scalar.
+func DecodePatch(patchData []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(patchData)
+	if len(trimmed) == 0 || trimmed[0] != '"' {
+		return patchData, nil
+	}
+	var document string
+	if err := json.Unmarshal(trimmed, &document); err != nil {
+		return nil, err
+	}
+	patchJSON, err := utilyaml.ToJSON([]byte(document))
+	if err != nil {
+		return nil, fmt.Errorf("invalid YAML patch: %v", err)
+	}
+	return patchJSON, nil
+}
+
+// maxDecompressedPatchSize limits the size of a compressed patch after decompression.
+const maxDecompressedPatchSize = 1 << 20
+
+// DecodePatchWithEncoding returns the JSON of the patch in spec.patches, which is decompressed first
+// if it is compressed by the encoding.
+func DecodePatchWithEncoding(patchData []byte, encoding appsv1beta1.DaemonSetPatchEncodingType) ([]byte, error) {
+	patchData, err := decompressPatch(patchData, encoding)
+	if err != nil {
+		return nil, err
+	}
+	return DecodePatch(patchData)
+}
+
+// decompressPatch returns the JSON of the patch compressed by the encoding, or the patch itself if it is not compressed.
+func decompressPatch(patchData []byte, encoding appsv1beta1.DaemonSetPatchEncodingType) ([]byte, error) {
+	switch encoding {
+	case "":
+		return patchData, nil
+	case appsv1beta1.GzipDaemonSetPatchEncoding:
+	default:
+		return nil, fmt.Errorf("unsupported patch encoding %q", encoding)
+	}
+	var encoded string
+	if err := json.Unmarshal(patchData, &encoded); err != nil {
+		return nil, fmt.Errorf("gzip patch must be a base64 encoded string: %v", err)
+	}
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 of gzip patch: %v", err)
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip patch: %v", err)
+	}
+	defer reader.Close()
+	document, err := io.ReadAll(io.LimitReader(reader, maxDecompressedPatchSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip patch: %v", err)
+	}
+	if len(document) > maxDecompressedPatchSize {
+		return nil, fmt.Errorf("gzip patch exceeds %d bytes after decompression", maxDecompressedPatchSize)
+	}
+	patchJSON, err := utilyaml.ToJSON(document)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip patch: %v", err)
+	}
+	return patchJSON, nil
+}
+
 // applyStrategicMergePatch applies strategic merge patch to pod template
 func applyStrategicMergePatch(template *corev1.PodTemplateSpec, patchData []byte) (*corev1.PodTemplateSpec, error) {
 	if len(patchData) == 0 {
 		return template, nil
 	}
+	patchData, err := DecodePatch(patchData)
+	if err != nil {
+		return nil, &patchRenderError{reason: patchRenderErrorDecode, err: err}
+	}
+	// A JSON patch is a list of operations, which would be reported as an invalid document by strategic merge.
+	if trimmed := bytes.TrimSpace(patchData); len(trimmed) > 0 && trimmed[0] == '[' {
+		return nil, &patchRenderError{reason: patchRenderErrorDecode, err: fmt.Errorf("JSON patch is not supported, the patch must be a strategic merge patch")}
+	}
+	var patchMap map[string]interface{}
+	if err := json.Unmarshal(patchData, &patchMap); err != nil {
+		return nil, &patchRenderError{reason: patchRenderErrorDecode, err: err}
+	}
 
-	// Convert template to JSON
-	templateJSON, err := json.Marshal(template)
+	// Convert template and patch to the canonical JSON, which is the same as revision history and webhook use
+	templateJSON, err := kruiseutil.CanonicalJSON(template)
 	if err != nil {
-		return nil, err
+		return nil, &patchRenderError{reason: patchRenderErrorMerge, err: err}
+	}
+	patchJSON, err := kruiseutil.CanonicalJSON(patchData)
+	if err != nil {
+		return nil, &patchRenderError{reason: patchRenderErrorDecode, err: err}
 	}
 
 	// Apply strategic merge patch
-	patchedJSON, err := strategicpatch.StrategicMergePatch(templateJSON, patchData, &corev1.PodTemplateSpec{})
+	schema, err := lookupPodTemplatePatchMeta()
 	if err != nil {
 		return nil, err
 	}
+	patchedJSON, err := strategicpatch.StrategicMergePatchUsingLookupPatchMeta(templateJSON, patchJSON, schema)
+	if err != nil {
+		return nil, &patchRenderError{reason: patchRenderErrorMerge, err: err}
+	}
 
 	// Convert back to PodTemplateSpec
 	var patchedTemplate corev1.PodTemplateSpec
 	if err := json.Unmarshal(patchedJSON, &patchedTemplate); err != nil {
-		return nil, err
+		return nil, &patchRenderError{reason: patchRenderErrorMerge, err: err}
 	}
 
 	return &patchedTemplate, nil
 }
 
+// podTemplatePatchMetaFunc returns the strategic merge schema of pod template, which is replaced in tests.
+var podTemplatePatchMetaFunc = func() (strategicpatch.LookupPatchMeta, error) {
+	return strategicpatch.NewPatchMetaFromStruct(&corev1.PodTemplateSpec{})
+}
+
+// lookupPodTemplatePatchMeta returns the strategic merge schema of pod template. Without the patch strategies
+// in the schema, lists such as containers would be replaced by the patch instead of merged by their keys, so
+// the schema has to resolve the merge key of spec.containers, or the patches are not applied at all.
+func lookupPodTemplatePatchMeta() (strategicpatch.LookupPatchMeta, error) {
+	schema, err := podTemplatePatchMetaFunc()
+	if err == nil && schema == nil {
+		err = fmt.Errorf("schema is empty")
+	}
+	if err == nil {
+		var specSchema strategicpatch.LookupPatchMeta
+		var containersMeta strategicpatch.PatchMeta
+		if specSchema, _, err = schema.LookupPatchMetadataForStruct("spec"); err == nil {
+			if _, containersMeta, err = specSchema.LookupPatchMetadataForSlice("containers"); err == nil && containersMeta.GetPatchMergeKey() != "name" {
+				err = fmt.Errorf("merge key of spec.containers is %q instead of \"name\"", containersMeta.GetPatchMergeKey())
+			}
+		}
+	}
+	if err != nil {
+		return nil, &patchRenderError{reason: patchRenderErrorSchema, err: fmt.Errorf("failed to look up the strategic merge schema of pod template: %v", err)}
+	}
+	return schema, nil
+}
+
 func (dsc *ReconcileDaemonSet) updateDaemonSetStatus(ctx context.Context, ds *appsv1beta1.DaemonSet, nodeList []*corev1.Node, hash string, updateObservedGen bool) error {
 	nodeToDaemonPods, err := dsc.getNodesToDaemonPods(ctx, ds)
 	if err != nil {
 		return fmt.Errorf("couldn't get node to daemon pod mapping for DaemonSet %q: %v", ds.Name, err)
 	}
 
-	var desiredNumberScheduled, currentNumberScheduled, numberMisscheduled, numberReady, updatedNumberScheduled, numberAvailable int
+	// If the returned error is not nil we have a parse error.
+	// The controller handles this via the hash.
+	generation, err := GetTemplateGeneration(ds)
+	if err != nil {
+		generation = nil
+	}
+
+	// Evaluate the nodes in parallel, then aggregate the results in the order of nodes.
+	type nodeStatus struct {
+		shouldRun, scheduled, ready, available bool
+		updatedPod                             *corev1.Pod
+	}
+	nodeStatuses := make([]nodeStatus, len(nodeList))
 	now := dsc.failedPodsBackoff.Clock.Now()
-	for _, node := range nodeList {
-		shouldRun, _ := nodeShouldRunDaemonPod(node, ds)
-		scheduled := len(nodeToDaemonPods[node.Name]) > 0
+	parallelizeNodes(ctx, len(nodeList), func(i int) {
+		node, status := nodeList[i], &nodeStatuses[i]
+		status.shouldRun, _ = nodeShouldRunDaemonPod(node, ds)
+		daemonPods := nodeToDaemonPods[node.Name]
+		status.scheduled = len(daemonPods) > 0
+		if !status.shouldRun || !status.scheduled {
+			return
+		}
+		// Sort the daemon pods by creation time, so that the oldest is first.
+		sort.Sort(podByCreationTimestampAndPhase(daemonPods))
+		pod := daemonPods[0]
+		if podutil.IsPodReady(pod) {
+			status.ready = true
+			status.available = isDaemonPodAvailable(pod, ds.Spec.MinReadySeconds, metav1.Time{Time: now})
+		}
+		if util.IsPodUpdated(pod, hash, generation) {
+			status.updatedPod = pod
+		}
+	})
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
-		if shouldRun {
+	var desiredNumberScheduled, currentNumberScheduled, numberMisscheduled, numberReady, updatedNumberScheduled, numberAvailable int
+	patchStatuses := newPatchStatuses(ds)
+	for i, node := range nodeList {
+		status := &nodeStatuses[i]
+		if status.shouldRun {
 			desiredNumberScheduled++
-			if scheduled {
+			if status.scheduled {
 				currentNumberScheduled++
-				// Sort the daemon pods by creation time, so that the oldest is first.
-				daemonPods := nodeToDaemonPods[node.Name]
-				sort.Sort(podByCreationTimestampAndPhase(daemonPods))
-				pod := daemonPods[0]
-				if podutil.IsPodReady(pod) {
+				if status.ready {
 					numberReady++
-					if isDaemonPodAvailable(pod, ds.Spec.MinReadySeconds, metav1.Time{Time: now}) {
-						numberAvailable++
-					}
 				}
-				// If the returned error is not nil we have a parse error.
-				// The controller handles this via the hash.
-				generation, err := GetTemplateGeneration(ds)
-				if err != nil {
-					generation = nil
+				if status.available {
+					numberAvailable++
 				}
-				if util.IsPodUpdated(pod, hash, generation) {
+				if status.updatedPod != nil {
 					updatedNumberScheduled++
+					if status.available {
+						availabilityTracker.observe(ds, hash, node, status.updatedPod)
+					}
 				}
 			}
+			observePatchStatuses(ds, node, status.updatedPod, patchStatuses)
 		} else {
-			if scheduled {
+			if status.scheduled {
 				numberMisscheduled++
 			}
 		}
 	}
 	numberUnavailable := desiredNumberScheduled - numberAvailable
 
-	err = dsc.storeDaemonSetStatus(ctx, ds, desiredNumberScheduled, currentNumberScheduled, numberMisscheduled, numberReady, updatedNumberScheduled, numberAvailable, numberUnavailable, updateObservedGen, hash)
+	// surface the slowest node classes after the rollout completed, otherwise keep the ones of the last rollout
+	slowestNodeClasses := ds.Status.SlowestNodeClasses
+	if desiredNumberScheduled > 0 && updatedNumberScheduled == desiredNumberScheduled && numberAvailable == desiredNumberScheduled {
+		if nodeClasses := availabilityTracker.slowestNodeClasses(ds, hash); len(nodeClasses) > 0 {
+			slowestNodeClasses = nodeClasses
+		}
+	}
+
+	conditions := dsc.newPatchRenderConditions(ds)
+	err = dsc.storeDaemonSetStatus(ctx, ds, desiredNumberScheduled, currentNumberScheduled, numberMisscheduled, numberReady, updatedNumberScheduled, numberAvailable, numberUnavailable, updateObservedGen, hash, slowestNodeClasses, patchStatuses, conditions)
 	if err != nil {
 		return fmt.Errorf("error storing status for DaemonSet %v: %v", ds.Name, err)
 	}
 
+	// Resync the DaemonSet to resume creating the patched pods after the render circuit breaker backs off.
+	if delay, open := dsc.renderBreaker.isOpen(ds); open {
+		durationStore.Push(keyFunc(ds), delay)
+	}
+
 	// Resync the DaemonSet after MinReadySeconds as a last line of defense to guard against clock-skew.
 	if ds.Spec.MinReadySeconds >= 0 && numberReady != numberAvailable {
 		durationStore.Push(keyFunc(ds), time.Duration(ds.Spec.MinReadySeconds)*time.Second)
@@ -676,7 +1010,10 @@ func (dsc *ReconcileDaemonSet) storeDaemonSetStatus(
 	numberAvailable,
 	numberUnavailable int,
 	updateObservedGen bool,
-	hash string) error {
+	hash string,
+	slowestNodeClasses []appsv1beta1.DaemonSetNodeClassAvailability,
+	patchStatuses []appsv1beta1.DaemonSetPatchStatus,
+	conditions []apps.DaemonSetCondition) error {
 	if int(ds.Status.DesiredNumberScheduled) == desiredNumberScheduled &&
 		int(ds.Status.CurrentNumberScheduled) == currentNumberScheduled &&
 		int(ds.Status.NumberMisscheduled) == numberMisscheduled &&
@@ -685,7 +1022,10 @@ func (dsc *ReconcileDaemonSet) storeDaemonSetStatus(
 		int(ds.Status.NumberAvailable) == numberAvailable &&
 		int(ds.Status.NumberUnavailable) == numberUnavailable &&
 		ds.Status.ObservedGeneration >= ds.Generation &&
-		ds.Status.UpdateRevision == hash {
+		ds.Status.UpdateRevision == hash &&
+		reflect.DeepEqual(ds.Status.SlowestNodeClasses, slowestNodeClasses) &&
+		reflect.DeepEqual(ds.Status.PatchStatuses, patchStatuses) &&
+		reflect.DeepEqual(ds.Status.Conditions, conditions) {
 		return nil
 	}
 
@@ -704,6 +1044,9 @@ func (dsc *ReconcileDaemonSet) storeDaemonSetStatus(
 		toUpdate.Status.NumberAvailable = int32(numberAvailable)
 		toUpdate.Status.NumberUnavailable = int32(numberUnavailable)
 		toUpdate.Status.UpdateRevision = hash
+		toUpdate.Status.SlowestNodeClasses = slowestNodeClasses
+		toUpdate.Status.PatchStatuses = patchStatuses
+		toUpdate.Status.Conditions = conditions
 
 		if _, updateErr = dsClient.UpdateStatus(ctx, toUpdate, metav1.UpdateOptions{}); updateErr == nil {
 			klog.InfoS("Updated DaemonSet status", "daemonSet", klog.KObj(ds), "status", kruiseutil.DumpJSON(toUpdate.Status))
@@ -735,22 +1078,42 @@ func (dsc *ReconcileDaemonSet) manage(ctx context.Context, ds *appsv1beta1.Daemo
 
 	// For each node, if the node is running the daemon pod but isn't supposed to, kill the daemon
 	// pod. If the node is supposed to run the daemon pod, but isn't, create the daemon pod on the node.
+	// The nodes are evaluated in parallel, and the results are aggregated in the order of nodes.
+	type nodeResult struct {
+		nodesNeedingDaemonPods, podsToDelete []string
+		shouldRun, hasNewPod                 bool
+	}
+	nodeResults := make([]nodeResult, len(nodeList))
+	parallelizeNodes(ctx, len(nodeList), func(i int) {
+		node, result := nodeList[i], &nodeResults[i]
+		result.nodesNeedingDaemonPods, result.podsToDelete = dsc.podsShouldBeOnNode(node, nodeToDaemonPods, ds, hash)
+		result.shouldRun, _ = nodeShouldRunDaemonPod(node, ds)
+		newPod, _, ok := findUpdatedPodsOnNode(ds, nodeToDaemonPods[node.Name], hash)
+		result.hasNewPod = ok && newPod != nil
+	})
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	var nodesNeedingDaemonPods, podsToDelete []string
 	var nodesDesireScheduled, newPodCount int
-	for _, node := range nodeList {
-		nodesNeedingDaemonPodsOnNode, podsToDeleteOnNode := dsc.podsShouldBeOnNode(node, nodeToDaemonPods, ds, hash)
-
-		nodesNeedingDaemonPods = append(nodesNeedingDaemonPods, nodesNeedingDaemonPodsOnNode...)
-		podsToDelete = append(podsToDelete, podsToDeleteOnNode...)
-
-		if shouldRun, _ := nodeShouldRunDaemonPod(node, ds); shouldRun {
+	for i := range nodeResults {
+		result := &nodeResults[i]
+		nodesNeedingDaemonPods = append(nodesNeedingDaemonPods, result.nodesNeedingDaemonPods...)
+		podsToDelete = append(podsToDelete, result.podsToDelete...)
+		if result.shouldRun {
 			nodesDesireScheduled++
 		}
-		if newPod, _, ok := findUpdatedPodsOnNode(ds, nodeToDaemonPods[node.Name], hash); ok && newPod != nil {
+		if result.hasNewPod {
 			newPodCount++
 		}
 	}
 
+	// Advanced: record the pod template rendered with the shadow patches in the existing pods
+	if err := dsc.syncShadowRenders(ctx, ds, nodeList, nodeToDaemonPods); err != nil {
+		return fmt.Errorf("failed to sync shadow renders: %v", err)
+	}
+
 	// Remove unscheduled pods assigned to not existing nodes when daemonset pods are scheduled by scheduler.
 	// If node doesn't exist then pods are never scheduled and can't be deleted by PodGCController.
 	podsToDelete = append(podsToDelete, getUnscheduledPodsWithoutNode(nodeList, nodeToDaemonPods)...)
@@ -785,6 +1148,9 @@ func (dsc *ReconcileDaemonSet) syncNodes(ctx context.Context, ds *appsv1beta1.Da
 		}
 	}
 
+	// the patched pods are not created while rendering spec.patches keeps failing
+	nodesNeedingDaemonPods = dsc.filterNodesWithOpenRenderBreaker(ds, nodesNeedingDaemonPods)
+
 	logger := klog.FromContext(ctx)
 	dsKey := keyFunc(ds)
 	createDiff := len(nodesNeedingDaemonPods)
@@ -839,13 +1205,61 @@ func (dsc *ReconcileDaemonSet) syncNodes(ctx context.Context, ds *appsv1beta1.Da
 
 				podTemplate := util.CreatePodTemplate(ds.Spec.Template, generation, hash)
 
-				// Apply patches to pod template
+				// Apply patches and the registered renderer to pod template
+				var appliedPatches string
+				renderedTemplate, err := renderPodTemplate(ds, node, &podTemplate)
+				if err != nil {
+					if delay, retry := dsc.shouldRetryRender(ds, node.Name, err); retry {
+						klog.InfoS("Failed to render pod template transiently, will retry", "daemonSet", klog.KObj(ds), "nodeName", node.Name, "delay", delay, "err", err)
+						durationStore.Push(keyFunc(ds), delay)
+						dsc.expectations.CreationObserved(logger, dsKey)
+						return
+					}
+					// the patches can not be applied without the schema, so the pod is not created with a template
+					// that has none of the patches, until the schema is available
+					if IsPatchSchemaError(err) {
+						klog.ErrorS(err, "Failed to render pod template, skip creating pod", "daemonSet", klog.KObj(ds), "nodeName", node.Name)
+						dsc.expectations.CreationObserved(logger, dsKey)
+						errCh <- err
+						return
+					}
+					klog.ErrorS(err, "Failed to render pod template", "daemonSet", klog.KObj(ds), "nodeName", nodesNeedingDaemonPods[ix])
+					if dsc.renderBreaker.observe(ds, node, err) {
+						delay, _ := dsc.renderBreaker.isOpen(ds)
+						dsc.eventRecorder.Eventf(ds, corev1.EventTypeWarning, FailedRenderPatchesReason,
+							"paused creating pods on the nodes matched by spec.patches for %v after %d consecutive failures to render them: %v",
+							delay, renderFailureThreshold, err)
+					}
+					if _, open := dsc.renderBreaker.isOpen(ds); open {
+						dsc.expectations.CreationObserved(logger, dsKey)
+						return
+					}
+				} else {
+					podTemplate = *renderedTemplate
+					appliedPatches = describeAppliedPatches(ds, node)
+					dsc.renderRetryBackoff.Reset(failedPodsBackoffKey(ds, node.Name))
+					dsc.renderBreaker.observe(ds, node, nil)
+				}
+				// the render hash is recorded even without patches, so that adding the first patch only
+				// recreates the pods on the nodes it matches
+				if utilfeature.DefaultFeatureGate.Enabled(features.DaemonSetPatches) {
+					if renderHash, err := computeRenderHash(ds, node); err == nil {
+						if podTemplate.Annotations == nil {
+							podTemplate.Annotations = make(map[string]string)
+						}
+						podTemplate.Annotations[RenderHashAnnotation] = renderHash
+					}
+				}
 				if len(ds.Spec.Patches) > 0 {
-					patchedTemplate, err := applyPatchesToPodTemplate(ds, node, &podTemplate)
-					if err != nil {
-						klog.ErrorS(err, "Failed to apply patches to pod template", "daemonSet", klog.KObj(ds), "nodeName", nodesNeedingDaemonPods[ix])
-					} else {
-						podTemplate = *patchedTemplate
+					if shadowAnnotations, err := shadowRenderAnnotations(ds, node); err != nil {
+						klog.ErrorS(err, "Failed to render pod template with shadow patches", "daemonSet", klog.KObj(ds), "nodeName", node.Name)
+					} else if len(shadowAnnotations) > 0 {
+						if podTemplate.Annotations == nil {
+							podTemplate.Annotations = make(map[string]string)
+						}
+						for k, v := range shadowAnnotations {
+							podTemplate.Annotations[k] = v
+						}
 					}
 				}
 
@@ -884,6 +1298,9 @@ func (dsc *ReconcileDaemonSet) syncNodes(ctx context.Context, ds *appsv1beta1.Da
 					dsc.expectations.CreationObserved(logger, dsKey)
 					errCh <- err
 					utilruntime.HandleError(err)
+				} else if appliedPatches != "" {
+					dsc.eventRecorder.Eventf(ds, corev1.EventTypeNormal, PatchAppliedReason,
+						"Created pod on node %s with %s", node.Name, appliedPatches)
 				}
 			}(i)
 		}
@@ -1099,6 +1516,27 @@ func (dsc *ReconcileDaemonSet) getNodesToDaemonPods(ctx context.Context, ds *app
 	return nodeToDaemonPods, nil
 }
 
+// shouldRetryRender returns whether the daemon pod on the node should be created later, after rendering its
+// pod template failed with a transient error, and the delay to retry. It gives up once the backoff reaches
+// maxRenderRetryBackoff, and then the pod is created as rendering failed permanently.
+func (dsc *ReconcileDaemonSet) shouldRetryRender(ds *appsv1beta1.DaemonSet, nodeName string, err error) (time.Duration, bool) {
+	if !IsTransientRenderError(err) {
+		return 0, false
+	}
+	backoffKey := failedPodsBackoffKey(ds, nodeName)
+	if dsc.renderRetryBackoff.Get(backoffKey) >= maxRenderRetryBackoff {
+		return 0, false
+	}
+
+	// the retries are only counted once per backoff, no matter how many times the DaemonSet is synced
+	now := dsc.renderRetryBackoff.Clock.Now()
+	if !dsc.renderRetryBackoff.IsInBackOffSinceUpdate(backoffKey, now) {
+		dsc.renderRetryBackoff.Next(backoffKey, now)
+	}
+	delay := dsc.renderRetryBackoff.Get(backoffKey)
+	return delay, delay < maxRenderRetryBackoff
+}
+
 func failedPodsBackoffKey(ds *appsv1beta1.DaemonSet, nodeName string) string {
 	return fmt.Sprintf("%s/%d/%s", ds.UID, ds.Status.ObservedGeneration, nodeName)
 }
@@ -1136,6 +1574,8 @@ func (dsc *ReconcileDaemonSet) cleanupHistory(ctx context.Context, ds *appsv1bet
 	}
 	toKill := len(old) - toKeep
 	if toKill <= 0 {
+		// old histories exclude the current one
+		historyutil.RecordRevisionCleanup(controllerKind.Kind, len(old)+1, 0)
 		return nil
 	}
 
@@ -1151,6 +1591,7 @@ func (dsc *ReconcileDaemonSet) cleanupHistory(ctx context.Context, ds *appsv1bet
 
 	// Clean up old history from smallest to highest revision (from oldest to newest)
 	sort.Sort(historiesByRevision(old))
+	var deleted int
 	for _, history := range old {
 		if toKill <= 0 {
 			break
@@ -1164,7 +1605,9 @@ func (dsc *ReconcileDaemonSet) cleanupHistory(ctx context.Context, ds *appsv1bet
 			return err
 		}
 		toKill--
+		deleted++
 	}
+	historyutil.RecordRevisionCleanup(controllerKind.Kind, len(old)+1-deleted, deleted)
 	return nil
 }
 
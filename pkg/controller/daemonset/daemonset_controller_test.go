@@ -19,6 +19,7 @@ package daemonset
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"sync"
@@ -31,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/apiserver/pkg/storage/names"
 	"k8s.io/client-go/informers"
@@ -90,6 +92,7 @@ func newDaemonSet(name string) *appsv1beta1.DaemonSet {
 				Spec: corev1.PodSpec{
 					Containers: []corev1.Container{
 						{
+							Name:                   "foo",
 							Image:                  "foo/bar",
 							TerminationMessagePath: corev1.TerminationMessagePathDefault,
 							ImagePullPolicy:        corev1.PullIfNotPresent,
@@ -128,8 +131,9 @@ func (f *fakePodControl) CreatePods(ctx context.Context, namespace string, templ
 
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
-			Labels:    template.Labels,
-			Namespace: namespace,
+			Labels:      template.Labels,
+			Annotations: template.Annotations,
+			Namespace:   namespace,
 		},
 	}
 
@@ -167,6 +171,30 @@ func (f *fakePodControl) DeletePod(ctx context.Context, namespace string, podID
 	return nil
 }
 
+func (f *fakePodControl) PatchPod(ctx context.Context, namespace, name string, data []byte) error {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.FakePodControl.PatchPod(ctx, namespace, name, data); err != nil {
+		return fmt.Errorf("failed to patch pod %q", name)
+	}
+	pod, ok := f.podIDMap[name]
+	if !ok {
+		return fmt.Errorf("pod %q does not exist", name)
+	}
+	podJSON, _ := json.Marshal(pod)
+	patchedJSON, err := strategicpatch.StrategicMergePatch(podJSON, data, &corev1.Pod{})
+	if err != nil {
+		return err
+	}
+	patched := &corev1.Pod{}
+	if err := json.Unmarshal(patchedJSON, patched); err != nil {
+		return err
+	}
+	f.podStore.Update(patched)
+	f.podIDMap[name] = patched
+	return nil
+}
+
 // just define for test
 type daemonSetsController struct {
 	*ReconcileDaemonSet
@@ -264,6 +292,8 @@ func NewDaemonSetController(
 		podLister:                   podInformer.Lister(),
 		nodeLister:                  nodeInformer.Lister(),
 		failedPodsBackoff:           failedPodsBackoff,
+		renderRetryBackoff:          flowcontrol.NewBackOff(renderRetryInitialBackoff, maxRenderRetryBackoff),
+		renderBreaker:               newRenderCircuitBreaker(flowcontrol.NewBackOff(renderBreakerInitialBackoff, renderBreakerMaxBackoff)),
 	}
 }
 
@@ -409,3 +439,144 @@ func Test_isControlledByDaemonSet(t *testing.T) {
 		})
 	}
 }
+
+// newManyNodesController returns a controller with a DaemonSet and numNodes nodes. In steady state, every node runs
+// a ready daemon pod; otherwise some nodes need to create pods, and some have failed, excess or misscheduled pods.
+func newManyNodesController(tb testing.TB, numNodes int, steady bool) (*daemonSetsController, *fakePodControl, *appsv1beta1.DaemonSet, []*corev1.Node, string) {
+	ds := newDaemonSet("foo")
+	ds.Spec.Template.Spec.NodeSelector = map[string]string{"daemon": "true"}
+	manager, podControl, _, err := newTestController(ds)
+	if err != nil {
+		tb.Fatalf("error creating DaemonSets controller: %v", err)
+	}
+	// do not block on the events of failed pods
+	manager.eventRecorder = &record.FakeRecorder{}
+	if err = manager.dsStore.Add(ds); err != nil {
+		tb.Fatal(err)
+	}
+
+	created := metav1.NewTime(time.Now().Add(-time.Hour))
+	var nodeList []*corev1.Node
+	for i := 0; i < numNodes; i++ {
+		label := map[string]string{"daemon": "true"}
+		if !steady && i%10 == 0 {
+			label = nil
+		}
+		node := newNode(fmt.Sprintf("node-%d", i), label)
+		nodeList = append(nodeList, node)
+		if err = manager.nodeStore.Add(node); err != nil {
+			tb.Fatal(err)
+		}
+
+		podCount := 1
+		if !steady && i%3 == 0 {
+			podCount = 0
+		} else if !steady && i%11 == 0 {
+			podCount = 2
+		}
+		for j := 0; j < podCount; j++ {
+			pod := newPod("foo-", node.Name, simpleDaemonSetLabel, ds)
+			pod.Name = fmt.Sprintf("foo-%d-%d", i, j)
+			pod.CreationTimestamp = metav1.NewTime(created.Add(time.Duration(j) * time.Second))
+			// only one failed pod on a node, the next one is limited by backoff
+			if !steady && i%7 == 0 && podCount == 1 {
+				pod.Status.Phase = corev1.PodFailed
+			} else {
+				markPodReady(pod)
+			}
+			if err = manager.podStore.Add(pod); err != nil {
+				tb.Fatal(err)
+			}
+			podControl.podIDMap[pod.Name] = pod
+		}
+	}
+	hash := newPod("foo-", "", simpleDaemonSetLabel, ds).Labels[apps.DefaultDaemonSetUniqueLabelKey]
+	return manager, podControl, ds, nodeList, hash
+}
+
+func getTemplateNodeName(template *corev1.PodTemplateSpec) string {
+	if template.Spec.NodeName != "" {
+		return template.Spec.NodeName
+	}
+	if affinity := template.Spec.Affinity; affinity != nil && affinity.NodeAffinity != nil &&
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+			for _, field := range term.MatchFields {
+				if len(field.Values) > 0 {
+					return field.Values[0]
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func TestManageNodesInParallel(t *testing.T) {
+	defer func(n int) { nodeWorkers = n }(nodeWorkers)
+
+	var expected string
+	for _, workers := range []int{1, 8} {
+		nodeWorkers = workers
+		manager, podControl, ds, nodeList, hash := newManyNodesController(t, 300, false)
+		if err := manager.manage(context.TODO(), ds, nodeList, hash); err != nil {
+			t.Fatalf("failed to manage with %d workers: %v", workers, err)
+		}
+		if err := manager.updateDaemonSetStatus(context.TODO(), ds, nodeList, hash, true); err != nil {
+			t.Fatalf("failed to update status with %d workers: %v", workers, err)
+		}
+		updated, err := manager.kruiseClient.AppsV1beta1().DaemonSets(ds.Namespace).Get(context.TODO(), ds.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var createdNodes []string
+		for i := range podControl.Templates {
+			createdNodes = append(createdNodes, getTemplateNodeName(&podControl.Templates[i]))
+		}
+		sort.Strings(createdNodes)
+		deletedPods := append([]string{}, podControl.DeletePodName...)
+		sort.Strings(deletedPods)
+		if len(createdNodes) == 0 || len(deletedPods) == 0 {
+			t.Fatalf("expected both creations and deletions, got %d and %d", len(createdNodes), len(deletedPods))
+		}
+
+		got := fmt.Sprintf("created: %v, deleted: %v, status: %+v", createdNodes, deletedPods, updated.Status)
+		if expected == "" {
+			expected = got
+		} else if got != expected {
+			t.Fatalf("expected the same results with %d workers as sequential:\n%s\ngot:\n%s", workers, expected, got)
+		}
+	}
+}
+
+func BenchmarkManageManyNodes(b *testing.B) {
+	defer func(n int) { nodeWorkers = n }(nodeWorkers)
+	for _, workers := range []int{1, 16} {
+		b.Run(fmt.Sprintf("workers-%d", workers), func(b *testing.B) {
+			nodeWorkers = workers
+			manager, _, ds, nodeList, hash := newManyNodesController(b, 10000, true)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := manager.manage(context.TODO(), ds, nodeList, hash); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkUpdateDaemonSetStatusManyNodes(b *testing.B) {
+	defer func(n int) { nodeWorkers = n }(nodeWorkers)
+	for _, workers := range []int{1, 16} {
+		b.Run(fmt.Sprintf("workers-%d", workers), func(b *testing.B) {
+			nodeWorkers = workers
+			manager, _, ds, nodeList, hash := newManyNodesController(b, 10000, true)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := manager.updateDaemonSetStatus(context.TODO(), ds, nodeList, hash, true); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -36,6 +36,7 @@ import (
 
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
 	"github.com/openkruise/kruise/pkg/util"
+	"github.com/openkruise/kruise/pkg/util/fieldindex"
 )
 
 var _ handler.TypedEventHandler[*v1.Pod, reconcile.Request] = &podEventHandler{}
@@ -272,7 +273,8 @@ func (e *nodeEventHandler) Update(ctx context.Context, evt event.TypedUpdateEven
 		oldShouldRun, oldShouldContinueRunning := nodeShouldRunDaemonPod(oldNode, ds)
 		currentShouldRun, currentShouldContinueRunning := nodeShouldRunDaemonPod(curNode, ds)
 		if (oldShouldRun != currentShouldRun) || (oldShouldContinueRunning != currentShouldContinueRunning) ||
-			(NodeShouldUpdateBySelector(oldNode, ds) != NodeShouldUpdateBySelector(curNode, ds)) {
+			(NodeShouldUpdateBySelector(oldNode, ds) != NodeShouldUpdateBySelector(curNode, ds)) ||
+			(waitNodeReadyBeforePatch(ds) && isNodeReadyForPatch(oldNode) != isNodeReadyForPatch(curNode)) {
 			klog.V(6).InfoS("Update node triggers DaemonSet to reconcile", "nodeName", curNode.Name, "daemonSet", klog.KObj(ds))
 			q.Add(reconcile.Request{NamespacedName: types.NamespacedName{
 				Name:      ds.GetName(),
@@ -280,6 +282,26 @@ func (e *nodeEventHandler) Update(ctx context.Context, evt event.TypedUpdateEven
 			}})
 		}
 	}
+
+	// The patches matching the node may change with its labels, find the DaemonSets whose patches
+	// reference the changed label keys by the index, instead of checking the patches of all DaemonSets.
+	for _, key := range changedLabelKeys(oldNode.Labels, curNode.Labels) {
+		patchedList := &appsv1beta1.DaemonSetList{}
+		if err := e.reader.List(context.TODO(), patchedList, client.MatchingFields{fieldindex.IndexNameForDaemonSetPatchLabel: key}); err != nil {
+			klog.V(4).ErrorS(err, "Error listing DaemonSets by patch label key", "key", key)
+			return
+		}
+		for i := range patchedList.Items {
+			ds := &patchedList.Items[i]
+			if patchesMatchChanged(ds, oldNode, curNode) {
+				klog.V(6).InfoS("Update node labels triggers DaemonSet patches to reconcile", "nodeName", curNode.Name, "daemonSet", klog.KObj(ds), "key", key)
+				q.Add(reconcile.Request{NamespacedName: types.NamespacedName{
+					Name:      ds.GetName(),
+					Namespace: ds.GetNamespace(),
+				}})
+			}
+		}
+	}
 }
 
 func (e *nodeEventHandler) Delete(ctx context.Context, evt event.TypedDeleteEvent[*v1.Node], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
@@ -18,6 +18,7 @@ package daemonset
 
 import (
 	"context"
+	"reflect"
 	"testing"
 	"time"
 
@@ -32,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/util/fieldindex"
 )
 
 func newTestPodEventHandler(reader client.Reader, expectations kubecontroller.ControllerExpectationsInterface) *podEventHandler {
@@ -930,3 +932,90 @@ func TestEnqueueRequestForNodeUpdate(t *testing.T) {
 		}
 	}
 }
+
+func TestEnqueueRequestForNodeUpdatePatchLabels(t *testing.T) {
+	newPatchedDaemonSet := func(name string, selectors ...map[string]string) *appsv1beta1.DaemonSet {
+		ds := &appsv1beta1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: appsv1beta1.DaemonSetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+				Template: v1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": name}}},
+			},
+		}
+		for _, selector := range selectors {
+			ds.Spec.Patches = append(ds.Spec.Patches, appsv1beta1.DaemonSetPatch{
+				Selector: &metav1.LabelSelector{MatchLabels: selector},
+			})
+		}
+		return ds
+	}
+	dss := []*appsv1beta1.DaemonSet{
+		newPatchedDaemonSet("ds-zone", map[string]string{"zone": "a"}),
+		newPatchedDaemonSet("ds-disk", map[string]string{"disk": "slow"}),
+		newPatchedDaemonSet("ds-none"),
+	}
+	newNode := func(labels map[string]string) *v1.Node {
+		return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-0", Labels: labels}}
+	}
+
+	cases := []struct {
+		name             string
+		oldLabels        map[string]string
+		curLabels        map[string]string
+		expectedRequests []string
+	}{
+		{
+			name:             "zone label changed to matched",
+			oldLabels:        map[string]string{"zone": "b", "disk": "fast"},
+			curLabels:        map[string]string{"zone": "a", "disk": "fast"},
+			expectedRequests: []string{"ds-zone"},
+		},
+		{
+			name:             "disk label removed",
+			oldLabels:        map[string]string{"zone": "b", "disk": "slow"},
+			curLabels:        map[string]string{"zone": "b"},
+			expectedRequests: []string{"ds-disk"},
+		},
+		{
+			name:      "zone label changed but still unmatched",
+			oldLabels: map[string]string{"zone": "b"},
+			curLabels: map[string]string{"zone": "c"},
+		},
+		{
+			name:      "label not referenced by patches",
+			oldLabels: map[string]string{"rack": "1"},
+			curLabels: map[string]string{"rack": "2"},
+		},
+	}
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().
+				WithIndex(&appsv1beta1.DaemonSet{}, fieldindex.IndexNameForDaemonSetPatchLabel, fieldindex.IndexDaemonSetPatchLabelKeys).
+				Build()
+			for _, ds := range dss {
+				if err := fakeClient.Create(context.TODO(), ds.DeepCopy()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			enqueueHandler := newTestNodeEventHandler(fakeClient)
+			q := workqueue.NewTypedRateLimitingQueueWithConfig(
+				workqueue.DefaultTypedControllerRateLimiter[reconcile.Request](),
+				workqueue.TypedRateLimitingQueueConfig[reconcile.Request]{
+					Name: "test-queue",
+				},
+			)
+
+			e := event.TypedUpdateEvent[*v1.Node]{ObjectOld: newNode(testCase.oldLabels), ObjectNew: newNode(testCase.curLabels)}
+			enqueueHandler.Update(context.TODO(), e, q)
+			var requests []string
+			for q.Len() > 0 {
+				req, _ := q.Get()
+				requests = append(requests, req.Name)
+				q.Done(req)
+			}
+			if !reflect.DeepEqual(requests, testCase.expectedRequests) {
+				t.Fatalf("expected requests %v, got %v", testCase.expectedRequests, requests)
+			}
+		})
+	}
+}
@@ -19,22 +19,31 @@ package daemonset
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"reflect"
+	"sort"
 
 	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/klog/v2"
 	kubecontroller "k8s.io/kubernetes/pkg/controller"
+	hashutil "k8s.io/kubernetes/pkg/util/hash"
 	labelsutil "k8s.io/kubernetes/pkg/util/labels"
 
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/features"
 	"github.com/openkruise/kruise/pkg/util"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
 )
 
 func (dsc *ReconcileDaemonSet) constructHistory(ctx context.Context, ds *appsv1beta1.DaemonSet) (cur *apps.ControllerRevision, old []*apps.ControllerRevision, err error) {
@@ -45,11 +54,23 @@ func (dsc *ReconcileDaemonSet) constructHistory(ctx context.Context, ds *appsv1b
 		return nil, nil, err
 	}
 	for _, history := range histories {
-		// Add the unique label if it's not already added to the history
-		// We use history name instead of computing hash, so that we don't need to worry about hash collision
-		if _, ok := history.Labels[apps.DefaultDaemonSetUniqueLabelKey]; !ok {
+		_, hasUniqueLabel := history.Labels[apps.DefaultDaemonSetUniqueLabelKey]
+		_, hasPatchesHash := history.Annotations[RevisionPatchesHashAnnotation]
+		if !hasUniqueLabel || !hasPatchesHash {
 			toUpdate := history.DeepCopy()
-			toUpdate.Labels[apps.DefaultDaemonSetUniqueLabelKey] = toUpdate.Name
+			// Add the unique label if it's not already added to the history
+			// We use history name instead of computing hash, so that we don't need to worry about hash collision
+			if !hasUniqueLabel {
+				toUpdate.Labels[apps.DefaultDaemonSetUniqueLabelKey] = toUpdate.Name
+			}
+			// The history was recorded when the patches were immutable, so its pods are rendered with the current
+			// patches of the DaemonSet.
+			if !hasPatchesHash {
+				if toUpdate.Annotations == nil {
+					toUpdate.Annotations = map[string]string{}
+				}
+				toUpdate.Annotations[RevisionPatchesHashAnnotation] = computePatchesHash(ds.Spec.Patches)
+			}
 			history, err = dsc.kubeClient.AppsV1().ControllerRevisions(ds.Namespace).Update(ctx, toUpdate, metav1.UpdateOptions{})
 			if err != nil {
 				return nil, nil, err
@@ -127,8 +148,24 @@ func (dsc *ReconcileDaemonSet) controlledHistories(ctx context.Context, ds *apps
 	return cm.ClaimControllerRevisions(ctx, histories)
 }
 
-// Match check if the given DaemonSet's template matches the template stored in the given history.
+// Match check if the given DaemonSet's template and patches match the ones stored in the given history.
+// The history recorded before the patches were mutable doesn't record the patches in its data, so the patches
+// are compared with the hash in its annotation instead.
 func Match(ds *appsv1beta1.DaemonSet, history *apps.ControllerRevision) (bool, error) {
+	if patchesHash, ok := history.Annotations[RevisionPatchesHashAnnotation]; ok {
+		if patchesHash != computePatchesHash(ds.Spec.Patches) {
+			return false, nil
+		}
+		recorded, err := GetPatchesFromRevision(history)
+		if err != nil {
+			return false, err
+		}
+		if len(recorded) == 0 {
+			clone := ds.DeepCopy()
+			clone.Spec.Patches = nil
+			ds = clone
+		}
+	}
 	patch, err := getPatch(ds)
 	if err != nil {
 		return false, err
@@ -145,9 +182,11 @@ func getPatch(ds *appsv1beta1.DaemonSet) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	// keep the numbers as they are, so that the template in revision is the same as the canonical JSON of render
 	var raw map[string]interface{}
-	err = json.Unmarshal(dsBytes, &raw)
-	if err != nil {
+	decoder := json.NewDecoder(bytes.NewReader(dsBytes))
+	decoder.UseNumber()
+	if err = decoder.Decode(&raw); err != nil {
 		return nil, err
 	}
 	objCopy := make(map[string]interface{})
@@ -158,9 +197,228 @@ func getPatch(ds *appsv1beta1.DaemonSet) ([]byte, error) {
 	template := spec["template"].(map[string]interface{})
 	specCopy["template"] = template
 	template["$patch"] = "replace"
+	// Record the active patch set as well, so that a change of patches is rolled out as a new revision
+	if patches, ok := spec["patches"]; ok {
+		specCopy["patches"] = patches
+	}
 	objCopy["spec"] = specCopy
-	patch, err := json.Marshal(objCopy)
-	return patch, err
+	return util.CanonicalJSON(objCopy)
+}
+
+// getStablePatch returns the revision patch of the DaemonSet without the patches applied to new pods only.
+// Old pods whose revision has the same stable patch don't have to be recreated.
+func getStablePatch(ds *appsv1beta1.DaemonSet) ([]byte, error) {
+	clone := ds.DeepCopy()
+	clone.Spec.Patches = patchesForExistingPods(ds.Spec.Patches)
+	return getPatch(clone)
+}
+
+// getStablePatchFromRevision returns the patch recorded in the given revision without the patches applied to
+// new pods only.
+func getStablePatchFromRevision(history *apps.ControllerRevision) ([]byte, error) {
+	var obj struct {
+		Spec struct {
+			Template corev1.PodTemplateSpec       `json:"template"`
+			Patches  []appsv1beta1.DaemonSetPatch `json:"patches,omitempty"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(history.Data.Raw, &obj); err != nil {
+		return nil, err
+	}
+	ds := &appsv1beta1.DaemonSet{}
+	ds.Spec.Template = obj.Spec.Template
+	ds.Spec.Patches = patchesForExistingPods(obj.Spec.Patches)
+	return getPatch(ds)
+}
+
+// patchesForExistingPods returns the patches that are also applied to the existing pods, which are neither
+// applied to new pods only nor shadow.
+func patchesForExistingPods(patches []appsv1beta1.DaemonSetPatch) []appsv1beta1.DaemonSetPatch {
+	var out []appsv1beta1.DaemonSetPatch
+	for i := range patches {
+		if !patches[i].ApplyToNewPodsOnly && !patches[i].Shadow {
+			out = append(out, patches[i])
+		}
+	}
+	return out
+}
+
+// hasNewPodsOnlyPatches returns true if any of the patches is applied to new pods only, or shadow which is not
+// applied to any pod.
+func hasNewPodsOnlyPatches(patches []appsv1beta1.DaemonSetPatch) bool {
+	for i := range patches {
+		if patches[i].ApplyToNewPodsOnly || patches[i].Shadow {
+			return true
+		}
+	}
+	return false
+}
+
+// computeRevisionHash returns the hash used to name the revision of the DaemonSet.
+// It keeps the hash of spec.template unchanged for DaemonSets without patches.
+func computeRevisionHash(ds *appsv1beta1.DaemonSet) string {
+	if len(ds.Spec.Patches) == 0 {
+		return kubecontroller.ComputeHash(&ds.Spec.Template, ds.Status.CollisionCount)
+	}
+
+	hasher := fnv.New32a()
+	hashutil.DeepHashObject(hasher, struct {
+		Template *corev1.PodTemplateSpec
+		Patches  []appsv1beta1.DaemonSetPatch
+	}{&ds.Spec.Template, ds.Spec.Patches})
+	if ds.Status.CollisionCount != nil {
+		collisionCountBytes := make([]byte, 8)
+		binary.LittleEndian.PutUint32(collisionCountBytes, uint32(*ds.Status.CollisionCount))
+		hasher.Write(collisionCountBytes)
+	}
+	return rand.SafeEncodeString(fmt.Sprint(hasher.Sum32()))
+}
+
+// computePatchesHash returns the hash of the given DaemonSet patches recorded in RevisionPatchesHashAnnotation.
+// The patches are hashed in JSON, so that it is kept unchanged when new optional fields are added to the patches.
+func computePatchesHash(patches []appsv1beta1.DaemonSetPatch) string {
+	hasher := fnv.New32a()
+	if len(patches) > 0 {
+		hasher.Write([]byte(util.DumpJSON(patches)))
+	}
+	return rand.SafeEncodeString(fmt.Sprint(hasher.Sum32()))
+}
+
+// computeRenderHash returns the hash of pod template rendered for the given node, which is
+// spec.template with the matched patches applied and post-processed by the registered TemplateRenderer.
+// It is recorded in daemon pods, so that a pod is only recreated when the template rendered for its node
+// actually changed.
+func computeRenderHash(ds *appsv1beta1.DaemonSet, node *corev1.Node) (string, error) {
+	template, err := renderPodTemplate(ds, node, &ds.Spec.Template)
+	if err != nil {
+		return "", err
+	}
+	return hashRenderedTemplate(template), nil
+}
+
+// computeBaseRenderHash returns the render hash of the daemon pods created from spec.template without any patches,
+// which is the hash that the pods created before the render hash was recorded are regarded to have.
+func computeBaseRenderHash(ds *appsv1beta1.DaemonSet, node *corev1.Node) (string, error) {
+	template, err := renderPatchedPodTemplate(node, &ds.Spec.Template)
+	if err != nil {
+		return "", err
+	}
+	return hashRenderedTemplate(template), nil
+}
+
+// hashRenderedTemplate hashes the rendered template together with the ID of the registered TemplateRenderer,
+// so that the daemon pods are recreated when a different renderer is registered, even if it renders the same
+// template for now. The ID of the default renderer is not hashed to keep the existing hashes unchanged.
+func hashRenderedTemplate(template *corev1.PodTemplateSpec) string {
+	hasher := fnv.New32a()
+	hashutil.DeepHashObject(hasher, canonicalizeRenderedTemplate(template))
+	if renderer := getTemplateRenderer(); !isNoopTemplateRenderer(renderer) {
+		hasher.Write([]byte(renderer.ID()))
+	}
+	return rand.SafeEncodeString(fmt.Sprint(hasher.Sum32()))
+}
+
+// maxShadowRenderDiffSize bounds the size of the diff recorded in ShadowRenderDiffAnnotation, a larger diff is
+// not recorded and the shadow render hash is left for comparison.
+const maxShadowRenderDiffSize = 4096
+
+// shadowRenderAnnotations returns the annotations recording the pod template rendered for the node with the shadow
+// patches applied as well, or nil if none of the shadow patches matches the node.
+func shadowRenderAnnotations(ds *appsv1beta1.DaemonSet, node *corev1.Node) (map[string]string, error) {
+	if !nodeMatchesShadowPatches(ds, node) {
+		return nil, nil
+	}
+	liveTemplate, err := renderPodTemplate(ds, node, &ds.Spec.Template)
+	if err != nil {
+		return nil, err
+	}
+	shadowTemplate, err := applyPatchesToPodTemplateWithShadow(ds, node, &ds.Spec.Template, true)
+	if err != nil {
+		return nil, err
+	}
+	if shadowTemplate, err = renderPatchedPodTemplate(node, shadowTemplate); err != nil {
+		return nil, err
+	}
+
+	annotations := map[string]string{ShadowRenderHashAnnotation: hashRenderedTemplate(shadowTemplate)}
+	liveJSON, err := json.Marshal(canonicalizeRenderedTemplate(liveTemplate))
+	if err != nil {
+		return nil, err
+	}
+	shadowJSON, err := json.Marshal(canonicalizeRenderedTemplate(shadowTemplate))
+	if err != nil {
+		return nil, err
+	}
+	diff, err := strategicpatch.CreateTwoWayMergePatch(liveJSON, shadowJSON, &corev1.PodTemplateSpec{})
+	if err != nil {
+		return nil, err
+	}
+	if len(diff) <= maxShadowRenderDiffSize {
+		annotations[ShadowRenderDiffAnnotation] = string(diff)
+	}
+	return annotations, nil
+}
+
+// nodeMatchesShadowPatches returns true if the node is matched by any of the shadow patches.
+func nodeMatchesShadowPatches(ds *appsv1beta1.DaemonSet, node *corev1.Node) bool {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.DaemonSetPatches) {
+		return false
+	}
+	for i := range ds.Spec.Patches {
+		if ds.Spec.Patches[i].Shadow && ExplainMatch(&ds.Spec.Patches[i], node).Matched {
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalizeRenderedTemplate returns a copy of the rendered template with the lists whose order is
+// semantically meaningless sorted and deduplicated, so that reordering their entries in patches does not
+// change the render hash. Lists whose order matters, such as containers and env, are kept as they are.
+func canonicalizeRenderedTemplate(template *corev1.PodTemplateSpec) *corev1.PodTemplateSpec {
+	template = template.DeepCopy()
+	template.Spec.ImagePullSecrets = sortAndDedupe(template.Spec.ImagePullSecrets)
+	template.Spec.Tolerations = sortAndDedupe(template.Spec.Tolerations)
+	template.Spec.TopologySpreadConstraints = sortAndDedupe(template.Spec.TopologySpreadConstraints)
+	return template
+}
+
+// sortAndDedupe sorts the items by their JSON encoding and removes the duplicated ones.
+func sortAndDedupe[T any](items []T) []T {
+	if len(items) == 0 {
+		return items
+	}
+	itemsByKey := make(map[string]T, len(items))
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		key := util.DumpJSON(item)
+		if _, ok := itemsByKey[key]; !ok {
+			itemsByKey[key] = item
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	sorted := make([]T, 0, len(keys))
+	for _, key := range keys {
+		sorted = append(sorted, itemsByKey[key])
+	}
+	return sorted
+}
+
+// GetPatchesFromRevision returns the DaemonSet patches recorded in the given revision.
+func GetPatchesFromRevision(history *apps.ControllerRevision) ([]appsv1beta1.DaemonSetPatch, error) {
+	if history == nil || len(history.Data.Raw) == 0 {
+		return nil, nil
+	}
+	var obj struct {
+		Spec struct {
+			Patches []appsv1beta1.DaemonSetPatch `json:"patches,omitempty"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(history.Data.Raw, &obj); err != nil {
+		return nil, err
+	}
+	return obj.Spec.Patches, nil
 }
 
 // maxRevision returns the max revision number of the given list of histories
@@ -179,14 +437,14 @@ func (dsc *ReconcileDaemonSet) snapshot(ctx context.Context, ds *appsv1beta1.Dae
 	if err != nil {
 		return nil, err
 	}
-	hash := kubecontroller.ComputeHash(&ds.Spec.Template, ds.Status.CollisionCount)
+	hash := computeRevisionHash(ds)
 	name := ds.Name + "-" + hash
 	history := &apps.ControllerRevision{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            name,
 			Namespace:       ds.Namespace,
 			Labels:          labelsutil.CloneAndAddLabel(ds.Spec.Template.Labels, apps.DefaultDaemonSetUniqueLabelKey, hash),
-			Annotations:     ds.Annotations,
+			Annotations:     labelsutil.CloneAndAddLabel(ds.Annotations, RevisionPatchesHashAnnotation, computePatchesHash(ds.Spec.Patches)),
 			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ds, controllerKind)},
 		},
 		Data:     runtime.RawExtension{Raw: patch},
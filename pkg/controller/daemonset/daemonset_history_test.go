@@ -0,0 +1,337 @@
+/*
+Copyright 2021 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemonset
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubecontroller "k8s.io/kubernetes/pkg/controller"
+	labelsutil "k8s.io/kubernetes/pkg/util/labels"
+	"k8s.io/utils/ptr"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/features"
+	"github.com/openkruise/kruise/pkg/util"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+)
+
+func TestConstructHistoryWithPatches(t *testing.T) {
+	ds := newDaemonSet("foo")
+	manager, _, _, err := newTestController(ds)
+	if err != nil {
+		t.Fatalf("error creating DaemonSets controller: %v", err)
+	}
+	if err := manager.dsStore.Add(ds); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.TODO()
+	cur, _, err := manager.constructHistory(ctx, ds)
+	if err != nil {
+		t.Fatalf("failed to construct history: %v", err)
+	}
+	if expected := kubecontroller.ComputeHash(&ds.Spec.Template, ds.Status.CollisionCount); cur.Name != ds.Name+"-"+expected {
+		t.Fatalf("expected revision name of DaemonSet without patches unchanged, got %s", cur.Name)
+	}
+	if patches, err := GetPatchesFromRevision(cur); err != nil || len(patches) != 0 {
+		t.Fatalf("expected no patches in revision, got %v, err %v", patches, err)
+	}
+	if err := manager.historyStore.Add(cur); err != nil {
+		t.Fatal(err)
+	}
+
+	ds = ds.DeepCopy()
+	ds.Spec.Patches = []appsv1beta1.DaemonSetPatch{
+		{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+			Patch:    runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"patched":"true"}}}`)},
+			Priority: 1,
+		},
+	}
+	if err := manager.dsStore.Update(ds); err != nil {
+		t.Fatal(err)
+	}
+
+	newCur, old, err := manager.constructHistory(ctx, ds)
+	if err != nil {
+		t.Fatalf("failed to construct history: %v", err)
+	}
+	if newCur.Name == cur.Name {
+		t.Fatalf("expected a new revision after patches changed, got %s", newCur.Name)
+	}
+	if newCur.Revision <= cur.Revision {
+		t.Fatalf("expected new revision number greater than %d, got %d", cur.Revision, newCur.Revision)
+	}
+	if len(old) != 1 || old[0].Name != cur.Name {
+		t.Fatalf("expected old revision %s, got %v", cur.Name, old)
+	}
+	patches, err := GetPatchesFromRevision(newCur)
+	if err != nil {
+		t.Fatalf("failed to get patches from revision: %v", err)
+	}
+	if len(patches) != 1 || !reflect.DeepEqual(patches[0].Selector, ds.Spec.Patches[0].Selector) ||
+		patches[0].Priority != ds.Spec.Patches[0].Priority || string(patches[0].Patch.Raw) != string(ds.Spec.Patches[0].Patch.Raw) {
+		t.Fatalf("unexpected patches in revision: %v", patches)
+	}
+	if matched, err := Match(ds, newCur); err != nil || !matched {
+		t.Fatalf("expected DaemonSet to match the new revision, matched %v, err %v", matched, err)
+	}
+	if matched, err := Match(ds, cur); err != nil || matched {
+		t.Fatalf("expected DaemonSet not to match the old revision, matched %v, err %v", matched, err)
+	}
+}
+
+func TestConstructHistoryAdoptsRevisionWithoutPatches(t *testing.T) {
+	ds := newDaemonSet("foo")
+	ds.Spec.Patches = []appsv1beta1.DaemonSetPatch{
+		{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+			Patch:    runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"patched":"true"}}}`)},
+			Priority: 1,
+		},
+	}
+	// the revision recorded before the patches were recorded, which only has spec.template in its data
+	unpatched := ds.DeepCopy()
+	unpatched.Spec.Patches = nil
+	data, err := getPatch(unpatched)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := kubecontroller.ComputeHash(&ds.Spec.Template, ds.Status.CollisionCount)
+	legacy := &apps.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            ds.Name + "-" + hash,
+			Namespace:       ds.Namespace,
+			Labels:          labelsutil.CloneAndAddLabel(ds.Spec.Template.Labels, apps.DefaultDaemonSetUniqueLabelKey, hash),
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ds, controllerKind)},
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: 1,
+	}
+	manager, _, _, err := newTestController(ds, legacy)
+	if err != nil {
+		t.Fatalf("error creating DaemonSets controller: %v", err)
+	}
+	if err := manager.dsStore.Add(ds); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.historyStore.Add(legacy); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.TODO()
+	cur, old, err := manager.constructHistory(ctx, ds)
+	if err != nil {
+		t.Fatalf("failed to construct history: %v", err)
+	}
+	if cur.Name != legacy.Name || len(old) != 0 {
+		t.Fatalf("expected the revision without patches kept as current, got %s and old %v", cur.Name, old)
+	}
+	if got, expected := cur.Annotations[RevisionPatchesHashAnnotation], computePatchesHash(ds.Spec.Patches); got != expected {
+		t.Fatalf("expected patches hash %s recorded in the adopted revision, got %s", expected, got)
+	}
+	if err := manager.historyStore.Update(cur); err != nil {
+		t.Fatal(err)
+	}
+
+	ds = ds.DeepCopy()
+	ds.Spec.Patches[0].Priority = 2
+	if err := manager.dsStore.Update(ds); err != nil {
+		t.Fatal(err)
+	}
+	newCur, old, err := manager.constructHistory(ctx, ds)
+	if err != nil {
+		t.Fatalf("failed to construct history: %v", err)
+	}
+	if newCur.Name == legacy.Name || len(old) != 1 || old[0].Name != legacy.Name {
+		t.Fatalf("expected a new revision after patches changed, got %s and old %v", newCur.Name, old)
+	}
+}
+
+func TestComputeRenderHashCanonicalization(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.DaemonSetPatches, true)()
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"type": "special"}}}
+	renderHash := func(patches ...string) string {
+		ds := &appsv1beta1.DaemonSet{
+			Spec: appsv1beta1.DaemonSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "a", Image: "a"}, {Name: "b", Image: "b"}},
+					},
+				},
+			},
+		}
+		for _, patch := range patches {
+			ds.Spec.Patches = append(ds.Spec.Patches, appsv1beta1.DaemonSetPatch{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"type": "special"}},
+				Patch:    runtime.RawExtension{Raw: []byte(patch)},
+			})
+		}
+		hash, err := computeRenderHash(ds, node)
+		if err != nil {
+			t.Fatalf("failed to compute render hash: %v", err)
+		}
+		return hash
+	}
+
+	tests := []struct {
+		name           string
+		patches        [2][]string
+		expectSameHash bool
+	}{
+		{
+			name: "imagePullSecrets reordered",
+			patches: [2][]string{
+				{`{"spec":{"imagePullSecrets":[{"name":"s1"},{"name":"s2"}]}}`},
+				{`{"spec":{"imagePullSecrets":[{"name":"s2"},{"name":"s1"}]}}`},
+			},
+			expectSameHash: true,
+		},
+		{
+			name: "imagePullSecrets split into patches in different order",
+			patches: [2][]string{
+				{`{"spec":{"imagePullSecrets":[{"name":"s1"}]}}`, `{"spec":{"imagePullSecrets":[{"name":"s2"}]}}`},
+				{`{"spec":{"imagePullSecrets":[{"name":"s2"}]}}`, `{"spec":{"imagePullSecrets":[{"name":"s1"}]}}`},
+			},
+			expectSameHash: true,
+		},
+		{
+			name: "tolerations reordered and duplicated",
+			patches: [2][]string{
+				{`{"spec":{"tolerations":[{"key":"k1","operator":"Exists"},{"key":"k2","operator":"Exists"}]}}`},
+				{`{"spec":{"tolerations":[{"key":"k2","operator":"Exists"},{"key":"k1","operator":"Exists"},{"key":"k2","operator":"Exists"}]}}`},
+			},
+			expectSameHash: true,
+		},
+		{
+			name: "topologySpreadConstraints reordered",
+			patches: [2][]string{
+				{`{"spec":{"topologySpreadConstraints":[{"topologyKey":"zone","maxSkew":1,"whenUnsatisfiable":"DoNotSchedule"},{"topologyKey":"host","maxSkew":1,"whenUnsatisfiable":"ScheduleAnyway"}]}}`},
+				{`{"spec":{"topologySpreadConstraints":[{"topologyKey":"host","maxSkew":1,"whenUnsatisfiable":"ScheduleAnyway"},{"topologyKey":"zone","maxSkew":1,"whenUnsatisfiable":"DoNotSchedule"}]}}`},
+			},
+			expectSameHash: true,
+		},
+		{
+			name: "tolerations changed",
+			patches: [2][]string{
+				{`{"spec":{"tolerations":[{"key":"k1","operator":"Exists"}]}}`},
+				{`{"spec":{"tolerations":[{"key":"k2","operator":"Exists"}]}}`},
+			},
+			expectSameHash: false,
+		},
+		{
+			name: "containers reordered",
+			patches: [2][]string{
+				{`{"spec":{"containers":[{"name":"a","image":"a"},{"name":"b","image":"b"},{"$patch":"replace"}]}}`},
+				{`{"spec":{"containers":[{"name":"b","image":"b"},{"name":"a","image":"a"},{"$patch":"replace"}]}}`},
+			},
+			expectSameHash: false,
+		},
+		{
+			name: "env reordered",
+			patches: [2][]string{
+				{`{"spec":{"containers":[{"name":"a","env":[{"name":"E1","value":"1"},{"name":"E2","value":"$(E1)"},{"$patch":"replace"}]}]}}`},
+				{`{"spec":{"containers":[{"name":"a","env":[{"name":"E2","value":"$(E1)"},{"name":"E1","value":"1"},{"$patch":"replace"}]}]}}`},
+			},
+			expectSameHash: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash1, hash2 := renderHash(tt.patches[0]...), renderHash(tt.patches[1]...)
+			if (hash1 == hash2) != tt.expectSameHash {
+				t.Fatalf("expected same hash %v, got %s and %s", tt.expectSameHash, hash1, hash2)
+			}
+		})
+	}
+}
+
+func TestCanonicalJSONOfRenderAndRevision(t *testing.T) {
+	template := corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"b": "2", "a": "1"}},
+		Spec: corev1.PodSpec{
+			Containers:                    []corev1.Container{{Name: "main", Image: "main:v1"}},
+			TerminationGracePeriodSeconds: ptr.To[int64](9007199254740993),
+		},
+	}
+	rawPatch := []byte(`{
+		"spec": {"containers": [{"image": "main:v2", "name": "main"}]},
+		"metadata": {"labels": {"patched": "true"}}
+	}`)
+	ds := &appsv1beta1.DaemonSet{Spec: appsv1beta1.DaemonSetSpec{
+		Template: template,
+		Patches: []appsv1beta1.DaemonSetPatch{{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"type": "special"}},
+			Patch:    runtime.RawExtension{Raw: rawPatch},
+		}},
+	}}
+
+	expectedTemplate, err := util.CanonicalJSON(&template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedPatch, err := util.CanonicalJSON(rawPatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// render: an empty patch keeps the template, which is decoded from the canonical bytes
+	rendered, err := applyStrategicMergePatch(&template, []byte(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renderedJSON, _ := util.CanonicalJSON(rendered); !bytes.Equal(renderedJSON, expectedTemplate) {
+		t.Fatalf("expected rendered template %s, got %s", expectedTemplate, renderedJSON)
+	}
+
+	// revision: spec.template and spec.patches recorded in the revision data
+	revisionData, err := getPatch(ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if canonical, _ := util.CanonicalJSON(revisionData); !bytes.Equal(canonical, revisionData) {
+		t.Fatalf("expected canonical revision data, got %s", revisionData)
+	}
+	var revision struct {
+		Spec struct {
+			Template map[string]json.RawMessage `json:"template"`
+			Patches  []struct {
+				Patch json.RawMessage `json:"patch"`
+			} `json:"patches"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(revisionData, &revision); err != nil {
+		t.Fatal(err)
+	}
+	delete(revision.Spec.Template, "$patch")
+	if revisionTemplate, _ := util.CanonicalJSON(revision.Spec.Template); !bytes.Equal(revisionTemplate, expectedTemplate) {
+		t.Fatalf("expected template in revision %s, got %s", expectedTemplate, revisionTemplate)
+	}
+	if len(revision.Spec.Patches) != 1 || !bytes.Equal(revision.Spec.Patches[0].Patch, expectedPatch) {
+		t.Fatalf("expected patch in revision %s, got %v", expectedPatch, revision.Spec.Patches)
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemonset
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+)
+
+// PatchMatchResult explains whether a node matches the selector of a patch.
+type PatchMatchResult struct {
+	// Matched is true if the node matches the selector of the patch.
+	Matched bool
+	// FailedRequirement is the first requirement of the selector not satisfied by the node,
+	// such as "zone in (a,b)", empty if matched or the selector is invalid.
+	FailedRequirement string
+	// Reason is why the node does not match, empty if matched.
+	Reason string
+}
+
+// ExplainMatch returns whether the node matches the selector of the patch, and the specific requirement
+// failed if not. The requirements from matchLabels and matchExpressions are checked in the sorted order of keys.
+func ExplainMatch(patch *appsv1beta1.DaemonSetPatch, node *corev1.Node) PatchMatchResult {
+	if patch.Selector == nil {
+		return PatchMatchResult{Reason: "selector is nil"}
+	}
+	selector, err := metav1.LabelSelectorAsSelector(patch.Selector)
+	if err != nil {
+		return PatchMatchResult{Reason: fmt.Sprintf("invalid selector: %v", err)}
+	}
+
+	nodeLabels := labels.Set(node.Labels)
+	requirements, _ := selector.Requirements()
+	for _, r := range requirements {
+		if r.Matches(nodeLabels) {
+			continue
+		}
+		reason := fmt.Sprintf("node has no label %s", r.Key())
+		if nodeLabels.Has(r.Key()) {
+			reason = fmt.Sprintf("node has label %s=%s", r.Key(), nodeLabels.Get(r.Key()))
+		}
+		return PatchMatchResult{FailedRequirement: r.String(), Reason: reason}
+	}
+	return PatchMatchResult{Matched: true}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemonset
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+)
+
+func TestExplainMatch(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "node-a",
+		Labels: map[string]string{"disk": "hdd", "zone": "zone-c"},
+	}}
+
+	tests := []struct {
+		name     string
+		selector *metav1.LabelSelector
+		expected PatchMatchResult
+	}{
+		{
+			name: "matched",
+			selector: &metav1.LabelSelector{
+				MatchLabels:      map[string]string{"disk": "hdd"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "zone", Operator: metav1.LabelSelectorOpIn, Values: []string{"zone-c"}}},
+			},
+			expected: PatchMatchResult{Matched: true},
+		},
+		{
+			name:     "matchLabels miss",
+			selector: &metav1.LabelSelector{MatchLabels: map[string]string{"disk": "ssd", "zone": "zone-c"}},
+			expected: PatchMatchResult{FailedRequirement: "disk=ssd", Reason: "node has label disk=hdd"},
+		},
+		{
+			name: "matchExpressions In miss",
+			selector: &metav1.LabelSelector{
+				MatchLabels:      map[string]string{"disk": "hdd"},
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "zone", Operator: metav1.LabelSelectorOpIn, Values: []string{"zone-a", "zone-b"}}},
+			},
+			expected: PatchMatchResult{FailedRequirement: "zone in (zone-a,zone-b)", Reason: "node has label zone=zone-c"},
+		},
+		{
+			name:     "label missing",
+			selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "gpu", Operator: metav1.LabelSelectorOpExists}}},
+			expected: PatchMatchResult{FailedRequirement: "gpu", Reason: "node has no label gpu"},
+		},
+		{
+			name:     "nil selector",
+			expected: PatchMatchResult{Reason: "selector is nil"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ExplainMatch(&appsv1beta1.DaemonSetPatch{Selector: tt.selector}, node)
+			if result != tt.expected {
+				t.Fatalf("expected %+v, got %+v", tt.expected, result)
+			}
+			if result.Matched != matchesNodeSelector(node, tt.selector) {
+				t.Fatalf("expected consistent with matchesNodeSelector, got %v", result.Matched)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemonset
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/klog/v2"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+)
+
+var (
+	// renderFailureThreshold is the number of consecutive failures to render the patches of a DaemonSet,
+	// across its nodes, that trips the circuit breaker. 0 disables the circuit breaker.
+	renderFailureThreshold = 10
+	// renderBreakerInitialBackoff is how long the circuit breaker stays open after it trips for the first time.
+	renderBreakerInitialBackoff = 30 * time.Second
+	// renderBreakerMaxBackoff bounds how long the circuit breaker stays open, which doubles every time it trips
+	// again for the same spec of the DaemonSet.
+	renderBreakerMaxBackoff = 10 * time.Minute
+)
+
+const (
+	// patchRenderCircuitOpenReason is the reason of the condition and event when the circuit breaker trips.
+	patchRenderCircuitOpenReason = "PatchRenderFailuresExceeded"
+)
+
+// renderCircuitBreaker counts the consecutive failures to render spec.patches across the nodes of each DaemonSet.
+// Once the failures reach renderFailureThreshold, it opens and the controller pauses creating and recreating the
+// daemon pods on the nodes matched by the patches, instead of retrying the patches that fail for most of the nodes.
+// It half-opens after the backoff, so that one more failure trips it again with a doubled backoff, and it closes
+// on the first success or once the DaemonSet is changed, e.g., the patches are fixed.
+type renderCircuitBreaker struct {
+	lock sync.Mutex
+	// states and backoff are keyed by the namespace/name of DaemonSet
+	states  map[string]*renderBreakerState
+	backoff *flowcontrol.Backoff
+}
+
+type renderBreakerState struct {
+	// uid and generation are of the DaemonSet that the failures are counted for
+	uid        types.UID
+	generation int64
+	failures   int
+	lastErr    error
+}
+
+func newRenderCircuitBreaker(backoff *flowcontrol.Backoff) *renderCircuitBreaker {
+	return &renderCircuitBreaker{
+		states:  make(map[string]*renderBreakerState),
+		backoff: backoff,
+	}
+}
+
+// getState returns the state of the DaemonSet, which is reset if the DaemonSet has been changed since the
+// failures counted. It must be called with the lock held.
+func (b *renderCircuitBreaker) getState(ds *appsv1beta1.DaemonSet) *renderBreakerState {
+	key := keyFunc(ds)
+	state := b.states[key]
+	if state != nil && (state.uid != ds.UID || state.generation != ds.Generation) {
+		b.backoff.Reset(key)
+		delete(b.states, key)
+		state = nil
+	}
+	return state
+}
+
+// observe records the result of rendering the patches of the DaemonSet for a node, and returns true if the
+// failure trips the circuit breaker. Only the failures to apply the patches are counted, because the errors of
+// the schema and the transient errors of TemplateRenderer are not caused by the DaemonSet itself. Likewise, only
+// the successes on the nodes matched by any of the patches close the circuit breaker, otherwise the nodes without
+// patches would keep resetting the failures of the patches that can never be rendered.
+func (b *renderCircuitBreaker) observe(ds *appsv1beta1.DaemonSet, node *corev1.Node, err error) bool {
+	if renderFailureThreshold <= 0 {
+		return false
+	}
+	var renderErr *patchRenderError
+	if err != nil && (!errors.As(err, &renderErr) || IsPatchSchemaError(err)) {
+		return false
+	}
+	if err == nil && !nodeMatchesPatches(node, ds.Spec.Patches) {
+		return false
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	key := keyFunc(ds)
+	state := b.getState(ds)
+	if err == nil {
+		if state != nil {
+			b.backoff.Reset(key)
+			delete(b.states, key)
+		}
+		return false
+	}
+	if state == nil {
+		state = &renderBreakerState{uid: ds.UID, generation: ds.Generation}
+		b.states[key] = state
+	}
+	state.failures++
+	state.lastErr = err
+	now := b.backoff.Clock.Now()
+	if state.failures < renderFailureThreshold || b.backoff.IsInBackOffSinceUpdate(key, now) {
+		return false
+	}
+	b.backoff.Next(key, now)
+	return true
+}
+
+// isOpen returns true with the backoff if the circuit breaker of the DaemonSet is open.
+func (b *renderCircuitBreaker) isOpen(ds *appsv1beta1.DaemonSet) (time.Duration, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	state := b.getState(ds)
+	if state == nil || state.failures < renderFailureThreshold {
+		return 0, false
+	}
+	key := keyFunc(ds)
+	if !b.backoff.IsInBackOffSinceUpdate(key, b.backoff.Clock.Now()) {
+		return 0, false
+	}
+	return b.backoff.Get(key), true
+}
+
+// tripped returns the number of consecutive failures and the last error if the circuit breaker of the DaemonSet
+// has tripped and not closed yet, including when it is half-open.
+func (b *renderCircuitBreaker) tripped(ds *appsv1beta1.DaemonSet) (int, error, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	state := b.getState(ds)
+	if state == nil || renderFailureThreshold <= 0 || state.failures < renderFailureThreshold {
+		return 0, nil, false
+	}
+	return state.failures, state.lastErr, true
+}
+
+// forget drops the state of the DaemonSet deleted.
+func (b *renderCircuitBreaker) forget(key string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.backoff.Reset(key)
+	delete(b.states, key)
+}
+
+// newPatchRenderConditions returns the conditions of the DaemonSet with the PatchRenderCircuitOpen condition
+// updated by the circuit breaker. The condition is added once the circuit breaker trips, and turned to false
+// after it closes.
+func (dsc *ReconcileDaemonSet) newPatchRenderConditions(ds *appsv1beta1.DaemonSet) []apps.DaemonSetCondition {
+	condition := apps.DaemonSetCondition{
+		Type:   appsv1beta1.DaemonSetPatchRenderCircuitOpen,
+		Status: corev1.ConditionFalse,
+		Reason: "PatchesRendered",
+	}
+	if failures, err, ok := dsc.renderBreaker.tripped(ds); ok {
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = patchRenderCircuitOpenReason
+		condition.Message = fmt.Sprintf("paused creating pods on the nodes matched by spec.patches after %d consecutive failures to render them: %v", failures, err)
+	}
+
+	conditions := make([]apps.DaemonSetCondition, 0, len(ds.Status.Conditions)+1)
+	var found bool
+	for _, c := range ds.Status.Conditions {
+		if c.Type != condition.Type {
+			conditions = append(conditions, c)
+			continue
+		}
+		found = true
+		if c.Status == condition.Status && c.Reason == condition.Reason && c.Message == condition.Message {
+			conditions = append(conditions, c)
+			continue
+		}
+		condition.LastTransitionTime = c.LastTransitionTime
+		if c.Status != condition.Status {
+			condition.LastTransitionTime = metav1.NewTime(dsc.failedPodsBackoff.Clock.Now())
+		}
+		conditions = append(conditions, condition)
+	}
+	if !found && condition.Status == corev1.ConditionTrue {
+		condition.LastTransitionTime = metav1.NewTime(dsc.failedPodsBackoff.Clock.Now())
+		conditions = append(conditions, condition)
+	}
+	if len(conditions) == 0 {
+		return nil
+	}
+	return conditions
+}
+
+// filterNodesWithOpenRenderBreaker removes the nodes matched by patches from the nodes needing daemon pods if the
+// circuit breaker of the DaemonSet is open, so that no more pods are created with the patches failed to render.
+func (dsc *ReconcileDaemonSet) filterNodesWithOpenRenderBreaker(ds *appsv1beta1.DaemonSet, nodesNeedingDaemonPods []string) []string {
+	if len(ds.Spec.Patches) == 0 || len(nodesNeedingDaemonPods) == 0 {
+		return nodesNeedingDaemonPods
+	}
+	if _, open := dsc.renderBreaker.isOpen(ds); !open {
+		return nodesNeedingDaemonPods
+	}
+	nodeNames := make([]string, 0, len(nodesNeedingDaemonPods))
+	for _, nodeName := range nodesNeedingDaemonPods {
+		node, err := dsc.nodeLister.Get(nodeName)
+		if err == nil && nodeMatchesPatches(node, ds.Spec.Patches) {
+			continue
+		}
+		nodeNames = append(nodeNames, nodeName)
+	}
+	if skipped := len(nodesNeedingDaemonPods) - len(nodeNames); skipped > 0 {
+		klog.V(3).InfoS("DaemonSet paused creating the patched pods as the render circuit breaker is open", "daemonSet", klog.KObj(ds), "nodeCount", skipped)
+	}
+	return nodeNames
+}
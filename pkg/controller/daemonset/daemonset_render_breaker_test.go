@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemonset
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	apps "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/flowcontrol"
+	testingclock "k8s.io/utils/clock/testing"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/features"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+)
+
+func TestPatchRenderCircuitBreaker(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.DaemonSetPatches, true)()
+	defer func(threshold int) { renderFailureThreshold = threshold }(renderFailureThreshold)
+	renderFailureThreshold = 2
+
+	ds := newDaemonSet("foo")
+	ds.Generation = 1
+	ds.Spec.Patches = []appsv1beta1.DaemonSetPatch{{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+		Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"hostNetwork":"yes"}}`)},
+	}}
+	manager, podControl, _, err := newTestController(ds)
+	if err != nil {
+		t.Fatalf("error creating DaemonSets controller: %v", err)
+	}
+	clock := testingclock.NewFakeClock(time.Now())
+	manager.renderBreaker = newRenderCircuitBreaker(flowcontrol.NewFakeBackOff(time.Minute, 4*time.Minute, clock))
+	addNodes(manager.nodeStore, 0, 3, map[string]string{"zone": "a"})
+	if err = manager.dsStore.Add(ds); err != nil {
+		t.Fatal(err)
+	}
+
+	getCondition := func() *apps.DaemonSetCondition {
+		t.Helper()
+		updated, err := manager.kruiseClient.AppsV1beta1().DaemonSets(ds.Namespace).Get(context.TODO(), ds.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range updated.Status.Conditions {
+			if updated.Status.Conditions[i].Type == appsv1beta1.DaemonSetPatchRenderCircuitOpen {
+				return &updated.Status.Conditions[i]
+			}
+		}
+		return nil
+	}
+
+	// the pod on the first node is created without the patches, and the breaker trips on the second failure
+	expectSyncDaemonSets(t, manager, ds, podControl, 1, 0, 1)
+	if delay, open := manager.renderBreaker.isOpen(ds); !open || delay != time.Minute {
+		t.Fatalf("expected breaker open for %v, got open %v for %v", time.Minute, open, delay)
+	}
+	if c := getCondition(); c == nil || c.Status != corev1.ConditionTrue || c.Reason != patchRenderCircuitOpenReason {
+		t.Fatalf("expected condition of open breaker, got %+v", c)
+	}
+
+	// no more pods are created on the patched nodes while the breaker is open
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 0, 1)
+
+	// the breaker half-opens after the backoff, and trips again with a doubled backoff on the next failure
+	clock.Step(time.Minute)
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 0, 2)
+	if delay, open := manager.renderBreaker.isOpen(ds); !open || delay != 2*time.Minute {
+		t.Fatalf("expected breaker open for %v, got open %v for %v", 2*time.Minute, open, delay)
+	}
+
+	// the breaker resets once the patch is fixed, and the pods are created with the patch
+	ds, err = manager.kruiseClient.AppsV1beta1().DaemonSets(ds.Namespace).Get(context.TODO(), ds.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds.Generation++
+	ds.Spec.Patches[0].Patch = runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"zone":"a"}}}`)}
+	if err = manager.dsStore.Update(ds); err != nil {
+		t.Fatal(err)
+	}
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 2, 0, 4)
+	if _, open := manager.renderBreaker.isOpen(ds); open {
+		t.Fatalf("expected breaker closed after the patch fixed")
+	}
+	if _, _, tripped := manager.renderBreaker.tripped(ds); tripped {
+		t.Fatalf("expected breaker reset after the patch fixed")
+	}
+	for _, template := range podControl.Templates {
+		if template.Labels["zone"] != "a" {
+			t.Fatalf("expected pod created with the patch, got labels %v", template.Labels)
+		}
+	}
+	if c := getCondition(); c == nil || c.Status != corev1.ConditionFalse {
+		t.Fatalf("expected condition of closed breaker, got %+v", c)
+	}
+}
+
+func TestPatchRenderCircuitBreakerIgnoresUnpatchedNodes(t *testing.T) {
+	defer func(threshold int) { renderFailureThreshold = threshold }(renderFailureThreshold)
+	renderFailureThreshold = 2
+
+	ds := newDaemonSet("foo")
+	ds.Generation = 1
+	ds.Spec.Patches = []appsv1beta1.DaemonSetPatch{{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+		Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"hostNetwork":"yes"}}`)},
+	}}
+	patched := newNode("patched", map[string]string{"zone": "a"})
+	unpatched := newNode("unpatched", map[string]string{"zone": "b"})
+	renderErr := &patchRenderError{err: fmt.Errorf("cannot unmarshal string into bool")}
+
+	breaker := newRenderCircuitBreaker(flowcontrol.NewFakeBackOff(time.Minute, 4*time.Minute, testingclock.NewFakeClock(time.Now())))
+	if breaker.observe(ds, patched, renderErr) {
+		t.Fatalf("expected breaker not tripped on the first failure")
+	}
+	// the pods on the nodes without patches are always rendered successfully
+	breaker.observe(ds, unpatched, nil)
+	if !breaker.observe(ds, patched, renderErr) {
+		t.Fatalf("expected breaker tripped on the second failure")
+	}
+
+	// a success on a patched node closes the breaker
+	breaker.observe(ds, patched, nil)
+	if _, _, tripped := breaker.tripped(ds); tripped {
+		t.Fatalf("expected breaker closed after a patched node rendered")
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemonset
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+)
+
+// TemplateRenderer post-processes the pod template of a DaemonSet for a node, after the patches
+// matching the node are applied, e.g., to inject secrets rendered by an external system.
+type TemplateRenderer interface {
+	// Render receives the node and a copy of the patched template, which can be modified in place,
+	// and returns the template that the daemon pod on the node will be created with.
+	// It must be deterministic for the same inputs, because the result is hashed to decide whether
+	// the daemon pod on the node should be updated.
+	Render(node *corev1.Node, template *corev1.PodTemplateSpec) (*corev1.PodTemplateSpec, error)
+	// ID returns the name and version of the renderer, e.g., "vault-injector/v1", which is hashed together
+	// with the rendered template. It should be a constant, and bumped only when the renderer renders differently
+	// for the same inputs, so that the daemon pods are recreated by the new version.
+	ID() string
+}
+
+// noopTemplateRendererID is the ID of the default renderer, which is not hashed to keep the existing hashes.
+const noopTemplateRendererID = "noop/v1"
+
+// noopTemplateRenderer is the default renderer which returns the template as it is.
+type noopTemplateRenderer struct{}
+
+func (noopTemplateRenderer) ID() string {
+	return noopTemplateRendererID
+}
+
+func (noopTemplateRenderer) Render(_ *corev1.Node, template *corev1.PodTemplateSpec) (*corev1.PodTemplateSpec, error) {
+	return template, nil
+}
+
+func isNoopTemplateRenderer(renderer TemplateRenderer) bool {
+	_, ok := renderer.(noopTemplateRenderer)
+	return ok
+}
+
+const (
+	// renderRetryInitialBackoff is the initial delay to retry rendering pod template after a transient error.
+	renderRetryInitialBackoff = time.Second
+	// maxRenderRetryBackoff bounds the retries of rendering pod template for a node, the controller gives up
+	// retrying once the backoff reaches it, which is about 30 seconds after the first transient error.
+	maxRenderRetryBackoff = 32 * time.Second
+)
+
+var (
+	templateRendererLock sync.RWMutex
+	templateRenderer     TemplateRenderer = noopTemplateRenderer{}
+)
+
+// RegisterTemplateRenderer registers the renderer called by the controller after applying patches to
+// the pod template. It should be called before the controller starts, and nil restores the default
+// no-op renderer.
+func RegisterTemplateRenderer(renderer TemplateRenderer) {
+	templateRendererLock.Lock()
+	defer templateRendererLock.Unlock()
+	if renderer == nil {
+		renderer = noopTemplateRenderer{}
+	}
+	templateRenderer = renderer
+}
+
+func getTemplateRenderer() TemplateRenderer {
+	templateRendererLock.RLock()
+	defer templateRendererLock.RUnlock()
+	return templateRenderer
+}
+
+// renderPodTemplate applies the patches matching the node to the template, and then calls the registered renderer.
+// It is called in the reconcile path, so the errors of applying the patches are counted in metrics.
+func renderPodTemplate(ds *appsv1beta1.DaemonSet, node *corev1.Node, template *corev1.PodTemplateSpec) (*corev1.PodTemplateSpec, error) {
+	patchedTemplate, err := applyPatchesToPodTemplate(ds, node, template)
+	if err != nil {
+		recordPatchRenderError(err)
+		return nil, err
+	}
+	return renderPatchedPodTemplate(node, patchedTemplate)
+}
+
+// renderPatchedPodTemplate calls the registered renderer with the template that the patches have been applied to.
+func renderPatchedPodTemplate(node *corev1.Node, patchedTemplate *corev1.PodTemplateSpec) (*corev1.PodTemplateSpec, error) {
+	renderer := getTemplateRenderer()
+	if isNoopTemplateRenderer(renderer) {
+		return patchedTemplate, nil
+	}
+	// the patched template may be the one in DaemonSet from cache, so the renderer always gets a copy
+	renderedTemplate, err := renderer.Render(node, patchedTemplate.DeepCopy())
+	if err != nil {
+		return nil, fmt.Errorf("failed to render pod template by %T: %w", renderer, err)
+	}
+	return renderedTemplate, nil
+}
+
+// transientRenderError is an error of TemplateRenderer caused by the inputs temporarily unavailable.
+type transientRenderError struct {
+	err error
+}
+
+func (e *transientRenderError) Error() string { return e.err.Error() }
+
+func (e *transientRenderError) Unwrap() error { return e.err }
+
+// NewTransientRenderError wraps the error returned by TemplateRenderer, when the inputs of rendering are
+// temporarily unavailable, e.g., a ConfigMap that has not been created yet. The controller retries
+// rendering with backoff instead of creating the daemon pod with an unrendered template.
+func NewTransientRenderError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &transientRenderError{err: err}
+}
+
+// IsTransientRenderError returns true if rendering pod template failed because of the inputs temporarily
+// unavailable, which is either wrapped by NewTransientRenderError or a retriable error from apiserver.
+// Errors of applying the patches are permanent, because they are decided by the DaemonSet itself.
+func IsTransientRenderError(err error) bool {
+	var transientErr *transientRenderError
+	if errors.As(err, &transientErr) {
+		return true
+	}
+	return apierrors.IsNotFound(err) || apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err) ||
+		apierrors.IsTooManyRequests(err) || apierrors.IsServiceUnavailable(err)
+}
+
+const (
+	// patchRenderErrorDecode means the patch is not a valid strategic merge patch document.
+	patchRenderErrorDecode = "decode"
+	// patchRenderErrorMerge means the patch failed to be merged into the pod template.
+	patchRenderErrorMerge = "merge"
+	// patchRenderErrorValidation means the pod template is invalid after all the patches applied.
+	patchRenderErrorValidation = "validation"
+	// patchRenderErrorSchema means the strategic merge schema of pod template is unavailable or incomplete.
+	patchRenderErrorSchema = "schema"
+)
+
+var DaemonSetPatchRenderErrorsMetrics = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "kruise_daemonset_patch_render_errors_total",
+		Help: "Number of errors of applying spec.patches to the pod template of Advanced DaemonSet, by reason",
+	}, []string{"reason"},
+)
+
+// patchRenderError is the error of applying spec.patches with its reason.
+type patchRenderError struct {
+	reason string
+	err    error
+}
+
+func (e *patchRenderError) Error() string { return e.err.Error() }
+
+func (e *patchRenderError) Unwrap() error { return e.err }
+
+// recordPatchRenderError counts the error of applying spec.patches in the reconcile path.
+func recordPatchRenderError(err error) {
+	var renderErr *patchRenderError
+	if errors.As(err, &renderErr) {
+		DaemonSetPatchRenderErrorsMetrics.WithLabelValues(renderErr.reason).Inc()
+	}
+}
+
+// IsPatchSchemaError returns true if applying spec.patches failed because the strategic merge schema of
+// pod template is unavailable, which fails all the patches instead of something wrong in the DaemonSet.
+func IsPatchSchemaError(err error) bool {
+	var renderErr *patchRenderError
+	return errors.As(err, &renderErr) && renderErr.reason == patchRenderErrorSchema
+}
@@ -0,0 +1,268 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemonset
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/kubernetes/pkg/controller"
+	testingclock "k8s.io/utils/clock/testing"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+)
+
+type fakeTemplateRenderer struct {
+	err error
+}
+
+func (r *fakeTemplateRenderer) ID() string {
+	return "fake/v1"
+}
+
+func (r *fakeTemplateRenderer) Render(node *corev1.Node, template *corev1.PodTemplateSpec) (*corev1.PodTemplateSpec, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	for i := range template.Spec.Containers {
+		template.Spec.Containers[i].Env = append(template.Spec.Containers[i].Env, corev1.EnvVar{Name: "SECRET", Value: "rendered-for-" + node.Name})
+	}
+	return template, nil
+}
+
+type identityTemplateRenderer struct {
+	id string
+}
+
+func (r identityTemplateRenderer) ID() string {
+	return r.id
+}
+
+func (identityTemplateRenderer) Render(_ *corev1.Node, template *corev1.PodTemplateSpec) (*corev1.PodTemplateSpec, error) {
+	return template, nil
+}
+
+func TestRenderPodTemplate(t *testing.T) {
+	defer RegisterTemplateRenderer(nil)
+
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"type": "gpu"}}}
+	ds := &appsv1beta1.DaemonSet{
+		Spec: appsv1beta1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "main", Image: "base-image"}},
+				},
+			},
+			Patches: []appsv1beta1.DaemonSetPatch{
+				{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"type": "gpu"}},
+					Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"main","image":"gpu-image"}]}}`)},
+				},
+			},
+		},
+	}
+	defaultHash, err := computeRenderHash(ds, node)
+	if err != nil {
+		t.Fatalf("Failed to compute render hash: %v", err)
+	}
+
+	RegisterTemplateRenderer(&fakeTemplateRenderer{})
+	template, err := renderPodTemplate(ds, node, &ds.Spec.Template)
+	if err != nil {
+		t.Fatalf("Failed to render pod template: %v", err)
+	}
+	container := template.Spec.Containers[0]
+	if container.Image != "gpu-image" {
+		t.Errorf("Expected the renderer called after patches, got image %s", container.Image)
+	}
+	if len(container.Env) != 1 || container.Env[0].Value != "rendered-for-node-1" {
+		t.Errorf("Expected env rendered for node-1, got %v", container.Env)
+	}
+	if len(ds.Spec.Template.Spec.Containers[0].Env) != 0 {
+		t.Errorf("Expected spec.template not modified, got %v", ds.Spec.Template.Spec.Containers[0].Env)
+	}
+
+	// the renderer is also called for DaemonSet without patches
+	ds.Spec.Patches = nil
+	template, err = renderPodTemplate(ds, node, &ds.Spec.Template)
+	if err != nil {
+		t.Fatalf("Failed to render pod template: %v", err)
+	}
+	if len(template.Spec.Containers[0].Env) != 1 || len(ds.Spec.Template.Spec.Containers[0].Env) != 0 {
+		t.Errorf("Expected env rendered on a copy of spec.template, got %v", template.Spec.Containers[0].Env)
+	}
+	ds.Spec.Patches = []appsv1beta1.DaemonSetPatch{{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"type": "gpu"}},
+		Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"main","image":"gpu-image"}]}}`)},
+	}}
+
+	renderedHash, err := computeRenderHash(ds, node)
+	if err != nil {
+		t.Fatalf("Failed to compute render hash: %v", err)
+	}
+	if renderedHash == defaultHash {
+		t.Errorf("Expected render hash changed by the renderer")
+	}
+
+	// the renderer registered is hashed as well, even if it renders the same template as the default one
+	RegisterTemplateRenderer(identityTemplateRenderer{id: "identity/v1"})
+	identityHash, err := computeRenderHash(ds, node)
+	if err != nil {
+		t.Fatalf("Failed to compute render hash: %v", err)
+	}
+	if identityHash == defaultHash {
+		t.Errorf("Expected render hash changed by registering a different renderer")
+	}
+	// a new version of the renderer is hashed differently as well
+	RegisterTemplateRenderer(identityTemplateRenderer{id: "identity/v2"})
+	if hash, err := computeRenderHash(ds, node); err != nil {
+		t.Fatalf("Failed to compute render hash: %v", err)
+	} else if hash == identityHash {
+		t.Errorf("Expected render hash changed by bumping the version of renderer")
+	}
+
+	RegisterTemplateRenderer(&fakeTemplateRenderer{err: fmt.Errorf("vault unavailable")})
+	if _, err = renderPodTemplate(ds, node, &ds.Spec.Template); err == nil {
+		t.Errorf("Expected error of the renderer returned")
+	}
+
+	// nil restores the default no-op renderer
+	RegisterTemplateRenderer(nil)
+	template, err = renderPodTemplate(ds, node, &ds.Spec.Template)
+	if err != nil {
+		t.Fatalf("Failed to render pod template: %v", err)
+	}
+	if len(template.Spec.Containers[0].Env) != 0 {
+		t.Errorf("Expected no env rendered by the default renderer, got %v", template.Spec.Containers[0].Env)
+	}
+}
+
+// configMapTemplateRenderer renders the env from a ConfigMap, which may be missing for a while.
+type configMapTemplateRenderer struct {
+	lock  sync.Mutex
+	value *string
+}
+
+func (r *configMapTemplateRenderer) ID() string {
+	return "configmap/v1"
+}
+
+func (r *configMapTemplateRenderer) setValue(value *string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.value = value
+}
+
+func (r *configMapTemplateRenderer) Render(_ *corev1.Node, template *corev1.PodTemplateSpec) (*corev1.PodTemplateSpec, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.value == nil {
+		return nil, apierrors.NewNotFound(corev1.Resource("configmaps"), "secrets")
+	}
+	for i := range template.Spec.Containers {
+		template.Spec.Containers[i].Env = append(template.Spec.Containers[i].Env, corev1.EnvVar{Name: "SECRET", Value: *r.value})
+	}
+	return template, nil
+}
+
+func TestIsTransientRenderError(t *testing.T) {
+	cases := []struct {
+		err       error
+		transient bool
+	}{
+		{err: fmt.Errorf("failed to apply spec.patches[0] with priority 0: invalid"), transient: false},
+		{err: fmt.Errorf("failed to render pod template: %w", apierrors.NewNotFound(corev1.Resource("configmaps"), "secrets")), transient: true},
+		{err: apierrors.NewTooManyRequests("throttled", 1), transient: true},
+		{err: apierrors.NewForbidden(corev1.Resource("configmaps"), "secrets", fmt.Errorf("denied")), transient: false},
+		{err: NewTransientRenderError(fmt.Errorf("vault sealed")), transient: true},
+	}
+	for _, c := range cases {
+		if got := IsTransientRenderError(c.err); got != c.transient {
+			t.Errorf("expected transient %v for %v, got %v", c.transient, c.err, got)
+		}
+	}
+}
+
+func TestSyncNodesRetryTransientRenderError(t *testing.T) {
+	renderer := &configMapTemplateRenderer{}
+	RegisterTemplateRenderer(renderer)
+	defer RegisterTemplateRenderer(nil)
+
+	ds := newDaemonSet("foo")
+	manager, podControl, _, err := newTestController(ds)
+	if err != nil {
+		t.Fatalf("error creating DaemonSets controller: %v", err)
+	}
+	clock := testingclock.NewFakeClock(time.Now())
+	manager.renderRetryBackoff = flowcontrol.NewFakeBackOff(renderRetryInitialBackoff, maxRenderRetryBackoff, clock)
+	addNodes(manager.nodeStore, 0, 1, nil)
+	manager.dsStore.Add(ds)
+
+	// the ConfigMap is missing, so no pod created and retry later
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 0, 0)
+	if delay := manager.renderRetryBackoff.Get(failedPodsBackoffKey(ds, "node-0")); delay != renderRetryInitialBackoff {
+		t.Fatalf("expected retry after %v, got %v", renderRetryInitialBackoff, delay)
+	}
+	// syncing again within the backoff does not count a retry
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 0, 0)
+	if delay := manager.renderRetryBackoff.Get(failedPodsBackoffKey(ds, "node-0")); delay != renderRetryInitialBackoff {
+		t.Fatalf("expected retry after %v, got %v", renderRetryInitialBackoff, delay)
+	}
+
+	// the ConfigMap is created, and the pod is created with the rendered template on retry
+	value := "from-configmap"
+	renderer.setValue(&value)
+	clock.Step(renderRetryInitialBackoff)
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 1, 0, 0)
+	if env := podControl.Templates[0].Spec.Containers[0].Env; len(env) != 1 || env[0].Value != value {
+		t.Fatalf("expected pod created with rendered env, got %v", env)
+	}
+	if delay := manager.renderRetryBackoff.Get(failedPodsBackoffKey(ds, "node-0")); delay != 0 {
+		t.Fatalf("expected backoff reset after rendered, got %v", delay)
+	}
+
+	// the retries are bounded, the pod is created as rendering failed permanently at last
+	renderer.setValue(nil)
+	addNodes(manager.nodeStore, 1, 1, nil)
+	dsKey, _ := controller.KeyFunc(ds)
+	var retries int
+	for ; retries < 10; retries++ {
+		clearExpectations(t, manager, ds, podControl)
+		if err := manager.syncHandler(dsKey); err != nil {
+			t.Fatal(err)
+		}
+		if len(podControl.Templates) > 0 {
+			break
+		}
+		clock.Step(manager.renderRetryBackoff.Get(failedPodsBackoffKey(ds, "node-1")))
+	}
+	if retries != 5 {
+		t.Fatalf("expected pod created after 5 retries, got %d retries", retries)
+	}
+	if env := podControl.Templates[0].Spec.Containers[0].Env; len(env) != 0 {
+		t.Fatalf("expected pod created without rendered env, got %v", env)
+	}
+}
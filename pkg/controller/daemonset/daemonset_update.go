@@ -17,7 +17,9 @@ limitations under the License.
 package daemonset
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strconv"
@@ -32,10 +34,14 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+	kubecontroller "k8s.io/kubernetes/pkg/controller"
+	daemonsetutil "k8s.io/kubernetes/pkg/controller/daemon/util"
 
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
 	clonesetutils "github.com/openkruise/kruise/pkg/controller/cloneset/utils"
+	"github.com/openkruise/kruise/pkg/features"
 	"github.com/openkruise/kruise/pkg/util"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
 	"github.com/openkruise/kruise/pkg/util/inplaceupdate"
 )
 
@@ -47,6 +53,15 @@ func (dsc *ReconcileDaemonSet) rollingUpdate(ctx context.Context, ds *appsv1beta
 	if err != nil {
 		return fmt.Errorf("couldn't get node to daemon pod mapping for daemon set %q: %v", ds.Name, err)
 	}
+	// Advanced: only roll out the nodes whose update strategy is not overridden to OnDelete by patches
+	nodeList = filterNodesWithOnDeleteStrategy(ds, nodeList, nodeToDaemonPods)
+	// Advanced: keep the old pods on the nodes matched by patches if the patches can not be applied
+	dsc.skipNodesWithoutPatchSchema(ds, nodeList, hash, nodeToDaemonPods)
+	// Advanced: keep the old pods on the nodes matched by patches if rendering the patches keeps failing
+	dsc.skipNodesWithOpenRenderBreaker(ds, nodeList, hash, nodeToDaemonPods)
+	if err := dsc.syncRenderedPods(ctx, ds, nodeList, hash, nodeToDaemonPods, oldRevisions); err != nil {
+		return fmt.Errorf("failed to sync rendered pods: %v", err)
+	}
 	maxSurge, maxUnavailable, err := dsc.updatedDesiredNodeCounts(ds, nodeList, nodeToDaemonPods)
 	if err != nil {
 		return fmt.Errorf("couldn't get unavailable numbers: %v", err)
@@ -57,6 +72,8 @@ func (dsc *ReconcileDaemonSet) rollingUpdate(ctx context.Context, ds *appsv1beta
 	if err != nil {
 		return fmt.Errorf("failed to filterDaemonPodsToUpdate: %v", err)
 	}
+	// Advanced: defer recreating the patched pods on the nodes which are not ready
+	dsc.skipNodesNotReadyForPatch(ds, nodeList, hash, nodeToDaemonPods, curRevision, oldRevisions)
 
 	now := dsc.failedPodsBackoff.Clock.Now()
 
@@ -139,7 +156,7 @@ func (dsc *ReconcileDaemonSet) rollingUpdate(ctx context.Context, ds *appsv1beta
 		oldPodsToDelete := append(allowedReplacementPods, candidatePodsToDelete[:remainingUnavailable]...)
 
 		// Advanced: update pods in-place first and still delete the others
-		if ds.Spec.UpdateStrategy.RollingUpdate.Type == appsv1beta1.InplaceRollingUpdateType {
+		if isInPlaceRollingUpdate(ds) {
 			oldPodsToDelete, err = dsc.inPlaceUpdatePods(ds, oldPodsToDelete, curRevision, oldRevisions)
 			if err != nil {
 				return err
@@ -460,3 +477,320 @@ func (dsc *ReconcileDaemonSet) inPlaceUpdatePods(ds *appsv1beta1.DaemonSet, podN
 
 	return podsNeedDelete, utilerrors.NewAggregate(errors)
 }
+
+// syncRenderedPods relabels the old pods to the current revision if the pod template rendered for
+// their nodes has not changed, so that editing a patch only recreates pods on the nodes it matches.
+// The old pods whose revision only differs in the patches applied to new pods only are relabeled as well.
+func (dsc *ReconcileDaemonSet) syncRenderedPods(ctx context.Context, ds *appsv1beta1.DaemonSet, nodeList []*corev1.Node, hash string,
+	nodeToDaemonPods map[string][]*corev1.Pod, oldRevisions []*apps.ControllerRevision) error {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.DaemonSetPatches) {
+		return nil
+	}
+	generation, err := GetTemplateGeneration(ds)
+	if err != nil {
+		generation = nil
+	}
+	stableHashes, err := stableRevisionHashes(ds, oldRevisions)
+	if err != nil {
+		return err
+	}
+
+	// the pods without render hash are created from spec.template without any patches, so they are only
+	// regarded as rendered from the base template if spec.template has not changed since then
+	baseTemplateHash := kubecontroller.ComputeHash(&ds.Spec.Template, ds.Status.CollisionCount)
+	for _, node := range nodeList {
+		pods := nodeToDaemonPods[node.Name]
+		var renderHash, baseRenderHash string
+		for i, pod := range pods {
+			if pod.DeletionTimestamp != nil || daemonsetutil.IsPodUpdated(pod, hash, generation) {
+				continue
+			}
+			if !stableHashes.Has(pod.Labels[apps.DefaultDaemonSetUniqueLabelKey]) {
+				podRenderHash := pod.Annotations[RenderHashAnnotation]
+				if podRenderHash == "" {
+					if pod.Labels[apps.DefaultDaemonSetUniqueLabelKey] != baseTemplateHash {
+						continue
+					}
+					if baseRenderHash == "" {
+						if baseRenderHash, err = computeBaseRenderHash(ds, node); err != nil {
+							return err
+						}
+					}
+					podRenderHash = baseRenderHash
+				}
+				if renderHash == "" {
+					if renderHash, err = computeRenderHash(ds, node); err != nil {
+						return err
+					}
+				}
+				if podRenderHash != renderHash {
+					continue
+				}
+			}
+
+			klog.V(3).InfoS("DaemonSet pod has the same rendered template, relabel it to current revision", "daemonSet", klog.KObj(ds), "pod", klog.KObj(pod), "nodeName", node.Name, "hash", hash)
+			patch := fmt.Sprintf(`{"metadata":{"labels":{"%s":"%s"}}}`, apps.DefaultDaemonSetUniqueLabelKey, hash)
+			if err := dsc.podControl.PatchPod(ctx, pod.Namespace, pod.Name, []byte(patch)); err != nil {
+				return err
+			}
+			clone := pod.DeepCopy()
+			if clone.Labels == nil {
+				clone.Labels = make(map[string]string)
+			}
+			clone.Labels[apps.DefaultDaemonSetUniqueLabelKey] = hash
+			pods[i] = clone
+		}
+	}
+	return nil
+}
+
+// syncShadowRenders records the pod template rendered with the shadow patches in the annotations of the pods on
+// the nodes matched by them, and removes the annotations from the pods on the other nodes, e.g., after the shadow
+// patches are promoted or removed.
+func (dsc *ReconcileDaemonSet) syncShadowRenders(ctx context.Context, ds *appsv1beta1.DaemonSet, nodeList []*corev1.Node,
+	nodeToDaemonPods map[string][]*corev1.Pod) error {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.DaemonSetPatches) {
+		return nil
+	}
+	for _, node := range nodeList {
+		pods := nodeToDaemonPods[node.Name]
+		if len(pods) == 0 {
+			continue
+		}
+		annotations, err := shadowRenderAnnotations(ds, node)
+		if err != nil {
+			// the live render errors are handled when the pods are created
+			klog.V(3).InfoS("DaemonSet failed to render pod template with shadow patches", "daemonSet", klog.KObj(ds), "nodeName", node.Name, "err", err)
+			continue
+		}
+		for i, pod := range pods {
+			if pod.DeletionTimestamp != nil {
+				continue
+			}
+			toPatch := make(map[string]interface{})
+			for _, key := range []string{ShadowRenderHashAnnotation, ShadowRenderDiffAnnotation} {
+				value, ok := annotations[key]
+				if current, exists := pod.Annotations[key]; ok && current != value {
+					toPatch[key] = value
+				} else if !ok && exists {
+					toPatch[key] = nil
+				}
+			}
+			if len(toPatch) == 0 {
+				continue
+			}
+			patch, _ := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"annotations": toPatch}})
+			if err := dsc.podControl.PatchPod(ctx, pod.Namespace, pod.Name, patch); err != nil {
+				return err
+			}
+			clone := pod.DeepCopy()
+			if clone.Annotations == nil {
+				clone.Annotations = make(map[string]string)
+			}
+			for k, v := range toPatch {
+				if v == nil {
+					delete(clone.Annotations, k)
+				} else {
+					clone.Annotations[k] = v.(string)
+				}
+			}
+			pods[i] = clone
+		}
+	}
+	return nil
+}
+
+// stableRevisionHashes returns the hashes of the old revisions that only differ from the DaemonSet in the patches
+// applied to new pods only, whose pods are kept rather than recreated.
+func stableRevisionHashes(ds *appsv1beta1.DaemonSet, oldRevisions []*apps.ControllerRevision) (sets.Set[string], error) {
+	hashes := sets.New[string]()
+	var stablePatch []byte
+	for _, r := range oldRevisions {
+		patches, err := GetPatchesFromRevision(r)
+		if err != nil {
+			return nil, err
+		}
+		if !hasNewPodsOnlyPatches(ds.Spec.Patches) && !hasNewPodsOnlyPatches(patches) {
+			continue
+		}
+		if stablePatch == nil {
+			if stablePatch, err = getStablePatch(ds); err != nil {
+				return nil, err
+			}
+		}
+		revisionPatch, err := getStablePatchFromRevision(r)
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(stablePatch, revisionPatch) {
+			hashes.Insert(r.Labels[apps.DefaultDaemonSetUniqueLabelKey])
+		}
+	}
+	return hashes, nil
+}
+
+// skipNodesNotReadyForPatch removes the nodes matched by patches from nodeToDaemonPods if they are not Ready or
+// schedulable and the old pods on them have to be recreated, when waitNodeReadyBeforePatch is enabled.
+// The nodes removed are neither updated nor counted as unavailable, and the DaemonSet is enqueued again once
+// they become ready.
+func (dsc *ReconcileDaemonSet) skipNodesNotReadyForPatch(ds *appsv1beta1.DaemonSet, nodeList []*corev1.Node, hash string,
+	nodeToDaemonPods map[string][]*corev1.Pod, curRevision *apps.ControllerRevision, oldRevisions []*apps.ControllerRevision) {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.DaemonSetPatches) || !waitNodeReadyBeforePatch(ds) {
+		return
+	}
+
+	nodes := make(map[string]*corev1.Node, len(nodeList))
+	for _, node := range nodeList {
+		nodes[node.Name] = node
+	}
+	for nodeName, pods := range nodeToDaemonPods {
+		node := nodes[nodeName]
+		if node == nil || isNodeReadyForPatch(node) {
+			continue
+		}
+		newPod, oldPod, ok := findUpdatedPodsOnNode(ds, pods, hash)
+		if !ok || newPod != nil || isPodNilOrPreDeleting(oldPod) {
+			continue
+		}
+		if !nodeMatchesPatches(node, ds.Spec.Patches) && !nodeMatchesPatches(node, dsc.getPodRevisionPatches(oldPod, oldRevisions)) {
+			continue
+		}
+		if isInPlaceRollingUpdate(ds) && dsc.canPodInPlaceUpdate(oldPod, curRevision, oldRevisions) {
+			continue
+		}
+		klog.V(3).InfoS("DaemonSet deferred recreating the patched pod until the node is ready", "daemonSet", klog.KObj(ds), "pod", klog.KObj(oldPod), "nodeName", nodeName)
+		delete(nodeToDaemonPods, nodeName)
+	}
+}
+
+// skipNodesWithoutPatchSchema removes the nodes matched by patches from nodeToDaemonPods if the strategic merge
+// schema of pod template is unavailable and the old pods on them have not been updated. The old pods are kept
+// rather than recreated with a template that has none of the patches, until the schema is available.
+func (dsc *ReconcileDaemonSet) skipNodesWithoutPatchSchema(ds *appsv1beta1.DaemonSet, nodeList []*corev1.Node, hash string,
+	nodeToDaemonPods map[string][]*corev1.Pod) {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.DaemonSetPatches) || len(ds.Spec.Patches) == 0 {
+		return
+	}
+	_, err := lookupPodTemplatePatchMeta()
+	if err == nil {
+		return
+	}
+
+	skipped := skipOldPodsOnPatchedNodes(ds, nodeList, hash, nodeToDaemonPods)
+	if len(skipped) > 0 {
+		klog.ErrorS(err, "DaemonSet kept the old pods on the patched nodes", "daemonSet", klog.KObj(ds), "nodeCount", len(skipped))
+		dsc.eventRecorder.Eventf(ds, corev1.EventTypeWarning, FailedRenderPatchesReason,
+			"keep the old pods on %d nodes matched by spec.patches: %v", len(skipped), err)
+	}
+}
+
+// skipNodesWithOpenRenderBreaker removes the nodes matched by patches from nodeToDaemonPods if the render circuit
+// breaker of the DaemonSet is open and the old pods on them have not been updated. The old pods are kept rather
+// than recreated, until the circuit breaker backs off or the patches are changed.
+func (dsc *ReconcileDaemonSet) skipNodesWithOpenRenderBreaker(ds *appsv1beta1.DaemonSet, nodeList []*corev1.Node, hash string,
+	nodeToDaemonPods map[string][]*corev1.Pod) {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.DaemonSetPatches) || len(ds.Spec.Patches) == 0 {
+		return
+	}
+	if _, open := dsc.renderBreaker.isOpen(ds); !open {
+		return
+	}
+
+	if skipped := skipOldPodsOnPatchedNodes(ds, nodeList, hash, nodeToDaemonPods); len(skipped) > 0 {
+		klog.V(3).InfoS("DaemonSet kept the old pods on the patched nodes as the render circuit breaker is open", "daemonSet", klog.KObj(ds), "nodeCount", len(skipped))
+	}
+}
+
+// nodeUpdateStrategyType returns the update strategy type for the daemon pod on the node, which is overridden by the
+// patches matching the node, the one applied last wins.
+func nodeUpdateStrategyType(ds *appsv1beta1.DaemonSet, node *corev1.Node) appsv1beta1.DaemonSetUpdateStrategyType {
+	strategyType := ds.Spec.UpdateStrategy.Type
+	if !utilfeature.DefaultFeatureGate.Enabled(features.DaemonSetPatches) {
+		return strategyType
+	}
+	for _, i := range sortedPatchIndexes(ds) {
+		patch := &ds.Spec.Patches[i]
+		if patch.UpdateStrategyType == "" || patch.Shadow || !matchesNodeSelector(node, patch.Selector) {
+			continue
+		}
+		strategyType = patch.UpdateStrategyType
+	}
+	return strategyType
+}
+
+// hasPatchUpdateStrategyType returns true if any of the patches overrides the update strategy with the given type.
+func hasPatchUpdateStrategyType(ds *appsv1beta1.DaemonSet, strategyType appsv1beta1.DaemonSetUpdateStrategyType) bool {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.DaemonSetPatches) {
+		return false
+	}
+	for i := range ds.Spec.Patches {
+		if ds.Spec.Patches[i].UpdateStrategyType == strategyType && !ds.Spec.Patches[i].Shadow {
+			return true
+		}
+	}
+	return false
+}
+
+// filterNodesWithOnDeleteStrategy removes the nodes whose update strategy is OnDelete, either overridden by patches
+// or inherited from the DaemonSet, from nodeToDaemonPods and returns the other nodes, so that their old pods are
+// only replaced when deleted, and they are not counted in maxUnavailable and maxSurge of the nodes rolled out.
+func filterNodesWithOnDeleteStrategy(ds *appsv1beta1.DaemonSet, nodeList []*corev1.Node, nodeToDaemonPods map[string][]*corev1.Pod) []*corev1.Node {
+	if !hasPatchUpdateStrategyType(ds, appsv1beta1.OnDeleteDaemonSetStrategyType) &&
+		!hasPatchUpdateStrategyType(ds, appsv1beta1.RollingUpdateDaemonSetStrategyType) {
+		return nodeList
+	}
+	nodes := make([]*corev1.Node, 0, len(nodeList))
+	for _, node := range nodeList {
+		if nodeUpdateStrategyType(ds, node) == appsv1beta1.OnDeleteDaemonSetStrategyType {
+			delete(nodeToDaemonPods, node.Name)
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	if skipped := len(nodeList) - len(nodes); skipped > 0 {
+		klog.V(5).InfoS("DaemonSet skipped rolling update on the nodes of OnDelete strategy", "daemonSet", klog.KObj(ds), "nodeCount", skipped)
+	}
+	return nodes
+}
+
+// isInPlaceRollingUpdate returns true if the old pods are updated in-place when possible.
+func isInPlaceRollingUpdate(ds *appsv1beta1.DaemonSet) bool {
+	return ds.Spec.UpdateStrategy.RollingUpdate != nil && ds.Spec.UpdateStrategy.RollingUpdate.Type == appsv1beta1.InplaceRollingUpdateType
+}
+
+// skipOldPodsOnPatchedNodes removes the nodes matched by patches, whose old pods have not been updated, from
+// nodeToDaemonPods, and returns the names of them.
+func skipOldPodsOnPatchedNodes(ds *appsv1beta1.DaemonSet, nodeList []*corev1.Node, hash string,
+	nodeToDaemonPods map[string][]*corev1.Pod) []string {
+	nodes := make(map[string]*corev1.Node, len(nodeList))
+	for _, node := range nodeList {
+		nodes[node.Name] = node
+	}
+	var skipped []string
+	for nodeName, pods := range nodeToDaemonPods {
+		node := nodes[nodeName]
+		if node == nil || !nodeMatchesPatches(node, ds.Spec.Patches) {
+			continue
+		}
+		if newPod, _, ok := findUpdatedPodsOnNode(ds, pods, hash); !ok || newPod != nil {
+			continue
+		}
+		delete(nodeToDaemonPods, nodeName)
+		skipped = append(skipped, nodeName)
+	}
+	return skipped
+}
+
+// getPodRevisionPatches returns the patches recorded in the revision of the pod.
+func (dsc *ReconcileDaemonSet) getPodRevisionPatches(pod *corev1.Pod, revisions []*apps.ControllerRevision) []appsv1beta1.DaemonSetPatch {
+	for _, r := range revisions {
+		if dsc.revisionAdapter.EqualToRevisionHash("", pod, r.Labels[apps.DefaultDaemonSetUniqueLabelKey]) {
+			patches, err := GetPatchesFromRevision(r)
+			if err != nil {
+				klog.ErrorS(err, "Failed to get patches from revision", "revision", klog.KObj(r))
+			}
+			return patches
+		}
+	}
+	return nil
+}
@@ -18,7 +18,9 @@ package daemonset
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -26,13 +28,17 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	"k8s.io/kubernetes/pkg/controller/daemon/util"
 	testingclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
 
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
 )
@@ -292,6 +298,501 @@ func TestDaemonSetUpdatesNoTemplateChanged(t *testing.T) {
 	clearExpectations(t, manager, ds, podControl)
 }
 
+func TestDaemonSetUpdatesOnlyPatchedNodes(t *testing.T) {
+	ds := newDaemonSet("foo")
+	ds.Spec.Patches = []appsv1beta1.DaemonSetPatch{{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+		Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"priorityClassName":"zone-a"}}`)},
+	}}
+	manager, podControl, _, err := newTestController(ds)
+	if err != nil {
+		t.Fatalf("error creating DaemonSets controller: %v", err)
+	}
+	addNodes(manager.nodeStore, 0, 2, map[string]string{"zone": "a"})
+	addNodes(manager.nodeStore, 2, 3, map[string]string{"zone": "b"})
+	manager.dsStore.Add(ds)
+	expectSyncDaemonSets(t, manager, ds, podControl, 5, 0, 2)
+	markPodsReady(podControl.podStore)
+
+	// edit the patch which only matches nodes in zone a
+	ds.Spec.Patches[0].Patch.Raw = []byte(`{"spec":{"priorityClassName":"zone-a-high"}}`)
+	ds.Spec.UpdateStrategy.Type = appsv1beta1.RollingUpdateDaemonSetStrategyType
+	intStr := intstr.FromInt(5)
+	ds.Spec.UpdateStrategy.RollingUpdate = &appsv1beta1.RollingUpdateDaemonSet{MaxUnavailable: &intStr}
+	manager.dsStore.Update(ds)
+
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 2, 2)
+	hash, err := currentDSHash(context.TODO(), manager, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byNode := podsByNodeMatchingHash(manager, hash)
+	if len(byNode) != 3 {
+		t.Fatalf("expected pods on 3 nodes relabeled to current revision, got %v", byNode)
+	}
+	for nodeName := range byNode {
+		if nodeName == "node-0" || nodeName == "node-1" {
+			t.Fatalf("unexpected pod on patched node %s relabeled", nodeName)
+		}
+	}
+
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 2, 0, 4)
+	markPodsReady(podControl.podStore)
+
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 0, 4)
+	if byNode = podsByNodeMatchingHash(manager, hash); len(byNode) != 5 {
+		t.Fatalf("expected pods on all nodes updated, got %v", byNode)
+	}
+}
+
+func TestDaemonSetUpdatesAddFirstPatch(t *testing.T) {
+	ds := newDaemonSet("foo")
+	manager, podControl, _, err := newTestController(ds)
+	if err != nil {
+		t.Fatalf("error creating DaemonSets controller: %v", err)
+	}
+	addNodes(manager.nodeStore, 0, 2, map[string]string{"zone": "a"})
+	addNodes(manager.nodeStore, 2, 3, map[string]string{"zone": "b"})
+	manager.dsStore.Add(ds)
+	expectSyncDaemonSets(t, manager, ds, podControl, 5, 0, 0)
+	markPodsReady(podControl.podStore)
+	for _, obj := range podControl.podStore.List() {
+		pod := obj.(*corev1.Pod)
+		if pod.Annotations[RenderHashAnnotation] == "" {
+			t.Fatalf("expected render hash recorded in pod %s created without patches", pod.Name)
+		}
+		// the pods created before the render hash was recorded are regarded to be rendered from the base template
+		if pod.Spec.NodeName == "node-4" {
+			delete(pod.Annotations, RenderHashAnnotation)
+		}
+	}
+
+	// add the first patch which only matches nodes in zone a
+	ds.Spec.Patches = []appsv1beta1.DaemonSetPatch{{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+		Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"priorityClassName":"zone-a"}}`)},
+	}}
+	ds.Spec.UpdateStrategy.Type = appsv1beta1.RollingUpdateDaemonSetStrategyType
+	intStr := intstr.FromInt(5)
+	ds.Spec.UpdateStrategy.RollingUpdate = &appsv1beta1.RollingUpdateDaemonSet{MaxUnavailable: &intStr}
+	manager.dsStore.Update(ds)
+
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 2, 0)
+	hash, err := currentDSHash(context.TODO(), manager, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byNode := podsByNodeMatchingHash(manager, hash)
+	if len(byNode) != 3 {
+		t.Fatalf("expected pods on 3 nodes relabeled to current revision, got %v", byNode)
+	}
+	for nodeName := range byNode {
+		if nodeName == "node-0" || nodeName == "node-1" {
+			t.Fatalf("unexpected pod on patched node %s relabeled", nodeName)
+		}
+	}
+}
+
+func TestDaemonSetUpdatesRevertRemovedPatch(t *testing.T) {
+	ds := newDaemonSet("foo")
+	ds.Spec.Patches = []appsv1beta1.DaemonSetPatch{{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+		Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"priorityClassName":"zone-a"}}`)},
+	}}
+	manager, podControl, _, err := newTestController(ds)
+	if err != nil {
+		t.Fatalf("error creating DaemonSets controller: %v", err)
+	}
+	addNodes(manager.nodeStore, 0, 2, map[string]string{"zone": "a"})
+	addNodes(manager.nodeStore, 2, 3, map[string]string{"zone": "b"})
+	manager.dsStore.Add(ds)
+	expectSyncDaemonSets(t, manager, ds, podControl, 5, 0, 2)
+	markPodsReady(podControl.podStore)
+	var patchedPods int
+	for _, obj := range manager.podStore.List() {
+		if obj.(*corev1.Pod).Spec.PriorityClassName == "zone-a" {
+			patchedPods++
+		}
+	}
+	if patchedPods != 2 {
+		t.Fatalf("expected 2 pods patched, got %d", patchedPods)
+	}
+
+	// remove the patch, then the pods on the formerly matched nodes should converge back to the base template
+	ds.Spec.Patches = nil
+	ds.Spec.UpdateStrategy.Type = appsv1beta1.RollingUpdateDaemonSetStrategyType
+	intStr := intstr.FromInt(5)
+	ds.Spec.UpdateStrategy.RollingUpdate = &appsv1beta1.RollingUpdateDaemonSet{MaxUnavailable: &intStr}
+	manager.dsStore.Update(ds)
+
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 2, 2)
+	hash, err := currentDSHash(context.TODO(), manager, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for nodeName := range podsByNodeMatchingHash(manager, hash) {
+		if nodeName == "node-0" || nodeName == "node-1" {
+			t.Fatalf("unexpected pod on formerly patched node %s relabeled", nodeName)
+		}
+	}
+
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 2, 0, 2)
+	markPodsReady(podControl.podStore)
+
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 0, 2)
+	if byNode := podsByNodeMatchingHash(manager, hash); len(byNode) != 5 {
+		t.Fatalf("expected pods on all nodes updated, got %v", byNode)
+	}
+	for _, obj := range manager.podStore.List() {
+		pod := obj.(*corev1.Pod)
+		if pod.DeletionTimestamp == nil && pod.Spec.PriorityClassName != ds.Spec.Template.Spec.PriorityClassName {
+			t.Fatalf("expected pod %s reverted to priorityClassName %q of the base template, got %q",
+				pod.Name, ds.Spec.Template.Spec.PriorityClassName, pod.Spec.PriorityClassName)
+		}
+	}
+}
+
+func TestDaemonSetUpdatesApplyToNewPodsOnly(t *testing.T) {
+	ds := newDaemonSet("foo")
+	manager, podControl, _, err := newTestController(ds)
+	if err != nil {
+		t.Fatalf("error creating DaemonSets controller: %v", err)
+	}
+	addNodes(manager.nodeStore, 0, 2, map[string]string{"zone": "a"})
+	addNodes(manager.nodeStore, 2, 1, map[string]string{"zone": "b"})
+	manager.dsStore.Add(ds)
+	expectSyncDaemonSets(t, manager, ds, podControl, 3, 0, 0)
+	markPodsReady(podControl.podStore)
+	cur, _, err := manager.constructHistory(context.TODO(), ds)
+	if err != nil {
+		t.Fatalf("failed to construct history: %v", err)
+	}
+	if err := manager.historyStore.Add(cur); err != nil {
+		t.Fatal(err)
+	}
+
+	// add a patch applied to new pods only, the existing pods should be relabeled rather than recreated
+	ds.Spec.Patches = []appsv1beta1.DaemonSetPatch{{
+		Selector:           &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+		Patch:              runtime.RawExtension{Raw: []byte(`{"spec":{"priorityClassName":"zone-a"}}`)},
+		ApplyToNewPodsOnly: true,
+	}}
+	ds.Spec.UpdateStrategy.Type = appsv1beta1.RollingUpdateDaemonSetStrategyType
+	intStr := intstr.FromInt(5)
+	ds.Spec.UpdateStrategy.RollingUpdate = &appsv1beta1.RollingUpdateDaemonSet{MaxUnavailable: &intStr}
+	manager.dsStore.Update(ds)
+
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 0, 0)
+	hash, err := currentDSHash(context.TODO(), manager, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if byNode := podsByNodeMatchingHash(manager, hash); len(byNode) != 3 {
+		t.Fatalf("expected existing pods on all nodes relabeled, got %v", byNode)
+	}
+	for _, obj := range manager.podStore.List() {
+		if pod := obj.(*corev1.Pod); pod.Spec.PriorityClassName != "" {
+			t.Fatalf("expected existing pod %s not patched, got priorityClassName %q", pod.Name, pod.Spec.PriorityClassName)
+		}
+	}
+
+	// the pod created on a new matched node should have the patch
+	addNodes(manager.nodeStore, 3, 1, map[string]string{"zone": "a"})
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 1, 0, 1)
+	for _, obj := range manager.podStore.List() {
+		pod := obj.(*corev1.Pod)
+		nodeName, err := util.GetTargetNodeName(pod)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if patched := pod.Spec.PriorityClassName == "zone-a"; patched != (nodeName == "node-3") {
+			t.Fatalf("expected only the pod on new node patched, got pod %s on %s with priorityClassName %q",
+				pod.Name, nodeName, pod.Spec.PriorityClassName)
+		}
+	}
+}
+
+func TestDaemonSetUpdatesShadowPatches(t *testing.T) {
+	ds := newDaemonSet("foo")
+	manager, podControl, _, err := newTestController(ds)
+	if err != nil {
+		t.Fatalf("error creating DaemonSets controller: %v", err)
+	}
+	addNodes(manager.nodeStore, 0, 2, map[string]string{"zone": "a"})
+	addNodes(manager.nodeStore, 2, 1, map[string]string{"zone": "b"})
+	manager.dsStore.Add(ds)
+	expectSyncDaemonSets(t, manager, ds, podControl, 3, 0, 0)
+	markPodsReady(podControl.podStore)
+	cur, _, err := manager.constructHistory(context.TODO(), ds)
+	if err != nil {
+		t.Fatalf("failed to construct history: %v", err)
+	}
+	if err := manager.historyStore.Add(cur); err != nil {
+		t.Fatal(err)
+	}
+
+	// add a shadow patch, the existing pods should be relabeled rather than recreated, and the shadow render
+	// recorded in the pods on the matched nodes
+	ds.Spec.Patches = []appsv1beta1.DaemonSetPatch{{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+		Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"priorityClassName":"zone-a"}}`)},
+		Shadow:   true,
+	}}
+	ds.Spec.UpdateStrategy.Type = appsv1beta1.RollingUpdateDaemonSetStrategyType
+	intStr := intstr.FromInt(5)
+	ds.Spec.UpdateStrategy.RollingUpdate = &appsv1beta1.RollingUpdateDaemonSet{MaxUnavailable: &intStr}
+	manager.dsStore.Update(ds)
+
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 0, 0)
+	hash, err := currentDSHash(context.TODO(), manager, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if byNode := podsByNodeMatchingHash(manager, hash); len(byNode) != 3 {
+		t.Fatalf("expected existing pods on all nodes relabeled, got %v", byNode)
+	}
+	checkShadowPods := func(shadowNodes ...string) {
+		t.Helper()
+		for _, obj := range manager.podStore.List() {
+			pod := obj.(*corev1.Pod)
+			nodeName, err := util.GetTargetNodeName(pod)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pod.Spec.PriorityClassName != "" {
+				t.Fatalf("expected pod %s on %s not patched, got priorityClassName %q", pod.Name, nodeName, pod.Spec.PriorityClassName)
+			}
+			shadowHash, diff := pod.Annotations[ShadowRenderHashAnnotation], pod.Annotations[ShadowRenderDiffAnnotation]
+			if !sets.New[string](shadowNodes...).Has(nodeName) {
+				if shadowHash != "" || diff != "" {
+					t.Fatalf("expected no shadow render on pod %s on %s, got hash %q diff %q", pod.Name, nodeName, shadowHash, diff)
+				}
+				continue
+			}
+			if shadowHash == "" || shadowHash == pod.Annotations[RenderHashAnnotation] {
+				t.Fatalf("expected shadow render hash on pod %s on %s differs from render hash, got %q", pod.Name, nodeName, shadowHash)
+			}
+			if expected := `{"spec":{"priorityClassName":"zone-a"}}`; diff != expected {
+				t.Fatalf("expected shadow render diff %s on pod %s on %s, got %s", expected, pod.Name, nodeName, diff)
+			}
+		}
+	}
+	checkShadowPods("node-0", "node-1")
+
+	// the pod created on a new matched node should not have the shadow patch applied
+	addNodes(manager.nodeStore, 3, 1, map[string]string{"zone": "a"})
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 1, 0, 0)
+	checkShadowPods("node-0", "node-1", "node-3")
+
+	// the shadow renders are removed with the shadow patch
+	if cur, _, err = manager.constructHistory(context.TODO(), ds); err != nil {
+		t.Fatalf("failed to construct history: %v", err)
+	}
+	if err := manager.historyStore.Add(cur); err != nil {
+		t.Fatal(err)
+	}
+	ds.Spec.Patches = nil
+	manager.dsStore.Update(ds)
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 0, 0)
+	checkShadowPods()
+}
+
+func TestDaemonSetUpdatesPatchUpdateStrategy(t *testing.T) {
+	cases := []struct {
+		name             string
+		strategy         appsv1beta1.DaemonSetUpdateStrategy
+		patchStrategy    appsv1beta1.DaemonSetUpdateStrategyType
+		expectedUpdated  []string
+		expectedOnDelete []string
+		// events of the pods created with the patch
+		expectedEvents int
+	}{
+		{
+			name: "OnDelete nodes in RollingUpdate DaemonSet",
+			strategy: appsv1beta1.DaemonSetUpdateStrategy{
+				Type:          appsv1beta1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &appsv1beta1.RollingUpdateDaemonSet{MaxUnavailable: ptr.To(intstr.FromInt(5))},
+			},
+			patchStrategy:    appsv1beta1.OnDeleteDaemonSetStrategyType,
+			expectedUpdated:  []string{"node-0", "node-1"},
+			expectedOnDelete: []string{"node-2"},
+			expectedEvents:   1,
+		},
+		{
+			name:             "RollingUpdate nodes in OnDelete DaemonSet",
+			strategy:         appsv1beta1.DaemonSetUpdateStrategy{Type: appsv1beta1.OnDeleteDaemonSetStrategyType},
+			patchStrategy:    appsv1beta1.RollingUpdateDaemonSetStrategyType,
+			expectedUpdated:  []string{"node-2"},
+			expectedOnDelete: []string{"node-0", "node-1"},
+			expectedEvents:   2,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			ds := newDaemonSet("foo")
+			ds.Spec.UpdateStrategy = cs.strategy
+			ds.Spec.Patches = []appsv1beta1.DaemonSetPatch{{
+				Selector:           &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "b"}},
+				Patch:              runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"zone":"b"}}}`)},
+				UpdateStrategyType: cs.patchStrategy,
+			}}
+			manager, podControl, _, err := newTestController(ds)
+			if err != nil {
+				t.Fatalf("error creating DaemonSets controller: %v", err)
+			}
+			addNodes(manager.nodeStore, 0, 2, map[string]string{"zone": "a"})
+			addNodes(manager.nodeStore, 2, 1, map[string]string{"zone": "b"})
+			manager.dsStore.Add(ds)
+			expectSyncDaemonSets(t, manager, ds, podControl, 3, 0, 1)
+			markPodsReady(podControl.podStore)
+
+			ds.Spec.Template.Spec.Containers[0].Image = "foo2/bar2"
+			manager.dsStore.Update(ds)
+			clearExpectations(t, manager, ds, podControl)
+			expectSyncDaemonSets(t, manager, ds, podControl, 0, len(cs.expectedUpdated), 1)
+			clearExpectations(t, manager, ds, podControl)
+			expectSyncDaemonSets(t, manager, ds, podControl, len(cs.expectedUpdated), 0, cs.expectedEvents)
+			markPodsReady(podControl.podStore)
+			clearExpectations(t, manager, ds, podControl)
+			expectSyncDaemonSets(t, manager, ds, podControl, 0, 0, cs.expectedEvents)
+
+			hash, err := currentDSHash(context.TODO(), manager, ds)
+			if err != nil {
+				t.Fatal(err)
+			}
+			updated := podsByNodeMatchingHash(manager, hash)
+			for _, nodeName := range cs.expectedUpdated {
+				if len(updated[nodeName]) != 1 {
+					t.Fatalf("expected pod on %s updated, got %v", nodeName, updated)
+				}
+			}
+			for _, nodeName := range cs.expectedOnDelete {
+				if len(updated[nodeName]) != 0 {
+					t.Fatalf("expected old pod on %s kept for OnDelete, got %v", nodeName, updated)
+				}
+			}
+		})
+	}
+}
+
+func TestDaemonSetUpdatesKeepPodsWithoutPatchSchema(t *testing.T) {
+	ds := newDaemonSet("foo")
+	ds.Spec.Patches = []appsv1beta1.DaemonSetPatch{{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+		Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"priorityClassName":"zone-a"}}`)},
+	}}
+	manager, podControl, _, err := newTestController(ds)
+	if err != nil {
+		t.Fatalf("error creating DaemonSets controller: %v", err)
+	}
+	addNodes(manager.nodeStore, 0, 2, map[string]string{"zone": "a"})
+	addNodes(manager.nodeStore, 2, 3, map[string]string{"zone": "b"})
+	manager.dsStore.Add(ds)
+	expectSyncDaemonSets(t, manager, ds, podControl, 5, 0, 2)
+	markPodsReady(podControl.podStore)
+
+	// the schema is unavailable when the patch changed, the old pods on the patched nodes should be kept
+	defer func(schemaFunc func() (strategicpatch.LookupPatchMeta, error)) {
+		podTemplatePatchMetaFunc = schemaFunc
+	}(podTemplatePatchMetaFunc)
+	podTemplatePatchMetaFunc = func() (strategicpatch.LookupPatchMeta, error) {
+		return nil, fmt.Errorf("openapi schema not found")
+	}
+	ds.Spec.Patches[0].Patch = runtime.RawExtension{Raw: []byte(`{"spec":{"priorityClassName":"zone-a-new"}}`)}
+	ds.Spec.UpdateStrategy.Type = appsv1beta1.RollingUpdateDaemonSetStrategyType
+	intStr := intstr.FromInt(5)
+	ds.Spec.UpdateStrategy.RollingUpdate = &appsv1beta1.RollingUpdateDaemonSet{MaxUnavailable: &intStr}
+	manager.dsStore.Update(ds)
+
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 0, 3)
+	hash, err := currentDSHash(context.TODO(), manager, ds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for nodeName := range podsByNodeMatchingHash(manager, hash) {
+		if nodeName == "node-0" || nodeName == "node-1" {
+			t.Fatalf("unexpected pod on patched node %s updated without the schema", nodeName)
+		}
+	}
+
+	for event := range manager.fakeRecorder.Events {
+		if strings.Contains(event, FailedRenderPatchesReason) {
+			break
+		}
+		if !strings.Contains(event, PatchAppliedReason) {
+			t.Fatalf("unexpected event %q", event)
+		}
+	}
+
+	// the pods are recreated once the schema is available again
+	podTemplatePatchMetaFunc = func() (strategicpatch.LookupPatchMeta, error) {
+		return strategicpatch.NewPatchMetaFromStruct(&corev1.PodTemplateSpec{})
+	}
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 2, 0)
+}
+
+func TestDaemonSetUpdatesWaitNodeReadyBeforePatch(t *testing.T) {
+	ds := newDaemonSet("foo")
+	ds.Spec.Patches = []appsv1beta1.DaemonSetPatch{{
+		Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+		Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"priorityClassName":"zone-a"}}`)},
+	}}
+	manager, podControl, _, err := newTestController(ds)
+	if err != nil {
+		t.Fatalf("error creating DaemonSets controller: %v", err)
+	}
+	addNodes(manager.nodeStore, 0, 3, map[string]string{"zone": "a"})
+	manager.dsStore.Add(ds)
+	expectSyncDaemonSets(t, manager, ds, podControl, 3, 0, 3)
+	markPodsReady(podControl.podStore)
+
+	// node-0 is NotReady and node-1 is cordoned
+	notReadyNode := newNode("node-0", map[string]string{"zone": "a"})
+	notReadyNode.Status.Conditions[0].Status = corev1.ConditionFalse
+	manager.nodeStore.Update(notReadyNode)
+	cordonedNode := newNode("node-1", map[string]string{"zone": "a"})
+	cordonedNode.Spec.Unschedulable = true
+	manager.nodeStore.Update(cordonedNode)
+
+	ds.Spec.Patches[0].Patch.Raw = []byte(`{"spec":{"priorityClassName":"zone-a-high"}}`)
+	ds.Spec.UpdateStrategy.Type = appsv1beta1.RollingUpdateDaemonSetStrategyType
+	intStr := intstr.FromInt(3)
+	ds.Spec.UpdateStrategy.RollingUpdate = &appsv1beta1.RollingUpdateDaemonSet{MaxUnavailable: &intStr, WaitNodeReadyBeforePatch: true}
+	manager.dsStore.Update(ds)
+
+	// only the pod on the ready node is recreated
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 1, 3)
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 1, 0, 4)
+	markPodsReady(podControl.podStore)
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 0, 4)
+
+	// the deferred pods are recreated once their nodes are ready
+	manager.nodeStore.Update(newNode("node-0", map[string]string{"zone": "a"}))
+	manager.nodeStore.Update(newNode("node-1", map[string]string{"zone": "a"}))
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 0, 2, 4)
+	clearExpectations(t, manager, ds, podControl)
+	expectSyncDaemonSets(t, manager, ds, podControl, 2, 0, 6)
+}
+
 func podsByNodeMatchingHash(dsc *daemonSetsController, hash string) map[string][]string {
 	byNode := make(map[string][]string)
 	for _, obj := range dsc.podStore.List() {
@@ -17,8 +17,10 @@ limitations under the License.
 package daemonset
 
 import (
+	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -28,6 +30,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	intstrutil "k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
 	v1helper "k8s.io/component-helpers/scheduling/corev1"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	"k8s.io/kubernetes/pkg/controller/daemon/util"
@@ -35,7 +39,9 @@ import (
 
 	appspub "github.com/openkruise/kruise/apis/apps/pub"
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/features"
 	kruiseutil "github.com/openkruise/kruise/pkg/util"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
 	"github.com/openkruise/kruise/pkg/util/inplaceupdate"
 	"github.com/openkruise/kruise/pkg/util/lifecycle"
 )
@@ -61,6 +67,16 @@ func loadNewPodForDS(ds *appsv1beta1.DaemonSet) *corev1.Pod {
 	return nil
 }
 
+// parallelizeNodes calls fn for each index of nodes by at most nodeWorkers goroutines. fn must only write
+// the results of the node at its index, so that the results can be aggregated in the order of nodes.
+func parallelizeNodes(ctx context.Context, nodes int, fn func(i int)) {
+	workers := nodeWorkers
+	if workers < 1 {
+		workers = 1
+	}
+	workqueue.ParallelizeUntil(ctx, workers, nodes, fn)
+}
+
 // nodeInSameCondition returns true if all effective types ("Status" is true) equals;
 // otherwise, returns false.
 func nodeInSameCondition(old []corev1.NodeCondition, cur []corev1.NodeCondition) bool {
@@ -132,6 +148,122 @@ func shouldIgnoreNodeUpdate(oldNode, curNode corev1.Node) bool {
 	return apiequality.Semantic.DeepEqual(oldNode, curNode)
 }
 
+// changedLabelKeys returns the sorted keys of the labels added, removed or modified.
+func changedLabelKeys(oldLabels, curLabels map[string]string) []string {
+	keys := sets.New[string]()
+	for k, v := range oldLabels {
+		if cur, ok := curLabels[k]; !ok || cur != v {
+			keys.Insert(k)
+		}
+	}
+	for k := range curLabels {
+		if _, ok := oldLabels[k]; !ok {
+			keys.Insert(k)
+		}
+	}
+	return sets.List(keys)
+}
+
+// patchesMatchChanged returns true if any patch of the DaemonSet matches one of the nodes but not the other.
+func patchesMatchChanged(ds *appsv1beta1.DaemonSet, oldNode, curNode *corev1.Node) bool {
+	for i := range ds.Spec.Patches {
+		if ExplainMatch(&ds.Spec.Patches[i], oldNode).Matched != ExplainMatch(&ds.Spec.Patches[i], curNode).Matched {
+			return true
+		}
+	}
+	return false
+}
+
+// waitNodeReadyBeforePatch returns true if the patched pods should not be recreated until their nodes are ready.
+func waitNodeReadyBeforePatch(ds *appsv1beta1.DaemonSet) bool {
+	return ds.Spec.UpdateStrategy.RollingUpdate != nil && ds.Spec.UpdateStrategy.RollingUpdate.WaitNodeReadyBeforePatch
+}
+
+// isNodeReadyForPatch returns true if the node is Ready and not cordoned.
+func isNodeReadyForPatch(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// nodeMatchesPatches returns true if the node is matched by any of the patches.
+func nodeMatchesPatches(node *corev1.Node, patches []appsv1beta1.DaemonSetPatch) bool {
+	for i := range patches {
+		if matchesNodeSelector(node, patches[i].Selector) {
+			return true
+		}
+	}
+	return false
+}
+
+// newPatchStatuses returns the empty statuses of spec.patches, which keep the last rendered time
+// observed before if the patches are not changed in number.
+func newPatchStatuses(ds *appsv1beta1.DaemonSet) []appsv1beta1.DaemonSetPatchStatus {
+	if len(ds.Spec.Patches) == 0 || !utilfeature.DefaultFeatureGate.Enabled(features.DaemonSetPatches) {
+		return nil
+	}
+	patchStatuses := make([]appsv1beta1.DaemonSetPatchStatus, len(ds.Spec.Patches))
+	for i := range patchStatuses {
+		patchStatuses[i].Description = ds.Spec.Patches[i].Description
+		patchStatuses[i].Owner = ds.Spec.Patches[i].Owner
+	}
+	if len(ds.Status.PatchStatuses) == len(patchStatuses) {
+		for i := range patchStatuses {
+			patchStatuses[i].LastRenderedTime = ds.Status.PatchStatuses[i].LastRenderedTime
+		}
+	}
+	return patchStatuses
+}
+
+// describeAppliedPatches describes the patches applied to the daemon pod on node, in the order they are applied,
+// with their owners and descriptions if specified. It returns an empty string if no patch matches the node.
+func describeAppliedPatches(ds *appsv1beta1.DaemonSet, node *corev1.Node) string {
+	if len(ds.Spec.Patches) == 0 || !utilfeature.DefaultFeatureGate.Enabled(features.DaemonSetPatches) {
+		return ""
+	}
+	var descriptions []string
+	for _, i := range sortedPatchIndexes(ds) {
+		patch := &ds.Spec.Patches[i]
+		if patch.Shadow || !ExplainMatch(patch, node).Matched {
+			continue
+		}
+		description := fmt.Sprintf("spec.patches[%d]", i)
+		if patch.Owner != "" {
+			description += fmt.Sprintf(" (owner: %s)", patch.Owner)
+		}
+		if patch.Description != "" {
+			description += fmt.Sprintf(": %s", patch.Description)
+		}
+		descriptions = append(descriptions, description)
+	}
+	return strings.Join(descriptions, "; ")
+}
+
+// observePatchStatuses counts the node into the statuses of the patches matching it, and records the
+// creation time of updatedPod, which is rendered with the patches, as their last rendered time.
+func observePatchStatuses(ds *appsv1beta1.DaemonSet, node *corev1.Node, updatedPod *corev1.Pod, patchStatuses []appsv1beta1.DaemonSetPatchStatus) {
+	for i := range patchStatuses {
+		if !matchesNodeSelector(node, ds.Spec.Patches[i].Selector) {
+			continue
+		}
+		patchStatuses[i].MatchedNodes++
+		// the shadow patches are never rendered into pods
+		if updatedPod == nil || ds.Spec.Patches[i].Shadow {
+			continue
+		}
+		renderedTime := updatedPod.CreationTimestamp
+		if patchStatuses[i].LastRenderedTime == nil || patchStatuses[i].LastRenderedTime.Before(&renderedTime) {
+			patchStatuses[i].LastRenderedTime = &renderedTime
+		}
+	}
+}
+
 func getBurstReplicas(ds *appsv1beta1.DaemonSet) int {
 	// Error caught by validation
 	burstReplicas, _ := intstrutil.GetScaledValueFromIntOrPercent(
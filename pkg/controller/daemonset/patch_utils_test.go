@@ -1,12 +1,31 @@
 package daemonset
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/features"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/utils/ptr"
 )
 
 func TestApplyPatchesToPodTemplate(t *testing.T) {
@@ -250,6 +269,98 @@ func TestEmptyPatches(t *testing.T) {
 	}
 }
 
+func TestApplyPatchesInjectSidecar(t *testing.T) {
+	baseTemplate := &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "main", Image: "main-image"}},
+		},
+	}
+	newDaemonSet := func(raw string) *appsv1beta1.DaemonSet {
+		return &appsv1beta1.DaemonSet{Spec: appsv1beta1.DaemonSetSpec{Patches: []appsv1beta1.DaemonSetPatch{{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"region": "cn-east"}},
+			Patch:    runtime.RawExtension{Raw: []byte(raw)},
+		}}}}
+	}
+	matchedNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"region": "cn-east"}}}
+	unmatchedNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{"region": "cn-west"}}}
+
+	ds := newDaemonSet(`{"spec":{"containers":[{"name":"sidecar","image":"sidecar-image"}]}}`)
+	patchedTemplate, err := applyPatchesToPodTemplate(ds, matchedNode, baseTemplate)
+	if err != nil {
+		t.Fatalf("Failed to apply patches: %v", err)
+	}
+	// the container with a new name is added by strategic merge patch in front of the existing ones, not replacing them
+	expected := []corev1.Container{{Name: "sidecar", Image: "sidecar-image"}, {Name: "main", Image: "main-image"}}
+	if !reflect.DeepEqual(patchedTemplate.Spec.Containers, expected) {
+		t.Errorf("Expected containers %v on matched node, got %v", expected, patchedTemplate.Spec.Containers)
+	}
+
+	patchedTemplate, err = applyPatchesToPodTemplate(ds, unmatchedNode, baseTemplate)
+	if err != nil {
+		t.Fatalf("Failed to apply patches: %v", err)
+	}
+	if !reflect.DeepEqual(patchedTemplate.Spec.Containers, baseTemplate.Spec.Containers) {
+		t.Errorf("Expected containers %v on unmatched node, got %v", baseTemplate.Spec.Containers, patchedTemplate.Spec.Containers)
+	}
+	if len(baseTemplate.Spec.Containers) != 1 {
+		t.Errorf("Expected base template not modified, got %v", baseTemplate.Spec.Containers)
+	}
+
+	ds = newDaemonSet(`{"spec":{"containers":[{"name":"sidecar"}]}}`)
+	if _, err = applyPatchesToPodTemplate(ds, matchedNode, baseTemplate); err == nil {
+		t.Errorf("Expected error for sidecar without image")
+	}
+}
+
+func TestApplyYAMLPatch(t *testing.T) {
+	baseTemplate := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "main", Image: "main-image"}},
+		},
+	}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"type": "special"}}}
+	render := func(raw string) (*corev1.PodTemplateSpec, error) {
+		ds := &appsv1beta1.DaemonSet{Spec: appsv1beta1.DaemonSetSpec{Patches: []appsv1beta1.DaemonSetPatch{{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"type": "special"}},
+			Patch:    runtime.RawExtension{Raw: []byte(raw)},
+		}}}}
+		return applyPatchesToPodTemplate(ds, node, baseTemplate)
+	}
+
+	jsonTemplate, err := render(`{"metadata":{"labels":{"patched":"true"}},"spec":{"containers":[{"name":"main","image":"main-image:v2","env":[{"name":"PORT","value":"8080"}]}]}}`)
+	if err != nil {
+		t.Fatalf("Failed to apply JSON patch: %v", err)
+	}
+	yamlPatch, _ := json.Marshal(`metadata:
+  labels:
+    patched: "true"
+spec:
+  containers:
+  - name: main
+    image: main-image:v2
+    env:
+    - name: PORT
+      value: "8080"
+`)
+	yamlTemplate, err := render(string(yamlPatch))
+	if err != nil {
+		t.Fatalf("Failed to apply YAML patch: %v", err)
+	}
+	if !reflect.DeepEqual(jsonTemplate, yamlTemplate) {
+		t.Errorf("Expected YAML patch rendered the same as JSON patch %v, got %v", jsonTemplate, yamlTemplate)
+	}
+
+	invalidPatch, _ := json.Marshal("spec:\n  containers: [")
+	if _, err := render(string(invalidPatch)); err == nil {
+		t.Errorf("Expected error for invalid YAML patch")
+	}
+	jsonPatch, _ := json.Marshal("- op: remove\n  path: /metadata/labels/app\n")
+	if _, err := render(string(jsonPatch)); err == nil {
+		t.Errorf("Expected error for JSON patch written in YAML")
+	}
+}
+
 func TestPrioritySorting(t *testing.T) {
 	baseTemplate := &corev1.PodTemplateSpec{
 		Spec: corev1.PodSpec{
@@ -308,3 +419,1064 @@ func TestPrioritySorting(t *testing.T) {
 		t.Errorf("Expected high-priority patch to override, got '%s'", container.Image)
 	}
 }
+
+func TestPatchPriorityPrecedence(t *testing.T) {
+	baseTemplate := &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "test-container", Image: "base-image"}},
+		},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"type": "special"},
+		},
+	}
+	newPatch := func(priority int32, image, env string) appsv1beta1.DaemonSetPatch {
+		return appsv1beta1.DaemonSetPatch{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"type": "special"},
+			},
+			Priority: priority,
+			Patch: runtime.RawExtension{
+				Raw: []byte(`{"spec":{"containers":[{"name":"test-container","image":"` + image + `","env":[{"name":"` + env + `","value":"true"}]}]}}`),
+			},
+		}
+	}
+
+	// Patches are listed out of priority order on purpose, the one with the
+	// highest priority must win regardless of its position in the list.
+	patches := []appsv1beta1.DaemonSetPatch{
+		newPatch(50, "middle-priority", "MIDDLE"),
+		newPatch(100, "high-priority", "HIGH"),
+		newPatch(10, "low-priority", "LOW"),
+	}
+
+	patchedTemplate, err := applyPatchesToPodTemplate(
+		&appsv1beta1.DaemonSet{Spec: appsv1beta1.DaemonSetSpec{Patches: patches}},
+		node,
+		baseTemplate,
+	)
+	if err != nil {
+		t.Fatalf("Failed to apply patches: %v", err)
+	}
+
+	container := patchedTemplate.Spec.Containers[0]
+	if container.Image != "high-priority" {
+		t.Errorf("Expected high-priority patch to win, got '%s'", container.Image)
+	}
+	// Non-conflicting fields of every matched patch are merged
+	envs := map[string]bool{}
+	for _, env := range container.Env {
+		envs[env.Name] = true
+	}
+	if !envs["LOW"] || !envs["MIDDLE"] || !envs["HIGH"] {
+		t.Errorf("Expected env from all matched patches, got %v", container.Env)
+	}
+}
+
+func TestPatchOrderBy(t *testing.T) {
+	baseTemplate := &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "test-container", Image: "base-image"}},
+		},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"type": "special"}},
+	}
+	newPatch := func(image string, priority, order int32) appsv1beta1.DaemonSetPatch {
+		return appsv1beta1.DaemonSetPatch{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"type": "special"}},
+			Priority: priority,
+			Order:    order,
+			Patch: runtime.RawExtension{
+				Raw: []byte(`{"spec":{"containers":[{"name":"test-container","image":"` + image + `"}]}}`),
+			},
+		}
+	}
+	// the patch with higher priority is ordered before the other one
+	patches := []appsv1beta1.DaemonSetPatch{
+		newPatch("first-in-order", 100, 1),
+		newPatch("high-priority", 200, 0),
+	}
+
+	cases := []struct {
+		name          string
+		orderBy       appsv1beta1.DaemonSetPatchOrderByType
+		expectedImage string
+	}{
+		{name: "default", expectedImage: "high-priority"},
+		{name: "by priority", orderBy: appsv1beta1.PriorityDaemonSetPatchOrderBy, expectedImage: "high-priority"},
+		{name: "by order", orderBy: appsv1beta1.OrderDaemonSetPatchOrderBy, expectedImage: "first-in-order"},
+	}
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			ds := &appsv1beta1.DaemonSet{Spec: appsv1beta1.DaemonSetSpec{Patches: patches, PatchOrderBy: cs.orderBy}}
+			patchedTemplate, err := applyPatchesToPodTemplate(ds, node, baseTemplate)
+			if err != nil {
+				t.Fatalf("Failed to apply patches: %v", err)
+			}
+			if image := patchedTemplate.Spec.Containers[0].Image; image != cs.expectedImage {
+				t.Errorf("Expected image %s, got %s", cs.expectedImage, image)
+			}
+		})
+	}
+}
+
+func TestSamePriorityKeepsListOrder(t *testing.T) {
+	baseTemplate := &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "test-container",
+					Image: "base-image",
+				},
+			},
+		},
+	}
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"type": "special"},
+		},
+	}
+
+	newPatch := func(image string) appsv1beta1.DaemonSetPatch {
+		return appsv1beta1.DaemonSetPatch{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"type": "special"},
+			},
+			Patch: runtime.RawExtension{
+				Raw: []byte(`{"spec":{"containers":[{"name":"test-container","image":"` + image + `"}]}}`),
+			},
+		}
+	}
+
+	patchedTemplate, err := applyPatchesToPodTemplate(
+		&appsv1beta1.DaemonSet{
+			Spec: appsv1beta1.DaemonSetSpec{
+				Patches: []appsv1beta1.DaemonSetPatch{newPatch("first"), newPatch("second"), newPatch("third")},
+			},
+		},
+		node,
+		baseTemplate,
+	)
+	if err != nil {
+		t.Fatalf("Failed to apply patches with same priority: %v", err)
+	}
+
+	// patches with the same priority are applied in list order, so the last one wins
+	container := patchedTemplate.Spec.Containers[0]
+	if container.Image != "third" {
+		t.Errorf("Expected last listed patch to win, got '%s'", container.Image)
+	}
+}
+
+func TestApplyConflictingPatches(t *testing.T) {
+	baseTemplate := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"env": "base"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "test-container", Image: "base-image"}},
+		},
+	}
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node-1",
+			Labels: map[string]string{"type": "special"},
+		},
+	}
+	newPatch := func(priority int32, raw string) appsv1beta1.DaemonSetPatch {
+		return appsv1beta1.DaemonSetPatch{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"type": "special"}},
+			Priority: priority,
+			Patch:    runtime.RawExtension{Raw: []byte(raw)},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		patches       []appsv1beta1.DaemonSetPatch
+		expectedLabel string
+		expectedErr   string
+	}{
+		{
+			name: "higher priority replace listed first wins",
+			patches: []appsv1beta1.DaemonSetPatch{
+				newPatch(100, `{"metadata":{"labels":{"env":"high"}}}`),
+				newPatch(10, `{"metadata":{"labels":{"env":"low"}}}`),
+			},
+			expectedLabel: "high",
+		},
+		{
+			name: "lower priority remove is applied before higher priority replace",
+			patches: []appsv1beta1.DaemonSetPatch{
+				newPatch(100, `{"metadata":{"labels":{"env":"high"}}}`),
+				newPatch(10, `{"metadata":{"labels":{"env":null}}}`),
+			},
+			expectedLabel: "high",
+		},
+		{
+			name: "out-of-order JSON patch remove reports the failed patch",
+			patches: []appsv1beta1.DaemonSetPatch{
+				newPatch(100, `{"metadata":{"labels":{"env":"high"}}}`),
+				newPatch(10, `[{"op":"remove","path":"/metadata/labels/env"}]`),
+			},
+			expectedErr: "failed to apply spec.patches[1] with priority 10: JSON patch is not supported, the patch must be a strategic merge patch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := &appsv1beta1.DaemonSet{Spec: appsv1beta1.DaemonSetSpec{Patches: tt.patches}}
+			patchedTemplate, err := applyPatchesToPodTemplate(ds, node, baseTemplate)
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Fatalf("Expected error %q, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to apply conflicting patches: %v", err)
+			}
+			if got := patchedTemplate.Labels["env"]; got != tt.expectedLabel {
+				t.Errorf("Expected label env=%s, got %s", tt.expectedLabel, got)
+			}
+			if baseTemplate.Labels["env"] != "base" {
+				t.Errorf("Expected base template not modified, got %v", baseTemplate.Labels)
+			}
+		})
+	}
+}
+
+func TestApplyPatchesSchedulerNameAndPriorityClassName(t *testing.T) {
+	baseTemplate := &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			SchedulerName:     "default-scheduler",
+			PriorityClassName: "default-priority",
+			Containers:        []corev1.Container{{Name: "test-container", Image: "base-image"}},
+		},
+	}
+	dedicatedNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"type": "dedicated"}}}
+	normalNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{"type": "normal"}}}
+	ds := &appsv1beta1.DaemonSet{
+		Spec: appsv1beta1.DaemonSetSpec{
+			Patches: []appsv1beta1.DaemonSetPatch{
+				{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"type": "dedicated"}},
+					Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"schedulerName":"dedicated-scheduler","priorityClassName":"dedicated-priority"}}`)},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name                      string
+		node                      *corev1.Node
+		expectedSchedulerName     string
+		expectedPriorityClassName string
+	}{
+		{
+			name:                      "dedicated node overridden",
+			node:                      dedicatedNode,
+			expectedSchedulerName:     "dedicated-scheduler",
+			expectedPriorityClassName: "dedicated-priority",
+		},
+		{
+			name:                      "normal node kept",
+			node:                      normalNode,
+			expectedSchedulerName:     "default-scheduler",
+			expectedPriorityClassName: "default-priority",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patchedTemplate, err := applyPatchesToPodTemplate(ds, tt.node, baseTemplate)
+			if err != nil {
+				t.Fatalf("Failed to apply patches: %v", err)
+			}
+			if patchedTemplate.Spec.SchedulerName != tt.expectedSchedulerName {
+				t.Errorf("Expected schedulerName %s, got %s", tt.expectedSchedulerName, patchedTemplate.Spec.SchedulerName)
+			}
+			if patchedTemplate.Spec.PriorityClassName != tt.expectedPriorityClassName {
+				t.Errorf("Expected priorityClassName %s, got %s", tt.expectedPriorityClassName, patchedTemplate.Spec.PriorityClassName)
+			}
+			if len(patchedTemplate.Spec.Containers) != 1 || patchedTemplate.Spec.Containers[0].Image != "base-image" {
+				t.Errorf("Expected containers kept, got %v", patchedTemplate.Spec.Containers)
+			}
+		})
+	}
+}
+
+func TestApplyPatchesRuntimeClassAndOverhead(t *testing.T) {
+	baseTemplate := &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Overhead:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m")},
+			Containers: []corev1.Container{{Name: "test-container", Image: "base-image"}},
+		},
+	}
+	sandboxNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"runtime": "gvisor"}}}
+	normalNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{"runtime": "runc"}}}
+	ds := &appsv1beta1.DaemonSet{
+		Spec: appsv1beta1.DaemonSetSpec{
+			Patches: []appsv1beta1.DaemonSetPatch{
+				{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"runtime": "gvisor"}},
+					Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"runtimeClassName":"gvisor","overhead":{"cpu":"250m","memory":"120Mi"}}}`)},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name                     string
+		node                     *corev1.Node
+		expectedRuntimeClassName *string
+		expectedOverhead         corev1.ResourceList
+	}{
+		{
+			name:                     "sandbox node patched",
+			node:                     sandboxNode,
+			expectedRuntimeClassName: ptr.To("gvisor"),
+			expectedOverhead:         corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("250m"), corev1.ResourceMemory: resource.MustParse("120Mi")},
+		},
+		{
+			name:             "normal node kept",
+			node:             normalNode,
+			expectedOverhead: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("10m")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patchedTemplate, err := applyPatchesToPodTemplate(ds, tt.node, baseTemplate)
+			if err != nil {
+				t.Fatalf("Failed to apply patches: %v", err)
+			}
+			if !reflect.DeepEqual(patchedTemplate.Spec.RuntimeClassName, tt.expectedRuntimeClassName) {
+				t.Errorf("Expected runtimeClassName %v, got %v", tt.expectedRuntimeClassName, patchedTemplate.Spec.RuntimeClassName)
+			}
+			if len(patchedTemplate.Spec.Overhead) != len(tt.expectedOverhead) {
+				t.Fatalf("Expected overhead %v, got %v", tt.expectedOverhead, patchedTemplate.Spec.Overhead)
+			}
+			for name, quantity := range tt.expectedOverhead {
+				if got := patchedTemplate.Spec.Overhead[name]; got.Cmp(quantity) != 0 {
+					t.Errorf("Expected overhead %s %s, got %s", name, quantity.String(), got.String())
+				}
+			}
+		})
+	}
+}
+
+func TestApplyPatchesTerminationGracePeriodSeconds(t *testing.T) {
+	baseTemplate := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			TerminationGracePeriodSeconds: ptr.To[int64](30),
+			Containers:                    []corev1.Container{{Name: "test-container", Image: "base-image"}},
+		},
+	}
+	drainNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"drain": "slow"}}}
+	normalNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{"drain": "fast"}}}
+	ds := &appsv1beta1.DaemonSet{
+		Spec: appsv1beta1.DaemonSetSpec{
+			Template: baseTemplate,
+			Patches: []appsv1beta1.DaemonSetPatch{
+				{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"drain": "slow"}},
+					Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"terminationGracePeriodSeconds":600}}`)},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name                string
+		node                *corev1.Node
+		expectedGracePeriod int64
+	}{
+		{
+			name:                "slow-drain node patched",
+			node:                drainNode,
+			expectedGracePeriod: 600,
+		},
+		{
+			name:                "normal node kept",
+			node:                normalNode,
+			expectedGracePeriod: 30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderedTemplate, err := RenderPodTemplateForNode(ds, tt.node)
+			if err != nil {
+				t.Fatalf("Failed to render template: %v", err)
+			}
+			if gracePeriod := renderedTemplate.Spec.TerminationGracePeriodSeconds; gracePeriod == nil || *gracePeriod != tt.expectedGracePeriod {
+				t.Errorf("Expected terminationGracePeriodSeconds %d, got %v", tt.expectedGracePeriod, gracePeriod)
+			}
+			if len(renderedTemplate.Spec.Containers) != 1 || renderedTemplate.Spec.Containers[0].Image != "base-image" {
+				t.Errorf("Expected containers kept, got %v", renderedTemplate.Spec.Containers)
+			}
+		})
+	}
+	if *ds.Spec.Template.Spec.TerminationGracePeriodSeconds != 30 {
+		t.Errorf("Expected spec.template not modified, got %d", *ds.Spec.Template.Spec.TerminationGracePeriodSeconds)
+	}
+}
+
+func TestApplyPatchesProbes(t *testing.T) {
+	baseProbe := &corev1.Probe{
+		ProbeHandler:   corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt32(8080)}},
+		TimeoutSeconds: 1,
+		PeriodSeconds:  10,
+	}
+	ds := &appsv1beta1.DaemonSet{
+		Spec: appsv1beta1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "test-container", Image: "base-image", ReadinessProbe: baseProbe}},
+				},
+			},
+			Patches: []appsv1beta1.DaemonSetPatch{
+				{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"disk": "slow"}},
+					Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"test-container","readinessProbe":{"timeoutSeconds":10}}]}}`)},
+				},
+				{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"network": "none"}},
+					Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"test-container","readinessProbe":{"$patch":"replace","exec":{"command":["true"]},"timeoutSeconds":5}}]}}`)},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		nodeLabels    map[string]string
+		expectedProbe *corev1.Probe
+	}{
+		{
+			name:          "normal node kept",
+			nodeLabels:    map[string]string{"disk": "fast"},
+			expectedProbe: baseProbe,
+		},
+		{
+			name:       "slow-disk node merged with longer timeout",
+			nodeLabels: map[string]string{"disk": "slow"},
+			expectedProbe: &corev1.Probe{
+				ProbeHandler:   baseProbe.ProbeHandler,
+				TimeoutSeconds: 10,
+				PeriodSeconds:  10,
+			},
+		},
+		{
+			name:       "probe replaced with another handler",
+			nodeLabels: map[string]string{"network": "none"},
+			expectedProbe: &corev1.Probe{
+				ProbeHandler:   corev1.ProbeHandler{Exec: &corev1.ExecAction{Command: []string{"true"}}},
+				TimeoutSeconds: 5,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: tt.nodeLabels}}
+			renderedTemplate, err := RenderPodTemplateForNode(ds, node)
+			if err != nil {
+				t.Fatalf("Failed to render template: %v", err)
+			}
+			if probe := renderedTemplate.Spec.Containers[0].ReadinessProbe; !reflect.DeepEqual(probe, tt.expectedProbe) {
+				t.Errorf("Expected readinessProbe %v, got %v", tt.expectedProbe, probe)
+			}
+		})
+	}
+	if ds.Spec.Template.Spec.Containers[0].ReadinessProbe.TimeoutSeconds != 1 {
+		t.Errorf("Expected spec.template not modified, got %v", ds.Spec.Template.Spec.Containers[0].ReadinessProbe)
+	}
+}
+
+func TestApplyPatchesSchemaLookupFailure(t *testing.T) {
+	ds := &appsv1beta1.DaemonSet{
+		Spec: appsv1beta1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "test-container", Image: "base-image"}},
+				},
+			},
+			Patches: []appsv1beta1.DaemonSetPatch{{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+				Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"sidecar","image":"sidecar-image"}]}}`)},
+			}},
+		},
+	}
+	// a schema without the patch strategies, with which the containers would be replaced by the patch
+	type specWithoutStrategies struct {
+		Containers []corev1.Container `json:"containers"`
+	}
+	type templateWithoutStrategies struct {
+		Spec specWithoutStrategies `json:"spec"`
+	}
+
+	tests := []struct {
+		name       string
+		schemaFunc func() (strategicpatch.LookupPatchMeta, error)
+	}{
+		{
+			name: "schema lookup failed",
+			schemaFunc: func() (strategicpatch.LookupPatchMeta, error) {
+				return nil, fmt.Errorf("openapi schema not found")
+			},
+		},
+		{
+			name: "schema without patch strategies",
+			schemaFunc: func() (strategicpatch.LookupPatchMeta, error) {
+				return strategicpatch.NewPatchMetaFromStruct(&templateWithoutStrategies{})
+			},
+		},
+	}
+
+	defer func(schemaFunc func() (strategicpatch.LookupPatchMeta, error)) {
+		podTemplatePatchMetaFunc = schemaFunc
+	}(podTemplatePatchMetaFunc)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			podTemplatePatchMetaFunc = tt.schemaFunc
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"zone": "a"}}}
+			before := testutil.ToFloat64(DaemonSetPatchRenderErrorsMetrics.WithLabelValues(patchRenderErrorSchema))
+			renderedTemplate, err := renderPodTemplate(ds, node, &ds.Spec.Template)
+			if err == nil {
+				t.Fatalf("Expected error, got template %v", renderedTemplate.Spec.Containers)
+			}
+			if !IsPatchSchemaError(err) || !strings.Contains(err.Error(), "strategic merge schema") {
+				t.Errorf("Expected schema error, got %v", err)
+			}
+			if after := testutil.ToFloat64(DaemonSetPatchRenderErrorsMetrics.WithLabelValues(patchRenderErrorSchema)); after != before+1 {
+				t.Errorf("Expected schema error counted, got %v -> %v", before, after)
+			}
+
+			// nodes not matched by the patches do not need the schema
+			node.Labels = map[string]string{"zone": "b"}
+			if _, err := renderPodTemplate(ds, node, &ds.Spec.Template); err != nil {
+				t.Errorf("Expected no error on node not patched, got %v", err)
+			}
+		})
+	}
+}
+
+func TestApplyPatchesHostAliases(t *testing.T) {
+	ds := &appsv1beta1.DaemonSet{
+		Spec: appsv1beta1.DaemonSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers:  []corev1.Container{{Name: "test-container", Image: "base-image"}},
+					HostAliases: []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"registry.local"}}},
+				},
+			},
+			Patches: []appsv1beta1.DaemonSetPatch{
+				{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"cache": "local"}},
+					Patch: runtime.RawExtension{Raw: []byte(`{"spec":{"hostAliases":[` +
+						`{"ip":"169.254.20.10","hostnames":["cache.node.local"]},` +
+						`{"ip":"10.0.0.1","hostnames":["registry.node.local"]}]}}`)},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name                string
+		nodeLabels          map[string]string
+		expectedHostAliases map[string][]string
+	}{
+		{
+			name:                "normal node kept",
+			nodeLabels:          map[string]string{"cache": "remote"},
+			expectedHostAliases: map[string][]string{"10.0.0.1": {"registry.local"}},
+		},
+		{
+			name:       "matched node merged by ip",
+			nodeLabels: map[string]string{"cache": "local"},
+			expectedHostAliases: map[string][]string{
+				"10.0.0.1":      {"registry.node.local"},
+				"169.254.20.10": {"cache.node.local"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: tt.nodeLabels}}
+			renderedTemplate, err := RenderPodTemplateForNode(ds, node)
+			if err != nil {
+				t.Fatalf("Failed to render template: %v", err)
+			}
+			hostAliases := map[string][]string{}
+			for _, alias := range renderedTemplate.Spec.HostAliases {
+				if _, ok := hostAliases[alias.IP]; ok {
+					t.Fatalf("Expected hostAliases deduplicated by ip, got %v", renderedTemplate.Spec.HostAliases)
+				}
+				hostAliases[alias.IP] = alias.Hostnames
+			}
+			if !reflect.DeepEqual(hostAliases, tt.expectedHostAliases) {
+				t.Errorf("Expected hostAliases %v, got %v", tt.expectedHostAliases, renderedTemplate.Spec.HostAliases)
+			}
+		})
+	}
+}
+
+func TestApplyPatchesFeatureGate(t *testing.T) {
+	baseTemplate := &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{
+					Name:  "test-container",
+					Image: "base-image",
+				},
+			},
+		},
+	}
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"type": "special"},
+		},
+	}
+
+	ds := &appsv1beta1.DaemonSet{
+		Spec: appsv1beta1.DaemonSetSpec{
+			Patches: []appsv1beta1.DaemonSetPatch{
+				{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"type": "special"},
+					},
+					Patch: runtime.RawExtension{
+						Raw: []byte(`{"spec":{"containers":[{"name":"test-container","image":"patched-image"}]}}`),
+					},
+				},
+			},
+		},
+	}
+
+	for _, enabled := range []bool{true, false} {
+		func() {
+			defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.DaemonSetPatches, enabled)()
+			patchedTemplate, err := applyPatchesToPodTemplate(ds, node, baseTemplate)
+			if err != nil {
+				t.Fatalf("Failed to apply patches with feature gate %v: %v", enabled, err)
+			}
+
+			expectedImage := "base-image"
+			if enabled {
+				expectedImage = "patched-image"
+			}
+			if image := patchedTemplate.Spec.Containers[0].Image; image != expectedImage {
+				t.Errorf("Expected image '%s' with feature gate %v, got '%s'", expectedImage, enabled, image)
+			}
+		}()
+	}
+}
+
+func TestApplyPatchesTopologySpreadConstraints(t *testing.T) {
+	baseTemplate := &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "test-container", Image: "base-image"}},
+			TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+				{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule},
+				{MaxSkew: 2, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: corev1.ScheduleAnyway},
+			},
+		},
+	}
+
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"nic": "multi"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		patch    string
+		expected []corev1.TopologySpreadConstraint
+	}{
+		{
+			name:  "merge by topologyKey",
+			patch: `{"spec":{"topologySpreadConstraints":[{"topologyKey":"topology.kubernetes.io/zone","maxSkew":3}]}}`,
+			expected: []corev1.TopologySpreadConstraint{
+				{MaxSkew: 3, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule},
+				{MaxSkew: 2, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: corev1.ScheduleAnyway},
+			},
+		},
+		{
+			name:  "add new topologyKey",
+			patch: `{"spec":{"topologySpreadConstraints":[{"topologyKey":"example.com/nic-group","maxSkew":1,"whenUnsatisfiable":"DoNotSchedule"}]}}`,
+			expected: []corev1.TopologySpreadConstraint{
+				{MaxSkew: 1, TopologyKey: "example.com/nic-group", WhenUnsatisfiable: corev1.DoNotSchedule},
+				{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule},
+				{MaxSkew: 2, TopologyKey: "kubernetes.io/hostname", WhenUnsatisfiable: corev1.ScheduleAnyway},
+			},
+		},
+		{
+			name:  "delete by topologyKey",
+			patch: `{"spec":{"topologySpreadConstraints":[{"topologyKey":"kubernetes.io/hostname","$patch":"delete"}]}}`,
+			expected: []corev1.TopologySpreadConstraint{
+				{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule},
+			},
+		},
+		{
+			name:  "replace the whole list",
+			patch: `{"spec":{"topologySpreadConstraints":[{"$patch":"replace"},{"topologyKey":"example.com/nic-group","maxSkew":1,"whenUnsatisfiable":"DoNotSchedule"}]}}`,
+			expected: []corev1.TopologySpreadConstraint{
+				{MaxSkew: 1, TopologyKey: "example.com/nic-group", WhenUnsatisfiable: corev1.DoNotSchedule},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := &appsv1beta1.DaemonSet{
+				Spec: appsv1beta1.DaemonSetSpec{
+					Patches: []appsv1beta1.DaemonSetPatch{
+						{
+							Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"nic": "multi"}},
+							Patch:    runtime.RawExtension{Raw: []byte(tt.patch)},
+						},
+					},
+				},
+			}
+			patchedTemplate, err := applyPatchesToPodTemplate(ds, node, baseTemplate)
+			if err != nil {
+				t.Fatalf("Failed to apply patches: %v", err)
+			}
+			if !reflect.DeepEqual(patchedTemplate.Spec.TopologySpreadConstraints, tt.expected) {
+				t.Errorf("Expected constraints %v, got %v", tt.expected, patchedTemplate.Spec.TopologySpreadConstraints)
+			}
+			if len(baseTemplate.Spec.TopologySpreadConstraints) != 2 {
+				t.Errorf("Base template should not be modified, got %v", baseTemplate.Spec.TopologySpreadConstraints)
+			}
+		})
+	}
+}
+
+func TestUpdateDaemonSetStatusPatchStatuses(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.DaemonSetPatches, true)()
+
+	ds := newDaemonSet("foo")
+	ds.Spec.Patches = []appsv1beta1.DaemonSetPatch{
+		{
+			Selector:    &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+			Patch:       runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"zone":"a"}}}`)},
+			Description: "label pods in zone a",
+			Owner:       "team-a",
+		},
+		{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "b"}},
+			Patch:    runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"zone":"b"}}}`)},
+		},
+		{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "c"}},
+			Patch:    runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"zone":"c"}}}`)},
+		},
+	}
+	manager, _, _, err := newTestController(ds)
+	if err != nil {
+		t.Fatalf("error creating DaemonSets controller: %v", err)
+	}
+	if err = manager.dsStore.Add(ds); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	nodeList := []*corev1.Node{
+		newNode("node-0", map[string]string{"zone": "a"}),
+		newNode("node-1", map[string]string{"zone": "a"}),
+		newNode("node-2", map[string]string{"zone": "a"}),
+		newNode("node-3", map[string]string{"zone": "b"}),
+	}
+	for i, node := range nodeList {
+		if err = manager.nodeStore.Add(node); err != nil {
+			t.Fatal(err)
+		}
+		// no daemon pod is created on node-2 yet
+		if i == 2 {
+			continue
+		}
+		pod := newPod("foo-", node.Name, simpleDaemonSetLabel, ds)
+		pod.CreationTimestamp = metav1.NewTime(start.Add(time.Duration(i) * time.Minute))
+		if err = manager.podStore.Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hash := newPod("foo-", "", simpleDaemonSetLabel, ds).Labels[apps.DefaultDaemonSetUniqueLabelKey]
+	if err = manager.updateDaemonSetStatus(context.TODO(), ds, nodeList, hash, true); err != nil {
+		t.Fatalf("failed to update status: %v", err)
+	}
+	updated, err := manager.kruiseClient.AppsV1beta1().DaemonSets(ds.Namespace).Get(context.TODO(), ds.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastRenderedTimeA := metav1.NewTime(start.Add(time.Minute))
+	lastRenderedTimeB := metav1.NewTime(start.Add(3 * time.Minute))
+	expected := []appsv1beta1.DaemonSetPatchStatus{
+		{MatchedNodes: 3, LastRenderedTime: &lastRenderedTimeA, Description: "label pods in zone a", Owner: "team-a"},
+		{MatchedNodes: 1, LastRenderedTime: &lastRenderedTimeB},
+		{MatchedNodes: 0},
+	}
+	if !reflect.DeepEqual(updated.Status.PatchStatuses, expected) {
+		t.Fatalf("expected patch statuses %+v, got %+v", expected, updated.Status.PatchStatuses)
+	}
+}
+
+func TestPatchAppliedEvents(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.DaemonSetPatches, true)()
+
+	ds := newDaemonSet("foo")
+	ds.Spec.Patches = []appsv1beta1.DaemonSetPatch{
+		{
+			Selector:    &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+			Patch:       runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"zone":"a"}}}`)},
+			Priority:    10,
+			Description: "label pods in zone a",
+			Owner:       "team-a",
+		},
+		{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+			Patch:    runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"tier":"base"}}}`)},
+		},
+		{
+			Selector:    &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "b"}},
+			Patch:       runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"zone":"b"}}}`)},
+			Description: "label pods in zone b",
+		},
+	}
+	manager, podControl, _, err := newTestController(ds)
+	if err != nil {
+		t.Fatalf("error creating DaemonSets controller: %v", err)
+	}
+	addNodes(manager.nodeStore, 0, 1, map[string]string{"zone": "a"})
+	addNodes(manager.nodeStore, 1, 1, map[string]string{"zone": "b"})
+	addNodes(manager.nodeStore, 2, 1, map[string]string{"zone": "c"})
+	if err = manager.dsStore.Add(ds); err != nil {
+		t.Fatal(err)
+	}
+	expectSyncDaemonSets(t, manager, ds, podControl, 3, 0, 2)
+
+	expected := sets.New[string](
+		"Normal PatchApplied Created pod on node node-0 with spec.patches[1]; spec.patches[0] (owner: team-a): label pods in zone a",
+		"Normal PatchApplied Created pod on node node-1 with spec.patches[2]: label pods in zone b",
+	)
+	got := sets.New[string](<-manager.fakeRecorder.Events, <-manager.fakeRecorder.Events)
+	if !got.Equal(expected) {
+		t.Fatalf("expected events %v, got %v", sets.List(expected), sets.List(got))
+	}
+}
+
+func TestPatchRenderErrorsMetrics(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-0", Labels: map[string]string{"type": "special"}}}
+	newDaemonSet := func(raw string) *appsv1beta1.DaemonSet {
+		return &appsv1beta1.DaemonSet{
+			Spec: appsv1beta1.DaemonSetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}},
+					Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Image: "main:v1"}}},
+				},
+				Patches: []appsv1beta1.DaemonSetPatch{{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"type": "special"}},
+					Patch:    runtime.RawExtension{Raw: []byte(raw)},
+				}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		patch          string
+		expectedReason string
+	}{
+		{
+			name:           "invalid json",
+			patch:          `{"spec":`,
+			expectedReason: patchRenderErrorDecode,
+		},
+		{
+			name:           "json patch",
+			patch:          `[{"op":"remove","path":"/metadata/labels/app"}]`,
+			expectedReason: patchRenderErrorDecode,
+		},
+		{
+			name:           "mismatched type",
+			patch:          `{"spec":{"containers":"main"}}`,
+			expectedReason: patchRenderErrorMerge,
+		},
+		{
+			name:           "labels not matching selector",
+			patch:          `{"metadata":{"labels":{"app":"other"}}}`,
+			expectedReason: patchRenderErrorValidation,
+		},
+		{
+			name:           "all containers deleted",
+			patch:          `{"spec":{"containers":[{"name":"main","$patch":"delete"}]}}`,
+			expectedReason: patchRenderErrorValidation,
+		},
+	}
+
+	reasons := []string{patchRenderErrorDecode, patchRenderErrorMerge, patchRenderErrorValidation}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := newDaemonSet(tt.patch)
+			before := map[string]float64{}
+			for _, reason := range reasons {
+				before[reason] = testutil.ToFloat64(DaemonSetPatchRenderErrorsMetrics.WithLabelValues(reason))
+			}
+
+			// rendering outside the reconcile path is not counted
+			if _, err := RenderPodTemplateForNode(ds, node); err == nil {
+				t.Fatalf("Expected error of rendering")
+			}
+			if _, err := renderPodTemplate(ds, node, &ds.Spec.Template); err == nil {
+				t.Fatalf("Expected error of rendering")
+			}
+
+			for _, reason := range reasons {
+				expected := before[reason]
+				if reason == tt.expectedReason {
+					expected++
+				}
+				if got := testutil.ToFloat64(DaemonSetPatchRenderErrorsMetrics.WithLabelValues(reason)); got != expected {
+					t.Fatalf("Expected %v errors of reason %s, got %v", expected, reason, got)
+				}
+			}
+		})
+	}
+}
+
+func gzipPatch(t *testing.T, document string) runtime.RawExtension {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write([]byte(document)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := json.Marshal(base64.StdEncoding.EncodeToString(buf.Bytes()))
+	return runtime.RawExtension{Raw: raw}
+}
+
+func TestApplyCompressedPatchesToPodTemplate(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.DaemonSetPatches, true)()
+
+	template := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "main", Image: "main:v1"}},
+		},
+	}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-0", Labels: map[string]string{"zone": "a"}}}
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}}
+	patchJSON := `{"metadata":{"labels":{"zone":"a"}},"spec":{"containers":[{"name":"main","env":[{"name":"A","value":"1"},{"name":"B","value":"2"}]}]}}`
+	patchYAML := "metadata:\n  labels:\n    zone: a\nspec:\n  containers:\n  - name: main\n    env:\n    - name: A\n      value: \"1\"\n    - name: B\n      value: \"2\"\n"
+
+	render := func(patch appsv1beta1.DaemonSetPatch) (*corev1.PodTemplateSpec, error) {
+		patch.Selector = selector
+		ds := &appsv1beta1.DaemonSet{Spec: appsv1beta1.DaemonSetSpec{Patches: []appsv1beta1.DaemonSetPatch{patch}}}
+		return applyPatchesToPodTemplate(ds, node, template)
+	}
+	expected, err := render(appsv1beta1.DaemonSetPatch{Patch: runtime.RawExtension{Raw: []byte(patchJSON)}})
+	if err != nil {
+		t.Fatalf("failed to render plaintext patch: %v", err)
+	}
+	if len(expected.Spec.Containers[0].Env) != 2 {
+		t.Fatalf("expected plaintext patch applied, got %+v", expected.Spec.Containers[0])
+	}
+
+	for name, document := range map[string]string{"json": patchJSON, "yaml": patchYAML} {
+		rendered, err := render(appsv1beta1.DaemonSetPatch{
+			Patch:         gzipPatch(t, document),
+			PatchEncoding: appsv1beta1.GzipDaemonSetPatchEncoding,
+		})
+		if err != nil {
+			t.Fatalf("failed to render compressed %s patch: %v", name, err)
+		}
+		if !reflect.DeepEqual(rendered, expected) {
+			t.Fatalf("expected compressed %s patch rendered as %+v, got %+v", name, expected, rendered)
+		}
+	}
+
+	// the compressed patch that is not base64 encoded, or is too large after decompression
+	for _, patch := range []runtime.RawExtension{
+		{Raw: []byte(`"not base64"`)},
+		gzipPatch(t, `{"metadata":{"annotations":{"a":"`+strings.Repeat("a", maxDecompressedPatchSize)+`"}}}`),
+	} {
+		_, err = render(appsv1beta1.DaemonSetPatch{Patch: patch, PatchEncoding: appsv1beta1.GzipDaemonSetPatchEncoding})
+		var renderErr *patchRenderError
+		if !errors.As(err, &renderErr) || renderErr.reason != patchRenderErrorDecode {
+			t.Fatalf("expected decode error for invalid compressed patch, got %v", err)
+		}
+	}
+}
+
+func TestApplySinglePatch(t *testing.T) {
+	template := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "main", Image: "main:v1"}},
+		},
+	}
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-0", Labels: map[string]string{"zone": "a"}}}
+	newPatch := func(zone, patch string) *appsv1beta1.DaemonSetPatch {
+		return &appsv1beta1.DaemonSetPatch{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": zone}},
+			Patch:    runtime.RawExtension{Raw: []byte(patch)},
+		}
+	}
+
+	// the matched patch is applied without changing the original template
+	patched, matched, err := ApplySinglePatch(template, newPatch("a", `{"spec":{"containers":[{"name":"main","image":"main:v2"}]}}`), node)
+	if err != nil || !matched {
+		t.Fatalf("expected patch applied, got matched %v, err %v", matched, err)
+	}
+	if patched.Spec.Containers[0].Image != "main:v2" || patched.Labels["app"] != "test" {
+		t.Fatalf("expected image patched and labels kept, got %+v", patched)
+	}
+	if template.Spec.Containers[0].Image != "main:v1" {
+		t.Fatalf("expected original template unchanged, got %+v", template)
+	}
+
+	// the template is returned as is if the node does not match
+	patched, matched, err = ApplySinglePatch(template, newPatch("b", `{"spec":{"containers":[{"name":"main","image":"main:v2"}]}}`), node)
+	if err != nil || matched || patched != template {
+		t.Fatalf("expected template unchanged for unmatched node, got matched %v, err %v", matched, err)
+	}
+
+	// the shadow patch is applied as well, which is only skipped by the controller
+	shadow := newPatch("a", `"spec:\n  hostNetwork: true\n"`)
+	shadow.Shadow = true
+	patched, matched, err = ApplySinglePatch(template, shadow, node)
+	if err != nil || !matched || !patched.Spec.HostNetwork {
+		t.Fatalf("expected YAML shadow patch applied, got matched %v, err %v", matched, err)
+	}
+
+	// the compressed patch is decompressed
+	compressed := &appsv1beta1.DaemonSetPatch{
+		Selector:      &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+		Patch:         gzipPatch(t, `{"metadata":{"labels":{"zone":"a"}}}`),
+		PatchEncoding: appsv1beta1.GzipDaemonSetPatchEncoding,
+	}
+	if patched, _, err = ApplySinglePatch(template, compressed, node); err != nil || patched.Labels["zone"] != "a" {
+		t.Fatalf("expected compressed patch applied, got %+v, err %v", patched, err)
+	}
+
+	// the invalid patch fails with the decode error
+	for _, patch := range []string{`[{"op":"add","path":"/spec/hostNetwork","value":true}]`, `{"spec":`} {
+		_, matched, err = ApplySinglePatch(template, newPatch("a", patch), node)
+		var renderErr *patchRenderError
+		if !matched || !errors.As(err, &renderErr) || renderErr.reason != patchRenderErrorDecode {
+			t.Fatalf("expected decode error for patch %s, got matched %v, err %v", patch, matched, err)
+		}
+	}
+}
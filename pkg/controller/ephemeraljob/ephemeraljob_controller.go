@@ -27,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	kubecontroller "k8s.io/kubernetes/pkg/controller"
@@ -42,6 +43,7 @@ import (
 	"github.com/openkruise/kruise/pkg/util"
 	utilclient "github.com/openkruise/kruise/pkg/util/client"
 	utildiscovery "github.com/openkruise/kruise/pkg/util/discovery"
+	"github.com/openkruise/kruise/pkg/util/ephemeralcontainer"
 	"github.com/openkruise/kruise/pkg/util/expectations"
 )
 
@@ -212,6 +214,9 @@ func (r *ReconcileEphemeralJob) Reconcile(context context.Context, request recon
 	}
 
 	klog.V(5).InfoS("Filter target pods", "targetPodCount", len(targetPods))
+	if targetsRequeueAfter := getTargetsRequeueAfter(job, targetPods); targetsRequeueAfter > 0 && (requeueAfter == 0 || targetsRequeueAfter < requeueAfter) {
+		requeueAfter = targetsRequeueAfter
+	}
 	// calculate status
 	if err := r.calculateStatus(job, targetPods); err != nil {
 		klog.ErrorS(err, "Error calculate EphemeralJob status", "ephemeralJob", klog.KObj(job))
@@ -220,6 +225,10 @@ func (r *ReconcileEphemeralJob) Reconcile(context context.Context, request recon
 	klog.InfoS("Sync calculate job status", "ephemeralJob", klog.KObj(job), "match", job.Status.Matches, "success", job.Status.Succeeded,
 		"failed", job.Status.Failed, "running", job.Status.Running, "waiting", job.Status.Waiting)
 
+	if err := r.stopTimeoutEphemeralContainers(job, targetPods); err != nil {
+		return reconcile.Result{RequeueAfter: requeueAfter}, err
+	}
+
 	if job.Status.Phase == appsv1alpha1.EphemeralJobPause {
 		return reconcile.Result{RequeueAfter: requeueAfter}, r.updateJobStatus(job)
 	}
@@ -255,8 +264,13 @@ func (r *ReconcileEphemeralJob) filterPods(job *appsv1alpha1.EphemeralJob) ([]*v
 	})
 
 	// Ignore inactive pods
+	podNames := sets.NewString(job.Spec.PodNames...)
 	var targetPods []*v1.Pod
 	for i := range podList.Items {
+		if podNames.Len() > 0 && !podNames.Has(podList.Items[i].Name) {
+			continue
+		}
+
 		if !kubecontroller.IsPodActive(&podList.Items[i]) {
 			continue
 		}
@@ -265,7 +279,8 @@ func (r *ReconcileEphemeralJob) filterPods(job *appsv1alpha1.EphemeralJob) ([]*v
 			continue
 		}
 
-		if job.Spec.Replicas == nil || len(targetPods) < int(*job.Spec.Replicas) {
+		// replicas will not work if pod names are given
+		if podNames.Len() > 0 || job.Spec.Replicas == nil || len(targetPods) < int(*job.Spec.Replicas) {
 			targetPods = append(targetPods, &podList.Items[i])
 		}
 	}
@@ -292,9 +307,13 @@ func (r *ReconcileEphemeralJob) filterInjectedPods(job *appsv1alpha1.EphemeralJo
 
 	control := econtainer.New(job)
 	// Ignore inactive pods
+	podNames := sets.NewString(job.Spec.PodNames...)
 	var targetPods []*v1.Pod
 	for i := range podList.Items {
 		pod := &podList.Items[i]
+		if podNames.Len() > 0 && !podNames.Has(pod.Name) {
+			continue
+		}
 		if !kubecontroller.IsPodActive(pod) {
 			continue
 		}
@@ -311,6 +330,12 @@ func (r *ReconcileEphemeralJob) filterInjectedPods(job *appsv1alpha1.EphemeralJo
 }
 
 func (r *ReconcileEphemeralJob) syncTargetPods(job *appsv1alpha1.EphemeralJob, targetPods []*v1.Pod) error {
+	// the job will be failed for exceeding the deadline, so no more targets are attached
+	if pastActiveDeadline(job) {
+		klog.InfoS("EphemeralJob exceeded the deadline, stop to attach new pods", "ephemeralJob", klog.KObj(job))
+		return nil
+	}
+
 	toCreatePods, _, _ := getSyncPods(job, targetPods)
 	if len(toCreatePods) == 0 {
 		klog.InfoS("There was no target pod to attach")
@@ -389,9 +414,13 @@ func (r *ReconcileEphemeralJob) calculateStatus(job *appsv1alpha1.EphemeralJob,
 	if err != nil {
 		return err
 	}
+	job.Status.PodStatuses, err = calculatePodStatuses(job, targetPods)
+	if err != nil {
+		return err
+	}
 
 	var replicas int32
-	if job.Spec.Replicas == nil {
+	if job.Spec.Replicas == nil || len(job.Spec.PodNames) > 0 {
 		replicas = job.Status.Matches
 	} else {
 		replicas = *job.Spec.Replicas
@@ -410,6 +439,11 @@ func (r *ReconcileEphemeralJob) calculateStatus(job *appsv1alpha1.EphemeralJob,
 		job.Status.CompletionTime = timeNow()
 		job.Status.Phase = appsv1alpha1.EphemeralJobFailed
 		job.Status.Conditions = addConditions(job.Status.Conditions, appsv1alpha1.EJobFailed, "JobFailed", "job failed to run all tasks")
+	} else if job.Status.Timeout > 0 && job.Status.Succeeded+job.Status.Failed+job.Status.Timeout == replicas {
+		job.Status.CompletionTime = timeNow()
+		job.Status.Phase = appsv1alpha1.EphemeralJobTimeout
+		job.Status.Conditions = addConditions(job.Status.Conditions, appsv1alpha1.EJobTimeout, "DeadlineExceeded",
+			fmt.Sprintf("EphemeralJob %s/%s has %d targets active longer than specified deadline", job.Namespace, job.Name, job.Status.Timeout))
 	} else if job.Status.Waiting == replicas {
 		job.Status.Phase = appsv1alpha1.EphemeralJobWaiting
 	} else {
@@ -442,6 +476,27 @@ func (r *ReconcileEphemeralJob) calculateStatus(job *appsv1alpha1.EphemeralJob,
 	return nil
 }
 
+// stopTimeoutEphemeralContainers asks kruise-daemon to stop the running ephemeral containers in targets which have
+// exceeded the deadline. It only works if kruise-daemon is watching pods and the container runtime supports it.
+func (r *ReconcileEphemeralJob) stopTimeoutEphemeralContainers(job *appsv1alpha1.EphemeralJob, targetPods []*v1.Pod) error {
+	for _, pod := range targetPods {
+		containerIDs, err := getTimeoutEphemeralContainerIDs(job, pod)
+		if err != nil {
+			return err
+		}
+		if len(containerIDs) == 0 {
+			continue
+		}
+		if patched, err := ephemeralcontainer.PatchPodContainersToStop(r.Client, pod, containerIDs); err != nil {
+			return fmt.Errorf("failed to stop timeout ephemeral containers in pod %s/%s: %v", pod.Namespace, pod.Name, err)
+		} else if patched {
+			klog.InfoS("EphemeralJob asked to stop timeout ephemeral containers in pod", "ephemeralJob", klog.KObj(job), "pod", klog.KObj(pod), "containerIDs", containerIDs)
+			r.recorder.Eventf(job, v1.EventTypeNormal, "StopTimeout", "stop timeout ephemeral containers %v in pod %s", containerIDs, pod.Name)
+		}
+	}
+	return nil
+}
+
 func (r *ReconcileEphemeralJob) updateJobStatus(job *appsv1alpha1.EphemeralJob) error {
 	klog.V(5).InfoS("Updating job status", "ephemeralJob", klog.KObj(job), "status", job.Status)
 	return r.Status().Update(context.TODO(), job)
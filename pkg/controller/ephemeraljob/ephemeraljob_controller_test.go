@@ -0,0 +1,332 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ephemeraljob
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+func newTestPod(name string, labels map[string]string) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+}
+
+func newTestEphemeralJob(podNames []string) *appsv1alpha1.EphemeralJob {
+	return &appsv1alpha1.EphemeralJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "ejob", Namespace: "default", UID: "ejob-uid"},
+		Spec: appsv1alpha1.EphemeralJobSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+			PodNames: podNames,
+			Replicas: ptr.To(int32(1)),
+			Template: appsv1alpha1.EphemeralContainerTemplateSpec{
+				EphemeralContainers: []v1.EphemeralContainer{
+					{EphemeralContainerCommon: v1.EphemeralContainerCommon{Name: "debugger", Image: "busybox"}},
+				},
+			},
+		},
+	}
+}
+
+func TestFilterPodsByPodNames(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = appsv1alpha1.AddToScheme(scheme)
+
+	pods := []runtime.Object{
+		newTestPod("api-7f9-abcde", map[string]string{"app": "api"}),
+		newTestPod("api-7f9-fghij", map[string]string{"app": "api"}),
+		newTestPod("api-7f9-klmno", map[string]string{"app": "api"}),
+		newTestPod("web-7f9-abcde", map[string]string{"app": "web"}),
+	}
+	r := &ReconcileEphemeralJob{Client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(pods...).Build(), scheme: scheme}
+
+	cases := []struct {
+		name     string
+		podNames []string
+		expected []string
+	}{
+		{
+			name:     "selector with replicas",
+			expected: []string{"api-7f9-abcde"},
+		},
+		{
+			name:     "pod names ignore replicas",
+			podNames: []string{"api-7f9-abcde", "api-7f9-fghij"},
+			expected: []string{"api-7f9-abcde", "api-7f9-fghij"},
+		},
+		{
+			name:     "pod names intersect with selector",
+			podNames: []string{"api-7f9-klmno", "web-7f9-abcde", "not-exist"},
+			expected: []string{"api-7f9-klmno"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			targetPods, err := r.filterPods(newTestEphemeralJob(tc.podNames))
+			if err != nil {
+				t.Fatalf("failed to filter pods: %v", err)
+			}
+			var got []string
+			for _, pod := range targetPods {
+				got = append(got, pod.Name)
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected target pods %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestCalculatePodStatuses(t *testing.T) {
+	job := newTestEphemeralJob([]string{"running", "not-injected", "not-exist", "succeeded"})
+	injected := v1.EphemeralContainer{
+		EphemeralContainerCommon: v1.EphemeralContainerCommon{
+			Name: "debugger",
+			Env:  []v1.EnvVar{{Name: appsv1alpha1.EphemeralContainerEnvKey, Value: string(job.UID)}},
+		},
+	}
+
+	runningPod := newTestPod("running", nil)
+	runningPod.Spec.EphemeralContainers = []v1.EphemeralContainer{injected}
+	runningPod.Status.EphemeralContainerStatuses = []v1.ContainerStatus{
+		{Name: "debugger", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+	}
+	succeededPod := newTestPod("succeeded", nil)
+	succeededPod.Spec.EphemeralContainers = []v1.EphemeralContainer{injected}
+	succeededPod.Status.EphemeralContainerStatuses = []v1.ContainerStatus{
+		{Name: "debugger", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 0, FinishedAt: metav1.Now()}}},
+	}
+	notInjectedPod := newTestPod("not-injected", nil)
+
+	podStatuses, err := calculatePodStatuses(job, []*v1.Pod{runningPod, succeededPod, notInjectedPod})
+	if err != nil {
+		t.Fatalf("failed to calculate pod statuses: %v", err)
+	}
+	expected := []appsv1alpha1.EphemeralJobPodStatus{
+		{Name: "not-exist", State: appsv1alpha1.EphemeralJobPodNotFound},
+		{Name: "not-injected", State: appsv1alpha1.EphemeralJobPodNotInjected},
+		{Name: "running", State: appsv1alpha1.EphemeralJobPodRunning},
+		{Name: "succeeded", State: appsv1alpha1.EphemeralJobPodSucceeded},
+	}
+	if !reflect.DeepEqual(podStatuses, expected) {
+		t.Fatalf("expected pod statuses %v, got %v", expected, podStatuses)
+	}
+
+	// the target not injected times out from the start time of the job
+	job.Spec.TargetActiveDeadlineSeconds = ptr.To(int64(60))
+	job.Status.StartTime = &metav1.Time{Time: time.Now().Add(-time.Minute * 5)}
+	if podStatuses, err = calculatePodStatuses(job, []*v1.Pod{notInjectedPod}); err != nil {
+		t.Fatalf("failed to calculate pod statuses: %v", err)
+	}
+	if podStatuses[1].Name != "not-injected" || podStatuses[1].State != appsv1alpha1.EphemeralJobPodTimeout {
+		t.Fatalf("expected the target not injected timed out, got %v", podStatuses)
+	}
+
+	job.Spec.PodNames = nil
+	if podStatuses, err = calculatePodStatuses(job, []*v1.Pod{runningPod}); err != nil || podStatuses != nil {
+		t.Fatalf("expected no pod statuses without pod names, got %v, err %v", podStatuses, err)
+	}
+}
+
+func TestIsTargetTimeout(t *testing.T) {
+	job := newTestEphemeralJob(nil)
+	job.Spec.TargetActiveDeadlineSeconds = ptr.To(int64(60))
+	job.Status.StartTime = &metav1.Time{Time: time.Now().Add(-time.Minute * 5)}
+
+	startedAt := func(ago time.Duration) metav1.Time { return metav1.NewTime(time.Now().Add(-ago)) }
+	cases := []struct {
+		name     string
+		statuses []v1.ContainerStatus
+		phase    v1.PodPhase
+		expected bool
+	}{
+		{
+			name:     "not started long after job started",
+			phase:    v1.PodUnknown,
+			expected: true,
+		},
+		{
+			name: "waiting long after job started",
+			statuses: []v1.ContainerStatus{
+				{Name: "debugger", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ContainerCreating"}}},
+			},
+			phase:    v1.PodPending,
+			expected: true,
+		},
+		{
+			name: "running within target deadline",
+			statuses: []v1.ContainerStatus{
+				{Name: "debugger", State: v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: startedAt(time.Second * 30)}}},
+			},
+			phase:    v1.PodRunning,
+			expected: false,
+		},
+		{
+			name: "running after target deadline",
+			statuses: []v1.ContainerStatus{
+				{Name: "debugger", State: v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: startedAt(time.Second * 90)}}},
+			},
+			phase:    v1.PodRunning,
+			expected: true,
+		},
+		{
+			name: "succeeded after target deadline",
+			statuses: []v1.ContainerStatus{
+				{Name: "debugger", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{StartedAt: startedAt(time.Second * 90), FinishedAt: startedAt(0)}}},
+			},
+			phase:    v1.PodSucceeded,
+			expected: false,
+		},
+		{
+			name: "failed before target deadline",
+			statuses: []v1.ContainerStatus{
+				{Name: "debugger", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 1, StartedAt: startedAt(time.Second * 90), FinishedAt: startedAt(time.Second * 80)}}},
+			},
+			phase:    v1.PodFailed,
+			expected: false,
+		},
+		{
+			name: "stopped at target deadline",
+			statuses: []v1.ContainerStatus{
+				{Name: "debugger", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 137, StartedAt: startedAt(time.Second * 90), FinishedAt: startedAt(time.Second * 10)}}},
+			},
+			phase:    v1.PodFailed,
+			expected: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isTargetTimeout(job, tc.statuses, tc.phase); got != tc.expected {
+				t.Fatalf("expected timeout %v, got %v", tc.expected, got)
+			}
+		})
+	}
+
+	job.Status.StartTime = &metav1.Time{Time: time.Now().Add(-time.Second * 30)}
+	if isTargetTimeout(job, nil, v1.PodUnknown) {
+		t.Fatalf("expected no target timeout within target deadline after job started")
+	}
+
+	job.Spec.TargetActiveDeadlineSeconds = nil
+	job.Spec.ActiveDeadlineSeconds = ptr.To(int64(60))
+	statuses := []v1.ContainerStatus{
+		{Name: "debugger", State: v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: startedAt(time.Second * 90)}}},
+	}
+	if isTargetTimeout(job, statuses, v1.PodRunning) {
+		t.Fatalf("expected no target timeout without targetActiveDeadlineSeconds")
+	}
+}
+
+func TestStopTimeoutEphemeralContainers(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = appsv1alpha1.AddToScheme(scheme)
+
+	job := newTestEphemeralJob([]string{"timeout", "running"})
+	job.Spec.TargetActiveDeadlineSeconds = ptr.To(int64(60))
+	job.Status.StartTime = &metav1.Time{Time: time.Now().Add(-time.Minute * 5)}
+	injected := v1.EphemeralContainer{
+		EphemeralContainerCommon: v1.EphemeralContainerCommon{
+			Name: "debugger",
+			Env:  []v1.EnvVar{{Name: appsv1alpha1.EphemeralContainerEnvKey, Value: string(job.UID)}},
+		},
+	}
+
+	timeoutPod := newTestPod("timeout", map[string]string{"app": "api"})
+	timeoutPod.Spec.EphemeralContainers = []v1.EphemeralContainer{injected}
+	timeoutPod.Status.EphemeralContainerStatuses = []v1.ContainerStatus{{
+		Name:        "debugger",
+		ContainerID: "containerd://timeout",
+		State:       v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: metav1.NewTime(time.Now().Add(-time.Minute * 2))}},
+	}}
+	runningPod := newTestPod("running", map[string]string{"app": "api"})
+	runningPod.Spec.EphemeralContainers = []v1.EphemeralContainer{injected}
+	runningPod.Status.EphemeralContainerStatuses = []v1.ContainerStatus{{
+		Name:        "debugger",
+		ContainerID: "containerd://running",
+		State:       v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: metav1.Now()}},
+	}}
+
+	r := &ReconcileEphemeralJob{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(timeoutPod, runningPod).Build(),
+		scheme:   scheme,
+		recorder: record.NewFakeRecorder(10),
+	}
+	if err := r.stopTimeoutEphemeralContainers(job, []*v1.Pod{timeoutPod, runningPod}); err != nil {
+		t.Fatalf("failed to stop timeout ephemeral containers: %v", err)
+	}
+
+	for name, expected := range map[string]string{"timeout": `["containerd://timeout"]`, "running": ""} {
+		pod := &v1.Pod{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: name}, pod); err != nil {
+			t.Fatalf("failed to get pod %s: %v", name, err)
+		}
+		if got := pod.Annotations[appsv1alpha1.EphemeralContainersToStopAnnotation]; got != expected {
+			t.Fatalf("expected pod %s containers to stop %q, got %q", name, expected, got)
+		}
+	}
+
+	if err := r.calculateStatus(job, []*v1.Pod{timeoutPod, runningPod}); err != nil {
+		t.Fatalf("failed to calculate status: %v", err)
+	}
+	if job.Status.Timeout != 1 || job.Status.Running != 1 || job.Status.Phase != appsv1alpha1.EphemeralJobRunning {
+		t.Fatalf("expected 1 timeout and 1 running target in running job, got %+v", job.Status)
+	}
+}
+
+func TestCalculateStatusPastActiveDeadline(t *testing.T) {
+	job := newTestEphemeralJob([]string{"running"})
+	job.Spec.ActiveDeadlineSeconds = ptr.To(int64(60))
+	job.Status.StartTime = &metav1.Time{Time: time.Now().Add(-time.Minute * 5)}
+	runningPod := newTestPod("running", map[string]string{"app": "api"})
+	runningPod.Spec.EphemeralContainers = []v1.EphemeralContainer{{
+		EphemeralContainerCommon: v1.EphemeralContainerCommon{
+			Name: "debugger",
+			Env:  []v1.EnvVar{{Name: appsv1alpha1.EphemeralContainerEnvKey, Value: string(job.UID)}},
+		},
+	}}
+	runningPod.Status.EphemeralContainerStatuses = []v1.ContainerStatus{{
+		Name:  "debugger",
+		State: v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: metav1.Now()}},
+	}}
+
+	r := &ReconcileEphemeralJob{}
+	if err := r.calculateStatus(job, []*v1.Pod{runningPod}); err != nil {
+		t.Fatalf("failed to calculate status: %v", err)
+	}
+	if job.Status.Running != 1 || job.Status.Timeout != 0 || job.Status.Phase != appsv1alpha1.EphemeralJobFailed || job.Status.CompletionTime == nil {
+		t.Fatalf("expected running job past the deadline to be failed, got %+v", job.Status)
+	}
+}
@@ -98,7 +98,7 @@ func (e *ejobHandler) Update(ctx context.Context, evt event.TypedUpdateEvent[*ap
 	// only update these fields
 	if oldEJob.Spec.TTLSecondsAfterFinished != curEJob.Spec.TTLSecondsAfterFinished ||
 		oldEJob.Spec.Paused != curEJob.Spec.Paused || oldEJob.Spec.Parallelism != curEJob.Spec.Parallelism ||
-		oldEJob.Spec.Replicas != curEJob.Spec.Replicas {
+		oldEJob.Spec.Replicas != curEJob.Spec.Replicas || !reflect.DeepEqual(oldEJob.Spec.PodNames, curEJob.Spec.PodNames) {
 		klog.V(3).InfoS("Observed updated Spec for EphemeralJob", "ephemeralJob", klog.KObj(curEJob))
 		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: curEJob.Namespace, Name: curEJob.Name}})
 	}
@@ -7,6 +7,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/klog/v2"
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
@@ -25,6 +26,102 @@ func pastActiveDeadline(job *appsv1alpha1.EphemeralJob) bool {
 	return duration >= allowedDuration
 }
 
+// getTargetDeadline returns the time when the target exceeds spec.targetActiveDeadlineSeconds. It is counted from the
+// earliest start time of the ephemeral containers in the target, or from the start time of the job if none of them
+// has started, e.g., the target has not been injected or its ephemeral containers are still waiting.
+func getTargetDeadline(job *appsv1alpha1.EphemeralJob, statuses []v1.ContainerStatus) (time.Time, bool) {
+	if job.Spec.TargetActiveDeadlineSeconds == nil {
+		return time.Time{}, false
+	}
+
+	eContainerMap, _ := getEphemeralContainersMaps(job.Spec.Template.EphemeralContainers)
+	var start time.Time
+	for i := range statuses {
+		if _, ok := eContainerMap[statuses[i].Name]; !ok {
+			continue
+		}
+		var startedAt time.Time
+		if statuses[i].State.Running != nil {
+			startedAt = statuses[i].State.Running.StartedAt.Time
+		} else if statuses[i].State.Terminated != nil {
+			startedAt = statuses[i].State.Terminated.StartedAt.Time
+		}
+		if !startedAt.IsZero() && (start.IsZero() || startedAt.Before(start)) {
+			start = startedAt
+		}
+	}
+	if start.IsZero() {
+		if job.Status.StartTime == nil {
+			return time.Time{}, false
+		}
+		start = job.Status.StartTime.Time
+	}
+	return start.Add(time.Duration(*job.Spec.TargetActiveDeadlineSeconds) * time.Second), true
+}
+
+// isTargetTimeout checks if the target in the given phase has exceeded spec.targetActiveDeadlineSeconds.
+func isTargetTimeout(job *appsv1alpha1.EphemeralJob, statuses []v1.ContainerStatus, phase v1.PodPhase) bool {
+	deadline, ok := getTargetDeadline(job, statuses)
+	if !ok || phase == v1.PodSucceeded {
+		return false
+	}
+	if phase == v1.PodFailed {
+		// containers finished after the deadline are considered as stopped for timeout
+		eContainerMap, _ := getEphemeralContainersMaps(job.Spec.Template.EphemeralContainers)
+		for i := range statuses {
+			if _, ok := eContainerMap[statuses[i].Name]; !ok || statuses[i].State.Terminated == nil {
+				continue
+			}
+			if !statuses[i].State.Terminated.FinishedAt.Time.Before(deadline) {
+				return true
+			}
+		}
+		return false
+	}
+	return !time.Now().Before(deadline)
+}
+
+// getTargetsRequeueAfter returns the duration until the nearest deadline of the targets, zero means no deadline.
+func getTargetsRequeueAfter(job *appsv1alpha1.EphemeralJob, pods []*v1.Pod) time.Duration {
+	var requeueAfter time.Duration
+	control := econtainer.New(job)
+	for _, pod := range pods {
+		deadline, ok := getTargetDeadline(job, control.GetEphemeralContainersStatus(pod))
+		if !ok {
+			continue
+		}
+		if left := time.Until(deadline); left > 0 && (requeueAfter == 0 || left < requeueAfter) {
+			requeueAfter = left
+		}
+	}
+	return requeueAfter
+}
+
+// getTimeoutEphemeralContainerIDs returns the IDs of running ephemeral containers in the target which has exceeded
+// spec.targetActiveDeadlineSeconds.
+func getTimeoutEphemeralContainerIDs(job *appsv1alpha1.EphemeralJob, pod *v1.Pod) ([]string, error) {
+	statuses := econtainer.New(job).GetEphemeralContainersStatus(pod)
+	phase, err := parseEphemeralPodStatus(job, statuses)
+	if err != nil {
+		return nil, err
+	}
+	if !isTargetTimeout(job, statuses, phase) {
+		return nil, nil
+	}
+
+	eContainerMap, _ := getEphemeralContainersMaps(job.Spec.Template.EphemeralContainers)
+	var containerIDs []string
+	for i := range statuses {
+		if _, ok := eContainerMap[statuses[i].Name]; !ok {
+			continue
+		}
+		if statuses[i].State.Running != nil && statuses[i].ContainerID != "" {
+			containerIDs = append(containerIDs, statuses[i].ContainerID)
+		}
+	}
+	return containerIDs, nil
+}
+
 func podMatchedEphemeralJob(pod *v1.Pod, ejob *appsv1alpha1.EphemeralJob) (bool, error) {
 	// if selector not matched, then continue
 	if pod.Namespace != ejob.Namespace {
@@ -34,6 +131,10 @@ func podMatchedEphemeralJob(pod *v1.Pod, ejob *appsv1alpha1.EphemeralJob) (bool,
 	if err != nil {
 		return false, err
 	}
+	if len(ejob.Spec.PodNames) > 0 {
+		// the listed pods are targeted only if they are also matched by selector
+		return sets.NewString(ejob.Spec.PodNames...).Has(pod.Name) && selector.Matches(labels.Set(pod.Labels)), nil
+	}
 	if !selector.Empty() && selector.Matches(labels.Set(pod.Labels)) {
 		return true, nil
 	}
@@ -139,13 +240,19 @@ func getSyncPods(job *appsv1alpha1.EphemeralJob, pods []*v1.Pod) (toCreate, toUp
 }
 
 func calculateEphemeralContainerStatus(job *appsv1alpha1.EphemeralJob, pods []*v1.Pod) error {
-	var success, failed, running, waiting int32
+	var success, failed, running, waiting, timeout int32
 	for _, pod := range pods {
-		state, err := parseEphemeralPodStatus(job, econtainer.New(job).GetEphemeralContainersStatus(pod))
+		statuses := econtainer.New(job).GetEphemeralContainersStatus(pod)
+		state, err := parseEphemeralPodStatus(job, statuses)
 		if err != nil {
 			return err
 		}
 
+		if isTargetTimeout(job, statuses, state) {
+			timeout++
+			continue
+		}
+
 		switch state {
 		case v1.PodSucceeded:
 			success++
@@ -162,6 +269,7 @@ func calculateEphemeralContainerStatus(job *appsv1alpha1.EphemeralJob, pods []*v
 	job.Status.Failed = failed
 	job.Status.Running = running
 	job.Status.Waiting = waiting
+	job.Status.Timeout = timeout
 
 	return nil
 }
@@ -206,6 +314,51 @@ func parseEphemeralPodStatus(ejob *appsv1alpha1.EphemeralJob, statuses []v1.Cont
 	return v1.PodUnknown, nil
 }
 
+// calculatePodStatuses returns the injection state of each pod listed in spec.podNames.
+func calculatePodStatuses(job *appsv1alpha1.EphemeralJob, pods []*v1.Pod) ([]appsv1alpha1.EphemeralJobPodStatus, error) {
+	if len(job.Spec.PodNames) == 0 {
+		return nil, nil
+	}
+
+	control := econtainer.New(job)
+	podsMap := make(map[string]*v1.Pod, len(pods))
+	for _, pod := range pods {
+		podsMap[pod.Name] = pod
+	}
+
+	podStatuses := make([]appsv1alpha1.EphemeralJobPodStatus, 0, len(job.Spec.PodNames))
+	for _, name := range sets.NewString(job.Spec.PodNames...).List() {
+		pod, ok := podsMap[name]
+		if !ok {
+			podStatuses = append(podStatuses, appsv1alpha1.EphemeralJobPodStatus{Name: name, State: appsv1alpha1.EphemeralJobPodNotFound})
+			continue
+		}
+		var statuses []v1.ContainerStatus
+		state := appsv1alpha1.EphemeralJobPodWaiting
+		if exists, owned := control.ContainsEphemeralContainer(pod); exists && owned {
+			statuses = control.GetEphemeralContainersStatus(pod)
+		} else {
+			state = appsv1alpha1.EphemeralJobPodNotInjected
+		}
+		phase, err := parseEphemeralPodStatus(job, statuses)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case isTargetTimeout(job, statuses, phase):
+			state = appsv1alpha1.EphemeralJobPodTimeout
+		case phase == v1.PodSucceeded:
+			state = appsv1alpha1.EphemeralJobPodSucceeded
+		case phase == v1.PodFailed:
+			state = appsv1alpha1.EphemeralJobPodFailed
+		case phase == v1.PodRunning:
+			state = appsv1alpha1.EphemeralJobPodRunning
+		}
+		podStatuses = append(podStatuses, appsv1alpha1.EphemeralJobPodStatus{Name: name, State: state})
+	}
+	return podStatuses, nil
+}
+
 type ephemeralContainerStatusState int
 
 const (
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"reflect"
+	"sort"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -259,9 +260,16 @@ func (r *ReconcileImageListPullJob) computeImagePullJobActions(job *appsv1beta1.
 }
 
 func (r *ReconcileImageListPullJob) calculateStatus(job *appsv1beta1.ImageListPullJob, imagePullJobs map[string]*appsv1beta1.ImagePullJob) *appsv1beta1.ImageListPullJobStatus {
-	var active, completed, succeeded int32
+	var active, completed, succeeded, failed int32
 	// record the failed image status
 	var failedImageStatuses []*appsv1beta1.FailedImageStatus
+	var failedNodeLimit, failedImageLimit int32
+	if job.Spec.CompletionPolicy.FailedNodeLimit != nil {
+		failedNodeLimit = *job.Spec.CompletionPolicy.FailedNodeLimit
+	}
+	if job.Spec.CompletionPolicy.FailedImageLimit != nil {
+		failedImageLimit = *job.Spec.CompletionPolicy.FailedImageLimit
+	}
 
 	for _, imagePullJob := range imagePullJobs {
 		if imagePullJob.Status.StartTime == nil {
@@ -272,21 +280,26 @@ func (r *ReconcileImageListPullJob) calculateStatus(job *appsv1beta1.ImageListPu
 			active = active + 1
 		}
 
+		// the image is regarded as succeeded if its failed nodes are tolerated by completionPolicy.failedNodeLimit
+		tolerated := imagePullJob.Status.Failed <= failedNodeLimit
 		if imagePullJob.Status.Failed > 0 {
 			failedImagePullJobStatus := &appsv1beta1.FailedImageStatus{
 				ImagePullJob: imagePullJob.Name,
 				Name:         imagePullJob.Spec.Image,
 				Message:      fmt.Sprintf("Please check for details which nodes failed by 'kubectl get ImagePullJob %s'.", imagePullJob.Name),
+				FailedNodes:  imagePullJob.Status.FailedNodes,
+				Tolerated:    tolerated,
 			}
 			failedImageStatuses = append(failedImageStatuses, failedImagePullJobStatus)
 		}
 
 		if imagePullJob.Status.Desired == (imagePullJob.Status.Failed + imagePullJob.Status.Succeeded) {
 			completed = completed + 1
-		}
-
-		if imagePullJob.Status.Desired == imagePullJob.Status.Succeeded {
-			succeeded = succeeded + 1
+			if tolerated {
+				succeeded = succeeded + 1
+			} else {
+				failed = failed + 1
+			}
 		}
 	}
 
@@ -296,9 +309,13 @@ func (r *ReconcileImageListPullJob) calculateStatus(job *appsv1beta1.ImageListPu
 		Active:              active,
 		Completed:           completed,
 		Succeeded:           succeeded,
+		Failed:              failed,
 		StartTime:           job.Status.StartTime,
 		FailedImageStatuses: failedImageStatuses,
 	}
+	sort.Slice(newStatus.FailedImageStatuses, func(i, j int) bool {
+		return newStatus.FailedImageStatuses[i].Name < newStatus.FailedImageStatuses[j].Name
+	})
 
 	now := metav1.NewTime(r.clock.Now())
 	if newStatus.StartTime == nil {
@@ -307,6 +324,10 @@ func (r *ReconcileImageListPullJob) calculateStatus(job *appsv1beta1.ImageListPu
 
 	if job.Spec.CompletionPolicy.Type != appsv1beta1.Never && newStatus.Desired == newStatus.Completed {
 		newStatus.CompletionTime = &now
+		newStatus.Phase = appsv1beta1.ImageListPullJobSucceeded
+		if newStatus.Failed > failedImageLimit {
+			newStatus.Phase = appsv1beta1.ImageListPullJobFailed
+		}
 	}
 	return newStatus
 }
@@ -29,6 +29,7 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/clock"
+	utilpointer "k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -423,6 +424,111 @@ func TestComputeImagePullJobActions(t *testing.T) {
 	}
 }
 
+func TestCalculateStatusWithFailureTolerance(t *testing.T) {
+	now := metav1.Now()
+	newImagePullJob := func(image string, succeeded, failed int32, failedNodes ...string) *appsv1beta1.ImagePullJob {
+		return &appsv1beta1.ImagePullJob{
+			ObjectMeta: metav1.ObjectMeta{Name: "job-" + image, Namespace: "default"},
+			Spec:       appsv1beta1.ImagePullJobSpec{Image: image},
+			Status: appsv1beta1.ImagePullJobStatus{
+				StartTime:   &now,
+				Desired:     3,
+				Active:      3 - succeeded - failed,
+				Succeeded:   succeeded,
+				Failed:      failed,
+				FailedNodes: failedNodes,
+			},
+		}
+	}
+	imagePullJobs := map[string]*appsv1beta1.ImagePullJob{
+		images[0]: newImagePullJob(images[0], 3, 0),
+		images[1]: newImagePullJob(images[1], 2, 1, "node-1"),
+		images[2]: newImagePullJob(images[2], 1, 2, "node-1", "node-2"),
+	}
+
+	cases := []struct {
+		name             string
+		failedNodeLimit  *int32
+		failedImageLimit *int32
+		imagePullJobs    map[string]*appsv1beta1.ImagePullJob
+		expectSucceeded  int32
+		expectFailed     int32
+		expectPhase      appsv1beta1.ImageListPullJobPhase
+		expectTolerated  []bool
+	}{
+		{
+			name:            "no failure tolerated",
+			imagePullJobs:   imagePullJobs,
+			expectSucceeded: 1,
+			expectFailed:    2,
+			expectPhase:     appsv1beta1.ImageListPullJobFailed,
+			expectTolerated: []bool{false, false},
+		},
+		{
+			name:            "failed nodes tolerated partially",
+			failedNodeLimit: utilpointer.Int32(1),
+			imagePullJobs:   imagePullJobs,
+			expectSucceeded: 2,
+			expectFailed:    1,
+			expectPhase:     appsv1beta1.ImageListPullJobFailed,
+			expectTolerated: []bool{true, false},
+		},
+		{
+			name:             "failed images tolerated",
+			failedNodeLimit:  utilpointer.Int32(1),
+			failedImageLimit: utilpointer.Int32(1),
+			imagePullJobs:    imagePullJobs,
+			expectSucceeded:  2,
+			expectFailed:     1,
+			expectPhase:      appsv1beta1.ImageListPullJobSucceeded,
+			expectTolerated:  []bool{true, false},
+		},
+		{
+			name:             "not completed",
+			failedNodeLimit:  utilpointer.Int32(2),
+			failedImageLimit: utilpointer.Int32(1),
+			imagePullJobs: map[string]*appsv1beta1.ImagePullJob{
+				images[0]: newImagePullJob(images[0], 2, 0),
+				images[1]: imagePullJobs[images[1]],
+				images[2]: imagePullJobs[images[2]],
+			},
+			expectSucceeded: 2,
+			expectTolerated: []bool{true, true},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			job := &appsv1beta1.ImageListPullJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "default"},
+				Spec: appsv1beta1.ImageListPullJobSpec{
+					Images: images,
+					ImagePullJobTemplate: appsv1beta1.ImagePullJobTemplate{
+						CompletionPolicy: appsv1beta1.CompletionPolicy{
+							Type:             appsv1beta1.Always,
+							FailedNodeLimit:  cs.failedNodeLimit,
+							FailedImageLimit: cs.failedImageLimit,
+						},
+					},
+				},
+			}
+			r := &ReconcileImageListPullJob{clock: clock.RealClock{}}
+			status := r.calculateStatus(job, cs.imagePullJobs)
+			assert.Equal(t, cs.expectSucceeded, status.Succeeded)
+			assert.Equal(t, cs.expectFailed, status.Failed)
+			assert.Equal(t, cs.expectPhase, status.Phase)
+			assert.Equal(t, cs.expectPhase != "", status.CompletionTime != nil)
+
+			var tolerated []bool
+			for _, s := range status.FailedImageStatuses {
+				assert.Equal(t, cs.imagePullJobs[s.Name].Status.FailedNodes, s.FailedNodes)
+				tolerated = append(tolerated, s.Tolerated)
+			}
+			assert.Equal(t, cs.expectTolerated, tolerated)
+		})
+	}
+}
+
 func createReconcileJob(scheme *k8sruntime.Scheme, initObjs ...client.Object) ReconcileImageListPullJob {
 	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).
 		WithIndex(&appsv1beta1.ImagePullJob{}, fieldindex.IndexNameForOwnerRefUID, func(obj client.Object) []string {
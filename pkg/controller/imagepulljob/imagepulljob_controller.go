@@ -30,6 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/clock"
@@ -38,9 +39,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/openkruise/kruise/apis/apps/defaults"
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
 	daemonutil "github.com/openkruise/kruise/pkg/daemon/util"
@@ -55,6 +59,8 @@ import (
 
 func init() {
 	flag.IntVar(&concurrentReconciles, "imagepulljob-workers", concurrentReconciles, "Max concurrent workers for ImagePullJob controller.")
+	// register prometheus
+	metrics.Registry.MustRegister(ImagePullJobActivePullsMetrics)
 }
 
 var (
@@ -62,12 +68,22 @@ var (
 	controllerKind              = appsv1beta1.SchemeGroupVersion.WithKind("ImagePullJob")
 	resourceVersionExpectations = expectations.NewResourceVersionExpectation()
 	scaleExpectations           = expectations.NewScaleExpectations()
+
+	ImagePullJobActivePullsMetrics = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "imagepulljob_active_pulls",
+			Help: "The number of nodes actively pulling the images of ImagePullJob",
+		}, []string{"namespace", "name"},
+	)
 )
 
 const (
 	defaultParallelism = 1
 	minRequeueTime     = time.Second
 
+	// serviceAccountNotFoundRequeueTime is the interval to recheck a missing ServiceAccount
+	serviceAccountNotFoundRequeueTime = 10 * time.Second
+
 	// SecretAnnotationSourceSecretKey stores the reference (namespace/name) to the source secret
 	// that was used to create the corresponding secret in kruise-daemon-config namespace.
 	SecretAnnotationSourceSecretKey = "imagepulljobs.kruise.io/source-key"
@@ -130,6 +146,12 @@ func add(mgr manager.Manager, r *ReconcileImagePullJob) error {
 		return err
 	}
 
+	// Watch for node for jobs that filter nodes by node fields or schedulability
+	err = c.Watch(source.Kind(mgr.GetCache(), &v1.Node{}, &nodeEventHandler{Reader: mgr.GetCache()}))
+	if err != nil {
+		return err
+	}
+
 	// Watch for secret for jobs that have pullSecrets
 	err = c.Watch(source.Kind(mgr.GetCache(), &v1.Secret{}, &secretEventHandler{Reader: mgr.GetCache()}))
 	if err != nil {
@@ -152,6 +174,7 @@ type ReconcileImagePullJob struct {
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=imagepulljobs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=imagepulljobs/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=imagepulljobs/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch
 
 // Reconcile reads that state of the cluster for a ImagePullJob object and makes changes based on the state read
 // and what is in the ImagePullJob.Spec
@@ -176,6 +199,7 @@ func (r *ReconcileImagePullJob) Reconcile(_ context.Context, request reconcile.R
 		if errors.IsNotFound(err) {
 			// Object not found, return.  Created objects are automatically garbage collected.
 			// For additional cleanup logic use finalizers.
+			ImagePullJobActivePullsMetrics.DeleteLabelValues(request.Namespace, request.Name)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -204,12 +228,14 @@ func (r *ReconcileImagePullJob) Reconcile(_ context.Context, request reconcile.R
 	}
 
 	if job.DeletionTimestamp != nil {
+		ImagePullJobActivePullsMetrics.DeleteLabelValues(job.Namespace, job.Name)
 		// ensure the GC of secrets and remove protection finalizer
 		return reconcile.Result{}, r.finalize(job)
 	}
 
 	// The Job has been finished
 	if job.Status.CompletionTime != nil {
+		ImagePullJobActivePullsMetrics.DeleteLabelValues(job.Namespace, job.Name)
 		// ensure the GC of secrets and remove protection finalizer
 		if err = r.finalize(job); err != nil {
 			return reconcile.Result{}, fmt.Errorf("failed to remove finalizer: %v", err)
@@ -254,6 +280,17 @@ func (r *ReconcileImagePullJob) Reconcile(_ context.Context, request reconcile.R
 		}
 	}
 
+	// wait for the ServiceAccount which provides pull secrets
+	if job.Spec.ServiceAccountName != "" {
+		sa := &v1.ServiceAccount{}
+		if err = r.Get(context.TODO(), types.NamespacedName{Namespace: job.Namespace, Name: job.Spec.ServiceAccountName}, sa); err != nil {
+			if !errors.IsNotFound(err) {
+				return reconcile.Result{}, fmt.Errorf("failed to get serviceAccount %s: %v", job.Spec.ServiceAccountName, err)
+			}
+			return reconcile.Result{RequeueAfter: serviceAccountNotFoundRequeueTime}, r.updateServiceAccountNotFoundStatus(job)
+		}
+	}
+
 	// sync secret to kruise-daemon-config namespace before pulling
 	secrets, err := r.syncJobPullSecrets(job)
 	if err != nil {
@@ -265,6 +302,7 @@ func (r *ReconcileImagePullJob) Reconcile(_ context.Context, request reconcile.R
 	if err != nil {
 		return reconcile.Result{}, fmt.Errorf("failed to calculate status: %v", err)
 	}
+	ImagePullJobActivePullsMetrics.WithLabelValues(job.Namespace, job.Name).Set(float64(newStatus.Active))
 
 	// Sync image to more NodeImages
 	if err = r.syncNodeImages(job, newStatus, notSyncedNodeImages, secrets); err != nil {
@@ -298,7 +336,11 @@ func (r *ReconcileImagePullJob) syncJobPullSecrets(job *appsv1beta1.ImagePullJob
 	var secretRefs []appsv1beta1.ReferenceObject
 	// If it's in kruise-daemon-config namespace, no need to go through the sync logic, return directly
 	if job.Namespace == util.GetKruiseDaemonConfigNamespace() {
-		for _, name := range job.Spec.PullSecrets {
+		names, err := r.getPullSecretNames(job)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
 			secretRefs = append(secretRefs, appsv1beta1.ReferenceObject{Namespace: job.Namespace, Name: name})
 		}
 		return secretRefs, nil
@@ -317,16 +359,58 @@ func (r *ReconcileImagePullJob) syncJobPullSecrets(job *appsv1beta1.ImagePullJob
 	return r.claimImagePullJobSecrets(job, pullSecrets)
 }
 
+// getPullSecretNames returns the names of pull secrets for the job, including the spec.pullSecrets and
+// the imagePullSecrets of spec.serviceAccountName. The ServiceAccount is resolved on each call so that
+// its updates will be synced. A missing ServiceAccount contributes no secrets, it is reported by Reconcile.
+func (r *ReconcileImagePullJob) getPullSecretNames(job *appsv1beta1.ImagePullJob) ([]string, error) {
+	if job.Spec.ServiceAccountName == "" {
+		return job.Spec.PullSecrets, nil
+	}
+	sa := &v1.ServiceAccount{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: job.Namespace, Name: job.Spec.ServiceAccountName}, sa); err != nil {
+		if errors.IsNotFound(err) {
+			return job.Spec.PullSecrets, nil
+		}
+		return nil, fmt.Errorf("failed to get serviceAccount %s: %v", job.Spec.ServiceAccountName, err)
+	}
+	names := sets.NewString(job.Spec.PullSecrets...)
+	result := append([]string{}, job.Spec.PullSecrets...)
+	for _, ref := range sa.ImagePullSecrets {
+		if ref.Name == "" || names.Has(ref.Name) {
+			continue
+		}
+		names.Insert(ref.Name)
+		result = append(result, ref.Name)
+	}
+	return result, nil
+}
+
+// updateServiceAccountNotFoundStatus records in status that the job is waiting for its ServiceAccount.
+func (r *ReconcileImagePullJob) updateServiceAccountNotFoundStatus(job *appsv1beta1.ImagePullJob) error {
+	newStatus := job.Status.DeepCopy()
+	if newStatus.StartTime == nil {
+		now := metav1.NewTime(r.clock.Now())
+		newStatus.StartTime = &now
+	}
+	newStatus.Message = fmt.Sprintf("job is waiting, serviceAccount %s not found", job.Spec.ServiceAccountName)
+	if util.IsJSONObjectEqual(&job.Status, newStatus) {
+		return nil
+	}
+	job.Status = *newStatus
+	if err := r.Status().Update(context.TODO(), job); err != nil {
+		return fmt.Errorf("update ImagePullJob status error: %v", err)
+	}
+	resourceVersionExpectations.Expect(job)
+	return nil
+}
+
 func (r *ReconcileImagePullJob) syncNodeImages(job *appsv1beta1.ImagePullJob, newStatus *appsv1beta1.ImagePullJobStatus, notSyncedNodeImages []string, secrets []appsv1beta1.ReferenceObject) error {
 	if len(notSyncedNodeImages) == 0 {
 		return nil
 	}
 
 	klog.V(3).InfoS("Start to sync NodeImages", "imagePullJob", klog.KObj(job), "activePullingCount", newStatus.Active)
-	parallelismLimit := defaultParallelism
-	if job.Spec.Parallelism != nil {
-		parallelismLimit = job.Spec.Parallelism.IntValue()
-	}
+	parallelismLimit := getParallelism(job)
 	parallelism := parallelismLimit - int(newStatus.Active)
 	if parallelism <= 0 {
 		klog.V(3).InfoS("Found ImagePullJob have active pulling more than parallelism, so skip to sync the left NodeImages",
@@ -340,67 +424,88 @@ func (r *ReconcileImagePullJob) syncNodeImages(job *appsv1beta1.ImagePullJob, ne
 	ownerRef := getOwnerRef(job)
 	pullPolicy := getImagePullPolicy(job)
 	now := metav1.NewTime(r.clock.Now())
-	imageName, imageTag, _ := daemonutil.NormalizeImageRefToNameTag(job.Spec.Image)
+	images := getJobImages(job)
 	for i := 0; i < parallelism; i++ {
-		var skip bool
+		var skip, unsupported bool
 		updateErr := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 			nodeImage := appsv1beta1.NodeImage{}
 			if err := r.Get(context.TODO(), types.NamespacedName{Name: notSyncedNodeImages[i]}, &nodeImage); err != nil {
 				return err
 			}
+			// the capabilities may be reported after the status calculated, never assign images to the nodes
+			// that kruise-daemon is not able to pull them
+			if !nodeImage.Status.Capabilities.IsFeatureSupported(appsv1beta1.NodeFeatureImagePull) {
+				unsupported = true
+				return nil
+			}
 			if nodeImage.Spec.Images == nil {
-				nodeImage.Spec.Images = make(map[string]appsv1beta1.ImageSpec, 1)
+				nodeImage.Spec.Images = make(map[string]appsv1beta1.ImageSpec, len(images))
 			}
-			imageSpec := nodeImage.Spec.Images[imageName]
-			imageSpec.SandboxConfig = job.Spec.SandboxConfig
 
-			for _, secret := range secrets {
-				if !containsObject(imageSpec.PullSecrets, secret) {
-					imageSpec.PullSecrets = append(imageSpec.PullSecrets, secret)
+			var changed bool
+			for _, image := range images {
+				imageName, imageTag, _ := daemonutil.NormalizeImageRefToNameTag(image)
+				imageSpec := nodeImage.Spec.Images[imageName]
+				imageSpec.SandboxConfig = job.Spec.SandboxConfig
+
+				for _, secret := range secrets {
+					if !containsObject(imageSpec.PullSecrets, secret) {
+						imageSpec.PullSecrets = append(imageSpec.PullSecrets, secret)
+					}
 				}
-			}
 
-			var found bool
-			for i := range imageSpec.Tags {
-				tagSpec := &imageSpec.Tags[i]
-				if tagSpec.Tag != imageTag {
-					continue
+				var found, synced bool
+				for i := range imageSpec.Tags {
+					tagSpec := &imageSpec.Tags[i]
+					if tagSpec.Tag != imageTag {
+						continue
+					}
+					if util.ContainsObjectRef(tagSpec.OwnerReferences, *ownerRef) {
+						synced = true
+						break
+					}
+					// increase version to start a new round of image downloads
+					tagSpec.Version++
+					// merge owner reference
+					tagSpec.OwnerReferences = append(tagSpec.OwnerReferences, *ownerRef)
+					tagSpec.CreatedAt = &now
+					tagSpec.ImagePullPolicy = job.Spec.ImagePullPolicy
+					tagSpec.Platform = job.Spec.Platform
+					found = true
+					break
 				}
-				if util.ContainsObjectRef(tagSpec.OwnerReferences, *ownerRef) {
-					skip = true
-					return nil
+				if synced {
+					continue
 				}
-				// increase version to start a new round of image downloads
-				tagSpec.Version++
-				// merge owner reference
-				tagSpec.OwnerReferences = append(tagSpec.OwnerReferences, *ownerRef)
-				tagSpec.CreatedAt = &now
-				tagSpec.ImagePullPolicy = job.Spec.ImagePullPolicy
-				found = true
-				break
-			}
-			if !found {
-				var foundVersion int64 = -1
-				if imageStatus, ok := nodeImage.Status.ImageStatuses[imageName]; ok {
-					for _, tagStatus := range imageStatus.Tags {
-						if tagStatus.Tag == imageTag {
-							foundVersion = tagStatus.Version
-							break
+				if !found {
+					var foundVersion int64 = -1
+					if imageStatus, ok := nodeImage.Status.ImageStatuses[imageName]; ok {
+						for _, tagStatus := range imageStatus.Tags {
+							if tagStatus.Tag == imageTag {
+								foundVersion = tagStatus.Version
+								break
+							}
 						}
 					}
-				}
 
-				imageSpec.Tags = append(imageSpec.Tags, appsv1beta1.ImageTagSpec{
-					Tag:             imageTag,
-					Version:         foundVersion + 1,
-					PullPolicy:      pullPolicy,
-					OwnerReferences: []v1.ObjectReference{*ownerRef},
-					CreatedAt:       &now,
-					ImagePullPolicy: job.Spec.ImagePullPolicy,
-				})
+					imageSpec.Tags = append(imageSpec.Tags, appsv1beta1.ImageTagSpec{
+						Tag:             imageTag,
+						Version:         foundVersion + 1,
+						PullPolicy:      pullPolicy,
+						OwnerReferences: []v1.ObjectReference{*ownerRef},
+						CreatedAt:       &now,
+						ImagePullPolicy: job.Spec.ImagePullPolicy,
+						Platform:        job.Spec.Platform,
+					})
+				}
+				utilimagejob.SortSpecImageTagsV1beta1(&imageSpec)
+				nodeImage.Spec.Images[imageName] = imageSpec
+				changed = true
+			}
+			if !changed {
+				skip = true
+				return nil
 			}
-			utilimagejob.SortSpecImageTagsV1beta1(&imageSpec)
-			nodeImage.Spec.Images[imageName] = imageSpec
 
 			oldResourceVersion := nodeImage.ResourceVersion
 			err := r.Update(context.TODO(), &nodeImage)
@@ -414,11 +519,14 @@ func (r *ReconcileImagePullJob) syncNodeImages(job *appsv1beta1.ImagePullJob, ne
 		})
 		if updateErr != nil {
 			return fmt.Errorf("update NodeImage %s error: %v", notSyncedNodeImages[i], updateErr)
+		} else if unsupported {
+			klog.V(3).InfoS("ImagePullJob skipped NodeImage not supporting image pull", "imagePullJob", klog.KObj(job), "nodeImage", notSyncedNodeImages[i])
+			continue
 		} else if skip {
-			klog.V(4).InfoS("ImagePullJob found image already synced in NodeImage", "imagePullJob", klog.KObj(job), "image", job.Spec.Image, "nodeImage", notSyncedNodeImages[i])
+			klog.V(4).InfoS("ImagePullJob found images already synced in NodeImage", "imagePullJob", klog.KObj(job), "images", images, "nodeImage", notSyncedNodeImages[i])
 			continue
 		}
-		klog.V(3).InfoS("ImagePullJob had synced image into NodeImage", "imagePullJob", klog.KObj(job), "image", job.Spec.Image, "nodeImage", notSyncedNodeImages[i])
+		klog.V(3).InfoS("ImagePullJob had synced images into NodeImage", "imagePullJob", klog.KObj(job), "images", images, "nodeImage", notSyncedNodeImages[i])
 	}
 	return nil
 }
@@ -454,7 +562,11 @@ func (r *ReconcileImagePullJob) classifyPullSecretsForJob(job *appsv1beta1.Image
 
 	// Only sync pullSecrets when the job is in running state
 	if job.DeletionTimestamp.IsZero() && job.Status.CompletionTime == nil {
-		for _, name := range job.Spec.PullSecrets {
+		names, err := r.getPullSecretNames(job)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, name := range names {
 			pullSecrets[appsv1beta1.ReferenceObject{Namespace: job.Namespace, Name: name}] = nil
 		}
 	}
@@ -613,76 +725,92 @@ func (r *ReconcileImagePullJob) calculateStatus(job *appsv1beta1.ImagePullJob, n
 		newStatus.StartTime = &now
 	}
 
-	imageName, imageTag, err := daemonutil.NormalizeImageRefToNameTag(job.Spec.Image)
-	if err != nil {
-		return nil, nil, fmt.Errorf("invalid image %s: %v", job.Spec.Image, err)
+	images := getJobImages(job)
+	imageNames := make([]string, len(images))
+	imageTags := make([]string, len(images))
+	for i, image := range images {
+		imageName, imageTag, err := daemonutil.NormalizeImageRefToNameTag(image)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid image %s: %v", image, err)
+		}
+		imageNames[i], imageTags[i] = imageName, imageTag
+	}
+	imageStatuses := make([]appsv1beta1.ImagePullJobImageStatus, len(images))
+	for i, image := range images {
+		imageStatuses[i] = appsv1beta1.ImagePullJobImageStatus{Image: image, Desired: int32(len(nodeImages))}
 	}
 
-	var notSynced, pulling, succeeded, failed []string
+	var notSynced, pulling, succeeded, failed, unsupported []string
+	// the sum of the pulling progress of all images on all nodes
+	var totalProgress int64
+	// the times observed from the NodeImages to estimate when the waiting nodes will be synced
+	var pullingSyncedTimes []time.Time
+	var pullDurations []time.Duration
+	lastSyncedTime := newStatus.StartTime.Time
 	for _, nodeImage := range nodeImages {
-		var tagVersion int64 = -1
-		var secretSynced bool = true
-		if imageSpec, ok := nodeImage.Spec.Images[imageName]; ok {
-			for _, secret := range secrets {
-				if !containsObject(imageSpec.PullSecrets, secret) {
-					secretSynced = false
-					break
-				}
+		// fail fast on the nodes that kruise-daemon is not able to pull images
+		if !nodeImage.Status.Capabilities.IsFeatureSupported(appsv1beta1.NodeFeatureImagePull) {
+			unsupported = append(unsupported, nodeImage.Name)
+			for i := range imageStatuses {
+				imageStatuses[i].Failed++
 			}
+			totalProgress += int64(100 * len(images))
+			continue
+		}
 
-			if !secretSynced {
-				notSynced = append(notSynced, nodeImage.Name)
-				continue
+		// the state of a node is the least progressed one among all images
+		nodeState := imagePullSucceeded
+		for i := range images {
+			state, progress := getImagePullState(job, nodeImage, imageNames[i], imageTags[i], secrets)
+			totalProgress += int64(progress)
+			switch state {
+			case imagePulling:
+				imageStatuses[i].Active++
+			case imagePullSucceeded:
+				imageStatuses[i].Succeeded++
+			case imagePullFailed:
+				imageStatuses[i].Failed++
 			}
-
-			for _, tagSpec := range imageSpec.Tags {
-				if tagSpec.Tag != imageTag {
-					continue
-				}
-				var foundOwner bool
-				for _, ref := range tagSpec.OwnerReferences {
-					if ref.UID == job.UID {
-						foundOwner = true
-						break
-					}
-				}
-				if !foundOwner {
-					break
-				}
-				tagVersion = tagSpec.Version
+			if state < nodeState {
+				nodeState = state
 			}
 		}
-
-		if tagVersion < 0 {
+		switch nodeState {
+		case imagePullNotSynced:
 			notSynced = append(notSynced, nodeImage.Name)
-			continue
+		case imagePulling:
+			pulling = append(pulling, nodeImage.Name)
+		case imagePullFailed:
+			failed = append(failed, nodeImage.Name)
+		default:
+			succeeded = append(succeeded, nodeImage.Name)
 		}
 
-		imageStatus, _ := nodeImage.Status.ImageStatuses[imageName]
-		foundTag := false
-		for _, tagStatus := range imageStatus.Tags {
-			if tagStatus.Tag != imageTag {
-				continue
-			}
-			if tagStatus.Version != tagVersion {
-				break
-			}
-			switch tagStatus.Phase {
-			case appsv1beta1.ImagePhaseSucceeded:
-				succeeded = append(succeeded, nodeImage.Name)
-			case appsv1beta1.ImagePhaseFailed:
-				failed = append(failed, nodeImage.Name)
-			default:
-				pulling = append(pulling, nodeImage.Name)
+		if nodeState == imagePullNotSynced {
+			continue
+		}
+		syncedTime, completionTime := getImagePullTimes(job, nodeImage, imageNames, imageTags)
+		if nodeState == imagePulling {
+			// the zero time of the tasks synced without createdAt is regarded as lastSyncedTime
+			var t time.Time
+			if syncedTime != nil {
+				t = syncedTime.Time
 			}
-			foundTag = true
-			break
+			pullingSyncedTimes = append(pullingSyncedTimes, t)
 		}
-		if !foundTag {
-			pulling = append(pulling, nodeImage.Name)
+		if syncedTime == nil {
+			continue
+		}
+		if syncedTime.After(lastSyncedTime) {
+			lastSyncedTime = syncedTime.Time
+		}
+		if nodeState == imagePullSucceeded && completionTime != nil && !completionTime.Before(syncedTime) {
+			pullDurations = append(pullDurations, completionTime.Sub(syncedTime.Time))
 		}
 	}
 
+	failed = append(failed, unsupported...)
+
 	if job.Spec.CompletionPolicy.Type != appsv1beta1.Never && job.Spec.CompletionPolicy.ActiveDeadlineSeconds != nil && int(newStatus.Desired) != len(succeeded)+len(failed) {
 		if time.Duration(*job.Spec.CompletionPolicy.ActiveDeadlineSeconds)*time.Second <= time.Since(newStatus.StartTime.Time) {
 			newStatus.CompletionTime = &now
@@ -692,23 +820,103 @@ func (r *ReconcileImagePullJob) calculateStatus(job *appsv1beta1.ImagePullJob, n
 			newStatus.Failed = int32(len(failed))
 			newStatus.FailedNodes = failed
 			newStatus.Message = "job exceeds activeDeadlineSeconds"
+			newStatus.Progress = 100
+			newStatus.NodeStates = &appsv1beta1.ImagePullJobNodeStates{Succeeded: newStatus.Succeeded, Failed: newStatus.Failed}
+			if len(job.Spec.Images) > 0 {
+				for i := range imageStatuses {
+					imageStatuses[i].Failed = imageStatuses[i].Desired - imageStatuses[i].Succeeded
+					imageStatuses[i].Active = 0
+				}
+				newStatus.ImageStatuses = imageStatuses
+			}
 			return &newStatus, nil, nil
 		}
 	}
 
 	newStatus.Active = int32(len(pulling))
+	if len(nodeImages) > 0 && len(images) > 0 {
+		newStatus.Progress = int32(totalProgress / int64(len(nodeImages)*len(images)))
+	}
 	newStatus.Succeeded = int32(len(succeeded))
 	newStatus.Failed = int32(len(failed))
 	newStatus.FailedNodes = failed
 	if job.Spec.CompletionPolicy.Type != appsv1beta1.Never && (newStatus.Desired-newStatus.Succeeded-newStatus.Failed) == 0 {
 		newStatus.CompletionTime = &now
 	}
+	newStatus.NodeStates = &appsv1beta1.ImagePullJobNodeStates{
+		Waiting:            int32(len(notSynced)),
+		Pulling:            newStatus.Active,
+		Succeeded:          newStatus.Succeeded,
+		Failed:             newStatus.Failed,
+		AveragePullSeconds: averagePullSeconds(pullDurations),
+	}
+	newStatus.NodeStates.WaitingNodes = getWaitingNodes(notSynced, getParallelism(job), pullingSyncedTimes, lastSyncedTime,
+		time.Duration(newStatus.NodeStates.AveragePullSeconds)*time.Second)
 
 	newStatus.Message = formatStatusMessage(&newStatus)
+	if len(unsupported) > 0 {
+		newStatus.Message = fmt.Sprintf("%s, %d nodes do not support %s", newStatus.Message, len(unsupported), appsv1beta1.NodeFeatureImagePull)
+	}
+	if len(job.Spec.Images) > 0 {
+		newStatus.ImageStatuses = imageStatuses
+	}
 	sort.Strings(newStatus.FailedNodes)
 	return &newStatus, notSynced, nil
 }
 
+// getImagePullState returns the pulling state of the image for the job in the NodeImage, and its pulling progress
+// between 0-100 reported by kruise-daemon.
+func getImagePullState(job *appsv1beta1.ImagePullJob, nodeImage *appsv1beta1.NodeImage, imageName, imageTag string, secrets []appsv1beta1.ReferenceObject) (imagePullState, int32) {
+	var tagVersion int64 = -1
+	if imageSpec, ok := nodeImage.Spec.Images[imageName]; ok {
+		for _, secret := range secrets {
+			if !containsObject(imageSpec.PullSecrets, secret) {
+				return imagePullNotSynced, 0
+			}
+		}
+
+		for _, tagSpec := range imageSpec.Tags {
+			if tagSpec.Tag != imageTag {
+				continue
+			}
+			var foundOwner bool
+			for _, ref := range tagSpec.OwnerReferences {
+				if ref.UID == job.UID {
+					foundOwner = true
+					break
+				}
+			}
+			if !foundOwner {
+				break
+			}
+			tagVersion = tagSpec.Version
+		}
+	}
+
+	if tagVersion < 0 {
+		return imagePullNotSynced, 0
+	}
+
+	imageStatus := nodeImage.Status.ImageStatuses[imageName]
+	for _, tagStatus := range imageStatus.Tags {
+		if tagStatus.Tag != imageTag {
+			continue
+		}
+		if tagStatus.Version != tagVersion {
+			break
+		}
+		switch tagStatus.Phase {
+		case appsv1beta1.ImagePhaseSucceeded:
+			return imagePullSucceeded, 100
+		case appsv1beta1.ImagePhaseFailed:
+			return imagePullFailed, 100
+		default:
+			return imagePulling, tagStatus.Progress
+		}
+	}
+	return imagePulling, 0
+}
+
 func (r *ReconcileImagePullJob) namespaceIsActive(name string) error {
 	namespace := v1.Namespace{}
 	err := r.Get(context.TODO(), types.NamespacedName{Name: name}, &namespace)
@@ -5,18 +5,23 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	k8stesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
 	"github.com/openkruise/kruise/pkg/util"
+	utilimagejob "github.com/openkruise/kruise/pkg/util/imagejob"
 )
 
 func TestReconcileImagePullJob_calculateStatus(t *testing.T) {
@@ -490,6 +495,86 @@ func TestReconcileImagePullJob_calculateStatus(t *testing.T) {
 			expectedNotSynced: []string{"node1"},
 			expectError:       false,
 		},
+		{
+			name: "nodes not supporting image pull failed fast",
+			job: &appsv1beta1.ImagePullJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-job",
+					Namespace: "default",
+					UID:       "job-uid-5",
+				},
+				Spec: appsv1beta1.ImagePullJobSpec{
+					Image: "nginx:1.20",
+				},
+			},
+			nodeImages: []*appsv1beta1.NodeImage{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+					Status: appsv1beta1.NodeImageStatus{
+						Capabilities: &appsv1beta1.NodeCapabilities{
+							Features: []appsv1beta1.NodeFeature{appsv1beta1.NodeFeatureContainerRecreate},
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "node2"},
+					Status: appsv1beta1.NodeImageStatus{
+						Capabilities: &appsv1beta1.NodeCapabilities{
+							Features: []appsv1beta1.NodeFeature{appsv1beta1.NodeFeatureImagePull},
+						},
+					},
+				},
+				{
+					// capabilities not reported by old daemon
+					ObjectMeta: metav1.ObjectMeta{Name: "node3"},
+				},
+			},
+			secrets: []appsv1beta1.ReferenceObject{},
+			expectedStatus: &appsv1beta1.ImagePullJobStatus{
+				Desired:     3,
+				Succeeded:   0,
+				Active:      0,
+				Failed:      1,
+				FailedNodes: []string{"node1"},
+				Message:     "job is running, progress 33.3%, 1 nodes do not support ImagePull",
+			},
+			expectedNotSynced: []string{"node2", "node3"},
+			expectError:       false,
+		},
+		{
+			name: "multiple images in different states",
+			job: &appsv1beta1.ImagePullJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-job",
+					Namespace: "default",
+					UID:       "job-uid-6",
+				},
+				Spec: appsv1beta1.ImagePullJobSpec{
+					Images: []string{"nginx:1.20", "redis:6"},
+				},
+			},
+			nodeImages: []*appsv1beta1.NodeImage{
+				newTestNodeImage("node1", "job-uid-6", map[string]appsv1beta1.ImagePullPhase{"nginx": appsv1beta1.ImagePhaseSucceeded, "redis": appsv1beta1.ImagePhaseSucceeded}),
+				newTestNodeImage("node2", "job-uid-6", map[string]appsv1beta1.ImagePullPhase{"nginx": appsv1beta1.ImagePhaseSucceeded, "redis": appsv1beta1.ImagePhaseFailed}),
+				newTestNodeImage("node3", "job-uid-6", map[string]appsv1beta1.ImagePullPhase{"nginx": appsv1beta1.ImagePhaseSucceeded, "redis": appsv1beta1.ImagePhasePulling}),
+				newTestNodeImage("node4", "job-uid-6", map[string]appsv1beta1.ImagePullPhase{"nginx": appsv1beta1.ImagePhaseSucceeded}),
+			},
+			secrets: []appsv1beta1.ReferenceObject{},
+			expectedStatus: &appsv1beta1.ImagePullJobStatus{
+				Desired:     4,
+				Succeeded:   1,
+				Active:      1,
+				Failed:      1,
+				FailedNodes: []string{"node2"},
+				Message:     "job is running, progress 50.0%",
+				ImageStatuses: []appsv1beta1.ImagePullJobImageStatus{
+					{Image: "nginx:1.20", Desired: 4, Succeeded: 4},
+					{Image: "redis:6", Desired: 4, Active: 1, Succeeded: 1, Failed: 1},
+				},
+			},
+			expectedNotSynced: []string{"node4"},
+			expectError:       false,
+		},
 		{
 			name: "invalid image reference",
 			job: &appsv1beta1.ImagePullJob{
@@ -536,6 +621,7 @@ func TestReconcileImagePullJob_calculateStatus(t *testing.T) {
 			assert.Equal(t, tt.expectedStatus.Failed, status.Failed)
 			assert.Equal(t, tt.expectedStatus.Message, status.Message)
 			assert.ElementsMatch(t, tt.expectedStatus.FailedNodes, status.FailedNodes)
+			assert.Equal(t, tt.expectedStatus.ImageStatuses, status.ImageStatuses)
 
 			// Check not synced nodes
 			assert.ElementsMatch(t, tt.expectedNotSynced, notSynced)
@@ -546,6 +632,77 @@ func TestReconcileImagePullJob_calculateStatus(t *testing.T) {
 	}
 }
 
+// newTestNodeImage returns a NodeImage with the given image phases synced for the job, the tag of each image is the
+// one used in the tests, e.g., nginx:1.20 and redis:6.
+func newTestNodeImage(name string, jobUID types.UID, phases map[string]appsv1beta1.ImagePullPhase) *appsv1beta1.NodeImage {
+	tags := map[string]string{"nginx": "1.20", "redis": "6"}
+	nodeImage := &appsv1beta1.NodeImage{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       appsv1beta1.NodeImageSpec{Images: map[string]appsv1beta1.ImageSpec{}},
+		Status:     appsv1beta1.NodeImageStatus{ImageStatuses: map[string]appsv1beta1.ImageStatus{}},
+	}
+	for imageName, phase := range phases {
+		nodeImage.Spec.Images[imageName] = appsv1beta1.ImageSpec{
+			Tags: []appsv1beta1.ImageTagSpec{{Tag: tags[imageName], Version: 1, OwnerReferences: []v1.ObjectReference{{UID: jobUID}}}},
+		}
+		nodeImage.Status.ImageStatuses[imageName] = appsv1beta1.ImageStatus{
+			Tags: []appsv1beta1.ImageTagStatus{{Tag: tags[imageName], Version: 1, Phase: phase}},
+		}
+	}
+	return nodeImage
+}
+
+func TestSyncNodeImagesWithMultipleImages(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = appsv1beta1.AddToScheme(scheme)
+
+	job := &appsv1beta1.ImagePullJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-job", Namespace: "default", UID: "job-uid"},
+		Spec: appsv1beta1.ImagePullJobSpec{
+			Images:               []string{"nginx:1.20", "redis:6"},
+			ImagePullJobTemplate: appsv1beta1.ImagePullJobTemplate{Parallelism: ptr.To(intstr.FromInt32(3))},
+		},
+	}
+	// node1 has already synced nginx for the job, node2 has nothing, and node3 does not support image pull
+	node1 := newTestNodeImage("node1", "job-uid", map[string]appsv1beta1.ImagePullPhase{"nginx": appsv1beta1.ImagePhaseSucceeded})
+	node2 := &appsv1beta1.NodeImage{ObjectMeta: metav1.ObjectMeta{Name: "node2"}}
+	node3 := &appsv1beta1.NodeImage{
+		ObjectMeta: metav1.ObjectMeta{Name: "node3"},
+		Status: appsv1beta1.NodeImageStatus{
+			Capabilities: &appsv1beta1.NodeCapabilities{Features: []appsv1beta1.NodeFeature{appsv1beta1.NodeFeatureContainerRecreate}},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(node1, node2, node3).Build()
+	r := &ReconcileImagePullJob{Client: fakeClient, clock: k8stesting.NewFakeClock(time.Now())}
+
+	if err := r.syncNodeImages(job, &appsv1beta1.ImagePullJobStatus{}, []string{"node1", "node2", "node3"}, nil); err != nil {
+		t.Fatalf("failed to sync NodeImages: %v", err)
+	}
+	unsupportedNodeImage := &appsv1beta1.NodeImage{}
+	if err := fakeClient.Get(context.TODO(), types.NamespacedName{Name: "node3"}, unsupportedNodeImage); err != nil {
+		t.Fatalf("failed to get NodeImage node3: %v", err)
+	}
+	if len(unsupportedNodeImage.Spec.Images) != 0 {
+		t.Fatalf("expected no image synced into NodeImage not supporting image pull, got %v", util.DumpJSON(unsupportedNodeImage.Spec.Images))
+	}
+	for _, name := range []string{"node1", "node2"} {
+		nodeImage := &appsv1beta1.NodeImage{}
+		if err := fakeClient.Get(context.TODO(), types.NamespacedName{Name: name}, nodeImage); err != nil {
+			t.Fatalf("failed to get NodeImage %s: %v", name, err)
+		}
+		for imageName, tag := range map[string]string{"nginx": "1.20", "redis": "6"} {
+			imageSpec, ok := nodeImage.Spec.Images[imageName]
+			if !ok || len(imageSpec.Tags) != 1 || imageSpec.Tags[0].Tag != tag {
+				t.Fatalf("expected %s:%s synced into NodeImage %s, got %v", imageName, tag, name, util.DumpJSON(nodeImage.Spec.Images))
+			}
+			if len(imageSpec.Tags[0].OwnerReferences) != 1 || imageSpec.Tags[0].OwnerReferences[0].UID != job.UID {
+				t.Fatalf("expected %s in NodeImage %s owned by job only, got %v", imageName, name, imageSpec.Tags[0].OwnerReferences)
+			}
+		}
+	}
+}
+
 func TestReconcileImagePullJob_calculateStatus_CompletionPolicy(t *testing.T) {
 	fakeClock := k8stesting.NewFakeClock(time.Now())
 	now := metav1.NewTime(fakeClock.Now())
@@ -1996,3 +2153,300 @@ func TestClaimImagePullJobSecrets_UpdateExistingSecret(t *testing.T) {
 	assert.Contains(t, updatedSecret.Annotations[SecretAnnotationReferenceJobs], "default/test-job")
 	assert.Contains(t, updatedSecret.Annotations[SecretAnnotationReferenceJobs], "default/other-job")
 }
+
+func TestSyncJobPullSecrets_ServiceAccount(t *testing.T) {
+	job := &appsv1beta1.ImagePullJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "test-job",
+		},
+		Spec: appsv1beta1.ImagePullJobSpec{
+			ImagePullJobTemplate: appsv1beta1.ImagePullJobTemplate{
+				PullSecrets:        []string{"secret-a"},
+				ServiceAccountName: "image-puller",
+			},
+		},
+	}
+
+	newSecret := func(name string) *v1.Secret {
+		return &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Data:       map[string][]byte{"username": []byte(name)},
+		}
+	}
+	sa := &v1.ServiceAccount{
+		ObjectMeta:       metav1.ObjectMeta{Name: "image-puller", Namespace: "default"},
+		ImagePullSecrets: []v1.LocalObjectReference{{Name: "secret-a"}, {Name: "secret-b"}},
+	}
+	existingObjects := []runtime.Object{
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: util.GetKruiseDaemonConfigNamespace()}},
+		newSecret("secret-a"),
+		newSecret("secret-b"),
+		newSecret("secret-c"),
+		sa,
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(existingObjects...).Build()
+	r := &ReconcileImagePullJob{
+		Client: fakeClient,
+		scheme: scheme,
+		generateRandomStringFunc: func() string {
+			return "123456"
+		},
+	}
+
+	result, err := r.syncJobPullSecrets(job)
+	assert.NoError(t, err)
+	var names []string
+	for _, ref := range result {
+		names = append(names, ref.Name)
+	}
+	assert.ElementsMatch(t, []string{"secret-a-123456", "secret-b-123456"}, names)
+
+	// the ServiceAccount is resolved again on each sync
+	sa = &v1.ServiceAccount{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "image-puller"}, sa))
+	sa.ImagePullSecrets = append(sa.ImagePullSecrets, v1.LocalObjectReference{Name: "secret-c"})
+	assert.NoError(t, fakeClient.Update(context.TODO(), sa))
+
+	result, err = r.syncJobPullSecrets(job)
+	assert.NoError(t, err)
+	names = nil
+	for _, ref := range result {
+		names = append(names, ref.Name)
+	}
+	assert.ElementsMatch(t, []string{"secret-a-123456", "secret-b-123456", "secret-c-123456"}, names)
+}
+
+func TestReconcileImagePullJob_ServiceAccountNotFound(t *testing.T) {
+	job := &appsv1beta1.ImagePullJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "sa-not-found-job",
+			UID:       "sa-not-found-job-uid",
+		},
+		Spec: appsv1beta1.ImagePullJobSpec{
+			Image: "nginx:latest",
+			ImagePullJobTemplate: appsv1beta1.ImagePullJobTemplate{
+				ServiceAccountName: "image-puller",
+				CompletionPolicy:   appsv1beta1.CompletionPolicy{Type: appsv1beta1.Always},
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(job).WithStatusSubresource(job).Build()
+	r := &ReconcileImagePullJob{
+		Client: fakeClient,
+		scheme: scheme,
+		clock:  k8stesting.NewFakeClock(time.Now()),
+	}
+
+	names, err := r.getPullSecretNames(job)
+	assert.NoError(t, err)
+	assert.Empty(t, names)
+
+	result, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "sa-not-found-job"}})
+	assert.NoError(t, err)
+	assert.Equal(t, serviceAccountNotFoundRequeueTime, result.RequeueAfter)
+
+	got := &appsv1beta1.ImagePullJob{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "sa-not-found-job"}, got))
+	assert.Equal(t, "job is waiting, serviceAccount image-puller not found", got.Status.Message)
+}
+
+func TestReconcileImagePullJob_NodeFilter(t *testing.T) {
+	newNode := func(name, arch string, unschedulable bool) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1.NodeSpec{Unschedulable: unschedulable},
+			Status:     v1.NodeStatus{NodeInfo: v1.NodeSystemInfo{Architecture: arch, OperatingSystem: "linux"}},
+		}
+	}
+	job := &appsv1beta1.ImagePullJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "node-filter-job",
+			UID:       "node-filter-job-uid",
+		},
+		Spec: appsv1beta1.ImagePullJobSpec{
+			Image: "nginx:latest",
+			ImagePullJobTemplate: appsv1beta1.ImagePullJobTemplate{
+				Selector: &appsv1beta1.ImagePullJobNodeSelector{
+					MatchFields: []v1.NodeSelectorRequirement{
+						{Key: "status.nodeInfo.architecture", Operator: v1.NodeSelectorOpIn, Values: []string{"arm64"}},
+					},
+				},
+				SkipUnschedulableNodes: true,
+				CompletionPolicy:       appsv1beta1.CompletionPolicy{Type: appsv1beta1.Always},
+			},
+		},
+	}
+	objs := []client.Object{
+		job,
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: util.GetKruiseDaemonConfigNamespace()}},
+		newNode("arm-1", "arm64", false),
+		newNode("arm-2", "arm64", false),
+		newNode("arm-cordoned", "arm64", true),
+		newNode("amd-1", "amd64", false),
+	}
+	for _, name := range []string{"arm-1", "arm-2", "arm-cordoned", "amd-1", "node-deleted"} {
+		objs = append(objs, &appsv1beta1.NodeImage{ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name)}})
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithStatusSubresource(job).Build()
+	r := &ReconcileImagePullJob{
+		Client: fakeClient,
+		scheme: scheme,
+		clock:  k8stesting.NewFakeClock(time.Now()),
+	}
+
+	_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "node-filter-job"}})
+	assert.NoError(t, err)
+
+	got := &appsv1beta1.ImagePullJob{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "node-filter-job"}, got))
+	assert.Equal(t, int32(2), got.Status.Desired)
+
+	// nodes uncordoned are selected again
+	cordoned := &v1.Node{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: "arm-cordoned"}, cordoned))
+	cordoned.Spec.Unschedulable = false
+	assert.NoError(t, fakeClient.Update(context.TODO(), cordoned))
+	job.Spec.SkipUnschedulableNodes = false
+	job.Spec.Selector.MatchFields[0].Operator = v1.NodeSelectorOpNotIn
+	nodeImages, err := utilimagejob.GetNodeImagesForJob(fakeClient, job)
+	assert.NoError(t, err)
+	assert.Len(t, nodeImages, 1)
+	assert.Equal(t, "amd-1", nodeImages[0].Name)
+}
+
+func TestReconcileImagePullJob_NodeStates(t *testing.T) {
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	at := func(seconds int) *metav1.Time {
+		t := metav1.NewTime(start.Add(time.Duration(seconds) * time.Second))
+		return &t
+	}
+	job := &appsv1beta1.ImagePullJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "node-states-job",
+			UID:       "node-states-job-uid",
+		},
+		Spec: appsv1beta1.ImagePullJobSpec{
+			Image: "nginx:1.20",
+			ImagePullJobTemplate: appsv1beta1.ImagePullJobTemplate{
+				Parallelism:      ptr.To(intstr.FromInt32(2)),
+				CompletionPolicy: appsv1beta1.CompletionPolicy{Type: appsv1beta1.Always},
+			},
+		},
+		Status: appsv1beta1.ImagePullJobStatus{StartTime: at(0)},
+	}
+	newNodeImage := func(name string, phase appsv1beta1.ImagePullPhase, createdAt, completionTime *metav1.Time) *appsv1beta1.NodeImage {
+		nodeImage := newTestNodeImage(name, job.UID, map[string]appsv1beta1.ImagePullPhase{"nginx": phase})
+		nodeImage.UID = types.UID(name)
+		imageSpec := nodeImage.Spec.Images["nginx"]
+		imageSpec.Tags[0].CreatedAt = createdAt
+		nodeImage.Spec.Images["nginx"] = imageSpec
+		imageStatus := nodeImage.Status.ImageStatuses["nginx"]
+		imageStatus.Tags[0].CompletionTime = completionTime
+		nodeImage.Status.ImageStatuses["nginx"] = imageStatus
+		return nodeImage
+	}
+	objs := []client.Object{
+		job,
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: util.GetKruiseDaemonConfigNamespace()}},
+		newNodeImage("node-1", appsv1beta1.ImagePhaseSucceeded, at(0), at(90)),
+		newNodeImage("node-2", appsv1beta1.ImagePhaseFailed, at(30), at(100)),
+		newNodeImage("node-3", appsv1beta1.ImagePhasePulling, at(120), nil),
+	}
+	for _, name := range []string{"node-4", "node-5", "node-6"} {
+		objs = append(objs, &appsv1beta1.NodeImage{ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name)}})
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithStatusSubresource(job).Build()
+	r := &ReconcileImagePullJob{
+		Client: fakeClient,
+		scheme: scheme,
+		clock:  k8stesting.NewFakeClock(time.Now()),
+	}
+	defer ImagePullJobActivePullsMetrics.DeleteLabelValues(job.Namespace, job.Name)
+
+	_, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: job.Namespace, Name: job.Name}})
+	assert.NoError(t, err)
+
+	got := &appsv1beta1.ImagePullJob{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: job.Namespace, Name: job.Name}, got))
+	assert.Equal(t, int32(6), got.Status.Desired)
+	// the free slot of parallelism is filled by node-4 at lastSyncedTime, and the other two wait for the slots
+	// to be freed average pull duration after node-3 and node-4 were synced
+	expected := &appsv1beta1.ImagePullJobNodeStates{
+		Waiting:            3,
+		Pulling:            1,
+		Succeeded:          1,
+		Failed:             1,
+		AveragePullSeconds: 90,
+		WaitingNodes: []appsv1beta1.ImagePullJobWaitingNode{
+			{Name: "node-4", QueueAhead: 0, EstimatedStartTime: at(120)},
+			{Name: "node-5", QueueAhead: 1, EstimatedStartTime: at(210)},
+			{Name: "node-6", QueueAhead: 2, EstimatedStartTime: at(210)},
+		},
+	}
+	assert.Equal(t, util.DumpJSON(expected), util.DumpJSON(got.Status.NodeStates))
+	assert.Equal(t, got.Status.Desired, got.Status.NodeStates.Waiting+got.Status.NodeStates.Pulling+
+		got.Status.NodeStates.Succeeded+got.Status.NodeStates.Failed)
+	assert.Equal(t, float64(1), testutil.ToFloat64(ImagePullJobActivePullsMetrics.WithLabelValues(job.Namespace, job.Name)))
+
+	// only node-4 is synced to fill the parallelism
+	for name, synced := range map[string]bool{"node-4": true, "node-5": false, "node-6": false} {
+		nodeImage := &appsv1beta1.NodeImage{}
+		assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: name}, nodeImage))
+		_, ok := nodeImage.Spec.Images["nginx"]
+		assert.Equal(t, synced, ok, name)
+	}
+
+	// node-4 starts pulling, and node-5 is at the head of the queue
+	resourceVersionExpectations.Delete(got)
+	_, err = r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: job.Namespace, Name: job.Name}})
+	assert.NoError(t, err)
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: job.Namespace, Name: job.Name}, got))
+	assert.Equal(t, int32(2), got.Status.NodeStates.Waiting)
+	assert.Equal(t, int32(2), got.Status.NodeStates.Pulling)
+	assert.Equal(t, int32(1), got.Status.NodeStates.Succeeded)
+	assert.Equal(t, int32(1), got.Status.NodeStates.Failed)
+	assert.Len(t, got.Status.NodeStates.WaitingNodes, 2)
+	assert.Equal(t, "node-5", got.Status.NodeStates.WaitingNodes[0].Name)
+	assert.Equal(t, float64(2), testutil.ToFloat64(ImagePullJobActivePullsMetrics.WithLabelValues(job.Namespace, job.Name)))
+}
+
+func TestReconcileImagePullJob_calculateStatus_Progress(t *testing.T) {
+	job := &appsv1beta1.ImagePullJob{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "progress-job", UID: "progress-job-uid"},
+		Spec:       appsv1beta1.ImagePullJobSpec{Images: []string{"nginx:1.20", "redis:6"}},
+	}
+	withProgress := func(nodeImage *appsv1beta1.NodeImage, imageName string, progress int32) *appsv1beta1.NodeImage {
+		imageStatus := nodeImage.Status.ImageStatuses[imageName]
+		imageStatus.Tags[0].Progress = progress
+		nodeImage.Status.ImageStatuses[imageName] = imageStatus
+		return nodeImage
+	}
+	nodeImages := []*appsv1beta1.NodeImage{
+		newTestNodeImage("node1", job.UID, map[string]appsv1beta1.ImagePullPhase{
+			"nginx": appsv1beta1.ImagePhaseSucceeded,
+			"redis": appsv1beta1.ImagePhaseFailed,
+		}),
+		withProgress(newTestNodeImage("node2", job.UID, map[string]appsv1beta1.ImagePullPhase{
+			"nginx": appsv1beta1.ImagePhaseSucceeded,
+			"redis": appsv1beta1.ImagePhasePulling,
+		}), "redis", 40),
+		newTestNodeImage("node3", job.UID, map[string]appsv1beta1.ImagePullPhase{
+			"nginx": appsv1beta1.ImagePhaseSucceeded,
+		}),
+		{ObjectMeta: metav1.ObjectMeta{Name: "node4"}},
+	}
+	r := &ReconcileImagePullJob{clock: k8stesting.NewFakeClock(time.Now())}
+	status, _, err := r.calculateStatus(job, nodeImages, nil)
+	assert.NoError(t, err)
+	// (100 + 100) + (100 + 40) + (100 + 0) + (0 + 0) over 8 images on 4 nodes
+	assert.Equal(t, int32(55), status.Progress)
+	assert.Equal(t, int32(1), status.Active)
+}
@@ -35,6 +35,7 @@ import (
 	kruiseutil "github.com/openkruise/kruise/pkg/util"
 	utilclient "github.com/openkruise/kruise/pkg/util/client"
 	"github.com/openkruise/kruise/pkg/util/expectations"
+	"github.com/openkruise/kruise/pkg/util/fieldindex"
 	utilimagejob "github.com/openkruise/kruise/pkg/util/imagejob"
 )
 
@@ -111,13 +112,16 @@ func (e *nodeImageEventHandler) handleUpdate(nodeImage, oldNodeImage *appsv1beta
 	}
 	diffSet := diffJobs(newJobs, oldJobs)
 	for _, j := range newJobs {
-		imageName, _, err := daemonutil.NormalizeImageRefToNameTag(j.Spec.Image)
-		if err != nil {
-			klog.InfoS("Invalid image in job", "image", j.Spec.Image, "imagePullJob", klog.KObj(j))
-			continue
-		}
-		if changedImages.Has(imageName) {
-			diffSet[types.NamespacedName{Namespace: j.Namespace, Name: j.Name}] = struct{}{}
+		for _, image := range getJobImages(j) {
+			imageName, _, err := daemonutil.NormalizeImageRefToNameTag(image)
+			if err != nil {
+				klog.InfoS("Invalid image in job", "image", image, "imagePullJob", klog.KObj(j))
+				continue
+			}
+			if changedImages.Has(imageName) {
+				diffSet[types.NamespacedName{Namespace: j.Namespace, Name: j.Name}] = struct{}{}
+				break
+			}
 		}
 	}
 	for name := range diffSet {
@@ -289,6 +293,43 @@ func jobContainsSecret(job *appsv1beta1.ImagePullJob, secretName string) bool {
 	return false
 }
 
+type nodeEventHandler struct {
+	client.Reader
+}
+
+var _ handler.TypedEventHandler[*v1.Node, reconcile.Request] = &nodeEventHandler{}
+
+func (e *nodeEventHandler) Create(ctx context.Context, evt event.TypedCreateEvent[*v1.Node], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+}
+
+func (e *nodeEventHandler) Update(ctx context.Context, evt event.TypedUpdateEvent[*v1.Node], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	obj := evt.ObjectNew
+	oldObj := evt.ObjectOld
+	// only the fields used to filter nodes of jobs matter, NodeImage events cover the others
+	if obj.Spec.Unschedulable == oldObj.Spec.Unschedulable &&
+		obj.Status.NodeInfo.Architecture == oldObj.Status.NodeInfo.Architecture &&
+		obj.Status.NodeInfo.OperatingSystem == oldObj.Status.NodeInfo.OperatingSystem {
+		return
+	}
+	jobList := &appsv1beta1.ImagePullJobList{}
+	if err := e.List(context.TODO(), jobList, client.MatchingFields{fieldindex.IndexNameForIsActive: "true"}, utilclient.DisableDeepCopy); err != nil {
+		klog.ErrorS(err, "Failed to get jobs for Node", "node", obj.Name)
+		return
+	}
+	for i := range jobList.Items {
+		job := &jobList.Items[i]
+		if utilimagejob.HasNodeFilter(job) {
+			q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Namespace: job.Namespace, Name: job.Name}})
+		}
+	}
+}
+
+func (e *nodeEventHandler) Delete(ctx context.Context, evt event.TypedDeleteEvent[*v1.Node], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+}
+
+func (e *nodeEventHandler) Generic(ctx context.Context, evt event.TypedGenericEvent[*v1.Node], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+}
+
 func diffJobs(newJobs, oldJobs []*appsv1beta1.ImagePullJob) set {
 	setNew := make(set, len(newJobs))
 	setOld := make(set, len(oldJobs))
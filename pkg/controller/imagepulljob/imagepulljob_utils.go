@@ -19,13 +19,19 @@ package imagepulljob
 import (
 	"encoding/hex"
 	"fmt"
+	"math"
 	"math/rand"
+	"sort"
 	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/integer"
 	utilpointer "k8s.io/utils/pointer"
 
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
@@ -35,6 +41,19 @@ import (
 const (
 	defaultTTLSecondsForNever            = int32(24 * 3600)
 	defaultActiveDeadlineSecondsForNever = int64(1800)
+
+	// maxWaitingNodesInStatus bounds the waiting nodes reported in status.nodeStates.waitingNodes.
+	maxWaitingNodesInStatus = 10
+)
+
+// imagePullState is the pulling state of an image on a node, ordered by progress
+type imagePullState int
+
+const (
+	imagePullNotSynced imagePullState = iota
+	imagePulling
+	imagePullFailed
+	imagePullSucceeded
 )
 
 func getTTLSecondsForAlways(job *appsv1beta1.ImagePullJob) *int32 {
@@ -73,6 +92,7 @@ func getImagePullPolicy(job *appsv1beta1.ImagePullJob) *appsv1beta1.ImageTagPull
 	if job.Spec.PullPolicy != nil {
 		pullPolicy.BackoffLimit = job.Spec.PullPolicy.BackoffLimit
 		pullPolicy.TimeoutSeconds = job.Spec.PullPolicy.TimeoutSeconds
+		pullPolicy.MaxConcurrentPulls = job.Spec.PullPolicy.MaxConcurrentPullsPerNode
 	}
 	if job.Spec.CompletionPolicy.Type == appsv1beta1.Never {
 		pullPolicy.TTLSecondsAfterFinished = getTTLSecondsForNever()
@@ -101,6 +121,114 @@ func getActiveDeadlineSecondsForNever(job *appsv1beta1.ImagePullJob) *int64 {
 	return utilpointer.Int64(ret)
 }
 
+// getJobImages returns the images to be pulled by the job, either spec.image or spec.images.
+func getJobImages(job *appsv1beta1.ImagePullJob) []string {
+	if job.Spec.Image != "" {
+		return []string{job.Spec.Image}
+	}
+	return job.Spec.Images
+}
+
+// getParallelism returns the max number of nodes pulling the images at the same time.
+func getParallelism(job *appsv1beta1.ImagePullJob) int {
+	if job.Spec.Parallelism != nil {
+		return job.Spec.Parallelism.IntValue()
+	}
+	return defaultParallelism
+}
+
+// getImagePullTimes returns the latest time when the pulling tasks of the job were synced into the NodeImage,
+// and the latest time when they were completed by the daemon.
+func getImagePullTimes(job *appsv1beta1.ImagePullJob, nodeImage *appsv1beta1.NodeImage, imageNames, imageTags []string) (syncedTime, completionTime *metav1.Time) {
+	for i := range imageNames {
+		var tagVersion int64 = -1
+		for _, tagSpec := range nodeImage.Spec.Images[imageNames[i]].Tags {
+			if tagSpec.Tag != imageTags[i] || !containsOwnerUID(tagSpec.OwnerReferences, job.UID) {
+				continue
+			}
+			tagVersion = tagSpec.Version
+			if tagSpec.CreatedAt != nil && (syncedTime == nil || syncedTime.Before(tagSpec.CreatedAt)) {
+				syncedTime = tagSpec.CreatedAt
+			}
+		}
+		if tagVersion < 0 {
+			continue
+		}
+		for _, tagStatus := range nodeImage.Status.ImageStatuses[imageNames[i]].Tags {
+			if tagStatus.Tag != imageTags[i] || tagStatus.Version != tagVersion {
+				continue
+			}
+			if tagStatus.CompletionTime != nil && (completionTime == nil || completionTime.Before(tagStatus.CompletionTime)) {
+				completionTime = tagStatus.CompletionTime
+			}
+		}
+	}
+	return syncedTime, completionTime
+}
+
+func containsOwnerUID(refs []v1.ObjectReference, uid types.UID) bool {
+	for _, ref := range refs {
+		if ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
+
+func averagePullSeconds(durations []time.Duration) int32 {
+	if len(durations) == 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	return int32(math.Round((total / time.Duration(len(durations))).Seconds()))
+}
+
+// getWaitingNodes returns the queue positions of the first waiting nodes. The start time of each node is estimated
+// by simulating that the pulling slots are freed averagePullDuration after the pulling tasks were synced, and the slots
+// free now are regarded as freed at lastSyncedTime, so that the estimation is stable across reconciles.
+func getWaitingNodes(waiting []string, parallelism int, pullingSyncedTimes []time.Time, lastSyncedTime time.Time, averagePullDuration time.Duration) []appsv1beta1.ImagePullJobWaitingNode {
+	if len(waiting) == 0 {
+		return nil
+	}
+	waitingNodes := make([]appsv1beta1.ImagePullJobWaitingNode, integer.IntMin(len(waiting), maxWaitingNodesInStatus))
+	for i := range waitingNodes {
+		waitingNodes[i] = appsv1beta1.ImagePullJobWaitingNode{Name: waiting[i], QueueAhead: int32(i)}
+	}
+	if averagePullDuration <= 0 || parallelism <= 0 {
+		return waitingNodes
+	}
+
+	freeTimes := make([]time.Time, 0, len(pullingSyncedTimes)+len(waitingNodes))
+	for _, t := range pullingSyncedTimes {
+		if t.IsZero() {
+			t = lastSyncedTime
+		}
+		freeTimes = append(freeTimes, t.Add(averagePullDuration))
+	}
+	sort.Slice(freeTimes, func(i, j int) bool { return freeTimes[i].Before(freeTimes[j]) })
+	startTime := lastSyncedTime
+	for i := range waitingNodes {
+		for len(freeTimes) >= parallelism {
+			if freeTimes[0].After(startTime) {
+				startTime = freeTimes[0]
+			}
+			freeTimes = freeTimes[1:]
+		}
+		estimatedStartTime := metav1.NewTime(startTime)
+		waitingNodes[i].EstimatedStartTime = &estimatedStartTime
+
+		freeTime := startTime.Add(averagePullDuration)
+		idx := sort.Search(len(freeTimes), func(j int) bool { return freeTimes[j].After(freeTime) })
+		freeTimes = append(freeTimes, time.Time{})
+		copy(freeTimes[idx+1:], freeTimes[idx:])
+		freeTimes[idx] = freeTime
+	}
+	return waitingNodes
+}
+
 func containsObject(slice []appsv1beta1.ReferenceObject, obj appsv1beta1.ReferenceObject) bool {
 	for _, o := range slice {
 		if o.Namespace == obj.Namespace && o.Name == obj.Name {
@@ -318,6 +318,16 @@ func (r *ReconcileNodeImage) doUpdateNodeImage(nodeImage *appsv1beta1.NodeImage,
 					klog.InfoS("When check owners for image in NodeImage, found job UID not equal", "imageName", fullName, "nodeImageName", name, "job", util.DumpJSON(ref), "jobUID", job.UID)
 					continue
 				}
+				// If the job has finished longer than TTL, it no longer owns this tag even if it has not been deleted,
+				// otherwise the tag will be kept forever when the daemon never reports its status.
+				if job.Status.CompletionTime != nil && tagSpec.PullPolicy != nil && tagSpec.PullPolicy.TTLSecondsAfterFinished != nil {
+					leftTime := time.Duration(*tagSpec.PullPolicy.TTLSecondsAfterFinished)*time.Second - time.Since(job.Status.CompletionTime.Time)
+					if leftTime <= 0 {
+						klog.InfoS("When check owners for image in NodeImage, found job finished over TTL", "imageName", fullName, "nodeImageName", name, "job", util.DumpJSON(ref), "completionTime", job.Status.CompletionTime)
+						continue
+					}
+					wait.UpdateWithMsg(leftTime, "[spec]image %s wait TTL (%v)s since job %s/%s completed", fullName, *tagSpec.PullPolicy.TTLSecondsAfterFinished, ref.Namespace, ref.Name)
+				}
 				activeRefs = append(activeRefs, ref)
 			}
 			if len(activeRefs) != len(tagSpec.OwnerReferences) {
@@ -439,6 +439,65 @@ func TestDoUpdateNodeImage(t *testing.T) {
 			wantWaitNotNil: true,
 			wantTagCounts:  map[string]int{"nginx": 1}, // Tag preserved as TTL not exceeded
 		},
+		{
+			name: "job finished over TTL without tag status should remove tag",
+			args: args{
+				nodeImage: func() *appsv1beta1.NodeImage {
+					ni := baseNodeImage.DeepCopy()
+					ni.Spec.Images["nginx"].Tags[0].PullPolicy = &appsv1beta1.ImageTagPullPolicy{
+						TTLSecondsAfterFinished: int32Ptr(60),
+					}
+					return ni
+				}(),
+				node: baseNode.DeepCopy(),
+			},
+			objs: []client.Object{
+				&appsv1beta1.ImagePullJob{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "job1",
+						Namespace: "default",
+						UID:       "uid1",
+					},
+					Status: appsv1beta1.ImagePullJobStatus{
+						CompletionTime: &metav1.Time{Time: time.Now().Add(-61 * time.Second)},
+					},
+				},
+			},
+			wantModified: true,
+			wantMessages: []string{
+				"image nginx:latest owners is cleared",
+				"no longer has nginx image spec",
+			},
+			wantTagCounts: map[string]int{},
+		},
+		{
+			name: "job finished within TTL without tag status should set wait duration",
+			args: args{
+				nodeImage: func() *appsv1beta1.NodeImage {
+					ni := baseNodeImage.DeepCopy()
+					ni.Spec.Images["nginx"].Tags[0].PullPolicy = &appsv1beta1.ImageTagPullPolicy{
+						TTLSecondsAfterFinished: int32Ptr(60),
+					}
+					return ni
+				}(),
+				node: baseNode.DeepCopy(),
+			},
+			objs: []client.Object{
+				&appsv1beta1.ImagePullJob{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "job1",
+						Namespace: "default",
+						UID:       "uid1",
+					},
+					Status: appsv1beta1.ImagePullJobStatus{
+						CompletionTime: &metav1.Time{Time: time.Now().Add(-30 * time.Second)},
+					},
+				},
+			},
+			wantModified:   false,
+			wantWaitNotNil: true,
+			wantTagCounts:  map[string]int{"nginx": 1},
+		},
 		{
 			name: "multiple images scenario",
 			args: args{
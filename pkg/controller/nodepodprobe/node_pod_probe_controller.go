@@ -303,7 +303,7 @@ func (r *ReconcileNodePodProbe) updatePodProbeStatus(pod *corev1.Pod, status app
 				oppositePolicy = &policy[j]
 			}
 		}
-		if oppositePolicy != nil {
+		if oppositePolicy != nil && oppositePolicy.ShouldRemoveOnOpposite() {
 			for k := range oppositePolicy.Labels {
 				probeMetadata.Labels[k] = nil
 			}
@@ -20,14 +20,17 @@ import (
 	"context"
 	"flag"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
@@ -62,8 +65,9 @@ var (
 	// kubernetes
 	KindSts = appsv1.SchemeGroupVersion.WithKind("StatefulSet")
 	// kruise
-	KruiseKindSts = appsv1beta1.SchemeGroupVersion.WithKind("StatefulSet")
-	KruiseKindPps = appsv1alpha1.SchemeGroupVersion.WithKind("PersistentPodState")
+	KruiseKindSts      = appsv1beta1.SchemeGroupVersion.WithKind("StatefulSet")
+	KruiseKindCloneSet = appsv1beta1.SchemeGroupVersion.WithKind("CloneSet")
+	KruiseKindPps      = appsv1alpha1.SchemeGroupVersion.WithKind("PersistentPodState")
 	// AutoGeneratePersistentPodStatePrefix auto generate PersistentPodState crd
 	AutoGeneratePersistentPodStatePrefix = "generate#"
 )
@@ -122,6 +126,11 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// watch for changes to CloneSet
+	if err = c.Watch(source.Kind(mgr.GetCache(), &appsv1beta1.CloneSet{}, &enqueueRequestForCloneSet{reader: mgr.GetClient()})); err != nil {
+		return err
+	}
+
 	whiteList, err := configuration.GetPPSWatchCustomWorkloadWhiteList(mgr.GetClient())
 	if err != nil {
 		return err
@@ -146,6 +155,9 @@ type innerStatefulset struct {
 	// kruise statefulset filed
 	ReserveOrdinals   sets.Set[int]
 	DeletionTimestamp *metav1.Time
+	// cloneset field, pods of CloneSet are identified by instance id rather than ordinal
+	CloneSetName string
+	InstanceIDs  sets.Set[string]
 }
 
 // ReconcilePersistentPodState reconciles a PersistentPodState object
@@ -163,6 +175,8 @@ type ReconcilePersistentPodState struct {
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=persistentpodstates/finalizers,verbs=update
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps,resources=statefulsets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=clonesets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch
 
 // Reconcile reads that state of the cluster for a PersistentPodState object and makes changes based on the state read
 // and what is in the PersistentPodState.Spec
@@ -212,6 +226,9 @@ func (r *ReconcilePersistentPodState) Reconcile(_ context.Context, req ctrl.Requ
 	for _, item := range persistentPodState.Spec.PreferredPersistentTopology {
 		nodeTopologyKeys.Insert(item.Preference.NodeTopologyKeys...)
 	}
+	for _, item := range persistentPodState.Spec.PersistentTopologies {
+		nodeTopologyKeys.Insert(item.NodeTopologyKeys...)
+	}
 
 	annotationKeys := sets.NewString()
 	for _, item := range persistentPodState.Spec.PersistentPodAnnotations {
@@ -252,6 +269,13 @@ func (r *ReconcilePersistentPodState) Reconcile(_ context.Context, req ctrl.Requ
 	// scale down statefulSet scenario
 	if persistentPodState.Spec.PersistentPodStateRetentionPolicy != appsv1alpha1.PersistentPodStateRetentionPolicyWhenDeleted {
 		for podName := range newStatus.PodStates {
+			// the pod recreated for update reuses the instance id of CloneSet, so only delete the retired ones
+			if innerSts.CloneSetName != "" {
+				if isCloneSetInstanceRetired(podName, innerSts) {
+					delete(newStatus.PodStates, podName)
+				}
+				continue
+			}
 			index, err := parseStsPodIndex(podName)
 			if err != nil {
 				klog.ErrorS(err, "Failed to parse PersistentPodState podName", "persistentPodState", klog.KObj(persistentPodState), "podName", podName)
@@ -267,7 +291,80 @@ func (r *ReconcilePersistentPodState) Reconcile(_ context.Context, req ctrl.Requ
 			}
 		}
 	}
-	return ctrl.Result{}, r.updatePersistentPodStateStatus(persistentPodState, *newStatus)
+
+	// bound the states of the deleted pods by retention
+	requeueAfter, err := r.prunePodStates(persistentPodState, pods, newStatus, time.Now())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, r.updatePersistentPodStateStatus(persistentPodState, *newStatus)
+}
+
+// prunePodStates records the time when the pods of the states are found deleted, and prunes the states of the pods
+// deleted longer than ttlSecondsAfterDeleted, or beyond maxEntries with the earliest deleted first.
+// The states of the existing pods, including the terminating ones, are never pruned.
+// It returns the duration after which the next state expires, or zero if there is none.
+func (r *ReconcilePersistentPodState) prunePodStates(pps *appsv1alpha1.PersistentPodState, pods map[string]*corev1.Pod,
+	status *appsv1alpha1.PersistentPodStateStatus, now time.Time) (time.Duration, error) {
+	retention := pps.Spec.PersistentPodStateRetention
+	var deleted []string
+	for podName, podState := range status.PodStates {
+		exists := pods[podName] != nil
+		if !exists && retention != nil {
+			// the inactive pods are not listed in pods
+			pod := &corev1.Pod{}
+			err := r.Client.Get(context.TODO(), client.ObjectKey{Namespace: pps.Namespace, Name: podName}, pod)
+			if err != nil && !errors.IsNotFound(err) {
+				return 0, err
+			}
+			exists = err == nil
+		}
+		if exists || retention == nil {
+			if podState.DeletedTime != nil {
+				podState.DeletedTime = nil
+				status.PodStates[podName] = podState
+			}
+			continue
+		}
+		if podState.DeletedTime == nil {
+			podState.DeletedTime = &metav1.Time{Time: now}
+			status.PodStates[podName] = podState
+		}
+		deleted = append(deleted, podName)
+	}
+	if len(deleted) == 0 {
+		return 0, nil
+	}
+	sort.Slice(deleted, func(i, j int) bool {
+		ti, tj := status.PodStates[deleted[i]].DeletedTime, status.PodStates[deleted[j]].DeletedTime
+		if !ti.Equal(tj) {
+			return ti.Before(tj)
+		}
+		return deleted[i] < deleted[j]
+	})
+
+	var pruned int
+	if retention.MaxEntries != nil {
+		for ; pruned < len(deleted) && len(status.PodStates) > int(*retention.MaxEntries); pruned++ {
+			delete(status.PodStates, deleted[pruned])
+		}
+	}
+	var requeueAfter time.Duration
+	if retention.TTLSecondsAfterDeleted != nil {
+		ttl := time.Duration(*retention.TTLSecondsAfterDeleted) * time.Second
+		for ; pruned < len(deleted); pruned++ {
+			if expireAt := status.PodStates[deleted[pruned]].DeletedTime.Add(ttl); expireAt.After(now) {
+				requeueAfter = expireAt.Sub(now)
+				break
+			}
+			delete(status.PodStates, deleted[pruned])
+		}
+	}
+	if pruned > 0 {
+		klog.V(3).InfoS("Pruned pod states of deleted pods for PersistentPodState", "persistentPodState", klog.KObj(pps), "count", pruned)
+		status.PrunedPodStates += int64(pruned)
+	}
+	return requeueAfter, nil
 }
 
 func (r *ReconcilePersistentPodState) updatePersistentPodStateStatus(pps *appsv1alpha1.PersistentPodState, newStatus appsv1alpha1.PersistentPodStateStatus) error {
@@ -321,9 +418,57 @@ func (r *ReconcilePersistentPodState) getPodsAndStatefulset(persistentPodState *
 		pod := pods[i]
 		matchedPods[pod.Name] = pod
 	}
+
+	if isCloneSetRef(ref) {
+		inner.CloneSetName = workload.Name
+		if inner.InstanceIDs, err = r.getCloneSetInstanceIDs(persistentPodState.Namespace, workload, pods); err != nil {
+			klog.ErrorS(err, "Failed to get instance ids of CloneSet", "persistentPodState", klog.KObj(persistentPodState), "cloneSetName", ref.Name)
+			return nil, nil, err
+		}
+	}
 	return matchedPods, inner, nil
 }
 
+func isCloneSetRef(ref appsv1alpha1.TargetReference) bool {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return false
+	}
+	return gv.Group == KruiseKindCloneSet.Group && ref.Kind == KruiseKindCloneSet.Kind
+}
+
+// getCloneSetInstanceIDs returns the instance ids of CloneSet that are in use, by pods or by the pvcs retained for
+// the pods being recreated, which reuse the instance ids of the old ones.
+func (r *ReconcilePersistentPodState) getCloneSetInstanceIDs(ns string, workload *controllerfinder.ScaleAndSelector, pods []*corev1.Pod) (sets.Set[string], error) {
+	ids := sets.New[string]()
+	for _, pod := range pods {
+		if id := pod.Labels[appsv1beta1.CloneSetInstanceID]; id != "" {
+			ids.Insert(id)
+		}
+	}
+	selector, err := util.ValidatedLabelSelectorAsSelector(workload.Selector)
+	if err != nil {
+		return nil, err
+	}
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	if err = r.List(context.TODO(), pvcList, &client.ListOptions{Namespace: ns, LabelSelector: selector}, utilclient.DisableDeepCopy); err != nil {
+		return nil, err
+	}
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		if id := pvc.Labels[appsv1beta1.CloneSetInstanceID]; id != "" && pvc.DeletionTimestamp.IsZero() {
+			ids.Insert(id)
+		}
+	}
+	return ids, nil
+}
+
+// isCloneSetInstanceRetired returns true if neither the pod nor the pvcs of the CloneSet instance exist.
+// Pods of CloneSet are named {cloneSetName}-{instanceID}.
+func isCloneSetInstanceRetired(podName string, cs *innerStatefulset) bool {
+	return !cs.InstanceIDs.Has(strings.TrimPrefix(podName, cs.CloneSetName+"-"))
+}
+
 func (r *ReconcilePersistentPodState) getPodState(pod *corev1.Pod, nodeTopologyKeys sets.String, annotationKeys sets.String) (appsv1alpha1.PodState, error) {
 	// pod state
 	podState := appsv1alpha1.PodState{
@@ -30,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/kubernetes/pkg/apis/apps"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -562,3 +563,270 @@ func getLatestPersistentPodState(client client.Client, staticIP *appsv1alpha1.Pe
 	err := client.Get(context.TODO(), Key, newPersistentPodState)
 	return newPersistentPodState, err
 }
+
+func TestReconcilePersistentPodStateForCloneSet(t *testing.T) {
+	cloneSet := &appsv1alpha1.CloneSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns-test",
+			Name:      "test-cs",
+			UID:       "5f9ff5a4-3f87-4a3b-a4a0-d1b5c1f1e3c7",
+		},
+		Spec: appsv1alpha1.CloneSetSpec{
+			Replicas: ptr.To[int32](2),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test-cs"}},
+		},
+	}
+	newPod := func(id, nodeName string, ready bool) *corev1.Pod {
+		pod := podDemo.DeepCopy()
+		pod.Name = fmt.Sprintf("%s-%s", cloneSet.Name, id)
+		pod.Labels["app"] = "test-cs"
+		pod.Labels[appsv1beta1.CloneSetInstanceID] = id
+		pod.OwnerReferences[0].UID = cloneSet.UID
+		pod.Spec.NodeName = nodeName
+		if !ready {
+			pod.Status.Conditions[0].Status = corev1.ConditionFalse
+		}
+		return pod
+	}
+	newPVC := func(id string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: cloneSet.Namespace,
+				Name:      fmt.Sprintf("data-%s-%s", cloneSet.Name, id),
+				Labels:    map[string]string{"app": "test-cs", appsv1beta1.CloneSetInstanceID: id},
+			},
+		}
+	}
+	podState := func(i int) appsv1alpha1.PodState {
+		return appsv1alpha1.PodState{
+			NodeName: fmt.Sprintf("node-%d", i),
+			NodeTopologyLabels: map[string]string{
+				podStateZoneTopologyLabel: fmt.Sprintf("cn-beijing-%d", i),
+				podStateNodeTopologyLabel: fmt.Sprintf("kube-resource011162007216-%d", i),
+			},
+		}
+	}
+
+	cases := []struct {
+		name         string
+		getPods      func() []*corev1.Pod
+		getPVCs      func() []*corev1.PersistentVolumeClaim
+		expectStates map[string]appsv1alpha1.PodState
+	}{
+		{
+			name: "pods in-place updated, keep the pod states",
+			getPods: func() []*corev1.Pod {
+				return []*corev1.Pod{newPod("abcde", "node-0", false), newPod("fghij", "node-1", true)}
+			},
+			expectStates: map[string]appsv1alpha1.PodState{"test-cs-abcde": podState(0), "test-cs-fghij": podState(1)},
+		},
+		{
+			name: "pod deleted to recreate with pvc retained, keep the pod state for the instance id",
+			getPods: func() []*corev1.Pod {
+				return []*corev1.Pod{newPod("fghij", "node-1", true)}
+			},
+			getPVCs: func() []*corev1.PersistentVolumeClaim {
+				return []*corev1.PersistentVolumeClaim{newPVC("abcde"), newPVC("fghij")}
+			},
+			expectStates: map[string]appsv1alpha1.PodState{"test-cs-abcde": podState(0), "test-cs-fghij": podState(1)},
+		},
+		{
+			name: "pod recreated with the same instance id on another node, record the new node",
+			getPods: func() []*corev1.Pod {
+				return []*corev1.Pod{newPod("abcde", "node-2", true), newPod("fghij", "node-1", true)}
+			},
+			getPVCs: func() []*corev1.PersistentVolumeClaim {
+				return []*corev1.PersistentVolumeClaim{newPVC("abcde"), newPVC("fghij")}
+			},
+			expectStates: map[string]appsv1alpha1.PodState{"test-cs-abcde": podState(2), "test-cs-fghij": podState(1)},
+		},
+		{
+			name: "instance retired without pod and pvc, delete the pod state",
+			getPods: func() []*corev1.Pod {
+				return []*corev1.Pod{newPod("fghij", "node-1", true), newPod("klmno", "node-2", true)}
+			},
+			expectStates: map[string]appsv1alpha1.PodState{"test-cs-fghij": podState(1), "test-cs-klmno": podState(2)},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			pps := staticIPDemo.DeepCopy()
+			pps.Name = cloneSet.Name
+			pps.Spec.TargetReference = appsv1alpha1.TargetReference{
+				APIVersion: KruiseKindCloneSet.GroupVersion().String(),
+				Kind:       KruiseKindCloneSet.Kind,
+				Name:       cloneSet.Name,
+			}
+			pps.Status.PodStates = map[string]appsv1alpha1.PodState{"test-cs-abcde": podState(0), "test-cs-fghij": podState(1)}
+
+			clientBuilder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cloneSet.DeepCopy(), pps)
+			for i := 0; i < 3; i++ {
+				node := nodeDemo.DeepCopy()
+				node.Name = fmt.Sprintf("node-%d", i)
+				node.Labels[podStateZoneTopologyLabel] = fmt.Sprintf("cn-beijing-%d", i)
+				node.Labels[podStateNodeTopologyLabel] = fmt.Sprintf("kube-resource011162007216-%d", i)
+				clientBuilder.WithObjects(node)
+			}
+			for _, pod := range cs.getPods() {
+				clientBuilder.WithObjects(pod)
+			}
+			if cs.getPVCs != nil {
+				for _, pvc := range cs.getPVCs() {
+					clientBuilder.WithObjects(pvc)
+				}
+			}
+			clientBuilder.WithStatusSubresource(&appsv1alpha1.PersistentPodState{})
+			fakeClient := clientBuilder.WithIndex(&corev1.Pod{}, fieldindex.IndexNameForOwnerRefUID, func(obj client.Object) []string {
+				var owners []string
+				for _, ref := range obj.GetOwnerReferences() {
+					owners = append(owners, string(ref.UID))
+				}
+				return owners
+			}).Build()
+			reconciler := ReconcilePersistentPodState{
+				Client: fakeClient,
+				finder: &controllerfinder.ControllerFinder{Client: fakeClient},
+			}
+			request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: pps.Namespace, Name: pps.Name}}
+			if _, err := reconciler.Reconcile(context.TODO(), request); err != nil {
+				t.Fatalf("reconcile failed, err: %v", err)
+			}
+
+			latest, err := getLatestPersistentPodState(fakeClient, pps)
+			if err != nil {
+				t.Fatalf("get latest PersistentPodState failed, err: %v", err)
+			}
+			if !reflect.DeepEqual(latest.Status.PodStates, cs.expectStates) {
+				t.Fatalf("expect pod states %v, but got %v", cs.expectStates, latest.Status.PodStates)
+			}
+		})
+	}
+}
+
+func TestReconcilePersistentPodStateRetention(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		name               string
+		retention          *appsv1alpha1.PersistentPodStateRetention
+		expectPodStates    []int
+		expectPruned       int64
+		expectDeletedTimes bool
+		expectRequeue      bool
+	}{
+		{
+			name:            "no retention, keep all the states",
+			expectPodStates: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+		},
+		{
+			name:               "max entries, prune the earliest deleted first",
+			retention:          &appsv1alpha1.PersistentPodStateRetention{MaxEntries: ptr.To[int32](9)},
+			expectPodStates:    []int{0, 1, 2, 3, 7, 8, 9, 10, 11},
+			expectPruned:       3,
+			expectDeletedTimes: true,
+		},
+		{
+			name:               "zero max entries, keep the states of existing pods",
+			retention:          &appsv1alpha1.PersistentPodStateRetention{MaxEntries: ptr.To[int32](0)},
+			expectPodStates:    []int{0, 1, 2, 3},
+			expectPruned:       8,
+			expectDeletedTimes: true,
+		},
+		{
+			name:               "ttl, prune the states of pods deleted longer than it",
+			retention:          &appsv1alpha1.PersistentPodStateRetention{TTLSecondsAfterDeleted: ptr.To[int32](5*3600 + 1800)},
+			expectPodStates:    []int{0, 1, 2, 3, 7, 8, 9, 10, 11},
+			expectPruned:       3,
+			expectDeletedTimes: true,
+			expectRequeue:      true,
+		},
+		{
+			name: "ttl and max entries",
+			retention: &appsv1alpha1.PersistentPodStateRetention{
+				TTLSecondsAfterDeleted: ptr.To[int32](5*3600 + 1800),
+				MaxEntries:             ptr.To[int32](6),
+			},
+			// the states deleted at the same time are pruned by the order of names
+			expectPodStates:    []int{0, 1, 2, 3, 9, 11},
+			expectPruned:       6,
+			expectDeletedTimes: true,
+			expectRequeue:      true,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			sts := kruiseStsDemo.DeepCopy()
+			pps := staticIPDemo.DeepCopy()
+			pps.Spec.PersistentPodStateRetentionPolicy = appsv1alpha1.PersistentPodStateRetentionPolicyWhenDeleted
+			pps.Spec.PersistentPodStateRetention = cs.retention
+			pps.Status.PrunedPodStates = 1
+			// pods 0-2 are running, pod 3 is terminating, and the others are deleted:
+			// pods 4-8 were deleted 8-4 hours ago, and pods 9-11 are found deleted now
+			clientBuilder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sts)
+			for i := 0; i < 12; i++ {
+				podName := fmt.Sprintf("%s-%d", sts.Name, i)
+				podState := appsv1alpha1.PodState{NodeName: fmt.Sprintf("node-%d", i)}
+				if i >= 4 && i <= 8 {
+					podState.DeletedTime = &metav1.Time{Time: now.Add(-time.Duration(12-i) * time.Hour)}
+				}
+				pps.Status.PodStates[podName] = podState
+				if i > 3 {
+					continue
+				}
+				pod := podDemo.DeepCopy()
+				pod.Name = podName
+				pod.OwnerReferences[0].UID = sts.UID
+				pod.Spec.NodeName = fmt.Sprintf("node-%d", i)
+				if i == 3 {
+					pod.DeletionTimestamp = &metav1.Time{Time: now}
+					pod.Finalizers = []string{"kruise.io/test"}
+				}
+				clientBuilder.WithObjects(pod)
+				node := nodeDemo.DeepCopy()
+				node.Name = pod.Spec.NodeName
+				clientBuilder.WithObjects(node)
+			}
+			fakeClient := clientBuilder.WithObjects(pps).WithStatusSubresource(&appsv1alpha1.PersistentPodState{}).
+				WithIndex(&corev1.Pod{}, fieldindex.IndexNameForOwnerRefUID, func(obj client.Object) []string {
+					var owners []string
+					for _, ref := range obj.GetOwnerReferences() {
+						owners = append(owners, string(ref.UID))
+					}
+					return owners
+				}).Build()
+			reconciler := ReconcilePersistentPodState{
+				Client: fakeClient,
+				finder: &controllerfinder.ControllerFinder{Client: fakeClient},
+			}
+			result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Namespace: pps.Namespace, Name: pps.Name}})
+			if err != nil {
+				t.Fatalf("reconcile failed, err: %v", err)
+			}
+			if cs.expectRequeue != (result.RequeueAfter > 0) {
+				t.Fatalf("expect requeue %v, but got %v", cs.expectRequeue, result.RequeueAfter)
+			}
+
+			latest, err := getLatestPersistentPodState(fakeClient, pps)
+			if err != nil {
+				t.Fatalf("get latest PersistentPodState failed, err: %v", err)
+			}
+			expected := sets.New[string]()
+			for _, i := range cs.expectPodStates {
+				expected.Insert(fmt.Sprintf("%s-%d", sts.Name, i))
+			}
+			if got := sets.KeySet(latest.Status.PodStates); !got.Equal(expected) {
+				t.Fatalf("expect pod states %v, but got %v", sets.List(expected), sets.List(got))
+			}
+			if latest.Status.PrunedPodStates != 1+cs.expectPruned {
+				t.Fatalf("expect %d pruned pod states, but got %d", 1+cs.expectPruned, latest.Status.PrunedPodStates)
+			}
+			for podName, podState := range latest.Status.PodStates {
+				index, _ := parseStsPodIndex(podName)
+				if expectDeleted := cs.expectDeletedTimes && index > 3; expectDeleted != (podState.DeletedTime != nil) {
+					t.Fatalf("expect deleted time of %s recorded %v, but got %v", podName, expectDeleted, podState.DeletedTime)
+				}
+			}
+		})
+	}
+}
@@ -263,6 +263,66 @@ func (p *enqueueRequestForKruiseStatefulSet) Update(ctx context.Context, evt eve
 	}
 }
 
+var _ handler.TypedEventHandler[*appsv1beta1.CloneSet, reconcile.Request] = &enqueueRequestForCloneSet{}
+
+type enqueueRequestForCloneSet struct {
+	reader client.Reader
+}
+
+func (p *enqueueRequestForCloneSet) Create(ctx context.Context, evt event.TypedCreateEvent[*appsv1beta1.CloneSet], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	cs := evt.Object
+	if cs.Annotations[appsv1alpha1.AnnotationAutoGeneratePersistentPodState] == "true" &&
+		(cs.Annotations[appsv1alpha1.AnnotationRequiredPersistentTopology] != "" ||
+			cs.Annotations[appsv1alpha1.AnnotationPreferredPersistentTopology] != "") {
+		enqueuePersistentPodStateRequest(q, KruiseKindCloneSet.GroupVersion().String(), KruiseKindCloneSet.Kind, cs.Namespace, cs.Name)
+	}
+}
+
+func (p *enqueueRequestForCloneSet) Delete(ctx context.Context, evt event.TypedDeleteEvent[*appsv1beta1.CloneSet], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	cs := evt.Object
+	if pps := mutating.SelectorPersistentPodState(p.reader, appsv1alpha1.TargetReference{
+		APIVersion: KruiseKindCloneSet.GroupVersion().String(),
+		Kind:       KruiseKindCloneSet.Kind,
+		Name:       cs.Name,
+	}, cs.Namespace); pps != nil {
+		q.Add(reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      pps.Name,
+				Namespace: pps.Namespace,
+			},
+		})
+	}
+}
+
+func (p *enqueueRequestForCloneSet) Generic(ctx context.Context, evt event.TypedGenericEvent[*appsv1beta1.CloneSet], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+}
+
+func (p *enqueueRequestForCloneSet) Update(ctx context.Context, evt event.TypedUpdateEvent[*appsv1beta1.CloneSet], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	oCs := evt.ObjectOld
+	nCs := evt.ObjectNew
+	if oCs.Annotations[appsv1alpha1.AnnotationAutoGeneratePersistentPodState] != nCs.Annotations[appsv1alpha1.AnnotationAutoGeneratePersistentPodState] ||
+		oCs.Annotations[appsv1alpha1.AnnotationRequiredPersistentTopology] != nCs.Annotations[appsv1alpha1.AnnotationRequiredPersistentTopology] ||
+		oCs.Annotations[appsv1alpha1.AnnotationPreferredPersistentTopology] != nCs.Annotations[appsv1alpha1.AnnotationPreferredPersistentTopology] {
+		enqueuePersistentPodStateRequest(q, KruiseKindCloneSet.GroupVersion().String(), KruiseKindCloneSet.Kind, nCs.Namespace, nCs.Name)
+	}
+
+	// delete cloneSet scenario
+	if oCs.DeletionTimestamp.IsZero() && !nCs.DeletionTimestamp.IsZero() {
+		if pps := mutating.SelectorPersistentPodState(p.reader, appsv1alpha1.TargetReference{
+			APIVersion: KruiseKindCloneSet.GroupVersion().String(),
+			Kind:       KruiseKindCloneSet.Kind,
+			Name:       nCs.Name,
+		}, nCs.Namespace); pps != nil {
+			q.Add(reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      pps.Name,
+					Namespace: pps.Namespace,
+				},
+			})
+		}
+	}
+}
+
 var _ handler.EventHandler = &enqueueRequestForStatefulSetLike{}
 
 type enqueueRequestForStatefulSetLike struct {
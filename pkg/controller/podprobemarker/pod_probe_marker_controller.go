@@ -255,6 +255,20 @@ func (r *ReconcilePodProbeMarker) markServerlessPod(pod *corev1.Pod, markers map
 			continue
 		}
 
+		// the markers are reconciled as a set, the ones of the opposite policy are removed before the ones of
+		// the matched policy applied, so that they do not linger after the probe result flips
+		for i := range policy {
+			obj := &policy[i]
+			if obj.State.IsEqualPodConditionStatus(condition.Status) || !obj.ShouldRemoveOnOpposite() {
+				continue
+			}
+			for k := range obj.Annotations {
+				delete(newObjectMeta.Annotations, k)
+			}
+			for k := range obj.Labels {
+				delete(newObjectMeta.Labels, k)
+			}
+		}
 		for _, obj := range policy {
 			if !obj.State.IsEqualPodConditionStatus(condition.Status) {
 				continue
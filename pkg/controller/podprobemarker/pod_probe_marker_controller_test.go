@@ -28,6 +28,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -2088,3 +2089,110 @@ func TestMarkerServerlessPod(t *testing.T) {
 	}
 
 }
+
+func TestMarkerServerlessPodFlipProbeResult(t *testing.T) {
+	newPod := func() *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "pod-1",
+				Labels:      map[string]string{"app": "web"},
+				Annotations: map[string]string{"app": "web"},
+			},
+			Status: corev1.PodStatus{
+				Conditions: []corev1.PodCondition{{Type: corev1.PodConditionType("game.io/idle")}},
+			},
+		}
+	}
+	cases := []struct {
+		name     string
+		policy   []appsv1alpha1.ProbeMarkerPolicy
+		expected map[corev1.ConditionStatus]map[string]string
+	}{
+		{
+			name: "both policies defined",
+			policy: []appsv1alpha1.ProbeMarkerPolicy{
+				{
+					State:       appsv1alpha1.ProbeSucceeded,
+					Labels:      map[string]string{"gameserver-idle": "true", "idle-since": "probe"},
+					Annotations: map[string]string{"controller.kubernetes.io/pod-deletion-cost": "-10"},
+				},
+				{
+					State:       appsv1alpha1.ProbeFailed,
+					Labels:      map[string]string{"gameserver-idle": "false", "busy": "true"},
+					Annotations: map[string]string{"controller.kubernetes.io/pod-deletion-cost": "10"},
+				},
+			},
+			expected: map[corev1.ConditionStatus]map[string]string{
+				corev1.ConditionTrue:  {"app": "web", "gameserver-idle": "true", "idle-since": "probe", "controller.kubernetes.io/pod-deletion-cost": "-10"},
+				corev1.ConditionFalse: {"app": "web", "gameserver-idle": "false", "busy": "true", "controller.kubernetes.io/pod-deletion-cost": "10"},
+			},
+		},
+		{
+			name: "only succeeded policy defined",
+			policy: []appsv1alpha1.ProbeMarkerPolicy{
+				{
+					State:       appsv1alpha1.ProbeSucceeded,
+					Labels:      map[string]string{"gameserver-idle": "true"},
+					Annotations: map[string]string{"controller.kubernetes.io/pod-deletion-cost": "-10"},
+				},
+			},
+			expected: map[corev1.ConditionStatus]map[string]string{
+				corev1.ConditionTrue:  {"app": "web", "gameserver-idle": "true", "controller.kubernetes.io/pod-deletion-cost": "-10"},
+				corev1.ConditionFalse: {"app": "web"},
+			},
+		},
+		{
+			name: "markers kept on opposite",
+			policy: []appsv1alpha1.ProbeMarkerPolicy{
+				{
+					State:            appsv1alpha1.ProbeSucceeded,
+					Labels:           map[string]string{"gameserver-idle": "true", "idle-since": "probe"},
+					Annotations:      map[string]string{"controller.kubernetes.io/pod-deletion-cost": "-10"},
+					RemoveOnOpposite: ptr.To(false),
+				},
+				{
+					State:       appsv1alpha1.ProbeFailed,
+					Labels:      map[string]string{"gameserver-idle": "false"},
+					Annotations: map[string]string{"controller.kubernetes.io/pod-deletion-cost": "10"},
+				},
+			},
+			expected: map[corev1.ConditionStatus]map[string]string{
+				corev1.ConditionTrue:  {"app": "web", "gameserver-idle": "true", "idle-since": "probe", "controller.kubernetes.io/pod-deletion-cost": "-10"},
+				corev1.ConditionFalse: {"app": "web", "gameserver-idle": "false", "idle-since": "probe", "controller.kubernetes.io/pod-deletion-cost": "10"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newPod()).Build()
+			r := &ReconcilePodProbeMarker{Client: fakeClient}
+			markers := map[string][]appsv1alpha1.ProbeMarkerPolicy{"game.io/idle": tc.policy}
+			// flip the probe result repeatedly, the markers are always the ones of the current result
+			for i, status := range []corev1.ConditionStatus{corev1.ConditionTrue, corev1.ConditionFalse, corev1.ConditionTrue,
+				corev1.ConditionFalse, corev1.ConditionFalse, corev1.ConditionTrue} {
+				pod := &corev1.Pod{}
+				if err := fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(newPod()), pod); err != nil {
+					t.Fatal(err)
+				}
+				pod.Status.Conditions[0].Status = status
+				if err := r.markServerlessPod(pod, markers); err != nil {
+					t.Fatal(err)
+				}
+				if err := fakeClient.Get(context.TODO(), client.ObjectKeyFromObject(pod), pod); err != nil {
+					t.Fatal(err)
+				}
+				markerValues := map[string]string{}
+				for k, v := range pod.Labels {
+					markerValues[k] = v
+				}
+				for k, v := range pod.Annotations {
+					markerValues[k] = v
+				}
+				if expected := tc.expected[status]; !reflect.DeepEqual(expected, markerValues) {
+					t.Fatalf("flip %d to %s: expected markers %v, but got %v", i, status, expected, markerValues)
+				}
+			}
+		})
+	}
+}
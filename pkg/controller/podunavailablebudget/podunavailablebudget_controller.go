@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
@@ -42,6 +43,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -61,11 +63,22 @@ import (
 
 func init() {
 	flag.IntVar(&concurrentReconciles, "podunavailablebudget-workers", concurrentReconciles, "Max concurrent workers for PodUnavailableBudget controller.")
+	metrics.Registry.MustRegister(PodUnavailableBudgetStaleEntriesMetrics)
 }
 
 var (
 	concurrentReconciles = 3
 	controllerKind       = policyv1alpha1.SchemeGroupVersion.WithKind("PodUnavailableBudget")
+
+	// PodUnavailableBudgetStaleEntriesMetrics counts the entries removed from status.disruptedPods and
+	// status.unavailablePods because their pods no longer exist, e.g., deleted while the webhook was down.
+	PodUnavailableBudgetStaleEntriesMetrics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pod_unavailable_budget_stale_entries_removed",
+			Help: "PodUnavailableBudget Stale Entries Removed Metrics",
+			// pub namespace, name, and status field of the entries
+		}, []string{"namespace", "name", "field"},
+	)
 )
 
 const (
@@ -129,6 +142,11 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for pod operations rejected by the webhook, to flush them into pub status
+	if err = c.Watch(source.Channel(pubcontrol.BlockedOperationEvents, &handler.TypedEnqueueRequestForObject[*policyv1alpha1.PodUnavailableBudget]{})); err != nil {
+		return err
+	}
+
 	// In workload scaling scenario, there is a risk of interception by the pub webhook against the scaled pod.
 	// The solution for this scenario: the pub controller listens to workload replicas changes and adjusts UnavailableAllowed in time.
 	// Example for:
@@ -229,6 +247,7 @@ func (r *ReconcilePodUnavailableBudget) Reconcile(_ context.Context, req ctrl.Re
 		}); cacheErr != nil {
 			klog.ErrorS(err, "Deleted cache failed for PodUnavailableBudget", "podUnavailableBudget", req)
 		}
+		pubcontrol.ForgetBlockedOperations(req.NamespacedName)
 		// Object not found, return.  Created objects are automatically garbage collected.
 		// For additional cleanup logic use finalizers.
 		return reconcile.Result{}, nil
@@ -319,11 +338,14 @@ func (r *ReconcilePodUnavailableBudget) syncPodUnavailableBudget(pub *policyv1al
 		var disruptedPods, unavailablePods map[string]metav1.Time
 		disruptedPods, unavailablePods, recheckTime = r.buildDisruptedAndUnavailablePods(pods, pubClone, currentTime)
 		currentAvailable := countAvailablePods(pods, disruptedPods, unavailablePods)
+		staleDisruptedPods, staleUnavailablePods := getStalePodEntries(pods, pubClone)
 
 		start = time.Now()
 		updateErr := r.updatePubStatus(pubClone, currentAvailable, desiredAvailable, expectedCount, disruptedPods, unavailablePods)
 		costOfUpdate += time.Since(start)
 		if updateErr == nil {
+			recordStalePodEntries(pub, "disruptedPods", staleDisruptedPods)
+			recordStalePodEntries(pub, "unavailablePods", staleUnavailablePods)
 			return nil
 		}
 		// update failed, and retry
@@ -466,6 +488,37 @@ func (r *ReconcilePodUnavailableBudget) buildDisruptedAndUnavailablePods(pods []
 	return resultDisruptedPods, resultUnavailablePods, recheckTime
 }
 
+// getStalePodEntries returns the names in status.disruptedPods and status.unavailablePods whose pods no longer
+// exist or are no longer active, which are never released by the webhook if the pods were deleted outside of it.
+// They are removed by buildDisruptedAndUnavailablePods, so that the budget they consume is given back.
+func getStalePodEntries(pods []*corev1.Pod, pub *policyv1alpha1.PodUnavailableBudget) (staleDisruptedPods, staleUnavailablePods []string) {
+	activePods := sets.New[string]()
+	for _, pod := range pods {
+		if kubecontroller.IsPodActive(pod) {
+			activePods.Insert(pod.Name)
+		}
+	}
+	for name := range pub.Status.DisruptedPods {
+		if !activePods.Has(name) {
+			staleDisruptedPods = append(staleDisruptedPods, name)
+		}
+	}
+	for name := range pub.Status.UnavailablePods {
+		if !activePods.Has(name) {
+			staleUnavailablePods = append(staleUnavailablePods, name)
+		}
+	}
+	return staleDisruptedPods, staleUnavailablePods
+}
+
+func recordStalePodEntries(pub *policyv1alpha1.PodUnavailableBudget, field string, names []string) {
+	if len(names) == 0 {
+		return
+	}
+	klog.V(3).InfoS("PodUnavailableBudget removed stale pod entries", "podUnavailableBudget", klog.KObj(pub), "field", field, "pods", names)
+	PodUnavailableBudgetStaleEntriesMetrics.WithLabelValues(pub.Namespace, pub.Name, field).Add(float64(len(names)))
+}
+
 func (r *ReconcilePodUnavailableBudget) updatePubStatus(pub *policyv1alpha1.PodUnavailableBudget, currentAvailable, desiredAvailable, expectedCount int32,
 	disruptedPods, unavailablePods map[string]metav1.Time) error {
 
@@ -474,7 +527,10 @@ func (r *ReconcilePodUnavailableBudget) updatePubStatus(pub *policyv1alpha1.PodU
 		unavailableAllowed = 0
 	}
 
-	if pub.Status.CurrentAvailable == currentAvailable &&
+	// flush the pod operations rejected by the webhook
+	blocked := pubcontrol.TakeBlockedOperations(pub)
+	if blocked == nil &&
+		pub.Status.CurrentAvailable == currentAvailable &&
 		pub.Status.DesiredAvailable == desiredAvailable &&
 		pub.Status.TotalReplicas == expectedCount &&
 		pub.Status.UnavailableAllowed == unavailableAllowed &&
@@ -492,9 +548,13 @@ func (r *ReconcilePodUnavailableBudget) updatePubStatus(pub *policyv1alpha1.PodU
 		DisruptedPods:      disruptedPods,
 		UnavailablePods:    unavailablePods,
 		ObservedGeneration: pub.Generation,
+		BlockedOperations:  pub.Status.BlockedOperations,
+		TotalBlockedCount:  pub.Status.TotalBlockedCount,
 	}
+	blocked.MergeInto(&pub.Status)
 	err := r.Client.Status().Update(context.TODO(), pub)
 	if err != nil {
+		pubcontrol.RestoreBlockedOperations(pub, blocked)
 		return err
 	}
 	if err = util.GlobalCache.Add(pub); err != nil {
@@ -23,6 +23,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -1305,6 +1306,72 @@ func TestPubReconcile(t *testing.T) {
 	}
 }
 
+func TestPubReconcileStalePodEntries(t *testing.T) {
+	pub := pubDemo.DeepCopy()
+	pub.Annotations[policyv1alpha1.PubProtectTotalReplicasAnnotation] = "10"
+	// test-pod-10, test-pod-11 and test-pod-12 were deleted while the webhook was down,
+	// so their entries are never released by the webhook.
+	pub.Status.DisruptedPods["test-pod-0"] = metav1.Now()
+	pub.Status.DisruptedPods["test-pod-10"] = metav1.Now()
+	pub.Status.DisruptedPods["test-pod-11"] = metav1.Time{Time: time.Now().Add(-time.Hour)}
+	pub.Status.UnavailablePods["test-pod-1"] = metav1.Now()
+	pub.Status.UnavailablePods["test-pod-12"] = metav1.Now()
+	defer util.GlobalCache.Delete(pub)
+
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pub).
+		WithStatusSubresource(&policyv1alpha1.PodUnavailableBudget{})
+	for i := 0; i < 10; i++ {
+		pod := podDemo.DeepCopy()
+		pod.OwnerReferences = nil
+		pod.Name = fmt.Sprintf("%s-%d", pod.Name, i)
+		builder.WithObjects(pod)
+	}
+	fakeClient := builder.Build()
+	finder := &controllerfinder.ControllerFinder{Client: fakeClient}
+	pubcontrol.InitPubControl(fakeClient, finder, record.NewFakeRecorder(10))
+	reconciler := ReconcilePodUnavailableBudget{
+		Client:           fakeClient,
+		recorder:         record.NewFakeRecorder(10),
+		controllerFinder: finder,
+	}
+
+	staleDisrupted := PodUnavailableBudgetStaleEntriesMetrics.WithLabelValues(pub.Namespace, pub.Name, "disruptedPods")
+	staleUnavailable := PodUnavailableBudgetStaleEntriesMetrics.WithLabelValues(pub.Namespace, pub.Name, "unavailablePods")
+	disruptedBefore, unavailableBefore := testutil.ToFloat64(staleDisrupted), testutil.ToFloat64(staleUnavailable)
+	if _, err := reconciler.syncPodUnavailableBudget(pub); err != nil {
+		t.Fatalf("sync PodUnavailableBudget failed: %s", err.Error())
+	}
+	newPub, err := getLatestPub(fakeClient, pub)
+	if err != nil {
+		t.Fatalf("getLatestPub failed: %s", err.Error())
+	}
+	expectStatus := policyv1alpha1.PodUnavailableBudgetStatus{
+		DisruptedPods:      map[string]metav1.Time{"test-pod-0": metav1.Now()},
+		UnavailablePods:    map[string]metav1.Time{"test-pod-1": metav1.Now()},
+		TotalReplicas:      10,
+		DesiredAvailable:   7,
+		CurrentAvailable:   8,
+		UnavailableAllowed: 1,
+	}
+	if !isPubStatusEqual(expectStatus, newPub.Status) {
+		t.Fatalf("expect pub status(%s) but get(%s)", util.DumpJSON(expectStatus), util.DumpJSON(newPub.Status))
+	}
+	if got := testutil.ToFloat64(staleDisrupted) - disruptedBefore; got != 2 {
+		t.Fatalf("expect 2 stale disruptedPods removed, but got %v", got)
+	}
+	if got := testutil.ToFloat64(staleUnavailable) - unavailableBefore; got != 1 {
+		t.Fatalf("expect 1 stale unavailablePods removed, but got %v", got)
+	}
+
+	// nothing is stale any more
+	if _, err := reconciler.syncPodUnavailableBudget(newPub); err != nil {
+		t.Fatalf("sync PodUnavailableBudget failed: %s", err.Error())
+	}
+	if got := testutil.ToFloat64(staleDisrupted) - disruptedBefore; got != 2 {
+		t.Fatalf("expect no more stale disruptedPods removed, but got %v", got)
+	}
+}
+
 func TestDesiredAvailableForPub(t *testing.T) {
 	cases := []struct {
 		name             string
@@ -1365,3 +1432,67 @@ func isPubStatusEqual(expectStatus, nowStatus policyv1alpha1.PodUnavailableBudge
 
 	return reflect.DeepEqual(expectStatus, nowStatus)
 }
+
+func TestPubReconcileFlushBlockedOperations(t *testing.T) {
+	pub := pubDemo.DeepCopy()
+	pub.UID = "pub-uid"
+	pub.Annotations[policyv1alpha1.PubProtectTotalReplicasAnnotation] = "3"
+	pub.Spec.MaxUnavailable = &intstr.IntOrString{Type: intstr.Int, IntVal: 0}
+	defer util.GlobalCache.Delete(pub)
+	defer pubcontrol.ForgetBlockedOperations(client.ObjectKeyFromObject(pub))
+
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pub).
+		WithStatusSubresource(&policyv1alpha1.PodUnavailableBudget{})
+	var pods []*corev1.Pod
+	for i := 0; i < 3; i++ {
+		pod := podDemo.DeepCopy()
+		pod.OwnerReferences = nil
+		pod.Name = fmt.Sprintf("%s-%d", pod.Name, i)
+		pod.Annotations[pubcontrol.PodRelatedPubAnnotation] = pub.Name
+		builder.WithObjects(pod)
+		pods = append(pods, pod)
+	}
+	fakeClient := builder.Build()
+	finder := &controllerfinder.ControllerFinder{Client: fakeClient}
+	pubcontrol.InitPubControl(fakeClient, finder, record.NewFakeRecorder(10))
+	reconciler := ReconcilePodUnavailableBudget{
+		Client:           fakeClient,
+		recorder:         record.NewFakeRecorder(10),
+		controllerFinder: finder,
+	}
+	if _, err := reconciler.syncPodUnavailableBudget(pub); err != nil {
+		t.Fatalf("sync PodUnavailableBudget failed: %s", err.Error())
+	}
+
+	// the rejection is aggregated without writing pub status
+	allowed, _, err := pubcontrol.PodUnavailableBudgetValidatePod(pods[0], policyv1alpha1.PubDeleteOperation, "fake-user", false)
+	if err != nil || allowed {
+		t.Fatalf("expect rejected, but got allowed %v, err %v", allowed, err)
+	}
+	newPub, err := getLatestPub(fakeClient, pub)
+	if err != nil {
+		t.Fatalf("getLatestPub failed: %s", err.Error())
+	}
+	if len(newPub.Status.BlockedOperations) != 0 {
+		t.Fatalf("expect no blocked operation in status before flushed, but got %v", newPub.Status.BlockedOperations)
+	}
+
+	if _, err := reconciler.syncPodUnavailableBudget(newPub); err != nil {
+		t.Fatalf("sync PodUnavailableBudget failed: %s", err.Error())
+	}
+	newPub, err = getLatestPub(fakeClient, pub)
+	if err != nil {
+		t.Fatalf("getLatestPub failed: %s", err.Error())
+	}
+	blocked := newPub.Status.BlockedOperations
+	if len(blocked) != 1 || blocked[0].PodName != pods[0].Name || blocked[0].Operation != policyv1alpha1.PubDeleteOperation {
+		t.Fatalf("expect the blocked operation flushed, but got %v", blocked)
+	}
+	expectCount := map[policyv1alpha1.PubOperation]int64{policyv1alpha1.PubDeleteOperation: 1}
+	if !reflect.DeepEqual(newPub.Status.TotalBlockedCount, expectCount) {
+		t.Fatalf("expect total blocked count %v, but got %v", expectCount, newPub.Status.TotalBlockedCount)
+	}
+	if newPub.Status.UnavailableAllowed != 0 || newPub.Status.TotalReplicas != 3 {
+		t.Fatalf("unexpected pub status %s", util.DumpJSON(newPub.Status))
+	}
+}
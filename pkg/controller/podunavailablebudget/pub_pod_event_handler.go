@@ -248,14 +248,12 @@ func (e *SetEnqueueRequestForPUB) addSetRequest(object client.Object, q workqueu
 		klog.ErrorS(err, "SetEnqueueRequestForPUB list pub failed")
 		return
 	}
-	var matched policyv1alpha1.PodUnavailableBudget
 	for _, pub := range pubList.Items {
 		// if targetReference isn't nil, priority to take effect
 		if pub.Spec.TargetReference != nil {
 			// belongs the same workload
-			if pubcontrol.IsReferenceEqual(targetRef, pub.Spec.TargetReference) {
-				matched = pub
-				break
+			if !pubcontrol.IsReferenceEqual(targetRef, pub.Spec.TargetReference) {
+				continue
 			}
 		} else {
 			// This error is irreversible, so continue
@@ -267,17 +265,16 @@ func (e *SetEnqueueRequestForPUB) addSetRequest(object client.Object, q workqueu
 			if labelSelector.Empty() || !labelSelector.Matches(labels.Set(temLabels)) {
 				continue
 			}
-			matched = pub
-			break
 		}
-	}
 
-	q.Add(reconcile.Request{
-		NamespacedName: types.NamespacedName{
-			Name:      matched.Name,
-			Namespace: matched.Namespace,
-		},
-	})
-	klog.V(3).InfoS("Workload changed, and reconcile PodUnavailableBudget",
-		"wordload", klog.KRef(namespace, targetRef.Name), "podUnavailableBudget", klog.KRef(matched.Namespace, matched.Name))
+		// the total replicas of all the matched PUBs should follow the workload
+		q.Add(reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      pub.Name,
+				Namespace: pub.Namespace,
+			},
+		})
+		klog.V(3).InfoS("Workload changed, and reconcile PodUnavailableBudget",
+			"wordload", klog.KRef(namespace, targetRef.Name), "podUnavailableBudget", klog.KObj(&pub))
+	}
 }
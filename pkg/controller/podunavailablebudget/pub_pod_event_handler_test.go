@@ -22,13 +22,19 @@ import (
 	"testing"
 	"time"
 
+	apps "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/util/workqueue"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
 	"github.com/openkruise/kruise/pkg/control/pubcontrol"
 )
 
@@ -91,3 +97,56 @@ func TestPodEventHandler(t *testing.T) {
 		t.Errorf("unexpected update event handle queue size, expected 0 actual %d", updateQ.Len())
 	}
 }
+
+type fakeManager struct {
+	manager.Manager
+	client client.Client
+}
+
+func (m *fakeManager) GetClient() client.Client {
+	return m.client
+}
+
+func (m *fakeManager) GetScheme() *runtime.Scheme {
+	return scheme
+}
+
+func TestSetEventHandler(t *testing.T) {
+	deployment := &apps.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nginx"},
+		Spec: apps.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"pub-controller": "true"}}},
+		},
+	}
+	newUpdateQueue := func(c client.Client) workqueue.TypedRateLimitingInterface[reconcile.Request] {
+		q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		handler := &SetEnqueueRequestForPUB{mgr: &fakeManager{client: c}}
+		handler.Update(context.TODO(), event.UpdateEvent{ObjectOld: deployment, ObjectNew: deployment}, q)
+		return q
+	}
+
+	// no pub matched
+	if q := newUpdateQueue(fake.NewClientBuilder().WithScheme(scheme).Build()); q.Len() != 0 {
+		t.Fatalf("expected no request for the workload without pub, got %d", q.Len())
+	}
+
+	pubBySelector := pubDemo.DeepCopy()
+	pubByRef := pubDemo.DeepCopy()
+	pubByRef.Name = "pub-ref"
+	pubByRef.Spec.Selector = nil
+	pubByRef.Spec.TargetReference = &policyv1alpha1.TargetReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "nginx"}
+	pubOther := pubByRef.DeepCopy()
+	pubOther.Name = "pub-other"
+	pubOther.Spec.TargetReference.Name = "other"
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pubBySelector, pubByRef, pubOther).Build()
+	q := newUpdateQueue(c)
+	if q.Len() != 2 {
+		t.Fatalf("expected requests for all the matched pubs, got %d", q.Len())
+	}
+	for i := 0; i < 2; i++ {
+		req, _ := q.Get()
+		if req.Name != pubBySelector.Name && req.Name != pubByRef.Name {
+			t.Fatalf("unexpected request %v", req)
+		}
+	}
+}
@@ -18,18 +18,23 @@ package resourcedistribution
 
 import (
 	"context"
+	goerrors "errors"
 	"flag"
 	"fmt"
 	"reflect"
+	"sort"
+	"sync"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -74,6 +79,7 @@ func newReconciler(mgr manager.Manager) reconcile.Reconciler {
 	return &ReconcileResourceDistribution{
 		Client: cli,
 		scheme: mgr.GetScheme(),
+		clock:  clock.RealClock{},
 	}
 }
 
@@ -154,6 +160,7 @@ var _ reconcile.Reconciler = &ReconcileResourceDistribution{}
 type ReconcileResourceDistribution struct {
 	client.Client
 	scheme *runtime.Scheme
+	clock  clock.Clock
 }
 
 //+kubebuilder:rbac:groups=apps.kruise.io,resources=resourcedistributions,verbs=get;list;watch;
@@ -203,30 +210,49 @@ func (r *ReconcileResourceDistribution) doReconcile(distributor *appsv1alpha1.Re
 		return reconcile.Result{}, err
 	}
 
-	// 1. distribute resource to matched namespaces
-	succeeded, distributeErrList := r.distributeResource(distributor, matchedNamespaces, resource)
+	// 1. distribute resource to matched namespaces, except the failed ones waiting for retry
+	now := r.clock.Now()
+	namespacesToDistribute, waitingNamespaces := splitNamespacesInBackoff(distributor, matchedNamespaces, now)
+	succeeded, skippedNamespaces, distributeErrList := r.distributeResource(distributor, namespacesToDistribute, resource)
+	for i := range waitingNamespaces {
+		distributeErrList = append(distributeErrList, &UnexpectedError{
+			err:         goerrors.New(waitingNamespaces[i].LastError),
+			namespace:   waitingNamespaces[i].Name,
+			conditionID: conditionIDOf(waitingNamespaces[i].ConditionType),
+		})
+	}
 
 	// 2. clean its owned resources in unmatched namespaces
 	_, cleanErrList := r.cleanResource(distributor, unmatchedNamespaces, resource)
 
-	// 3. process all errors about resource distribution and cleanup
-	conditions, errList := r.handleErrors(distributeErrList, cleanErrList)
+	// 3. process all errors about resource distribution and cleanup, the failed distributions are retried
+	// with backoff per namespace instead of requeueing the distributor with errors
+	conditions, _ := r.handleErrors(distributeErrList, cleanErrList)
+	_, errList := r.handleErrors(cleanErrList)
+	failedNamespaces, requeueAfter := calculateFailedNamespaces(distributor, waitingNamespaces, distributeErrList, now)
 
 	// 4. update distributor status
 	newStatus := calculateNewStatus(distributor, conditions, int32(len(matchedNamespaces)), succeeded)
+	newStatus.SkippedNamespaces = skippedNamespaces
+	newStatus.FailedNamespaces = failedNamespaces
 	if err := r.updateDistributorStatus(distributor, newStatus); err != nil {
 		errList = append(errList, field.InternalError(field.NewPath("updateStatus"), err))
 	}
-	return ctrl.Result{}, errList.ToAggregate()
+	if len(errList) != 0 {
+		return ctrl.Result{}, errList.ToAggregate()
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 func (r *ReconcileResourceDistribution) distributeResource(distributor *appsv1alpha1.ResourceDistribution,
-	matchedNamespaces []string, resource runtime.Object) (int32, []*UnexpectedError) {
+	matchedNamespaces []string, resource runtime.Object) (int32, []appsv1alpha1.ResourceDistributionSkippedNamespace, []*UnexpectedError) {
 
 	resourceName := utils.ConvertToUnstructured(resource).GetName()
 	resourceKind := resource.GetObjectKind().GroupVersionKind().Kind
 	resourceHashCode := hashResource(distributor.Spec.Resource)
-	return syncItSlowly(matchedNamespaces, 1, func(namespace string) *UnexpectedError {
+	var skippedLock sync.Mutex
+	var skippedNamespaces []appsv1alpha1.ResourceDistributionSkippedNamespace
+	succeeded, errList := syncItSlowly(matchedNamespaces, 1, func(namespace string) *UnexpectedError {
 		ns := &corev1.Namespace{}
 		getNSErr := r.Client.Get(context.TODO(), types.NamespacedName{Name: namespace}, ns)
 		if errors.IsNotFound(getNSErr) || (getNSErr == nil && ns.DeletionTimestamp != nil) {
@@ -237,6 +263,20 @@ func (r *ReconcileResourceDistribution) distributeResource(distributor *appsv1al
 			}
 		}
 
+		// render the resource for the namespace if resourceTemplating is enabled
+		rendered, hashCode := utils.ConvertToUnstructured(resource), resourceHashCode
+		if distributor.Spec.ResourceTemplating {
+			var renderErr error
+			if rendered, hashCode, renderErr = renderResource(resource, namespace); renderErr != nil {
+				klog.ErrorS(renderErr, "Error occurred when rendering resource for namespace", "namespace", namespace, "resourceDistribution", klog.KObj(distributor))
+				return &UnexpectedError{
+					err:         renderErr,
+					namespace:   namespace,
+					conditionID: CreateConditionID,
+				}
+			}
+		}
+
 		// 1. try to fetch existing old resource
 		oldResource := &unstructured.Unstructured{}
 		oldResource.SetGroupVersionKind(resource.GetObjectKind().GroupVersionKind())
@@ -252,7 +292,7 @@ func (r *ReconcileResourceDistribution) distributeResource(distributor *appsv1al
 
 		// 2. if resource doesn't exist, create resource;
 		if getErr != nil && errors.IsNotFound(getErr) {
-			newResource := makeResourceObject(distributor, namespace, resource, resourceHashCode, nil)
+			newResource := makeResourceObject(distributor, namespace, rendered, hashCode, nil)
 			if createErr := r.Client.Create(context.TODO(), newResource.(client.Object)); createErr != nil {
 				klog.ErrorS(createErr, "Error occurred when creating resource in namespace", "namespace", namespace, "resourceDistribution", klog.KObj(distributor))
 				return &UnexpectedError{
@@ -265,19 +305,59 @@ func (r *ReconcileResourceDistribution) distributeResource(distributor *appsv1al
 			return nil
 		}
 
-		// 3. check conflict
+		// 3. check conflict, and handle it according to spec.conflictPolicy
+		adopting := false
 		if !isControlledByDistributor(oldResource, distributor) {
-			klog.InfoS("Conflict with existing resource in namespace", "resourceKind", resourceKind, "resourceName", resourceName, "namespace", namespace, "resourceDistribution", klog.KObj(distributor))
-			return &UnexpectedError{
-				err:         fmt.Errorf("conflict with existing resources because of the same namespace, group, version, kind and name"),
-				namespace:   namespace,
-				conditionID: ConflictConditionID,
+			switch {
+			case distributor.Spec.ConflictPolicy == appsv1alpha1.ResourceDistributionConflictPolicyIgnore:
+				klog.V(3).InfoS("Skipped namespace with conflicting resource", "resourceKind", resourceKind, "resourceName", resourceName, "namespace", namespace, "resourceDistribution", klog.KObj(distributor))
+				skippedLock.Lock()
+				defer skippedLock.Unlock()
+				skippedNamespaces = append(skippedNamespaces, appsv1alpha1.ResourceDistributionSkippedNamespace{
+					Name:   namespace,
+					Reason: appsv1alpha1.ResourceDistributionSkippedReasonConflict,
+				})
+				return nil
+			case distributor.Spec.ConflictPolicy == appsv1alpha1.ResourceDistributionConflictPolicyOverwrite && metav1.GetControllerOf(oldResource) == nil:
+				// the resource controlled by others, e.g., another ResourceDistribution, is never adopted
+				klog.InfoS("Adopting existing resource in namespace", "resourceKind", resourceKind, "resourceName", resourceName, "namespace", namespace, "resourceDistribution", klog.KObj(distributor))
+				adopting = true
+			default:
+				klog.InfoS("Conflict with existing resource in namespace", "resourceKind", resourceKind, "resourceName", resourceName, "namespace", namespace, "resourceDistribution", klog.KObj(distributor))
+				return &UnexpectedError{
+					err:         fmt.Errorf("conflict with existing resources because of the same namespace, group, version, kind and name"),
+					namespace:   namespace,
+					conditionID: ConflictConditionID,
+				}
 			}
 		}
 
-		// 4. check whether resource need to update
-		if needToUpdate(oldResource, utils.ConvertToUnstructured(resource)) {
-			newResource := makeResourceObject(distributor, namespace, resource, resourceHashCode, oldResource)
+		// 4. the type of Secret is immutable, so recreate the resource if its type changed
+		if secretTypeOf(oldResource) != secretTypeOf(rendered) {
+			if deleteErr := r.Client.Delete(context.TODO(), oldResource); deleteErr != nil && !errors.IsNotFound(deleteErr) {
+				klog.ErrorS(deleteErr, "Error occurred when deleting resource in namespace to change its type", "namespace", namespace, "resourceDistribution", klog.KObj(distributor))
+				return &UnexpectedError{
+					err:         deleteErr,
+					namespace:   namespace,
+					conditionID: DeleteConditionID,
+				}
+			}
+			newResource := makeResourceObject(distributor, namespace, rendered, hashCode, nil)
+			if createErr := r.Client.Create(context.TODO(), newResource.(client.Object)); createErr != nil {
+				klog.ErrorS(createErr, "Error occurred when recreating resource in namespace", "namespace", namespace, "resourceDistribution", klog.KObj(distributor))
+				return &UnexpectedError{
+					err:         createErr,
+					namespace:   namespace,
+					conditionID: CreateConditionID,
+				}
+			}
+			klog.V(3).InfoS("ResourceDistribution recreated resource in namespace", "resourceDistribution", klog.KObj(distributor), "resourceKind", resourceKind, "resourceName", resourceName, "namespace", namespace)
+			return nil
+		}
+
+		// 5. check whether resource need to update, the adopted resource is always updated to set the ownership
+		if adopting || needToUpdate(oldResource, rendered) {
+			newResource := makeResourceObject(distributor, namespace, rendered, hashCode, oldResource)
 			if updateErr := r.Client.Update(context.TODO(), newResource.(client.Object)); updateErr != nil {
 				klog.ErrorS(updateErr, "Error occurred when updating resource in namespace", "namespace", namespace, "resourceDistribution", klog.KObj(distributor))
 				return &UnexpectedError{
@@ -290,6 +370,8 @@ func (r *ReconcileResourceDistribution) distributeResource(distributor *appsv1al
 		}
 		return nil
 	})
+	sort.Slice(skippedNamespaces, func(i, j int) bool { return skippedNamespaces[i].Name < skippedNamespaces[j].Name })
+	return succeeded, skippedNamespaces, errList
 }
 
 func (r *ReconcileResourceDistribution) cleanResource(distributor *appsv1alpha1.ResourceDistribution,
@@ -18,7 +18,12 @@ package resourcedistribution
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -26,7 +31,11 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/clock"
+	testingclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	utils "github.com/openkruise/kruise/pkg/webhook/resourcedistribution/validating"
@@ -34,7 +43,7 @@ import (
 
 var (
 	scheme           *runtime.Scheme
-	reconcileHandler = &ReconcileResourceDistribution{}
+	reconcileHandler = &ReconcileResourceDistribution{clock: clock.RealClock{}}
 )
 
 func init() {
@@ -96,6 +105,317 @@ func TestDoReconcile(t *testing.T) {
 	}
 }
 
+func TestDoReconcileSecretTypes(t *testing.T) {
+	dockerConfigJSON := []byte(`{"auths":{"registry.example.com":{"auth":"dXNlcjpwYXNz"}}}`)
+	cases := []struct {
+		name       string
+		secretType corev1.SecretType
+		data       map[string][]byte
+	}{
+		{
+			name:       "tls secret",
+			secretType: corev1.SecretTypeTLS,
+			data:       map[string][]byte{corev1.TLSCertKey: []byte("cert"), corev1.TLSPrivateKeyKey: []byte("key")},
+		},
+		{
+			name:       "dockerconfigjson secret",
+			secretType: corev1.SecretTypeDockerConfigJson,
+			data:       map[string][]byte{corev1.DockerConfigJsonKey: dockerConfigJSON},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			raw, err := json.Marshal(&corev1.Secret{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+				ObjectMeta: metav1.ObjectMeta{Name: "test-secret-1"},
+				Type:       cs.secretType,
+				Data:       cs.data,
+			})
+			if err != nil {
+				t.Fatalf("failed to marshal secret, err %v", err)
+			}
+			distributor := buildResourceDistribution(runtime.RawExtension{Raw: raw})
+			// the existing Secret in ns-1 is Opaque, so it has to be recreated with the new type
+			makeClientEnvironment(distributor)
+
+			if _, err := reconcileHandler.doReconcile(distributor); err != nil {
+				t.Fatalf("failed to test doReconcile, err %v", err)
+			}
+			matched, _, err := listNamespacesForDistributor(reconcileHandler.Client, &distributor.Spec.Targets)
+			if err != nil {
+				t.Fatalf("failed to list namespaces for distributor, err %v", err)
+			}
+			resourceVersions := make(map[string]string, len(matched))
+			for _, namespace := range matched {
+				secret := &corev1.Secret{}
+				if err := reconcileHandler.Client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: "test-secret-1"}, secret); err != nil {
+					t.Fatalf("failed to get secret in namespace %s, err %v", namespace, err)
+				}
+				if secret.Type != cs.secretType {
+					t.Fatalf("expected type %s of secret in namespace %s, got %s", cs.secretType, namespace, secret.Type)
+				}
+				if !reflect.DeepEqual(secret.Data, cs.data) {
+					t.Fatalf("expected data %v of secret in namespace %s, got %v", cs.data, namespace, secret.Data)
+				}
+				resourceVersions[namespace] = secret.ResourceVersion
+			}
+
+			// reconciling again should leave the distributed secrets untouched
+			if _, err := reconcileHandler.doReconcile(distributor); err != nil {
+				t.Fatalf("failed to test doReconcile, err %v", err)
+			}
+			for _, namespace := range matched {
+				secret := &corev1.Secret{}
+				if err := reconcileHandler.Client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: "test-secret-1"}, secret); err != nil {
+					t.Fatalf("failed to get secret in namespace %s, err %v", namespace, err)
+				}
+				if secret.ResourceVersion != resourceVersions[namespace] {
+					t.Fatalf("unexpected update of secret in namespace %s", namespace)
+				}
+			}
+		})
+	}
+}
+
+func TestDoReconcileConflictPolicy(t *testing.T) {
+	cases := []struct {
+		name              string
+		conflictPolicy    appsv1alpha1.ResourceDistributionConflictPolicyType
+		controlledByOther bool
+		expectConflict    bool
+		expectSkipped     []appsv1alpha1.ResourceDistributionSkippedNamespace
+		expectAdopted     bool
+	}{
+		{
+			name:           "default policy fails",
+			expectConflict: true,
+		},
+		{
+			name:           "fail",
+			conflictPolicy: appsv1alpha1.ResourceDistributionConflictPolicyFail,
+			expectConflict: true,
+		},
+		{
+			name:           "ignore",
+			conflictPolicy: appsv1alpha1.ResourceDistributionConflictPolicyIgnore,
+			expectSkipped: []appsv1alpha1.ResourceDistributionSkippedNamespace{
+				{Name: "ns-2", Reason: appsv1alpha1.ResourceDistributionSkippedReasonConflict},
+			},
+		},
+		{
+			name:           "overwrite",
+			conflictPolicy: appsv1alpha1.ResourceDistributionConflictPolicyOverwrite,
+			expectAdopted:  true,
+		},
+		{
+			name:              "overwrite never adopts resource controlled by others",
+			conflictPolicy:    appsv1alpha1.ResourceDistributionConflictPolicyOverwrite,
+			controlledByOther: true,
+			expectConflict:    true,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			distributor := buildResourceDistributionWithSecret()
+			distributor.Spec.ConflictPolicy = cs.conflictPolicy
+			conflicting := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-secret-1", Namespace: "ns-2"},
+				Data:       map[string][]byte{"test": []byte("existing")},
+			}
+			if cs.controlledByOther {
+				conflicting.OwnerReferences = []metav1.OwnerReference{
+					*metav1.NewControllerRef(&appsv1alpha1.ResourceDistribution{ObjectMeta: metav1.ObjectMeta{Name: "other", UID: "other"}},
+						appsv1alpha1.GroupVersion.WithKind("ResourceDistribution")),
+				}
+			}
+			makeClientEnvironment(distributor, conflicting)
+
+			if _, err := reconcileHandler.doReconcile(distributor); err != nil {
+				t.Fatalf("failed to test doReconcile, err %v", err)
+			}
+
+			mice := &appsv1alpha1.ResourceDistribution{}
+			if err := reconcileHandler.Client.Get(context.TODO(), types.NamespacedName{Name: distributor.Name}, mice); err != nil {
+				t.Fatalf("failed to get distributor, err %v", err)
+			}
+			conflictCondition := mice.Status.Conditions[ConflictConditionID]
+			if hasConflict := conflictCondition.Status == appsv1alpha1.ResourceDistributionConditionTrue; hasConflict != cs.expectConflict {
+				t.Fatalf("expected conflict %v, got condition %v", cs.expectConflict, conflictCondition)
+			}
+			if cs.expectConflict != (mice.Status.Failed == 1) {
+				t.Fatalf("unexpected failed number %d", mice.Status.Failed)
+			}
+			if !reflect.DeepEqual(mice.Status.SkippedNamespaces, cs.expectSkipped) {
+				t.Fatalf("expected skipped namespaces %v, got %v", cs.expectSkipped, mice.Status.SkippedNamespaces)
+			}
+
+			secret := &corev1.Secret{}
+			if err := reconcileHandler.Client.Get(context.TODO(), types.NamespacedName{Namespace: "ns-2", Name: "test-secret-1"}, secret); err != nil {
+				t.Fatalf("failed to get secret, err %v", err)
+			}
+			if adopted := isControlledByDistributor(secret, distributor); adopted != cs.expectAdopted {
+				t.Fatalf("expected adopted %v, got owners %v", cs.expectAdopted, secret.OwnerReferences)
+			}
+			if cs.expectAdopted {
+				if string(secret.Data["test"]) == "existing" || secret.Annotations[utils.SourceResourceDistributionOfResource] != distributor.Name {
+					t.Fatalf("expected secret overwritten by distributor, got %v", secret)
+				}
+			} else if string(secret.Data["test"]) != "existing" {
+				t.Fatalf("expected secret untouched, got %v", secret)
+			}
+		})
+	}
+}
+
+func TestDoReconcileNamespaceBackoff(t *testing.T) {
+	distributor := buildResourceDistributionWithSecret()
+	var failing = true
+	createCalls := map[string]int{}
+	env := append(makeEnvironment(), distributor)
+	reconcileHandler.Client = fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(env...).
+		WithStatusSubresource(&appsv1alpha1.ResourceDistribution{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				createCalls[obj.GetNamespace()]++
+				if failing && obj.GetNamespace() == "ns-2" {
+					return fmt.Errorf("admission webhook denied the request")
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).Build()
+	fakeClock := testingclock.NewFakeClock(time.Now())
+	reconcileHandler.clock = fakeClock
+	defer func() { reconcileHandler.clock = clock.RealClock{} }()
+
+	reconcile := func(expectedRequeueAfter time.Duration) *appsv1alpha1.ResourceDistribution {
+		current := &appsv1alpha1.ResourceDistribution{}
+		if err := reconcileHandler.Client.Get(context.TODO(), types.NamespacedName{Name: distributor.Name}, current); err != nil {
+			t.Fatalf("failed to get distributor, err %v", err)
+		}
+		result, err := reconcileHandler.doReconcile(current)
+		if err != nil {
+			t.Fatalf("failed to test doReconcile, err %v", err)
+		}
+		if result.RequeueAfter < expectedRequeueAfter-time.Second || result.RequeueAfter > expectedRequeueAfter {
+			t.Fatalf("expected requeue after %v, got %v", expectedRequeueAfter, result.RequeueAfter)
+		}
+		if err := reconcileHandler.Client.Get(context.TODO(), types.NamespacedName{Name: distributor.Name}, current); err != nil {
+			t.Fatalf("failed to get distributor, err %v", err)
+		}
+		return current
+	}
+	checkFailed := func(status *appsv1alpha1.ResourceDistributionStatus, attempts int32) {
+		if len(status.FailedNamespaces) != 1 || status.FailedNamespaces[0].Name != "ns-2" || status.FailedNamespaces[0].Attempts != attempts ||
+			status.FailedNamespaces[0].ConditionType != appsv1alpha1.ResourceDistributionCreateResourceFailed ||
+			status.FailedNamespaces[0].LastError != "admission webhook denied the request" {
+			t.Fatalf("unexpected failed namespaces %v", status.FailedNamespaces)
+		}
+		if condition := status.Conditions[CreateConditionID]; !reflect.DeepEqual(condition.FailedNamespaces, []string{"ns-2"}) {
+			t.Fatalf("unexpected create condition %v", condition)
+		}
+		if status.Failed != 1 {
+			t.Fatalf("unexpected failed number %d", status.Failed)
+		}
+	}
+	resourceVersions := func() map[string]string {
+		versions := map[string]string{}
+		for _, namespace := range []string{"ns-1", "ns-3", "ns-5"} {
+			secret := &corev1.Secret{}
+			if err := reconcileHandler.Client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: "test-secret-1"}, secret); err != nil {
+				t.Fatalf("failed to get secret in %s, err %v", namespace, err)
+			}
+			versions[namespace] = secret.ResourceVersion
+		}
+		return versions
+	}
+
+	// 1. the failed namespace is retried with backoff
+	checkFailed(&reconcile(namespaceRetryInitialBackoff).Status, 1)
+	succeededVersions := resourceVersions()
+
+	// 2. the failed namespace is not retried before the backoff expires
+	checkFailed(&reconcile(namespaceRetryInitialBackoff).Status, 1)
+	if createCalls["ns-2"] != 1 {
+		t.Fatalf("expected ns-2 not retried, got %d creations", createCalls["ns-2"])
+	}
+
+	// 3. the backoff is doubled when it fails again
+	fakeClock.Step(namespaceRetryInitialBackoff)
+	checkFailed(&reconcile(2*namespaceRetryInitialBackoff).Status, 2)
+	if createCalls["ns-2"] != 2 {
+		t.Fatalf("expected ns-2 retried, got %d creations", createCalls["ns-2"])
+	}
+
+	// 4. the failed namespace recovers, and the succeeded ones are never rewritten
+	failing = false
+	fakeClock.Step(2 * namespaceRetryInitialBackoff)
+	if status := reconcile(0).Status; len(status.FailedNamespaces) != 0 || status.Failed != 0 {
+		t.Fatalf("expected no failed namespaces, got %v", status)
+	}
+	if versions := resourceVersions(); !reflect.DeepEqual(versions, succeededVersions) {
+		t.Fatalf("expected succeeded namespaces not rewritten, %v -> %v", succeededVersions, versions)
+	}
+	for _, namespace := range []string{"ns-1", "ns-3", "ns-5"} {
+		if createCalls[namespace] > 1 {
+			t.Fatalf("expected %s created at most once, got %d", namespace, createCalls[namespace])
+		}
+	}
+}
+
+func TestDoReconcileResourceTemplating(t *testing.T) {
+	const resourceJSON = `{
+		"apiVersion": "v1",
+		"binaryData": {"raw": "JHtOQU1FU1BBQ0V9"},
+		"data": {"endpoint": "svc.${NAMESPACE}.svc", "name": "{{ .Namespace }}"},
+		"kind": "ConfigMap",
+		"metadata": {"name": "test-configmap"}
+	}`
+	distributor := buildResourceDistribution(runtime.RawExtension{Raw: []byte(resourceJSON)})
+	distributor.Spec.ResourceTemplating = true
+	makeClientEnvironment(distributor)
+
+	if _, err := reconcileHandler.doReconcile(distributor); err != nil {
+		t.Fatalf("failed to test doReconcile, err %v", err)
+	}
+	matched, _, err := listNamespacesForDistributor(reconcileHandler.Client, &distributor.Spec.Targets)
+	if err != nil {
+		t.Fatalf("failed to list namespaces for distributor, err %v", err)
+	}
+	hashCodes := map[string]string{}
+	for _, namespace := range matched {
+		configMap := &corev1.ConfigMap{}
+		if err := reconcileHandler.Client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: "test-configmap"}, configMap); err != nil {
+			t.Fatalf("failed to get configmap in %s, err %v", namespace, err)
+		}
+		expected := map[string]string{"endpoint": "svc." + namespace + ".svc", "name": namespace}
+		if !reflect.DeepEqual(configMap.Data, expected) {
+			t.Fatalf("expected data %v in %s, got %v", expected, namespace, configMap.Data)
+		}
+		if string(configMap.BinaryData["raw"]) != "${NAMESPACE}" {
+			t.Fatalf("expected binaryData not rendered in %s, got %q", namespace, configMap.BinaryData["raw"])
+		}
+		hashCodes[configMap.Annotations[utils.ResourceHashCodeAnnotation]] = namespace
+	}
+	if len(hashCodes) != len(matched) {
+		t.Fatalf("expected hash codes computed on the rendered content per namespace, got %v", hashCodes)
+	}
+
+	// the copies are updated once the template is changed
+	distributor.Spec.Resource.Raw = []byte(strings.Replace(resourceJSON, "svc.${NAMESPACE}.svc", "${NAMESPACE}.example.com", 1))
+	if _, err := reconcileHandler.doReconcile(distributor); err != nil {
+		t.Fatalf("failed to test doReconcile, err %v", err)
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := reconcileHandler.Client.Get(context.TODO(), types.NamespacedName{Namespace: "ns-1", Name: "test-configmap"}, configMap); err != nil {
+		t.Fatalf("failed to get configmap, err %v", err)
+	}
+	if configMap.Data["endpoint"] != "ns-1.example.com" {
+		t.Fatalf("expected configmap updated, got %v", configMap.Data)
+	}
+}
+
 func buildResourceDistributionWithSecret() *appsv1alpha1.ResourceDistribution {
 	const resourceJSON = `{
 		"apiVersion": "v1",
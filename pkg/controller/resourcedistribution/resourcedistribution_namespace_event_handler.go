@@ -76,8 +76,8 @@ func (p *enqueueRequestForNamespace) updateNamespace(q workqueue.TypedRateLimiti
 	if !okOld || !okNew || reflect.DeepEqual(namespaceNew.ObjectMeta.Labels, namespaceOld.ObjectMeta.Labels) {
 		return
 	}
-	p.addNamespace(q, objNew, matchViaLabelSelector)
-	p.addNamespace(q, objOld, matchViaLabelSelector)
+	p.addNamespace(q, objNew, matchViaLabelSelectors)
+	p.addNamespace(q, objOld, matchViaLabelSelectors)
 }
 
 // getNamespaceMatchedResourceDistributions returns all matched ResourceDistributions via labelSelector
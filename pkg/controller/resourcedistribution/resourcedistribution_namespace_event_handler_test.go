@@ -74,6 +74,28 @@ func TestNamespaceEventHandler(t *testing.T) {
 	testEnqueueRequestForNamespaceDelete(namespaceDemo2, 0, t)
 }
 
+func TestNamespaceEventHandlerExcludedSelector(t *testing.T) {
+	distributor := buildResourceDistributionWithSecret()
+	distributor.Spec.Targets.NamespaceLabelSelector.MatchLabels["group"] = "three"
+	distributor.Spec.Targets.ExcludedNamespacesSelector = v1.LabelSelector{
+		MatchLabels: map[string]string{"distribution": "skip"},
+	}
+	env := append(makeEnvironment(), distributor)
+	enqueueHandler.reader = fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(env...).Build()
+
+	namespaceOld := &corev1.Namespace{
+		ObjectMeta: v1.ObjectMeta{
+			Name:   "ns-9",
+			Labels: map[string]string{"group": "nine"},
+		},
+	}
+	namespaceNew := namespaceOld.DeepCopy()
+	namespaceNew.Labels["distribution"] = "skip"
+	// gaining and losing the exclusion label both trigger re-distribution
+	testEnqueueRequestForNamespaceUpdate(namespaceOld, namespaceNew, 1, t)
+	testEnqueueRequestForNamespaceUpdate(namespaceNew, namespaceOld, 1, t)
+}
+
 func testEnqueueRequestForNamespaceCreate(namespace *corev1.Namespace, expectedNumber int, t *testing.T) {
 	createQ := workqueue.NewTypedRateLimitingQueue(
 		workqueue.DefaultTypedControllerRateLimiter[reconcile.Request](),
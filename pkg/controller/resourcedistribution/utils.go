@@ -20,7 +20,9 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"reflect"
+	"sort"
 	"sync"
 	"time"
 
@@ -50,6 +52,11 @@ const (
 	NotExistConditionID    = 5
 	NumberOfConditionTypes = 6
 	OperationSucceeded     = "Succeeded"
+
+	// namespaceRetryInitialBackoff is the initial delay to retry distributing resource to a failed namespace.
+	namespaceRetryInitialBackoff = 5 * time.Second
+	// namespaceRetryMaxBackoff bounds the delay to retry distributing resource to a failed namespace.
+	namespaceRetryMaxBackoff = 5 * time.Minute
 )
 
 // UnexpectedError is designed to store the information about .status.conditions when error occurs
@@ -79,7 +86,17 @@ func matchViaIncludedNamespaces(namespace *corev1.Namespace, distributor *appsv1
 
 // matchViaLabelSelector return true if namespace matches with target.NamespacesLabelSelectors
 func matchViaLabelSelector(namespace *corev1.Namespace, distributor *appsv1alpha1.ResourceDistribution) (bool, error) {
-	selector, err := util.ValidatedLabelSelectorAsSelector(&distributor.Spec.Targets.NamespaceLabelSelector)
+	return matchNamespaceLabelSelector(namespace, &distributor.Spec.Targets.NamespaceLabelSelector)
+}
+
+// matchViaExcludedLabelSelector return true if namespace matches with target.ExcludedNamespacesSelector
+func matchViaExcludedLabelSelector(namespace *corev1.Namespace, distributor *appsv1alpha1.ResourceDistribution) (bool, error) {
+	return matchNamespaceLabelSelector(namespace, &distributor.Spec.Targets.ExcludedNamespacesSelector)
+}
+
+// matchNamespaceLabelSelector return true if the labelSelector is not empty and matches with namespace
+func matchNamespaceLabelSelector(namespace *corev1.Namespace, labelSelector *metav1.LabelSelector) (bool, error) {
+	selector, err := util.ValidatedLabelSelectorAsSelector(labelSelector)
 	if err != nil {
 		return false, err
 	}
@@ -89,11 +106,30 @@ func matchViaLabelSelector(namespace *corev1.Namespace, distributor *appsv1alpha
 	return false, nil
 }
 
+// matchViaLabelSelectors return true if namespace matches with target.NamespacesLabelSelectors or target.ExcludedNamespacesSelector,
+// i.e., whether the label changes of namespace may change the result of targets
+func matchViaLabelSelectors(namespace *corev1.Namespace, distributor *appsv1alpha1.ResourceDistribution) (bool, error) {
+	if matched, err := matchViaLabelSelector(namespace, distributor); err != nil || matched {
+		return matched, err
+	}
+	return matchViaExcludedLabelSelector(namespace, distributor)
+}
+
+// isExcludedNamespace return true if namespace is excluded via target.ExcludedNamespaces or target.ExcludedNamespacesSelector
+func isExcludedNamespace(namespace *corev1.Namespace, targets *appsv1alpha1.ResourceDistributionTargets) (bool, error) {
+	for _, excluded := range targets.ExcludedNamespaces.List {
+		if utils.MatchNamespaceName(excluded.Name, namespace.Name) {
+			return true, nil
+		}
+	}
+	return matchNamespaceLabelSelector(namespace, &targets.ExcludedNamespacesSelector)
+}
+
 // matchViaTargets check whether Namespace matches ResourceDistribution via spec.targets
 func matchViaTargets(namespace *corev1.Namespace, distributor *appsv1alpha1.ResourceDistribution) (bool, error) {
 	targets := &distributor.Spec.Targets
-	if isInList(namespace.Name, targets.ExcludedNamespaces.List) {
-		return false, nil
+	if excluded, err := isExcludedNamespace(namespace, targets); err != nil || excluded {
+		return false, err
 	}
 	if targets.AllNamespaces {
 		return true, nil
@@ -143,6 +179,111 @@ func initConditionType(conditions []appsv1alpha1.ResourceDistributionCondition)
 	conditions[NotExistConditionID].Type = appsv1alpha1.ResourceDistributionNamespaceNotExists
 }
 
+// conditionIDOf return the ID of condition with the conditionType, and -1 if it is unknown
+func conditionIDOf(conditionType appsv1alpha1.ResourceDistributionConditionType) int {
+	conditions := make([]appsv1alpha1.ResourceDistributionCondition, NumberOfConditionTypes)
+	initConditionType(conditions)
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return i
+		}
+	}
+	return -1
+}
+
+// isRetriedWithBackoff return true if the distribution failed with the condition is retried with backoff, while
+// the conflicts and the namespaces not exist are retried when they are changed
+func isRetriedWithBackoff(conditionID int) bool {
+	switch conditionID {
+	case GetConditionID, CreateConditionID, UpdateConditionID, DeleteConditionID:
+		return true
+	}
+	return false
+}
+
+// splitNamespacesInBackoff splits the matched namespaces into the ones to distribute resource to, and the failed ones
+// waiting for retry. All namespaces are retried once spec of distributor is changed.
+func splitNamespacesInBackoff(distributor *appsv1alpha1.ResourceDistribution, namespaces []string, now time.Time) ([]string, []appsv1alpha1.ResourceDistributionFailedNamespace) {
+	if distributor.Status.ObservedGeneration != distributor.Generation || len(distributor.Status.FailedNamespaces) == 0 {
+		return namespaces, nil
+	}
+	waiting := make(map[string]*appsv1alpha1.ResourceDistributionFailedNamespace)
+	for i := range distributor.Status.FailedNamespaces {
+		failed := &distributor.Status.FailedNamespaces[i]
+		if isRetriedWithBackoff(conditionIDOf(failed.ConditionType)) && now.Before(failed.NextRetryTime.Time) {
+			waiting[failed.Name] = failed
+		}
+	}
+
+	var namespacesToDistribute []string
+	var waitingNamespaces []appsv1alpha1.ResourceDistributionFailedNamespace
+	for _, namespace := range namespaces {
+		if failed, ok := waiting[namespace]; ok {
+			waitingNamespaces = append(waitingNamespaces, *failed)
+		} else {
+			namespacesToDistribute = append(namespacesToDistribute, namespace)
+		}
+	}
+	return namespacesToDistribute, waitingNamespaces
+}
+
+// calculateFailedNamespaces returns the new .status.failedNamespaces, which keeps the waiting ones and backs off
+// the ones failed again, and the delay to retry the earliest one
+func calculateFailedNamespaces(distributor *appsv1alpha1.ResourceDistribution, waitingNamespaces []appsv1alpha1.ResourceDistributionFailedNamespace,
+	errList []*UnexpectedError, now time.Time) ([]appsv1alpha1.ResourceDistributionFailedNamespace, time.Duration) {
+
+	waiting := sets.NewString()
+	failedNamespaces := make([]appsv1alpha1.ResourceDistributionFailedNamespace, 0, len(errList))
+	for i := range waitingNamespaces {
+		waiting.Insert(waitingNamespaces[i].Name)
+		failedNamespaces = append(failedNamespaces, waitingNamespaces[i])
+	}
+	oldFailedNamespaces := make(map[string]*appsv1alpha1.ResourceDistributionFailedNamespace)
+	if distributor.Status.ObservedGeneration == distributor.Generation {
+		for i := range distributor.Status.FailedNamespaces {
+			oldFailedNamespaces[distributor.Status.FailedNamespaces[i].Name] = &distributor.Status.FailedNamespaces[i]
+		}
+	}
+
+	conditions := make([]appsv1alpha1.ResourceDistributionCondition, NumberOfConditionTypes)
+	initConditionType(conditions)
+	for _, unexpected := range errList {
+		if waiting.Has(unexpected.namespace) || !isRetriedWithBackoff(unexpected.conditionID) {
+			continue
+		}
+		attempts := int32(1)
+		if old, ok := oldFailedNamespaces[unexpected.namespace]; ok {
+			attempts = old.Attempts + 1
+		}
+		backoff := namespaceRetryMaxBackoff
+		if attempts <= 16 && namespaceRetryInitialBackoff<<(attempts-1) < namespaceRetryMaxBackoff {
+			backoff = namespaceRetryInitialBackoff << (attempts - 1)
+		}
+		failedNamespaces = append(failedNamespaces, appsv1alpha1.ResourceDistributionFailedNamespace{
+			Name:          unexpected.namespace,
+			ConditionType: conditions[unexpected.conditionID].Type,
+			LastError:     unexpected.err.Error(),
+			Attempts:      attempts,
+			NextRetryTime: metav1.NewTime(now.Add(backoff)).Rfc3339Copy(),
+		})
+	}
+	if len(failedNamespaces) == 0 {
+		return nil, 0
+	}
+
+	sort.Slice(failedNamespaces, func(i, j int) bool { return failedNamespaces[i].Name < failedNamespaces[j].Name })
+	requeueAfter := namespaceRetryMaxBackoff
+	for i := range failedNamespaces {
+		if delay := failedNamespaces[i].NextRetryTime.Sub(now); delay < requeueAfter {
+			requeueAfter = delay
+		}
+	}
+	if requeueAfter < time.Second {
+		requeueAfter = time.Second
+	}
+	return failedNamespaces, requeueAfter
+}
+
 // calculateNewStatus returns a complete new status to update distributor.status
 func calculateNewStatus(distributor *appsv1alpha1.ResourceDistribution, newConditions []appsv1alpha1.ResourceDistributionCondition, desired, succeeded int32) *appsv1alpha1.ResourceDistributionStatus {
 	status := &appsv1alpha1.ResourceDistributionStatus{}
@@ -207,6 +348,20 @@ func mergeMetadata(newResource, oldResource *unstructured.Unstructured) {
 		Union(sets.NewString(oldResource.GetFinalizers()...)).List())
 }
 
+// renderResource return the resource rendered for the namespace, and its hash code computed from the rendered
+// content, so that the copies are updated once their rendered content changes
+func renderResource(resource runtime.Object, namespace string) (*unstructured.Unstructured, string, error) {
+	rendered, err := utils.RenderResourceForNamespace(resource, namespace)
+	if err != nil {
+		return nil, "", err
+	}
+	content, err := json.Marshal(rendered)
+	if err != nil {
+		return nil, "", err
+	}
+	return utils.ConvertToUnstructured(rendered), hashResource(runtime.RawExtension{Raw: content}), nil
+}
+
 // makeResourceObject set some necessary information for resource before updating and creating
 func makeResourceObject(distributor *appsv1alpha1.ResourceDistribution, namespace string, resource runtime.Object, hashCode string, oldResource *unstructured.Unstructured) runtime.Object {
 	// convert to unstructured
@@ -240,6 +395,11 @@ func makeResourceObject(distributor *appsv1alpha1.ResourceDistribution, namespac
 	annotations[utils.SourceResourceDistributionOfResource] = distributor.Name
 	newResource.SetAnnotations(annotations)
 
+	// 4. set the type of Secret explicitly, so that the distributed copies keep the same type
+	if secretType := secretTypeOf(newResource); secretType != "" {
+		newResource.Object["type"] = string(secretType)
+	}
+
 	return newResource
 }
 
@@ -315,9 +475,24 @@ func listNamespacesForDistributor(handlerClient client.Client, targets *appsv1al
 		}
 	}
 
-	// 4. exclude the namespaces via target.ExcludedNamespaces
-	for _, namespace := range targets.ExcludedNamespaces.List {
-		matchedSet.Delete(namespace.Name)
+	// 4. exclude the namespaces via target.ExcludedNamespaces and target.ExcludedNamespacesSelector,
+	// the listed namespaces which do not exist are excluded by name only
+	existing := make(map[string]*corev1.Namespace, len(namespacesList.Items))
+	for i := range namespacesList.Items {
+		existing[namespacesList.Items[i].Name] = &namespacesList.Items[i]
+	}
+	for _, name := range matchedSet.List() {
+		namespace, ok := existing[name]
+		if !ok {
+			namespace = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		}
+		excluded, err := isExcludedNamespace(namespace, targets)
+		if err != nil {
+			return nil, nil, err
+		}
+		if excluded {
+			matchedSet.Delete(name)
+		}
 	}
 
 	// 5. remove matched namespaces from unmatched namespace set
@@ -333,9 +508,26 @@ func needToUpdate(old, new *unstructured.Unstructured) bool {
 	newObject["metadata"] = nil
 	oldObject["status"] = nil
 	newObject["status"] = nil
+	if secretType := secretTypeOf(new); secretType != "" {
+		oldObject["type"] = string(secretTypeOf(old))
+		newObject["type"] = string(secretType)
+	}
 	return !reflect.DeepEqual(oldObject, newObject)
 }
 
+// secretTypeOf returns the type of the resource if it is a Secret, defaulted to Opaque
+// as the apiserver does, or an empty string if the resource is not a Secret
+func secretTypeOf(resource *unstructured.Unstructured) corev1.SecretType {
+	if resource.GroupVersionKind().GroupKind() != corev1.SchemeGroupVersion.WithKind("Secret").GroupKind() {
+		return ""
+	}
+	secretType, _, _ := unstructured.NestedString(resource.Object, "type")
+	if secretType == "" {
+		return corev1.SecretTypeOpaque
+	}
+	return corev1.SecretType(secretType)
+}
+
 func isControlledByDistributor(resource metav1.Object, distributor *appsv1alpha1.ResourceDistribution) bool {
 	controller := metav1.GetControllerOf(resource)
 	if controller != nil && distributor != nil &&
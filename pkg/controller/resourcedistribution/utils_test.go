@@ -17,9 +17,13 @@ limitations under the License.
 package resourcedistribution
 
 import (
+	"reflect"
 	"testing"
 
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 func TestMatchFunctions(t *testing.T) {
@@ -72,3 +76,61 @@ func TestGetNamespaceForDistributor(t *testing.T) {
 		t.Fatalf("the number of expected unmatched namespace is %d, but got %d", 1, len(unmatched))
 	}
 }
+
+func TestGetNamespaceForDistributorWithExclusion(t *testing.T) {
+	distributor := buildResourceDistributionWithSecret()
+	distributor.Spec.Targets.ExcludedNamespaces.List = append(distributor.Spec.Targets.ExcludedNamespaces.List,
+		appsv1alpha1.ResourceDistributionNamespace{Name: "*-3"})
+	distributor.Spec.Targets.ExcludedNamespacesSelector = metav1.LabelSelector{
+		MatchLabels: map[string]string{"environment": "test"},
+	}
+	makeClientEnvironment(distributor)
+
+	matched, unmatched, err := listNamespacesForDistributor(reconcileHandler.Client, &distributor.Spec.Targets)
+	if err != nil {
+		t.Fatalf("failed to test getNamespaceForDistributor function, err %v", err)
+	}
+	if !reflect.DeepEqual(matched, []string{"ns-1", "ns-2"}) {
+		t.Fatalf("expected matched namespaces %v, but got %v", []string{"ns-1", "ns-2"}, matched)
+	}
+	if len(unmatched) != 3 {
+		t.Fatalf("the number of expected unmatched namespace is %d, but got %d", 3, len(unmatched))
+	}
+
+	namespace := &corev1.Namespace{}
+	namespace.SetName("ns-1")
+	namespace.SetLabels(map[string]string{"group": "one", "environment": "test"})
+	if ok, err := matchViaTargets(namespace, distributor); ok || err != nil {
+		t.Fatalf("failed to matchViaTargets, expected: unmatched, autual: matched, err %v", err)
+	}
+}
+
+func TestNeedToUpdateSecretType(t *testing.T) {
+	newSecret := func(secretType string) *unstructured.Unstructured {
+		secret := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   map[string]interface{}{"name": "test-secret"},
+			"data":       map[string]interface{}{"test": "dGVzdA=="},
+		}}
+		if secretType != "" {
+			secret.Object["type"] = secretType
+		}
+		return secret
+	}
+
+	cases := []struct {
+		name     string
+		old, new *unstructured.Unstructured
+		expected bool
+	}{
+		{"type defaulted to Opaque", newSecret(string(corev1.SecretTypeOpaque)), newSecret(""), false},
+		{"same type", newSecret(string(corev1.SecretTypeTLS)), newSecret(string(corev1.SecretTypeTLS)), false},
+		{"type changed", newSecret(string(corev1.SecretTypeOpaque)), newSecret(string(corev1.SecretTypeDockerConfigJson)), true},
+	}
+	for _, cs := range cases {
+		if got := needToUpdate(cs.old, cs.new); got != cs.expected {
+			t.Fatalf("%s: expected needToUpdate %v, got %v", cs.name, cs.expected, got)
+		}
+	}
+}
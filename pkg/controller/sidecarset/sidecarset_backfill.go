@@ -0,0 +1,361 @@
+/*
+Copyright 2026 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecarset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
+	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
+	"github.com/openkruise/kruise/pkg/util/expectations"
+)
+
+// backfillResyncPeriod is the interval to reconcile the SidecarSet while backfill is running,
+// because the pods which are not injected do not trigger the SidecarSet by pod events.
+const backfillResyncPeriod = 10 * time.Second
+
+// backfillExpectations records the pods evicted by backfill that have not been observed terminating in the cache,
+// keyed by the SidecarSet name. They are counted as unavailable pods of their workloads.
+var backfillExpectations = expectations.NewScaleExpectations()
+
+// backfillWorkload is the backfill state of a workload calculated in a round.
+type backfillWorkload struct {
+	status     appsv1beta1.SidecarSetBackfillWorkloadStatus
+	activePods int
+	// unavailable is the number of the pods which are not ready, terminating, or evicted but not observed yet.
+	unavailable int
+	// uninjected is the pods to be evicted, excluding the ones the sidecar containers would not be injected into.
+	uninjected []*corev1.Pod
+}
+
+// backfillRecreatingOwners are the controllers which recreate their pods after eviction. The pods controlled by others
+// are never evicted by backfill, e.g. the pods of Jobs running to completion or the mirror pods owned by Nodes.
+var backfillRecreatingOwners = map[schema.GroupKind]bool{
+	{Group: "", Kind: "ReplicationController"}:     true,
+	{Group: "apps", Kind: "ReplicaSet"}:            true,
+	{Group: "apps", Kind: "StatefulSet"}:           true,
+	{Group: "apps", Kind: "DaemonSet"}:             true,
+	{Group: "apps.kruise.io", Kind: "CloneSet"}:    true,
+	{Group: "apps.kruise.io", Kind: "StatefulSet"}: true,
+	{Group: "apps.kruise.io", Kind: "DaemonSet"}:   true,
+}
+
+// backfillUninjectedPods evicts the matched pods which are not injected with the sidecarSet, so that their
+// workloads recreate them with the sidecar containers injected. The pods are grouped by their controllers and
+// at most maxUnavailablePerWorkload pods of each workload are unavailable at the same time.
+// It records the progress into status and returns the duration after which the sidecarSet should be reconciled.
+func (p *Processor) backfillUninjectedPods(sidecarSet *appsv1beta1.SidecarSet, status *appsv1beta1.SidecarSetStatus) (time.Duration, error) {
+	strategy := sidecarSet.Spec.InjectionStrategy.Backfill
+	if strategy == nil || !strategy.Enabled {
+		backfillExpectations.DeleteExpectations(sidecarSet.Name)
+		return 0, nil
+	}
+
+	selectedPods, err := p.getSelectedPodsForSidecarSet(sidecarSet)
+	if err != nil {
+		return 0, err
+	}
+	canaryPods, err := p.getCanaryInjectedPods(sidecarSet, selectedPods)
+	if err != nil {
+		return 0, err
+	}
+	workloads := groupPodsForBackfill(sidecarSet, selectedPods, canaryPods)
+
+	deadlineExceeded := strategy.Deadline != nil && !time.Now().Before(strategy.Deadline.Time)
+	// the recreated pods will not be injected if the injection is paused
+	evictable := !deadlineExceeded && !sidecarSet.Spec.InjectionStrategy.Paused
+	maxUnavailable := intstr.FromInt32(1)
+	if strategy.MaxUnavailablePerWorkload != nil {
+		maxUnavailable = *strategy.MaxUnavailablePerWorkload
+	}
+
+	var uninjectedPods int32
+	var evictErr error
+	backfillStatus := &appsv1beta1.SidecarSetBackfillStatus{}
+	for _, w := range workloads {
+		if evictable && evictErr == nil && len(w.uninjected) > 0 {
+			evictErr = p.evictWorkloadPods(sidecarSet, w, maxUnavailable)
+		}
+		w.status.UninjectedPods = int32(len(w.uninjected)) + w.status.SkippedPods
+		uninjectedPods += int32(len(w.uninjected))
+		if w.status.UninjectedPods > 0 || w.status.EvictedPods > 0 {
+			backfillStatus.Workloads = append(backfillStatus.Workloads, w.status)
+		}
+	}
+
+	var requeueAfter time.Duration
+	switch {
+	case uninjectedPods == 0:
+		backfillStatus.Phase = appsv1beta1.SidecarSetBackfillCompleted
+	case deadlineExceeded:
+		backfillStatus.Phase = appsv1beta1.SidecarSetBackfillDeadlineExceeded
+	default:
+		backfillStatus.Phase = appsv1beta1.SidecarSetBackfillRunning
+		if evictable {
+			requeueAfter = backfillResyncPeriod
+		}
+		if strategy.Deadline != nil {
+			if untilDeadline := time.Until(strategy.Deadline.Time); requeueAfter == 0 || untilDeadline < requeueAfter {
+				requeueAfter = untilDeadline
+			}
+		}
+	}
+	if deadlineExceeded {
+		backfillExpectations.DeleteExpectations(sidecarSet.Name)
+	}
+	status.BackfillStatus = backfillStatus
+	return requeueAfter, evictErr
+}
+
+// groupPodsForBackfill groups the selected pods by their controllers, the pods whose controllers will not recreate
+// them are ignored. The uninjected pods which the sidecar containers would not be injected into when recreated,
+// including the ones injected with the canary sidecarSets instead, are counted as skipped instead of being evicted.
+// The result is sorted by the namespace, kind and name of the workloads.
+func groupPodsForBackfill(sidecarSet *appsv1beta1.SidecarSet, pods []*corev1.Pod, canaryPods map[string]string) []*backfillWorkload {
+	evictedPods := map[string]int32{}
+	if sidecarSet.Status.BackfillStatus != nil {
+		for _, w := range sidecarSet.Status.BackfillStatus.Workloads {
+			evictedPods[fmt.Sprintf("%s/%s/%s", w.Namespace, w.Kind, w.Name)] = w.EvictedPods
+		}
+	}
+	evicting := backfillExpectations.GetExpectations(sidecarSet.Name)[expectations.Delete]
+
+	workloadMap := map[string]*backfillWorkload{}
+	existingPods := sets.NewString()
+	for _, pod := range pods {
+		podKey := fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)
+		owner := metav1.GetControllerOf(pod)
+		if !isRecreatingOwner(owner) {
+			continue
+		}
+		key := fmt.Sprintf("%s/%s/%s", pod.Namespace, owner.Kind, owner.Name)
+		w := workloadMap[key]
+		if w == nil {
+			w = &backfillWorkload{status: appsv1beta1.SidecarSetBackfillWorkloadStatus{
+				APIVersion:  owner.APIVersion,
+				Kind:        owner.Kind,
+				Name:        owner.Name,
+				Namespace:   pod.Namespace,
+				EvictedPods: evictedPods[key],
+			}}
+			workloadMap[key] = w
+		}
+
+		if !sidecarcontrol.IsActivePod(pod) {
+			if pod.DeletionTimestamp != nil && pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+				w.unavailable++
+			}
+			continue
+		}
+		existingPods.Insert(podKey)
+		w.activePods++
+		if evicting.Has(podKey) {
+			// evicted, but the cache has not observed it terminating
+			w.unavailable++
+			continue
+		}
+		if !podutil.IsPodReady(pod) {
+			w.unavailable++
+		}
+		if !sidecarcontrol.IsPodInjectedSidecarSet(pod, sidecarSet) {
+			reason := getBackfillSkippedReason(sidecarSet, pod)
+			if canary, ok := canaryPods[podKey]; ok {
+				reason = fmt.Sprintf("injected with canary sidecarSet %s instead", canary)
+			}
+			if reason != "" {
+				klog.V(4).InfoS("SidecarSet skipped pod for backfill", "sidecarSet", klog.KObj(sidecarSet), "pod", klog.KObj(pod), "reason", reason)
+				w.status.SkippedPods++
+				continue
+			}
+			w.uninjected = append(w.uninjected, pod)
+		}
+	}
+	for _, podKey := range evicting.List() {
+		if !existingPods.Has(podKey) {
+			backfillExpectations.ObserveScale(sidecarSet.Name, expectations.Delete, podKey)
+		}
+	}
+
+	workloads := make([]*backfillWorkload, 0, len(workloadMap))
+	for _, w := range workloadMap {
+		// evict the pods which are not ready first, then the older ones
+		sort.SliceStable(w.uninjected, func(i, j int) bool {
+			iReady, jReady := podutil.IsPodReady(w.uninjected[i]), podutil.IsPodReady(w.uninjected[j])
+			if iReady != jReady {
+				return !iReady
+			}
+			return w.uninjected[i].CreationTimestamp.Before(&w.uninjected[j].CreationTimestamp)
+		})
+		workloads = append(workloads, w)
+	}
+	sort.Slice(workloads, func(i, j int) bool {
+		a, b := workloads[i].status, workloads[j].status
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return workloads
+}
+
+func isRecreatingOwner(owner *metav1.OwnerReference) bool {
+	if owner == nil {
+		return false
+	}
+	gv, err := schema.ParseGroupVersion(owner.APIVersion)
+	if err != nil {
+		return false
+	}
+	return backfillRecreatingOwners[schema.GroupKind{Group: gv.Group, Kind: owner.Kind}]
+}
+
+// getCanaryInjectedPods returns the uninjected pods matched by the canary sidecarSets of the sidecarSet, keyed by
+// namespace/name with the name of the canary sidecarSet. The pod webhook injects the canary sidecarSet instead of
+// the base one into them, so evicting them never gets the sidecarSet injected.
+func (p *Processor) getCanaryInjectedPods(sidecarSet *appsv1beta1.SidecarSet, pods []*corev1.Pod) (map[string]string, error) {
+	sidecarSetList := &appsv1beta1.SidecarSetList{}
+	if err := p.Client.List(context.TODO(), sidecarSetList); err != nil {
+		return nil, err
+	}
+	var canaries []*appsv1beta1.SidecarSet
+	for i := range sidecarSetList.Items {
+		canary := &sidecarSetList.Items[i]
+		if isCanary, base := sidecarcontrol.IsCanarySidecarSet(canary); !isCanary || base != sidecarSet.Name {
+			continue
+		}
+		if canary.Spec.InjectionStrategy.Paused || !sidecarcontrol.New(canary).IsActiveSidecarSet() {
+			continue
+		}
+		canaries = append(canaries, canary)
+	}
+	if len(canaries) == 0 {
+		return nil, nil
+	}
+
+	canaryPods := map[string]string{}
+	for _, pod := range pods {
+		if !sidecarcontrol.IsActivePod(pod) || sidecarcontrol.IsPodInjectedSidecarSet(pod, sidecarSet) {
+			continue
+		}
+		for _, canary := range canaries {
+			matched, err := sidecarcontrol.PodMatchedSidecarSet(p.Client, pod, canary)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				canaryPods[fmt.Sprintf("%s/%s", pod.Namespace, pod.Name)] = canary.Name
+				break
+			}
+		}
+	}
+	return canaryPods, nil
+}
+
+// getBackfillSkippedReason returns why the sidecar containers would not be injected into the pod when it is
+// recreated, or empty if they would be injected.
+func getBackfillSkippedReason(sidecarSet *appsv1beta1.SidecarSet, pod *corev1.Pod) string {
+	if skippedStr := pod.Annotations[sidecarcontrol.SidecarSetSkippedListAnnotation]; len(skippedStr) > 0 &&
+		sets.NewString(strings.Split(skippedStr, ",")...).Has(sidecarSet.Name) {
+		return "skipped by footprint limits"
+	}
+	if decisionsStr := pod.Annotations[sidecarcontrol.SidecarSetQoSDecisionAnnotation]; len(decisionsStr) > 0 {
+		decisions := make(map[string]appsv1beta1.SidecarSetQoSPolicyType)
+		if err := json.Unmarshal([]byte(decisionsStr), &decisions); err == nil &&
+			decisions[sidecarSet.Name] == appsv1beta1.SidecarSetQoSPolicySkipInjection {
+			return "skipped by qosPolicy"
+		}
+	}
+	if policy := sidecarSet.Spec.InjectionStrategy.QoSPolicy; policy != nil && policy.Type != appsv1beta1.SidecarSetQoSPolicyInheritLimits &&
+		v1qos.GetPodQOS(pod) == corev1.PodQOSGuaranteed && !sidecarcontrol.IsPodGuaranteedWithSidecarSet(pod, sidecarSet) {
+		return fmt.Sprintf("injection would downgrade the Guaranteed pod, which is %s by qosPolicy", policy.Type)
+	}
+
+	containers := sets.NewString()
+	for i := range pod.Spec.InitContainers {
+		containers.Insert(pod.Spec.InitContainers[i].Name)
+	}
+	for i := range pod.Spec.Containers {
+		containers.Insert(pod.Spec.Containers[i].Name)
+	}
+	for i := range sidecarSet.Spec.InitContainers {
+		if name := sidecarSet.Spec.InitContainers[i].Name; containers.Has(name) {
+			return fmt.Sprintf("container %s conflicts with the pod", name)
+		}
+	}
+	for i := range sidecarSet.Spec.Containers {
+		if name := sidecarSet.Spec.Containers[i].Name; containers.Has(name) {
+			return fmt.Sprintf("container %s conflicts with the pod", name)
+		}
+	}
+	return ""
+}
+
+// evictWorkloadPods evicts the uninjected pods of the workload within the unavailable budget. An eviction rejected
+// by the disruption budgets stops the eviction of this workload in this round, and its reason is recorded in status.
+func (p *Processor) evictWorkloadPods(sidecarSet *appsv1beta1.SidecarSet, w *backfillWorkload, maxUnavailable intstr.IntOrString) error {
+	limit, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, w.activePods, true)
+	if err != nil {
+		return err
+	}
+	w.status.Message = ""
+	for i := 0; i < limit-w.unavailable && len(w.uninjected) > 0; i++ {
+		pod := w.uninjected[0]
+		eviction := &policyv1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		}
+		err = p.Client.SubResource("eviction").Create(context.TODO(), pod.DeepCopy(), eviction)
+		if err != nil && !errors.IsNotFound(err) {
+			if errors.IsTooManyRequests(err) || errors.IsForbidden(err) {
+				w.status.Message = fmt.Sprintf("eviction of pod %s was rejected: %s", pod.Name, err.Error())
+				p.recorder.Eventf(sidecarSet, corev1.EventTypeWarning, "BackfillEvictionRejected",
+					"Eviction of pod %s/%s was rejected: %v", pod.Namespace, pod.Name, err)
+				return nil
+			}
+			klog.ErrorS(err, "SidecarSet failed to evict pod for backfill", "sidecarSet", klog.KObj(sidecarSet), "pod", klog.KObj(pod))
+			return err
+		}
+
+		w.uninjected = w.uninjected[1:]
+		if err == nil {
+			backfillExpectations.ExpectScale(sidecarSet.Name, expectations.Delete, fmt.Sprintf("%s/%s", pod.Namespace, pod.Name))
+			w.status.EvictedPods++
+			p.recorder.Eventf(sidecarSet, corev1.EventTypeNormal, "BackfillEvictedPod",
+				"Evicted pod %s/%s to inject sidecar containers", pod.Namespace, pod.Name)
+			klog.V(3).InfoS("SidecarSet evicted pod for backfill", "sidecarSet", klog.KObj(sidecarSet), "pod", klog.KObj(pod))
+		}
+	}
+	return nil
+}
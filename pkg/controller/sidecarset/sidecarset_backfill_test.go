@@ -0,0 +1,523 @@
+/*
+Copyright 2026 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecarset
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
+)
+
+const backfillTestFinalizer = "test.kruise.io/hold"
+
+func newBackfillSidecarSet(maxUnavailable int32) *appsv1beta1.SidecarSet {
+	sidecarSet := sidecarSetDemo.DeepCopy()
+	sidecarSet.Spec.UpdateStrategy.Type = appsv1beta1.NotUpdateSidecarSetStrategyType
+	sidecarSet.Spec.InjectionStrategy.Backfill = &appsv1beta1.SidecarSetBackfillStrategy{
+		Enabled:                   true,
+		MaxUnavailablePerWorkload: &intstr.IntOrString{Type: intstr.Int, IntVal: maxUnavailable},
+	}
+	return sidecarSet
+}
+
+// newBackfillPod returns a pod controlled by the ReplicaSet, which is terminating for a while after eviction.
+func newBackfillPod(name, owner string, injected, ready bool, age time.Duration) *corev1.Pod {
+	pod := podDemo.DeepCopy()
+	pod.Name = name
+	pod.UID = types.UID(name)
+	pod.CreationTimestamp = metav1.NewTime(time.Now().Add(-age))
+	pod.Finalizers = []string{backfillTestFinalizer}
+	if owner != "" {
+		pod.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "apps/v1",
+			Kind:       "ReplicaSet",
+			Name:       owner,
+			UID:        types.UID(owner),
+			Controller: func() *bool { b := true; return &b }(),
+		}}
+	}
+	if !injected {
+		pod.Annotations = map[string]string{}
+		pod.Spec.Containers = pod.Spec.Containers[:1]
+		pod.Status.ContainerStatuses = pod.Status.ContainerStatuses[:1]
+	}
+	if !ready {
+		pod.Status.Conditions[0].Status = corev1.ConditionFalse
+	}
+	return pod
+}
+
+type backfillTestEnv struct {
+	client    client.Client
+	evicted   []string
+	reconcile func(t *testing.T) reconcile.Result
+}
+
+// newBackfillTestEnv builds a reconciler on a fake client with the other objects, evictFn decides the result of
+// each eviction, and the eviction goes through the fake client if evictFn returns nil.
+func newBackfillTestEnv(sidecarSet *appsv1beta1.SidecarSet, pods []*corev1.Pod, evictFn func(pod *corev1.Pod) error, others ...client.Object) *backfillTestEnv {
+	env := &backfillTestEnv{}
+	objects := append([]client.Object{sidecarSet}, others...)
+	for _, pod := range pods {
+		objects = append(objects, pod)
+	}
+	env.client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).
+		WithStatusSubresource(&appsv1beta1.SidecarSet{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceCreate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+				pod := obj.(*corev1.Pod)
+				if evictFn != nil {
+					if err := evictFn(pod); err != nil {
+						return err
+					}
+				}
+				env.evicted = append(env.evicted, pod.Name)
+				return c.SubResource(subResourceName).Create(ctx, obj, subResource, opts...)
+			},
+		}).Build()
+	reconciler := ReconcileSidecarSet{
+		Client:    env.client,
+		processor: NewSidecarSetProcessor(env.client, record.NewFakeRecorder(100)),
+	}
+	env.reconcile = func(t *testing.T) reconcile.Result {
+		result, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: sidecarSet.Name}})
+		if err != nil {
+			t.Fatalf("reconcile failed, err: %v", err)
+		}
+		return result
+	}
+	return env
+}
+
+func (env *backfillTestEnv) takeEvicted() []string {
+	evicted := env.evicted
+	env.evicted = nil
+	return evicted
+}
+
+func (env *backfillTestEnv) backfillStatus(t *testing.T, sidecarSet *appsv1beta1.SidecarSet) *appsv1beta1.SidecarSetBackfillStatus {
+	latest, err := getLatestSidecarSet(env.client, sidecarSet)
+	if err != nil {
+		t.Fatalf("get latest sidecarSet failed, err: %v", err)
+	}
+	return latest.Status.BackfillStatus
+}
+
+// releasePod removes the finalizer of the terminating pod so that it is deleted.
+func (env *backfillTestEnv) releasePod(t *testing.T, name string) {
+	pod := &corev1.Pod{}
+	if err := env.client.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: name}, pod); err != nil {
+		t.Fatalf("get pod %s failed, err: %v", name, err)
+	}
+	pod.Finalizers = nil
+	if err := env.client.Update(context.TODO(), pod); err != nil {
+		t.Fatalf("release pod %s failed, err: %v", name, err)
+	}
+}
+
+func expectEvicted(t *testing.T, round string, got []string, expected ...string) {
+	if strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Fatalf("%s: expected evicted pods %v, got %v", round, expected, got)
+	}
+}
+
+func TestBackfillRateLimit(t *testing.T) {
+	defer backfillExpectations.DeleteExpectations(sidecarSetDemo.Name)
+	sidecarSet := newBackfillSidecarSet(2)
+	pods := []*corev1.Pod{
+		newBackfillPod("rs-a-1", "rs-a", false, true, 5*time.Hour),
+		newBackfillPod("rs-a-2", "rs-a", false, true, 4*time.Hour),
+		newBackfillPod("rs-a-3", "rs-a", false, true, 3*time.Hour),
+		newBackfillPod("rs-a-4", "rs-a", false, false, 2*time.Hour),
+		newBackfillPod("rs-b-1", "rs-b", false, true, time.Hour),
+		newBackfillPod("rs-b-2", "rs-b", true, false, time.Hour),
+		newBackfillPod("bare-pod", "", false, true, time.Hour),
+	}
+	env := newBackfillTestEnv(sidecarSet, pods, nil)
+
+	// round 1: the pods not ready count against the budget and are evicted first, the bare pod is never evicted
+	if result := env.reconcile(t); result.RequeueAfter != backfillResyncPeriod {
+		t.Fatalf("expected requeue after %v, got %v", backfillResyncPeriod, result.RequeueAfter)
+	}
+	expectEvicted(t, "round 1", env.takeEvicted(), "rs-a-4", "rs-b-1")
+	status := env.backfillStatus(t, sidecarSet)
+	expected := []appsv1beta1.SidecarSetBackfillWorkloadStatus{
+		{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "rs-a", Namespace: "default", UninjectedPods: 3, EvictedPods: 1},
+		{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "rs-b", Namespace: "default", UninjectedPods: 0, EvictedPods: 1},
+	}
+	if status == nil || status.Phase != appsv1beta1.SidecarSetBackfillRunning || fmt.Sprint(status.Workloads) != fmt.Sprint(expected) {
+		t.Fatalf("expected running backfill status with workloads %+v, got %+v", expected, status)
+	}
+
+	// round 2: the evicted pod is terminating, the oldest pod is evicted
+	env.reconcile(t)
+	expectEvicted(t, "round 2", env.takeEvicted(), "rs-a-1")
+
+	// round 3: two evicted pods are terminating, no more pods of rs-a are evicted
+	env.reconcile(t)
+	expectEvicted(t, "round 3", env.takeEvicted())
+
+	// round 4: one evicted pod is gone and recreated with sidecar injected but not ready
+	env.releasePod(t, "rs-a-4")
+	if err := env.client.Create(context.TODO(), newBackfillPod("rs-a-5", "rs-a", true, false, 0)); err != nil {
+		t.Fatal(err)
+	}
+	env.reconcile(t)
+	expectEvicted(t, "round 4", env.takeEvicted())
+
+	// round 5: the other evicted pod is gone and the recreated pod becomes ready
+	env.releasePod(t, "rs-a-1")
+	recreated := &corev1.Pod{}
+	if err := env.client.Get(context.TODO(), types.NamespacedName{Namespace: "default", Name: "rs-a-5"}, recreated); err != nil {
+		t.Fatal(err)
+	}
+	recreated.Status.Conditions[0].Status = corev1.ConditionTrue
+	if err := env.client.Status().Update(context.TODO(), recreated); err != nil {
+		t.Fatal(err)
+	}
+	env.reconcile(t)
+	expectEvicted(t, "round 5", env.takeEvicted(), "rs-a-2", "rs-a-3")
+
+	// round 6: all pods controlled by workloads are backfilled
+	env.releasePod(t, "rs-a-2")
+	env.releasePod(t, "rs-a-3")
+	if result := env.reconcile(t); result.RequeueAfter != 0 {
+		t.Fatalf("expected no requeue after backfill completed, got %v", result.RequeueAfter)
+	}
+	expectEvicted(t, "round 6", env.takeEvicted())
+	status = env.backfillStatus(t, sidecarSet)
+	if status.Phase != appsv1beta1.SidecarSetBackfillCompleted || len(status.Workloads) != 2 || status.Workloads[0].EvictedPods != 4 {
+		t.Fatalf("expected completed backfill status, got %+v", status)
+	}
+}
+
+func TestBackfillEvictionNotObservedInCache(t *testing.T) {
+	defer backfillExpectations.DeleteExpectations(sidecarSetDemo.Name)
+	sidecarSet := newBackfillSidecarSet(1)
+	pods := []*corev1.Pod{
+		newBackfillPod("rs-a-1", "rs-a", false, true, 2*time.Hour),
+		newBackfillPod("rs-a-2", "rs-a", false, true, time.Hour),
+	}
+	// the evictions succeed, but the evicted pods are not updated in the cache yet
+	var evicted []string
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sidecarSet, pods[0], pods[1]).
+		WithStatusSubresource(&appsv1beta1.SidecarSet{}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceCreate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+				evicted = append(evicted, obj.GetName())
+				return nil
+			},
+		}).Build()
+	processor := NewSidecarSetProcessor(fakeClient, record.NewFakeRecorder(100))
+
+	for i := 0; i < 3; i++ {
+		if _, err := processor.UpdateSidecarSet(sidecarSet.DeepCopy()); err != nil {
+			t.Fatalf("update sidecarSet failed, err: %v", err)
+		}
+	}
+	expectEvicted(t, "evicted but not observed", evicted, "rs-a-1")
+}
+
+func TestBackfillEvictionRejected(t *testing.T) {
+	defer backfillExpectations.DeleteExpectations(sidecarSetDemo.Name)
+	sidecarSet := newBackfillSidecarSet(2)
+	pods := []*corev1.Pod{
+		newBackfillPod("rs-a-1", "rs-a", false, true, 3*time.Hour),
+		newBackfillPod("rs-a-2", "rs-a", false, true, 2*time.Hour),
+		newBackfillPod("rs-b-1", "rs-b", false, true, time.Hour),
+	}
+	rejected := map[string]error{
+		"rs-a-1": errors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 10),
+	}
+	env := newBackfillTestEnv(sidecarSet, pods, func(pod *corev1.Pod) error {
+		return rejected[pod.Name]
+	})
+
+	// the rejected eviction stops the eviction of rs-a, but not rs-b
+	if result := env.reconcile(t); result.RequeueAfter != backfillResyncPeriod {
+		t.Fatalf("expected requeue after %v, got %v", backfillResyncPeriod, result.RequeueAfter)
+	}
+	expectEvicted(t, "rejected by pdb", env.takeEvicted(), "rs-b-1")
+	status := env.backfillStatus(t, sidecarSet)
+	if status.Phase != appsv1beta1.SidecarSetBackfillRunning || len(status.Workloads) != 2 {
+		t.Fatalf("unexpected backfill status %+v", status)
+	}
+	if w := status.Workloads[0]; w.Name != "rs-a" || w.UninjectedPods != 2 || w.EvictedPods != 0 || !strings.Contains(w.Message, "disruption budget") {
+		t.Fatalf("expected eviction of rs-a rejected by pdb, got %+v", w)
+	}
+
+	// rejected by PodUnavailableBudget webhook
+	rejected["rs-a-1"] = errors.NewForbidden(schema.GroupResource{Resource: "pods"}, "rs-a-1", fmt.Errorf("pub unavailable allowed is negative"))
+	env.reconcile(t)
+	expectEvicted(t, "rejected by pub", env.takeEvicted())
+	if w := env.backfillStatus(t, sidecarSet).Workloads[0]; !strings.Contains(w.Message, "pub unavailable allowed is negative") {
+		t.Fatalf("expected eviction of rs-a rejected by pub, got %+v", w)
+	}
+
+	// the budget allows eviction again, and the message is cleared
+	delete(rejected, "rs-a-1")
+	env.reconcile(t)
+	expectEvicted(t, "allowed", env.takeEvicted(), "rs-a-1", "rs-a-2")
+	if w := env.backfillStatus(t, sidecarSet).Workloads[0]; w.Message != "" || w.EvictedPods != 2 {
+		t.Fatalf("expected rs-a evicted, got %+v", w)
+	}
+
+	// unexpected errors are returned, and the progress is still recorded
+	env.releasePod(t, "rs-a-1")
+	env.releasePod(t, "rs-a-2")
+	env.releasePod(t, "rs-b-1")
+	if err := env.client.Create(context.TODO(), newBackfillPod("rs-c-1", "rs-c", false, true, time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	rejected["rs-c-1"] = errors.NewInternalError(fmt.Errorf("etcdserver: request timed out"))
+	reconciler := ReconcileSidecarSet{Client: env.client, processor: NewSidecarSetProcessor(env.client, record.NewFakeRecorder(100))}
+	if _, err := reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: sidecarSet.Name}}); err == nil {
+		t.Fatalf("expected error of eviction returned")
+	}
+	if status := env.backfillStatus(t, sidecarSet); len(status.Workloads) != 1 || status.Workloads[0].Name != "rs-c" || status.Workloads[0].UninjectedPods != 1 {
+		t.Fatalf("expected rs-c recorded in status, got %+v", status)
+	}
+}
+
+func TestBackfillStop(t *testing.T) {
+	cases := []struct {
+		name           string
+		modify         func(sidecarSet *appsv1beta1.SidecarSet)
+		expectEvicted  []string
+		expectPhase    appsv1beta1.SidecarSetBackfillPhase
+		expectNoStatus bool
+		expectRequeue  func(requeueAfter time.Duration) bool
+	}{
+		{
+			name: "deadline exceeded",
+			modify: func(sidecarSet *appsv1beta1.SidecarSet) {
+				sidecarSet.Spec.InjectionStrategy.Backfill.Deadline = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+			},
+			expectPhase:   appsv1beta1.SidecarSetBackfillDeadlineExceeded,
+			expectRequeue: func(requeueAfter time.Duration) bool { return requeueAfter == 0 },
+		},
+		{
+			name: "deadline not reached",
+			modify: func(sidecarSet *appsv1beta1.SidecarSet) {
+				sidecarSet.Spec.InjectionStrategy.Backfill.Deadline = &metav1.Time{Time: time.Now().Add(time.Hour)}
+			},
+			expectEvicted: []string{"rs-a-1"},
+			expectPhase:   appsv1beta1.SidecarSetBackfillRunning,
+			expectRequeue: func(requeueAfter time.Duration) bool { return requeueAfter == backfillResyncPeriod },
+		},
+		{
+			name: "deadline is nearer than resync period",
+			modify: func(sidecarSet *appsv1beta1.SidecarSet) {
+				sidecarSet.Spec.InjectionStrategy.Backfill.Deadline = &metav1.Time{Time: time.Now().Add(3 * time.Second)}
+			},
+			expectEvicted: []string{"rs-a-1"},
+			expectPhase:   appsv1beta1.SidecarSetBackfillRunning,
+			expectRequeue: func(requeueAfter time.Duration) bool {
+				return requeueAfter > 0 && requeueAfter <= 3*time.Second
+			},
+		},
+		{
+			name: "injection paused",
+			modify: func(sidecarSet *appsv1beta1.SidecarSet) {
+				sidecarSet.Spec.InjectionStrategy.Paused = true
+			},
+			expectPhase:   appsv1beta1.SidecarSetBackfillRunning,
+			expectRequeue: func(requeueAfter time.Duration) bool { return requeueAfter == 0 },
+		},
+		{
+			name: "backfill disabled",
+			modify: func(sidecarSet *appsv1beta1.SidecarSet) {
+				sidecarSet.Spec.InjectionStrategy.Backfill.Enabled = false
+			},
+			expectNoStatus: true,
+			expectRequeue:  func(requeueAfter time.Duration) bool { return requeueAfter == 0 },
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			defer backfillExpectations.DeleteExpectations(sidecarSetDemo.Name)
+			sidecarSet := newBackfillSidecarSet(1)
+			cs.modify(sidecarSet)
+			pods := []*corev1.Pod{
+				newBackfillPod("rs-a-1", "rs-a", false, true, 2*time.Hour),
+				newBackfillPod("rs-a-2", "rs-a", false, true, time.Hour),
+			}
+			env := newBackfillTestEnv(sidecarSet, pods, nil)
+			result := env.reconcile(t)
+			if !cs.expectRequeue(result.RequeueAfter) {
+				t.Fatalf("unexpected requeue after %v", result.RequeueAfter)
+			}
+			expectEvicted(t, cs.name, env.takeEvicted(), cs.expectEvicted...)
+			status := env.backfillStatus(t, sidecarSet)
+			if cs.expectNoStatus {
+				if status != nil {
+					t.Fatalf("expected no backfill status, got %+v", status)
+				}
+				return
+			}
+			if status == nil || status.Phase != cs.expectPhase {
+				t.Fatalf("expected backfill phase %s, got %+v", cs.expectPhase, status)
+			}
+			if uninjected := int32(len(pods) - len(cs.expectEvicted)); status.Workloads[0].UninjectedPods != uninjected {
+				t.Fatalf("expected %d uninjected pods, got %+v", uninjected, status.Workloads[0])
+			}
+		})
+	}
+}
+
+func TestBackfillIgnoresInjectedPods(t *testing.T) {
+	defer backfillExpectations.DeleteExpectations(sidecarSetDemo.Name)
+	sidecarSet := newBackfillSidecarSet(1)
+	injected := newBackfillPod("rs-a-1", "rs-a", true, true, time.Hour)
+	if !sidecarcontrol.IsPodInjectedSidecarSet(injected, sidecarSet) {
+		t.Fatalf("expected pod injected")
+	}
+	env := newBackfillTestEnv(sidecarSet, []*corev1.Pod{injected}, nil)
+	env.reconcile(t)
+	expectEvicted(t, "injected", env.takeEvicted())
+	if status := env.backfillStatus(t, sidecarSet); status.Phase != appsv1beta1.SidecarSetBackfillCompleted || len(status.Workloads) != 0 {
+		t.Fatalf("expected completed backfill without workloads, got %+v", status)
+	}
+}
+
+func TestBackfillSkipsPods(t *testing.T) {
+	cases := []struct {
+		name         string
+		mutate       func(sidecarSet *appsv1beta1.SidecarSet, pod *corev1.Pod)
+		canary       bool
+		expectStatus []appsv1beta1.SidecarSetBackfillWorkloadStatus
+	}{
+		{
+			name: "controlled by job",
+			mutate: func(_ *appsv1beta1.SidecarSet, pod *corev1.Pod) {
+				pod.OwnerReferences[0].APIVersion = "batch/v1"
+				pod.OwnerReferences[0].Kind = "Job"
+			},
+		},
+		{
+			name: "mirror pod owned by node",
+			mutate: func(_ *appsv1beta1.SidecarSet, pod *corev1.Pod) {
+				pod.OwnerReferences[0].APIVersion = "v1"
+				pod.OwnerReferences[0].Kind = "Node"
+			},
+		},
+		{
+			name: "controlled by unknown workload",
+			mutate: func(_ *appsv1beta1.SidecarSet, pod *corev1.Pod) {
+				pod.OwnerReferences[0].APIVersion = "example.com/v1"
+				pod.OwnerReferences[0].Kind = "ReplicaSet"
+			},
+		},
+		{
+			name:   "injected with canary sidecarSet",
+			mutate: func(_ *appsv1beta1.SidecarSet, _ *corev1.Pod) {},
+			canary: true,
+			expectStatus: []appsv1beta1.SidecarSetBackfillWorkloadStatus{
+				{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "rs-a", Namespace: "default", UninjectedPods: 1, SkippedPods: 1},
+			},
+		},
+		{
+			name: "skipped by footprint limits",
+			mutate: func(sidecarSet *appsv1beta1.SidecarSet, pod *corev1.Pod) {
+				pod.Annotations[sidecarcontrol.SidecarSetSkippedListAnnotation] = "other," + sidecarSet.Name
+			},
+			expectStatus: []appsv1beta1.SidecarSetBackfillWorkloadStatus{
+				{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "rs-a", Namespace: "default", UninjectedPods: 1, SkippedPods: 1},
+			},
+		},
+		{
+			name: "skipped by qosPolicy",
+			mutate: func(sidecarSet *appsv1beta1.SidecarSet, pod *corev1.Pod) {
+				pod.Annotations[sidecarcontrol.SidecarSetQoSDecisionAnnotation] = fmt.Sprintf(`{"%s":"SkipInjection"}`, sidecarSet.Name)
+			},
+			expectStatus: []appsv1beta1.SidecarSetBackfillWorkloadStatus{
+				{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "rs-a", Namespace: "default", UninjectedPods: 1, SkippedPods: 1},
+			},
+		},
+		{
+			name: "guaranteed pod rejected by qosPolicy",
+			mutate: func(sidecarSet *appsv1beta1.SidecarSet, pod *corev1.Pod) {
+				sidecarSet.Spec.InjectionStrategy.QoSPolicy = &appsv1beta1.SidecarSetQoSPolicy{Type: appsv1beta1.SidecarSetQoSPolicyReject}
+				resources := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")}
+				pod.Spec.Containers[0].Resources = corev1.ResourceRequirements{Limits: resources, Requests: resources}
+			},
+			expectStatus: []appsv1beta1.SidecarSetBackfillWorkloadStatus{
+				{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "rs-a", Namespace: "default", UninjectedPods: 1, SkippedPods: 1},
+			},
+		},
+		{
+			name: "container name conflicts",
+			mutate: func(sidecarSet *appsv1beta1.SidecarSet, pod *corev1.Pod) {
+				pod.Spec.Containers[0].Name = sidecarSet.Spec.Containers[0].Name
+			},
+			expectStatus: []appsv1beta1.SidecarSetBackfillWorkloadStatus{
+				{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: "rs-a", Namespace: "default", UninjectedPods: 1, SkippedPods: 1},
+			},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			defer backfillExpectations.DeleteExpectations(sidecarSetDemo.Name)
+			sidecarSet := newBackfillSidecarSet(1)
+			pod := newBackfillPod("rs-a-1", "rs-a", false, true, time.Hour)
+			cs.mutate(sidecarSet, pod)
+			var others []client.Object
+			if cs.canary {
+				canary := sidecarSetDemo.DeepCopy()
+				canary.Name = sidecarSet.Name + "-canary"
+				canary.Annotations = map[string]string{
+					appsv1beta1.SidecarSetCanaryAnnotation: "true",
+					appsv1beta1.SidecarSetBaseAnnotation:   sidecarSet.Name,
+				}
+				others = append(others, canary)
+			}
+			env := newBackfillTestEnv(sidecarSet, []*corev1.Pod{pod}, nil, others...)
+			if result := env.reconcile(t); result.RequeueAfter != 0 {
+				t.Fatalf("expected no requeue, got %v", result.RequeueAfter)
+			}
+			expectEvicted(t, cs.name, env.takeEvicted())
+			status := env.backfillStatus(t, sidecarSet)
+			if status.Phase != appsv1beta1.SidecarSetBackfillCompleted {
+				t.Fatalf("expected completed backfill, got %+v", status)
+			}
+			if !reflect.DeepEqual(status.Workloads, cs.expectStatus) {
+				t.Fatalf("expected workloads %+v, got %+v", cs.expectStatus, status.Workloads)
+			}
+		})
+	}
+}
@@ -0,0 +1,366 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecarset
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
+	"github.com/openkruise/kruise/pkg/util"
+)
+
+// configHashEntry is the content of a ConfigMap or Secret to be hashed.
+type configHashEntry struct {
+	Kind      string            `json:"kind"`
+	Namespace string            `json:"namespace"`
+	Name      string            `json:"name"`
+	Data      map[string][]byte `json:"data,omitempty"`
+}
+
+// computeSidecarConfigHashes returns the hashes of the ConfigMaps and Secrets in configHashFrom of sidecar containers,
+// which is a map from the name of container to the hash. The object not found is hashed as empty.
+func computeSidecarConfigHashes(reader client.Reader, sidecarSet *appsv1beta1.SidecarSet) (map[string]string, error) {
+	hashes := make(map[string]string)
+	for i := range sidecarSet.Spec.Containers {
+		container := &sidecarSet.Spec.Containers[i]
+		if len(container.ConfigHashFrom) == 0 {
+			continue
+		}
+		entries := make([]configHashEntry, 0, len(container.ConfigHashFrom))
+		for _, source := range container.ConfigHashFrom {
+			var entry configHashEntry
+			var data map[string][]byte
+			switch {
+			case source.ConfigMapRef != nil:
+				entry = configHashEntry{Kind: "ConfigMap", Namespace: source.ConfigMapRef.Namespace, Name: source.ConfigMapRef.Name}
+				cm := &corev1.ConfigMap{}
+				if err := reader.Get(context.TODO(), types.NamespacedName{Namespace: entry.Namespace, Name: entry.Name}, cm); err != nil {
+					if !errors.IsNotFound(err) {
+						return nil, err
+					}
+				} else {
+					data = make(map[string][]byte, len(cm.Data)+len(cm.BinaryData))
+					for k, v := range cm.Data {
+						data[k] = []byte(v)
+					}
+					for k, v := range cm.BinaryData {
+						data[k] = v
+					}
+				}
+			case source.SecretRef != nil:
+				entry = configHashEntry{Kind: "Secret", Namespace: source.SecretRef.Namespace, Name: source.SecretRef.Name}
+				secret := &corev1.Secret{}
+				if err := reader.Get(context.TODO(), types.NamespacedName{Namespace: entry.Namespace, Name: entry.Name}, secret); err != nil {
+					if !errors.IsNotFound(err) {
+						return nil, err
+					}
+				} else {
+					data = secret.Data
+				}
+			default:
+				continue
+			}
+			entry.Data = filterConfigData(data, source.Keys)
+			entries = append(entries, entry)
+		}
+		// json.Marshal sorts the keys of map in a stable order in the encoding
+		by, err := json.Marshal(entries)
+		if err != nil {
+			return nil, err
+		}
+		hashes[container.Name] = fmt.Sprintf("%x", sha256.Sum256(by))[:16]
+	}
+	return hashes, nil
+}
+
+func filterConfigData(data map[string][]byte, keys []string) map[string][]byte {
+	if len(keys) == 0 {
+		return data
+	}
+	filtered := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		if v, ok := data[key]; ok {
+			filtered[key] = v
+		}
+	}
+	return filtered
+}
+
+// syncSidecarConfigHashes updates the config hashes in sidecarSet annotations if the referenced config has been changed,
+// together with the sidecarSet hash, so that a new revision will be registered to restart the sidecar containers.
+func (p *Processor) syncSidecarConfigHashes(sidecarSet *appsv1beta1.SidecarSet) error {
+	hashes, err := computeSidecarConfigHashes(p.Client, sidecarSet)
+	if err != nil {
+		return err
+	}
+	var hashStr string
+	if len(hashes) > 0 {
+		by, _ := json.Marshal(hashes)
+		hashStr = string(by)
+	}
+	if sidecarSet.Annotations[sidecarcontrol.SidecarSetConfigHashAnnotation] == hashStr {
+		return nil
+	}
+
+	clone := sidecarSet.DeepCopy()
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if clone.Annotations == nil {
+			clone.Annotations = make(map[string]string)
+		}
+		if hashStr == "" {
+			delete(clone.Annotations, sidecarcontrol.SidecarSetConfigHashAnnotation)
+		} else {
+			clone.Annotations[sidecarcontrol.SidecarSetConfigHashAnnotation] = hashStr
+		}
+		hash, err := sidecarcontrol.SidecarSetHashV1beta1(clone)
+		if err != nil {
+			return err
+		}
+		clone.Annotations[sidecarcontrol.SidecarSetHashAnnotation] = hash
+		updateErr := p.Client.Update(context.TODO(), clone)
+		if updateErr == nil {
+			return nil
+		}
+		if err := p.Client.Get(context.TODO(), types.NamespacedName{Name: sidecarSet.Name}, clone); err != nil {
+			return err
+		}
+		return updateErr
+	})
+	if err != nil {
+		return err
+	}
+	klog.V(3).InfoS("SidecarSet updated config hashes", "sidecarSet", klog.KObj(sidecarSet), "configHashes", hashStr)
+	p.recorder.Eventf(sidecarSet, corev1.EventTypeNormal, "ConfigChanged", "config referenced by sidecar containers changed, hashes %s", hashStr)
+	*sidecarSet = *clone
+	return nil
+}
+
+// getSidecarConfigRestartContainers returns the sidecar containers to be restarted for the new config,
+// excluding the ones whose image is updated.
+func getSidecarConfigRestartContainers(sidecarSet *appsv1beta1.SidecarSet, pod *corev1.Pod, updatedPod *corev1.Pod) []string {
+	changed := sidecarcontrol.GetSidecarConfigChangedContainers(sidecarSet, pod)
+	if len(changed) == 0 {
+		return nil
+	}
+	images := make(map[string]string, len(pod.Spec.Containers))
+	for i := range pod.Spec.Containers {
+		images[pod.Spec.Containers[i].Name] = pod.Spec.Containers[i].Image
+	}
+	var restartContainers []string
+	for _, name := range changed {
+		for i := range updatedPod.Spec.Containers {
+			c := &updatedPod.Spec.Containers[i]
+			if c.Name == name && c.Image == images[name] {
+				restartContainers = append(restartContainers, name)
+			}
+		}
+	}
+	return restartContainers
+}
+
+// restartSidecarContainers creates a ContainerRecreateRequest to restart the sidecar containers in pod.
+func (p *Processor) restartSidecarContainers(sidecarSet *appsv1beta1.SidecarSet, pod *corev1.Pod, containers []string) error {
+	crrContainers := make([]appsv1alpha1.ContainerRecreateRequestContainer, 0, len(containers))
+	for _, name := range containers {
+		crrContainers = append(crrContainers, appsv1alpha1.ContainerRecreateRequestContainer{Name: name})
+	}
+	crr := &appsv1alpha1.ContainerRecreateRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: pod.Namespace,
+			Name:      getSidecarConfigCRRName(sidecarSet, pod),
+			Labels:    map[string]string{sidecarcontrol.SidecarSetKindName: sidecarSet.Name},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(pod, corev1.SchemeGroupVersion.WithKind("Pod")),
+			},
+		},
+		Spec: appsv1alpha1.ContainerRecreateRequestSpec{
+			PodName:    pod.Name,
+			Containers: crrContainers,
+			Strategy: &appsv1alpha1.ContainerRecreateRequestStrategy{
+				OrderedRecreate: true,
+				FailurePolicy:   appsv1alpha1.ContainerRecreateRequestFailurePolicyFail,
+			},
+			TTLSecondsAfterFinished: &sidecarConfigCRRTTLSeconds,
+		},
+	}
+	if err := p.Client.Create(context.TODO(), crr); err != nil && !errors.IsAlreadyExists(err) {
+		p.recorder.Eventf(sidecarSet, corev1.EventTypeWarning, "FailedRestart", "failed to restart containers %v of pod %s/%s for the new config: %v",
+			containers, pod.Namespace, pod.Name, err)
+		return err
+	}
+	klog.V(3).InfoS("SidecarSet restarted sidecar containers for the new config", "sidecarSet", klog.KObj(sidecarSet), "pod", klog.KObj(pod), "containers", containers)
+	return nil
+}
+
+var sidecarConfigCRRTTLSeconds int32 = 600
+
+// getSidecarConfigCRRName returns the name of ContainerRecreateRequest for the sidecarSet revision,
+// so that the containers are restarted only once for the same config.
+func getSidecarConfigCRRName(sidecarSet *appsv1beta1.SidecarSet, pod *corev1.Pod) string {
+	return util.GetBoundedObjectName(fmt.Sprintf("%s-%s-%s", pod.Name, sidecarSet.Name, sidecarcontrol.GetSidecarSetRevision(sidecarSet)))
+}
+
+// isSidecarConfigCRRFailed returns true if the ContainerRecreateRequest has completed without restarting all the containers.
+func isSidecarConfigCRRFailed(crr *appsv1alpha1.ContainerRecreateRequest) bool {
+	if crr.Status.Phase != appsv1alpha1.ContainerRecreateRequestCompleted {
+		return false
+	}
+	if crr.Status.Message != "" {
+		return true
+	}
+	for i := range crr.Status.ContainerRecreateStates {
+		if crr.Status.ContainerRecreateStates[i].Phase == appsv1alpha1.ContainerRecreateRequestFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// retrySidecarConfigRestarts retries to restart the sidecar containers of the updated pods, which are requested to restart
+// for the new config but have not been restarted, if the ContainerRecreateRequest has failed or disappeared.
+// Otherwise the pods would never be consistent and block the update. The failed request is deleted first,
+// and a new one is created in the next round.
+func (p *Processor) retrySidecarConfigRestarts(sidecarSet *appsv1beta1.SidecarSet, pods []*corev1.Pod) error {
+	for _, pod := range pods {
+		if !sidecarcontrol.IsPodSidecarUpdated(sidecarSet, pod) {
+			continue
+		}
+		containers := sidecarcontrol.GetSidecarConfigRestartingContainers(pod, sidecarSet.Name)
+		if len(containers) == 0 {
+			continue
+		}
+		crr := &appsv1alpha1.ContainerRecreateRequest{}
+		err := p.Client.Get(context.TODO(), types.NamespacedName{Namespace: pod.Namespace, Name: getSidecarConfigCRRName(sidecarSet, pod)}, crr)
+		if errors.IsNotFound(err) {
+			if err := p.restartSidecarContainers(sidecarSet, pod, containers); err != nil {
+				return err
+			}
+			continue
+		} else if err != nil {
+			return err
+		}
+		if crr.DeletionTimestamp != nil || !isSidecarConfigCRRFailed(crr) {
+			continue
+		}
+		p.recorder.Eventf(sidecarSet, corev1.EventTypeWarning, "FailedRestart", "failed to restart containers %v of pod %s/%s for the new config, will retry: %s",
+			containers, pod.Namespace, pod.Name, crr.Status.Message)
+		if err := p.Client.Delete(context.TODO(), crr); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ handler.TypedEventHandler[*corev1.ConfigMap, reconcile.Request] = &enqueueSidecarSetForConfig[*corev1.ConfigMap]{}
+var _ handler.TypedEventHandler[*corev1.Secret, reconcile.Request] = &enqueueSidecarSetForConfig[*corev1.Secret]{}
+
+// enqueueSidecarSetForConfig enqueues the sidecarSets which reference the ConfigMap or Secret in configHashFrom.
+type enqueueSidecarSetForConfig[T client.Object] struct {
+	reader client.Reader
+}
+
+func (e *enqueueSidecarSetForConfig[T]) Create(ctx context.Context, evt event.TypedCreateEvent[T], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	e.enqueue(evt.Object, q)
+}
+
+func (e *enqueueSidecarSetForConfig[T]) Update(ctx context.Context, evt event.TypedUpdateEvent[T], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	if evt.ObjectOld.GetResourceVersion() == evt.ObjectNew.GetResourceVersion() {
+		return
+	}
+	e.enqueue(evt.ObjectNew, q)
+}
+
+func (e *enqueueSidecarSetForConfig[T]) Delete(ctx context.Context, evt event.TypedDeleteEvent[T], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	e.enqueue(evt.Object, q)
+}
+
+func (e *enqueueSidecarSetForConfig[T]) Generic(ctx context.Context, evt event.TypedGenericEvent[T], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+}
+
+func (e *enqueueSidecarSetForConfig[T]) enqueue(obj T, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	_, isSecret := any(obj).(*corev1.Secret)
+	sidecarSets := &appsv1beta1.SidecarSetList{}
+	if err := e.reader.List(context.TODO(), sidecarSets); err != nil {
+		klog.ErrorS(err, "Failed to list SidecarSets for config", "object", klog.KObj(obj))
+		return
+	}
+	for i := range sidecarSets.Items {
+		if isSidecarSetReferencingConfig(&sidecarSets.Items[i], obj.GetNamespace(), obj.GetName(), isSecret) {
+			q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: sidecarSets.Items[i].Name}})
+		}
+	}
+}
+
+func isSidecarSetReferencingConfig(sidecarSet *appsv1beta1.SidecarSet, namespace, name string, isSecret bool) bool {
+	refs := sets.NewString()
+	for i := range sidecarSet.Spec.Containers {
+		for _, source := range sidecarSet.Spec.Containers[i].ConfigHashFrom {
+			if !isSecret && source.ConfigMapRef != nil {
+				refs.Insert(source.ConfigMapRef.Namespace + "/" + source.ConfigMapRef.Name)
+			} else if isSecret && source.SecretRef != nil {
+				refs.Insert(source.SecretRef.Namespace + "/" + source.SecretRef.Name)
+			}
+		}
+	}
+	return refs.Has(namespace + "/" + name)
+}
+
+var _ handler.TypedEventHandler[*appsv1alpha1.ContainerRecreateRequest, reconcile.Request] = &enqueueSidecarSetForCRR{}
+
+// enqueueSidecarSetForCRR enqueues the sidecarSet which restarts the sidecar containers for the new config by the CRR,
+// once the CRR has completed or been deleted.
+type enqueueSidecarSetForCRR struct{}
+
+func (e *enqueueSidecarSetForCRR) Create(ctx context.Context, evt event.TypedCreateEvent[*appsv1alpha1.ContainerRecreateRequest], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+}
+
+func (e *enqueueSidecarSetForCRR) Update(ctx context.Context, evt event.TypedUpdateEvent[*appsv1alpha1.ContainerRecreateRequest], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	if evt.ObjectOld.Status.Phase != evt.ObjectNew.Status.Phase && evt.ObjectNew.Status.Phase == appsv1alpha1.ContainerRecreateRequestCompleted {
+		e.enqueue(evt.ObjectNew, q)
+	}
+}
+
+func (e *enqueueSidecarSetForCRR) Delete(ctx context.Context, evt event.TypedDeleteEvent[*appsv1alpha1.ContainerRecreateRequest], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	e.enqueue(evt.Object, q)
+}
+
+func (e *enqueueSidecarSetForCRR) Generic(ctx context.Context, evt event.TypedGenericEvent[*appsv1alpha1.ContainerRecreateRequest], q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+}
+
+func (e *enqueueSidecarSetForCRR) enqueue(crr *appsv1alpha1.ContainerRecreateRequest, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+	if name := crr.Labels[sidecarcontrol.SidecarSetKindName]; name != "" {
+		q.Add(reconcile.Request{NamespacedName: types.NamespacedName{Name: name}})
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sidecarset
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
+)
+
+func newConfigHashSidecarSet() *appsv1beta1.SidecarSet {
+	sidecarSet := sidecarSetDemo.DeepCopy()
+	sidecarSet.Spec.Containers[0].Image = "test-image:v1"
+	sidecarSet.Spec.Containers[0].ConfigHashFrom = []appsv1beta1.SidecarConfigHashSource{
+		{ConfigMapRef: &appsv1beta1.SidecarConfigObjectReference{Namespace: "default", Name: "sidecar-config"}, Keys: []string{"envoy.yaml"}},
+		{SecretRef: &appsv1beta1.SidecarConfigObjectReference{Namespace: "default", Name: "sidecar-cert"}},
+	}
+	return sidecarSet
+}
+
+func TestComputeSidecarConfigHashesWithKeys(t *testing.T) {
+	sidecarSet := newConfigHashSidecarSet()
+	sidecarSet.Spec.Containers = append(sidecarSet.Spec.Containers, appsv1beta1.SidecarContainer{Container: corev1.Container{Name: "other-sidecar"}})
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sidecar-config"},
+		Data:       map[string]string{"envoy.yaml": "v1", "unrelated": "v1"},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sidecar-cert"},
+		Data:       map[string][]byte{"tls.crt": []byte("v1")},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm, secret).Build()
+
+	computeHash := func() string {
+		hashes, err := computeSidecarConfigHashes(fakeClient, sidecarSet)
+		if err != nil {
+			t.Fatalf("failed to compute hashes: %v", err)
+		}
+		if len(hashes) != 1 || hashes["test-sidecar"] == "" {
+			t.Fatalf("expected hash only for test-sidecar, got %v", hashes)
+		}
+		return hashes["test-sidecar"]
+	}
+	hash := computeHash()
+
+	cm.Data["unrelated"] = "v2"
+	if err := fakeClient.Update(context.TODO(), cm); err != nil {
+		t.Fatalf("failed to update ConfigMap: %v", err)
+	}
+	if newHash := computeHash(); newHash != hash {
+		t.Fatalf("expected hash unchanged for the key not hashed, got %s -> %s", hash, newHash)
+	}
+
+	cm.Data["envoy.yaml"] = "v2"
+	if err := fakeClient.Update(context.TODO(), cm); err != nil {
+		t.Fatalf("failed to update ConfigMap: %v", err)
+	}
+	newHash := computeHash()
+	if newHash == hash {
+		t.Fatalf("expected hash changed for the key hashed")
+	}
+	hash = newHash
+
+	secret.Data["tls.crt"] = []byte("v2")
+	if err := fakeClient.Update(context.TODO(), secret); err != nil {
+		t.Fatalf("failed to update Secret: %v", err)
+	}
+	if newHash = computeHash(); newHash == hash {
+		t.Fatalf("expected hash changed for the Secret")
+	}
+	hash = newHash
+
+	if err := fakeClient.Delete(context.TODO(), cm); err != nil {
+		t.Fatalf("failed to delete ConfigMap: %v", err)
+	}
+	if newHash = computeHash(); newHash == hash {
+		t.Fatalf("expected hash changed for the deleted ConfigMap")
+	}
+}
+
+func TestUpdateSidecarSetWithConfigChanged(t *testing.T) {
+	testScheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(testScheme))
+	utilruntime.Must(appsv1alpha1.AddToScheme(testScheme))
+	utilruntime.Must(appsv1beta1.AddToScheme(testScheme))
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "sidecar-config"},
+		Data:       map[string]string{"envoy.yaml": "v1"},
+	}
+	sidecarSet := newConfigHashSidecarSet()
+	sidecarSet.Spec.Containers[0].ConfigHashFrom = sidecarSet.Spec.Containers[0].ConfigHashFrom[:1]
+	hashes, err := computeSidecarConfigHashes(fake.NewClientBuilder().WithObjects(cm).Build(), sidecarSet)
+	if err != nil {
+		t.Fatalf("failed to compute hashes: %v", err)
+	}
+	by, _ := json.Marshal(hashes)
+	sidecarSet.Annotations[sidecarcontrol.SidecarSetConfigHashAnnotation] = string(by)
+	sidecarSet.Annotations[sidecarcontrol.SidecarSetHashAnnotation], _ = sidecarcontrol.SidecarSetHashV1beta1(sidecarSet)
+	sidecarSet.Annotations[sidecarcontrol.SidecarSetHashWithoutImageAnnotation], _ = sidecarcontrol.SidecarSetHashWithoutImageV1beta1(sidecarSet)
+
+	// the pods are injected with the current config
+	var pods []client.Object
+	for _, name := range []string{"test-pod-1", "test-pod-2"} {
+		pod := podDemo.DeepCopy()
+		pod.Name = name
+		pod.Status.ContainerStatuses[1].ContainerID = "containerd://" + name + "-sidecar-1"
+		upgradeSpec := map[string]sidecarcontrol.SidecarSetUpgradeSpec{sidecarSet.Name: {
+			SidecarSetHash: sidecarSet.Annotations[sidecarcontrol.SidecarSetHashAnnotation],
+			SidecarSetName: sidecarSet.Name,
+			SidecarList:    []string{"test-sidecar"},
+		}}
+		by, _ := json.Marshal(upgradeSpec)
+		pod.Annotations[sidecarcontrol.SidecarSetHashAnnotation] = string(by)
+		withoutImageSpec := map[string]sidecarcontrol.SidecarSetUpgradeSpec{sidecarSet.Name: {
+			SidecarSetHash: sidecarSet.Annotations[sidecarcontrol.SidecarSetHashWithoutImageAnnotation],
+			SidecarSetName: sidecarSet.Name,
+		}}
+		by, _ = json.Marshal(withoutImageSpec)
+		pod.Annotations[sidecarcontrol.SidecarSetHashWithoutImageAnnotation] = string(by)
+		sidecarcontrol.UpdatePodSidecarConfigHashes(pod, sidecarSet, nil)
+		pods = append(pods, pod)
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(testScheme).
+		WithObjects(append(pods, sidecarSet, cm)...).
+		WithStatusSubresource(&appsv1beta1.SidecarSet{}).Build()
+	processor := NewSidecarSetProcessor(fakeClient, record.NewFakeRecorder(10))
+
+	reconcileAndListCRRs := func() []appsv1alpha1.ContainerRecreateRequest {
+		latest, err := getLatestSidecarSet(fakeClient, sidecarSet)
+		if err != nil {
+			t.Fatalf("failed to get sidecarSet: %v", err)
+		}
+		if _, err := processor.UpdateSidecarSet(latest); err != nil {
+			t.Fatalf("failed to update sidecarSet: %v", err)
+		}
+		crrList := &appsv1alpha1.ContainerRecreateRequestList{}
+		if err := fakeClient.List(context.TODO(), crrList); err != nil {
+			t.Fatalf("failed to list CRRs: %v", err)
+		}
+		return crrList.Items
+	}
+
+	if crrs := reconcileAndListCRRs(); len(crrs) != 0 {
+		t.Fatalf("expected no CRR for the unchanged config, got %d", len(crrs))
+	}
+
+	cm.Data["envoy.yaml"] = "v2"
+	if err := fakeClient.Update(context.TODO(), cm); err != nil {
+		t.Fatalf("failed to update ConfigMap: %v", err)
+	}
+	crrs := reconcileAndListCRRs()
+	if len(crrs) != 1 || len(crrs[0].Spec.Containers) != 1 || crrs[0].Spec.Containers[0].Name != "test-sidecar" {
+		t.Fatalf("expected a CRR to restart test-sidecar, got %v", crrs)
+	}
+	latest, _ := getLatestSidecarSet(fakeClient, sidecarSet)
+	if latest.Annotations[sidecarcontrol.SidecarSetHashAnnotation] == sidecarSet.Annotations[sidecarcontrol.SidecarSetHashAnnotation] {
+		t.Fatalf("expected sidecarSet hash changed with the config")
+	}
+	if latest.Annotations[sidecarcontrol.SidecarSetHashWithoutImageAnnotation] != sidecarSet.Annotations[sidecarcontrol.SidecarSetHashWithoutImageAnnotation] {
+		t.Fatalf("expected sidecarSet hash without image unchanged with the config")
+	}
+	restartedPod := &corev1.Pod{}
+	for _, obj := range pods {
+		if obj.GetName() == crrs[0].Spec.PodName {
+			restartedPod, _ = getLatestPod(fakeClient, obj.(*corev1.Pod))
+		}
+	}
+	if !sidecarcontrol.IsPodSidecarUpdated(latest, restartedPod) || !isSidecarImageUpdated(restartedPod, "test-sidecar", "test-image:v1") {
+		t.Fatalf("expected pod %s updated with the image unchanged", restartedPod.Name)
+	}
+	if changed := sidecarcontrol.GetSidecarConfigChangedContainers(latest, restartedPod); len(changed) != 0 {
+		t.Fatalf("expected config hash recorded in pod, got changed %v", changed)
+	}
+
+	// wait for the restart of the first pod under maxUnavailable
+	if crrs = reconcileAndListCRRs(); len(crrs) != 1 {
+		t.Fatalf("expected no more CRR before the restart completed, got %d", len(crrs))
+	}
+
+	// the failed CRR is deleted and then recreated to retry the restart
+	crrs[0].Status.Phase = appsv1alpha1.ContainerRecreateRequestCompleted
+	crrs[0].Status.ContainerRecreateStates = []appsv1alpha1.ContainerRecreateRequestContainerRecreateState{
+		{Name: "test-sidecar", Phase: appsv1alpha1.ContainerRecreateRequestFailed, Message: "failed to kill container"},
+	}
+	if err := fakeClient.Update(context.TODO(), &crrs[0]); err != nil {
+		t.Fatalf("failed to update CRR: %v", err)
+	}
+	if crrs = reconcileAndListCRRs(); len(crrs) != 0 {
+		t.Fatalf("expected the failed CRR deleted, got %v", crrs)
+	}
+	if crrs = reconcileAndListCRRs(); len(crrs) != 1 || crrs[0].Spec.PodName != restartedPod.Name || crrs[0].Status.Phase != "" {
+		t.Fatalf("expected a new CRR to retry the restart of pod %s, got %v", restartedPod.Name, crrs)
+	}
+
+	restartedPod.Status.ContainerStatuses[1].ContainerID = "containerd://" + restartedPod.Name + "-sidecar-2"
+	if err := fakeClient.Status().Update(context.TODO(), restartedPod); err != nil {
+		t.Fatalf("failed to update pod status: %v", err)
+	}
+	if crrs = reconcileAndListCRRs(); len(crrs) != 2 || crrs[0].Spec.PodName == crrs[1].Spec.PodName {
+		t.Fatalf("expected CRRs for both pods, got %v", crrs)
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
 	"github.com/openkruise/kruise/pkg/util"
 	utilclient "github.com/openkruise/kruise/pkg/util/client"
@@ -105,6 +106,19 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
+	// Watch for changes to ConfigMaps and Secrets referenced by configHashFrom
+	if err = c.Watch(source.Kind(mgr.GetCache(), &corev1.ConfigMap{}, &enqueueSidecarSetForConfig[*corev1.ConfigMap]{reader: mgr.GetCache()})); err != nil {
+		return err
+	}
+	if err = c.Watch(source.Kind(mgr.GetCache(), &corev1.Secret{}, &enqueueSidecarSetForConfig[*corev1.Secret]{reader: mgr.GetCache()})); err != nil {
+		return err
+	}
+
+	// Watch for the completion of ContainerRecreateRequests restarting sidecar containers for the new config
+	if err = c.Watch(source.Kind(mgr.GetCache(), &appsv1alpha1.ContainerRecreateRequest{}, &enqueueSidecarSetForCRR{})); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -121,6 +135,9 @@ type ReconcileSidecarSet struct {
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=sidecarsets/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps.kruise.io,resources=sidecarsets/finalizers,verbs=update
 // +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=pods/eviction,verbs=create
+// +kubebuilder:rbac:groups=core,resources=configmaps;secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps.kruise.io,resources=containerrecreaterequests,verbs=get;list;watch;create;delete
 
 // Reconcile reads that state of the cluster for a SidecarSet object and makes changes based on the state read
 // and what is in the SidecarSet.Spec
@@ -131,6 +148,7 @@ func (r *ReconcileSidecarSet) Reconcile(_ context.Context, request reconcile.Req
 		if errors.IsNotFound(err) {
 			// Object not found, return.  Created objects are automatically garbage collected.
 			// For additional cleanup logic use finalizers.
+			backfillExpectations.DeleteExpectations(request.Name)
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"strings"
 	"time"
 
@@ -65,12 +66,17 @@ func NewSidecarSetProcessor(cli client.Client, rec record.EventRecorder) *Proces
 	}
 }
 
-func (p *Processor) UpdateSidecarSet(sidecarSet *appsv1beta1.SidecarSet) (reconcile.Result, error) {
+func (p *Processor) UpdateSidecarSet(sidecarSet *appsv1beta1.SidecarSet) (result reconcile.Result, err error) {
 	control := sidecarcontrol.New(sidecarSet)
 	// check whether sidecarSet is active
 	if !control.IsActiveSidecarSet() {
 		return reconcile.Result{}, nil
 	}
+	// sync the hashes of config referenced by sidecar containers, the changes produce a new revision
+	if err := p.syncSidecarConfigHashes(sidecarSet); err != nil {
+		klog.ErrorS(err, "SidecarSet sync config hashes error", "sidecarSet", klog.KObj(sidecarSet))
+		return reconcile.Result{}, err
+	}
 	// 1. get matching pods with the sidecarSet
 	pods, err := p.getMatchingPods(sidecarSet)
 	if err != nil {
@@ -88,11 +94,23 @@ func (p *Processor) UpdateSidecarSet(sidecarSet *appsv1beta1.SidecarSet) (reconc
 
 	// 2. calculate SidecarSet status based on pod and revision information
 	status := calculateStatus(control, pods, latestRevision, collisionCount)
+	// evict the pods which are not injected if backfill is enabled, and record the progress in status
+	backfillRequeueAfter, backfillErr := p.backfillUninjectedPods(sidecarSet, status)
 	// update sidecarSet status in store
 	if err := p.updateSidecarSetStatus(sidecarSet, status); err != nil {
 		return reconcile.Result{}, err
 	}
 	sidecarSet.Status = *status
+	if backfillErr != nil {
+		return reconcile.Result{}, backfillErr
+	}
+	// the pods which are not injected are not related to the sidecarSet by pod events,
+	// so requeue the sidecarSet to continue backfill.
+	defer func() {
+		if err == nil && backfillRequeueAfter > 0 && (result.RequeueAfter == 0 || result.RequeueAfter > backfillRequeueAfter) {
+			result.RequeueAfter = backfillRequeueAfter
+		}
+	}()
 
 	// in case of informer cache latency
 	for _, pod := range pods {
@@ -120,6 +138,12 @@ func (p *Processor) UpdateSidecarSet(sidecarSet *appsv1beta1.SidecarSet) (reconc
 		return reconcile.Result{}, nil
 	}
 
+	// retry the failed restarts of sidecar containers for the new config
+	if err := p.retrySidecarConfigRestarts(sidecarSet, pods); err != nil {
+		klog.ErrorS(err, "SidecarSet retried to restart sidecar containers for the new config error", "sidecarSet", klog.KObj(sidecarSet))
+		return reconcile.Result{}, err
+	}
+
 	// 5. If sidecar container hot upgrade complete, then set the other one(empty sidecar container) image to HotUpgradeEmptyImage
 	if isSidecarSetHasHotUpgradeContainer(sidecarSet) {
 		var podsInHotUpgrading []*corev1.Pod
@@ -206,8 +230,17 @@ func (p *Processor) updatePodSidecarAndHash(control sidecarcontrol.SidecarContro
 		if err := p.Client.Get(context.TODO(), types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}, podClone); err != nil {
 			klog.ErrorS(err, "SidecarSet got updated pod from client failed", "sidecarSet", klog.KObj(sidecarSet), "pod", klog.KObj(pod))
 		}
+		oldPod := podClone.DeepCopy()
 		// update pod sidecar container
 		updatePodSidecarContainer(control, podClone)
+		// restart the sidecar containers whose config has been changed but image not
+		restartContainers := getSidecarConfigRestartContainers(sidecarSet, oldPod, podClone)
+		if len(restartContainers) > 0 {
+			if err := p.restartSidecarContainers(sidecarSet, podClone, restartContainers); err != nil {
+				return err
+			}
+		}
+		sidecarcontrol.UpdatePodSidecarConfigHashes(podClone, sidecarSet, restartContainers)
 		// older pod don't have SidecarSetListAnnotation
 		// which is to improve the performance of the sidecarSet controller
 		sidecarSetNames, ok := podClone.Annotations[sidecarcontrol.SidecarSetListAnnotation]
@@ -292,21 +325,7 @@ func (p *Processor) updateSidecarSetStatus(sidecarSet *appsv1beta1.SidecarSet, s
 
 // If you need update the pod object, you must DeepCopy it
 func (p *Processor) getMatchingPods(s *appsv1beta1.SidecarSet) ([]*corev1.Pod, error) {
-	// get more faster selector
-	selector, err := util.ValidatedLabelSelectorAsSelector(s.Spec.Selector)
-	if err != nil {
-		return nil, err
-	}
-	scopedNamespaces := sets.NewString()
-	if s.Spec.NamespaceSelector != nil {
-		if scopedNamespaces, err = sidecarcontrol.FetchSidecarSetMatchedNamespace(p.Client, s); err != nil {
-			return nil, err
-		}
-	} else {
-		// when namespace="", client will list pods in all namespaces
-		scopedNamespaces.Insert("")
-	}
-	selectedPods, err := p.getSelectedPods(scopedNamespaces, selector)
+	selectedPods, err := p.getSelectedPodsForSidecarSet(s)
 	if err != nil {
 		return nil, err
 	}
@@ -324,6 +343,26 @@ func (p *Processor) getMatchingPods(s *appsv1beta1.SidecarSet) ([]*corev1.Pod, e
 	return filteredPods, nil
 }
 
+// getSelectedPodsForSidecarSet returns all the pods selected by the sidecarSet in its scoped namespaces,
+// which must be deep copied before update.
+func (p *Processor) getSelectedPodsForSidecarSet(s *appsv1beta1.SidecarSet) ([]*corev1.Pod, error) {
+	// get more faster selector
+	selector, err := util.ValidatedLabelSelectorAsSelector(s.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	scopedNamespaces := sets.NewString()
+	if s.Spec.NamespaceSelector != nil {
+		if scopedNamespaces, err = sidecarcontrol.FetchSidecarSetMatchedNamespace(p.Client, s); err != nil {
+			return nil, err
+		}
+	} else {
+		// when namespace="", client will list pods in all namespaces
+		scopedNamespaces.Insert("")
+	}
+	return p.getSelectedPods(scopedNamespaces, selector)
+}
+
 // get selected pods(DisableDeepCopy:true, indicates must be deep copy before update pod objection)
 func (p *Processor) getSelectedPods(namespaces sets.String, selector labels.Selector) (relatedPods []*corev1.Pod, err error) {
 	// DisableDeepCopy:true, indicates must be deep copy before update pod objection
@@ -637,7 +676,8 @@ func inconsistentStatus(sidecarSet *appsv1beta1.SidecarSet, status *appsv1beta1.
 		status.ReadyPods != sidecarSet.Status.ReadyPods ||
 		status.UpdatedReadyPods != sidecarSet.Status.UpdatedReadyPods ||
 		status.LatestRevision != sidecarSet.Status.LatestRevision ||
-		!pointer.Int32Equal(sidecarSet.Status.CollisionCount, status.CollisionCount)
+		!pointer.Int32Equal(sidecarSet.Status.CollisionCount, status.CollisionCount) ||
+		!reflect.DeepEqual(sidecarSet.Status.BackfillStatus, status.BackfillStatus)
 }
 
 func isSidecarSetUpdateFinish(status *appsv1beta1.SidecarSetStatus) bool {
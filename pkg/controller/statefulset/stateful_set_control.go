@@ -41,6 +41,7 @@ import (
 	"github.com/openkruise/kruise/pkg/features"
 	"github.com/openkruise/kruise/pkg/util"
 	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+	historyutil "github.com/openkruise/kruise/pkg/util/history"
 	imagejobutilfunc "github.com/openkruise/kruise/pkg/util/imagejob/utilfunction"
 	"github.com/openkruise/kruise/pkg/util/inplaceupdate"
 	"github.com/openkruise/kruise/pkg/util/lifecycle"
@@ -228,6 +229,7 @@ func (ssc *defaultStatefulSetControl) truncateHistory(
 	historyLen := len(history)
 	historyLimit := int(*set.Spec.RevisionHistoryLimit)
 	if historyLen <= historyLimit {
+		historyutil.RecordRevisionCleanup(controllerKind.Kind, len(revisions), 0)
 		return nil
 	}
 	// delete any non-live history to maintain the revision limit.
@@ -237,6 +239,7 @@ func (ssc *defaultStatefulSetControl) truncateHistory(
 			return err
 		}
 	}
+	historyutil.RecordRevisionCleanup(controllerKind.Kind, len(revisions)-len(history), len(history))
 	return nil
 }
 
@@ -387,6 +390,7 @@ func (ssc *defaultStatefulSetControl) updateStatefulSet(
 
 	ssc.updatePVCStatus(&status, set, pods)
 	updateStatus(&status, minReadySeconds, currentRevision, updateRevision, pods)
+	status.HibernatedReplicas = ssc.getHibernatedReplicas(set, pods)
 
 	startOrdinal, endOrdinal, reserveOrdinals := getStatefulSetReplicasRange(set)
 	// slice that will contain all Pods such that startOrdinal <= getOrdinal(pod) < endOrdinal and not in reserveOrdinals
@@ -425,6 +429,7 @@ func (ssc *defaultStatefulSetControl) updateStatefulSet(
 				updateSet,
 				currentRevision.Name,
 				updateRevision.Name, ord, replicas)
+			applyHibernatedNodeAffinity(replicas[replicaIdx], ord, status.HibernatedReplicas)
 		}
 	}
 
@@ -644,7 +649,8 @@ func (ssc *defaultStatefulSetControl) rollingUpdateStatefulsetPods(
 		}
 
 		// the target is already up-to-date, go to next
-		if getPodRevision(replicas[target]) == updateRevision.Name && pvcMatched {
+		affinityMatched := ordinalAffinityMatches(set, replicas[target])
+		if getPodRevision(replicas[target]) == updateRevision.Name && pvcMatched && affinityMatched {
 			continue
 		}
 
@@ -683,9 +689,14 @@ func (ssc *defaultStatefulSetControl) rollingUpdateStatefulsetPods(
 		// delete the Pod if it is not already terminating and does not match the update revision.
 		if !specifiedDeletedPods.Has(replicas[target].Name) && !isTerminating(replicas[target]) {
 			// todo validate in-place for pub
-			inplacing, inplaceUpdateErr := ssc.inPlaceUpdatePod(set, replicas[target], updateRevision, revisions)
-			if inplaceUpdateErr != nil {
-				return status, inplaceUpdateErr
+			// node affinity of Pod is immutable, so it has to be recreated if ordinal affinity is changed
+			var inplacing bool
+			if affinityMatched {
+				var inplaceUpdateErr error
+				inplacing, inplaceUpdateErr = ssc.inPlaceUpdatePod(set, replicas[target], updateRevision, revisions)
+				if inplaceUpdateErr != nil {
+					return status, inplaceUpdateErr
+				}
 			}
 			// if pod is inplacing or actual deleting, decrease revision
 			revisionNeedDecrease := inplacing
@@ -927,6 +938,7 @@ func (ssc *defaultStatefulSetControl) updateStatefulSetStatus(
 
 	// complete any in progress rolling update if necessary
 	completeRollingUpdate(set, status)
+	updateHibernatedCondition(set, status)
 
 	// if the status is not inconsistent do not perform an update
 	if !inconsistentStatus(set, status) {
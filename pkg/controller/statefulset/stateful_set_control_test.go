@@ -4586,6 +4586,238 @@ func newRevisionOrDie(set *appsv1beta1.StatefulSet, revision int64) *apps.Contro
 	return rev
 }
 
+func TestStatefulSetControlOrdinalAffinity(t *testing.T) {
+	poolTerm := func(pool string) v1.NodeSelectorTerm {
+		return v1.NodeSelectorTerm{MatchExpressions: []v1.NodeSelectorRequirement{
+			{Key: "pool", Operator: v1.NodeSelectorOpIn, Values: []string{pool}},
+		}}
+	}
+	set := newStatefulSet(4)
+	set.Spec.OrdinalAffinity = []appsv1beta1.StatefulSetOrdinalAffinity{
+		{Ordinals: []intstr.IntOrString{intstr.FromString("0-1")}, NodeSelectorTerm: poolTerm("pool-a")},
+		{Ordinals: []intstr.IntOrString{intstr.FromString("2-3")}, NodeSelectorTerm: poolTerm("pool-b")},
+	}
+	client := fake.NewSimpleClientset()
+	kruiseClient := kruisefake.NewSimpleClientset(set)
+	om, _, ssc, stop := setupController(client, kruiseClient)
+	defer close(stop)
+	if err := scaleUpStatefulSetControl(set, ssc, om, assertMonotonicInvariants); err != nil {
+		t.Fatal(err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(set.Spec.Selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pods, err := om.podsLister.Pods(set.Namespace).List(selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Sort(ascendingOrdinal(pods))
+	for i, pod := range pods {
+		expected := "pool-a"
+		if i >= 2 {
+			expected = "pool-b"
+		}
+		terms := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		if len(terms) != 1 || terms[0].MatchExpressions[0].Values[0] != expected {
+			t.Fatalf("expected pod %s pinned to %s, got %v", pod.Name, expected, terms)
+		}
+	}
+	oldPods := map[string]*v1.Pod{}
+	for _, pod := range pods {
+		oldPods[pod.Name] = pod
+	}
+
+	// move ordinal 3 from pool-b to pool-c, only the pod of ordinal 3 should be recreated
+	set, err = om.setsLister.StatefulSets(set.Namespace).Get(set.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set.Spec.OrdinalAffinity = []appsv1beta1.StatefulSetOrdinalAffinity{
+		{Ordinals: []intstr.IntOrString{intstr.FromString("0-1")}, NodeSelectorTerm: poolTerm("pool-a")},
+		{Ordinals: []intstr.IntOrString{intstr.FromInt32(2)}, NodeSelectorTerm: poolTerm("pool-b")},
+		{Ordinals: []intstr.IntOrString{intstr.FromInt32(3)}, NodeSelectorTerm: poolTerm("pool-c")},
+	}
+	for i := 0; ; i++ {
+		if i > 50 {
+			t.Fatalf("ordinal affinity not rolled out")
+		}
+		if err = ssc.UpdateStatefulSet(context.TODO(), set, pods); err != nil {
+			t.Fatal(err)
+		}
+		if pods, err = om.podsLister.Pods(set.Namespace).List(selector); err != nil {
+			t.Fatal(err)
+		}
+		sort.Sort(ascendingOrdinal(pods))
+		completed := len(pods) == 4
+		for ord, pod := range pods {
+			switch pod.Status.Phase {
+			case "":
+				pods, err = om.setPodPending(set, ord)
+			case v1.PodPending:
+				pods, err = om.setPodRunning(set, ord)
+			case v1.PodRunning:
+				if !podutil.IsPodReady(pod) {
+					pods, err = om.setPodReady(set, ord)
+				}
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			completed = completed && podutil.IsPodReady(pod) && ordinalAffinityMatches(set, pod)
+		}
+		if completed {
+			break
+		}
+	}
+
+	for _, pod := range pods {
+		recreated := oldPods[pod.Name] != pod
+		if getOrdinal(pod) == 3 {
+			terms := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+			if !recreated || terms[0].MatchExpressions[0].Values[0] != "pool-c" {
+				t.Fatalf("expected pod %s recreated to pool-c, got %v", pod.Name, terms)
+			}
+		} else if recreated {
+			t.Fatalf("expected pod %s not recreated", pod.Name)
+		}
+	}
+}
+
+func TestStatefulSetControlHibernation(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.StatefulSetAutoDeletePVC, true)()
+	// node-1 disappears during the hibernation
+	defer func(fn func(string) (bool, error)) { nodeExists = fn }(nodeExists)
+	nodeExists = func(name string) (bool, error) { return name != "node-1", nil }
+
+	set := newStatefulSet(3)
+	set.Spec.Hibernation = &appsv1beta1.StatefulSetHibernation{Enabled: true}
+	set.Spec.PersistentVolumeClaimRetentionPolicy = &appsv1beta1.StatefulSetPersistentVolumeClaimRetentionPolicy{
+		WhenScaled:  appsv1beta1.DeletePersistentVolumeClaimRetentionPolicyType,
+		WhenDeleted: appsv1beta1.RetainPersistentVolumeClaimRetentionPolicyType,
+	}
+	client := fake.NewSimpleClientset()
+	kruiseClient := kruisefake.NewSimpleClientset(set)
+	om, _, ssc, stop := setupController(client, kruiseClient)
+	defer close(stop)
+	if err := scaleUpStatefulSetControl(set, ssc, om, emptyInvariants); err != nil {
+		t.Fatal(err)
+	}
+	selector, err := metav1.LabelSelectorAsSelector(set.Spec.Selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scheduleAll := func() {
+		pods, err := om.podsLister.Pods(set.Namespace).List(selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, pod := range pods {
+			pod = pod.DeepCopy()
+			pod.Spec.NodeName = fmt.Sprintf("node-%d", getOrdinal(pod))
+			om.podsIndexer.Update(pod)
+		}
+		claims, err := om.claimsLister.PersistentVolumeClaims(set.Namespace).List(labels.Everything())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, claim := range claims {
+			claim = claim.DeepCopy()
+			claim.Spec.VolumeName = "pv-" + claim.Name
+			om.claimsIndexer.Update(claim)
+		}
+	}
+	scheduleAll()
+
+	// hibernate
+	if set, err = om.setsLister.StatefulSets(set.Namespace).Get(set.Name); err != nil {
+		t.Fatal(err)
+	}
+	*set.Spec.Replicas = 0
+	if err = scaleDownStatefulSetControl(set, ssc, om, emptyInvariants); err != nil {
+		t.Fatal(err)
+	}
+	if set, err = om.setsLister.StatefulSets(set.Namespace).Get(set.Name); err != nil {
+		t.Fatal(err)
+	}
+	if set.Status.Replicas != 0 || len(set.Status.HibernatedReplicas) != 3 {
+		t.Fatalf("expected 3 hibernated replicas and no pod, got %d pods and records %v", set.Status.Replicas, set.Status.HibernatedReplicas)
+	}
+	for i, record := range set.Status.HibernatedReplicas {
+		claimName := fmt.Sprintf("datadir-%s", getPodName(set, i))
+		expected := appsv1beta1.StatefulSetHibernatedReplica{
+			Ordinal:                int32(i),
+			NodeName:               fmt.Sprintf("node-%d", i),
+			PersistentVolumeClaims: []appsv1beta1.StatefulSetHibernatedClaim{{Name: claimName, VolumeName: "pv-" + claimName}},
+		}
+		if !reflect.DeepEqual(record, expected) {
+			t.Fatalf("expected record %v, got %v", expected, record)
+		}
+		// the PVCs are retained regardless of the WhenScaled policy
+		claim, err := om.claimsLister.PersistentVolumeClaims(set.Namespace).Get(claimName)
+		if err != nil {
+			t.Fatalf("expected PVC %s retained: %v", claimName, err)
+		}
+		if !claimOwnerMatchesSetAndPod(claim, set, &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: getPodName(set, i)}}) {
+			t.Fatalf("expected PVC %s not owned by the pod, got %v", claimName, claim.OwnerReferences)
+		}
+	}
+	if cond := GetStatefulsetConditition(set.Status, appsv1beta1.StatefulSetHibernated); cond == nil || cond.Status != v1.ConditionTrue {
+		t.Fatalf("expected Hibernated condition true, got %v", cond)
+	}
+
+	// resume
+	*set.Spec.Replicas = 3
+	if err = scaleUpStatefulSetControl(set, ssc, om, emptyInvariants); err != nil {
+		t.Fatal(err)
+	}
+	if set, err = om.setsLister.StatefulSets(set.Namespace).Get(set.Name); err != nil {
+		t.Fatal(err)
+	}
+	pods, err := om.podsLister.Pods(set.Namespace).List(selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Sort(ascendingOrdinal(pods))
+	for i, pod := range pods {
+		var preferred []v1.PreferredSchedulingTerm
+		if pod.Spec.Affinity != nil && pod.Spec.Affinity.NodeAffinity != nil {
+			preferred = pod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+		}
+		if i == 1 {
+			if len(preferred) != 0 {
+				t.Fatalf("expected no preferred node of pod %s whose node has disappeared, got %v", pod.Name, preferred)
+			}
+			continue
+		}
+		if len(preferred) != 1 || preferred[0].Preference.MatchFields[0].Values[0] != fmt.Sprintf("node-%d", i) {
+			t.Fatalf("expected pod %s prefers node-%d, got %v", pod.Name, i, preferred)
+		}
+	}
+	if cond := GetStatefulsetConditition(set.Status, appsv1beta1.StatefulSetHibernated); cond == nil || cond.Reason != "Resuming" {
+		t.Fatalf("expected Hibernated condition resuming, got %v", cond)
+	}
+
+	// the records are removed after the pods are scheduled
+	scheduleAll()
+	if pods, err = om.podsLister.Pods(set.Namespace).List(selector); err != nil {
+		t.Fatal(err)
+	}
+	if err = ssc.UpdateStatefulSet(context.TODO(), set, pods); err != nil {
+		t.Fatal(err)
+	}
+	if set, err = om.setsLister.StatefulSets(set.Namespace).Get(set.Name); err != nil {
+		t.Fatal(err)
+	}
+	if len(set.Status.HibernatedReplicas) != 0 {
+		t.Fatalf("expected no hibernated replicas, got %v", set.Status.HibernatedReplicas)
+	}
+	if cond := GetStatefulsetConditition(set.Status, appsv1beta1.StatefulSetHibernated); cond == nil || cond.Status != v1.ConditionFalse || cond.Reason != "Active" {
+		t.Fatalf("expected Hibernated condition false, got %v", cond)
+	}
+}
+
 func TestScaleUpWithMaxUnavailable(t *testing.T) {
 	set := newStatefulSet(5)
 	set.Spec.PodManagementPolicy = apps.ParallelPodManagement
@@ -0,0 +1,230 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statefulset
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+)
+
+// hibernatedNodeAffinityWeight is the weight of the preferred node affinity to the recorded node on resume.
+const hibernatedNodeAffinityWeight = 100
+
+// nodeExists returns whether the node exists, which is a var so that it can be replaced in tests.
+var nodeExists = func(name string) (bool, error) {
+	if sigsruntimeClient == nil {
+		return true, nil
+	}
+	err := sigsruntimeClient.Get(context.TODO(), types.NamespacedName{Name: name}, &v1.Node{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func isHibernationEnabled(set *appsv1beta1.StatefulSet) bool {
+	return set.Spec.Hibernation != nil && set.Spec.Hibernation.Enabled
+}
+
+// isHibernating returns true if the StatefulSet with hibernation enabled is scaled to zero.
+func isHibernating(set *appsv1beta1.StatefulSet) bool {
+	return isHibernationEnabled(set) && set.Spec.Replicas != nil && *set.Spec.Replicas == 0
+}
+
+// getHibernatedReplicas returns the records of the ordinals for status.hibernatedReplicas. While hibernating, the node
+// and PVCs of every Pod are recorded before it is deleted. After resume, the record of an ordinal is removed once its
+// new Pod is scheduled or it is out of the replicas, and the node of a record is cleared if the node has disappeared.
+func (ssc *defaultStatefulSetControl) getHibernatedReplicas(set *appsv1beta1.StatefulSet, pods []*v1.Pod) []appsv1beta1.StatefulSetHibernatedReplica {
+	if !isHibernationEnabled(set) {
+		return nil
+	}
+
+	records := make(map[int32]*appsv1beta1.StatefulSetHibernatedReplica, len(set.Status.HibernatedReplicas))
+	for i := range set.Status.HibernatedReplicas {
+		record := set.Status.HibernatedReplicas[i].DeepCopy()
+		records[record.Ordinal] = record
+	}
+
+	hibernating := isHibernating(set)
+	for _, pod := range pods {
+		ord := getOrdinal(pod)
+		if ord < 0 {
+			continue
+		}
+		ordinal := int32(ord)
+		if !hibernating {
+			if records[ordinal] != nil && pod.Spec.NodeName != "" && !isTerminating(pod) {
+				delete(records, ordinal)
+			}
+			continue
+		}
+
+		record := &appsv1beta1.StatefulSetHibernatedReplica{Ordinal: ordinal, NodeName: pod.Spec.NodeName}
+		oldRecord := records[ordinal]
+		if record.NodeName == "" && oldRecord != nil {
+			record.NodeName = oldRecord.NodeName
+		}
+		record.PersistentVolumeClaims = ssc.getHibernatedClaims(set, pod, oldRecord)
+		records[ordinal] = record
+	}
+
+	if !hibernating {
+		startOrdinal, endOrdinal, reserveOrdinals := getStatefulSetReplicasRange(set)
+		for ordinal, record := range records {
+			// the ordinals not resumed will never be scheduled
+			if ord := int(ordinal); ord < startOrdinal || ord >= endOrdinal || reserveOrdinals.Has(ord) {
+				delete(records, ordinal)
+				continue
+			}
+			if record.NodeName == "" {
+				continue
+			}
+			if exists, err := nodeExists(record.NodeName); err != nil {
+				klog.ErrorS(err, "Failed to get the recorded node of hibernated StatefulSet", "statefulSet", klog.KObj(set), "node", record.NodeName)
+			} else if !exists {
+				klog.V(3).InfoS("Recorded node of hibernated StatefulSet has disappeared", "statefulSet", klog.KObj(set), "ordinal", record.Ordinal, "node", record.NodeName)
+				record.NodeName = ""
+			}
+		}
+	}
+
+	if len(records) == 0 {
+		return nil
+	}
+	hibernatedReplicas := make([]appsv1beta1.StatefulSetHibernatedReplica, 0, len(records))
+	for _, record := range records {
+		hibernatedReplicas = append(hibernatedReplicas, *record)
+	}
+	sort.Slice(hibernatedReplicas, func(i, j int) bool {
+		return hibernatedReplicas[i].Ordinal < hibernatedReplicas[j].Ordinal
+	})
+	return hibernatedReplicas
+}
+
+// getHibernatedClaims returns the PVCs of the Pod with the volumes bound to them. The volume in the old record is
+// kept if the PVC can not be got.
+func (ssc *defaultStatefulSetControl) getHibernatedClaims(set *appsv1beta1.StatefulSet, pod *v1.Pod,
+	oldRecord *appsv1beta1.StatefulSetHibernatedReplica) []appsv1beta1.StatefulSetHibernatedClaim {
+	oldVolumes := map[string]string{}
+	if oldRecord != nil {
+		for _, claim := range oldRecord.PersistentVolumeClaims {
+			oldVolumes[claim.Name] = claim.VolumeName
+		}
+	}
+
+	var claims []appsv1beta1.StatefulSetHibernatedClaim
+	for _, claim := range getPersistentVolumeClaims(set, pod) {
+		hibernatedClaim := appsv1beta1.StatefulSetHibernatedClaim{Name: claim.Name, VolumeName: oldVolumes[claim.Name]}
+		pvc, err := ssc.podControl.objectMgr.GetClaim(claim.Namespace, claim.Name)
+		if err == nil {
+			hibernatedClaim.VolumeName = pvc.Spec.VolumeName
+		} else if !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to get PVC of hibernated StatefulSet", "statefulSet", klog.KObj(set), "claim", claim.Name)
+		}
+		claims = append(claims, hibernatedClaim)
+	}
+	sort.Slice(claims, func(i, j int) bool {
+		return claims[i].Name < claims[j].Name
+	})
+	return claims
+}
+
+// applyHibernatedNodeAffinity adds the preferred node affinity to the recorded node of the ordinal to the Pod,
+// so that the Pod recreated on resume is likely to be scheduled to where its local state is.
+func applyHibernatedNodeAffinity(pod *v1.Pod, ordinal int, hibernatedReplicas []appsv1beta1.StatefulSetHibernatedReplica) {
+	var nodeName string
+	for i := range hibernatedReplicas {
+		if hibernatedReplicas[i].Ordinal == int32(ordinal) {
+			nodeName = hibernatedReplicas[i].NodeName
+			break
+		}
+	}
+	if nodeName == "" {
+		return
+	}
+
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &v1.Affinity{}
+	}
+	if pod.Spec.Affinity.NodeAffinity == nil {
+		pod.Spec.Affinity.NodeAffinity = &v1.NodeAffinity{}
+	}
+	pod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+		pod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+		v1.PreferredSchedulingTerm{
+			Weight: hibernatedNodeAffinityWeight,
+			Preference: v1.NodeSelectorTerm{
+				MatchFields: []v1.NodeSelectorRequirement{{
+					Key:      "metadata.name",
+					Operator: v1.NodeSelectorOpIn,
+					Values:   []string{nodeName},
+				}},
+			},
+		},
+	)
+}
+
+// updateHibernatedCondition sets the Hibernated condition in status if hibernation is enabled.
+// The condition is true only if the StatefulSet is scaled to zero and all of its Pods have been deleted.
+func updateHibernatedCondition(set *appsv1beta1.StatefulSet, status *appsv1beta1.StatefulSetStatus) {
+	if !isHibernationEnabled(set) {
+		return
+	}
+
+	condition := NewStatefulsetCondition(appsv1beta1.StatefulSetHibernated, v1.ConditionFalse, "Active", "")
+	switch {
+	case isHibernating(set) && status.Replicas == 0:
+		condition = NewStatefulsetCondition(appsv1beta1.StatefulSetHibernated, v1.ConditionTrue, "Hibernated",
+			fmt.Sprintf("%d replicas recorded", len(status.HibernatedReplicas)))
+	case isHibernating(set):
+		condition.Reason = "Hibernating"
+		condition.Message = fmt.Sprintf("waiting for %d pods to be deleted", status.Replicas)
+	case len(status.HibernatedReplicas) > 0:
+		condition.Reason = "Resuming"
+		condition.Message = fmt.Sprintf("waiting for %d pods to be scheduled", len(status.HibernatedReplicas))
+	}
+
+	// the conditions are calculated in every reconcile, keep the transition time in the current status
+	if oldCondition := GetStatefulsetConditition(set.Status, appsv1beta1.StatefulSetHibernated); oldCondition != nil &&
+		oldCondition.Status == condition.Status {
+		condition.LastTransitionTime = oldCondition.LastTransitionTime
+	}
+	SetStatefulsetCondition(status, condition)
+}
+
+// hibernationStatusChanged returns true if the hibernated replicas or the Hibernated condition has been changed.
+func hibernationStatusChanged(set *appsv1beta1.StatefulSet, status *appsv1beta1.StatefulSetStatus) bool {
+	if !apiequality.Semantic.DeepEqual(set.Status.HibernatedReplicas, status.HibernatedReplicas) {
+		return true
+	}
+	oldCondition := GetStatefulsetConditition(set.Status, appsv1beta1.StatefulSetHibernated)
+	newCondition := GetStatefulsetConditition(*status, appsv1beta1.StatefulSetHibernated)
+	if oldCondition == nil || newCondition == nil {
+		return (oldCondition == nil) != (newCondition == nil)
+	}
+	return oldCondition.Status != newCondition.Status || oldCondition.Reason != newCondition.Reason ||
+		oldCondition.Message != newCondition.Message
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statefulset
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	hashutil "k8s.io/kubernetes/pkg/util/hash"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	apiutil "github.com/openkruise/kruise/pkg/util/api"
+)
+
+// OrdinalAffinityHashAnnotationKey is the hash of the nodeSelectorTerm in spec.ordinalAffinity that the Pod is created with.
+const OrdinalAffinityHashAnnotationKey = "apps.kruise.io/ordinal-affinity-hash"
+
+// getOrdinalNodeSelectorTerm returns the nodeSelectorTerm in spec.ordinalAffinity for the ordinal,
+// or nil if no entry matches it.
+func getOrdinalNodeSelectorTerm(set *appsv1beta1.StatefulSet, ordinal int) *v1.NodeSelectorTerm {
+	for i := range set.Spec.OrdinalAffinity {
+		if apiutil.GetReserveOrdinalIntSet(set.Spec.OrdinalAffinity[i].Ordinals).Has(ordinal) {
+			return &set.Spec.OrdinalAffinity[i].NodeSelectorTerm
+		}
+	}
+	return nil
+}
+
+func hashNodeSelectorTerm(term *v1.NodeSelectorTerm) string {
+	if term == nil {
+		return ""
+	}
+	hash := fnv.New32a()
+	termJSON, _ := json.Marshal(term)
+	hashutil.DeepHashObject(hash, termJSON)
+	return strconv.FormatUint(uint64(hash.Sum32()), 10)
+}
+
+// applyOrdinalAffinity merges the nodeSelectorTerm for the ordinal into the required node affinity of the Pod,
+// which means the Pod must be scheduled to the nodes matching both of them.
+func applyOrdinalAffinity(set *appsv1beta1.StatefulSet, pod *v1.Pod, ordinal int) {
+	term := getOrdinalNodeSelectorTerm(set, ordinal)
+	if term == nil {
+		return
+	}
+
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &v1.Affinity{}
+	}
+	if pod.Spec.Affinity.NodeAffinity == nil {
+		pod.Spec.Affinity.NodeAffinity = &v1.NodeAffinity{}
+	}
+	nodeAffinity := pod.Spec.Affinity.NodeAffinity
+	if nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil ||
+		len(nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms) == 0 {
+		nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &v1.NodeSelector{
+			NodeSelectorTerms: []v1.NodeSelectorTerm{*term.DeepCopy()},
+		}
+	} else {
+		// terms are ORed, so the ordinal term should be ANDed into each of them
+		terms := nodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		for i := range terms {
+			terms[i].MatchExpressions = append(terms[i].MatchExpressions, term.DeepCopy().MatchExpressions...)
+			terms[i].MatchFields = append(terms[i].MatchFields, term.DeepCopy().MatchFields...)
+		}
+	}
+
+	if pod.Annotations == nil {
+		pod.Annotations = make(map[string]string)
+	}
+	pod.Annotations[OrdinalAffinityHashAnnotationKey] = hashNodeSelectorTerm(term)
+}
+
+// ordinalAffinityMatches returns false if the nodeSelectorTerm for the ordinal of the Pod has been changed
+// since the Pod was created, so that the Pod should be recreated.
+func ordinalAffinityMatches(set *appsv1beta1.StatefulSet, pod *v1.Pod) bool {
+	expected := hashNodeSelectorTerm(getOrdinalNodeSelectorTerm(set, getOrdinal(pod)))
+	return pod.Annotations[OrdinalAffinityHashAnnotationKey] == expected
+}
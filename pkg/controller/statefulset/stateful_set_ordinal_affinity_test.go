@@ -0,0 +1,113 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statefulset
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+)
+
+func TestApplyOrdinalAffinity(t *testing.T) {
+	poolRequirement := func(pool string) v1.NodeSelectorRequirement {
+		return v1.NodeSelectorRequirement{Key: "pool", Operator: v1.NodeSelectorOpIn, Values: []string{pool}}
+	}
+	zoneRequirement := func(zone string) v1.NodeSelectorRequirement {
+		return v1.NodeSelectorRequirement{Key: "zone", Operator: v1.NodeSelectorOpIn, Values: []string{zone}}
+	}
+
+	set := newStatefulSet(25)
+	set.Spec.OrdinalAffinity = []appsv1beta1.StatefulSetOrdinalAffinity{
+		{
+			Ordinals:         []intstr.IntOrString{intstr.FromString("0-9")},
+			NodeSelectorTerm: v1.NodeSelectorTerm{MatchExpressions: []v1.NodeSelectorRequirement{poolRequirement("pool-a")}},
+		},
+		{
+			Ordinals:         []intstr.IntOrString{intstr.FromString("10-19"), intstr.FromInt32(21)},
+			NodeSelectorTerm: v1.NodeSelectorTerm{MatchExpressions: []v1.NodeSelectorRequirement{poolRequirement("pool-b")}},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		ordinal  int
+		affinity *v1.Affinity
+		expected []v1.NodeSelectorTerm
+	}{
+		{
+			name:     "first range",
+			ordinal:  9,
+			expected: []v1.NodeSelectorTerm{{MatchExpressions: []v1.NodeSelectorRequirement{poolRequirement("pool-a")}}},
+		},
+		{
+			name:     "single ordinal",
+			ordinal:  21,
+			expected: []v1.NodeSelectorTerm{{MatchExpressions: []v1.NodeSelectorRequirement{poolRequirement("pool-b")}}},
+		},
+		{
+			name:    "not pinned",
+			ordinal: 20,
+		},
+		{
+			name:    "merged into existing terms",
+			ordinal: 10,
+			affinity: &v1.Affinity{NodeAffinity: &v1.NodeAffinity{RequiredDuringSchedulingIgnoredDuringExecution: &v1.NodeSelector{
+				NodeSelectorTerms: []v1.NodeSelectorTerm{
+					{MatchExpressions: []v1.NodeSelectorRequirement{zoneRequirement("zone-a")}},
+					{MatchExpressions: []v1.NodeSelectorRequirement{zoneRequirement("zone-b")}},
+				},
+			}}},
+			expected: []v1.NodeSelectorTerm{
+				{MatchExpressions: []v1.NodeSelectorRequirement{zoneRequirement("zone-a"), poolRequirement("pool-b")}},
+				{MatchExpressions: []v1.NodeSelectorRequirement{zoneRequirement("zone-b"), poolRequirement("pool-b")}},
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			set := set.DeepCopy()
+			set.Spec.Template.Spec.Affinity = testCase.affinity
+			pod := newStatefulSetPod(set, testCase.ordinal)
+
+			var terms []v1.NodeSelectorTerm
+			if pod.Spec.Affinity != nil && pod.Spec.Affinity.NodeAffinity != nil &&
+				pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+				terms = pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+			}
+			if !reflect.DeepEqual(terms, testCase.expected) {
+				t.Fatalf("expected node selector terms %v, got %v", testCase.expected, terms)
+			}
+			if _, ok := pod.Annotations[OrdinalAffinityHashAnnotationKey]; ok != (testCase.expected != nil) {
+				t.Fatalf("unexpected annotations %v", pod.Annotations)
+			}
+			if !ordinalAffinityMatches(set, pod) {
+				t.Fatalf("expected ordinal affinity matched")
+			}
+
+			// the pod should be recreated once its nodeSelectorTerm changed
+			set.Spec.OrdinalAffinity[1].NodeSelectorTerm.MatchExpressions[0].Values = []string{"pool-c"}
+			if matched := ordinalAffinityMatches(set, pod); matched == (testCase.ordinal >= 10 && testCase.ordinal != 20) {
+				t.Fatalf("unexpected ordinal affinity matched %v after changed", matched)
+			}
+		})
+	}
+}
@@ -157,6 +157,11 @@ func getPersistentVolumeClaimRetentionPolicy(set *appsv1beta1.StatefulSet) appsv
 	if set.Spec.PersistentVolumeClaimRetentionPolicy != nil {
 		policy = *set.Spec.PersistentVolumeClaimRetentionPolicy
 	}
+	// the PVCs are always retained while hibernating or resuming, so that they can be reused by the recorded ordinals,
+	// and a normal scale down with hibernation enabled still follows the policy
+	if isHibernating(set) || (isHibernationEnabled(set) && len(set.Status.HibernatedReplicas) > 0) {
+		policy.WhenScaled = appsv1beta1.RetainPersistentVolumeClaimRetentionPolicyType
+	}
 	return policy
 }
 
@@ -497,6 +502,7 @@ func newStatefulSetPod(set *appsv1beta1.StatefulSet, ordinal int) *v1.Pod {
 	pod.Name = getPodName(set, ordinal)
 	initIdentity(set, pod)
 	updateStorage(set, pod)
+	applyOrdinalAffinity(set, pod, ordinal)
 	return pod
 }
 
@@ -652,7 +658,8 @@ func inconsistentStatus(set *appsv1beta1.StatefulSet, status *appsv1beta1.Statef
 		status.UpdatedReplicas != set.Status.UpdatedReplicas ||
 		status.CurrentRevision != set.Status.CurrentRevision ||
 		status.UpdateRevision != set.Status.UpdateRevision ||
-		status.LabelSelector != set.Status.LabelSelector {
+		status.LabelSelector != set.Status.LabelSelector ||
+		hibernationStatusChanged(set, status) {
 		return true
 	}
 
@@ -342,6 +342,25 @@ func TestGetPersistentVolumeClaimRetentionPolicy(t *testing.T) {
 	if got.WhenScaled != appsv1beta1.DeletePersistentVolumeClaimRetentionPolicyType || got.WhenDeleted != appsv1beta1.RetainPersistentVolumeClaimRetentionPolicyType {
 		t.Errorf("Expected scaledown policy")
 	}
+
+	// scaling down from 5 to 3 with hibernation enabled is not a hibernation
+	set.Spec.Hibernation = &appsv1beta1.StatefulSetHibernation{Enabled: true}
+	set.Spec.Replicas = ptr.To[int32](3)
+	got = getPersistentVolumeClaimRetentionPolicy(&set)
+	if got.WhenScaled != appsv1beta1.DeletePersistentVolumeClaimRetentionPolicyType {
+		t.Errorf("Expected scaledown policy with hibernation enabled but not hibernating")
+	}
+	set.Spec.Replicas = ptr.To[int32](0)
+	got = getPersistentVolumeClaimRetentionPolicy(&set)
+	if got.WhenScaled != appsv1beta1.RetainPersistentVolumeClaimRetentionPolicyType {
+		t.Errorf("Expected retain policy while hibernating")
+	}
+	set.Spec.Replicas = ptr.To[int32](3)
+	set.Status.HibernatedReplicas = []appsv1beta1.StatefulSetHibernatedReplica{{Ordinal: 2, NodeName: "node-a"}}
+	got = getPersistentVolumeClaimRetentionPolicy(&set)
+	if got.WhenScaled != appsv1beta1.RetainPersistentVolumeClaimRetentionPolicyType {
+		t.Errorf("Expected retain policy while resuming")
+	}
 }
 
 func TestClaimOwnerMatchesSetAndPod(t *testing.T) {
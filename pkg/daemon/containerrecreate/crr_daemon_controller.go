@@ -244,11 +244,20 @@ func (c *Controller) sync(key string) (retErr error) {
 		}
 	}()
 
-	// once first update its phase to recreating
+	// once first update its phase to recreating, unless it is held for PodUnavailableBudget
 	if crr.Status.Phase != appsv1alpha1.ContainerRecreateRequestRecreating {
+		if crr.Status.Phase == appsv1alpha1.ContainerRecreateRequestPendingPUB && !isCRRAdmittedByPUB(crr) {
+			klog.InfoS("CRR is pending for PodUnavailableBudget", "namespace", crr.Namespace, "name", crr.Name)
+			return nil
+		}
 		return c.updateCRRPhase(crr, appsv1alpha1.ContainerRecreateRequestRecreating)
 	}
 
+	if !isCRRAdmittedByPUB(crr) {
+		klog.InfoS("CRR is waiting for PodUnavailableBudget admission", "namespace", crr.Namespace, "name", crr.Name)
+		return nil
+	}
+
 	if crr.Spec.Strategy.UnreadyGracePeriodSeconds != nil {
 		unreadyTimeStr := crr.Annotations[appsv1alpha1.ContainerRecreateRequestUnreadyAcquiredKey]
 		if unreadyTimeStr == "" {
@@ -353,19 +362,49 @@ func (c *Controller) manage(crr *appsv1alpha1.ContainerRecreateRequest) error {
 			break
 		}
 
+		// wait for the interval after the previous container has been recreated and ready
+		if crr.Spec.Strategy.OrderedRecreate && i > 0 {
+			if leftTime := getRecreateIntervalLeftTime(crr, &newCRRContainerRecreateStates[i-1]); leftTime > 0 {
+				klog.InfoS("CRR is waiting for recreate interval", "namespace", crr.Namespace, "name", crr.Name, "containerName", state.Name, "leftTime", leftTime)
+				c.queue.AddAfter(objectKey(crr), leftTime+100*time.Millisecond)
+				break
+			}
+		}
+
+		if crrContainer := getCRRContainer(crr, state.Name); crrContainer != nil && crrContainer.PreStopHook != nil {
+			if err := c.runPreStopHook(runtimeManager, crr, crrContainer, kubeContainerStatus.ID); err != nil {
+				klog.ErrorS(err, "Failed to run preStopHook of container in Pod for CRR", "containerName", state.Name, "podNamespace", pod.Namespace, "podName", pod.Name, "crrNamespace", crr.Namespace, "crrName", crr.Name)
+				state.PreStopHookMessage = err.Error()
+				if crrContainer.PreStopHook.FailurePolicy != appsv1alpha1.ContainerRecreateRequestFailurePolicyIgnore {
+					state.Phase = appsv1alpha1.ContainerRecreateRequestFailed
+					state.Message = fmt.Sprintf("preStopHook error: %v", err)
+					now := metav1.Now()
+					state.CompletionTime = &now
+					if crr.Spec.Strategy.FailurePolicy == appsv1alpha1.ContainerRecreateRequestFailurePolicyIgnore {
+						continue
+					}
+					return c.patchCRRContainerRecreateStates(crr, newCRRContainerRecreateStates)
+				}
+			}
+		}
+
 		msg := fmt.Sprintf("Stopping container %s by ContainerRecreateRequest %s", state.Name, crr.Name)
 		err := runtimeManager.KillContainer(pod, kubeContainerStatus.ID, state.Name, msg, nil)
 		if err != nil {
 			klog.ErrorS(err, "Failed to kill container in Pod for CRR", "containerName", state.Name, "podNamespace", pod.Namespace, "podName", pod.Name, "crrNamespace", crr.Namespace, "crrName", crr.Name)
 			state.Phase = appsv1alpha1.ContainerRecreateRequestFailed
 			state.Message = fmt.Sprintf("kill container error: %v", err)
+			now := metav1.Now()
+			state.CompletionTime = &now
 			if crr.Spec.Strategy.FailurePolicy == appsv1alpha1.ContainerRecreateRequestFailurePolicyIgnore {
 				continue
 			}
 			return c.patchCRRContainerRecreateStates(crr, newCRRContainerRecreateStates)
 		}
+		now := metav1.Now()
 		state.IsKilled = true
 		state.Phase = appsv1alpha1.ContainerRecreateRequestRecreating
+		state.StartTime = &now
 		break
 	}
 
@@ -400,6 +439,7 @@ func (c *Controller) patchCRRContainerRecreateStates(crr *appsv1alpha1.Container
 func (c *Controller) updateCRRPhase(crr *appsv1alpha1.ContainerRecreateRequest, phase appsv1alpha1.ContainerRecreateRequestPhase) error {
 	crr = crr.DeepCopy()
 	crr.Status.Phase = phase
+	crr.Status.Message = ""
 	oldRev := crr.ResourceVersion
 	defer func() {
 		if crr.ResourceVersion != oldRev {
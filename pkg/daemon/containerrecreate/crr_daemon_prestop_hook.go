@@ -0,0 +1,136 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerrecreate
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kubeletcontainer "k8s.io/kubernetes/pkg/kubelet/container"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+	"github.com/openkruise/kruise/pkg/daemon/kuberuntime"
+)
+
+const defaultPreStopHookTimeout = 30 * time.Second
+
+// runPreStopHook runs the preStopHook of the container before it is stopped.
+// The exec action is executed in the container by CRI, and the httpGet action is sent to the Pod IP by default.
+func (c *Controller) runPreStopHook(runtimeManager kuberuntime.Runtime, crr *appsv1alpha1.ContainerRecreateRequest,
+	crrContainer *appsv1alpha1.ContainerRecreateRequestContainer, containerID kubeletcontainer.ContainerID) error {
+
+	hook := crrContainer.PreStopHook
+	timeout := defaultPreStopHookTimeout
+	if hook.TimeoutSeconds > 0 {
+		timeout = time.Duration(hook.TimeoutSeconds) * time.Second
+	}
+
+	switch {
+	case hook.Exec != nil:
+		output, err := runtimeManager.RunInContainer(context.TODO(), containerID, hook.Exec.Command, timeout)
+		if err != nil {
+			return fmt.Errorf("exec %v failed: %v, output: %s", hook.Exec.Command, err, string(output))
+		}
+		return nil
+	case hook.HTTPGet != nil:
+		host := hook.HTTPGet.Host
+		if host == "" {
+			pod := &v1.Pod{}
+			if err := c.runtimeClient.Get(context.TODO(), types.NamespacedName{Namespace: crr.Namespace, Name: crr.Spec.PodName}, pod); err != nil {
+				return fmt.Errorf("failed to get Pod IP: %v", err)
+			}
+			if pod.Status.PodIP == "" {
+				return fmt.Errorf("no IP of Pod %s", pod.Name)
+			}
+			host = pod.Status.PodIP
+		}
+		return runHTTPGetHook(hook.HTTPGet, host, crrContainer.Ports, timeout)
+	}
+	return fmt.Errorf("no action in preStopHook")
+}
+
+// runHTTPGetHook sends the http request to the host, and regards the status codes in [200, 400) as success.
+func runHTTPGetHook(action *v1.HTTPGetAction, host string, ports []v1.ContainerPort, timeout time.Duration) error {
+	port, err := resolveContainerPort(action.Port, ports)
+	if err != nil {
+		return err
+	}
+	scheme := strings.ToLower(string(action.Scheme))
+	if scheme == "" {
+		scheme = "http"
+	}
+	path := action.Path
+	if path != "" && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	u := &url.URL{Scheme: scheme, Host: net.JoinHostPort(host, strconv.Itoa(port)), Path: path}
+	if idx := strings.Index(path, "?"); idx >= 0 {
+		u.Path, u.RawQuery = path[:idx], path[idx+1:]
+	}
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	for _, header := range action.HTTPHeaders {
+		req.Header.Add(header.Name, header.Value)
+	}
+
+	// the certificate is not verified, which is the same as the httpGet hook in kubelet
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("httpGet %s failed: %v", u.String(), err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("httpGet %s failed with status code %d", u.String(), resp.StatusCode)
+	}
+	return nil
+}
+
+// resolveContainerPort returns the port number, the named port is resolved from the ports of container.
+func resolveContainerPort(port intstr.IntOrString, ports []v1.ContainerPort) (int, error) {
+	if port.Type == intstr.Int {
+		if port.IntValue() <= 0 {
+			return 0, fmt.Errorf("invalid port %d", port.IntValue())
+		}
+		return port.IntValue(), nil
+	}
+	for _, p := range ports {
+		if p.Name == port.StrVal {
+			return int(p.ContainerPort), nil
+		}
+	}
+	if portNum, err := strconv.Atoi(port.StrVal); err == nil && portNum > 0 {
+		return portNum, nil
+	}
+	return 0, fmt.Errorf("port %s not found in container", port.StrVal)
+}
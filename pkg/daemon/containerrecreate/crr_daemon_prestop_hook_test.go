@@ -0,0 +1,68 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerrecreate
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestRunHTTPGetHook(t *testing.T) {
+	var gotPath, gotQuery, gotHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery, gotHeader = r.URL.Path, r.URL.RawQuery, r.Header.Get("X-Drain")
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	host, portStr, _ := net.SplitHostPort(server.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	ports := []v1.ContainerPort{{Name: "admin", ContainerPort: int32(port)}}
+
+	action := &v1.HTTPGetAction{
+		Path:        "drain?wait=true",
+		Port:        intstr.FromString("admin"),
+		HTTPHeaders: []v1.HTTPHeader{{Name: "X-Drain", Value: "true"}},
+	}
+	if err := runHTTPGetHook(action, host, ports, time.Second); err != nil {
+		t.Fatalf("expected success, got %v", err)
+	}
+	if gotPath != "/drain" || gotQuery != "wait=true" || gotHeader != "true" {
+		t.Fatalf("unexpected request path %q, query %q, header %q", gotPath, gotQuery, gotHeader)
+	}
+
+	action = &v1.HTTPGetAction{Path: "/fail", Port: intstr.FromInt32(int32(port))}
+	if err := runHTTPGetHook(action, host, nil, time.Second); err == nil {
+		t.Fatalf("expected error for status code 503")
+	}
+
+	action = &v1.HTTPGetAction{Path: "/", Port: intstr.FromString("unknown")}
+	if err := runHTTPGetHook(action, host, ports, time.Second); err == nil {
+		t.Fatalf("expected error for unknown port")
+	}
+}
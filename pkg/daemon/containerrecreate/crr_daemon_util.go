@@ -51,7 +51,7 @@ func (c crrListByPhaseAndCreated) phaseWeight(phase appsv1alpha1.ContainerRecrea
 	switch phase {
 	case appsv1alpha1.ContainerRecreateRequestCompleted:
 		return 1
-	case appsv1alpha1.ContainerRecreateRequestRecreating:
+	case appsv1alpha1.ContainerRecreateRequestRecreating, appsv1alpha1.ContainerRecreateRequestPendingPUB:
 		return 2
 	case appsv1alpha1.ContainerRecreateRequestPending:
 		return 3
@@ -59,6 +59,16 @@ func (c crrListByPhaseAndCreated) phaseWeight(phase appsv1alpha1.ContainerRecrea
 	return 4
 }
 
+// isCRRAdmittedByPUB returns whether the containers can be stopped without violating the PodUnavailableBudget of Pod,
+// which is checked by kruise-manager for it has the cluster view.
+func isCRRAdmittedByPUB(crr *appsv1alpha1.ContainerRecreateRequest) bool {
+	if crr.Spec.Strategy != nil && crr.Spec.Strategy.IgnorePodUnavailableBudget {
+		return true
+	}
+	_, ok := crr.Annotations[appsv1alpha1.ContainerRecreateRequestPUBAdmittedKey]
+	return ok
+}
+
 func getCurrentCRRContainersRecreateStates(
 	crr *appsv1alpha1.ContainerRecreateRequest,
 	podStatus *kubeletcontainer.PodStatus,
@@ -69,6 +79,7 @@ func getCurrentCRRContainersRecreateStates(
 		minStartedDuration = time.Duration(crr.Spec.Strategy.MinStartedSeconds) * time.Second
 	}
 
+	now := metav1.Now()
 	syncContainerStatuses := getCRRSyncContainerStatuses(crr)
 	var statuses []appsv1alpha1.ContainerRecreateRequestContainerRecreateState
 
@@ -132,12 +143,70 @@ func getCurrentCRRContainersRecreateStates(
 			}
 		}
 
+		if previousContainerRecreateState != nil {
+			currentState.StartTime = previousContainerRecreateState.StartTime
+			currentState.PreStopHookMessage = previousContainerRecreateState.PreStopHookMessage
+		}
+		if currentState.StartTime == nil && currentState.Phase != appsv1alpha1.ContainerRecreateRequestPending {
+			currentState.StartTime = &now
+		}
+		if currentState.Phase == appsv1alpha1.ContainerRecreateRequestSucceeded {
+			currentState.CompletionTime = &now
+		}
+		setContainerStoppedState(&currentState, previousContainerRecreateState, c, podStatus)
+
 		statuses = append(statuses, currentState)
 	}
 
 	return statuses
 }
 
+// getRecreateIntervalLeftTime returns the duration left to wait before stopping the next container,
+// which is counted from the time the previous container has been recreated and ready.
+func getRecreateIntervalLeftTime(
+	crr *appsv1alpha1.ContainerRecreateRequest,
+	previousState *appsv1alpha1.ContainerRecreateRequestContainerRecreateState,
+) time.Duration {
+	if crr.Spec.Strategy.RecreateIntervalSeconds <= 0 || previousState.Phase != appsv1alpha1.ContainerRecreateRequestSucceeded ||
+		previousState.CompletionTime == nil {
+		return 0
+	}
+	return time.Duration(crr.Spec.Strategy.RecreateIntervalSeconds)*time.Second - time.Since(previousState.CompletionTime.Time)
+}
+
+// setContainerStoppedState records the exit code of the old container and the ID of the new container into state.
+// They are kept once recorded, for the old container might have been removed by the runtime.
+func setContainerStoppedState(
+	state, previousState *appsv1alpha1.ContainerRecreateRequestContainerRecreateState,
+	c *appsv1alpha1.ContainerRecreateRequestContainer,
+	podStatus *kubeletcontainer.PodStatus,
+) {
+	if previousState != nil {
+		state.ExitCode = previousState.ExitCode
+		state.StoppedAt = previousState.StoppedAt
+		state.NewContainerID = previousState.NewContainerID
+	}
+	if c.StatusContext == nil || c.StatusContext.ContainerID == "" || podStatus == nil {
+		return
+	}
+
+	for _, containerStatus := range podStatus.ContainerStatuses {
+		if containerStatus.Name != c.Name {
+			continue
+		}
+		if containerStatus.ID.String() == c.StatusContext.ContainerID {
+			if state.ExitCode == nil && containerStatus.State == kubeletcontainer.ContainerStateExited {
+				state.ExitCode = ptr.To(int32(containerStatus.ExitCode))
+				if !containerStatus.FinishedAt.IsZero() {
+					state.StoppedAt = ptr.To(metav1.NewTime(containerStatus.FinishedAt))
+				}
+			}
+		} else if state.NewContainerID == "" && containerStatus.State == kubeletcontainer.ContainerStateRunning {
+			state.NewContainerID = containerStatus.ID.String()
+		}
+	}
+}
+
 func getPreviousContainerKillState(previousContainerRecreateState *appsv1alpha1.ContainerRecreateRequestContainerRecreateState) bool {
 	if previousContainerRecreateState == nil {
 		return false
@@ -145,6 +214,16 @@ func getPreviousContainerKillState(previousContainerRecreateState *appsv1alpha1.
 	return previousContainerRecreateState.IsKilled
 }
 
+func getCRRContainer(crr *appsv1alpha1.ContainerRecreateRequest, name string) *appsv1alpha1.ContainerRecreateRequestContainer {
+	for i := range crr.Spec.Containers {
+		c := &crr.Spec.Containers[i]
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
 func getCRRContainerRecreateState(crr *appsv1alpha1.ContainerRecreateRequest, name string) *appsv1alpha1.ContainerRecreateRequestContainerRecreateState {
 	for i := range crr.Status.ContainerRecreateStates {
 		c := &crr.Status.ContainerRecreateStates[i]
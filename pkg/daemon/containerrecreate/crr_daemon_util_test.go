@@ -0,0 +1,150 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package containerrecreate
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeletcontainer "k8s.io/kubernetes/pkg/kubelet/container"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+func TestGetCurrentCRRContainersRecreateStatesTimes(t *testing.T) {
+	created := time.Now().Add(-time.Minute)
+	startTime := metav1.NewTime(created.Add(10 * time.Second))
+	crr := &appsv1alpha1.ContainerRecreateRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			CreationTimestamp: metav1.NewTime(created),
+			Annotations: map[string]string{
+				appsv1alpha1.ContainerRecreateRequestSyncContainerStatusesKey: `[{"name":"proxy","ready":true,"containerID":"containerd://proxy-2"}]`,
+			},
+		},
+		Spec: appsv1alpha1.ContainerRecreateRequestSpec{
+			Containers: []appsv1alpha1.ContainerRecreateRequestContainer{
+				{Name: "proxy", StatusContext: &appsv1alpha1.ContainerRecreateRequestContainerContext{ContainerID: "containerd://proxy-1"}},
+				{Name: "app", StatusContext: &appsv1alpha1.ContainerRecreateRequestContainerContext{ContainerID: "containerd://app-1"}},
+			},
+			Strategy: &appsv1alpha1.ContainerRecreateRequestStrategy{OrderedRecreate: true, RecreateIntervalSeconds: 10},
+		},
+		Status: appsv1alpha1.ContainerRecreateRequestStatus{
+			ContainerRecreateStates: []appsv1alpha1.ContainerRecreateRequestContainerRecreateState{
+				{Name: "proxy", Phase: appsv1alpha1.ContainerRecreateRequestRecreating, IsKilled: true, StartTime: &startTime},
+				{Name: "app", Phase: appsv1alpha1.ContainerRecreateRequestPending},
+			},
+		},
+	}
+	podStatus := &kubeletcontainer.PodStatus{
+		ContainerStatuses: []*kubeletcontainer.Status{
+			{
+				Name:      "proxy",
+				ID:        kubeletcontainer.ContainerID{Type: "containerd", ID: "proxy-2"},
+				State:     kubeletcontainer.ContainerStateRunning,
+				StartedAt: created.Add(20 * time.Second),
+			},
+			{
+				Name:      "app",
+				ID:        kubeletcontainer.ContainerID{Type: "containerd", ID: "app-1"},
+				State:     kubeletcontainer.ContainerStateRunning,
+				StartedAt: created.Add(-time.Hour),
+			},
+		},
+	}
+
+	states := getCurrentCRRContainersRecreateStates(crr, podStatus)
+	if states[0].Phase != appsv1alpha1.ContainerRecreateRequestSucceeded || !states[0].StartTime.Equal(&startTime) || states[0].CompletionTime == nil {
+		t.Fatalf("expected proxy succeeded with start and completion time, got %+v", states[0])
+	}
+	if states[1].Phase != appsv1alpha1.ContainerRecreateRequestPending || states[1].StartTime != nil || states[1].CompletionTime != nil {
+		t.Fatalf("expected app pending without times, got %+v", states[1])
+	}
+
+	// the next container should wait for the interval after the previous one is ready
+	if leftTime := getRecreateIntervalLeftTime(crr, &states[0]); leftTime <= 9*time.Second || leftTime > 10*time.Second {
+		t.Fatalf("expected to wait for about 10s, got %v", leftTime)
+	}
+	completionTime := metav1.NewTime(time.Now().Add(-11 * time.Second))
+	states[0].CompletionTime = &completionTime
+	if leftTime := getRecreateIntervalLeftTime(crr, &states[0]); leftTime > 0 {
+		t.Fatalf("expected no wait after the interval, got %v", leftTime)
+	}
+	crr.Spec.Strategy.RecreateIntervalSeconds = 0
+	states[0].CompletionTime = &metav1.Time{Time: time.Now()}
+	if leftTime := getRecreateIntervalLeftTime(crr, &states[0]); leftTime > 0 {
+		t.Fatalf("expected no wait without interval, got %v", leftTime)
+	}
+
+	// the succeeded state is kept as it is
+	crr.Status.ContainerRecreateStates = states
+	if newStates := getCurrentCRRContainersRecreateStates(crr, podStatus); !newStates[0].CompletionTime.Equal(states[0].CompletionTime) {
+		t.Fatalf("expected completion time unchanged, got %v", newStates[0].CompletionTime)
+	}
+}
+
+func TestGetCurrentCRRContainersRecreateStatesStoppedState(t *testing.T) {
+	created := time.Now().Add(-time.Minute)
+	stoppedAt := created.Add(5 * time.Second)
+	crr := &appsv1alpha1.ContainerRecreateRequest{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)},
+		Spec: appsv1alpha1.ContainerRecreateRequestSpec{
+			Containers: []appsv1alpha1.ContainerRecreateRequestContainer{
+				{Name: "app", StatusContext: &appsv1alpha1.ContainerRecreateRequestContainerContext{ContainerID: "containerd://app-1"}},
+			},
+			Strategy: &appsv1alpha1.ContainerRecreateRequestStrategy{},
+		},
+		Status: appsv1alpha1.ContainerRecreateRequestStatus{
+			ContainerRecreateStates: []appsv1alpha1.ContainerRecreateRequestContainerRecreateState{
+				{Name: "app", Phase: appsv1alpha1.ContainerRecreateRequestRecreating, IsKilled: true},
+			},
+		},
+	}
+	oldContainer := &kubeletcontainer.Status{
+		Name:       "app",
+		ID:         kubeletcontainer.ContainerID{Type: "containerd", ID: "app-1"},
+		State:      kubeletcontainer.ContainerStateExited,
+		ExitCode:   143,
+		FinishedAt: stoppedAt,
+	}
+	newContainer := &kubeletcontainer.Status{
+		Name:      "app",
+		ID:        kubeletcontainer.ContainerID{Type: "containerd", ID: "app-2"},
+		State:     kubeletcontainer.ContainerStateRunning,
+		StartedAt: created.Add(10 * time.Second),
+	}
+
+	// the old container has exited and the new one is not created yet
+	states := getCurrentCRRContainersRecreateStates(crr, &kubeletcontainer.PodStatus{ContainerStatuses: []*kubeletcontainer.Status{oldContainer}})
+	if states[0].ExitCode == nil || *states[0].ExitCode != 143 || !states[0].StoppedAt.Time.Equal(stoppedAt) || states[0].NewContainerID != "" {
+		t.Fatalf("expected exit code and stoppedAt of the old container, got %+v", states[0])
+	}
+
+	// the new container is running
+	crr.Status.ContainerRecreateStates = states
+	states = getCurrentCRRContainersRecreateStates(crr, &kubeletcontainer.PodStatus{ContainerStatuses: []*kubeletcontainer.Status{newContainer, oldContainer}})
+	if states[0].ExitCode == nil || *states[0].ExitCode != 143 || states[0].NewContainerID != "containerd://app-2" {
+		t.Fatalf("expected exit code and new container ID, got %+v", states[0])
+	}
+
+	// the recorded states are kept after the old container has been removed
+	crr.Status.ContainerRecreateStates = states
+	states = getCurrentCRRContainersRecreateStates(crr, &kubeletcontainer.PodStatus{ContainerStatuses: []*kubeletcontainer.Status{newContainer}})
+	if states[0].ExitCode == nil || *states[0].ExitCode != 143 || !states[0].StoppedAt.Time.Equal(stoppedAt) || states[0].NewContainerID != "containerd://app-2" {
+		t.Fatalf("expected recorded states kept, got %+v", states[0])
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package criruntime
+
+import (
+	"context"
+	"runtime/debug"
+
+	"k8s.io/klog/v2"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	runtimeimage "github.com/openkruise/kruise/pkg/daemon/criruntime/imageruntime"
+)
+
+// GetNodeCapabilities returns the capabilities of kruise-daemon with the runtime of factory.
+func GetNodeCapabilities(f Factory) *appsv1beta1.NodeCapabilities {
+	capabilities := &appsv1beta1.NodeCapabilities{DaemonVersion: getDaemonVersion()}
+	if f == nil {
+		return capabilities
+	}
+
+	if impl, ok := f.(*factory); ok && len(impl.impls) > 0 {
+		capabilities.RuntimeEndpoint = impl.impls[0].cfg.runtimeRemoteURI
+		capabilities.ContainerdNamespace = impl.impls[0].cfg.containerdNamespace
+	}
+	if imageService := f.GetImageService(); imageService != nil {
+		capabilities.Features = append(capabilities.Features, appsv1beta1.NodeFeatureImagePull)
+		// ImagePullProgress is only supported by the image services reporting the progress of layers,
+		// which is not available with the PullImage of CRI
+		if reporter, ok := imageService.(runtimeimage.ImagePullProgressReporter); ok && reporter.IsPullProgressReported() {
+			capabilities.Features = append(capabilities.Features, appsv1beta1.NodeFeatureImagePullProgress)
+		}
+	}
+	if runtimeService := f.GetRuntimeService(); runtimeService != nil {
+		// The containers are stopped by CRI regardless of whether they are in spec.containers or spec.initContainers,
+		// and kubelet starts the native sidecar containers again as their restartPolicy is Always
+		capabilities.Features = append(capabilities.Features, appsv1beta1.NodeFeatureContainerRecreate, appsv1beta1.NodeFeatureNativeSidecarRestart)
+
+		typedVersion, err := runtimeService.Version(context.TODO(), kubeRuntimeAPIVersion)
+		if err != nil {
+			klog.ErrorS(err, "Failed to get runtime typed version for capabilities")
+		} else {
+			capabilities.RuntimeName = typedVersion.RuntimeName
+			capabilities.RuntimeVersion = typedVersion.RuntimeVersion
+		}
+	}
+	return capabilities
+}
+
+func getDaemonVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+	return info.Main.Version
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package criruntime
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	criapi "k8s.io/cri-api/pkg/apis"
+	critesting "k8s.io/cri-api/pkg/apis/testing"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	runtimeimage "github.com/openkruise/kruise/pkg/daemon/criruntime/imageruntime"
+)
+
+type fakeImageService struct{}
+
+func (s *fakeImageService) PullImage(context.Context, string, string, []v1.Secret, *appsv1beta1.SandboxConfig) (runtimeimage.ImagePullStatusReader, error) {
+	return nil, nil
+}
+
+func (s *fakeImageService) ListImages(context.Context) ([]runtimeimage.ImageInfo, error) {
+	return nil, nil
+}
+
+type fakeProgressImageService struct {
+	fakeImageService
+	progressReported bool
+}
+
+func (s *fakeProgressImageService) IsPullProgressReported() bool {
+	return s.progressReported
+}
+
+type fakeFactory struct {
+	imageService   runtimeimage.ImageService
+	runtimeService criapi.RuntimeService
+}
+
+func (f *fakeFactory) GetImageService() runtimeimage.ImageService { return f.imageService }
+
+func (f *fakeFactory) GetRuntimeService() criapi.RuntimeService { return f.runtimeService }
+
+func (f *fakeFactory) GetRuntimeServiceByName(string) criapi.RuntimeService { return f.runtimeService }
+
+func TestGetNodeCapabilities(t *testing.T) {
+	tests := []struct {
+		name     string
+		factory  Factory
+		expected []appsv1beta1.NodeFeature
+	}{
+		{
+			name:    "image service without progress",
+			factory: &fakeFactory{imageService: &fakeImageService{}},
+			expected: []appsv1beta1.NodeFeature{
+				appsv1beta1.NodeFeatureImagePull,
+			},
+		},
+		{
+			name:    "image service declaring no progress",
+			factory: &fakeFactory{imageService: &fakeProgressImageService{}},
+			expected: []appsv1beta1.NodeFeature{
+				appsv1beta1.NodeFeatureImagePull,
+			},
+		},
+		{
+			name:    "image service reporting progress",
+			factory: &fakeFactory{imageService: &fakeProgressImageService{progressReported: true}},
+			expected: []appsv1beta1.NodeFeature{
+				appsv1beta1.NodeFeatureImagePull,
+				appsv1beta1.NodeFeatureImagePullProgress,
+			},
+		},
+		{
+			name:    "runtime service",
+			factory: &fakeFactory{runtimeService: critesting.NewFakeRuntimeService()},
+			expected: []appsv1beta1.NodeFeature{
+				appsv1beta1.NodeFeatureContainerRecreate,
+				appsv1beta1.NodeFeatureNativeSidecarRestart,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			capabilities := GetNodeCapabilities(tc.factory)
+			if !reflect.DeepEqual(capabilities.Features, tc.expected) {
+				t.Fatalf("expected features %v, got %v", tc.expected, capabilities.Features)
+			}
+		})
+	}
+}
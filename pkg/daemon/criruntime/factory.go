@@ -18,7 +18,9 @@ package criruntime
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"os"
 	"time"
 
 	oteltrace "go.opentelemetry.io/otel/trace"
@@ -33,6 +35,17 @@ import (
 
 const (
 	kubeRuntimeAPIVersion = "0.1.0"
+
+	// defaultContainerdNamespace is the namespace of containerd used by kubelet.
+	defaultContainerdNamespace = "k8s.io"
+)
+
+var (
+	criSocket = flag.String("cri-socket", os.Getenv("CRI_SOCKET"),
+		"The endpoint of CRI socket, such as unix:///hostvarrun/containerd/containerd.sock. "+
+			"If it is not set or not available, the runtime will be auto-detected. Defaults to the env CRI_SOCKET.")
+	containerdNamespace = flag.String("containerd-namespace", getEnvOrDefault("CONTAINERD_NAMESPACE", defaultContainerdNamespace),
+		"The namespace of containerd, which is only used when the runtime is containerd. Defaults to the env CONTAINERD_NAMESPACE or k8s.io.")
 )
 
 // Factory is the interface to get container and image runtime service
@@ -53,6 +66,16 @@ type runtimeConfig struct {
 	runtimeType      ContainerRuntimeType
 	runtimeURI       string
 	runtimeRemoteURI string
+	// containerdNamespace is only set for containerd runtime
+	containerdNamespace string
+}
+
+func newContainerdConfig(runtimeRemoteURI string) runtimeConfig {
+	return runtimeConfig{
+		runtimeType:         ContainerRuntimeContainerd,
+		runtimeRemoteURI:    runtimeRemoteURI,
+		containerdNamespace: *containerdNamespace,
+	}
 }
 
 type factory struct {
@@ -105,7 +128,7 @@ func NewFactory(accountManager daemonutil.ImagePullAccountManager) (Factory, err
 			continue
 		}
 
-		klog.V(2).InfoS("Add runtime", "runtimeName", typedVersion.RuntimeName, "runtimeURI", cfg.runtimeURI, "runtimeRemoteURI", cfg.runtimeRemoteURI)
+		klog.V(2).InfoS("Add runtime", "runtimeName", typedVersion.RuntimeName, "runtimeURI", cfg.runtimeURI, "runtimeRemoteURI", cfg.runtimeRemoteURI, "containerdNamespace", cfg.containerdNamespace)
 		f.impls = append(f.impls, &runtimeImpl{
 			cfg:            cfg,
 			runtimeName:    typedVersion.RuntimeName,
@@ -136,3 +159,10 @@ func (f *factory) GetRuntimeServiceByName(runtimeName string) criapi.RuntimeServ
 	}
 	return nil
 }
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); len(value) > 0 {
+		return value
+	}
+	return defaultValue
+}
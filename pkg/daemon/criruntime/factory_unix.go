@@ -23,6 +23,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	kubeletutil "k8s.io/cri-client/pkg/util"
 	"k8s.io/klog/v2"
@@ -43,7 +45,12 @@ var (
 func detectRuntime() (cfgs []runtimeConfig) {
 	var err error
 
-	// firstly check if it is configured from flag
+	// firstly check if the endpoint is configured from flag, and fallback to the detection if it is not available
+	if cfg, ok := getConfiguredRuntime(); ok {
+		return []runtimeConfig{cfg}
+	}
+
+	// then check if the socket file is configured from flag
 	if criSocketFileName != nil && len(*criSocketFileName) > 0 {
 		filePath := fmt.Sprintf("%s/%s", varRunMountPath, *criSocketFileName)
 		if _, err = statFunc(filePath); err == nil {
@@ -63,16 +70,10 @@ func detectRuntime() (cfgs []runtimeConfig) {
 	// containerd, with the same behavior of pullImage as commonCRI
 	{
 		if _, err = statFunc(fmt.Sprintf("%s/containerd.sock", varRunMountPath)); err == nil {
-			cfgs = append(cfgs, runtimeConfig{
-				runtimeType:      ContainerRuntimeContainerd,
-				runtimeRemoteURI: fmt.Sprintf("unix://%s/containerd.sock", varRunMountPath),
-			})
+			cfgs = append(cfgs, newContainerdConfig(fmt.Sprintf("unix://%s/containerd.sock", varRunMountPath)))
 		}
 		if _, err = statFunc(fmt.Sprintf("%s/containerd/containerd.sock", varRunMountPath)); err == nil {
-			cfgs = append(cfgs, runtimeConfig{
-				runtimeType:      ContainerRuntimeContainerd,
-				runtimeRemoteURI: fmt.Sprintf("unix://%s/containerd/containerd.sock", varRunMountPath),
-			})
+			cfgs = append(cfgs, newContainerdConfig(fmt.Sprintf("unix://%s/containerd/containerd.sock", varRunMountPath)))
 		}
 	}
 
@@ -112,11 +113,37 @@ func detectRuntime() (cfgs []runtimeConfig) {
 	return cfgs
 }
 
+// getConfiguredRuntime returns the runtime config of the endpoint configured from flag, if it is available.
+func getConfiguredRuntime() (runtimeConfig, bool) {
+	if criSocket == nil || len(*criSocket) == 0 {
+		return runtimeConfig{}, false
+	}
+
+	endpoint := *criSocket
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "unix://" + endpoint
+	}
+	filePath := strings.TrimPrefix(endpoint, "unix://")
+	if _, err := statFunc(filePath); err != nil {
+		klog.ErrorS(err, "Failed to stat the CRI socket with given flag, fallback to detect runtime", "endpoint", endpoint)
+		return runtimeConfig{}, false
+	}
+
+	klog.InfoS("Find configured CRI endpoint with given flag", "endpoint", endpoint)
+	if strings.Contains(filepath.Base(filePath), "containerd") {
+		return newContainerdConfig(endpoint), true
+	}
+	return runtimeConfig{
+		runtimeType:      ContainerRuntimeCommonCRI,
+		runtimeRemoteURI: endpoint,
+	}, true
+}
+
 func newImageService(cfg runtimeConfig, accountManager daemonutil.ImagePullAccountManager) (runtimeimage.ImageService, error) {
 	addr, _, err := kubeletutil.GetAddressAndDialer(cfg.runtimeRemoteURI)
 	if err != nil {
 		klog.ErrorS(err, "Failed to get address", "runtimeType", cfg.runtimeType, "runtimeURI", cfg.runtimeURI, "runtimeRemoteURI", cfg.runtimeRemoteURI)
 		return nil, err
 	}
-	return runtimeimage.NewCRIImageService(addr, accountManager)
+	return runtimeimage.NewCRIImageService(addr, cfg.containerdNamespace, accountManager)
 }
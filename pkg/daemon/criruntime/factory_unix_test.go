@@ -178,3 +178,71 @@ func TestDetectRuntimeUnix(t *testing.T) {
 		}
 	}
 }
+
+func TestDetectRuntimeWithCRISocket(t *testing.T) {
+	testCases := []struct {
+		name                string
+		criSocket           string
+		existingFile        string
+		runtimeType         ContainerRuntimeType
+		runtimeRemoteURI    string
+		containerdNamespace string
+	}{
+		{
+			name:                "configured containerd socket",
+			criSocket:           "/hostvarrun/k3s/containerd/containerd.sock",
+			existingFile:        "/hostvarrun/k3s/containerd/containerd.sock",
+			runtimeType:         ContainerRuntimeContainerd,
+			runtimeRemoteURI:    "unix:///hostvarrun/k3s/containerd/containerd.sock",
+			containerdNamespace: "k8s-edge",
+		},
+		{
+			name:             "configured crio endpoint",
+			criSocket:        "unix:///hostvarrun/custom/crio.sock",
+			existingFile:     "/hostvarrun/custom/crio.sock",
+			runtimeType:      ContainerRuntimeCommonCRI,
+			runtimeRemoteURI: "unix:///hostvarrun/custom/crio.sock",
+		},
+		{
+			name:                "fallback to detection if configured socket not exists",
+			criSocket:           "/hostvarrun/non-existent.sock",
+			existingFile:        "/hostvarrun/containerd.sock",
+			runtimeType:         ContainerRuntimeContainerd,
+			runtimeRemoteURI:    "unix:///hostvarrun/containerd.sock",
+			containerdNamespace: "k8s-edge",
+		},
+	}
+
+	defer func() {
+		flag.Set("cri-socket", "")
+		flag.Set("containerd-namespace", defaultContainerdNamespace)
+		statFunc = os.Stat
+	}()
+	flag.Set("socket-file", "")
+	flag.Set("containerd-namespace", "k8s-edge")
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			flag.Set("cri-socket", testCase.criSocket)
+			statFunc = func(name string) (os.FileInfo, error) {
+				if name == testCase.existingFile {
+					return &fileInfo{name: name}, nil
+				}
+				return nil, os.ErrNotExist
+			}
+
+			cfgs := detectRuntime()
+			if len(cfgs) != 1 {
+				t.Fatalf("expected 1 runtime config, got %d", len(cfgs))
+			}
+			if cfgs[0].runtimeRemoteURI != testCase.runtimeRemoteURI {
+				t.Fatalf("expected runtime remote URI to be %s, got %s", testCase.runtimeRemoteURI, cfgs[0].runtimeRemoteURI)
+			}
+			if cfgs[0].runtimeType != testCase.runtimeType {
+				t.Fatalf("expected runtime type to be %s, got %s", testCase.runtimeType, cfgs[0].runtimeType)
+			}
+			if cfgs[0].containerdNamespace != testCase.containerdNamespace {
+				t.Fatalf("expected containerd namespace to be %q, got %q", testCase.containerdNamespace, cfgs[0].containerdNamespace)
+			}
+		})
+	}
+}
@@ -31,16 +31,17 @@ var (
 	containerdRemoteURI = `npipe://./pipe/containerd-containerd`
 )
 
-// detectRuntime returns containerd runtime config
+// detectRuntime returns containerd runtime config, with the endpoint configured from flag if it is set.
 // Windows node pools support only the containerd runtime for most Kubernetes service providers.
 func detectRuntime() (cfgs []runtimeConfig) {
-	cfgs = append(cfgs, runtimeConfig{
-		runtimeType:      ContainerRuntimeContainerd,
-		runtimeRemoteURI: containerdRemoteURI,
-	})
+	remoteURI := containerdRemoteURI
+	if criSocket != nil && len(*criSocket) > 0 {
+		remoteURI = *criSocket
+	}
+	cfgs = append(cfgs, newContainerdConfig(remoteURI))
 	return cfgs
 }
 
 func newImageService(cfg runtimeConfig, accountManager daemonutil.ImagePullAccountManager) (runtimeimage.ImageService, error) {
-	return runtimeimage.NewCRIImageService(cfg.runtimeRemoteURI, accountManager)
+	return runtimeimage.NewCRIImageService(cfg.runtimeRemoteURI, cfg.containerdNamespace, accountManager)
 }
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 	v1 "k8s.io/api/core/v1"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
@@ -37,10 +38,14 @@ import (
 const (
 	maxMsgSize                    = 1024 * 1024 * 16
 	pullingImageSandboxConfigAnno = "apps.kruise.io/pulling-image-by"
+
+	// containerdNamespaceHeader is the gRPC metadata key that containerd reads the namespace from.
+	containerdNamespaceHeader = "containerd-namespace"
 )
 
-// NewCRIImageService create a common CRI runtime
-func NewCRIImageService(runtimeURI string, accountManager daemonutil.ImagePullAccountManager) (ImageService, error) {
+// NewCRIImageService create a common CRI runtime.
+// If containerdNamespace is not empty, it will be sent to the runtime with each request.
+func NewCRIImageService(runtimeURI, containerdNamespace string, accountManager daemonutil.ImagePullAccountManager) (ImageService, error) {
 	klog.V(3).InfoS("Connecting to image service", "endpoint", runtimeURI)
 	addr, dialer, err := util.GetAddressAndDialer(runtimeURI)
 	if err != nil {
@@ -50,7 +55,11 @@ func NewCRIImageService(runtimeURI string, accountManager daemonutil.ImagePullAc
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	conn, err := grpc.DialContext(ctx, addr, grpc.WithInsecure(), grpc.WithContextDialer(dialer), grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMsgSize)))
+	dialOpts := []grpc.DialOption{grpc.WithInsecure(), grpc.WithContextDialer(dialer), grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMsgSize))}
+	if len(containerdNamespace) > 0 {
+		dialOpts = append(dialOpts, grpc.WithUnaryInterceptor(containerdNamespaceInterceptor(containerdNamespace)))
+	}
+	conn, err := grpc.DialContext(ctx, addr, dialOpts...)
 	if err != nil {
 		klog.ErrorS(err, "Connect remote image service failed", "address", addr)
 		return nil, err
@@ -68,6 +77,14 @@ func NewCRIImageService(runtimeURI string, accountManager daemonutil.ImagePullAc
 	}, nil
 }
 
+// containerdNamespaceInterceptor attaches the containerd namespace to the outgoing requests.
+func containerdNamespaceInterceptor(namespace string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx = metadata.AppendToOutgoingContext(ctx, containerdNamespaceHeader, namespace)
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
 type commonCRIImageService struct {
 	accountManager         daemonutil.ImagePullAccountManager
 	criImageClient         runtimeapi.ImageServiceClient
@@ -78,6 +95,12 @@ func (c *commonCRIImageService) useV1API() bool {
 	return c.criImageClientV1alpha2 == nil || reflect.ValueOf(c.criImageClientV1alpha2).IsNil()
 }
 
+// IsPullProgressReported implements ImagePullProgressReporter.IsPullProgressReported.
+// PullImage of CRI blocks until the image is pulled, so that only the completion is reported.
+func (c *commonCRIImageService) IsPullProgressReported() bool {
+	return false
+}
+
 // PullImage implements ImageService.PullImage.
 func (c *commonCRIImageService) PullImage(ctx context.Context, imageName, tag string, pullSecrets []v1.Secret, sandboxConfig *appsv1beta1.SandboxConfig) (ImagePullStatusReader, error) {
 	if c.useV1API() {
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	"google.golang.org/grpc"
@@ -251,6 +252,17 @@ func (c ImageInfo) ContainsImage(name string, tag string) bool {
 	return false
 }
 
+// GetRepoDigest returns the digest of the image in the repository of the given name, e.g., sha256:xxx.
+func (c ImageInfo) GetRepoDigest(name string) string {
+	for _, repoDigest := range c.RepoDigests {
+		imageRepo, digest, _ := daemonutil.NormalizeImageRefToNameTag(repoDigest)
+		if imageRepo == name && strings.Contains(digest, ":") {
+			return digest
+		}
+	}
+	return ""
+}
+
 func determineImageClientAPIVersion(conn *grpc.ClientConn) (runtimeapi.ImageServiceClient, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -51,3 +51,9 @@ type ImageService interface {
 	PullImage(ctx context.Context, imageName, tag string, pullSecrets []v1.Secret, sandboxConfig *appsv1beta1.SandboxConfig) (ImagePullStatusReader, error)
 	ListImages(ctx context.Context) ([]ImageInfo, error)
 }
+
+// ImagePullProgressReporter is optionally implemented by the ImageService that is able to report the progress
+// of pulling the layers of images in ImagePullStatus.
+type ImagePullProgressReporter interface {
+	IsPullProgressReported() bool
+}
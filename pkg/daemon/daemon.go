@@ -42,6 +42,7 @@ import (
 	"github.com/openkruise/kruise/pkg/daemon/containermeta"
 	"github.com/openkruise/kruise/pkg/daemon/containerrecreate"
 	daemonruntime "github.com/openkruise/kruise/pkg/daemon/criruntime"
+	"github.com/openkruise/kruise/pkg/daemon/ephemeralcontainer"
 	"github.com/openkruise/kruise/pkg/daemon/imagepuller"
 	daemonoptions "github.com/openkruise/kruise/pkg/daemon/options"
 	"github.com/openkruise/kruise/pkg/daemon/podprobe"
@@ -167,6 +168,12 @@ func NewDaemon(cfg *rest.Config, bindAddress string, MaxWorkersForPullImages int
 			return nil, fmt.Errorf("failed to new containermeta controller: %v", err)
 		}
 		runnables = append(runnables, containerMetaController)
+
+		ephemeralContainerController, err := ephemeralcontainer.NewController(opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to new ephemeralcontainer controller: %v", err)
+		}
+		runnables = append(runnables, ephemeralContainerController)
 	}
 
 	return &daemon{
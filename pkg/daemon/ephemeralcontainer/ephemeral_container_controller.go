@@ -0,0 +1,196 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ephemeralcontainer
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	kubeletcontainer "k8s.io/kubernetes/pkg/kubelet/container"
+
+	"github.com/openkruise/kruise/pkg/client"
+	daemonruntime "github.com/openkruise/kruise/pkg/daemon/criruntime"
+	"github.com/openkruise/kruise/pkg/daemon/kuberuntime"
+	daemonoptions "github.com/openkruise/kruise/pkg/daemon/options"
+	utilephemeralcontainer "github.com/openkruise/kruise/pkg/util/ephemeralcontainer"
+)
+
+var (
+	// TODO: make it a configurable flag
+	workers = 2
+)
+
+// Controller stops the running ephemeral containers listed in the pod annotation.
+type Controller struct {
+	queue          workqueue.RateLimitingInterface
+	podLister      corelisters.PodLister
+	runtimeFactory daemonruntime.Factory
+	eventRecorder  record.EventRecorder
+}
+
+// NewController returns the Controller for stopping ephemeral containers
+func NewController(opts daemonoptions.Options) (*Controller, error) {
+	if opts.PodInformer == nil {
+		return nil, fmt.Errorf("ephemeralcontainer Controller can not run without pod informer")
+	}
+
+	queue := workqueue.NewNamedRateLimitingQueue(
+		workqueue.NewItemExponentialFailureRateLimiter(500*time.Millisecond, 50*time.Second),
+		"ephemeral_container_stop",
+	)
+
+	opts.PodInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			pod, ok := obj.(*v1.Pod)
+			if ok && hasContainersToStop(pod) {
+				enqueue(queue, pod)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			newPod := newObj.(*v1.Pod)
+			if hasContainersToStop(newPod) {
+				enqueue(queue, newPod)
+			}
+		},
+	})
+
+	genericClient := client.GetGenericClientWithName("kruise-daemon-ephemeralcontainer")
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{Interface: genericClient.KubeClient.CoreV1().Events("")})
+	recorder := eventBroadcaster.NewRecorder(opts.Scheme, v1.EventSource{Component: "kruise-daemon-ephemeralcontainer", Host: opts.NodeName})
+
+	return &Controller{
+		queue:          queue,
+		podLister:      corelisters.NewPodLister(opts.PodInformer.GetIndexer()),
+		runtimeFactory: opts.RuntimeFactory,
+		eventRecorder:  recorder,
+	}, nil
+}
+
+// hasContainersToStop returns true if any running ephemeral container of the pod is listed in the annotation.
+func hasContainersToStop(pod *v1.Pod) bool {
+	if pod.DeletionTimestamp != nil {
+		return false
+	}
+	containerIDs, err := utilephemeralcontainer.GetContainersToStop(pod)
+	if err != nil || containerIDs.Len() == 0 {
+		return false
+	}
+	for i := range pod.Status.EphemeralContainerStatuses {
+		status := &pod.Status.EphemeralContainerStatuses[i]
+		if status.State.Running != nil && containerIDs.Has(status.ContainerID) {
+			return true
+		}
+	}
+	return false
+}
+
+func enqueue(q workqueue.Interface, pod *v1.Pod) {
+	q.Add(pod.Namespace + "/" + pod.Name)
+}
+
+func (c *Controller) Run(stop <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	klog.Info("Starting ephemeralcontainer Controller")
+	for i := 0; i < workers; i++ {
+		go wait.Until(func() {
+			for c.processNextWorkItem() {
+			}
+		}, time.Second, stop)
+	}
+
+	klog.Info("Started ephemeralcontainer Controller successfully")
+	<-stop
+}
+
+// processNextWorkItem will read a single work item off the workqueue and
+// attempt to process it, by calling the syncHandler.
+func (c *Controller) processNextWorkItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	err := c.sync(key.(string))
+	if err == nil {
+		c.queue.Forget(key)
+	} else {
+		c.queue.AddRateLimited(key)
+	}
+
+	return true
+}
+
+func (c *Controller) sync(key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		klog.InfoS("Invalid key", "key", key)
+		return nil
+	}
+
+	pod, err := c.podLister.Pods(namespace).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		klog.ErrorS(err, "Failed to get Pod from lister", "namespace", namespace, "name", name)
+		return err
+	} else if !hasContainersToStop(pod) {
+		return nil
+	}
+
+	containerIDs, _ := utilephemeralcontainer.GetContainersToStop(pod)
+	for i := range pod.Status.EphemeralContainerStatuses {
+		status := &pod.Status.EphemeralContainerStatuses[i]
+		if status.State.Running == nil || !containerIDs.Has(status.ContainerID) {
+			continue
+		}
+
+		containerID := kubeletcontainer.ContainerID{}
+		if err := containerID.ParseString(status.ContainerID); err != nil {
+			klog.ErrorS(err, "Failed to parse containerID", "namespace", namespace, "name", name, "containerID", status.ContainerID)
+			continue
+		}
+		criRuntime := c.runtimeFactory.GetRuntimeServiceByName(containerID.Type)
+		if criRuntime == nil {
+			klog.InfoS("Not found runtime service in daemon", "type", containerID.Type)
+			continue
+		}
+
+		klog.V(3).InfoS("Preparing to stop ephemeral container", "namespace", namespace, "name", name, "containerName", status.Name, "containerID", status.ContainerID)
+		kubeRuntime := kuberuntime.NewGenericRuntime(containerID.Type, criRuntime, c.eventRecorder, &http.Client{})
+		msg := fmt.Sprintf("Stopping ephemeral container %s for exceeding the target active deadline", status.Name)
+		if err := kubeRuntime.KillContainer(pod, containerID, status.Name, msg, nil); err != nil {
+			return fmt.Errorf("failed to stop ephemeral container %s in pod %s/%s: %v", status.Name, namespace, name, err)
+		}
+	}
+	return nil
+}
@@ -47,3 +47,49 @@ func TestChanPool_StopWithoutTasks(t *testing.T) {
 
 	}
 }
+
+func TestPullConcurrencyLimiter(t *testing.T) {
+	limiter := newPullConcurrencyLimiter()
+	stopCh := make(chan struct{})
+
+	if !limiter.acquire(2, stopCh) || !limiter.acquire(2, stopCh) {
+		t.Fatalf("expected to acquire under the limit")
+	}
+	// no limit
+	if !limiter.acquire(0, stopCh) {
+		t.Fatalf("expected to acquire without limit")
+	}
+
+	acquired := make(chan bool)
+	go func() {
+		acquired <- limiter.acquire(3, stopCh)
+	}()
+	select {
+	case <-acquired:
+		t.Fatalf("expected to wait for the limit")
+	case <-time.After(50 * time.Millisecond):
+	}
+	limiter.release()
+	select {
+	case ok := <-acquired:
+		if !ok {
+			t.Fatalf("expected to acquire after release")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected to acquire after release, but still waiting")
+	}
+
+	stopped := make(chan bool)
+	go func() {
+		stopped <- limiter.acquire(1, stopCh)
+	}()
+	close(stopCh)
+	select {
+	case ok := <-stopped:
+		if ok {
+			t.Fatalf("expected not to acquire after stop")
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("expected to return after stop, but still waiting")
+	}
+}
@@ -43,6 +43,7 @@ import (
 	"github.com/openkruise/kruise/pkg/client"
 	kruiseclient "github.com/openkruise/kruise/pkg/client/clientset/versioned"
 	listersbeta1 "github.com/openkruise/kruise/pkg/client/listers/apps/v1beta1"
+	daemonruntime "github.com/openkruise/kruise/pkg/daemon/criruntime"
 	daemonoptions "github.com/openkruise/kruise/pkg/daemon/options"
 	daemonutil "github.com/openkruise/kruise/pkg/daemon/util"
 	utilimagejob "github.com/openkruise/kruise/pkg/util/imagejob"
@@ -55,6 +56,7 @@ type Controller struct {
 	imagePullNodeInformer cache.SharedIndexInformer
 	imagePullNodeLister   listersbeta1.NodeImageLister
 	statusUpdater         *statusUpdater
+	capabilities          *appsv1beta1.NodeCapabilities
 }
 
 // NewController returns the controller for image pulling
@@ -119,6 +121,7 @@ func NewController(opts daemonoptions.Options, secretManager daemonutil.SecretMa
 		imagePullNodeInformer: informer,
 		imagePullNodeLister:   listersbeta1.NewNodeImageLister(informer.GetIndexer()),
 		statusUpdater:         newStatusUpdater(genericClient.KruiseClient.AppsV1beta1().NodeImages()),
+		capabilities:          daemonruntime.GetNodeCapabilities(opts.RuntimeFactory),
 	}, nil
 }
 
@@ -232,6 +235,7 @@ func (c *Controller) sync(key string) (retErr error) {
 
 	newStatus := appsv1beta1.NodeImageStatus{
 		ImageStatuses: make(map[string]appsv1beta1.ImageStatus),
+		Capabilities:  c.capabilities,
 	}
 	for imageName, imageSpec := range nodeImage.Spec.Images {
 		newStatus.Desired += int32(len(imageSpec.Tags))
@@ -2,6 +2,8 @@ package imagepuller
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -355,3 +357,207 @@ func TestRealPullerSyncWithLimitedPool(t *testing.T) {
 
 	r.clean()
 }
+
+type fakeImageListRuntime struct {
+	images []imageruntime.ImageInfo
+}
+
+func (f *fakeImageListRuntime) PullImage(ctx context.Context, imageName, tag string, pullSecrets []v1.Secret, sandboxConfig *appsv1beta1.SandboxConfig) (imageruntime.ImagePullStatusReader, error) {
+	return nil, fmt.Errorf("unexpected pulling image %s:%s", imageName, tag)
+}
+
+func (f *fakeImageListRuntime) ListImages(ctx context.Context) ([]imageruntime.ImageInfo, error) {
+	return f.images, nil
+}
+
+type fakeStatusUpdater struct {
+	status     *appsv1beta1.ImageTagStatus
+	progresses []int32
+}
+
+func (f *fakeStatusUpdater) UpdateStatus(status *appsv1beta1.ImageTagStatus) {
+	f.status = status.DeepCopy()
+	f.progresses = append(f.progresses, status.Progress)
+}
+
+func TestPullWorkerReportDigest(t *testing.T) {
+	digest := "sha256:2f44e1fcdf0a0d8b1a6e4e8f2b5a8d1f2b1d7a0c8f6a7c7e3b9d2e1f0a9b8c7d"
+	runtime := &fakeImageListRuntime{images: []imageruntime.ImageInfo{{
+		ID:          "sha256:image-id",
+		RepoTags:    []string{"docker.io/library/nginx:1.0"},
+		RepoDigests: []string{"docker.io/library/nginx@" + digest},
+		Size:        1024,
+	}}}
+
+	cases := []struct {
+		name           string
+		expectedDigest string
+		expectedPhase  appsv1beta1.ImagePullPhase
+	}{
+		{
+			name:          "no expected digest",
+			expectedPhase: appsv1beta1.ImagePhaseSucceeded,
+		},
+		{
+			name:           "expected digest matched",
+			expectedDigest: digest,
+			expectedPhase:  appsv1beta1.ImagePhaseSucceeded,
+		},
+		{
+			name:           "expected digest mismatched",
+			expectedDigest: "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			expectedPhase:  appsv1beta1.ImagePhaseFailed,
+		},
+	}
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			updater := &fakeStatusUpdater{}
+			w := &pullWorker{
+				name:          "nginx",
+				tagSpec:       appsv1beta1.ImageTagSpec{Tag: "1.0", ImagePullPolicy: appsv1beta1.PullIfNotPresent, ExpectedDigest: cs.expectedDigest},
+				runtime:       runtime,
+				statusUpdater: updater,
+				active:        true,
+				stopCh:        make(chan struct{}),
+			}
+			w.Run()
+
+			status := updater.status
+			if status.Phase != cs.expectedPhase {
+				t.Fatalf("expected phase %s, got %s: %s", cs.expectedPhase, status.Phase, status.Message)
+			}
+			if status.Digest != digest || status.Size != 1024 {
+				t.Fatalf("expected digest %s and size 1024, got %s and %d", digest, status.Digest, status.Size)
+			}
+		})
+	}
+}
+
+func TestPullWorkerImagePlatform(t *testing.T) {
+	runtime := &fakeImageListRuntime{images: []imageruntime.ImageInfo{{
+		ID:       "sha256:image-id",
+		RepoTags: []string{"docker.io/library/nginx:1.0"},
+	}}}
+
+	otherArch := "arm64"
+	if nodeArch == otherArch {
+		otherArch = "amd64"
+	}
+	cases := []struct {
+		name          string
+		platform      *appsv1beta1.ImagePlatform
+		expectedPhase appsv1beta1.ImagePullPhase
+	}{
+		{
+			name:          "no platform",
+			expectedPhase: appsv1beta1.ImagePhaseSucceeded,
+		},
+		{
+			name:          "node platform",
+			platform:      &appsv1beta1.ImagePlatform{OS: nodeOS, Architecture: nodeArch},
+			expectedPhase: appsv1beta1.ImagePhaseSucceeded,
+		},
+		{
+			name:          "other architecture",
+			platform:      &appsv1beta1.ImagePlatform{OS: nodeOS, Architecture: otherArch},
+			expectedPhase: appsv1beta1.ImagePhaseFailed,
+		},
+		{
+			name:          "other os",
+			platform:      &appsv1beta1.ImagePlatform{OS: "plan9", Architecture: nodeArch},
+			expectedPhase: appsv1beta1.ImagePhaseFailed,
+		},
+	}
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			updater := &fakeStatusUpdater{}
+			w := &pullWorker{
+				name:          "nginx",
+				tagSpec:       appsv1beta1.ImageTagSpec{Tag: "1.0", ImagePullPolicy: appsv1beta1.PullIfNotPresent, Platform: cs.platform},
+				runtime:       runtime,
+				statusUpdater: updater,
+				active:        true,
+				stopCh:        make(chan struct{}),
+			}
+			w.Run()
+
+			status := updater.status
+			if status.Phase != cs.expectedPhase {
+				t.Fatalf("expected phase %s, got %s: %s", cs.expectedPhase, status.Phase, status.Message)
+			}
+			if status.Phase == appsv1beta1.ImagePhaseFailed && !strings.Contains(status.Message, "is not supported by the runtime") {
+				t.Fatalf("unexpected message %q", status.Message)
+			}
+		})
+	}
+}
+
+type fakeProgressRuntime struct {
+	fakeImageListRuntime
+	progresses []int
+}
+
+func (f *fakeProgressRuntime) PullImage(ctx context.Context, imageName, tag string, pullSecrets []v1.Secret, sandboxConfig *appsv1beta1.SandboxConfig) (imageruntime.ImagePullStatusReader, error) {
+	ch := make(chan imageruntime.ImagePullStatus, len(f.progresses)+1)
+	for _, progress := range f.progresses {
+		ch <- imageruntime.ImagePullStatus{Process: progress}
+	}
+	ch <- imageruntime.ImagePullStatus{Process: 100, Finish: true}
+	return &fakeProgressReader{ch: ch}, nil
+}
+
+type fakeProgressReader struct {
+	ch chan imageruntime.ImagePullStatus
+}
+
+func (r *fakeProgressReader) C() <-chan imageruntime.ImagePullStatus {
+	return r.ch
+}
+
+func (r *fakeProgressReader) Close() {}
+
+func TestPullWorkerReportProgress(t *testing.T) {
+	defer func(interval time.Duration) {
+		imagePullingProgressUpdateInterval = interval
+	}(imagePullingProgressUpdateInterval)
+
+	cases := []struct {
+		name               string
+		interval           time.Duration
+		expectedProgresses []int32
+	}{
+		{
+			name:               "report every progress",
+			interval:           0,
+			expectedProgresses: []int32{0, 10, 20, 30, 40, 100},
+		},
+		{
+			name:               "report progress throttled",
+			interval:           time.Hour,
+			expectedProgresses: []int32{0, 10, 100},
+		},
+	}
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			imagePullingProgressUpdateInterval = cs.interval
+			updater := &fakeStatusUpdater{}
+			w := &pullWorker{
+				name:    "nginx",
+				tagSpec: appsv1beta1.ImageTagSpec{Tag: "1.0", ImagePullPolicy: appsv1beta1.PullAlways},
+				runtime: &fakeProgressRuntime{
+					fakeImageListRuntime: fakeImageListRuntime{images: []imageruntime.ImageInfo{{ID: "sha256:image-id", RepoTags: []string{"docker.io/library/nginx:1.0"}}}},
+					progresses:           []int{10, 20, 30, 40},
+				},
+				statusUpdater: updater,
+				active:        true,
+				stopCh:        make(chan struct{}),
+			}
+			w.Run()
+
+			if updater.status.Phase != appsv1beta1.ImagePhaseSucceeded {
+				t.Fatalf("expected phase %s, got %s: %s", appsv1beta1.ImagePhaseSucceeded, updater.status.Phase, updater.status.Message)
+			}
+			assert.Equal(t, cs.expectedProgresses, updater.progresses)
+		})
+	}
+}
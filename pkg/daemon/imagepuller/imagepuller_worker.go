@@ -26,6 +26,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
@@ -50,6 +51,12 @@ const (
 
 var workerLimitedPool ImagePullWorkerPool
 
+// imagePullingProgressUpdateInterval is the min interval to report the pulling progress of a tag,
+// so that the NodeImage status will not be updated for every progress read from the runtime.
+var imagePullingProgressUpdateInterval = 5 * time.Second
+
+var pullLimiter = newPullConcurrencyLimiter()
+
 type puller interface {
 	Sync(obj *appsv1beta1.NodeImage, ref *v1.ObjectReference) error
 	GetStatus(imageName string) *appsv1beta1.ImageStatus
@@ -291,6 +298,11 @@ func newPullWorker(name string, tagSpec appsv1beta1.ImageTagSpec, sandboxConfig
 		o.statusUpdater.UpdateStatus(newStatus)
 		klog.V(5).InfoS("pull worker waiting", "image", image)
 		fn := func() {
+			if !pullLimiter.acquire(o.maxConcurrentPulls(), o.stopCh) {
+				klog.V(5).InfoS("pull worker stopped before start", "image", image)
+				return
+			}
+			defer pullLimiter.release()
 			klog.V(5).InfoS("pull worker start", "image", image)
 			o.Run()
 			klog.V(5).InfoS("pull worker end", "image", image)
@@ -343,6 +355,13 @@ func (w *pullWorker) IsActive() bool {
 	return w.active
 }
 
+func (w *pullWorker) maxConcurrentPulls() int {
+	if w.tagSpec.PullPolicy != nil && w.tagSpec.PullPolicy.MaxConcurrentPulls != nil {
+		return int(*w.tagSpec.PullPolicy.MaxConcurrentPulls)
+	}
+	return 0
+}
+
 func (w *pullWorker) Run() {
 	klog.V(3).InfoS("starting worker", "image", w.ImageRef(), "version", w.tagSpec.Version)
 
@@ -359,7 +378,7 @@ func (w *pullWorker) Run() {
 	// which can meet the scenario that some large size images cannot return the result from CRI.PullImage within 60s. For one reason:
 	// For nodeimage controller will mark image:tag task failed (not responded for a long time) if daemon does not report status in 60s.
 	// Ref: https://github.com/openkruise/kruise/issues/1273
-	w.statusUpdater.UpdateStatus(newStatus)
+	w.statusUpdater.UpdateStatus(newStatus.DeepCopy())
 
 	defer func() {
 		cost := time.Since(startTime.Time)
@@ -396,6 +415,10 @@ func (w *pullWorker) Run() {
 	)
 
 	var lastError error
+	// the runtime is not able to pull the image of the platform, no need to try
+	if lastError = checkImagePlatform(w.tagSpec.Platform); lastError != nil {
+		backoffLimit = -1
+	}
 	for i := 0; i <= backoffLimit; i++ {
 		onceTimeout := timeout
 		if deadline != nil {
@@ -415,20 +438,38 @@ func (w *pullWorker) Run() {
 				break
 			}
 
-			klog.ErrorS(lastError, "Pulling image backoff", "name", w.name, "tag", tag, "backoff", i+1)
-			time.Sleep(step)
+			if i == backoffLimit {
+				break
+			}
+			// add jitter to avoid all nodes retrying against the registry at the same time
+			backoff := wait.Jitter(step, 0.5)
+			klog.ErrorS(lastError, "Pulling image backoff", "name", w.name, "tag", tag, "backoff", i+1, "after", backoff)
+			select {
+			case <-time.After(backoff):
+			case <-w.stopCh:
+			}
+			if !w.IsActive() {
+				break
+			}
 			step = minDuration(2*step, maxBackoff)
 			continue
 		}
 
 		if imageInfo, err := w.getImageInfo(pullContext); err == nil {
 			newStatus.ImageID = fmt.Sprintf("%v@%v", w.name, imageInfo.ID)
+			newStatus.Digest = imageInfo.GetRepoDigest(w.name)
+			newStatus.Size = imageInfo.Size
+		}
+		cancel()
+		// the image pulled is not the expected one, no need to retry
+		if expected := w.tagSpec.ExpectedDigest; expected != "" && newStatus.Digest != expected {
+			lastError = fmt.Errorf("digest %q of the image pulled does not match the expected digest %q", newStatus.Digest, expected)
+			break
 		}
 		w.finishPulling(newStatus, appsv1beta1.ImagePhaseSucceeded, "")
 		if w.ref != nil && w.eventRecorder != nil {
 			w.eventRecorder.Eventf(w.ref, v1.EventTypeNormal, PullImageSucceed, "Image %v:%v, ecalpsedTime %v", w.name, w.tagSpec.Tag, time.Since(startTime.Time))
 		}
-		cancel()
 		return
 	}
 	w.finishPulling(newStatus, appsv1beta1.ImagePhaseFailed, lastError.Error())
@@ -516,6 +557,9 @@ func (w *pullWorker) doPullImage(ctx context.Context, newStatus *appsv1beta1.Ima
 
 	progress := 0
 	var progressInfo string
+	// the status is reported by copy, so that the progress changed later is not visible until reported again
+	lastReportedProgress := newStatus.Progress
+	var lastReportedTime time.Time
 	logTicker := time.NewTicker(defaultImagePullingProgressLogInterval)
 	defer logTicker.Stop()
 
@@ -543,7 +587,10 @@ func (w *pullWorker) doPullImage(ctx context.Context, newStatus *appsv1beta1.Ima
 				}
 				return fmt.Errorf("pulling image %s:%s error %v", w.name, tag, progressStatus.Err)
 			}
-			w.statusUpdater.UpdateStatus(newStatus)
+			if newStatus.Progress != lastReportedProgress && time.Since(lastReportedTime) >= imagePullingProgressUpdateInterval {
+				lastReportedProgress, lastReportedTime = newStatus.Progress, time.Now()
+				w.statusUpdater.UpdateStatus(newStatus.DeepCopy())
+			}
 		}
 	}
 }
@@ -48,3 +48,45 @@ func (p *chanPool) Stop() {
 	p.wg.Wait()
 	klog.Info("all worker in image pull worker pool stopped")
 }
+
+// pullConcurrencyLimiter limits the number of images pulled concurrently on the node
+// for the pulling tasks with maxConcurrentPulls specified.
+type pullConcurrencyLimiter struct {
+	sync.Mutex
+	running int
+	// changed is closed and replaced each time a pulling task finishes
+	changed chan struct{}
+}
+
+func newPullConcurrencyLimiter() *pullConcurrencyLimiter {
+	return &pullConcurrencyLimiter{changed: make(chan struct{})}
+}
+
+// acquire blocks until fewer than limit images are being pulled, a non-positive limit means no limit.
+// It returns false if stopCh is closed before that.
+func (l *pullConcurrencyLimiter) acquire(limit int, stopCh <-chan struct{}) bool {
+	for {
+		l.Lock()
+		if limit <= 0 || l.running < limit {
+			l.running++
+			l.Unlock()
+			return true
+		}
+		changed := l.changed
+		l.Unlock()
+
+		select {
+		case <-changed:
+		case <-stopCh:
+			return false
+		}
+	}
+}
+
+func (l *pullConcurrencyLimiter) release() {
+	l.Lock()
+	defer l.Unlock()
+	l.running--
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
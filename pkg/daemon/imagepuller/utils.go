@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	goruntime "runtime"
 	"time"
 
 	"golang.org/x/time/rate"
@@ -125,3 +126,26 @@ func (su *statusUpdater) updateStatus(nodeImage *appsv1beta1.NodeImage, newStatu
 	su.previousTimestamp = time.Now()
 	return false, err
 }
+
+var (
+	nodeOS   = goruntime.GOOS
+	nodeArch = goruntime.GOARCH
+)
+
+// checkImagePlatform returns error if the platform of image can not be honored by the runtime.
+// CRI has no way to select the platform of a multi-arch manifest, which always pulls the platform of the node,
+// so only the platform of the node is supported.
+func checkImagePlatform(platform *appsv1beta1.ImagePlatform) error {
+	if platform == nil {
+		return nil
+	}
+	if platform.OS == nodeOS && platform.Architecture == nodeArch &&
+		(platform.Variant == "" || (nodeArch == "arm64" && platform.Variant == "v8")) {
+		return nil
+	}
+	formatted := fmt.Sprintf("%s/%s", platform.OS, platform.Architecture)
+	if platform.Variant != "" {
+		formatted = fmt.Sprintf("%s/%s", formatted, platform.Variant)
+	}
+	return fmt.Errorf("platform %s is not supported by the runtime, which can only pull images of the node platform %s/%s", formatted, nodeOS, nodeArch)
+}
@@ -19,6 +19,7 @@ package kuberuntime
 
 import (
 	"context"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -41,6 +42,8 @@ type Runtime interface {
 	// * Run the pre-stop lifecycle hooks (if applicable).
 	// * Stop the container.
 	KillContainer(pod *v1.Pod, containerID kubeletcontainer.ContainerID, containerName string, message string, gracePeriodOverride *int64) error
+	// RunInContainer synchronously executes the command in the container, and returns the output.
+	RunInContainer(ctx context.Context, id kubeletcontainer.ContainerID, cmd []string, timeout time.Duration) ([]byte, error)
 }
 
 func NewGenericRuntime(
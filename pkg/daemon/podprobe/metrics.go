@@ -0,0 +1,61 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podprobe
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+// probeResultError is the result label of the probes failed to be executed, e.g., the pod has no IP.
+const probeResultError = "Error"
+
+var (
+	PodProbeResultsMetrics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kruise_daemon_pod_probe_results_total",
+			Help: "Number of the results of PodProbeMarker probes executed by kruise-daemon, by probe and result",
+		}, []string{"probe", "result"},
+	)
+
+	PodProbeDurationMetrics = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kruise_daemon_pod_probe_duration_seconds",
+			Help:    "Latency of PodProbeMarker probes executed by kruise-daemon, by probe",
+			Buckets: []float64{0.005, 0.01, 0.05, 0.1, 0.5, 1, 2, 5, 10, 30},
+		}, []string{"probe"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(PodProbeResultsMetrics)
+	metrics.Registry.MustRegister(PodProbeDurationMetrics)
+}
+
+// recordProbeResult counts the result of a probe and observes its latency.
+func recordProbeResult(probeName string, result appsv1alpha1.ProbeState, err error, duration time.Duration) {
+	resultLabel := string(result)
+	if err != nil {
+		resultLabel = probeResultError
+	}
+	PodProbeResultsMetrics.WithLabelValues(probeName, resultLabel).Inc()
+	PodProbeDurationMetrics.WithLabelValues(probeName).Observe(duration.Seconds())
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podprobe
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+func TestRecordProbeResult(t *testing.T) {
+	probeName := "ppm-metrics#idle"
+	recordProbeResult(probeName, appsv1alpha1.ProbeSucceeded, nil, 10*time.Millisecond)
+	recordProbeResult(probeName, appsv1alpha1.ProbeFailed, nil, time.Second)
+	recordProbeResult(probeName, appsv1alpha1.ProbeFailed, nil, time.Second)
+	recordProbeResult(probeName, appsv1alpha1.ProbeFailed, fmt.Errorf("no IP of pod"), 0)
+
+	expected := map[string]float64{
+		string(appsv1alpha1.ProbeSucceeded): 1,
+		string(appsv1alpha1.ProbeFailed):    2,
+		probeResultError:                    1,
+	}
+	for result, count := range expected {
+		if got := testutil.ToFloat64(PodProbeResultsMetrics.WithLabelValues(probeName, result)); got != count {
+			t.Errorf("expected %v results of %s, got %v", count, result, got)
+		}
+	}
+	if got := testutil.CollectAndCount(PodProbeDurationMetrics, "kruise_daemon_pod_probe_duration_seconds"); got == 0 {
+		t.Errorf("expected probe latency observed")
+	}
+}
@@ -293,10 +293,10 @@ func (c *Controller) processUpdateWorkItem() bool {
 	err := c.syncUpdateNodePodProbeStatus()
 	if err == nil {
 		// No error, tell the queue to stop tracking history
-		c.queue.Forget(key)
+		c.updateQueue.Forget(key)
 	} else {
 		// requeue the item to work on later
-		c.queue.AddRateLimited(key)
+		c.updateQueue.AddRateLimited(key)
 	}
 
 	return true
@@ -17,6 +17,8 @@ limitations under the License.
 package podprobe
 
 import (
+	"context"
+	"fmt"
 	"reflect"
 	"sync"
 	"testing"
@@ -619,3 +621,78 @@ func TestSyncNodePodProbe(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncUpdateNodePodProbeStatusBatched(t *testing.T) {
+	npp := &appsv1alpha1.NodePodProbe{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}
+	for i := 0; i < 100; i++ {
+		npp.Spec.PodProbes = append(npp.Spec.PodProbes, appsv1alpha1.PodProbe{
+			Namespace: "default",
+			Name:      fmt.Sprintf("pod-%d", i),
+			UID:       fmt.Sprintf("pod-%d-uid", i),
+			Probes:    []appsv1alpha1.ContainerProbe{{Name: "ppm-1#healthy", ContainerName: "main"}},
+		})
+	}
+	fakeClient := fake.NewSimpleClientset(npp)
+	informer := newNodePodProbeInformer(fakeClient, "node-1")
+	updateQueue := workqueue.NewNamedRateLimitingQueue(
+		workqueue.NewItemExponentialFailureRateLimiter(500*time.Millisecond, 50*time.Second),
+		"update_node_pod_probe_status",
+	)
+	defer updateQueue.ShutDown()
+	c := &Controller{
+		nodePodProbeInformer: informer,
+		nodePodProbeLister:   listersalpha1.NewNodePodProbeLister(informer.GetIndexer()),
+		workers:              make(map[probeKey]*worker),
+		nodePodProbeClient:   fakeClient.AppsV1alpha1().NodePodProbes(),
+		nodeName:             "node-1",
+		updateQueue:          updateQueue,
+		result:               newResultManager(updateQueue),
+		eventRecorder:        record.NewFakeRecorder(1000),
+	}
+	c.result.batchWindow = 200 * time.Millisecond
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go c.nodePodProbeInformer.Run(stopCh)
+	if !cache.WaitForCacheSync(stopCh, c.nodePodProbeInformer.HasSynced) {
+		t.Fatalf("failed to sync NodePodProbe informer")
+	}
+	fakeClient.ClearActions()
+
+	// all the probes flip within one window
+	for _, podProbe := range npp.Spec.PodProbes {
+		key := probeKey{podNs: podProbe.Namespace, podName: podProbe.Name, podUID: podProbe.UID, containerName: "main", probeName: "ppm-1#healthy"}
+		c.result.set(podProbe.UID+"-main", key, appsv1alpha1.ProbeSucceeded, "")
+		c.result.set(podProbe.UID+"-main", key, appsv1alpha1.ProbeFailed, "failed")
+	}
+	if l := updateQueue.Len(); l != 0 {
+		t.Fatalf("expected no status update before the window ends, got %d", l)
+	}
+	if !c.processUpdateWorkItem() {
+		t.Fatalf("update queue shut down unexpectedly")
+	}
+	if l := updateQueue.Len(); l != 0 {
+		t.Fatalf("expected no more status update after the window, got %d", l)
+	}
+
+	var updates int
+	for _, action := range fakeClient.Actions() {
+		if action.GetVerb() == "update" && action.GetSubresource() == "status" {
+			updates++
+		}
+	}
+	if updates != 1 {
+		t.Fatalf("expected exactly 1 status update, got %d", updates)
+	}
+	updated, err := fakeClient.AppsV1alpha1().NodePodProbes().Get(context.TODO(), "node-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated.Status.PodProbeStatuses) != 100 {
+		t.Fatalf("expected status of 100 pods, got %d", len(updated.Status.PodProbeStatuses))
+	}
+	for _, status := range updated.Status.PodProbeStatuses {
+		if len(status.ProbeStates) != 1 || status.ProbeStates[0].State != appsv1alpha1.ProbeFailed {
+			t.Fatalf("expected failed probe state of pod %s, got %v", status.Name, status.ProbeStates)
+		}
+	}
+}
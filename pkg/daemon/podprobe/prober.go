@@ -25,6 +25,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
@@ -83,8 +84,18 @@ func (pb *prober) runProbe(p *appsv1alpha1.ContainerProbeSpec, probeKey probeKey
 	// TODO: support grpc prober
 	switch {
 	case p.Exec != nil:
-		return pb.exec.Probe(pb.newExecInContainer(containerID, p.Exec.Command, timeout))
+		cmd := pb.newExecInContainer(containerID, p.Exec.Command, timeout)
+		result, output, err := pb.exec.Probe(cmd)
+		// the output of a failed command alone can not tell a non-zero exit from a timeout
+		if err == nil && result == probe.Failure && cmd.exitCode > 0 {
+			output = fmt.Sprintf("exec probe failed with exit code %d: %s", cmd.exitCode, output)
+		}
+		return result, output, err
 	case p.HTTPGet != nil:
+		// kruise-daemon runs in the host network namespace, so dialing an empty host would probe the node itself
+		if p.HTTPGet.Host == "" && probeKey.podIP == "" {
+			return probe.Unknown, "", fmt.Errorf("no IP of pod %s/%s to probe", probeKey.podNs, probeKey.podName)
+		}
 		req, err := newRequestForHTTPGetAction(p.HTTPGet, probeKey.podIP, "probe")
 		if err != nil {
 			return probe.Unknown, "", err
@@ -104,7 +115,10 @@ func (pb *prober) runProbe(p *appsv1alpha1.ContainerProbeSpec, probeKey probeKey
 		if host == "" {
 			host = probeKey.podIP
 		}
-		klog.InfoS("TCP-Probe Host", "host", host, "port", port, "timeout", timeout)
+		if host == "" {
+			return probe.Unknown, "", fmt.Errorf("no IP of pod %s/%s to probe", probeKey.podNs, probeKey.podName)
+		}
+		klog.V(4).InfoS("TCP-Probe", "host", host, "port", port, "timeout", timeout)
 		return pb.tcp.Probe(host, port, timeout)
 	}
 
@@ -113,7 +127,7 @@ func (pb *prober) runProbe(p *appsv1alpha1.ContainerProbeSpec, probeKey probeKey
 }
 
 func newRequestForHTTPGetAction(httpGet *v1.HTTPGetAction, podIP string, userAgentFragment string) (*http.Request, error) {
-	scheme := string(httpGet.Scheme)
+	scheme := strings.ToLower(string(httpGet.Scheme))
 	if scheme == "" {
 		scheme = "http"
 	}
@@ -194,13 +208,16 @@ type execInContainer struct {
 	// error is returned if one occurred.
 	run    func() ([]byte, error)
 	writer io.Writer
+	// exitCode is the exit code of the command after started, if it exited with a non-zero code.
+	exitCode int
 }
 
-func (pb *prober) newExecInContainer(containerID string, cmd []string, timeout time.Duration) exec.Cmd {
+func (pb *prober) newExecInContainer(containerID string, cmd []string, timeout time.Duration) *execInContainer {
 	return &execInContainer{run: func() ([]byte, error) {
 		stdout, stderr, err := pb.runtimeService.ExecSync(context.TODO(), containerID, cmd, timeout)
 		if err != nil {
-			return stderr, err
+			// the command may write the reason of failure to either stdout or stderr
+			return append(stdout, stderr...), err
 		}
 		return stdout, nil
 	}}
@@ -247,6 +264,9 @@ func (eic *execInContainer) Start() error {
 	if eic.writer != nil {
 		eic.writer.Write(data)
 	}
+	if exitErr, ok := err.(exec.ExitError); ok {
+		eic.exitCode = exitErr.ExitStatus()
+	}
 	return err
 }
 
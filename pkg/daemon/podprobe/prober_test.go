@@ -1,6 +1,7 @@
 package podprobe
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
@@ -8,13 +9,17 @@ import (
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	criapi "k8s.io/cri-api/pkg/apis"
 	runtimeapi "k8s.io/cri-api/pkg/apis/runtime/v1"
+	criremote "k8s.io/cri-client/pkg"
 	"k8s.io/kubernetes/pkg/probe"
 	httpprobe "k8s.io/kubernetes/pkg/probe/http"
 	tcpprobe "k8s.io/kubernetes/pkg/probe/tcp"
+	utilexec "k8s.io/utils/exec"
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	"github.com/openkruise/kruise/pkg/util"
@@ -31,6 +36,10 @@ func New() prober {
 func TestRunProbe(t *testing.T) {
 	// Setup a test server that responds to probing correctly
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/headers" && r.Header.Get("X-Probe") != "kruise" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
 		w.WriteHeader(http.StatusOK)
 	}))
 	defer server.Close()
@@ -115,6 +124,66 @@ func TestRunProbe(t *testing.T) {
 			expectedError:  nil,
 		},
 
+		{
+			name: "test tcpProbe check, pod has no IP",
+			p: &appsv1alpha1.ContainerProbeSpec{
+				Probe: corev1.Probe{
+					ProbeHandler: corev1.ProbeHandler{
+						TCPSocket: &corev1.TCPSocketAction{
+							Port: intstr.FromInt(tPort),
+						},
+					},
+				},
+			},
+			probeKey: probeKey{
+				podNs:   "ns",
+				podName: "pod-0",
+			},
+			expectedStatus: probe.Unknown,
+			expectedError:  fmt.Errorf("no IP of pod ns/pod-0 to probe"),
+		},
+
+		{
+			name: "test httpProbe check, pod has no IP",
+			p: &appsv1alpha1.ContainerProbeSpec{
+				Probe: corev1.Probe{
+					ProbeHandler: corev1.ProbeHandler{
+						HTTPGet: &corev1.HTTPGetAction{
+							Path: "/",
+							Port: intstr.FromInt(tPort),
+						},
+					},
+				},
+			},
+			probeKey: probeKey{
+				podNs:   "ns",
+				podName: "pod-0",
+			},
+			expectedStatus: probe.Unknown,
+			expectedError:  fmt.Errorf("no IP of pod ns/pod-0 to probe"),
+		},
+
+		{
+			name: "test httpProbe check with upper case scheme and headers",
+			p: &appsv1alpha1.ContainerProbeSpec{
+				Probe: corev1.Probe{
+					ProbeHandler: corev1.ProbeHandler{
+						HTTPGet: &corev1.HTTPGetAction{
+							Path:        "/headers",
+							Port:        intstr.FromInt(tPort),
+							Scheme:      corev1.URISchemeHTTP,
+							HTTPHeaders: []corev1.HTTPHeader{{Name: "X-Probe", Value: "kruise"}},
+						},
+					},
+				},
+			},
+			probeKey: probeKey{
+				podIP: tHost,
+			},
+			expectedStatus: probe.Success,
+			expectedError:  nil,
+		},
+
 		{
 			name: "invalid probe handler",
 			p: &appsv1alpha1.ContainerProbeSpec{
@@ -472,3 +541,65 @@ func TestNewRequestForHTTPGetAction(t *testing.T) {
 		})
 	}
 }
+
+type fakeExecRuntimeService struct {
+	criapi.RuntimeService
+	stdout, stderr []byte
+	err            error
+}
+
+func (r *fakeExecRuntimeService) ExecSync(_ context.Context, _ string, _ []string, _ time.Duration) ([]byte, []byte, error) {
+	return r.stdout, r.stderr, r.err
+}
+
+func TestProbeExecMessage(t *testing.T) {
+	spec := &appsv1alpha1.ContainerProbeSpec{
+		Probe: corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				Exec: &corev1.ExecAction{Command: []string{"/bin/check"}},
+			},
+		},
+	}
+	cases := []struct {
+		name             string
+		runtimeService   *fakeExecRuntimeService
+		expectProbeState appsv1alpha1.ProbeState
+		expectMsg        string
+	}{
+		{
+			name:             "command succeeded",
+			runtimeService:   &fakeExecRuntimeService{stdout: []byte("idle")},
+			expectProbeState: appsv1alpha1.ProbeSucceeded,
+			expectMsg:        "idle",
+		},
+		{
+			name: "command exited with non-zero code",
+			runtimeService: &fakeExecRuntimeService{
+				stdout: []byte("players: 3\n"),
+				stderr: []byte("not idle"),
+				err:    utilexec.CodeExitError{Err: fmt.Errorf("command '/bin/check' exited with 2: not idle"), Code: 2},
+			},
+			expectProbeState: appsv1alpha1.ProbeFailed,
+			expectMsg:        "exec probe failed with exit code 2: players: 3\nnot idle",
+		},
+		{
+			name:             "command timed out",
+			runtimeService:   &fakeExecRuntimeService{err: fmt.Errorf("%w: \"/bin/check\" timed out after 1s", criremote.ErrCommandTimedOut)},
+			expectProbeState: appsv1alpha1.ProbeFailed,
+			expectMsg:        "command timed out: \"/bin/check\" timed out after 1s",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newProber(tc.runtimeService)
+			state, msg, err := p.probe(spec, probeKey{}, &runtimeapi.ContainerStatus{}, "container-1")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if state != tc.expectProbeState || msg != tc.expectMsg {
+				t.Errorf("expect %v %q, but got %v %q", tc.expectProbeState, tc.expectMsg, state, msg)
+			}
+		})
+	}
+}
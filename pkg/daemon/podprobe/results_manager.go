@@ -17,7 +17,9 @@ limitations under the License.
 package podprobe
 
 import (
+	"flag"
 	"sync"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/workqueue"
@@ -27,6 +29,16 @@ import (
 
 const maxSyncProbeTime = 600
 
+// statusBatchWindow is how long the changes of probe results are coalesced before written to NodePodProbe status
+// in a single update, so that the probes of many pods flipping together don't write the status on every change.
+// 0 writes the status as soon as a result changes.
+var statusBatchWindow = 3 * time.Second
+
+func init() {
+	flag.DurationVar(&statusBatchWindow, "pod-probe-status-batch-window", statusBatchWindow,
+		"The window to coalesce the changes of pod probe results into a single update of NodePodProbe status, 0 to update on every change.")
+}
+
 // Update is an enum of the types of updates sent over the Updates channel.
 type Update struct {
 	ContainerID   string
@@ -41,13 +53,17 @@ type resultManager struct {
 	// map of container ID -> probe Result
 	cache *sync.Map
 	queue workqueue.RateLimitingInterface
+	// batchWindow is how long the status update is delayed after a result changes, the other results changed
+	// meanwhile are written in the same update
+	batchWindow time.Duration
 }
 
 // newResultManager creates and returns an empty results resultManager.
 func newResultManager(queue workqueue.RateLimitingInterface) *resultManager {
 	return &resultManager{
-		cache: &sync.Map{},
-		queue: queue,
+		cache:       &sync.Map{},
+		queue:       queue,
+		batchWindow: statusBatchWindow,
 	}
 }
 
@@ -66,8 +82,18 @@ func (m *resultManager) set(id string, key probeKey, result appsv1alpha1.ProbeSt
 	prev, exists := m.cache.Load(id)
 	if !exists || prev.(Update).State != result || prev.(Update).Msg != msg || currentTime.Sub(prev.(Update).LastProbeTime.Time).Seconds() >= maxSyncProbeTime {
 		m.cache.Store(id, Update{id, key, result, msg, currentTime})
+		m.enqueueUpdate()
+	}
+}
+
+// enqueueUpdate enqueues the status update after the batch window. The delaying queue keeps the earliest time
+// of the same key waiting, so the results changed within the window are coalesced into one update.
+func (m *resultManager) enqueueUpdate() {
+	if m.batchWindow <= 0 {
 		m.queue.Add("updateStatus")
+		return
 	}
+	m.queue.AddAfter("updateStatus", m.batchWindow)
 }
 
 func (m *resultManager) remove(id string) {
@@ -295,6 +295,7 @@ func TestResultManagerSet(t *testing.T) {
 				"update_node_pod_probe_status",
 			)
 			r := newResultManager(updateQueue)
+			r.batchWindow = 0
 			prev := cs.getPrev()
 			if prev != nil {
 				r.cache.Store(prev.ContainerID, Update{prev.ContainerID, prev.Key, prev.State, prev.Msg, prev.LastProbeTime})
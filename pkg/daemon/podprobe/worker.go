@@ -153,7 +153,9 @@ func (w *worker) doProbe() (keepGoing bool) {
 
 	// the full container environment here, OR we must make a call to the CRI in order to get those environment
 	// values from the running container.
+	startTime := time.Now()
 	result, msg, err := w.probeController.prober.probe(w.spec, w.key, container, w.containerID)
+	recordProbeResult(w.key.probeName, result, err, time.Since(startTime))
 	if err != nil {
 		klog.ErrorS(err, "Pod do container probe spec failed",
 			"namespace", w.key.podNs, "podName", w.key.podName, "containerName", w.key.containerName, "probeName", w.key.probeName, "spec", util.DumpJSON(w.spec))
@@ -156,6 +156,14 @@ const (
 	// Enabling this means a default will be assigned even to embeddedPodSpecs
 	// (e.g. in a CloneSet,Advanced DaemonSet), which is the historical default.
 	DefaultHostNetworkHostPortsInPodTemplates featuregate.Feature = "DefaultHostNetworkHostPortsInPodTemplates"
+
+	// DaemonSetPatches enables Advanced DaemonSet to apply spec.patches to the pod template of matching nodes.
+	// If disabled, spec.patches is ignored by controller and forbidden by webhook.
+	DaemonSetPatches featuregate.Feature = "DaemonSetPatches"
+
+	// DaemonSetPatchImageDigestPinning requires the container images set by Advanced DaemonSet spec.patches
+	// to be pinned by sha256 digest, so that patches can not reintroduce mutable tags.
+	DaemonSetPatchImageDigestPinning featuregate.Feature = "DaemonSetPatchImageDigestPinning"
 )
 
 var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
@@ -197,6 +205,8 @@ var defaultFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	EnableSortSidecarContainerByName:          {Default: false, PreRelease: featuregate.Alpha},
 	InPlacePodVerticalScaling:                 {Default: false, PreRelease: featuregate.Alpha},
 	DefaultHostNetworkHostPortsInPodTemplates: {Default: false, PreRelease: featuregate.Alpha},
+	DaemonSetPatches:                          {Default: true, PreRelease: featuregate.Beta},
+	DaemonSetPatchImageDigestPinning:          {Default: false, PreRelease: featuregate.Alpha},
 }
 
 func init() {
@@ -75,6 +75,9 @@ func (p *CustomWorkloadWhiteList) IsDefaultSupport(apiVersion, kind string) bool
 	if (gv.Group == v1alpha1.GroupVersion.Group || gv.Group == appsv1.GroupName) && kind == "StatefulSet" {
 		return true
 	}
+	if gv.Group == v1alpha1.GroupVersion.Group && kind == "CloneSet" {
+		return true
+	}
 	return false
 }
 
@@ -0,0 +1,63 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ephemeralcontainer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+// GetContainersToStop returns the ephemeral container IDs in pod annotation that should be stopped by kruise-daemon.
+func GetContainersToStop(pod *v1.Pod) (sets.String, error) {
+	containerIDs := sets.NewString()
+	str := pod.Annotations[appsv1alpha1.EphemeralContainersToStopAnnotation]
+	if str == "" {
+		return containerIDs, nil
+	}
+	var ids []string
+	if err := json.Unmarshal([]byte(str), &ids); err != nil {
+		return containerIDs, fmt.Errorf("failed to unmarshal %s annotation: %v", appsv1alpha1.EphemeralContainersToStopAnnotation, err)
+	}
+	return containerIDs.Insert(ids...), nil
+}
+
+// PatchPodContainersToStop adds the ephemeral container IDs into pod annotation, so that kruise-daemon will stop them.
+// It returns false if all the IDs already exist in the annotation.
+func PatchPodContainersToStop(c client.Client, pod *v1.Pod, containerIDs []string) (bool, error) {
+	existing, _ := GetContainersToStop(pod)
+	if existing.HasAll(containerIDs...) {
+		return false, nil
+	}
+
+	value, _ := json.Marshal(existing.Insert(containerIDs...).List())
+	body, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				appsv1alpha1.EphemeralContainersToStopAnnotation: string(value),
+			},
+		},
+	})
+	return true, c.Patch(context.TODO(), pod, client.RawPatch(types.MergePatchType, body))
+}
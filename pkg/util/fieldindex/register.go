@@ -18,17 +18,20 @@ package fieldindex
 
 import (
 	"context"
+	"encoding/json"
 	"sync"
 
 	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
 	utildiscovery "github.com/openkruise/kruise/pkg/util/discovery"
+	wsutil "github.com/openkruise/kruise/pkg/util/workloadspread"
 )
 
 const (
@@ -38,6 +41,8 @@ const (
 	IndexNameForIsActive             = "isActive"
 	IndexNameForSidecarSetNamespace  = "namespace"
 	IndexValueSidecarSetClusterScope = "clusterScope"
+	IndexNameForPodWorkloadSpread    = "workloadSpreadSubset"
+	IndexNameForDaemonSetPatchLabel  = "daemonSetPatchLabelKey"
 	LabelMetadataName                = v1.LabelMetadataName
 )
 
@@ -77,10 +82,21 @@ func RegisterFieldIndexes(c cache.Cache) error {
 			}
 		}
 
+		// ContainerRecreateRequest ownerReference
+		if utildiscovery.DiscoverObject(&appsv1alpha1.ContainerRecreateRequestSet{}) {
+			if err = c.IndexField(context.TODO(), &appsv1alpha1.ContainerRecreateRequest{}, IndexNameForOwnerRefUID, ownerIndexFunc); err != nil {
+				return
+			}
+		}
+
 		// pod name
 		if err = indexPodNodeName(c); err != nil {
 			return
 		}
+		// pod matched workloadSpread subset
+		if err = indexPodWorkloadSpread(c); err != nil {
+			return
+		}
 		// job owner
 		if err = indexJob(c); err != nil {
 			return
@@ -127,6 +143,12 @@ func RegisterFieldIndexes(c cache.Cache) error {
 				return
 			}
 		}
+		// label keys referenced by daemonset patches
+		if utildiscovery.DiscoverObject(&appsv1beta1.DaemonSet{}) {
+			if err = indexDaemonSetPatchLabelKeys(c); err != nil {
+				return
+			}
+		}
 	})
 	return err
 }
@@ -144,6 +166,33 @@ func indexPodNodeName(c cache.Cache) error {
 	})
 }
 
+// IndexPodWorkloadSpread indexes pod by the WorkloadSpread subset it was injected into,
+// the value is formatted by WorkloadSpreadSubsetIndexValue.
+func IndexPodWorkloadSpread(rawObj client.Object) []string {
+	pod, ok := rawObj.(*v1.Pod)
+	if !ok {
+		return nil
+	}
+	str, ok := pod.Annotations[wsutil.MatchedWorkloadSpreadSubsetAnnotations]
+	if !ok || str == "" {
+		return nil
+	}
+	injectWS := &wsutil.InjectWorkloadSpread{}
+	if err := json.Unmarshal([]byte(str), injectWS); err != nil || injectWS.Name == "" {
+		return nil
+	}
+	return []string{WorkloadSpreadSubsetIndexValue(injectWS.Name, injectWS.Subset)}
+}
+
+// WorkloadSpreadSubsetIndexValue returns the value of IndexNameForPodWorkloadSpread for a subset.
+func WorkloadSpreadSubsetIndexValue(wsName, subsetName string) string {
+	return wsName + "/" + subsetName
+}
+
+func indexPodWorkloadSpread(c cache.Cache) error {
+	return c.IndexField(context.TODO(), &v1.Pod{}, IndexNameForPodWorkloadSpread, IndexPodWorkloadSpread)
+}
+
 func indexJob(c cache.Cache) error {
 	return c.IndexField(context.TODO(), &batchv1.Job{}, IndexNameForController, func(rawObj client.Object) []string {
 		// grab the job object, extract the owner...
@@ -302,3 +351,29 @@ func indexSidecarSetV1Beta1(c cache.Cache) error {
 		return IndexSidecarSetV1Beta1(rawObj)
 	})
 }
+
+// IndexDaemonSetPatchLabelKeys indexes DaemonSet by the label keys referenced by the selectors of its patches,
+// so that the DaemonSets whose patches may match a node differently after its labels changed can be found quickly.
+func IndexDaemonSetPatchLabelKeys(rawObj client.Object) []string {
+	ds, ok := rawObj.(*appsv1beta1.DaemonSet)
+	if !ok {
+		return nil
+	}
+	keys := sets.New[string]()
+	for _, patch := range ds.Spec.Patches {
+		if patch.Selector == nil {
+			continue
+		}
+		for key := range patch.Selector.MatchLabels {
+			keys.Insert(key)
+		}
+		for _, requirement := range patch.Selector.MatchExpressions {
+			keys.Insert(requirement.Key)
+		}
+	}
+	return sets.List(keys)
+}
+
+func indexDaemonSetPatchLabelKeys(c cache.Cache) error {
+	return c.IndexField(context.TODO(), &appsv1beta1.DaemonSet{}, IndexNameForDaemonSetPatchLabel, IndexDaemonSetPatchLabelKeys)
+}
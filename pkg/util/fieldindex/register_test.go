@@ -130,3 +130,26 @@ func TestIndexImagePullJob(t *testing.T) {
 	}
 	assert.Equal(t, []string{"false"}, IndexImagePullJob(deletedJob), "Expected deleted job to return 'false'")
 }
+
+func TestIndexDaemonSetPatchLabelKeys(t *testing.T) {
+	// Case 1: DaemonSet without patches
+	assert.Empty(t, IndexDaemonSetPatchLabelKeys(&appsv1beta1.DaemonSet{}), "Expected no keys without patches")
+
+	// Case 2: keys from matchLabels and matchExpressions of all patches, deduplicated and sorted
+	ds := &appsv1beta1.DaemonSet{
+		Spec: appsv1beta1.DaemonSetSpec{
+			Patches: []appsv1beta1.DaemonSetPatch{
+				{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a", "disk": "slow"}}},
+				{Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "zone", Operator: metav1.LabelSelectorOpIn, Values: []string{"b"}},
+					{Key: "gpu", Operator: metav1.LabelSelectorOpExists},
+				}}},
+				{Selector: nil},
+			},
+		},
+	}
+	assert.Equal(t, []string{"disk", "gpu", "zone"}, IndexDaemonSetPatchLabelKeys(ds), "Expected keys referenced by patches")
+
+	// Case 3: not a DaemonSet
+	assert.Nil(t, IndexDaemonSetPatchLabelKeys(&appsv1beta1.SidecarSet{}), "Expected nil for other objects")
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2026 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// RevisionCountMetrics observes the number of ControllerRevisions kept by a workload after each history cleanup.
+	RevisionCountMetrics = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "workload_controller_revisions",
+			Help:    "Number of ControllerRevisions kept by a workload after its history cleanup",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+		}, []string{"kind"},
+	)
+
+	// RevisionCompactionMetrics counts the ControllerRevisions deleted by the history cleanup of workloads.
+	RevisionCompactionMetrics = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "workload_controller_revisions_compacted_total",
+			Help: "Number of ControllerRevisions deleted by the history cleanup of workloads",
+		}, []string{"kind"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(RevisionCountMetrics, RevisionCompactionMetrics)
+}
+
+// RecordRevisionCleanup updates the revision metrics of the given kind of workload after its history cleanup,
+// which kept and deleted the given numbers of revisions.
+func RecordRevisionCleanup(kind string, kept, deleted int) {
+	RevisionCountMetrics.WithLabelValues(kind).Observe(float64(kept))
+	if deleted > 0 {
+		RevisionCompactionMetrics.WithLabelValues(kind).Add(float64(deleted))
+	}
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2026 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRecordRevisionCleanup(t *testing.T) {
+	defer RevisionCountMetrics.Reset()
+	defer RevisionCompactionMetrics.Reset()
+
+	RecordRevisionCleanup("CloneSet", 7, 3)
+	RecordRevisionCleanup("CloneSet", 7, 0)
+	RecordRevisionCleanup("DaemonSet", 2, 0)
+	if got := testutil.CollectAndCount(RevisionCountMetrics); got != 2 {
+		t.Fatalf("expected revision count observed for 2 kinds, got %d", got)
+	}
+	if got := testutil.ToFloat64(RevisionCompactionMetrics.WithLabelValues("CloneSet")); got != 3 {
+		t.Fatalf("expected compacted revisions 3, got %v", got)
+	}
+	if got := testutil.CollectAndCount(RevisionCompactionMetrics); got != 1 {
+		t.Fatalf("expected compacted revisions counted for 1 kind, got %d", got)
+	}
+}
@@ -44,6 +44,9 @@ import (
 var (
 	cacheLock        sync.Mutex
 	cachedNodeImages = make(map[string][]string)
+
+	// SupportedNodeFieldKeys are the node fields supported in spec.selector.matchFields of ImagePullJob.
+	SupportedNodeFieldKeys = sets.NewString("metadata.name", "status.nodeInfo.architecture", "status.nodeInfo.operatingSystem")
 )
 
 func PopCachedNodeImagesForJob(job *appsv1beta1.ImagePullJob) []string {
@@ -73,6 +76,14 @@ func GetNodeImagesForJob(reader client.Reader, job *appsv1beta1.ImagePullJob) (n
 		}
 	}()
 
+	nodeImages, err = getNodeImagesForJob(reader, job)
+	if err != nil || !HasNodeFilter(job) {
+		return nodeImages, err
+	}
+	return filterNodeImagesByNode(reader, job, nodeImages)
+}
+
+func getNodeImagesForJob(reader client.Reader, job *appsv1beta1.ImagePullJob) (nodeImages []*appsv1beta1.NodeImage, err error) {
 	if job.Spec.PodSelector != nil {
 		selector, err := util.ValidatedLabelSelectorAsSelector(&job.Spec.PodSelector.LabelSelector)
 		if err != nil {
@@ -155,6 +166,66 @@ func GetNodeImagesForJob(reader client.Reader, job *appsv1beta1.ImagePullJob) (n
 	return convertNodeImages(nodeImageList), err
 }
 
+// HasNodeFilter returns whether the nodes of the job are filtered by the Node objects,
+// which are spec.selector.matchFields and spec.skipUnschedulableNodes.
+func HasNodeFilter(job *appsv1beta1.ImagePullJob) bool {
+	return job.Spec.SkipUnschedulableNodes || (job.Spec.Selector != nil && len(job.Spec.Selector.MatchFields) > 0)
+}
+
+func filterNodeImagesByNode(reader client.Reader, job *appsv1beta1.ImagePullJob, nodeImages []*appsv1beta1.NodeImage) ([]*appsv1beta1.NodeImage, error) {
+	filtered := make([]*appsv1beta1.NodeImage, 0, len(nodeImages))
+	for _, nodeImage := range nodeImages {
+		node := &v1.Node{}
+		if err := reader.Get(context.TODO(), types.NamespacedName{Name: nodeImage.Name}, node); err != nil {
+			if errors.IsNotFound(err) {
+				klog.V(4).InfoS("Get NodeImages for ImagePullJob, Node not found", "namespace", job.Namespace, "name", job.Name, "node", nodeImage.Name)
+				continue
+			}
+			return nil, err
+		}
+		if job.Spec.SkipUnschedulableNodes && node.Spec.Unschedulable {
+			continue
+		}
+		if job.Spec.Selector != nil && !MatchNodeFields(job.Spec.Selector.MatchFields, node) {
+			continue
+		}
+		filtered = append(filtered, nodeImage)
+	}
+	return filtered, nil
+}
+
+// MatchNodeFields returns whether the node matches all the requirements over node fields.
+func MatchNodeFields(requirements []v1.NodeSelectorRequirement, node *v1.Node) bool {
+	for _, req := range requirements {
+		value, ok := getNodeField(node, req.Key)
+		switch req.Operator {
+		case v1.NodeSelectorOpIn:
+			if !ok || !slice.ContainsString(req.Values, value, nil) {
+				return false
+			}
+		case v1.NodeSelectorOpNotIn:
+			if ok && slice.ContainsString(req.Values, value, nil) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func getNodeField(node *v1.Node, key string) (string, bool) {
+	switch key {
+	case "metadata.name":
+		return node.Name, true
+	case "status.nodeInfo.architecture":
+		return node.Status.NodeInfo.Architecture, true
+	case "status.nodeInfo.operatingSystem":
+		return node.Status.NodeInfo.OperatingSystem, true
+	}
+	return "", false
+}
+
 func convertNodeImages(nodeImageList *appsv1beta1.NodeImageList) []*appsv1beta1.NodeImage {
 	nodeImages := make([]*appsv1beta1.NodeImage, 0, len(nodeImageList.Items))
 	for i := range nodeImageList.Items {
@@ -213,6 +284,10 @@ func GetActiveJobsForNodeImage(reader client.Reader, nodeImage, oldNodeImage *ap
 						oldMatched = true
 					}
 				}
+			} else if len(job.Spec.Selector.MatchFields) > 0 {
+				// node fields are not evaluated here, the job filters its nodes when reconciling
+				matched = true
+				oldMatched = true
 			}
 		}
 		if matched {
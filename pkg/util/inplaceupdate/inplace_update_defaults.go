@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"hash/fnv"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -310,39 +311,29 @@ func defaultCalculateInPlaceUpdateSpec(oldRevision, newRevision *apps.Controller
 	var metadataPatches []jsonpatch.Operation
 	for _, op := range patches {
 		op.Path = strings.Replace(op.Path, "/spec/template", "", 1)
-
-		if !strings.HasPrefix(op.Path, "/spec/") {
-			if strings.HasPrefix(op.Path, "/metadata/") {
-				metadataPatches = append(metadataPatches, op)
-				continue
-			}
+		if !isInPlaceUpdatablePatch(op, oldTemp) {
 			return nil
 		}
 
-		if op.Operation != "replace" {
-			return nil
+		if strings.HasPrefix(op.Path, "/metadata/") {
+			metadataPatches = append(metadataPatches, op)
+			continue
 		}
+
 		if containerImagePatchRexp.MatchString(op.Path) {
 			// for example: /spec/containers/0/image
 			words := strings.Split(op.Path, "/")
 			idx, _ := strconv.Atoi(words[3])
-			if len(oldTemp.Spec.Containers) <= idx {
-				return nil
-			}
 			updateSpec.ContainerImages[oldTemp.Spec.Containers[idx].Name] = op.Value.(string)
 			continue
 		}
 
-		if utilfeature.DefaultFeatureGate.Enabled(features.InPlaceWorkloadVerticalScaling) &&
-			containerResourcesPatchRexp.MatchString(op.Path) {
-			err = verticalUpdateImpl.UpdateInplaceUpdateMetadata(&op, oldTemp, updateSpec)
-			if err != nil {
-				klog.InfoS("UpdateInplaceUpdateMetadata error", "err", err)
-				return nil
-			}
-			continue
+		// container resources
+		err = verticalUpdateImpl.UpdateInplaceUpdateMetadata(&op, oldTemp, updateSpec)
+		if err != nil {
+			klog.InfoS("UpdateInplaceUpdateMetadata error", "err", err)
+			return nil
 		}
-		return nil
 	}
 	if utilfeature.DefaultFeatureGate.Enabled(features.InPlaceWorkloadVerticalScaling) &&
 		len(updateSpec.ContainerResources) != 0 {
@@ -411,6 +402,62 @@ func defaultCalculateInPlaceUpdateSpec(oldRevision, newRevision *apps.Controller
 	return updateSpec
 }
 
+// isInPlaceUpdatablePatch indicates whether the patch operation of pod template can be updated in-place,
+// the path of op has been trimmed of the "/spec/template" prefix.
+func isInPlaceUpdatablePatch(op jsonpatch.Operation, oldTemp *v1.PodTemplateSpec) bool {
+	if !strings.HasPrefix(op.Path, "/spec/") {
+		return strings.HasPrefix(op.Path, "/metadata/")
+	}
+	if op.Operation != "replace" {
+		return false
+	}
+	if containerImagePatchRexp.MatchString(op.Path) {
+		words := strings.Split(op.Path, "/")
+		idx, _ := strconv.Atoi(words[3])
+		return idx < len(oldTemp.Spec.Containers)
+	}
+	return utilfeature.DefaultFeatureGate.Enabled(features.InPlaceWorkloadVerticalScaling) &&
+		containerResourcesPatchRexp.MatchString(op.Path)
+}
+
+// GetNotInPlaceUpdatablePaths returns the sorted paths of pod template differences between the two revisions
+// which can not be updated in-place, at most limit paths are returned if limit is positive.
+// The paths are relative to the pod template, e.g., /spec/containers/0/env, except /volumeClaimTemplates
+// which indicates the changed volumeClaimTemplates of the workload.
+func GetNotInPlaceUpdatablePaths(oldRevision, newRevision *apps.ControllerRevision, opts *UpdateOptions, limit int) []string {
+	if oldRevision == nil || newRevision == nil {
+		return nil
+	}
+	opts = SetOptionsDefaults(opts)
+	patches, err := jsonpatch.CreatePatch(oldRevision.Data.Raw, newRevision.Data.Raw)
+	if err != nil {
+		return nil
+	}
+	oldTemp, err := GetTemplateFromRevision(oldRevision)
+	if err != nil {
+		return nil
+	}
+
+	var paths []string
+	if utilfeature.DefaultFeatureGate.Enabled(features.RecreatePodWhenChangeVCTInCloneSetGate) && !opts.IgnoreVolumeClaimTemplatesHashDiff {
+		if !volumeclaimtemplate.CanVCTemplateInplaceUpdate(oldRevision, newRevision) {
+			paths = append(paths, "/volumeClaimTemplates")
+		}
+	}
+	for _, op := range patches {
+		op.Path = strings.Replace(op.Path, "/spec/template", "", 1)
+		if !isInPlaceUpdatablePatch(op, oldTemp) {
+			paths = append(paths, op.Path)
+		}
+	}
+	// the order of patches generated is not stable
+	sort.Strings(paths)
+	if limit > 0 && len(paths) > limit {
+		paths = paths[:limit]
+	}
+	return paths
+}
+
 // DefaultCheckInPlaceUpdateCompleted checks whether imageID in pod status has been changed since in-place update.
 // If the imageID in containerStatuses has not been changed, we assume that kubelet has not updated
 // containers in Pod.
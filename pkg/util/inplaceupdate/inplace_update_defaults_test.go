@@ -2070,3 +2070,123 @@ func TestDefaultCheckPodNeedsBeUnready(t *testing.T) {
 		})
 	}
 }
+
+func TestGetNotInPlaceUpdatablePaths(t *testing.T) {
+	baseTemplate := v1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:      map[string]string{"app": "nginx"},
+			Annotations: map[string]string{"foo": "bar"},
+		},
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "init", Image: "busybox:1.0"}},
+			Containers: []v1.Container{{
+				Name:  "nginx",
+				Image: "nginx:1.0",
+				Env:   []v1.EnvVar{{Name: "A", Value: "a"}},
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
+					Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("2")},
+				},
+			}},
+		},
+	}
+	newRevision := func(name string, modify func(template *v1.PodTemplateSpec)) *apps.ControllerRevision {
+		template := baseTemplate.DeepCopy()
+		if modify != nil {
+			modify(template)
+		}
+		raw, _ := json.Marshal(map[string]interface{}{
+			"spec": map[string]interface{}{"template": template, "$patch": "replace"},
+		})
+		return &apps.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Data:       runtime.RawExtension{Raw: raw},
+		}
+	}
+	oldRevision := newRevision("old-revision", nil)
+
+	tests := []struct {
+		name          string
+		modify        func(template *v1.PodTemplateSpec)
+		vpaEnabled    bool
+		expectedPaths []string
+	}{
+		{
+			name:   "image only",
+			modify: func(template *v1.PodTemplateSpec) { template.Spec.Containers[0].Image = "nginx:2.0" },
+		},
+		{
+			name:   "annotation added",
+			modify: func(template *v1.PodTemplateSpec) { template.Annotations["new"] = "value" },
+		},
+		{
+			name:   "label changed",
+			modify: func(template *v1.PodTemplateSpec) { template.Labels["app"] = "web" },
+		},
+		{
+			name: "image and env changed",
+			modify: func(template *v1.PodTemplateSpec) {
+				template.Spec.Containers[0].Image = "nginx:2.0"
+				template.Spec.Containers[0].Env[0].Value = "b"
+			},
+			expectedPaths: []string{"/spec/containers/0/env/0/value"},
+		},
+		{
+			name:          "init container image changed",
+			modify:        func(template *v1.PodTemplateSpec) { template.Spec.InitContainers[0].Image = "busybox:2.0" },
+			expectedPaths: []string{"/spec/initContainers/0/image"},
+		},
+		{
+			name: "container added",
+			modify: func(template *v1.PodTemplateSpec) {
+				template.Spec.Containers = append(template.Spec.Containers, v1.Container{Name: "sidecar", Image: "sidecar:1.0"})
+			},
+			expectedPaths: []string{"/spec/containers/1"},
+		},
+		{
+			name:          "node selector added",
+			modify:        func(template *v1.PodTemplateSpec) { template.Spec.NodeSelector = map[string]string{"disk": "ssd"} },
+			expectedPaths: []string{"/spec/nodeSelector"},
+		},
+		{
+			name: "resources changed without vertical scaling",
+			modify: func(template *v1.PodTemplateSpec) {
+				template.Spec.Containers[0].Resources.Limits[v1.ResourceCPU] = resource.MustParse("4")
+			},
+			expectedPaths: []string{"/spec/containers/0/resources/limits/cpu"},
+		},
+		{
+			name: "resources changed with vertical scaling",
+			modify: func(template *v1.PodTemplateSpec) {
+				template.Spec.Containers[0].Resources.Limits[v1.ResourceCPU] = resource.MustParse("4")
+			},
+			vpaEnabled: true,
+		},
+		{
+			name: "only the first three paths",
+			modify: func(template *v1.PodTemplateSpec) {
+				template.Spec.Containers[0].Image = "nginx:2.0"
+				template.Spec.Containers[0].Env[0].Value = "b"
+				template.Spec.InitContainers[0].Image = "busybox:2.0"
+				template.Spec.NodeSelector = map[string]string{"disk": "ssd"}
+				template.Spec.HostNetwork = true
+			},
+			expectedPaths: []string{"/spec/containers/0/env/0/value", "/spec/hostNetwork", "/spec/initContainers/0/image"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.InPlaceWorkloadVerticalScaling, tt.vpaEnabled)()
+			updateRevision := newRevision("new-revision", tt.modify)
+			paths := GetNotInPlaceUpdatablePaths(oldRevision, updateRevision, nil, 3)
+			if !reflect.DeepEqual(paths, tt.expectedPaths) {
+				t.Fatalf("expected paths %v, got %v", tt.expectedPaths, paths)
+			}
+			// the paths are consistent with the decision of in-place update
+			if canInPlace := defaultCalculateInPlaceUpdateSpec(oldRevision, updateRevision, nil) != nil; canInPlace != (len(paths) == 0) {
+				t.Fatalf("expected in-place update %v, got paths %v", canInPlace, paths)
+			}
+		})
+	}
+}
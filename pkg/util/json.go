@@ -17,6 +17,7 @@ limitations under the License.
 package util
 
 import (
+	"bytes"
 	"encoding/json"
 	"reflect"
 )
@@ -48,3 +49,24 @@ func IsJSONObjectEqual(o1, o2 interface{}) bool {
 
 	return reflect.DeepEqual(om1, om2)
 }
+
+// CanonicalJSON returns the canonical JSON encoding of the object, which is compact with the keys of every object
+// sorted, so that the same content always produces the same bytes no matter it is a struct, a map or a raw JSON
+// document in []byte. Numbers are kept as they are written instead of being converted to float64.
+func CanonicalJSON(o interface{}) ([]byte, error) {
+	raw, ok := o.([]byte)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(o); err != nil {
+			return nil, err
+		}
+	}
+
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package util
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 	"time"
@@ -100,3 +101,42 @@ func TestIsJSONEqual(t *testing.T) {
 		t.Fatalf("expect t1 not json equal to t3")
 	}
 }
+
+func TestCanonicalJSON(t *testing.T) {
+	object := &v1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"b": "2", "a": "1"}},
+		Spec: v1.PodSpec{
+			Containers:                    []v1.Container{{Name: "main", Image: "main:v1"}},
+			TerminationGracePeriodSeconds: func() *int64 { i := int64(9007199254740993); return &i }(),
+		},
+	}
+	expected := `{"metadata":{"creationTimestamp":null,"labels":{"a":"1","b":"2"}},"spec":{"containers":[{"image":"main:v1","name":"main","resources":{}}],"terminationGracePeriodSeconds":9007199254740993}}`
+
+	inputs := map[string]interface{}{
+		"struct": object,
+		"map": map[string]interface{}{
+			"spec": map[string]interface{}{
+				"terminationGracePeriodSeconds": json.Number("9007199254740993"),
+				"containers":                    []interface{}{map[string]interface{}{"resources": map[string]interface{}{}, "name": "main", "image": "main:v1"}},
+			},
+			"metadata": map[string]interface{}{"labels": map[string]interface{}{"b": "2", "a": "1"}, "creationTimestamp": nil},
+		},
+		"raw": []byte(`{
+			"spec": {"terminationGracePeriodSeconds": 9007199254740993, "containers": [{"resources": {}, "name": "main", "image": "main:v1"}]},
+			"metadata": {"labels": {"b": "2", "a": "1"}, "creationTimestamp": null}
+		}`),
+	}
+	for name, input := range inputs {
+		got, err := CanonicalJSON(input)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if string(got) != expected {
+			t.Errorf("%s: expected %s, got %s", name, expected, got)
+		}
+	}
+
+	if _, err := CanonicalJSON([]byte(`{"a":`)); err == nil {
+		t.Errorf("expected error for invalid JSON")
+	}
+}
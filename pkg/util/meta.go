@@ -16,7 +16,15 @@ limitations under the License.
 
 package util
 
-import "os"
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
 
 func GetKruiseNamespace() string {
 	if ns := os.Getenv("POD_NAMESPACE"); len(ns) > 0 {
@@ -31,3 +39,16 @@ func GetKruiseDaemonConfigNamespace() string {
 	}
 	return "kruise-daemon-config"
 }
+
+// GetBoundedObjectName returns the name if it is not longer than the max length of object names, otherwise it truncates
+// the name and appends the hash of the full name, so that the same name is always mapped to the same result.
+func GetBoundedObjectName(name string) string {
+	if len(name) <= validation.DNS1123SubdomainMaxLength {
+		return name
+	}
+	hasher := fnv.New32a()
+	_, _ = hasher.Write([]byte(name))
+	hash := rand.SafeEncodeString(fmt.Sprint(hasher.Sum32()))
+	prefix := strings.TrimRight(name[:validation.DNS1123SubdomainMaxLength-len(hash)-1], "-.")
+	return prefix + "-" + hash
+}
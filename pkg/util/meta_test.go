@@ -18,7 +18,10 @@ package util
 
 import (
 	"os"
+	"strings"
 	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 func TestMetaGetNamespace(t *testing.T) {
@@ -37,3 +40,24 @@ func TestMetaGetNamespace(t *testing.T) {
 		t.Fatalf("expect(test), but get(%s)", GetKruiseDaemonConfigNamespace())
 	}
 }
+
+func TestGetBoundedObjectName(t *testing.T) {
+	if name := GetBoundedObjectName("foo-bar"); name != "foo-bar" {
+		t.Fatalf("expect(foo-bar), but get(%s)", name)
+	}
+
+	long := strings.Repeat("a", 200) + "-" + strings.Repeat("b", 100)
+	name := GetBoundedObjectName(long)
+	if len(name) > validation.DNS1123SubdomainMaxLength {
+		t.Fatalf("expect length <= %d, but get(%d)", validation.DNS1123SubdomainMaxLength, len(name))
+	}
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		t.Fatalf("expect valid name, but get(%v)", errs)
+	}
+	if GetBoundedObjectName(long) != name {
+		t.Fatalf("expect the same name for the same input")
+	}
+	if GetBoundedObjectName(long+"c") == name {
+		t.Fatalf("expect different names for different inputs")
+	}
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"k8s.io/klog/v2"
+
+	"github.com/openkruise/kruise/pkg/features"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+	"github.com/openkruise/kruise/pkg/webhook/deletionprotection/validating"
+	"github.com/openkruise/kruise/pkg/webhook/util/deletionprotection"
+)
+
+func init() {
+	addHandlersWithGate(validating.HandlerGetterMap, func() (enabled bool) {
+		if !utilfeature.DefaultFeatureGate.Enabled(features.ResourcesDeletionProtection) {
+			return false
+		}
+		resources, err := deletionprotection.ProtectedResources()
+		if err != nil {
+			klog.ErrorS(err, "Failed to parse the resources to protect from deletion")
+			return false
+		}
+		return len(resources) > 0
+	})
+}
@@ -66,6 +66,9 @@ type AdvancedCronJobCreateUpdateHandler struct {
 func (h *AdvancedCronJobCreateUpdateHandler) validateAdvancedCronJob(obj *appsv1beta1.AdvancedCronJob) field.ErrorList {
 	allErrs := genericvalidation.ValidateObjectMeta(&obj.ObjectMeta, true, validateAdvancedCronJobName, field.NewPath("metadata"))
 	allErrs = append(allErrs, validateAdvancedCronJobSpec(&obj.Spec, field.NewPath("spec"))...)
+	if obj.Spec.SuspendUntil != nil && obj.Spec.SuspendUntil.Time.Before(time.Now()) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "suspendUntil"), obj.Spec.SuspendUntil.Format(time.RFC3339), "suspendUntil must not be in the past"))
+	}
 	return allErrs
 }
 
@@ -297,12 +300,13 @@ func (h *AdvancedCronJobCreateUpdateHandler) validateAdvancedCronJobUpdate(obj,
 	advanceCronJob.Spec.FailedJobsHistoryLimit = oldObj.Spec.FailedJobsHistoryLimit
 	advanceCronJob.Spec.StartingDeadlineSeconds = oldObj.Spec.StartingDeadlineSeconds
 	advanceCronJob.Spec.Paused = oldObj.Spec.Paused
+	advanceCronJob.Spec.SuspendUntil = oldObj.Spec.SuspendUntil
 	advanceCronJob.Spec.TimeZone = oldObj.Spec.TimeZone
 	if oldObj.Spec.Template.ImageListPullJobTemplate != nil {
 		advanceCronJob.Spec.Template.ImageListPullJobTemplate = oldObj.Spec.Template.ImageListPullJobTemplate
 	}
 	if !apiequality.Semantic.DeepEqual(advanceCronJob.Spec, oldObj.Spec) {
-		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "updates to advancedcronjob spec for fields other than 'imageListPullJobTemplate', 'schedule', 'concurrencyPolicy', 'successfulJobsHistoryLimit', 'failedJobsHistoryLimit', 'startingDeadlineSeconds', 'timeZone' and 'paused' are forbidden"))
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "updates to advancedcronjob spec for fields other than 'imageListPullJobTemplate', 'schedule', 'concurrencyPolicy', 'successfulJobsHistoryLimit', 'failedJobsHistoryLimit', 'startingDeadlineSeconds', 'timeZone', 'paused' and 'suspendUntil' are forbidden"))
 	}
 	return allErrs
 }
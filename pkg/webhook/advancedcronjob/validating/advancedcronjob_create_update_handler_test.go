@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	batchv1 "k8s.io/api/batch/v1"
@@ -153,6 +154,74 @@ func TestAdvancedCronJobCreateUpdateHandler_Handle(t *testing.T) {
 			expectedResult: true,
 			expectedError:  false,
 		},
+		{
+			name: "create v1beta1 AdvancedCronJob with future suspendUntil",
+			request: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Resource: metav1.GroupVersionResource{
+						Group:    appsv1beta1.GroupVersion.Group,
+						Version:  appsv1beta1.GroupVersion.Version,
+						Resource: "advancedcronjobs",
+					},
+					Object: runtime.RawExtension{
+						Raw: createAdvancedCronJobV1Beta1JSON(t, &appsv1beta1.AdvancedCronJob{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "test-acj-suspend",
+								Namespace: "default",
+							},
+							Spec: appsv1beta1.AdvancedCronJobSpec{
+								Schedule:     "0 0 * * *",
+								SuspendUntil: &metav1.Time{Time: time.Now().Add(time.Hour)},
+								Template: appsv1beta1.CronJobTemplate{
+									JobTemplate: &batchv1.JobTemplateSpec{
+										Spec: batchv1.JobSpec{
+											Template: createValidPodTemplateSpec(),
+										},
+									},
+								},
+							},
+						}),
+					},
+				},
+			},
+			expectedResult: true,
+			expectedError:  false,
+		},
+		{
+			name: "create invalid v1beta1 AdvancedCronJob with past suspendUntil",
+			request: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Resource: metav1.GroupVersionResource{
+						Group:    appsv1beta1.GroupVersion.Group,
+						Version:  appsv1beta1.GroupVersion.Version,
+						Resource: "advancedcronjobs",
+					},
+					Object: runtime.RawExtension{
+						Raw: createAdvancedCronJobV1Beta1JSON(t, &appsv1beta1.AdvancedCronJob{
+							ObjectMeta: metav1.ObjectMeta{
+								Name:      "test-acj-suspend",
+								Namespace: "default",
+							},
+							Spec: appsv1beta1.AdvancedCronJobSpec{
+								Schedule:     "0 0 * * *",
+								SuspendUntil: &metav1.Time{Time: time.Now().Add(-time.Hour)},
+								Template: appsv1beta1.CronJobTemplate{
+									JobTemplate: &batchv1.JobTemplateSpec{
+										Spec: batchv1.JobSpec{
+											Template: createValidPodTemplateSpec(),
+										},
+									},
+								},
+							},
+						}),
+					},
+				},
+			},
+			expectedResult: false,
+			expectedError:  true,
+		},
 		{
 			name: "create invalid v1beta1 AdvancedCronJob with empty schedule",
 			request: admission.Request{
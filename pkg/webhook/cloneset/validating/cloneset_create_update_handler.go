@@ -57,6 +57,9 @@ func (h *CloneSetCreateUpdateHandler) Handle(ctx context.Context, req admission.
 			if allErrs := ValidateCloneSetV1beta1(obj, nil); len(allErrs) > 0 {
 				return admission.Errored(http.StatusUnprocessableEntity, allErrs.ToAggregate())
 			}
+			if err := deletionprotection.ValidateProtectionExpiry(obj, nil); err != nil {
+				return admission.Errored(http.StatusUnprocessableEntity, err)
+			}
 		case admissionv1.Update:
 			if err := h.Decoder.Decode(req, obj); err != nil {
 				return admission.Errored(http.StatusBadRequest, err)
@@ -68,6 +71,9 @@ func (h *CloneSetCreateUpdateHandler) Handle(ctx context.Context, req admission.
 			if allErrs := ValidateCloneSetUpdateV1beta1(obj, oldObj); len(allErrs) > 0 {
 				return admission.Errored(http.StatusUnprocessableEntity, allErrs.ToAggregate())
 			}
+			if err := deletionprotection.ValidateProtectionExpiry(obj, oldObj); err != nil {
+				return admission.Errored(http.StatusUnprocessableEntity, err)
+			}
 		case admissionv1.Delete:
 			if len(req.OldObject.Raw) == 0 {
 				klog.InfoS("Skip to validate CloneSet deletion for no old object, maybe because of Kubernetes version < 1.16", "namespace", req.Namespace, "name", req.Name)
@@ -96,6 +102,9 @@ func (h *CloneSetCreateUpdateHandler) Handle(ctx context.Context, req admission.
 			if allErrs := h.validateCloneSet(obj, nil); len(allErrs) > 0 {
 				return admission.Errored(http.StatusUnprocessableEntity, allErrs.ToAggregate())
 			}
+			if err := deletionprotection.ValidateProtectionExpiry(obj, nil); err != nil {
+				return admission.Errored(http.StatusUnprocessableEntity, err)
+			}
 		case admissionv1.Update:
 			if err := h.Decoder.Decode(req, obj); err != nil {
 				return admission.Errored(http.StatusBadRequest, err)
@@ -107,6 +116,9 @@ func (h *CloneSetCreateUpdateHandler) Handle(ctx context.Context, req admission.
 			if allErrs := h.validateCloneSetUpdate(obj, oldObj); len(allErrs) > 0 {
 				return admission.Errored(http.StatusUnprocessableEntity, allErrs.ToAggregate())
 			}
+			if err := deletionprotection.ValidateProtectionExpiry(obj, oldObj); err != nil {
+				return admission.Errored(http.StatusUnprocessableEntity, err)
+			}
 		case admissionv1.Delete:
 			if len(req.OldObject.Raw) == 0 {
 				klog.InfoS("Skip to validate CloneSet deletion for no old object, maybe because of Kubernetes version < 1.16", "namespace", req.Namespace, "name", req.Name)
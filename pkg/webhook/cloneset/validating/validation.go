@@ -17,6 +17,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
 	"k8s.io/kubernetes/pkg/apis/core"
 	apivalidation "k8s.io/kubernetes/pkg/apis/core/validation"
 
@@ -110,6 +111,12 @@ func (h *CloneSetCreateUpdateHandler) validateCloneSetSpec(spec, oldSpec *appsv1
 func (h *CloneSetCreateUpdateHandler) validateScaleStrategy(strategy, oldStrategy *appsv1alpha1.CloneSetScaleStrategy, metadata *metav1.ObjectMeta, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
+	if strategy.QuarantineSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("quarantineSeconds"), strategy.QuarantineSeconds, "must be non-negative"))
+	}
+
+	allErrs = append(allErrs, validateDecommission(strategy.Decommission, fldPath.Child("decommission"))...)
+
 	if list := util.CheckDuplicate(strategy.PodsToDelete); len(list) > 0 {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("podsToDelete"), strategy.PodsToDelete, fmt.Sprintf("duplicated items %v", list)))
 		return allErrs
@@ -135,6 +142,29 @@ func (h *CloneSetCreateUpdateHandler) validateScaleStrategy(strategy, oldStrateg
 	return allErrs
 }
 
+func validateDecommission(decommission []appsv1alpha1.CloneSetDecommission, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	podNames := sets.NewString()
+	for i, d := range decommission {
+		idxPath := fldPath.Index(i)
+		if d.PodName == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("podName"), ""))
+		} else if podNames.Has(d.PodName) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("podName"), d.PodName))
+		}
+		podNames.Insert(d.PodName)
+		if d.TimeoutSeconds != nil {
+			if *d.TimeoutSeconds < 0 {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("timeoutSeconds"), *d.TimeoutSeconds, "must be non-negative"))
+			}
+			if !d.RequireFinalizerRemoval {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("timeoutSeconds"), *d.TimeoutSeconds, "only works with requireFinalizerRemoval"))
+			}
+		}
+	}
+	return allErrs
+}
+
 func (h *CloneSetCreateUpdateHandler) validateUpdateStrategy(strategy *appsv1alpha1.CloneSetUpdateStrategy, replicas int, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	var err error
@@ -192,6 +222,25 @@ func (h *CloneSetCreateUpdateHandler) validateUpdateStrategy(strategy *appsv1alp
 			"maxUnavailable and maxSurge should not both be less than 1"))
 	}
 
+	switch strategy.HPACoordination {
+	case "", appsv1alpha1.NoneCloneSetHPACoordinationType, appsv1alpha1.AnnotateDesiredCloneSetHPACoordinationType:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("hpaCoordination"), strategy.HPACoordination, []string{
+			string(appsv1alpha1.NoneCloneSetHPACoordinationType), string(appsv1alpha1.AnnotateDesiredCloneSetHPACoordinationType)}))
+	}
+
+	if strategy.MinKubeletVersionForInPlaceUpdate != "" {
+		if _, err := utilversion.ParseGeneric(strategy.MinKubeletVersionForInPlaceUpdate); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("minKubeletVersionForInPlaceUpdate"), strategy.MinKubeletVersionForInPlaceUpdate, err.Error()))
+		}
+	}
+
+	if strategy.ReportPolicy != nil {
+		for _, msg := range apimachineryvalidation.NameIsDNSSubdomain(strategy.ReportPolicy.ConfigMapName, false) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("reportPolicy", "configMapName"), strategy.ReportPolicy.ConfigMapName, msg))
+		}
+	}
+
 	return allErrs
 }
 
@@ -309,6 +358,12 @@ func validateCloneSetV1beta1Spec(spec, oldSpec *v1beta1.CloneSetSpec, metadata *
 func validateScaleStrategyV1beta1(strategy, oldStrategy *v1beta1.CloneSetScaleStrategy, metadata *metav1.ObjectMeta, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
+	if strategy.QuarantineSeconds < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("quarantineSeconds"), strategy.QuarantineSeconds, "must be non-negative"))
+	}
+
+	allErrs = append(allErrs, validateDecommissionV1beta1(strategy.Decommission, fldPath.Child("decommission"))...)
+
 	if list := util.CheckDuplicate(strategy.PodsToDelete); len(list) > 0 {
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("podsToDelete"), strategy.PodsToDelete, fmt.Sprintf("duplicated items %v", list)))
 		return allErrs
@@ -317,6 +372,29 @@ func validateScaleStrategyV1beta1(strategy, oldStrategy *v1beta1.CloneSetScaleSt
 	return allErrs
 }
 
+func validateDecommissionV1beta1(decommission []v1beta1.CloneSetDecommission, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	podNames := sets.NewString()
+	for i, d := range decommission {
+		idxPath := fldPath.Index(i)
+		if d.PodName == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("podName"), ""))
+		} else if podNames.Has(d.PodName) {
+			allErrs = append(allErrs, field.Duplicate(idxPath.Child("podName"), d.PodName))
+		}
+		podNames.Insert(d.PodName)
+		if d.TimeoutSeconds != nil {
+			if *d.TimeoutSeconds < 0 {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("timeoutSeconds"), *d.TimeoutSeconds, "must be non-negative"))
+			}
+			if !d.RequireFinalizerRemoval {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("timeoutSeconds"), *d.TimeoutSeconds, "only works with requireFinalizerRemoval"))
+			}
+		}
+	}
+	return allErrs
+}
+
 func validateUpdateStrategyV1beta1(strategy *v1beta1.CloneSetUpdateStrategy, replicas int, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -455,6 +533,29 @@ func validateUpdateStrategyV1beta1(strategy *v1beta1.CloneSetUpdateStrategy, rep
 		}
 	}
 
+	// Validate HPACoordination
+	switch strategy.HPACoordination {
+	case v1beta1.NoneCloneSetHPACoordinationType,
+		v1beta1.AnnotateDesiredCloneSetHPACoordinationType,
+		"": // empty means default None
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("hpaCoordination"), strategy.HPACoordination, []string{
+			string(v1beta1.NoneCloneSetHPACoordinationType), string(v1beta1.AnnotateDesiredCloneSetHPACoordinationType)}))
+	}
+
+	// Validate MinKubeletVersionForInPlaceUpdate
+	if strategy.MinKubeletVersionForInPlaceUpdate != "" {
+		if _, err := utilversion.ParseGeneric(strategy.MinKubeletVersionForInPlaceUpdate); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("minKubeletVersionForInPlaceUpdate"), strategy.MinKubeletVersionForInPlaceUpdate, err.Error()))
+		}
+	}
+
+	if strategy.ReportPolicy != nil {
+		for _, msg := range apimachineryvalidation.NameIsDNSSubdomain(strategy.ReportPolicy.ConfigMapName, false) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("reportPolicy", "configMapName"), strategy.ReportPolicy.ConfigMapName, msg))
+		}
+	}
+
 	return allErrs
 }
 
@@ -439,6 +439,51 @@ func TestValidate(t *testing.T) {
 				},
 			},
 		},
+		"invalid-quarantineSeconds": {
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas: &val1,
+				Selector: &metav1.LabelSelector{MatchLabels: validLabels},
+				Template: validPodTemplate.Template,
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+					Partition:      util.GetIntOrStrPointer(intstr.FromInt32(2)),
+					MaxUnavailable: &intOrStr1,
+				},
+				ScaleStrategy: appsv1alpha1.CloneSetScaleStrategy{
+					QuarantineSeconds: -1,
+				},
+			},
+		},
+		"invalid-decommission": {
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas: &val1,
+				Selector: &metav1.LabelSelector{MatchLabels: validLabels},
+				Template: validPodTemplate.Template,
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+					Partition:      util.GetIntOrStrPointer(intstr.FromInt32(2)),
+					MaxUnavailable: &intOrStr1,
+				},
+				ScaleStrategy: appsv1alpha1.CloneSetScaleStrategy{
+					Decommission: []appsv1alpha1.CloneSetDecommission{{PodName: "p1"}, {PodName: "p1"}},
+				},
+			},
+		},
+		"invalid-decommission-timeoutSeconds": {
+			spec: &appsv1alpha1.CloneSetSpec{
+				Replicas: &val1,
+				Selector: &metav1.LabelSelector{MatchLabels: validLabels},
+				Template: validPodTemplate.Template,
+				UpdateStrategy: appsv1alpha1.CloneSetUpdateStrategy{
+					Type:           appsv1alpha1.InPlaceIfPossibleCloneSetUpdateStrategyType,
+					Partition:      util.GetIntOrStrPointer(intstr.FromInt32(2)),
+					MaxUnavailable: &intOrStr1,
+				},
+				ScaleStrategy: appsv1alpha1.CloneSetScaleStrategy{
+					Decommission: []appsv1alpha1.CloneSetDecommission{{PodName: "p1", TimeoutSeconds: ptr.To[int32](60)}},
+				},
+			},
+		},
 		"invalid-cloneset-update-1": {
 			spec: &appsv1alpha1.CloneSetSpec{
 				Replicas: &val1,
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"regexp"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	v1 "k8s.io/api/core/v1"
@@ -42,6 +43,11 @@ import (
 
 const (
 	minDeadlineSeconds = 3
+
+	defaultPreStopHookTimeoutSeconds = 30
+
+	// allContainersName is the container name in ContainerRecreateRequest which means all containers in Pod.
+	allContainersName = "*"
 )
 
 // ContainerRecreateRequestHandler handles ContainerRecreateRequest
@@ -107,6 +113,9 @@ func (h *ContainerRecreateRequestHandler) Handle(ctx context.Context, req admiss
 	if obj.Spec.Strategy.UnreadyGracePeriodSeconds != nil && *obj.Spec.Strategy.UnreadyGracePeriodSeconds < 0 {
 		return admission.Errored(http.StatusBadRequest, fmt.Errorf("unreadyGracePeriodSeconds must be non-negative integer"))
 	}
+	if obj.Spec.Strategy.RecreateIntervalSeconds < 0 {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("recreateIntervalSeconds must be non-negative integer"))
+	}
 
 	// defaults
 	switch obj.Spec.Strategy.FailurePolicy {
@@ -132,6 +141,14 @@ func (h *ContainerRecreateRequestHandler) Handle(ctx context.Context, req admiss
 		return admission.Errored(http.StatusBadRequest, fmt.Errorf("not allowed to recreate containers in a pending Pod"))
 	}
 
+	err = expandContainerRecreateRequestContainers(obj, pod)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	err = validateAndDefaultPreStopHooks(obj)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
 	err = injectPodIntoContainerRecreateRequest(obj, pod)
 	if err != nil {
 		return admission.Errored(http.StatusBadRequest, err)
@@ -156,6 +173,93 @@ func isTerminatedBySidecarTerminator(pod *v1.Pod) bool {
 	return false
 }
 
+// expandContainerRecreateRequestContainers replaces the entries with "*" name or nameRegexp by the matched containers in Pod,
+// so that the containers in ContainerRecreateRequest are always concrete for kruise-daemon.
+func expandContainerRecreateRequestContainers(obj *appsv1alpha1.ContainerRecreateRequest, pod *v1.Pod) error {
+	explicitNames := sets.NewString()
+	for i := range obj.Spec.Containers {
+		c := &obj.Spec.Containers[i]
+		if c.Name != allContainersName && c.NameRegexp == "" {
+			explicitNames.Insert(c.Name)
+		}
+	}
+
+	containers := make([]appsv1alpha1.ContainerRecreateRequestContainer, 0, len(obj.Spec.Containers))
+	expandedNames := sets.NewString()
+	for i := range obj.Spec.Containers {
+		c := &obj.Spec.Containers[i]
+		var matchName func(string) bool
+		switch {
+		case c.Name == "" && c.NameRegexp == "":
+			return fmt.Errorf("name or nameRegexp of container can not be empty")
+		case c.Name != "" && c.NameRegexp != "":
+			return fmt.Errorf("name and nameRegexp of container can not be both specified")
+		case c.Name == allContainersName:
+			matchName = func(string) bool { return true }
+		case c.NameRegexp != "":
+			re, err := regexp.Compile(c.NameRegexp)
+			if err != nil {
+				return fmt.Errorf("invalid nameRegexp %s: %v", c.NameRegexp, err)
+			}
+			matchName = re.MatchString
+		default:
+			containers = append(containers, *c)
+			continue
+		}
+
+		var matched bool
+		for _, podContainer := range pod.Spec.Containers {
+			if !matchName(podContainer.Name) {
+				continue
+			}
+			matched = true
+			if explicitNames.Has(podContainer.Name) || expandedNames.Has(podContainer.Name) {
+				continue
+			}
+			expandedNames.Insert(podContainer.Name)
+			expanded := c.DeepCopy()
+			expanded.Name = podContainer.Name
+			expanded.NameRegexp = ""
+			containers = append(containers, *expanded)
+		}
+		if !matched {
+			return fmt.Errorf("nameRegexp %s matches no container in Pod", c.NameRegexp)
+		}
+	}
+	obj.Spec.Containers = containers
+	return nil
+}
+
+// validateAndDefaultPreStopHooks validates the preStopHook of containers and sets the default timeout and failurePolicy.
+func validateAndDefaultPreStopHooks(obj *appsv1alpha1.ContainerRecreateRequest) error {
+	for i := range obj.Spec.Containers {
+		c := &obj.Spec.Containers[i]
+		hook := c.PreStopHook
+		if hook == nil {
+			continue
+		}
+		if (hook.Exec == nil) == (hook.HTTPGet == nil) {
+			return fmt.Errorf("one and only one of exec and httpGet should be specified in preStopHook of container %s", c.Name)
+		}
+		if hook.Exec != nil && len(hook.Exec.Command) == 0 {
+			return fmt.Errorf("exec command in preStopHook of container %s can not be empty", c.Name)
+		}
+		if hook.TimeoutSeconds < 0 {
+			return fmt.Errorf("timeoutSeconds in preStopHook of container %s must be non-negative integer", c.Name)
+		} else if hook.TimeoutSeconds == 0 {
+			hook.TimeoutSeconds = defaultPreStopHookTimeoutSeconds
+		}
+		switch hook.FailurePolicy {
+		case "":
+			hook.FailurePolicy = appsv1alpha1.ContainerRecreateRequestFailurePolicyFail
+		case appsv1alpha1.ContainerRecreateRequestFailurePolicyFail, appsv1alpha1.ContainerRecreateRequestFailurePolicyIgnore:
+		default:
+			return fmt.Errorf("unknown failurePolicy %s in preStopHook of container %s", hook.FailurePolicy, c.Name)
+		}
+	}
+	return nil
+}
+
 func injectPodIntoContainerRecreateRequest(obj *appsv1alpha1.ContainerRecreateRequest, pod *v1.Pod) error {
 	obj.Labels[appsv1alpha1.ContainerRecreateRequestNodeNameKey] = pod.Spec.NodeName
 	obj.Labels[appsv1alpha1.ContainerRecreateRequestPodUIDKey] = string(pod.UID)
@@ -0,0 +1,187 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+func TestExpandContainerRecreateRequestContainers(t *testing.T) {
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "init"}},
+			Containers:     []v1.Container{{Name: "main"}, {Name: "sidecar-a1b2"}, {Name: "sidecar-c3d4"}},
+		},
+	}
+
+	cases := []struct {
+		name          string
+		containers    []appsv1alpha1.ContainerRecreateRequestContainer
+		expectedNames []string
+		expectedErr   bool
+	}{
+		{
+			name:          "explicit names",
+			containers:    []appsv1alpha1.ContainerRecreateRequestContainer{{Name: "main"}},
+			expectedNames: []string{"main"},
+		},
+		{
+			name:          "all containers",
+			containers:    []appsv1alpha1.ContainerRecreateRequestContainer{{Name: "*"}},
+			expectedNames: []string{"main", "sidecar-a1b2", "sidecar-c3d4"},
+		},
+		{
+			name:          "regexp with explicit name",
+			containers:    []appsv1alpha1.ContainerRecreateRequestContainer{{NameRegexp: "^sidecar-"}, {Name: "main"}},
+			expectedNames: []string{"sidecar-a1b2", "sidecar-c3d4", "main"},
+		},
+		{
+			name:          "overlapped patterns",
+			containers:    []appsv1alpha1.ContainerRecreateRequestContainer{{NameRegexp: "a1b2$"}, {Name: "*"}, {Name: "main"}},
+			expectedNames: []string{"sidecar-a1b2", "sidecar-c3d4", "main"},
+		},
+		{
+			name:        "regexp matches no container",
+			containers:  []appsv1alpha1.ContainerRecreateRequestContainer{{NameRegexp: "^init$"}},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid regexp",
+			containers:  []appsv1alpha1.ContainerRecreateRequestContainer{{NameRegexp: "sidecar-("}},
+			expectedErr: true,
+		},
+		{
+			name:        "both name and regexp",
+			containers:  []appsv1alpha1.ContainerRecreateRequestContainer{{Name: "main", NameRegexp: "^sidecar-"}},
+			expectedErr: true,
+		},
+		{
+			name:        "neither name nor regexp",
+			containers:  []appsv1alpha1.ContainerRecreateRequestContainer{{}},
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			obj := &appsv1alpha1.ContainerRecreateRequest{Spec: appsv1alpha1.ContainerRecreateRequestSpec{Containers: testCase.containers}}
+			err := expandContainerRecreateRequestContainers(obj, pod)
+			if (err != nil) != testCase.expectedErr {
+				t.Fatalf("expected error %v, got %v", testCase.expectedErr, err)
+			}
+			if testCase.expectedErr {
+				return
+			}
+			var names []string
+			for _, c := range obj.Spec.Containers {
+				if c.NameRegexp != "" {
+					t.Fatalf("expected nameRegexp cleared after expanding, got %s", c.NameRegexp)
+				}
+				names = append(names, c.Name)
+			}
+			if !reflect.DeepEqual(names, testCase.expectedNames) {
+				t.Fatalf("expected containers %v, got %v", testCase.expectedNames, names)
+			}
+		})
+	}
+}
+
+func TestValidateAndDefaultPreStopHooks(t *testing.T) {
+	cases := []struct {
+		name        string
+		hook        *appsv1alpha1.ContainerRecreateRequestPreStopHook
+		expectedErr bool
+		expected    *appsv1alpha1.ContainerRecreateRequestPreStopHook
+	}{
+		{
+			name: "no hook",
+		},
+		{
+			name: "default exec hook",
+			hook: &appsv1alpha1.ContainerRecreateRequestPreStopHook{Exec: &v1.ExecAction{Command: []string{"/drain.sh"}}},
+			expected: &appsv1alpha1.ContainerRecreateRequestPreStopHook{
+				Exec:           &v1.ExecAction{Command: []string{"/drain.sh"}},
+				TimeoutSeconds: defaultPreStopHookTimeoutSeconds,
+				FailurePolicy:  appsv1alpha1.ContainerRecreateRequestFailurePolicyFail,
+			},
+		},
+		{
+			name: "httpGet hook with ignore policy",
+			hook: &appsv1alpha1.ContainerRecreateRequestPreStopHook{
+				HTTPGet:        &v1.HTTPGetAction{Path: "/drain"},
+				TimeoutSeconds: 10,
+				FailurePolicy:  appsv1alpha1.ContainerRecreateRequestFailurePolicyIgnore,
+			},
+			expected: &appsv1alpha1.ContainerRecreateRequestPreStopHook{
+				HTTPGet:        &v1.HTTPGetAction{Path: "/drain"},
+				TimeoutSeconds: 10,
+				FailurePolicy:  appsv1alpha1.ContainerRecreateRequestFailurePolicyIgnore,
+			},
+		},
+		{
+			name:        "no action",
+			hook:        &appsv1alpha1.ContainerRecreateRequestPreStopHook{TimeoutSeconds: 10},
+			expectedErr: true,
+		},
+		{
+			name: "both actions",
+			hook: &appsv1alpha1.ContainerRecreateRequestPreStopHook{
+				Exec:    &v1.ExecAction{Command: []string{"/drain.sh"}},
+				HTTPGet: &v1.HTTPGetAction{Path: "/drain"},
+			},
+			expectedErr: true,
+		},
+		{
+			name:        "empty command",
+			hook:        &appsv1alpha1.ContainerRecreateRequestPreStopHook{Exec: &v1.ExecAction{}},
+			expectedErr: true,
+		},
+		{
+			name:        "negative timeout",
+			hook:        &appsv1alpha1.ContainerRecreateRequestPreStopHook{Exec: &v1.ExecAction{Command: []string{"/drain.sh"}}, TimeoutSeconds: -1},
+			expectedErr: true,
+		},
+		{
+			name:        "unknown failurePolicy",
+			hook:        &appsv1alpha1.ContainerRecreateRequestPreStopHook{Exec: &v1.ExecAction{Command: []string{"/drain.sh"}}, FailurePolicy: "Retry"},
+			expectedErr: true,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			obj := &appsv1alpha1.ContainerRecreateRequest{Spec: appsv1alpha1.ContainerRecreateRequestSpec{
+				Containers: []appsv1alpha1.ContainerRecreateRequestContainer{{Name: "app", PreStopHook: testCase.hook}},
+			}}
+			err := validateAndDefaultPreStopHooks(obj)
+			if (err != nil) != testCase.expectedErr {
+				t.Fatalf("expected error %v, got %v", testCase.expectedErr, err)
+			}
+			if testCase.expectedErr {
+				return
+			}
+			if !reflect.DeepEqual(obj.Spec.Containers[0].PreStopHook, testCase.expected) {
+				t.Fatalf("expected preStopHook %+v, got %+v", testCase.expected, obj.Spec.Containers[0].PreStopHook)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2026 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metavalidation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+// ContainerRecreateRequestSetCreateUpdateHandler handles ContainerRecreateRequestSet
+type ContainerRecreateRequestSetCreateUpdateHandler struct {
+	// Decoder decodes objects
+	Decoder admission.Decoder
+}
+
+var _ admission.Handler = &ContainerRecreateRequestSetCreateUpdateHandler{}
+
+func NewHandler(mgr manager.Manager) admission.Handler {
+	return &ContainerRecreateRequestSetCreateUpdateHandler{Decoder: admission.NewDecoder(mgr.GetScheme())}
+}
+
+// Handle handles admission requests.
+func (h *ContainerRecreateRequestSetCreateUpdateHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	obj := &appsv1alpha1.ContainerRecreateRequestSet{}
+
+	err := h.Decoder.Decode(req, obj)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if err := validate(obj); err != nil {
+		klog.ErrorS(err, "Error validate ContainerRecreateRequestSet", "name", obj.Name)
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	return admission.ValidationResponse(true, "allowed")
+}
+
+func validate(obj *appsv1alpha1.ContainerRecreateRequestSet) error {
+	var allErrs field.ErrorList
+	// the name is the value of the label on the ContainerRecreateRequests created by this set
+	for _, msg := range validation.IsValidLabelValue(obj.Name) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("metadata", "name"), obj.Name, msg))
+	}
+	allErrs = append(allErrs, validatePodSelector(obj.Spec.PodSelector, field.NewPath("spec", "podSelector"))...)
+	allErrs = append(allErrs, validateContainers(obj.Spec.Containers, field.NewPath("spec", "containers"))...)
+	return allErrs.ToAggregate()
+}
+
+func validatePodSelector(selector *metav1.LabelSelector, fldPath *field.Path) field.ErrorList {
+	// an empty selector matches all Pods in the namespace
+	if selector == nil || (len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0) {
+		return field.ErrorList{field.Required(fldPath, "podSelector must not be empty")}
+	}
+	return metavalidation.ValidateLabelSelector(selector, metavalidation.LabelSelectorValidationOptions{}, fldPath)
+}
+
+func validateContainers(containers []appsv1alpha1.ContainerRecreateRequestContainer, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if len(containers) == 0 {
+		return append(allErrs, field.Required(fldPath, "containers must not be empty"))
+	}
+	names := sets.NewString()
+	nameRegexps := sets.NewString()
+	for i, c := range containers {
+		idxPath := fldPath.Index(i)
+		switch {
+		case c.Name != "" && c.NameRegexp != "":
+			allErrs = append(allErrs, field.Invalid(idxPath, c.Name, "name and nameRegexp must not be set at the same time"))
+		case c.Name != "":
+			if names.Has(c.Name) {
+				allErrs = append(allErrs, field.Duplicate(idxPath.Child("name"), c.Name))
+			}
+			names.Insert(c.Name)
+		case c.NameRegexp != "":
+			if nameRegexps.Has(c.NameRegexp) {
+				allErrs = append(allErrs, field.Duplicate(idxPath.Child("nameRegexp"), c.NameRegexp))
+			}
+			nameRegexps.Insert(c.NameRegexp)
+			if _, err := regexp.Compile(c.NameRegexp); err != nil {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("nameRegexp"), c.NameRegexp, err.Error()))
+			}
+		default:
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "name or nameRegexp must be set"))
+		}
+	}
+	return allErrs
+}
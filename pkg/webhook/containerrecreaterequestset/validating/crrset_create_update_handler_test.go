@@ -0,0 +1,134 @@
+/*
+Copyright 2026 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
+)
+
+func TestValidate(t *testing.T) {
+	newCRRSet := func(fn func(*appsv1alpha1.ContainerRecreateRequestSet)) *appsv1alpha1.ContainerRecreateRequestSet {
+		obj := &appsv1alpha1.ContainerRecreateRequestSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "restart-sidecar"},
+			Spec: appsv1alpha1.ContainerRecreateRequestSetSpec{
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "demo"}},
+				Containers:  []appsv1alpha1.ContainerRecreateRequestContainer{{Name: "sidecar"}, {NameRegexp: "^log-.*"}},
+			},
+		}
+		if fn != nil {
+			fn(obj)
+		}
+		return obj
+	}
+
+	cases := []struct {
+		name   string
+		obj    *appsv1alpha1.ContainerRecreateRequestSet
+		errMsg string
+	}{
+		{
+			name: "valid",
+			obj:  newCRRSet(nil),
+		},
+		{
+			name:   "too long name",
+			obj:    newCRRSet(func(obj *appsv1alpha1.ContainerRecreateRequestSet) { obj.Name = strings.Repeat("a", 64) }),
+			errMsg: "metadata.name",
+		},
+		{
+			name:   "nil podSelector",
+			obj:    newCRRSet(func(obj *appsv1alpha1.ContainerRecreateRequestSet) { obj.Spec.PodSelector = nil }),
+			errMsg: "podSelector must not be empty",
+		},
+		{
+			name: "empty podSelector",
+			obj: newCRRSet(func(obj *appsv1alpha1.ContainerRecreateRequestSet) {
+				obj.Spec.PodSelector = &metav1.LabelSelector{}
+			}),
+			errMsg: "podSelector must not be empty",
+		},
+		{
+			name: "invalid podSelector",
+			obj: newCRRSet(func(obj *appsv1alpha1.ContainerRecreateRequestSet) {
+				obj.Spec.PodSelector = &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "app", Operator: metav1.LabelSelectorOpIn},
+				}}
+			}),
+			errMsg: "spec.podSelector.matchExpressions[0].values",
+		},
+		{
+			name:   "empty containers",
+			obj:    newCRRSet(func(obj *appsv1alpha1.ContainerRecreateRequestSet) { obj.Spec.Containers = nil }),
+			errMsg: "containers must not be empty",
+		},
+		{
+			name: "container without name",
+			obj: newCRRSet(func(obj *appsv1alpha1.ContainerRecreateRequestSet) {
+				obj.Spec.Containers = append(obj.Spec.Containers, appsv1alpha1.ContainerRecreateRequestContainer{})
+			}),
+			errMsg: "name or nameRegexp must be set",
+		},
+		{
+			name: "duplicate container names",
+			obj: newCRRSet(func(obj *appsv1alpha1.ContainerRecreateRequestSet) {
+				obj.Spec.Containers = append(obj.Spec.Containers, appsv1alpha1.ContainerRecreateRequestContainer{Name: "sidecar"})
+			}),
+			errMsg: "Duplicate value",
+		},
+		{
+			name: "duplicate container nameRegexps",
+			obj: newCRRSet(func(obj *appsv1alpha1.ContainerRecreateRequestSet) {
+				obj.Spec.Containers = append(obj.Spec.Containers, appsv1alpha1.ContainerRecreateRequestContainer{NameRegexp: "^log-.*"})
+			}),
+			errMsg: "Duplicate value",
+		},
+		{
+			name: "invalid nameRegexp",
+			obj: newCRRSet(func(obj *appsv1alpha1.ContainerRecreateRequestSet) {
+				obj.Spec.Containers = []appsv1alpha1.ContainerRecreateRequestContainer{{NameRegexp: "("}}
+			}),
+			errMsg: "spec.containers[0].nameRegexp",
+		},
+		{
+			name: "both name and nameRegexp",
+			obj: newCRRSet(func(obj *appsv1alpha1.ContainerRecreateRequestSet) {
+				obj.Spec.Containers = []appsv1alpha1.ContainerRecreateRequestContainer{{Name: "sidecar", NameRegexp: "^log-.*"}}
+			}),
+			errMsg: "must not be set at the same time",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validate(tc.obj)
+			if tc.errMsg == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.errMsg) {
+				t.Fatalf("expected error containing %q, got %v", tc.errMsg, err)
+			}
+		})
+	}
+}
@@ -22,7 +22,11 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"sort"
+	"strings"
 
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -55,8 +59,19 @@ func (h *DaemonSetCreateUpdateHandler) Handle(ctx context.Context, req admission
 		if err := h.Decoder.Decode(req, obj); err != nil {
 			return admission.Errored(http.StatusBadRequest, err)
 		}
+		normalize := true
+		if req.AdmissionRequest.Operation == admissionv1.Update {
+			oldObj := &appsv1beta1.DaemonSet{}
+			if err := h.Decoder.DecodeRaw(req.AdmissionRequest.OldObject, oldObj); err != nil {
+				return admission.Errored(http.StatusBadRequest, err)
+			}
+			normalize = !reflect.DeepEqual(obj.Spec.Patches, oldObj.Spec.Patches)
+		}
 		var copy runtime.Object = obj.DeepCopy()
 		defaults.SetDefaultsDaemonSetV1beta1(obj)
+		if normalize {
+			normalizePatchSelectors(obj.Spec.Patches)
+		}
 		if reflect.DeepEqual(obj, copy) {
 			return admission.Allowed("")
 		}
@@ -74,8 +89,19 @@ func (h *DaemonSetCreateUpdateHandler) Handle(ctx context.Context, req admission
 		if err := h.Decoder.Decode(req, obj); err != nil {
 			return admission.Errored(http.StatusBadRequest, err)
 		}
+		normalize := true
+		if req.AdmissionRequest.Operation == admissionv1.Update {
+			oldObj := &appsv1alpha1.DaemonSet{}
+			if err := h.Decoder.DecodeRaw(req.AdmissionRequest.OldObject, oldObj); err != nil {
+				return admission.Errored(http.StatusBadRequest, err)
+			}
+			normalize = !reflect.DeepEqual(obj.Spec.Patches, oldObj.Spec.Patches)
+		}
 		var copy runtime.Object = obj.DeepCopy()
 		defaults.SetDefaultsDaemonSet(obj)
+		if normalize {
+			normalizePatchSelectorsV1alpha1(obj.Spec.Patches)
+		}
 		if reflect.DeepEqual(obj, copy) {
 			return admission.Allowed("")
 		}
@@ -92,6 +118,43 @@ func (h *DaemonSetCreateUpdateHandler) Handle(ctx context.Context, req admission
 	return admission.Errored(http.StatusBadRequest, fmt.Errorf("unsupported version: %s", req.AdmissionRequest.Resource.Version))
 }
 
+// normalizePatchSelectors normalizes the selectors of patches. It only runs on creation or when the patches are changed,
+// so that updating other fields never rewrites the patches already stored, which would produce a new revision.
+func normalizePatchSelectors(patches []appsv1beta1.DaemonSetPatch) {
+	for i := range patches {
+		normalizeLabelSelector(patches[i].Selector)
+	}
+}
+
+// normalizePatchSelectorsV1alpha1 is the same as normalizePatchSelectors for v1alpha1.
+func normalizePatchSelectorsV1alpha1(patches []appsv1alpha1.DaemonSetPatch) {
+	for i := range patches {
+		normalizeLabelSelector(patches[i].Selector)
+	}
+}
+
+// normalizeLabelSelector sorts the matchExpressions of selector by key and the values of each requirement, so that
+// the same selectors written in different orders are stored the same and don't produce spurious diffs. The
+// requirements are ANDed and the values are a set, so the order doesn't change what the selector matches.
+func normalizeLabelSelector(selector *metav1.LabelSelector) {
+	if selector == nil {
+		return
+	}
+	for i := range selector.MatchExpressions {
+		sort.Strings(selector.MatchExpressions[i].Values)
+	}
+	sort.SliceStable(selector.MatchExpressions, func(i, j int) bool {
+		a, b := selector.MatchExpressions[i], selector.MatchExpressions[j]
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		if a.Operator != b.Operator {
+			return a.Operator < b.Operator
+		}
+		return strings.Join(a.Values, ",") < strings.Join(b.Values, ",")
+	})
+}
+
 //var _ inject.Client = &DaemonSetCreateUpdateHandler{}
 //
 //// InjectClient injects the client into the DaemonSetCreateUpdateHandler
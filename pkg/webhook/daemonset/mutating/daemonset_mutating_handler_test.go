@@ -0,0 +1,213 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openkruise/kruise/apis"
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+)
+
+func TestNormalizeLabelSelector(t *testing.T) {
+	selector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{"pool": "a"},
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "zone", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"z3", "z1"}},
+			{Key: "disk-type", Operator: metav1.LabelSelectorOpIn, Values: []string{"ssd", "nvme"}},
+			{Key: "zone", Operator: metav1.LabelSelectorOpExists},
+			{Key: "arch", Operator: metav1.LabelSelectorOpIn, Values: []string{"arm64", "amd64"}},
+		},
+	}
+	expected := &metav1.LabelSelector{
+		MatchLabels: map[string]string{"pool": "a"},
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "arch", Operator: metav1.LabelSelectorOpIn, Values: []string{"amd64", "arm64"}},
+			{Key: "disk-type", Operator: metav1.LabelSelectorOpIn, Values: []string{"nvme", "ssd"}},
+			{Key: "zone", Operator: metav1.LabelSelectorOpExists},
+			{Key: "zone", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"z1", "z3"}},
+		},
+	}
+
+	normalized := selector.DeepCopy()
+	normalizeLabelSelector(normalized)
+	if !reflect.DeepEqual(normalized, expected) {
+		t.Fatalf("expected %v, got %v", expected, normalized)
+	}
+
+	// the same selector in any order is normalized the same
+	reversed := selector.DeepCopy()
+	for i, j := 0, len(reversed.MatchExpressions)-1; i < j; i, j = i+1, j-1 {
+		reversed.MatchExpressions[i], reversed.MatchExpressions[j] = reversed.MatchExpressions[j], reversed.MatchExpressions[i]
+	}
+	normalizeLabelSelector(reversed)
+	if !reflect.DeepEqual(reversed, expected) {
+		t.Fatalf("expected %v, got %v", expected, reversed)
+	}
+	normalizeLabelSelector(normalized)
+	if !reflect.DeepEqual(normalized, expected) {
+		t.Fatalf("expected normalization idempotent, got %v", normalized)
+	}
+
+	// the normalized selector matches the same labels
+	before, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := metav1.LabelSelectorAsSelector(normalized)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, set := range []labels.Set{
+		{"pool": "a", "zone": "z2", "disk-type": "ssd", "arch": "amd64"},
+		{"pool": "a", "zone": "z1", "disk-type": "ssd", "arch": "amd64"},
+		{"pool": "a", "zone": "z2", "disk-type": "hdd", "arch": "arm64"},
+		{"pool": "a", "disk-type": "nvme", "arch": "arm64"},
+		{"pool": "b", "zone": "z2", "disk-type": "nvme", "arch": "arm64"},
+	} {
+		if before.Matches(set) != after.Matches(set) {
+			t.Fatalf("expected the same match of %v, got %v before and %v after", set, before.Matches(set), after.Matches(set))
+		}
+	}
+
+	normalizeLabelSelector(nil)
+}
+
+func TestHandleNormalizesPatchSelectors(t *testing.T) {
+	utilruntime.Must(apis.AddToScheme(scheme.Scheme))
+	handler := DaemonSetCreateUpdateHandler{Decoder: admission.NewDecoder(scheme.Scheme)}
+
+	newDaemonSet := func(expressions ...metav1.LabelSelectorRequirement) []byte {
+		ds := &appsv1beta1.DaemonSet{
+			TypeMeta:   metav1.TypeMeta{APIVersion: appsv1beta1.GroupVersion.String(), Kind: "DaemonSet"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "foo"},
+		}
+		ds.Spec.Patches = []appsv1beta1.DaemonSetPatch{{
+			Selector: &metav1.LabelSelector{MatchExpressions: expressions},
+			Patch:    runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"patched":"true"}}}`)},
+		}}
+		raw, err := json.Marshal(ds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return raw
+	}
+	admit := func(raw []byte) *appsv1beta1.DaemonSet {
+		resp := handler.Handle(context.TODO(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Resource: metav1.GroupVersionResource{Group: appsv1beta1.GroupVersion.Group, Version: appsv1beta1.GroupVersion.Version, Resource: "daemonsets"},
+			Object:   runtime.RawExtension{Raw: raw},
+		}})
+		if !resp.Allowed {
+			t.Fatalf("expected allowed, got %v", resp.Result)
+		}
+		patchBytes, err := json.Marshal(resp.Patches)
+		if err != nil {
+			t.Fatal(err)
+		}
+		patch, err := jsonpatch.DecodePatch(patchBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		patched, err := patch.Apply(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ds := &appsv1beta1.DaemonSet{}
+		if err = json.Unmarshal(patched, ds); err != nil {
+			t.Fatal(err)
+		}
+		return ds
+	}
+
+	disk := metav1.LabelSelectorRequirement{Key: "disk-type", Operator: metav1.LabelSelectorOpIn, Values: []string{"ssd", "nvme"}}
+	arch := metav1.LabelSelectorRequirement{Key: "arch", Operator: metav1.LabelSelectorOpIn, Values: []string{"arm64", "amd64"}}
+	ds1 := admit(newDaemonSet(disk, arch))
+	ds2 := admit(newDaemonSet(*arch.DeepCopy(), *disk.DeepCopy()))
+	if !reflect.DeepEqual(ds1.Spec.Patches, ds2.Spec.Patches) {
+		t.Fatalf("expected the same patches admitted, got %v and %v", ds1.Spec.Patches, ds2.Spec.Patches)
+	}
+	expected := []metav1.LabelSelectorRequirement{
+		{Key: "arch", Operator: metav1.LabelSelectorOpIn, Values: []string{"amd64", "arm64"}},
+		{Key: "disk-type", Operator: metav1.LabelSelectorOpIn, Values: []string{"nvme", "ssd"}},
+	}
+	if !reflect.DeepEqual(ds1.Spec.Patches[0].Selector.MatchExpressions, expected) {
+		t.Fatalf("expected %v, got %v", expected, ds1.Spec.Patches[0].Selector.MatchExpressions)
+	}
+}
+
+func TestHandleNormalizesPatchSelectorsOnlyWhenChanged(t *testing.T) {
+	utilruntime.Must(apis.AddToScheme(scheme.Scheme))
+	handler := DaemonSetCreateUpdateHandler{Decoder: admission.NewDecoder(scheme.Scheme)}
+
+	newDaemonSet := func(image string, values ...string) []byte {
+		ds := &appsv1beta1.DaemonSet{
+			TypeMeta:   metav1.TypeMeta{APIVersion: appsv1beta1.GroupVersion.String(), Kind: "DaemonSet"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: metav1.NamespaceDefault, Name: "foo"},
+		}
+		ds.Spec.Template.Spec.Containers = []corev1.Container{{Name: "main", Image: image}}
+		ds.Spec.Patches = []appsv1beta1.DaemonSetPatch{{
+			Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "disk-type", Operator: metav1.LabelSelectorOpIn, Values: values},
+			}},
+			Patch: runtime.RawExtension{Raw: []byte(`{"metadata":{"labels":{"patched":"true"}}}`)},
+		}}
+		raw, err := json.Marshal(ds)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return raw
+	}
+	isSelectorPatched := func(newRaw, oldRaw []byte) bool {
+		resp := handler.Handle(context.TODO(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Update,
+			Resource:  metav1.GroupVersionResource{Group: appsv1beta1.GroupVersion.Group, Version: appsv1beta1.GroupVersion.Version, Resource: "daemonsets"},
+			Object:    runtime.RawExtension{Raw: newRaw},
+			OldObject: runtime.RawExtension{Raw: oldRaw},
+		}})
+		if !resp.Allowed {
+			t.Fatalf("expected allowed, got %v", resp.Result)
+		}
+		for _, p := range resp.Patches {
+			if strings.HasPrefix(p.Path, "/spec/patches") {
+				return true
+			}
+		}
+		return false
+	}
+
+	oldRaw := newDaemonSet("nginx:1.0", "ssd", "nvme")
+	if isSelectorPatched(newDaemonSet("nginx:1.1", "ssd", "nvme"), oldRaw) {
+		t.Fatalf("expected the unchanged patches not normalized on update")
+	}
+	if !isSelectorPatched(newDaemonSet("nginx:1.1", "ssd", "nvme", "hdd"), oldRaw) {
+		t.Fatalf("expected the changed patches normalized on update")
+	}
+}
@@ -20,17 +20,27 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
 	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	genericvalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	resourcehelper "k8s.io/component-helpers/resource"
 	"k8s.io/klog/v2"
 	apivalidation "k8s.io/kubernetes/pkg/apis/core/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	daemonsetcontroller "github.com/openkruise/kruise/pkg/controller/daemonset"
 )
 
 // ValidateDaemonSetName can be used to check whether the given daemon set name is valid.
@@ -38,8 +48,20 @@ import (
 // trailing dashes are allowed.
 var ValidateDaemonSetName = genericvalidation.NameIsDNSSubdomain
 
+const (
+	// maxDryRunSampleNodes is the max number of nodes to render the pod template for in a dry-run request.
+	maxDryRunSampleNodes = 10
+
+	// patchRecreatedPodsAnnotation is the key of the audit annotation in the admission response of an update of
+	// spec.patches, which is the number of pods that would be recreated by the edit.
+	patchRecreatedPodsAnnotation = "patch-recreated-pods"
+)
+
 // DaemonSetCreateUpdateHandler handles DaemonSet
 type DaemonSetCreateUpdateHandler struct {
+	// Client is used to list sample nodes to render the pod template for dry-run requests
+	Client client.Client
+
 	// Decoder decodes objects
 	Decoder admission.Decoder
 }
@@ -53,9 +75,10 @@ func (h *DaemonSetCreateUpdateHandler) validateDaemonSetUpdate(ds, oldDs *appsv1
 	daemonset.Spec.BurstReplicas = oldDs.Spec.BurstReplicas
 	daemonset.Spec.MinReadySeconds = oldDs.Spec.MinReadySeconds
 	daemonset.Spec.RevisionHistoryLimit = oldDs.Spec.RevisionHistoryLimit
+	daemonset.Spec.Patches = oldDs.Spec.Patches
 
 	if !apiequality.Semantic.DeepEqual(daemonset.Spec, oldDs.Spec) {
-		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "updates to daemonset spec for fields other than 'BurstReplicas', 'template', 'lifecycle',  'updateStrategy', 'minReadySeconds', and 'revisionHistoryLimit' are forbidden"))
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "updates to daemonset spec for fields other than 'BurstReplicas', 'template', 'patches', 'lifecycle',  'updateStrategy', 'minReadySeconds', and 'revisionHistoryLimit' are forbidden"))
 	}
 	allErrs = append(allErrs, validateDaemonSetSpec(&ds.Spec, field.NewPath("spec"))...)
 	return allErrs
@@ -70,10 +93,11 @@ func (h *DaemonSetCreateUpdateHandler) validateDaemonSetUpdateV1beta1(ds, oldDs
 	daemonset.Spec.BurstReplicas = oldDs.Spec.BurstReplicas
 	daemonset.Spec.MinReadySeconds = oldDs.Spec.MinReadySeconds
 	daemonset.Spec.RevisionHistoryLimit = oldDs.Spec.RevisionHistoryLimit
+	daemonset.Spec.Patches = oldDs.Spec.Patches
 	daemonset.Spec.ScaleStrategy = oldDs.Spec.ScaleStrategy
 
 	if !apiequality.Semantic.DeepEqual(daemonset.Spec, oldDs.Spec) {
-		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "updates to daemonset spec for fields other than 'BurstReplicas', 'template', 'lifecycle', 'scaleStrategy', 'updateStrategy', 'minReadySeconds', and 'revisionHistoryLimit' are forbidden"))
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "updates to daemonset spec for fields other than 'BurstReplicas', 'template', 'patches', 'lifecycle', 'scaleStrategy', 'updateStrategy', 'minReadySeconds', and 'revisionHistoryLimit' are forbidden"))
 	}
 	allErrs = append(allErrs, validateDaemonSetSpecV1beta1(&ds.Spec, field.NewPath("spec"))...)
 	return allErrs
@@ -97,7 +121,7 @@ func (h *DaemonSetCreateUpdateHandler) Handle(ctx context.Context, req admission
 				klog.ErrorS(err, "validate daemonset failed", "namespace", obj.Namespace, "name", obj.Name, "operation", req.AdmissionRequest.Operation)
 				return admission.Errored(http.StatusInternalServerError, err)
 			}
-			return admission.ValidationResponse(allowed, reason)
+			return admission.ValidationResponse(allowed, reason).WithWarnings(h.warningsV1beta1(ctx, req, obj)...)
 
 		case admissionv1.Update:
 			if err := h.Decoder.Decode(req, obj); err != nil {
@@ -110,6 +134,9 @@ func (h *DaemonSetCreateUpdateHandler) Handle(ctx context.Context, req admission
 			if allErrs := h.validateDaemonSetUpdateV1beta1(obj, oldObj); len(allErrs) > 0 {
 				return admission.Errored(http.StatusUnprocessableEntity, allErrs.ToAggregate())
 			}
+			resp := admission.ValidationResponse(true, "").WithWarnings(h.warningsV1beta1(ctx, req, obj)...)
+			resp.AuditAnnotations = h.patchBlastRadiusAnnotations(ctx, obj, oldObj)
+			return resp
 		}
 		return admission.ValidationResponse(true, "")
 
@@ -126,7 +153,7 @@ func (h *DaemonSetCreateUpdateHandler) Handle(ctx context.Context, req admission
 				klog.ErrorS(err, "validate daemonset failed", "namespace", obj.Namespace, "name", obj.Name, "operation", req.AdmissionRequest.Operation)
 				return admission.Errored(http.StatusInternalServerError, err)
 			}
-			return admission.ValidationResponse(allowed, reason)
+			return admission.ValidationResponse(allowed, reason).WithWarnings(patchTargetVersionWarningsV1alpha1(obj)...)
 
 		case admissionv1.Update:
 			if err := h.Decoder.Decode(req, obj); err != nil {
@@ -139,8 +166,262 @@ func (h *DaemonSetCreateUpdateHandler) Handle(ctx context.Context, req admission
 			if allErrs := h.validateDaemonSetUpdate(obj, oldObj); len(allErrs) > 0 {
 				return admission.Errored(http.StatusUnprocessableEntity, allErrs.ToAggregate())
 			}
+			return admission.ValidationResponse(true, "").WithWarnings(patchTargetVersionWarningsV1alpha1(obj)...)
 		}
 		return admission.ValidationResponse(true, "")
 	}
 	return admission.Errored(http.StatusBadRequest, fmt.Errorf("unsupported version: %s", req.AdmissionRequest.Resource.Version))
 }
+
+// warningsV1beta1 returns the warnings of patches unsupported on the target versions and of the rendered templates.
+func (h *DaemonSetCreateUpdateHandler) warningsV1beta1(ctx context.Context, req admission.Request, ds *appsv1beta1.DaemonSet) []string {
+	rawPatches := make([][]byte, len(ds.Spec.Patches))
+	for i := range ds.Spec.Patches {
+		rawPatches[i] = decodedPatch(ds.Spec.Patches[i].Patch.Raw, ds.Spec.Patches[i].PatchEncoding)
+	}
+	warnings := patchTargetVersionWarnings(ds.Annotations, rawPatches, field.NewPath("spec", "patches"))
+	warnings = append(warnings, h.dryRunRenderWarnings(ctx, req, ds)...)
+	return append(warnings, h.quotaWarnings(ctx, ds)...)
+}
+
+func patchTargetVersionWarningsV1alpha1(ds *appsv1alpha1.DaemonSet) []string {
+	rawPatches := make([][]byte, len(ds.Spec.Patches))
+	for i := range ds.Spec.Patches {
+		rawPatches[i] = decodedPatch(ds.Spec.Patches[i].Patch.Raw, appsv1beta1.DaemonSetPatchEncodingType(ds.Spec.Patches[i].PatchEncoding))
+	}
+	return patchTargetVersionWarnings(ds.Annotations, rawPatches, field.NewPath("spec", "patches"))
+}
+
+// dryRunRenderWarnings renders the pod template of the DaemonSet for sample nodes in a dry-run request,
+// such as `kubectl apply --dry-run=server`, and returns the errors of the rendered templates as warnings.
+// The validation is the same whether the request is dry-run or not, and it never changes anything in cluster.
+func (h *DaemonSetCreateUpdateHandler) dryRunRenderWarnings(ctx context.Context, req admission.Request, ds *appsv1beta1.DaemonSet) []string {
+	if req.DryRun == nil || !*req.DryRun || h.Client == nil || len(ds.Spec.Patches) == 0 {
+		return nil
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := h.Client.List(ctx, nodeList); err != nil {
+		klog.ErrorS(err, "Failed to list nodes to render daemonset in dry-run", "namespace", ds.Namespace, "name", ds.Name)
+		return []string{fmt.Sprintf("failed to list nodes to render pod template: %v", err)}
+	}
+
+	var warnings []string
+	for _, node := range sampleNodesForPatches(ds, nodeList.Items) {
+		result := renderDaemonSetForNode(ds, node)
+		for _, err := range result.Errors {
+			warnings = append(warnings, fmt.Sprintf("rendered for node %s with patches %v: %v", node.Name, result.MatchedPatches, err))
+		}
+	}
+	return warnings
+}
+
+// quotaWarnings estimates the requests added by the patches, i.e., the number of nodes matched by the patches times
+// the increase of pod requests on them, and warns if it would likely exceed the ResourceQuotas in the namespace.
+// It is best-effort, the nodes that the DaemonSet is not scheduled to are not excluded.
+func (h *DaemonSetCreateUpdateHandler) quotaWarnings(ctx context.Context, ds *appsv1beta1.DaemonSet) []string {
+	if h.Client == nil || len(ds.Spec.Patches) == 0 {
+		return nil
+	}
+
+	quotaList := &corev1.ResourceQuotaList{}
+	if err := h.Client.List(ctx, quotaList, client.InNamespace(ds.Namespace)); err != nil {
+		klog.ErrorS(err, "Failed to list resourcequotas to estimate requests of patches", "namespace", ds.Namespace, "name", ds.Name)
+		return nil
+	}
+	if len(quotaList.Items) == 0 {
+		return nil
+	}
+	nodeList := &corev1.NodeList{}
+	if err := h.Client.List(ctx, nodeList); err != nil {
+		klog.ErrorS(err, "Failed to list nodes to estimate requests of patches", "namespace", ds.Namespace, "name", ds.Name)
+		return nil
+	}
+	delta := patchedRequestsDelta(ds, nodeList.Items)
+	if len(delta) == 0 {
+		return nil
+	}
+
+	resourceNames := make([]string, 0, len(delta))
+	for resourceName := range delta {
+		resourceNames = append(resourceNames, string(resourceName))
+	}
+	sort.Strings(resourceNames)
+	sort.Slice(quotaList.Items, func(i, j int) bool { return quotaList.Items[i].Name < quotaList.Items[j].Name })
+	var warnings []string
+	for i := range quotaList.Items {
+		quota := &quotaList.Items[i]
+		for _, resourceName := range resourceNames {
+			increased := delta[corev1.ResourceName(resourceName)]
+			// the requests are limited by both the standard name and the one with requests prefix
+			for _, quotaName := range []corev1.ResourceName{corev1.ResourceName(resourceName), corev1.ResourceName(corev1.DefaultResourceRequestsPrefix + resourceName)} {
+				hard, ok := quota.Status.Hard[quotaName]
+				if !ok {
+					continue
+				}
+				used := quota.Status.Used[quotaName]
+				total := used.DeepCopy()
+				total.Add(increased)
+				if total.Cmp(hard) > 0 {
+					warnings = append(warnings, fmt.Sprintf("spec.patches: %s increased by %s on the patched nodes would likely exceed ResourceQuota %s, used %s of hard %s",
+						quotaName, increased.String(), quota.Name, used.String(), hard.String()))
+				}
+			}
+		}
+	}
+	return warnings
+}
+
+// patchBlastRadiusAnnotations returns the audit annotation of how many pods would be recreated by the edit of
+// spec.patches, so that reviewers can tell the disruption of a patch edit before it is rolled out.
+// It is best-effort like quotaWarnings, every node is counted as running a daemon pod.
+func (h *DaemonSetCreateUpdateHandler) patchBlastRadiusAnnotations(ctx context.Context, ds, oldDs *appsv1beta1.DaemonSet) map[string]string {
+	if h.Client == nil || apiequality.Semantic.DeepEqual(ds.Spec.Patches, oldDs.Spec.Patches) {
+		return nil
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := h.Client.List(ctx, nodeList); err != nil {
+		klog.ErrorS(err, "Failed to list nodes to count pods recreated by patches", "namespace", ds.Namespace, "name", ds.Name)
+		return nil
+	}
+	return map[string]string{patchRecreatedPodsAnnotation: strconv.Itoa(countPatchRecreatedPods(ds, oldDs, nodeList.Items))}
+}
+
+// countPatchRecreatedPods returns the number of nodes whose pod template rendered with the patches of the new
+// DaemonSet differs from the one rendered with the old patches. Both are rendered on top of the new spec.template, so
+// that only the pods recreated by the edit of patches are counted. The patches applied to new pods only and the
+// shadow ones are ignored as they never recreate the existing pods. The nodes that the daemon pods can not be
+// scheduled to, by the nodeSelector, affinity and tolerations of either DaemonSet, are not counted.
+// The templates are rendered once for each distinct pair of the old and new patches matching the nodes, and reused
+// for the other nodes of the pair, so that the cost does not grow with the number of nodes.
+func countPatchRecreatedPods(ds, oldDs *appsv1beta1.DaemonSet, nodes []corev1.Node) int {
+	ds, oldDs = withPatchesForExistingPods(ds), withPatchesForExistingPods(oldDs)
+	oldDs.Spec.Template = ds.Spec.Template
+	selectors, oldSelectors := patchSelectors(ds), patchSelectors(oldDs)
+	type renderedTemplates struct {
+		template, oldTemplate *corev1.PodTemplateSpec
+		changed               bool
+	}
+	renderedByPatches := make(map[string]*renderedTemplates)
+	var count int
+	for i := range nodes {
+		key, oldKey := matchedPatchesKey(selectors, &nodes[i]), matchedPatchesKey(oldSelectors, &nodes[i])
+		if key == "" && oldKey == "" {
+			continue
+		}
+		pairKey := key + "/" + oldKey
+		rendered, ok := renderedByPatches[pairKey]
+		if !ok {
+			template, err := daemonsetcontroller.RenderPodTemplateForNode(ds, &nodes[i])
+			oldTemplate, oldErr := daemonsetcontroller.RenderPodTemplateForNode(oldDs, &nodes[i])
+			// the pods are not recreated if the new patches fail to render
+			rendered = &renderedTemplates{
+				template:    template,
+				oldTemplate: oldTemplate,
+				changed:     err == nil && (oldErr != nil || !apiequality.Semantic.DeepEqual(template, oldTemplate)),
+			}
+			if oldErr != nil {
+				rendered.oldTemplate = &oldDs.Spec.Template
+			}
+			renderedByPatches[pairKey] = rendered
+		}
+		if !rendered.changed {
+			continue
+		}
+		if !daemonsetcontroller.PodTemplateShouldRunOnNode(rendered.template, &nodes[i]) ||
+			!daemonsetcontroller.PodTemplateShouldRunOnNode(rendered.oldTemplate, &nodes[i]) {
+			continue
+		}
+		count++
+	}
+	return count
+}
+
+// withPatchesForExistingPods returns a copy of the DaemonSet with only the patches applied to the existing pods.
+func withPatchesForExistingPods(ds *appsv1beta1.DaemonSet) *appsv1beta1.DaemonSet {
+	ds = ds.DeepCopy()
+	patches := ds.Spec.Patches[:0]
+	for i := range ds.Spec.Patches {
+		if !ds.Spec.Patches[i].ApplyToNewPodsOnly && !ds.Spec.Patches[i].Shadow {
+			patches = append(patches, ds.Spec.Patches[i])
+		}
+	}
+	ds.Spec.Patches = patches
+	return ds
+}
+
+// patchedRequestsDelta returns the total increase of pod requests by the patches on all the nodes, it renders
+// the pod template once for each distinct set of patches matching the nodes.
+func patchedRequestsDelta(ds *appsv1beta1.DaemonSet, nodes []corev1.Node) corev1.ResourceList {
+	selectors := patchSelectors(ds)
+	baseRequests := resourcehelper.PodRequests(&corev1.Pod{Spec: ds.Spec.Template.Spec}, resourcehelper.PodResourcesOptions{})
+	deltaByPatches := make(map[string]corev1.ResourceList)
+	total := corev1.ResourceList{}
+	for i := range nodes {
+		key := matchedPatchesKey(selectors, &nodes[i])
+		if key == "" {
+			continue
+		}
+		delta, ok := deltaByPatches[key]
+		if !ok {
+			delta = corev1.ResourceList{}
+			if template, err := daemonsetcontroller.RenderPodTemplateForNode(ds, &nodes[i]); err == nil {
+				requests := resourcehelper.PodRequests(&corev1.Pod{Spec: template.Spec}, resourcehelper.PodResourcesOptions{})
+				for resourceName, quantity := range requests {
+					quantity.Sub(baseRequests[resourceName])
+					if quantity.Sign() > 0 {
+						delta[resourceName] = quantity
+					}
+				}
+			}
+			deltaByPatches[key] = delta
+		}
+		for resourceName, quantity := range delta {
+			sum := total[resourceName]
+			sum.Add(quantity)
+			total[resourceName] = sum
+		}
+	}
+	return total
+}
+
+// patchSelectors returns the selectors of spec.patches, nil for the invalid ones.
+func patchSelectors(ds *appsv1beta1.DaemonSet) []labels.Selector {
+	selectors := make([]labels.Selector, len(ds.Spec.Patches))
+	for i := range ds.Spec.Patches {
+		selectors[i], _ = metav1.LabelSelectorAsSelector(ds.Spec.Patches[i].Selector)
+	}
+	return selectors
+}
+
+// matchedPatchesKey returns the indexes of patches matching the node joined by comma, empty if none matches.
+func matchedPatchesKey(selectors []labels.Selector, node *corev1.Node) string {
+	var matched []string
+	for j, selector := range selectors {
+		if selector != nil && selector.Matches(labels.Set(node.Labels)) {
+			matched = append(matched, strconv.Itoa(j))
+		}
+	}
+	return strings.Join(matched, ",")
+}
+
+// sampleNodesForPatches picks one node for each distinct set of patches matching the nodes, so that every rendered
+// pod template is covered, up to maxDryRunSampleNodes nodes.
+func sampleNodesForPatches(ds *appsv1beta1.DaemonSet, nodes []corev1.Node) []*corev1.Node {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	selectors := patchSelectors(ds)
+
+	var sampled []*corev1.Node
+	seen := sets.New[string]()
+	for i := range nodes {
+		if key := matchedPatchesKey(selectors, &nodes[i]); !seen.Has(key) {
+			seen.Insert(key)
+			sampled = append(sampled, &nodes[i])
+			if len(sampled) >= maxDryRunSampleNodes {
+				break
+			}
+		}
+	}
+	return sampled
+}
@@ -19,18 +19,27 @@ package validating
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	daemonsetcontroller "github.com/openkruise/kruise/pkg/controller/daemonset"
+	"github.com/openkruise/kruise/pkg/features"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
 )
 
 func TestDaemonSetCreateUpdateHandler_HandleV1alpha1Create(t *testing.T) {
@@ -537,3 +546,321 @@ func TestDaemonSetCreateUpdateHandler_HandleDecodeError(t *testing.T) {
 		t.Errorf("expected status code %d, got %d", http.StatusBadRequest, resp.Result.Code)
 	}
 }
+
+func TestDaemonSetCreateUpdateHandler_HandleV1beta1DryRun(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.DaemonSetPatches, true)()
+
+	scheme := runtime.NewScheme()
+	_ = appsv1beta1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	nodes := []client.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-b", Labels: map[string]string{"sidecar": "true"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-c", Labels: map[string]string{"sidecar": "true"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-d", Labels: map[string]string{"disk": "ssd"}}},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(nodes...).Build()
+	handler := &DaemonSetCreateUpdateHandler{
+		Client:  fakeClient,
+		Decoder: admission.NewDecoder(scheme),
+	}
+
+	newRequest := func(ds *appsv1beta1.DaemonSet, dryRun bool) admission.Request {
+		dsBytes, _ := json.Marshal(ds)
+		return admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Resource: metav1.GroupVersionResource{
+					Group:    appsv1beta1.GroupVersion.Group,
+					Version:  appsv1beta1.GroupVersion.Version,
+					Resource: "daemonsets",
+				},
+				Object: runtime.RawExtension{Raw: dsBytes},
+				DryRun: ptr.To(dryRun),
+			},
+		}
+	}
+
+	// the sidecar container patched in has no image, which is only found by rendering
+	ds := newDaemonSetWithPatches(
+		appsv1beta1.DaemonSetPatch{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"sidecar": "true"}},
+			Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"sidecar"}]}}`)},
+		},
+		appsv1beta1.DaemonSetPatch{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"disk": "ssd"}},
+			Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"main","image":"main:ssd"}]}}`)},
+		},
+	)
+	resp := handler.Handle(context.Background(), newRequest(ds, true))
+	if !resp.Allowed {
+		t.Fatalf("expected allowed, got denied: %v", resp.Result)
+	}
+	if len(resp.Warnings) == 0 {
+		t.Fatalf("expected warnings of rendered template in dry-run")
+	}
+	for _, warning := range resp.Warnings {
+		if !strings.Contains(warning, "node-b") {
+			t.Fatalf("expected warnings only for the first sample node with sidecar patch, got %v", resp.Warnings)
+		}
+	}
+	if resp = handler.Handle(context.Background(), newRequest(ds, false)); !resp.Allowed || len(resp.Warnings) > 0 {
+		t.Fatalf("expected allowed without warnings if not dry-run, got %v, %v", resp.Result, resp.Warnings)
+	}
+
+	// the patches are validated the same in dry-run
+	ds.Spec.Patches[1].Priority = -1
+	dryRunResp := handler.Handle(context.Background(), newRequest(ds, true))
+	resp = handler.Handle(context.Background(), newRequest(ds, false))
+	if dryRunResp.Allowed || resp.Allowed || dryRunResp.Result.Message != resp.Result.Message {
+		t.Fatalf("expected denied the same in dry-run, got %v and %v", dryRunResp.Result, resp.Result)
+	}
+
+	// nothing is changed in cluster
+	dsList := &appsv1beta1.DaemonSetList{}
+	if err := fakeClient.List(context.Background(), dsList); err != nil || len(dsList.Items) > 0 {
+		t.Fatalf("expected no daemonset created, got %v, %v", dsList.Items, err)
+	}
+	nodeList := &corev1.NodeList{}
+	if err := fakeClient.List(context.Background(), nodeList); err != nil || len(nodeList.Items) != len(nodes) {
+		t.Fatalf("expected nodes unchanged, got %v, %v", nodeList.Items, err)
+	}
+	for i := range nodeList.Items {
+		if nodeList.Items[i].ResourceVersion != "999" {
+			t.Fatalf("expected node %s unchanged, got resourceVersion %s", nodeList.Items[i].Name, nodeList.Items[i].ResourceVersion)
+		}
+	}
+}
+
+func TestDaemonSetCreateUpdateHandler_QuotaWarnings(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.DaemonSetPatches, true)()
+
+	scheme := runtime.NewScheme()
+	_ = appsv1beta1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	newQuota := func(name, hard, used string) *corev1.ResourceQuota {
+		return &corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse(hard)},
+				Used: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse(used)},
+			},
+		}
+	}
+	objects := []client.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-b", Labels: map[string]string{"gpu": "true"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-c", Labels: map[string]string{"gpu": "true"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-d", Labels: map[string]string{"gpu": "true"}}},
+		newQuota("small", "2", "1"),
+		newQuota("large", "10", "1"),
+		&corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Name: "other-namespace", Namespace: "other"},
+			Status: corev1.ResourceQuotaStatus{Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("1")}}},
+	}
+	handler := &DaemonSetCreateUpdateHandler{
+		Client:  fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		Decoder: admission.NewDecoder(scheme),
+	}
+
+	newDaemonSet := func(patchedCPU string) *appsv1beta1.DaemonSet {
+		ds := newDaemonSetWithPatches(appsv1beta1.DaemonSetPatch{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "true"}},
+			Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"main","resources":{"requests":{"cpu":"` + patchedCPU + `"}}}]}}`)},
+		})
+		ds.Spec.Template.Spec.Containers[0].Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}
+		return ds
+	}
+
+	// 3 nodes * (500m - 100m) = 1200m, which exceeds the small quota
+	warnings := handler.quotaWarnings(context.Background(), newDaemonSet("500m"))
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "ResourceQuota small") || !strings.Contains(warnings[0], "1200m") {
+		t.Fatalf("expected warning of the small quota, got %v", warnings)
+	}
+
+	// the warning is returned by admission without denying the request
+	dsBytes, _ := json.Marshal(newDaemonSet("500m"))
+	resp := handler.Handle(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+			Resource: metav1.GroupVersionResource{
+				Group:    appsv1beta1.GroupVersion.Group,
+				Version:  appsv1beta1.GroupVersion.Version,
+				Resource: "daemonsets",
+			},
+			Object: runtime.RawExtension{Raw: dsBytes},
+		},
+	})
+	if !resp.Allowed || len(resp.Warnings) != 1 {
+		t.Fatalf("expected allowed with quota warning, got %v, %v", resp.Result, resp.Warnings)
+	}
+
+	// 3 nodes * (300m - 100m) = 600m fits in both quotas, and lowering requests never warns
+	for _, cpu := range []string{"300m", "50m"} {
+		if warnings := handler.quotaWarnings(context.Background(), newDaemonSet(cpu)); len(warnings) != 0 {
+			t.Fatalf("expected no warnings for patched cpu %s, got %v", cpu, warnings)
+		}
+	}
+}
+
+func TestDaemonSetCreateUpdateHandler_PatchBlastRadius(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.DaemonSetPatches, true)()
+
+	scheme := runtime.NewScheme()
+	_ = appsv1beta1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+
+	objects := []client.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{"zone": "a"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-b", Labels: map[string]string{"zone": "a"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-c", Labels: map[string]string{"zone": "b"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-d", Labels: map[string]string{"zone": "b"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-e"}},
+		// the daemon pods are not scheduled to the nodes with untolerated taints
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-f", Labels: map[string]string{"zone": "a"}},
+			Spec:       corev1.NodeSpec{Taints: []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}},
+		},
+		// the daemon pods are not scheduled to the nodes not matching the nodeSelector of spec.template
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-g", Labels: map[string]string{"zone": "b", "pool": "batch"}}},
+	}
+	handler := &DaemonSetCreateUpdateHandler{
+		Client:  fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build(),
+		Decoder: admission.NewDecoder(scheme),
+	}
+
+	newPatch := func(image string, zones ...string) appsv1beta1.DaemonSetPatch {
+		return appsv1beta1.DaemonSetPatch{
+			Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "zone", Operator: metav1.LabelSelectorOpIn, Values: zones},
+			}},
+			Patch: runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"main","image":"` + image + `"}]}}`)},
+		}
+	}
+	shadowPatch := newPatch("main:v3", "a", "b")
+	shadowPatch.Shadow = true
+	affinity := &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+			MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "pool", Operator: corev1.NodeSelectorOpDoesNotExist}},
+		}}},
+	}}
+
+	tests := []struct {
+		name       string
+		oldPatches []appsv1beta1.DaemonSetPatch
+		patches    []appsv1beta1.DaemonSetPatch
+		expected   string
+	}{
+		{
+			name:     "add patch on both zones",
+			patches:  []appsv1beta1.DaemonSetPatch{newPatch("main:v2", "a", "b")},
+			expected: "4",
+		},
+		{
+			name:     "add patch with selector narrowed to one zone",
+			patches:  []appsv1beta1.DaemonSetPatch{newPatch("main:v2", "a")},
+			expected: "2",
+		},
+		{
+			name:       "change patch on both zones",
+			oldPatches: []appsv1beta1.DaemonSetPatch{newPatch("main:v2", "a", "b")},
+			patches:    []appsv1beta1.DaemonSetPatch{newPatch("main:v3", "a", "b")},
+			expected:   "4",
+		},
+		{
+			name:       "narrow selector of unchanged patch",
+			oldPatches: []appsv1beta1.DaemonSetPatch{newPatch("main:v2", "a", "b")},
+			patches:    []appsv1beta1.DaemonSetPatch{newPatch("main:v2", "a")},
+			expected:   "2",
+		},
+		{
+			name:       "add shadow patch",
+			oldPatches: []appsv1beta1.DaemonSetPatch{newPatch("main:v2", "a", "b")},
+			patches:    []appsv1beta1.DaemonSetPatch{newPatch("main:v2", "a", "b"), shadowPatch},
+			expected:   "0",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			oldDs := newDaemonSetWithPatches(tc.oldPatches...)
+			oldDs.ResourceVersion = "1"
+			oldDs.Spec.Template.Spec.Affinity = affinity
+			ds := newDaemonSetWithPatches(tc.patches...)
+			ds.ResourceVersion = "2"
+			ds.Spec.Template.Spec.Affinity = affinity
+			dsBytes, _ := json.Marshal(ds)
+			oldDsBytes, _ := json.Marshal(oldDs)
+			resp := handler.Handle(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Update,
+					Resource: metav1.GroupVersionResource{
+						Group:    appsv1beta1.GroupVersion.Group,
+						Version:  appsv1beta1.GroupVersion.Version,
+						Resource: "daemonsets",
+					},
+					Object:    runtime.RawExtension{Raw: dsBytes},
+					OldObject: runtime.RawExtension{Raw: oldDsBytes},
+				},
+			})
+			if !resp.Allowed {
+				t.Fatalf("expected allowed, got denied: %v", resp.Result)
+			}
+			if got := resp.AuditAnnotations[patchRecreatedPodsAnnotation]; got != tc.expected {
+				t.Fatalf("expected %s pods recreated, got %q", tc.expected, got)
+			}
+		})
+	}
+
+	// no annotation if the patches are unchanged
+	ds := newDaemonSetWithPatches(newPatch("main:v2", "a"))
+	if annotations := handler.patchBlastRadiusAnnotations(context.Background(), ds, ds.DeepCopy()); annotations != nil {
+		t.Fatalf("expected no annotations for unchanged patches, got %v", annotations)
+	}
+}
+
+type countingTemplateRenderer struct {
+	renders int
+}
+
+func (r *countingTemplateRenderer) ID() string {
+	return "counting/v1"
+}
+
+func (r *countingTemplateRenderer) Render(_ *corev1.Node, template *corev1.PodTemplateSpec) (*corev1.PodTemplateSpec, error) {
+	r.renders++
+	return template, nil
+}
+
+func TestCountPatchRecreatedPodsRendersOncePerPatches(t *testing.T) {
+	renderer := &countingTemplateRenderer{}
+	daemonsetcontroller.RegisterTemplateRenderer(renderer)
+	defer daemonsetcontroller.RegisterTemplateRenderer(nil)
+
+	var nodes []corev1.Node
+	for i := 0; i < 100; i++ {
+		zone := "a"
+		if i%2 == 1 {
+			zone = "b"
+		}
+		nodes = append(nodes, corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("node-%d", i), Labels: map[string]string{"zone": zone}}})
+	}
+	newPatch := func(image string, zones ...string) appsv1beta1.DaemonSetPatch {
+		return appsv1beta1.DaemonSetPatch{
+			Selector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "zone", Operator: metav1.LabelSelectorOpIn, Values: zones},
+			}},
+			Patch: runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"main","image":"` + image + `"}]}}`)},
+		}
+	}
+	oldDs := newDaemonSetWithPatches(newPatch("main:v2", "a", "b"))
+	ds := newDaemonSetWithPatches(newPatch("main:v3", "a"), newPatch("main:v2", "b"))
+
+	if count := countPatchRecreatedPods(ds, oldDs, nodes); count != 50 {
+		t.Fatalf("expected 50 pods recreated, got %d", count)
+	}
+	// the old and new templates are rendered for each of the 2 distinct pairs of patches
+	if renderer.renders != 4 {
+		t.Fatalf("expected 4 renders, got %d", renderer.renders)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	corevalidation "k8s.io/kubernetes/pkg/apis/core/validation"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	daemonsetcontroller "github.com/openkruise/kruise/pkg/controller/daemonset"
+	webhookutil "github.com/openkruise/kruise/pkg/webhook/util"
+	"github.com/openkruise/kruise/pkg/webhook/util/convertor"
+)
+
+// DaemonSetRenderResult is the pod template of a DaemonSet rendered for a sample node.
+type DaemonSetRenderResult struct {
+	// NodeName is the name of the sample node.
+	NodeName string
+	// MatchedPatches are the indexes in spec.patches of the patches matching the node.
+	MatchedPatches []int
+	// PatchMatches explain whether the node matches each of spec.patches, and why not.
+	PatchMatches []daemonsetcontroller.PatchMatchResult
+	// Template is the pod template with the matched patches applied, nil if it fails to render.
+	Template *corev1.PodTemplateSpec
+	// Errors are the errors of rendering or validating the rendered template.
+	Errors field.ErrorList
+}
+
+// ValidateDaemonSetWithNodes validates the DaemonSet without a cluster, such as in CI.
+// It returns the same errors as the admission webhook does when the DaemonSet is created,
+// and the pod templates rendered for each of the optional sample nodes.
+// Note that patches are only allowed when feature-gate DaemonSetPatches is enabled, same as the webhook.
+func ValidateDaemonSetWithNodes(ds *appsv1beta1.DaemonSet, nodes []*corev1.Node) (field.ErrorList, []DaemonSetRenderResult) {
+	allErrs := validateDaemonSetV1beta1(ds)
+	if len(allErrs) > 0 || len(nodes) == 0 {
+		return allErrs, nil
+	}
+
+	results := make([]DaemonSetRenderResult, 0, len(nodes))
+	for _, node := range nodes {
+		results = append(results, renderDaemonSetForNode(ds, node))
+	}
+	return allErrs, results
+}
+
+func renderDaemonSetForNode(ds *appsv1beta1.DaemonSet, node *corev1.Node) DaemonSetRenderResult {
+	result := DaemonSetRenderResult{NodeName: node.Name}
+	fldPath := field.NewPath("spec", "patches")
+	result.PatchMatches = make([]daemonsetcontroller.PatchMatchResult, len(ds.Spec.Patches))
+	for i := range ds.Spec.Patches {
+		result.PatchMatches[i] = daemonsetcontroller.ExplainMatch(&ds.Spec.Patches[i], node)
+		if result.PatchMatches[i].Matched {
+			result.MatchedPatches = append(result.MatchedPatches, i)
+		}
+	}
+
+	template, err := daemonsetcontroller.RenderPodTemplateForNode(ds, node)
+	if err != nil {
+		result.Errors = append(result.Errors, field.Invalid(fldPath, node.Name, fmt.Sprintf("failed to render template for node: %v", err)))
+		return result
+	}
+	result.Template = template
+
+	coreTemplate, err := convertor.ConvertPodTemplateSpec(template)
+	if err != nil {
+		result.Errors = append(result.Errors, field.Invalid(fldPath, node.Name, fmt.Sprintf("Convert_v1_PodTemplateSpec_To_core_PodTemplateSpec failed: %v", err)))
+		return result
+	}
+	result.Errors = append(result.Errors, corevalidation.ValidatePodTemplateSpec(coreTemplate, field.NewPath("spec", "template"), webhookutil.DefaultPodValidationOptions)...)
+	return result
+}
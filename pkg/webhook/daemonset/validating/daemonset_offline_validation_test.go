@@ -0,0 +1,203 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+)
+
+func newDaemonSetWithPatches(patches ...appsv1beta1.DaemonSetPatch) *appsv1beta1.DaemonSet {
+	maxUnavailable := intstr.FromInt32(1)
+	return &appsv1beta1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ds", Namespace: "default"},
+		Spec: appsv1beta1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyAlways,
+					DNSPolicy:     corev1.DNSClusterFirst,
+					Containers: []corev1.Container{{
+						Name:                     "main",
+						Image:                    "main:v1",
+						ImagePullPolicy:          corev1.PullIfNotPresent,
+						TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+					}},
+				},
+			},
+			UpdateStrategy: appsv1beta1.DaemonSetUpdateStrategy{
+				Type:          appsv1beta1.RollingUpdateDaemonSetStrategyType,
+				RollingUpdate: &appsv1beta1.RollingUpdateDaemonSet{MaxUnavailable: &maxUnavailable},
+			},
+			Patches: patches,
+		},
+	}
+}
+
+func TestValidateDaemonSetWithNodesSameAsAdmission(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = appsv1beta1.AddToScheme(scheme)
+	handler := &DaemonSetCreateUpdateHandler{Decoder: admission.NewDecoder(scheme)}
+
+	tests := []struct {
+		name    string
+		patches []appsv1beta1.DaemonSetPatch
+	}{
+		{
+			name: "valid patch",
+			patches: []appsv1beta1.DaemonSetPatch{{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"disk": "ssd"}},
+				Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"main","image":"main:ssd"}]}}`)},
+			}},
+		},
+		{
+			name: "patch without selector and negative priority",
+			patches: []appsv1beta1.DaemonSetPatch{{
+				Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"main","image":"main:ssd"}]}}`)},
+				Priority: -1,
+			}},
+		},
+		{
+			name: "patch with invalid strategic merge patch",
+			patches: []appsv1beta1.DaemonSetPatch{{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"disk": "ssd"}},
+				Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"containers":"main"}}`)},
+			}},
+		},
+		{
+			name: "patch with invalid topologySpreadConstraints",
+			patches: []appsv1beta1.DaemonSetPatch{{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"disk": "ssd"}},
+				Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"topologySpreadConstraints":[{"maxSkew":0,"topologyKey":"zone","whenUnsatisfiable":"DoNotSchedule"}]}}`)},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := newDaemonSetWithPatches(tt.patches...)
+			allErrs, _ := ValidateDaemonSetWithNodes(ds, nil)
+
+			dsBytes, _ := json.Marshal(ds)
+			resp := handler.Handle(context.Background(), admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Create,
+					Resource: metav1.GroupVersionResource{
+						Group:    appsv1beta1.GroupVersion.Group,
+						Version:  appsv1beta1.GroupVersion.Version,
+						Resource: "daemonsets",
+					},
+					Object: runtime.RawExtension{Raw: dsBytes},
+				},
+			})
+
+			if resp.Allowed != (len(allErrs) == 0) {
+				t.Fatalf("expected admission allowed %v, got %v: %v", len(allErrs) == 0, resp.Allowed, resp.Result)
+			}
+			if len(allErrs) > 0 && resp.Result.Message != allErrs.ToAggregate().Error() {
+				t.Fatalf("expected the same errors as admission %q, got %q", resp.Result.Message, allErrs.ToAggregate().Error())
+			}
+		})
+	}
+}
+
+func TestValidateDaemonSetWithNodesRender(t *testing.T) {
+	ds := newDaemonSetWithPatches(
+		appsv1beta1.DaemonSetPatch{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"disk": "ssd"}},
+			Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"main","image":"main:ssd"}]}}`)},
+		},
+		appsv1beta1.DaemonSetPatch{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"gpu": "true"}},
+			Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"main","image":"main:gpu"}]}}`)},
+			Priority: 1,
+		},
+		appsv1beta1.DaemonSetPatch{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"sidecar": "true"}},
+			Patch:    runtime.RawExtension{Raw: []byte(`{"spec":{"containers":[{"name":"sidecar"}]}}`)},
+		},
+	)
+	nodes := []*corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "plain"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ssd-gpu", Labels: map[string]string{"disk": "ssd", "gpu": "true"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "sidecar", Labels: map[string]string{"sidecar": "true"}}},
+	}
+
+	allErrs, results := ValidateDaemonSetWithNodes(ds, nodes)
+	if len(allErrs) > 0 {
+		t.Fatalf("expected no errors, got %v", allErrs)
+	}
+	if len(results) != len(nodes) {
+		t.Fatalf("expected %d render results, got %d", len(nodes), len(results))
+	}
+
+	expectations := []struct {
+		matchedPatches []int
+		image          string
+		hasErrors      bool
+	}{
+		{image: "main:v1"},
+		{matchedPatches: []int{0, 1}, image: "main:gpu"},
+		// the sidecar container patched in has no image, which fails to render
+		{matchedPatches: []int{2}, hasErrors: true},
+	}
+	if explained := results[0].PatchMatches[0]; explained.FailedRequirement != "disk=ssd" || explained.Reason != "node has no label disk" {
+		t.Fatalf("expected explanation of the ssd patch on the plain node, got %+v", explained)
+	}
+	for i, expected := range expectations {
+		result := results[i]
+		if result.NodeName != nodes[i].Name {
+			t.Fatalf("expected result of node %s, got %s", nodes[i].Name, result.NodeName)
+		}
+		if !reflect.DeepEqual(result.MatchedPatches, expected.matchedPatches) {
+			t.Fatalf("node %s: expected matched patches %v, got %v", result.NodeName, expected.matchedPatches, result.MatchedPatches)
+		}
+		if len(result.PatchMatches) != len(ds.Spec.Patches) {
+			t.Fatalf("node %s: expected explanations of %d patches, got %d", result.NodeName, len(ds.Spec.Patches), len(result.PatchMatches))
+		}
+		if (len(result.Errors) > 0) != expected.hasErrors {
+			t.Fatalf("node %s: expected errors %v, got %v", result.NodeName, expected.hasErrors, result.Errors)
+		}
+		if result.Template == nil {
+			if expected.image == "" {
+				continue
+			}
+			t.Fatalf("node %s: expected rendered template", result.NodeName)
+		}
+		var image string
+		for _, c := range result.Template.Spec.Containers {
+			if c.Name == "main" {
+				image = c.Image
+			}
+		}
+		if image != expected.image {
+			t.Fatalf("node %s: expected image %s, got %s", result.NodeName, expected.image, image)
+		}
+	}
+}
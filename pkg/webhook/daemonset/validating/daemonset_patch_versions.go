@@ -0,0 +1,150 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+)
+
+// patchFieldVersion is a field of pod template with the Kubernetes version since which it is supported.
+// The path is relative to the pod template, and "[*]" matches every element of a list.
+type patchFieldVersion struct {
+	path    string
+	version *utilversion.Version
+}
+
+// patchFieldVersions is the best-effort table of the pod fields added in recent Kubernetes versions,
+// which are unknown to the kubelets of older versions. The version is when the field was introduced.
+var patchFieldVersions = func() []patchFieldVersion {
+	podFields := map[string]string{
+		"spec.os":                                              "v1.23",
+		"spec.hostUsers":                                       "v1.25",
+		"spec.schedulingGates":                                 "v1.26",
+		"spec.resourceClaims":                                  "v1.26",
+		"spec.securityContext.appArmorProfile":                 "v1.30",
+		"spec.securityContext.supplementalGroupsPolicy":        "v1.31",
+		"spec.volumes[*].image":                                "v1.31",
+		"spec.resources":                                       "v1.32",
+		"spec.topologySpreadConstraints[*].minDomains":         "v1.24",
+		"spec.topologySpreadConstraints[*].matchLabelKeys":     "v1.25",
+		"spec.topologySpreadConstraints[*].nodeAffinityPolicy": "v1.25",
+		"spec.topologySpreadConstraints[*].nodeTaintsPolicy":   "v1.25",
+	}
+	containerFields := map[string]string{
+		"resources.claims":                  "v1.26",
+		"resizePolicy":                      "v1.27",
+		"restartPolicy":                     "v1.28",
+		"lifecycle.preStop.sleep":           "v1.29",
+		"lifecycle.postStart.sleep":         "v1.29",
+		"securityContext.appArmorProfile":   "v1.30",
+		"volumeMounts[*].recursiveReadOnly": "v1.30",
+		"lifecycle.stopSignal":              "v1.33",
+	}
+	for path, version := range containerFields {
+		podFields["spec.containers[*]."+path] = version
+		podFields["spec.initContainers[*]."+path] = version
+	}
+
+	fields := make([]patchFieldVersion, 0, len(podFields))
+	for path, version := range podFields {
+		fields = append(fields, patchFieldVersion{path: path, version: utilversion.MustParseGeneric(version)})
+	}
+	return fields
+}()
+
+// patchTargetVersionWarnings returns the warnings of the fields in patches which are not supported on the oldest
+// Kubernetes version in the DaemonSetPatchTargetVersionsAnnotation. It is best-effort and never rejects the request.
+func patchTargetVersionWarnings(annotations map[string]string, rawPatches [][]byte, fldPath *field.Path) []string {
+	value, ok := annotations[appsv1beta1.DaemonSetPatchTargetVersionsAnnotation]
+	if !ok || len(rawPatches) == 0 {
+		return nil
+	}
+
+	var oldest *utilversion.Version
+	for _, str := range strings.Split(value, ",") {
+		if str = strings.TrimSpace(str); str == "" {
+			continue
+		}
+		v, err := utilversion.ParseGeneric(str)
+		if err != nil {
+			return []string{fmt.Sprintf("ignored invalid version %q in annotation %s: %v", str, appsv1beta1.DaemonSetPatchTargetVersionsAnnotation, err)}
+		}
+		if oldest == nil || v.LessThan(oldest) {
+			oldest = v
+		}
+	}
+	if oldest == nil {
+		return nil
+	}
+
+	var warnings []string
+	for i, raw := range rawPatches {
+		var patch interface{}
+		if err := json.Unmarshal(raw, &patch); err != nil {
+			continue
+		}
+		var unsupported []string
+		for _, f := range patchFieldVersions {
+			if oldest.LessThan(f.version) && hasJSONPath(patch, strings.Split(f.path, ".")) {
+				unsupported = append(unsupported, fmt.Sprintf("%s (since v%s)", f.path, f.version.String()))
+			}
+		}
+		if len(unsupported) == 0 {
+			continue
+		}
+		// the table is built from maps, sort to keep the warnings stable
+		sort.Strings(unsupported)
+		warnings = append(warnings, fmt.Sprintf("%s: fields not supported on the oldest target version v%s: %s",
+			fldPath.Index(i).Child("patch").String(), oldest.String(), strings.Join(unsupported, ", ")))
+	}
+	return warnings
+}
+
+// hasJSONPath returns whether the path exists in the decoded JSON object, where "[*]" in a segment matches
+// any element of the list.
+func hasJSONPath(obj interface{}, segments []string) bool {
+	if len(segments) == 0 {
+		return true
+	}
+	m, ok := obj.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	key, isList := strings.CutSuffix(segments[0], "[*]")
+	value, ok := m[key]
+	if !ok {
+		return false
+	}
+	if !isList {
+		return hasJSONPath(value, segments[1:])
+	}
+	items, _ := value.([]interface{})
+	for _, item := range items {
+		if hasJSONPath(item, segments[1:]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+)
+
+func TestPatchTargetVersionWarnings(t *testing.T) {
+	patches := [][]byte{
+		[]byte(`{"spec":{"containers":[{"name":"main"},{"name":"agent","resizePolicy":[{"resourceName":"cpu","restartPolicy":"NotRequired"}]}]}}`),
+		[]byte(`{"spec":{"schedulingGates":[{"name":"gate"}],"nodeSelector":{"disk":"ssd"}}}`),
+	}
+
+	tests := []struct {
+		name             string
+		targetVersions   *string
+		expectedWarnings []string
+	}{
+		{
+			name: "no target versions",
+		},
+		{
+			name:           "all target versions support the fields",
+			targetVersions: ptr.To("v1.30, v1.28"),
+		},
+		{
+			name:           "field warned on the oldest version",
+			targetVersions: ptr.To("v1.30,v1.26.3"),
+			expectedWarnings: []string{
+				"spec.patches[0].patch: fields not supported on the oldest target version v1.26.3: spec.containers[*].resizePolicy (since v1.27)",
+			},
+		},
+		{
+			name:           "fields warned on an older version",
+			targetVersions: ptr.To("1.25"),
+			expectedWarnings: []string{
+				"spec.patches[0].patch: fields not supported on the oldest target version v1.25: spec.containers[*].resizePolicy (since v1.27)",
+				"spec.patches[1].patch: fields not supported on the oldest target version v1.25: spec.schedulingGates (since v1.26)",
+			},
+		},
+		{
+			name:           "invalid target version",
+			targetVersions: ptr.To("v1.30,latest"),
+			expectedWarnings: []string{
+				`ignored invalid version "latest" in annotation ` + appsv1beta1.DaemonSetPatchTargetVersionsAnnotation,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := map[string]string{}
+			if tt.targetVersions != nil {
+				annotations[appsv1beta1.DaemonSetPatchTargetVersionsAnnotation] = *tt.targetVersions
+			}
+			warnings := patchTargetVersionWarnings(annotations, patches, field.NewPath("spec", "patches"))
+			if len(warnings) != len(tt.expectedWarnings) {
+				t.Fatalf("expected warnings %v, got %v", tt.expectedWarnings, warnings)
+			}
+			for i := range warnings {
+				if !strings.HasPrefix(warnings[i], tt.expectedWarnings[i]) {
+					t.Fatalf("expected warning %q, got %q", tt.expectedWarnings[i], warnings[i])
+				}
+			}
+		})
+	}
+}
+
+func TestHasJSONPath(t *testing.T) {
+	var obj interface{} = map[string]interface{}{
+		"spec": map[string]interface{}{
+			"initContainers": []interface{}{
+				map[string]interface{}{"name": "init"},
+				map[string]interface{}{"name": "sidecar", "restartPolicy": "Always"},
+			},
+		},
+	}
+	if !hasJSONPath(obj, strings.Split("spec.initContainers[*].restartPolicy", ".")) {
+		t.Fatalf("expected restartPolicy found in initContainers")
+	}
+	if hasJSONPath(obj, strings.Split("spec.containers[*].restartPolicy", ".")) {
+		t.Fatalf("expected restartPolicy not found in containers")
+	}
+	if hasJSONPath(obj, strings.Split("spec.initContainers[*].name.value", ".")) {
+		t.Fatalf("expected no path under a string")
+	}
+}
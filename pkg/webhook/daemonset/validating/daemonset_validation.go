@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	extensions "k8s.io/api/extensions/v1beta1"
 	genericvalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	metavalidation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
@@ -16,10 +18,16 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	appsvalidation "k8s.io/kubernetes/pkg/apis/apps/validation"
+	"k8s.io/kubernetes/pkg/apis/core"
+	corehelper "k8s.io/kubernetes/pkg/apis/core/helper"
 	corevalidation "k8s.io/kubernetes/pkg/apis/core/validation"
 
 	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	daemonsetcontroller "github.com/openkruise/kruise/pkg/controller/daemonset"
+	"github.com/openkruise/kruise/pkg/features"
+	"github.com/openkruise/kruise/pkg/util"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
 	webhookutil "github.com/openkruise/kruise/pkg/webhook/util"
 	"github.com/openkruise/kruise/pkg/webhook/util/convertor"
 )
@@ -80,65 +88,44 @@ func validateDaemonSetSpec(spec *appsv1alpha1.DaemonSetSpec, fldPath *field.Path
 		}
 	}
 
-	// Validate patches
-	allErrs = append(allErrs, validateDaemonSetPatchesV1alpha1(spec.Patches, fldPath.Child("patches"))...)
-	return allErrs
-}
-
-// validateDaemonSetPatchesV1alpha1 validates the patches configuration for v1alpha1
-func validateDaemonSetPatchesV1alpha1(patches []appsv1alpha1.DaemonSetPatch, fldPath *field.Path) field.ErrorList {
-	allErrs := field.ErrorList{}
-
-	if len(patches) > 10 {
-		allErrs = append(allErrs, field.TooMany(fldPath, len(patches), 10))
+	// Validate patches, which are the same as v1beta1
+	patches := convertPatchesToV1beta1(spec.Patches)
+	allErrs = append(allErrs, validateDaemonSetPatches(patches, fldPath.Child("patches"))...)
+	switch spec.PatchOrderBy {
+	case "", appsv1alpha1.PriorityDaemonSetPatchOrderBy, appsv1alpha1.OrderDaemonSetPatchOrderBy:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("patchOrderBy"), spec.PatchOrderBy,
+			[]string{string(appsv1alpha1.PriorityDaemonSetPatchOrderBy), string(appsv1alpha1.OrderDaemonSetPatchOrderBy)}))
 	}
-
-	for i, patch := range patches {
-		patchPath := fldPath.Index(i)
-		allErrs = append(allErrs, validateDaemonSetPatchV1alpha1(&patch, patchPath)...)
+	if len(allErrs) == 0 {
+		allErrs = append(allErrs, validatePatchedTemplates(&spec.Template, patches, fldPath.Child("patches"))...)
 	}
-
 	return allErrs
 }
 
-// validateDaemonSetPatchV1alpha1 validates a single patch configuration for v1alpha1
-func validateDaemonSetPatchV1alpha1(patch *appsv1alpha1.DaemonSetPatch, fldPath *field.Path) field.ErrorList {
-	allErrs := field.ErrorList{}
-
-	if patch.Selector == nil {
-		allErrs = append(allErrs, field.Required(fldPath.Child("selector"), "selector is required"))
-	} else {
-		allErrs = append(allErrs, metavalidation.ValidateLabelSelector(patch.Selector, metavalidation.LabelSelectorValidationOptions{}, fldPath.Child("selector"))...)
-	}
-
-	if len(patch.Patch.Raw) == 0 {
-		allErrs = append(allErrs, field.Required(fldPath.Child("patch"), "patch is required"))
-	} else {
-		// Validate patch is valid JSON
-		var patchJSON interface{}
-		if err := json.Unmarshal(patch.Patch.Raw, &patchJSON); err != nil {
-			allErrs = append(allErrs, field.Invalid(fldPath.Child("patch"), string(patch.Patch.Raw), fmt.Sprintf("invalid JSON: %v", err)))
-		}
-
-		// Validate patch is a valid strategic merge patch for PodTemplateSpec
-		dummyTemplate := &corev1.PodTemplateSpec{
-			Spec: corev1.PodSpec{
-				Containers: []corev1.Container{{Name: "dummy"}},
-			},
-		}
-		dummyJSON, _ := json.Marshal(dummyTemplate)
-		_, err := strategicpatch.StrategicMergePatch(dummyJSON, patch.Patch.Raw, &corev1.PodTemplateSpec{})
-		if err != nil {
-			allErrs = append(allErrs, field.Invalid(fldPath.Child("patch"), string(patch.Patch.Raw), fmt.Sprintf("invalid strategic merge patch: %v", err)))
+// convertPatchesToV1beta1 converts the patches of v1alpha1 to v1beta1, whose fields are the same.
+func convertPatchesToV1beta1(patches []appsv1alpha1.DaemonSetPatch) []appsv1beta1.DaemonSetPatch {
+	if patches == nil {
+		return nil
+	}
+	out := make([]appsv1beta1.DaemonSetPatch, len(patches))
+	for i := range patches {
+		out[i] = appsv1beta1.DaemonSetPatch{
+			Selector:           patches[i].Selector,
+			Patch:              patches[i].Patch,
+			PatchEncoding:      appsv1beta1.DaemonSetPatchEncodingType(patches[i].PatchEncoding),
+			Priority:           patches[i].Priority,
+			Order:              patches[i].Order,
+			ApplyToNewPodsOnly: patches[i].ApplyToNewPodsOnly,
+			Shadow:             patches[i].Shadow,
+			UpdateStrategyType: appsv1beta1.DaemonSetUpdateStrategyType(patches[i].UpdateStrategyType),
+			Description:        patches[i].Description,
+			Owner:              patches[i].Owner,
 		}
 	}
-
-	if patch.Priority < 0 {
-		allErrs = append(allErrs, field.Invalid(fldPath.Child("priority"), patch.Priority, "priority must be non-negative"))
-	}
-
-	return allErrs
+	return out
 }
+
 func validateDaemonSetUpdateStrategy(strategy *appsv1alpha1.DaemonSetUpdateStrategy, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	switch strategy.Type {
@@ -290,13 +277,149 @@ func validateDaemonSetSpecV1beta1(spec *appsv1beta1.DaemonSetSpec, fldPath *fiel
 
 	// Validate patches
 	allErrs = append(allErrs, validateDaemonSetPatches(spec.Patches, fldPath.Child("patches"))...)
+	switch spec.PatchOrderBy {
+	case "", appsv1beta1.PriorityDaemonSetPatchOrderBy, appsv1beta1.OrderDaemonSetPatchOrderBy:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("patchOrderBy"), spec.PatchOrderBy,
+			[]string{string(appsv1beta1.PriorityDaemonSetPatchOrderBy), string(appsv1beta1.OrderDaemonSetPatchOrderBy)}))
+	}
+	if len(allErrs) == 0 {
+		allErrs = append(allErrs, validatePatchedTemplates(&spec.Template, spec.Patches, fldPath.Child("patches"))...)
+	}
 	return allErrs
 }
 
+// validatePatchedTemplates validates the pod template patched by each patch, and reports the errors of the fields set
+// by the patch whose values after strategic merge may be invalid even if both the template and the patch look fine
+// separately. Note that topologySpreadConstraints and hostAliases are merged by topologyKey and ip, i.e., the items
+// of the same key are merged, and probes are merged field by field, so a patch setting another handler type results
+// in a probe with more than one handler unless it replaces the whole probe.
+func validatePatchedTemplates(template *corev1.PodTemplateSpec, patches []appsv1beta1.DaemonSetPatch, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	templateJSON, err := util.CanonicalJSON(template)
+	if err != nil {
+		return allErrs
+	}
+
+	for i := range patches {
+		patch := decodedPatch(patches[i].Patch.Raw, patches[i].PatchEncoding)
+		patchPath := fldPath.Index(i).Child("patch")
+		isPatchedField := patchedFieldMatcher(patch, patchPath)
+		if isPatchedField == nil {
+			continue
+		}
+
+		coreTemplate, err := patchedCorePodTemplate(templateJSON, patch)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(patchPath, string(patch), err.Error()))
+			continue
+		}
+		for _, err := range corevalidation.ValidatePodTemplateSpec(coreTemplate, patchPath, webhookutil.DefaultPodValidationOptions) {
+			if isPatchedField(err.Field) {
+				allErrs = append(allErrs, err)
+			}
+		}
+	}
+	return allErrs
+}
+
+// patchedFieldMatcher returns a function that matches the paths of the fields to validate after the patch is applied,
+// or nil if the patch sets none of them.
+func patchedFieldMatcher(patch []byte, patchPath *field.Path) func(string) bool {
+	var patchSpec struct {
+		Spec struct {
+			TopologySpreadConstraints json.RawMessage `json:"topologySpreadConstraints"`
+			HostAliases               json.RawMessage `json:"hostAliases"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(patch, &patchSpec); err != nil {
+		return nil
+	}
+	var prefixes []string
+	if patchSpec.Spec.TopologySpreadConstraints != nil {
+		prefixes = append(prefixes, patchPath.Child("spec", "topologySpreadConstraints").String())
+	}
+	if patchSpec.Spec.HostAliases != nil {
+		prefixes = append(prefixes, patchPath.Child("spec", "hostAliases").String())
+	}
+	probes := patchTouchesProbes(patch)
+	if len(prefixes) == 0 && !probes {
+		return nil
+	}
+
+	containersPath := patchPath.Child("spec", "containers").String()
+	initContainersPath := patchPath.Child("spec", "initContainers").String()
+	return func(path string) bool {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		}
+		return probes && (strings.HasPrefix(path, containersPath) || strings.HasPrefix(path, initContainersPath)) &&
+			strings.Contains(path, "Probe")
+	}
+}
+
+// patchTouchesProbes returns whether the patch sets any probe of the containers or init containers.
+func patchTouchesProbes(patch []byte) bool {
+	type probes struct {
+		StartupProbe   json.RawMessage `json:"startupProbe"`
+		LivenessProbe  json.RawMessage `json:"livenessProbe"`
+		ReadinessProbe json.RawMessage `json:"readinessProbe"`
+	}
+	var patchSpec struct {
+		Spec struct {
+			Containers     []probes `json:"containers"`
+			InitContainers []probes `json:"initContainers"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(patch, &patchSpec); err != nil {
+		return false
+	}
+	for _, c := range append(patchSpec.Spec.InitContainers, patchSpec.Spec.Containers...) {
+		if c.StartupProbe != nil || c.LivenessProbe != nil || c.ReadinessProbe != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// patchedCorePodTemplate applies the patch to the pod template in JSON, and converts the result to
+// the internal PodTemplateSpec for the validation of core.
+func patchedCorePodTemplate(templateJSON, patch []byte) (*core.PodTemplateSpec, error) {
+	patchedJSON, err := strategicpatch.StrategicMergePatch(templateJSON, patch, &corev1.PodTemplateSpec{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch template: %v", err)
+	}
+	patchedTemplate := &corev1.PodTemplateSpec{}
+	if err := json.Unmarshal(patchedJSON, patchedTemplate); err != nil {
+		return nil, fmt.Errorf("failed to patch template: %v", err)
+	}
+	coreTemplate, err := convertor.ConvertPodTemplateSpec(patchedTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("Convert_v1_PodTemplateSpec_To_core_PodTemplateSpec failed: %v", err)
+	}
+	return coreTemplate, nil
+}
+
+// decodedPatch returns the JSON of the patch, or the patch itself if it fails to be decoded,
+// which is reported by the validation of the patch.
+func decodedPatch(raw []byte, encoding appsv1beta1.DaemonSetPatchEncodingType) []byte {
+	if patchJSON, err := daemonsetcontroller.DecodePatchWithEncoding(raw, encoding); err == nil {
+		return patchJSON
+	}
+	return raw
+}
+
 // validateDaemonSetPatches validates the patches configuration
 func validateDaemonSetPatches(patches []appsv1beta1.DaemonSetPatch, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
+	if len(patches) > 0 && !utilfeature.DefaultFeatureGate.Enabled(features.DaemonSetPatches) {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "patches is not allowed when feature-gate DaemonSetPatches is disabled"))
+		return allErrs
+	}
+
 	if len(patches) > 10 {
 		allErrs = append(allErrs, field.TooMany(fldPath, len(patches), 10))
 	}
@@ -322,10 +445,14 @@ func validateDaemonSetPatch(patch *appsv1beta1.DaemonSetPatch, fldPath *field.Pa
 	if len(patch.Patch.Raw) == 0 {
 		allErrs = append(allErrs, field.Required(fldPath.Child("patch"), "patch is required"))
 	} else {
-		// Validate patch is valid JSON
-		var patchJSON interface{}
-		if err := json.Unmarshal(patch.Patch.Raw, &patchJSON); err != nil {
-			allErrs = append(allErrs, field.Invalid(fldPath.Child("patch"), string(patch.Patch.Raw), fmt.Sprintf("invalid JSON: %v", err)))
+		// Validate patch is valid JSON or YAML, and use the canonical bytes the same as rendering
+		patchJSON, err := daemonsetcontroller.DecodePatchWithEncoding(patch.Patch.Raw, patch.PatchEncoding)
+		if err == nil {
+			patchJSON, err = util.CanonicalJSON(patchJSON)
+		}
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("patch"), string(patch.Patch.Raw), fmt.Sprintf("invalid JSON or YAML: %v", err)))
+			patchJSON = patch.Patch.Raw
 		}
 
 		// Validate patch is a valid strategic merge patch for PodTemplateSpec
@@ -335,19 +462,213 @@ func validateDaemonSetPatch(patch *appsv1beta1.DaemonSetPatch, fldPath *field.Pa
 				Containers: []corev1.Container{{Name: "dummy"}},
 			},
 		}
-		dummyJSON, _ := json.Marshal(dummyTemplate)
-		_, err := strategicpatch.StrategicMergePatch(dummyJSON, patch.Patch.Raw, &corev1.PodTemplateSpec{})
+		dummyJSON, _ := util.CanonicalJSON(dummyTemplate)
+		_, err = strategicpatch.StrategicMergePatch(dummyJSON, patchJSON, &corev1.PodTemplateSpec{})
 		if err != nil {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("patch"), string(patch.Patch.Raw), fmt.Sprintf("invalid strategic merge patch: %v", err)))
+		} else {
+			allErrs = append(allErrs, validatePatchControllerManagedFields(patchJSON, fldPath.Child("patch"))...)
+			allErrs = append(allErrs, validatePatchPriorityClassName(patchJSON, fldPath.Child("patch"))...)
+			allErrs = append(allErrs, validatePatchRuntimeClassAndOverhead(patchJSON, fldPath.Child("patch"))...)
+			allErrs = append(allErrs, validatePatchTerminationGracePeriodSeconds(patchJSON, fldPath.Child("patch"))...)
+			if utilfeature.DefaultFeatureGate.Enabled(features.DaemonSetPatchImageDigestPinning) {
+				allErrs = append(allErrs, validatePatchImagesDigestPinned(patchJSON, fldPath.Child("patch"))...)
+			}
 		}
 	}
 
 	if patch.Priority < 0 {
-		allErrs = append(allErrs, field.Invalid(fldPath.Child("priority"), patch.Priority, "priority must be non-negative"))
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("priority"), patch.Priority, "must be greater than or equal to 0"))
+	}
+	if patch.Order < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("order"), patch.Order, "must be greater than or equal to 0"))
+	}
+	allErrs = append(allErrs, validatePatchMetadata(patch.Description, patch.Owner, fldPath)...)
+	allErrs = append(allErrs, validatePatchEncoding(patch.PatchEncoding, fldPath.Child("patchEncoding"))...)
+	allErrs = append(allErrs, validatePatchUpdateStrategyType(patch.UpdateStrategyType, fldPath.Child("updateStrategyType"))...)
+
+	return allErrs
+}
+
+const (
+	maxPatchDescriptionLength = 256
+	maxPatchOwnerLength       = 128
+)
+
+func validatePatchEncoding(encoding appsv1beta1.DaemonSetPatchEncodingType, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch encoding {
+	case "", appsv1beta1.GzipDaemonSetPatchEncoding:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath, encoding, []string{string(appsv1beta1.GzipDaemonSetPatchEncoding)}))
+	}
+	return allErrs
+}
+
+func validatePatchUpdateStrategyType(strategyType appsv1beta1.DaemonSetUpdateStrategyType, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch strategyType {
+	case "", appsv1beta1.RollingUpdateDaemonSetStrategyType, appsv1beta1.OnDeleteDaemonSetStrategyType:
+	default:
+		validValues := []string{string(appsv1beta1.RollingUpdateDaemonSetStrategyType), string(appsv1beta1.OnDeleteDaemonSetStrategyType)}
+		allErrs = append(allErrs, field.NotSupported(fldPath, strategyType, validValues))
+	}
+	return allErrs
+}
+
+// validatePatchMetadata validates the description and owner of patch, which are surfaced in events and status.
+func validatePatchMetadata(description, owner string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(description) > maxPatchDescriptionLength {
+		allErrs = append(allErrs, field.TooLong(fldPath.Child("description"), description, maxPatchDescriptionLength))
+	}
+	if len(owner) > maxPatchOwnerLength {
+		allErrs = append(allErrs, field.TooLong(fldPath.Child("owner"), owner, maxPatchOwnerLength))
+	}
+	return allErrs
+}
+
+// controllerManagedLabels are the labels that the controller sets on daemon pods to track their revisions.
+var controllerManagedLabels = []string{extensions.DefaultDaemonSetUniqueLabelKey, extensions.DaemonSetTemplateGenerationKey}
+
+// validatePatchControllerManagedFields rejects the patch that sets or removes the fields managed exclusively
+// by the controller, which would break the tracking of daemon pods or bind them to wrong nodes.
+func validatePatchControllerManagedFields(raw []byte, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	var obj struct {
+		Metadata struct {
+			Labels          map[string]interface{} `json:"labels"`
+			Annotations     map[string]interface{} `json:"annotations"`
+			OwnerReferences json.RawMessage        `json:"ownerReferences"`
+		} `json:"metadata"`
+		Spec struct {
+			NodeName json.RawMessage `json:"nodeName"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return allErrs
+	}
+	for _, key := range controllerManagedLabels {
+		if _, ok := obj.Metadata.Labels[key]; ok {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("metadata", "labels").Key(key),
+				"the label is managed by the controller to track the revisions of daemon pods and cannot be patched"))
+		}
+	}
+	if _, ok := obj.Metadata.Annotations[daemonsetcontroller.RenderHashAnnotation]; ok {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("metadata", "annotations").Key(daemonsetcontroller.RenderHashAnnotation),
+			"the annotation is managed by the controller to track the patches rendered into daemon pods and cannot be patched"))
+	}
+	if len(obj.Metadata.OwnerReferences) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("metadata", "ownerReferences"),
+			"ownerReferences is managed by the controller to track the ownership of daemon pods and cannot be patched"))
+	}
+	if len(obj.Spec.NodeName) > 0 {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("spec", "nodeName"),
+			"nodeName is managed by the controller and the scheduler to bind daemon pods to their nodes and cannot be patched"))
+	}
+	return allErrs
+}
+
+// validatePatchPriorityClassName rejects the empty priorityClassName in patch, which would unset the
+// priorityClassName of spec.template unexpectedly instead of overriding it.
+func validatePatchPriorityClassName(raw []byte, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	var obj struct {
+		Spec struct {
+			PriorityClassName *string `json:"priorityClassName"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return allErrs
+	}
+	if obj.Spec.PriorityClassName != nil && *obj.Spec.PriorityClassName == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("spec", "priorityClassName"), "",
+			"priorityClassName must not be empty, which would unset the priorityClassName of the template"))
+	}
+	return allErrs
+}
+
+// validatePatchTerminationGracePeriodSeconds validates the terminationGracePeriodSeconds in patch, which is usually
+// patched to a longer one for the nodes that take long to drain.
+func validatePatchTerminationGracePeriodSeconds(raw []byte, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	var obj struct {
+		Spec struct {
+			TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return allErrs
+	}
+	if obj.Spec.TerminationGracePeriodSeconds != nil {
+		allErrs = append(allErrs, corevalidation.ValidateNonnegativeField(*obj.Spec.TerminationGracePeriodSeconds,
+			fldPath.Child("spec", "terminationGracePeriodSeconds"))...)
+	}
+	return allErrs
+}
+
+// validatePatchRuntimeClassAndOverhead validates the runtimeClassName and overhead in patch, which are usually
+// patched together for the nodes with a different runtime, e.g., gVisor or Kata.
+func validatePatchRuntimeClassAndOverhead(raw []byte, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	var obj struct {
+		Spec struct {
+			RuntimeClassName *string             `json:"runtimeClassName"`
+			Overhead         corev1.ResourceList `json:"overhead"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return allErrs
+	}
+
+	if obj.Spec.RuntimeClassName != nil {
+		for _, msg := range genericvalidation.NameIsDNSSubdomain(*obj.Spec.RuntimeClassName, false) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("spec", "runtimeClassName"), *obj.Spec.RuntimeClassName, msg))
+		}
 	}
 
+	for name, quantity := range obj.Spec.Overhead {
+		namePath := fldPath.Child("spec", "overhead").Key(string(name))
+		coreName := core.ResourceName(name)
+		if !strings.Contains(string(name), "/") {
+			if !corehelper.IsStandardContainerResourceName(coreName) {
+				allErrs = append(allErrs, field.Invalid(namePath, name, "must be a standard resource for containers"))
+				continue
+			}
+		} else if !corehelper.IsNativeResource(coreName) && !corehelper.IsExtendedResourceName(coreName) {
+			allErrs = append(allErrs, field.Invalid(namePath, name, "doesn't follow extended resource name standard"))
+			continue
+		}
+		allErrs = append(allErrs, corevalidation.ValidateResourceQuantityValue(coreName, quantity, namePath)...)
+	}
 	return allErrs
 }
+
+// validatePatchImagesDigestPinned rejects the container images in patch which are not pinned by sha256 digest.
+func validatePatchImagesDigestPinned(raw []byte, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	template := &corev1.PodTemplateSpec{}
+	if err := json.Unmarshal(raw, template); err != nil {
+		return allErrs
+	}
+
+	validateImages := func(containers []corev1.Container, containersPath *field.Path) {
+		for i := range containers {
+			image := containers[i].Image
+			if image == "" {
+				continue
+			}
+			if _, _, digest, err := util.ParseImage(image); err != nil || !strings.HasPrefix(digest, "sha256:") {
+				allErrs = append(allErrs, field.Invalid(containersPath.Index(i).Child("image"), image,
+					"image must be pinned by digest in the form of <repository>@sha256:<digest>"))
+			}
+		}
+	}
+	validateImages(template.Spec.InitContainers, fldPath.Child("spec", "initContainers"))
+	validateImages(template.Spec.Containers, fldPath.Child("spec", "containers"))
+	return allErrs
+}
+
 func validateDaemonSetUpdateStrategyV1beta1(strategy *appsv1beta1.DaemonSetUpdateStrategy, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	switch strategy.Type {
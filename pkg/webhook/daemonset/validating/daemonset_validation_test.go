@@ -824,6 +824,26 @@ func TestValidateDaemonSetUpdateV1beta1(t *testing.T) {
 	if errs := handler.validateDaemonSetUpdateV1beta1(obj2, oldObj2); len(errs) == 0 {
 		t.Errorf("expected error for forbidden field update")
 	}
+
+	// Success case - adding, changing and removing patches
+	patch := appsv1beta1.DaemonSetPatch{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}}}
+	patch.Patch.Raw = []byte(`{"metadata":{"labels":{"zone":"a"}}}`)
+	changedPatch := *patch.DeepCopy()
+	changedPatch.Priority = 1
+	for _, patches := range [][2][]appsv1beta1.DaemonSetPatch{
+		{nil, {patch}},
+		{{patch}, {changedPatch}},
+		{{patch}, nil},
+	} {
+		oldObj3 := obj.DeepCopy()
+		oldObj3.ResourceVersion = "1"
+		oldObj3.Spec.Patches = patches[0]
+		obj3 := obj.DeepCopy()
+		obj3.Spec.Patches = patches[1]
+		if errs := handler.validateDaemonSetUpdateV1beta1(obj3, oldObj3); len(errs) != 0 {
+			t.Errorf("expected success for updating patches from %v to %v: %v", patches[0], patches[1], errs)
+		}
+	}
 }
 
 func TestValidateDaemonSetUpdateStrategyV1beta1(t *testing.T) {
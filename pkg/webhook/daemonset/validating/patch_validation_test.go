@@ -1,11 +1,22 @@
 package validating
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
 
+	appsv1alpha1 "github.com/openkruise/kruise/apis/apps/v1alpha1"
 	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/features"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
@@ -83,6 +94,48 @@ func TestValidateDaemonSetPatches(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid YAML patch",
+			patches: []appsv1beta1.DaemonSetPatch{
+				{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"key": "value"},
+					},
+					Patch: runtime.RawExtension{
+						Raw: []byte(`"spec:\n  containers:\n  - name: test\n    image: test:latest\n"`),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid YAML patch",
+			patches: []appsv1beta1.DaemonSetPatch{
+				{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"key": "value"},
+					},
+					Patch: runtime.RawExtension{
+						Raw: []byte(`"spec:\n  containers: ["`),
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "YAML patch with empty priorityClassName",
+			patches: []appsv1beta1.DaemonSetPatch{
+				{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"key": "value"},
+					},
+					Patch: runtime.RawExtension{
+						Raw: []byte(`"spec:\n  priorityClassName: \"\"\n"`),
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "nil patch",
 			patches: []appsv1beta1.DaemonSetPatch{
@@ -135,6 +188,85 @@ func TestValidateDaemonSetPatchesPriority(t *testing.T) {
 	}
 }
 
+func TestValidateDaemonSetPatchesNegativePriority(t *testing.T) {
+	patchData := runtime.RawExtension{
+		Raw: []byte(`{"spec":{"containers":[{"name":"test","image":"test:latest"}]}}`),
+	}
+	selector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{"key": "value"},
+	}
+
+	patches := []appsv1beta1.DaemonSetPatch{
+		{Selector: selector, Priority: 10, Patch: patchData},
+		{Selector: selector, Priority: -1, Patch: patchData},
+	}
+	errors := validateDaemonSetPatches(patches, field.NewPath("spec", "patches"))
+	if len(errors) != 1 {
+		t.Fatalf("expected exactly one error for negative priority, got %v", errors)
+	}
+	if errors[0].Type != field.ErrorTypeInvalid || errors[0].Field != "spec.patches[1].priority" {
+		t.Errorf("expected invalid error on spec.patches[1].priority, got %v", errors[0])
+	}
+	if errors[0].Detail != "must be greater than or equal to 0" {
+		t.Errorf("unexpected error detail: %q", errors[0].Detail)
+	}
+
+	patchesV1alpha1 := []appsv1alpha1.DaemonSetPatch{
+		{Selector: selector, Priority: -100, Patch: patchData},
+	}
+	errors = validateDaemonSetPatches(convertPatchesToV1beta1(patchesV1alpha1), field.NewPath("spec", "patches"))
+	if len(errors) != 1 || errors[0].Field != "spec.patches[0].priority" {
+		t.Errorf("expected invalid error on spec.patches[0].priority, got %v", errors)
+	}
+}
+
+func TestValidateDaemonSetPatchOrder(t *testing.T) {
+	patchData := runtime.RawExtension{
+		Raw: []byte(`{"spec":{"containers":[{"name":"test","image":"test:latest"}]}}`),
+	}
+	selector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{"key": "value"},
+	}
+
+	patches := []appsv1beta1.DaemonSetPatch{
+		{Selector: selector, Order: 1, Patch: patchData},
+		{Selector: selector, Order: -1, Patch: patchData},
+	}
+	errors := validateDaemonSetPatches(patches, field.NewPath("spec", "patches"))
+	if len(errors) != 1 || errors[0].Field != "spec.patches[1].order" {
+		t.Fatalf("expected invalid error on spec.patches[1].order, got %v", errors)
+	}
+
+	spec := &appsv1beta1.DaemonSetSpec{PatchOrderBy: "name"}
+	errors = validateDaemonSetSpecV1beta1(spec, field.NewPath("spec"))
+	var found bool
+	for _, err := range errors {
+		if err.Field == "spec.patchOrderBy" && err.Type == field.ErrorTypeNotSupported {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected not supported error on spec.patchOrderBy, got %v", errors)
+	}
+	spec.PatchOrderBy = appsv1beta1.OrderDaemonSetPatchOrderBy
+	for _, err := range validateDaemonSetSpecV1beta1(spec, field.NewPath("spec")) {
+		if err.Field == "spec.patchOrderBy" {
+			t.Fatalf("expected no error on spec.patchOrderBy, got %v", err)
+		}
+	}
+
+	specV1alpha1 := &appsv1alpha1.DaemonSetSpec{PatchOrderBy: "name"}
+	found = false
+	for _, err := range validateDaemonSetSpec(specV1alpha1, field.NewPath("spec")) {
+		if err.Field == "spec.patchOrderBy" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected error on spec.patchOrderBy of v1alpha1")
+	}
+}
+
 func TestValidateDaemonSetPatchesComplexSelector(t *testing.T) {
 	patchData := runtime.RawExtension{
 		Raw: []byte(`{"spec":{"containers":[{"name":"test","image":"test:latest"}]}}`),
@@ -160,3 +292,620 @@ func TestValidateDaemonSetPatchesComplexSelector(t *testing.T) {
 		t.Errorf("valid complex selector should not cause errors: %v", errors)
 	}
 }
+
+func TestValidateDaemonSetPatchesFeatureGate(t *testing.T) {
+	patches := []appsv1beta1.DaemonSetPatch{
+		{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"key": "value"},
+			},
+			Patch: runtime.RawExtension{
+				Raw: []byte(`{"spec":{"containers":[{"name":"test","image":"test:latest"}]}}`),
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		enabled bool
+		patches []appsv1beta1.DaemonSetPatch
+		wantErr bool
+	}{
+		{
+			name:    "gate enabled with patches",
+			enabled: true,
+			patches: patches,
+			wantErr: false,
+		},
+		{
+			name:    "gate disabled with patches",
+			enabled: false,
+			patches: patches,
+			wantErr: true,
+		},
+		{
+			name:    "gate disabled without patches",
+			enabled: false,
+			patches: nil,
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.DaemonSetPatches, tt.enabled)()
+			errors := validateDaemonSetPatches(tt.patches, field.NewPath("spec", "patches"))
+			if (len(errors) > 0) != tt.wantErr {
+				t.Errorf("validateDaemonSetPatches() errors = %v, wantErr %v", errors, tt.wantErr)
+			}
+
+			alphaPatches := make([]appsv1alpha1.DaemonSetPatch, len(tt.patches))
+			for i := range tt.patches {
+				alphaPatches[i] = appsv1alpha1.DaemonSetPatch{
+					Selector: tt.patches[i].Selector,
+					Patch:    tt.patches[i].Patch,
+					Priority: tt.patches[i].Priority,
+				}
+			}
+			errors = validateDaemonSetPatches(convertPatchesToV1beta1(alphaPatches), field.NewPath("spec", "patches"))
+			if (len(errors) > 0) != tt.wantErr {
+				t.Errorf("validateDaemonSetPatches() with v1alpha1 patches errors = %v, wantErr %v", errors, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePatchedTopologySpreadConstraints(t *testing.T) {
+	template := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}},
+		Spec: corev1.PodSpec{
+			Containers:    []corev1.Container{{Name: "test", Image: "test:latest", ImagePullPolicy: corev1.PullAlways, TerminationMessagePolicy: corev1.TerminationMessageReadFile}},
+			RestartPolicy: corev1.RestartPolicyAlways,
+			DNSPolicy:     corev1.DNSClusterFirst,
+			TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+				{MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule},
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		patch      string
+		wantFields []string
+	}{
+		{
+			name:  "patch without topologySpreadConstraints",
+			patch: `{"spec":{"containers":[{"name":"test","image":"test:v2"}]}}`,
+		},
+		{
+			name:  "valid merged constraints",
+			patch: `{"spec":{"topologySpreadConstraints":[{"topologyKey":"topology.kubernetes.io/zone","maxSkew":2}]}}`,
+		},
+		{
+			name:       "invalid maxSkew after merge",
+			patch:      `{"spec":{"topologySpreadConstraints":[{"topologyKey":"topology.kubernetes.io/zone","maxSkew":0}]}}`,
+			wantFields: []string{"spec.patches[0].patch.spec.topologySpreadConstraints[0].maxSkew"},
+		},
+		{
+			name:       "invalid whenUnsatisfiable in new constraint",
+			patch:      `{"spec":{"topologySpreadConstraints":[{"topologyKey":"example.com/nic-group","maxSkew":1,"whenUnsatisfiable":"Unknown"}]}}`,
+			wantFields: []string{"spec.patches[0].patch.spec.topologySpreadConstraints[0].whenUnsatisfiable"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validatePatchedTemplates(template, []appsv1beta1.DaemonSetPatch{{Patch: runtime.RawExtension{Raw: []byte(tt.patch)}}}, field.NewPath("spec", "patches"))
+			var gotFields []string
+			for _, err := range errs {
+				gotFields = append(gotFields, err.Field)
+			}
+			if !reflect.DeepEqual(gotFields, tt.wantFields) {
+				t.Errorf("expected errors on %v, got %v", tt.wantFields, errs)
+			}
+		})
+	}
+}
+
+func TestValidatePatchedProbes(t *testing.T) {
+	template := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:                     "test",
+				Image:                    "test:latest",
+				ImagePullPolicy:          corev1.PullAlways,
+				TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+				ReadinessProbe: &corev1.Probe{
+					ProbeHandler: corev1.ProbeHandler{
+						HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt32(8080), Scheme: corev1.URISchemeHTTP},
+					},
+					TimeoutSeconds:   1,
+					PeriodSeconds:    10,
+					SuccessThreshold: 1,
+					FailureThreshold: 3,
+				},
+			}},
+			RestartPolicy: corev1.RestartPolicyAlways,
+			DNSPolicy:     corev1.DNSClusterFirst,
+		},
+	}
+
+	tests := []struct {
+		name       string
+		patch      string
+		wantFields []string
+	}{
+		{
+			name:  "patch without probes",
+			patch: `{"spec":{"containers":[{"name":"test","image":"test:v2"}]}}`,
+		},
+		{
+			name:  "longer readinessProbe timeout",
+			patch: `{"spec":{"containers":[{"name":"test","readinessProbe":{"timeoutSeconds":10}}]}}`,
+		},
+		{
+			name:       "negative readinessProbe timeout",
+			patch:      `{"spec":{"containers":[{"name":"test","readinessProbe":{"timeoutSeconds":-1}}]}}`,
+			wantFields: []string{"spec.patches[0].patch.spec.containers[0].readinessProbe.timeoutSeconds"},
+		},
+		{
+			name:       "another handler merged into readinessProbe",
+			patch:      `{"spec":{"containers":[{"name":"test","readinessProbe":{"exec":{"command":["true"]}}}]}}`,
+			wantFields: []string{"spec.patches[0].patch.spec.containers[0].readinessProbe.httpGet"},
+		},
+		{
+			name:  "readinessProbe replaced with another handler",
+			patch: `{"spec":{"containers":[{"name":"test","readinessProbe":{"$patch":"replace","exec":{"command":["true"]},"timeoutSeconds":10,"periodSeconds":10,"successThreshold":1,"failureThreshold":3}}]}}`,
+		},
+		{
+			name:       "startupProbe without handler",
+			patch:      `{"spec":{"containers":[{"name":"test","startupProbe":{"timeoutSeconds":10,"periodSeconds":10,"successThreshold":1,"failureThreshold":3}}]}}`,
+			wantFields: []string{"spec.patches[0].patch.spec.containers[0].startupProbe"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validatePatchedTemplates(template, []appsv1beta1.DaemonSetPatch{{Patch: runtime.RawExtension{Raw: []byte(tt.patch)}}}, field.NewPath("spec", "patches"))
+			var gotFields []string
+			for _, err := range errs {
+				gotFields = append(gotFields, err.Field)
+			}
+			if !reflect.DeepEqual(gotFields, tt.wantFields) {
+				t.Errorf("expected errors on %v, got %v", tt.wantFields, errs)
+			}
+		})
+	}
+}
+
+func TestValidatePatchedHostAliases(t *testing.T) {
+	template := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "test"}},
+		Spec: corev1.PodSpec{
+			Containers:    []corev1.Container{{Name: "test", Image: "test:latest", ImagePullPolicy: corev1.PullAlways, TerminationMessagePolicy: corev1.TerminationMessageReadFile}},
+			RestartPolicy: corev1.RestartPolicyAlways,
+			DNSPolicy:     corev1.DNSClusterFirst,
+			HostAliases:   []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"registry.local"}}},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		patch      string
+		wantFields []string
+	}{
+		{
+			name:  "patch without hostAliases",
+			patch: `{"spec":{"containers":[{"name":"test","image":"test:v2"}]}}`,
+		},
+		{
+			name:  "node-local hostAlias added",
+			patch: `{"spec":{"hostAliases":[{"ip":"169.254.20.10","hostnames":["cache.node.local"]}]}}`,
+		},
+		{
+			name:       "invalid ip",
+			patch:      `{"spec":{"hostAliases":[{"ip":"169.254.20","hostnames":["cache.node.local"]}]}}`,
+			wantFields: []string{"spec.patches[0].patch.spec.hostAliases[0].ip"},
+		},
+		{
+			name:       "invalid hostname of merged ip",
+			patch:      `{"spec":{"hostAliases":[{"ip":"10.0.0.1","hostnames":["Registry_Local"]}]}}`,
+			wantFields: []string{"spec.patches[0].patch.spec.hostAliases[0].hostnames[0]"},
+		},
+		{
+			name:  "invalid ip and probe in the same patch",
+			patch: `{"spec":{"hostAliases":[{"ip":"169.254.20","hostnames":["cache.node.local"]}],"containers":[{"name":"test","startupProbe":{"timeoutSeconds":10,"periodSeconds":10,"successThreshold":1,"failureThreshold":3}}]}}`,
+			wantFields: []string{
+				"spec.patches[0].patch.spec.containers[0].startupProbe",
+				"spec.patches[0].patch.spec.hostAliases[0].ip",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validatePatchedTemplates(template, []appsv1beta1.DaemonSetPatch{{Patch: runtime.RawExtension{Raw: []byte(tt.patch)}}}, field.NewPath("spec", "patches"))
+			var gotFields []string
+			for _, err := range errs {
+				gotFields = append(gotFields, err.Field)
+			}
+			if !reflect.DeepEqual(gotFields, tt.wantFields) {
+				t.Errorf("expected errors on %v, got %v", tt.wantFields, errs)
+			}
+		})
+	}
+}
+
+func TestValidateDaemonSetPatchesImageDigestPinning(t *testing.T) {
+	selector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{"key": "value"},
+	}
+	digestImage := "docker.io/library/nginx@sha256:a9286defaba7b3a519d585ba0e37d0b2cbee74ebfe590960b0b1d6a5e97d1e1d"
+
+	tests := []struct {
+		name        string
+		enabled     bool
+		patch       string
+		expectedErr []string
+	}{
+		{
+			name:        "tag image rejected",
+			enabled:     true,
+			patch:       `{"spec":{"containers":[{"name":"test","image":"nginx:1.25"}]}}`,
+			expectedErr: []string{"spec.patches[0].patch.spec.containers[0].image"},
+		},
+		{
+			name:    "digest image accepted",
+			enabled: true,
+			patch:   `{"spec":{"containers":[{"name":"test","image":"` + digestImage + `"}]}}`,
+		},
+		{
+			name:    "tag and digest image accepted",
+			enabled: true,
+			patch:   `{"spec":{"containers":[{"name":"test","image":"nginx:1.25@sha256:a9286defaba7b3a519d585ba0e37d0b2cbee74ebfe590960b0b1d6a5e97d1e1d"}]}}`,
+		},
+		{
+			name:        "init container tag image rejected",
+			enabled:     true,
+			patch:       `{"spec":{"initContainers":[{"name":"init","image":"busybox"}],"containers":[{"name":"test","image":"` + digestImage + `"}]}}`,
+			expectedErr: []string{"spec.patches[0].patch.spec.initContainers[0].image"},
+		},
+		{
+			name:    "patch without image accepted",
+			enabled: true,
+			patch:   `{"metadata":{"labels":{"foo":"bar"}},"spec":{"containers":[{"name":"test","env":[{"name":"A","value":"B"}]}]}}`,
+		},
+		{
+			name:    "tag image accepted when policy disabled",
+			enabled: false,
+			patch:   `{"spec":{"containers":[{"name":"test","image":"nginx:1.25"}]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.DaemonSetPatchImageDigestPinning, tt.enabled)()
+			patch := runtime.RawExtension{Raw: []byte(tt.patch)}
+
+			errors := validateDaemonSetPatches([]appsv1beta1.DaemonSetPatch{{Selector: selector, Patch: patch}}, field.NewPath("spec", "patches"))
+			var fields []string
+			for _, err := range errors {
+				fields = append(fields, err.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.expectedErr) {
+				t.Fatalf("expected errors on %v, got %v", tt.expectedErr, errors)
+			}
+
+			errors = validateDaemonSetPatches(convertPatchesToV1beta1([]appsv1alpha1.DaemonSetPatch{{Selector: selector, Patch: patch}}), field.NewPath("spec", "patches"))
+			if len(errors) != len(tt.expectedErr) {
+				t.Fatalf("expected %d errors for v1alpha1, got %v", len(tt.expectedErr), errors)
+			}
+		})
+	}
+}
+
+func TestValidateDaemonSetPatchesPriorityClassName(t *testing.T) {
+	selector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{"key": "value"},
+	}
+
+	tests := []struct {
+		name        string
+		patch       string
+		expectedErr []string
+	}{
+		{
+			name:  "scheduler name and priority class name accepted",
+			patch: `{"spec":{"schedulerName":"dedicated-scheduler","priorityClassName":"dedicated-priority"}}`,
+		},
+		{
+			name:        "empty priority class name rejected",
+			patch:       `{"spec":{"schedulerName":"dedicated-scheduler","priorityClassName":""}}`,
+			expectedErr: []string{"spec.patches[0].patch.spec.priorityClassName"},
+		},
+		{
+			name:  "patch without priority class name accepted",
+			patch: `{"metadata":{"labels":{"foo":"bar"}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch := runtime.RawExtension{Raw: []byte(tt.patch)}
+
+			errors := validateDaemonSetPatches([]appsv1beta1.DaemonSetPatch{{Selector: selector, Patch: patch}}, field.NewPath("spec", "patches"))
+			var fields []string
+			for _, err := range errors {
+				fields = append(fields, err.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.expectedErr) {
+				t.Fatalf("expected errors on %v, got %v", tt.expectedErr, errors)
+			}
+
+			errors = validateDaemonSetPatches(convertPatchesToV1beta1([]appsv1alpha1.DaemonSetPatch{{Selector: selector, Patch: patch}}), field.NewPath("spec", "patches"))
+			if len(errors) != len(tt.expectedErr) {
+				t.Fatalf("expected %d errors for v1alpha1, got %v", len(tt.expectedErr), errors)
+			}
+		})
+	}
+}
+
+func TestValidateDaemonSetPatchesRuntimeClassAndOverhead(t *testing.T) {
+	selector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{"runtime": "gvisor"},
+	}
+
+	tests := []struct {
+		name        string
+		patch       string
+		expectedErr []string
+	}{
+		{
+			name:  "runtime class name and overhead accepted",
+			patch: `{"spec":{"runtimeClassName":"gvisor","overhead":{"cpu":"250m","memory":"120Mi"}}}`,
+		},
+		{
+			name:  "extended resource in overhead accepted",
+			patch: `{"spec":{"overhead":{"example.com/foo":"1"}}}`,
+		},
+		{
+			name:        "invalid runtime class name rejected",
+			patch:       `{"spec":{"runtimeClassName":"GVisor_Sandbox"}}`,
+			expectedErr: []string{"spec.patches[0].patch.spec.runtimeClassName"},
+		},
+		{
+			name:        "invalid overhead resource name rejected",
+			patch:       `{"spec":{"runtimeClassName":"gvisor","overhead":{"cpus":"250m"}}}`,
+			expectedErr: []string{"spec.patches[0].patch.spec.overhead[cpus]"},
+		},
+		{
+			name:        "negative overhead rejected",
+			patch:       `{"spec":{"overhead":{"memory":"-1Mi"}}}`,
+			expectedErr: []string{"spec.patches[0].patch.spec.overhead[memory]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch := runtime.RawExtension{Raw: []byte(tt.patch)}
+
+			errors := validateDaemonSetPatches([]appsv1beta1.DaemonSetPatch{{Selector: selector, Patch: patch}}, field.NewPath("spec", "patches"))
+			var fields []string
+			for _, err := range errors {
+				fields = append(fields, err.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.expectedErr) {
+				t.Fatalf("expected errors on %v, got %v", tt.expectedErr, errors)
+			}
+
+			errors = validateDaemonSetPatches(convertPatchesToV1beta1([]appsv1alpha1.DaemonSetPatch{{Selector: selector, Patch: patch}}), field.NewPath("spec", "patches"))
+			if len(errors) != len(tt.expectedErr) {
+				t.Fatalf("expected %d errors for v1alpha1, got %v", len(tt.expectedErr), errors)
+			}
+		})
+	}
+}
+
+func TestValidateDaemonSetPatchesTerminationGracePeriodSeconds(t *testing.T) {
+	selector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{"drain": "slow"},
+	}
+
+	tests := []struct {
+		name        string
+		patch       string
+		expectedErr []string
+	}{
+		{
+			name:  "longer grace period accepted",
+			patch: `{"spec":{"terminationGracePeriodSeconds":600}}`,
+		},
+		{
+			name:  "zero grace period accepted",
+			patch: `{"spec":{"terminationGracePeriodSeconds":0}}`,
+		},
+		{
+			name:        "negative grace period rejected",
+			patch:       `{"spec":{"terminationGracePeriodSeconds":-1}}`,
+			expectedErr: []string{"spec.patches[0].patch.spec.terminationGracePeriodSeconds"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch := runtime.RawExtension{Raw: []byte(tt.patch)}
+
+			errors := validateDaemonSetPatches([]appsv1beta1.DaemonSetPatch{{Selector: selector, Patch: patch}}, field.NewPath("spec", "patches"))
+			var fields []string
+			for _, err := range errors {
+				fields = append(fields, err.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.expectedErr) {
+				t.Fatalf("expected errors on %v, got %v", tt.expectedErr, errors)
+			}
+
+			errors = validateDaemonSetPatches(convertPatchesToV1beta1([]appsv1alpha1.DaemonSetPatch{{Selector: selector, Patch: patch}}), field.NewPath("spec", "patches"))
+			if len(errors) != len(tt.expectedErr) {
+				t.Fatalf("expected %d errors for v1alpha1, got %v", len(tt.expectedErr), errors)
+			}
+		})
+	}
+}
+
+func TestValidateDaemonSetPatchMetadata(t *testing.T) {
+	patchData := runtime.RawExtension{
+		Raw: []byte(`{"spec":{"containers":[{"name":"test","image":"test:latest"}]}}`),
+	}
+	selector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{"key": "value"},
+	}
+
+	patches := []appsv1beta1.DaemonSetPatch{
+		{Selector: selector, Patch: patchData, Description: "raise memory limit for large nodes", Owner: "team-infra"},
+		{Selector: selector, Patch: patchData, Description: strings.Repeat("a", 257), Owner: strings.Repeat("b", 129)},
+	}
+	errors := validateDaemonSetPatches(patches, field.NewPath("spec", "patches"))
+	if len(errors) != 2 {
+		t.Fatalf("expected two errors for too long metadata, got %v", errors)
+	}
+	if errors[0].Type != field.ErrorTypeTooLong || errors[0].Field != "spec.patches[1].description" {
+		t.Errorf("expected too long error on spec.patches[1].description, got %v", errors[0])
+	}
+	if errors[1].Type != field.ErrorTypeTooLong || errors[1].Field != "spec.patches[1].owner" {
+		t.Errorf("expected too long error on spec.patches[1].owner, got %v", errors[1])
+	}
+
+	patchesV1alpha1 := []appsv1alpha1.DaemonSetPatch{
+		{Selector: selector, Patch: patchData, Owner: strings.Repeat("b", 129)},
+	}
+	errors = validateDaemonSetPatches(convertPatchesToV1beta1(patchesV1alpha1), field.NewPath("spec", "patches"))
+	if len(errors) != 1 || errors[0].Field != "spec.patches[0].owner" {
+		t.Errorf("expected too long error on spec.patches[0].owner, got %v", errors)
+	}
+}
+
+func TestValidateDaemonSetPatchControllerManagedFields(t *testing.T) {
+	selector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{"key": "value"},
+	}
+	cases := []struct {
+		name         string
+		patch        string
+		expectFields []string
+	}{
+		{
+			name:  "patch other labels and annotations",
+			patch: `{"metadata":{"labels":{"tier":"edge"},"annotations":{"owner":"team-a"}}}`,
+		},
+		{
+			name:         "patch the ownership label",
+			patch:        `{"metadata":{"labels":{"controller-revision-hash":"abc"}}}`,
+			expectFields: []string{"spec.patches[0].patch.metadata.labels[controller-revision-hash]"},
+		},
+		{
+			name:         "remove the template generation label",
+			patch:        `{"metadata":{"labels":{"pod-template-generation":null}}}`,
+			expectFields: []string{"spec.patches[0].patch.metadata.labels[pod-template-generation]"},
+		},
+		{
+			name:  "patch nodeName, ownerReferences and render hash in YAML",
+			patch: `"metadata:\n  annotations:\n    daemonset.kruise.io/render-hash: abc\n  ownerReferences: []\nspec:\n  nodeName: node-1\n"`,
+			expectFields: []string{
+				"spec.patches[0].patch.metadata.annotations[daemonset.kruise.io/render-hash]",
+				"spec.patches[0].patch.metadata.ownerReferences",
+				"spec.patches[0].patch.spec.nodeName",
+			},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			patches := []appsv1beta1.DaemonSetPatch{
+				{Selector: selector, Patch: runtime.RawExtension{Raw: []byte(cs.patch)}},
+			}
+			errors := validateDaemonSetPatches(patches, field.NewPath("spec", "patches"))
+			var fields []string
+			for _, err := range errors {
+				if err.Type != field.ErrorTypeForbidden {
+					t.Fatalf("unexpected error %v", err)
+				}
+				fields = append(fields, err.Field)
+			}
+			if !reflect.DeepEqual(fields, cs.expectFields) {
+				t.Fatalf("expected forbidden errors on %v, got %v", cs.expectFields, errors)
+			}
+
+			patchesV1alpha1 := []appsv1alpha1.DaemonSetPatch{
+				{Selector: selector, Patch: runtime.RawExtension{Raw: []byte(cs.patch)}},
+			}
+			if errors = validateDaemonSetPatches(convertPatchesToV1beta1(patchesV1alpha1), field.NewPath("spec", "patches")); len(errors) != len(cs.expectFields) {
+				t.Fatalf("expected %d errors for v1alpha1, got %v", len(cs.expectFields), errors)
+			}
+		})
+	}
+}
+
+func TestValidateDaemonSetPatchEncoding(t *testing.T) {
+	selector := &metav1.LabelSelector{
+		MatchLabels: map[string]string{"key": "value"},
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, _ = w.Write([]byte(`{"spec":{"containers":[{"name":"test","image":"test:latest"}]}}`))
+	_ = w.Close()
+	compressed, _ := json.Marshal(base64.StdEncoding.EncodeToString(buf.Bytes()))
+
+	cases := []struct {
+		name         string
+		patch        []byte
+		encoding     appsv1beta1.DaemonSetPatchEncodingType
+		expectFields []string
+	}{
+		{
+			name:     "gzip patch",
+			patch:    compressed,
+			encoding: appsv1beta1.GzipDaemonSetPatchEncoding,
+		},
+		{
+			name:         "gzip patch not base64 encoded",
+			patch:        []byte(`"not base64"`),
+			encoding:     appsv1beta1.GzipDaemonSetPatchEncoding,
+			expectFields: []string{"spec.patches[0].patch", "spec.patches[0].patch"},
+		},
+		{
+			name:         "plaintext patch with gzip encoding",
+			patch:        []byte(`{"spec":{"containers":[{"name":"test","image":"test:latest"}]}}`),
+			encoding:     appsv1beta1.GzipDaemonSetPatchEncoding,
+			expectFields: []string{"spec.patches[0].patch"},
+		},
+		{
+			name:         "unsupported encoding",
+			patch:        compressed,
+			encoding:     "zstd",
+			expectFields: []string{"spec.patches[0].patch", "spec.patches[0].patch", "spec.patches[0].patchEncoding"},
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			patches := []appsv1beta1.DaemonSetPatch{
+				{Selector: selector, Patch: runtime.RawExtension{Raw: cs.patch}, PatchEncoding: cs.encoding},
+			}
+			var fields []string
+			for _, err := range validateDaemonSetPatches(patches, field.NewPath("spec", "patches")) {
+				fields = append(fields, err.Field)
+			}
+			if !reflect.DeepEqual(fields, cs.expectFields) {
+				t.Fatalf("expected errors on %v, got %v", cs.expectFields, fields)
+			}
+
+			patchesV1alpha1 := []appsv1alpha1.DaemonSetPatch{
+				{Selector: selector, Patch: runtime.RawExtension{Raw: cs.patch}, PatchEncoding: appsv1alpha1.DaemonSetPatchEncodingType(cs.encoding)},
+			}
+			if errs := validateDaemonSetPatches(convertPatchesToV1beta1(patchesV1alpha1), field.NewPath("spec", "patches")); len(errs) != len(cs.expectFields) {
+				t.Fatalf("expected %d errors for v1alpha1, got %v", len(cs.expectFields), errs)
+			}
+		})
+	}
+}
@@ -23,13 +23,18 @@ import (
 	"github.com/openkruise/kruise/pkg/webhook/types"
 )
 
+// +kubebuilder:rbac:groups=core,resources=resourcequotas,verbs=get;list;watch
+
 // +kubebuilder:webhook:path=/validate-apps-kruise-io-daemonset,mutating=false,failurePolicy=fail,sideEffects=None,admissionReviewVersions=v1;v1beta1,groups=apps.kruise.io,resources=daemonsets,verbs=create;update,versions=v1alpha1;v1beta1,name=vdaemonset.kb.io
 
 var (
 	// HandlerGetterMap contains admission webhook handlers
 	HandlerGetterMap = map[string]types.HandlerGetter{
 		"validate-apps-kruise-io-daemonset": func(mgr manager.Manager) admission.Handler {
-			return &DaemonSetCreateUpdateHandler{Decoder: admission.NewDecoder(mgr.GetScheme())}
+			return &DaemonSetCreateUpdateHandler{
+				Client:  mgr.GetClient(),
+				Decoder: admission.NewDecoder(mgr.GetScheme()),
+			}
 		},
 	}
 )
@@ -0,0 +1,107 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validating
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/openkruise/kruise/pkg/util"
+	"github.com/openkruise/kruise/pkg/webhook/util/deletionprotection"
+)
+
+// ResourceHandler handles the resources configured by --deletion-protection-resources, e.g. PersistentVolumeClaim and custom resources
+type ResourceHandler struct {
+	Client client.Client
+
+	// Decoder decodes objects
+	Decoder admission.Decoder
+}
+
+var _ admission.Handler = &ResourceHandler{}
+
+// Handle handles admission requests.
+func (h *ResourceHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.AdmissionRequest.SubResource != "" {
+		return admission.ValidationResponse(true, "")
+	}
+	switch req.AdmissionRequest.Operation {
+	case admissionv1.Create, admissionv1.Update:
+		return h.validateExpiry(req)
+	case admissionv1.Delete:
+	default:
+		return admission.ValidationResponse(true, "")
+	}
+	if len(req.OldObject.Raw) == 0 {
+		klog.InfoS("Skip to validate for no old object, maybe because of Kubernetes version < 1.16", "kind", req.Kind.Kind, "namespace", req.Namespace, "name", req.Name)
+		return admission.ValidationResponse(true, "")
+	}
+
+	var err error
+	var namespace, name string
+	if req.Kind.Group == "" && req.Kind.Kind == "PersistentVolumeClaim" {
+		obj := &v1.PersistentVolumeClaim{}
+		if err := h.Decoder.DecodeRaw(req.AdmissionRequest.OldObject, obj); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		namespace, name = obj.Namespace, obj.Name
+		err = deletionprotection.ValidatePersistentVolumeClaimDeletion(h.Client, obj)
+	} else {
+		obj := &unstructured.Unstructured{}
+		if err := h.Decoder.DecodeRaw(req.AdmissionRequest.OldObject, obj); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		namespace, name = obj.GetNamespace(), obj.GetName()
+		err = deletionprotection.ValidateResourceDeletion(h.Client, obj)
+	}
+
+	if err != nil {
+		deletionprotection.WorkloadDeletionProtectionMetrics.WithLabelValues(fmt.Sprintf("%s_%s_%s", req.Kind.Kind, namespace, name), req.UserInfo.Username).Add(1)
+		util.LoggerProtectionInfo(util.ProtectionEventDeletionProtection, req.Kind.Kind, namespace, name, req.UserInfo.Username)
+		return admission.Errored(http.StatusForbidden, err)
+	}
+	return admission.ValidationResponse(true, "")
+}
+
+// validateExpiry checks the expiry of deletion protection set on the object created or updated.
+func (h *ResourceHandler) validateExpiry(req admission.Request) admission.Response {
+	obj := &unstructured.Unstructured{}
+	if err := h.Decoder.Decode(req, obj); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	var oldObj metav1.Object
+	if req.AdmissionRequest.Operation == admissionv1.Update {
+		old := &unstructured.Unstructured{}
+		if err := h.Decoder.DecodeRaw(req.AdmissionRequest.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		oldObj = old
+	}
+	if err := deletionprotection.ValidateProtectionExpiry(obj, oldObj); err != nil {
+		return admission.Errored(http.StatusUnprocessableEntity, err)
+	}
+	return admission.ValidationResponse(true, "")
+}
@@ -69,5 +69,6 @@ func validate(obj *appsv1alpha1.EphemeralJob) error {
 	hostUsers := true
 	// don't validate EphemeralContainer TargetContainerName
 	allErrs := validateEphemeralContainers(ecs, field.NewPath("ephemeralContainers"), validation.PodValidationOptions{}, hostUsers)
+	allErrs = append(allErrs, validatePodNames(obj.Spec.PodNames, field.NewPath("spec", "podNames"))...)
 	return allErrs.ToAggregate()
 }
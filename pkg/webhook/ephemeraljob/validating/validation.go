@@ -213,3 +213,24 @@ func validateEphemeralContainers(ephemeralContainers []core.EphemeralContainer,
 
 	return allErrs
 }
+
+// validatePodNames validates spec.podNames, which must be names of pods in the same namespace of EphemeralJob.
+func validatePodNames(podNames []string, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := sets.NewString()
+	for i, name := range podNames {
+		idxPath := fldPath.Index(i)
+		if strings.Contains(name, "/") {
+			allErrs = append(allErrs, field.Invalid(idxPath, name, "must be a pod name in the same namespace of EphemeralJob"))
+		} else {
+			for _, msg := range validation.ValidatePodName(name, false) {
+				allErrs = append(allErrs, field.Invalid(idxPath, name, msg))
+			}
+		}
+		if names.Has(name) {
+			allErrs = append(allErrs, field.Duplicate(idxPath, name))
+		}
+		names.Insert(name)
+	}
+	return allErrs
+}
@@ -392,3 +392,44 @@ func TestValidateEphemeralContainers(t *testing.T) {
 		})
 	}
 }
+
+func TestValidatePodNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		podNames []string
+		wantErrs int
+	}{
+		{
+			name:     "empty pod names",
+			podNames: nil,
+		},
+		{
+			name:     "valid pod names",
+			podNames: []string{"api-7f9-abcde", "api-7f9-fghij"},
+		},
+		{
+			name:     "pod name in other namespace",
+			podNames: []string{"other/api-7f9-abcde"},
+			wantErrs: 1,
+		},
+		{
+			name:     "invalid pod name",
+			podNames: []string{"API_7f9"},
+			wantErrs: 1,
+		},
+		{
+			name:     "duplicated pod names",
+			podNames: []string{"api-7f9-abcde", "api-7f9-abcde"},
+			wantErrs: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validatePodNames(tt.podNames, field.NewPath("spec", "podNames"))
+			if len(errs) != tt.wantErrs {
+				t.Errorf("expected %d errors, got %d: %s", tt.wantErrs, len(errs), prettyErrorList(errs))
+			}
+		})
+	}
+}
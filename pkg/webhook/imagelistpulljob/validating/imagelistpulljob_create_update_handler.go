@@ -146,6 +146,9 @@ func validate(obj *appsv1alpha1.ImageListPullJob) error {
 		}
 	}
 
+	if platform := obj.Spec.Platform; platform != nil && (len(platform.Architecture) == 0 || len(platform.OS) == 0) {
+		return fmt.Errorf("platform.architecture and platform.os can not be empty")
+	}
 	switch obj.Spec.CompletionPolicy.Type {
 	case appsv1alpha1.Always:
 	// is a no-op here.No need to do parameter dependency verification in this type.
@@ -228,6 +231,9 @@ func validateV1beta1(obj *appsv1beta1.ImageListPullJob) error {
 		}
 	}
 
+	if platform := obj.Spec.Platform; platform != nil && (len(platform.Architecture) == 0 || len(platform.OS) == 0) {
+		return fmt.Errorf("platform.architecture and platform.os can not be empty")
+	}
 	switch obj.Spec.CompletionPolicy.Type {
 	case appsv1beta1.Always:
 	// is a no-op here.No need to do parameter dependency verification in this type.
@@ -23,9 +23,11 @@ import (
 	"strconv"
 	"strings"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -35,6 +37,7 @@ import (
 	daemonutil "github.com/openkruise/kruise/pkg/daemon/util"
 	"github.com/openkruise/kruise/pkg/features"
 	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+	utilimagejob "github.com/openkruise/kruise/pkg/util/imagejob"
 )
 
 // ImagePullJobCreateUpdateHandler handles ImagePullJob
@@ -95,6 +98,9 @@ func validate(obj *appsv1alpha1.ImagePullJob) error {
 				return fmt.Errorf("duplicated name in selector names")
 			}
 		}
+		if err := validateMatchFields(obj.Spec.Selector.MatchFields); err != nil {
+			return err
+		}
 	}
 	if obj.Spec.PodSelector != nil {
 		if obj.Spec.Selector != nil {
@@ -105,12 +111,14 @@ func validate(obj *appsv1alpha1.ImagePullJob) error {
 		}
 	}
 
-	if len(obj.Spec.Image) == 0 {
-		return fmt.Errorf("image can not be empty")
+	if err := validateImages(obj.Spec.Image, obj.Spec.Images); err != nil {
+		return err
 	}
 
-	if _, err := daemonutil.NormalizeImageRef(obj.Spec.Image); err != nil {
-		return fmt.Errorf("invalid image %s: %v", obj.Spec.Image, err)
+	if obj.Spec.ServiceAccountName != "" {
+		if errs := validation.IsDNS1123Subdomain(obj.Spec.ServiceAccountName); len(errs) > 0 {
+			return fmt.Errorf("invalid serviceAccountName %s: %s", obj.Spec.ServiceAccountName, strings.Join(errs, ", "))
+		}
 	}
 
 	// Validate Parallelism (only supports integer, not percentage)
@@ -139,6 +147,15 @@ func validate(obj *appsv1alpha1.ImagePullJob) error {
 	if obj.Spec.PullPolicy.TimeoutSeconds == nil {
 		obj.Spec.PullPolicy.TimeoutSeconds = ptr.To[int32](600)
 	}
+	if obj.Spec.PullPolicy.BackoffLimit != nil && *obj.Spec.PullPolicy.BackoffLimit < 0 {
+		return fmt.Errorf("pullPolicy.backoffLimit must be non-negative")
+	}
+	if obj.Spec.PullPolicy.MaxConcurrentPullsPerNode != nil && *obj.Spec.PullPolicy.MaxConcurrentPullsPerNode <= 0 {
+		return fmt.Errorf("pullPolicy.maxConcurrentPullsPerNode must be positive")
+	}
+	if platform := obj.Spec.Platform; platform != nil && (len(platform.Architecture) == 0 || len(platform.OS) == 0) {
+		return fmt.Errorf("platform.architecture and platform.os can not be empty")
+	}
 	switch obj.Spec.CompletionPolicy.Type {
 	case appsv1alpha1.Always:
 		if obj.Spec.CompletionPolicy.ActiveDeadlineSeconds != nil && int64(*obj.Spec.PullPolicy.TimeoutSeconds) > *obj.Spec.CompletionPolicy.ActiveDeadlineSeconds {
@@ -171,6 +188,9 @@ func validateV1beta1(obj *appsv1beta1.ImagePullJob) error {
 				return fmt.Errorf("duplicated name in selector names")
 			}
 		}
+		if err := validateMatchFields(obj.Spec.Selector.MatchFields); err != nil {
+			return err
+		}
 	}
 	if obj.Spec.PodSelector != nil {
 		if obj.Spec.Selector != nil {
@@ -181,12 +201,14 @@ func validateV1beta1(obj *appsv1beta1.ImagePullJob) error {
 		}
 	}
 
-	if len(obj.Spec.Image) == 0 {
-		return fmt.Errorf("image can not be empty")
+	if err := validateImages(obj.Spec.Image, obj.Spec.Images); err != nil {
+		return err
 	}
 
-	if _, err := daemonutil.NormalizeImageRef(obj.Spec.Image); err != nil {
-		return fmt.Errorf("invalid image %s: %v", obj.Spec.Image, err)
+	if obj.Spec.ServiceAccountName != "" {
+		if errs := validation.IsDNS1123Subdomain(obj.Spec.ServiceAccountName); len(errs) > 0 {
+			return fmt.Errorf("invalid serviceAccountName %s: %s", obj.Spec.ServiceAccountName, strings.Join(errs, ", "))
+		}
 	}
 
 	// Validate Parallelism (only supports integer, not percentage)
@@ -215,6 +237,15 @@ func validateV1beta1(obj *appsv1beta1.ImagePullJob) error {
 	if obj.Spec.PullPolicy.TimeoutSeconds == nil {
 		obj.Spec.PullPolicy.TimeoutSeconds = ptr.To[int32](600)
 	}
+	if obj.Spec.PullPolicy.BackoffLimit != nil && *obj.Spec.PullPolicy.BackoffLimit < 0 {
+		return fmt.Errorf("pullPolicy.backoffLimit must be non-negative")
+	}
+	if obj.Spec.PullPolicy.MaxConcurrentPullsPerNode != nil && *obj.Spec.PullPolicy.MaxConcurrentPullsPerNode <= 0 {
+		return fmt.Errorf("pullPolicy.maxConcurrentPullsPerNode must be positive")
+	}
+	if platform := obj.Spec.Platform; platform != nil && (len(platform.Architecture) == 0 || len(platform.OS) == 0) {
+		return fmt.Errorf("platform.architecture and platform.os can not be empty")
+	}
 	switch obj.Spec.CompletionPolicy.Type {
 	case appsv1beta1.Always:
 		if obj.Spec.CompletionPolicy.ActiveDeadlineSeconds != nil && int64(*obj.Spec.PullPolicy.TimeoutSeconds) > *obj.Spec.CompletionPolicy.ActiveDeadlineSeconds {
@@ -230,3 +261,46 @@ func validateV1beta1(obj *appsv1beta1.ImagePullJob) error {
 
 	return nil
 }
+
+func validateMatchFields(requirements []v1.NodeSelectorRequirement) error {
+	for _, req := range requirements {
+		if !utilimagejob.SupportedNodeFieldKeys.Has(req.Key) {
+			return fmt.Errorf("unsupported key %s in selector matchFields, supported keys are %v", req.Key, utilimagejob.SupportedNodeFieldKeys.List())
+		}
+		if req.Operator != v1.NodeSelectorOpIn && req.Operator != v1.NodeSelectorOpNotIn {
+			return fmt.Errorf("unsupported operator %s in selector matchFields, only In and NotIn are supported", req.Operator)
+		}
+		if len(req.Values) == 0 {
+			return fmt.Errorf("values of %s in selector matchFields can not be empty", req.Key)
+		}
+	}
+	return nil
+}
+
+func validateImages(image string, images []string) error {
+	if len(image) == 0 && len(images) == 0 {
+		return fmt.Errorf("image can not be empty")
+	}
+	if len(image) > 0 && len(images) > 0 {
+		return fmt.Errorf("can not set both image and images")
+	}
+	if len(image) > 0 {
+		images = []string{image}
+	}
+
+	imageNames := sets.NewString()
+	for _, img := range images {
+		if len(img) == 0 {
+			return fmt.Errorf("image in images can not be empty")
+		}
+		imageName, imageTag, err := daemonutil.NormalizeImageRefToNameTag(img)
+		if err != nil {
+			return fmt.Errorf("invalid image %s: %v", img, err)
+		}
+		if imageNames.Has(imageName + ":" + imageTag) {
+			return fmt.Errorf("duplicated image %s in images", img)
+		}
+		imageNames.Insert(imageName + ":" + imageTag)
+	}
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"strings"
 	"testing"
 
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
@@ -219,3 +220,314 @@ func TestValidateParallelismV1beta1(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateImagesV1beta1(t *testing.T) {
+	tests := []struct {
+		name        string
+		image       string
+		images      []string
+		expectError bool
+		errorMsg    string
+	}{
+		{
+			name:  "valid single image",
+			image: "nginx:latest",
+		},
+		{
+			name:   "valid multiple images",
+			images: []string{"nginx:latest", "redis:6", "busybox"},
+		},
+		{
+			name:        "neither image nor images",
+			expectError: true,
+			errorMsg:    "image can not be empty",
+		},
+		{
+			name:        "both image and images",
+			image:       "nginx:latest",
+			images:      []string{"redis:6"},
+			expectError: true,
+			errorMsg:    "can not set both image and images",
+		},
+		{
+			name:        "empty image in images",
+			images:      []string{"nginx:latest", ""},
+			expectError: true,
+			errorMsg:    "image in images can not be empty",
+		},
+		{
+			name:        "invalid image in images",
+			images:      []string{"nginx:latest", "invalid image!!!"},
+			expectError: true,
+			errorMsg:    "invalid image",
+		},
+		{
+			name:        "duplicated images",
+			images:      []string{"nginx", "docker.io/library/nginx:latest"},
+			expectError: true,
+			errorMsg:    "duplicated image",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &appsv1beta1.ImagePullJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-job",
+					Namespace: "default",
+				},
+				Spec: appsv1beta1.ImagePullJobSpec{
+					Image:  tt.image,
+					Images: tt.images,
+					ImagePullJobTemplate: appsv1beta1.ImagePullJobTemplate{
+						CompletionPolicy: appsv1beta1.CompletionPolicy{
+							Type: appsv1beta1.Always,
+						},
+					},
+				},
+			}
+
+			err := validateV1beta1(obj)
+
+			hasError := err != nil
+			if hasError != tt.expectError {
+				t.Errorf("expected error: %v, got error: %v, error: %v", tt.expectError, hasError, err)
+				return
+			}
+
+			if tt.expectError && err != nil {
+				if !strings.Contains(err.Error(), tt.errorMsg) {
+					t.Errorf("expected error message containing '%s', got: %v", tt.errorMsg, err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateServiceAccountNameV1beta1(t *testing.T) {
+	tests := []struct {
+		name               string
+		serviceAccountName string
+		expectError        bool
+	}{
+		{
+			name: "empty serviceAccountName",
+		},
+		{
+			name:               "valid serviceAccountName",
+			serviceAccountName: "image-puller",
+		},
+		{
+			name:               "invalid serviceAccountName",
+			serviceAccountName: "Image_Puller",
+			expectError:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &appsv1beta1.ImagePullJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-job",
+					Namespace: "default",
+				},
+				Spec: appsv1beta1.ImagePullJobSpec{
+					Image: "nginx:latest",
+					ImagePullJobTemplate: appsv1beta1.ImagePullJobTemplate{
+						ServiceAccountName: tt.serviceAccountName,
+						CompletionPolicy: appsv1beta1.CompletionPolicy{
+							Type: appsv1beta1.Always,
+						},
+					},
+				},
+			}
+
+			err := validateV1beta1(obj)
+			if hasError := err != nil; hasError != tt.expectError {
+				t.Errorf("expected error: %v, got error: %v", tt.expectError, err)
+			}
+			if tt.expectError && err != nil && !strings.Contains(err.Error(), "invalid serviceAccountName") {
+				t.Errorf("unexpected error message: %v", err)
+			}
+		})
+	}
+}
+
+func TestValidatePullPolicyV1beta1(t *testing.T) {
+	tests := []struct {
+		name          string
+		pullPolicy    *appsv1beta1.PullPolicy
+		expectedError string
+	}{
+		{
+			name: "nil pullPolicy",
+		},
+		{
+			name: "valid pullPolicy",
+			pullPolicy: &appsv1beta1.PullPolicy{
+				BackoffLimit:              ptr.To[int32](5),
+				MaxConcurrentPullsPerNode: ptr.To[int32](1),
+			},
+		},
+		{
+			name:          "negative backoffLimit",
+			pullPolicy:    &appsv1beta1.PullPolicy{BackoffLimit: ptr.To[int32](-1)},
+			expectedError: "pullPolicy.backoffLimit must be non-negative",
+		},
+		{
+			name:          "zero maxConcurrentPullsPerNode",
+			pullPolicy:    &appsv1beta1.PullPolicy{MaxConcurrentPullsPerNode: ptr.To[int32](0)},
+			expectedError: "pullPolicy.maxConcurrentPullsPerNode must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &appsv1beta1.ImagePullJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-job",
+					Namespace: "default",
+				},
+				Spec: appsv1beta1.ImagePullJobSpec{
+					Image: "nginx:latest",
+					ImagePullJobTemplate: appsv1beta1.ImagePullJobTemplate{
+						PullPolicy: tt.pullPolicy,
+						CompletionPolicy: appsv1beta1.CompletionPolicy{
+							Type: appsv1beta1.Always,
+						},
+					},
+				},
+			}
+
+			err := validateV1beta1(obj)
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.expectedError {
+				t.Errorf("expected error %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestValidatePlatformV1beta1(t *testing.T) {
+	tests := []struct {
+		name          string
+		platform      *appsv1beta1.ImagePlatform
+		expectedError string
+	}{
+		{
+			name: "nil platform",
+		},
+		{
+			name:     "valid platform",
+			platform: &appsv1beta1.ImagePlatform{OS: "linux", Architecture: "arm64", Variant: "v8"},
+		},
+		{
+			name:          "empty architecture",
+			platform:      &appsv1beta1.ImagePlatform{OS: "linux"},
+			expectedError: "platform.architecture and platform.os can not be empty",
+		},
+		{
+			name:          "empty os",
+			platform:      &appsv1beta1.ImagePlatform{Architecture: "amd64"},
+			expectedError: "platform.architecture and platform.os can not be empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &appsv1beta1.ImagePullJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-job",
+					Namespace: "default",
+				},
+				Spec: appsv1beta1.ImagePullJobSpec{
+					Image: "nginx:latest",
+					ImagePullJobTemplate: appsv1beta1.ImagePullJobTemplate{
+						Platform: tt.platform,
+						CompletionPolicy: appsv1beta1.CompletionPolicy{
+							Type: appsv1beta1.Always,
+						},
+					},
+				},
+			}
+
+			err := validateV1beta1(obj)
+			if tt.expectedError == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.expectedError {
+				t.Errorf("expected error %q, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestValidateSelectorMatchFieldsV1beta1(t *testing.T) {
+	tests := []struct {
+		name        string
+		matchFields []v1.NodeSelectorRequirement
+		expectError bool
+	}{
+		{
+			name: "valid architecture",
+			matchFields: []v1.NodeSelectorRequirement{
+				{Key: "status.nodeInfo.architecture", Operator: v1.NodeSelectorOpIn, Values: []string{"arm64"}},
+				{Key: "metadata.name", Operator: v1.NodeSelectorOpNotIn, Values: []string{"node-1"}},
+			},
+		},
+		{
+			name: "unsupported key",
+			matchFields: []v1.NodeSelectorRequirement{
+				{Key: "spec.podCIDR", Operator: v1.NodeSelectorOpIn, Values: []string{"10.0.0.0/24"}},
+			},
+			expectError: true,
+		},
+		{
+			name: "unsupported operator",
+			matchFields: []v1.NodeSelectorRequirement{
+				{Key: "status.nodeInfo.architecture", Operator: v1.NodeSelectorOpExists},
+			},
+			expectError: true,
+		},
+		{
+			name: "empty values",
+			matchFields: []v1.NodeSelectorRequirement{
+				{Key: "status.nodeInfo.operatingSystem", Operator: v1.NodeSelectorOpIn},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &appsv1beta1.ImagePullJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-job",
+					Namespace: "default",
+				},
+				Spec: appsv1beta1.ImagePullJobSpec{
+					Image: "nginx:latest",
+					ImagePullJobTemplate: appsv1beta1.ImagePullJobTemplate{
+						Selector: &appsv1beta1.ImagePullJobNodeSelector{MatchFields: tt.matchFields},
+						CompletionPolicy: appsv1beta1.CompletionPolicy{
+							Type: appsv1beta1.Always,
+						},
+					},
+				},
+			}
+
+			err := validateV1beta1(obj)
+			if hasError := err != nil; hasError != tt.expectError {
+				t.Errorf("expected error: %v, got error: %v", tt.expectError, err)
+			}
+		})
+	}
+}
@@ -115,16 +115,75 @@ func validatePersistentPodStateSpec(obj *appsv1alpha1.PersistentPodState, fldPat
 
 	apiVersion, kind := spec.TargetReference.APIVersion, spec.TargetReference.Kind
 	if !whiteList.ValidateAPIVersionAndKind(apiVersion, kind) {
-		allErrs = append(allErrs, field.Invalid(fldPath.Child("TargetReference"), spec.TargetReference, "TargetReference.Kind must be StatefulSet or in PPS_Watch_Custom_Workload_WhiteList"))
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("TargetReference"), spec.TargetReference, "TargetReference.Kind must be StatefulSet, CloneSet or in PPS_Watch_Custom_Workload_WhiteList"))
 	}
 
-	if spec.RequiredPersistentTopology == nil && len(spec.PreferredPersistentTopology) == 0 {
+	if spec.RequiredPersistentTopology == nil && len(spec.PreferredPersistentTopology) == 0 && len(spec.PersistentTopologies) == 0 {
 		allErrs = append(allErrs, field.Invalid(fldPath, spec, "TopologyConstraint and TopologyPreference cannot be empty at the same time"))
 	}
+	allErrs = append(allErrs, validatePersistentTopologies(spec, fldPath.Child("persistentTopologies"))...)
+	allErrs = append(allErrs, validatePersistentPodStateRetention(spec.PersistentPodStateRetention, fldPath.Child("persistentPodStateRetention"))...)
 
 	return allErrs
 }
 
+func validatePersistentPodStateRetention(retention *appsv1alpha1.PersistentPodStateRetention, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if retention == nil {
+		return allErrs
+	}
+	if retention.TTLSecondsAfterDeleted != nil && *retention.TTLSecondsAfterDeleted < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("ttlSecondsAfterDeleted"), *retention.TTLSecondsAfterDeleted, "must be greater than or equal to 0"))
+	}
+	if retention.MaxEntries != nil && *retention.MaxEntries < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxEntries"), *retention.MaxEntries, "must be greater than or equal to 0"))
+	}
+	return allErrs
+}
+
+func validatePersistentTopologies(spec *appsv1alpha1.PersistentPodStateSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(spec.PersistentTopologies) == 0 {
+		return allErrs
+	}
+	hasRequired := false
+	for i, item := range spec.PersistentTopologies {
+		idxPath := fldPath.Index(i)
+		if len(item.NodeTopologyKeys) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("nodeTopologyKeys"), "nodeTopologyKeys cannot be empty"))
+		}
+		for j, key := range item.NodeTopologyKeys {
+			if key == "" {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("nodeTopologyKeys").Index(j), key, "nodeTopologyKey cannot be empty"))
+			}
+		}
+		switch item.Type {
+		case appsv1alpha1.PersistentTopologyRequired:
+			if item.Weight != 0 {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("weight"), item.Weight, "weight cannot be set for Required term"))
+			}
+			if len(item.NodeTopologyKeys) > 0 {
+				hasRequired = true
+			}
+		case appsv1alpha1.PersistentTopologyPreferred:
+			if item.Weight < 0 || item.Weight > 100 {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("weight"), item.Weight, "weight must be in the range 1-100"))
+			}
+		default:
+			allErrs = append(allErrs, field.NotSupported(idxPath.Child("type"), item.Type,
+				[]string{string(appsv1alpha1.PersistentTopologyRequired), string(appsv1alpha1.PersistentTopologyPreferred)}))
+		}
+	}
+	// with WhenScaled policy the pod states are kept for the rebuilt pods only to pin them to their topologies,
+	// which preferred terms alone cannot guarantee
+	if !hasRequired && spec.RequiredPersistentTopology == nil &&
+		spec.PersistentPodStateRetentionPolicy != appsv1alpha1.PersistentPodStateRetentionPolicyWhenDeleted {
+		allErrs = append(allErrs, field.Invalid(fldPath, spec.PersistentTopologies,
+			"at least one Required term is needed when persistentPodStateRetentionPolicy is WhenScaled"))
+	}
+	return allErrs
+}
+
 func validatePerConflict(pps *appsv1alpha1.PersistentPodState, others []appsv1alpha1.PersistentPodState, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
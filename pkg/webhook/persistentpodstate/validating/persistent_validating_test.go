@@ -24,6 +24,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -118,6 +119,82 @@ func TestValidatingPer(t *testing.T) {
 			},
 			expectErrList: 1,
 		},
+		{
+			name: "valid per, required zone and preferred node in persistentTopologies",
+			per: func() *appsv1alpha1.PersistentPodState {
+				pps := ppsDemo.DeepCopy()
+				pps.Spec.RequiredPersistentTopology = nil
+				pps.Spec.PreferredPersistentTopology = nil
+				pps.Spec.PersistentTopologies = []appsv1alpha1.PersistentTopologyTerm{
+					{NodeTopologyKeys: []string{podStateZoneTopologyLabel}, Type: appsv1alpha1.PersistentTopologyRequired},
+					{NodeTopologyKeys: []string{podStateNodeTopologyLabel}, Type: appsv1alpha1.PersistentTopologyPreferred, Weight: 50},
+				}
+				return pps
+			},
+			expectErrList: 0,
+		},
+		{
+			name: "invalid per, persistentTopologies with invalid type, weight and empty keys",
+			per: func() *appsv1alpha1.PersistentPodState {
+				pps := ppsDemo.DeepCopy()
+				pps.Spec.PersistentTopologies = []appsv1alpha1.PersistentTopologyTerm{
+					{NodeTopologyKeys: []string{podStateZoneTopologyLabel}, Type: "Unknown"},
+					{NodeTopologyKeys: []string{podStateZoneTopologyLabel}, Type: appsv1alpha1.PersistentTopologyRequired, Weight: 10},
+					{Type: appsv1alpha1.PersistentTopologyPreferred, Weight: 101},
+				}
+				return pps
+			},
+			expectErrList: 4,
+		},
+		{
+			name: "invalid per, persistentTopologies without required term for WhenScaled",
+			per: func() *appsv1alpha1.PersistentPodState {
+				pps := ppsDemo.DeepCopy()
+				pps.Spec.RequiredPersistentTopology = nil
+				pps.Spec.PersistentTopologies = []appsv1alpha1.PersistentTopologyTerm{
+					{NodeTopologyKeys: []string{podStateNodeTopologyLabel}, Type: appsv1alpha1.PersistentTopologyPreferred},
+				}
+				return pps
+			},
+			expectErrList: 1,
+		},
+		{
+			name: "valid per, persistentTopologies without required term for WhenDeleted",
+			per: func() *appsv1alpha1.PersistentPodState {
+				pps := ppsDemo.DeepCopy()
+				pps.Spec.RequiredPersistentTopology = nil
+				pps.Spec.PersistentPodStateRetentionPolicy = appsv1alpha1.PersistentPodStateRetentionPolicyWhenDeleted
+				pps.Spec.PersistentTopologies = []appsv1alpha1.PersistentTopologyTerm{
+					{NodeTopologyKeys: []string{podStateNodeTopologyLabel}, Type: appsv1alpha1.PersistentTopologyPreferred},
+				}
+				return pps
+			},
+			expectErrList: 0,
+		},
+		{
+			name: "valid per, persistentPodStateRetention",
+			per: func() *appsv1alpha1.PersistentPodState {
+				pps := ppsDemo.DeepCopy()
+				pps.Spec.PersistentPodStateRetention = &appsv1alpha1.PersistentPodStateRetention{
+					TTLSecondsAfterDeleted: ptr.To[int32](3600),
+					MaxEntries:             ptr.To[int32](0),
+				}
+				return pps
+			},
+			expectErrList: 0,
+		},
+		{
+			name: "invalid per, negative persistentPodStateRetention",
+			per: func() *appsv1alpha1.PersistentPodState {
+				pps := ppsDemo.DeepCopy()
+				pps.Spec.PersistentPodStateRetention = &appsv1alpha1.PersistentPodStateRetention{
+					TTLSecondsAfterDeleted: ptr.To[int32](-1),
+					MaxEntries:             ptr.To[int32](-1),
+				}
+				return pps
+			},
+			expectErrList: 2,
+		},
 	}
 
 	decoder := admission.NewDecoder(scheme)
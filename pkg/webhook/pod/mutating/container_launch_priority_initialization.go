@@ -2,6 +2,8 @@ package mutating
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"strconv"
 
 	admissionv1 "k8s.io/api/admission/v1"
@@ -11,6 +13,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	appspub "github.com/openkruise/kruise/apis/apps/pub"
+	"github.com/openkruise/kruise/pkg/util"
 	utilcontainerlaunchpriority "github.com/openkruise/kruise/pkg/util/containerlaunchpriority"
 )
 
@@ -22,23 +25,32 @@ func (h *PodCreateHandler) containerLaunchPriorityInitialization(_ context.Conte
 		return true, nil
 	}
 
-	if len(pod.Spec.Containers) == 1 {
+	// priority is only supported on the restartable init containers, which keep running with the containers
+	for i := range pod.Spec.InitContainers {
+		c := &pod.Spec.InitContainers[i]
+		if !util.IsRestartableInitContainer(c) && hasLaunchPriorityEnv(c) {
+			return false, fmt.Errorf("%s is not supported on init container %s which is not restartable", appspub.ContainerLaunchPriorityEnvName, c.Name)
+		}
+	}
+
+	containers := launchContainers(pod)
+	if len(containers) == 1 {
 		return true, nil
 	}
 
 	// if ordered flag has been set, then just process ordered logic and skip check for priority
 	if pod.Annotations[appspub.ContainerLaunchPriorityKey] == appspub.ContainerLaunchOrdered {
-		priority := make([]int, len(pod.Spec.Containers))
+		priority := make([]int, len(containers))
 		for i := range priority {
 			priority[i] = 0 - i
 		}
-		h.setPodEnv(priority, pod)
+		h.setPodEnv(priority, pod, containers)
 		klog.V(3).InfoS("Injected ordered container launch priority for Pod", "namespace", pod.Namespace, "name", pod.Name)
 		return false, nil
 	}
 
 	// check whether containers have KRUISE_CONTAINER_PRIORITY key value pairs
-	priority, priorityFlag, err := h.getPriority(pod)
+	priority, priorityFlag, err := h.getPriority(containers)
 	if err != nil {
 		return false, err
 	}
@@ -46,19 +58,91 @@ func (h *PodCreateHandler) containerLaunchPriorityInitialization(_ context.Conte
 		return true, nil
 	}
 
-	h.setPodEnv(priority, pod)
+	// kubelet starts the init containers one by one before the containers, so the restartable init containers
+	// are reordered by their priorities, and must not wait for the containers
+	if err = sortRestartableInitContainers(pod, containers, priority); err != nil {
+		return false, err
+	}
+	containers = launchContainers(pod)
+	if priority, _, err = h.getPriority(containers); err != nil {
+		return false, err
+	}
+
+	h.setPodEnv(priority, pod, containers)
 	klog.V(3).InfoS("Injected customized container launch priority for Pod", "namespace", pod.Namespace, "name", pod.Name)
 	return false, nil
 }
 
-// the return []int is priority for each container in the pod, ordered as container
-// order list in pod spec.
+// launchContainers returns the containers launched by priority, which are the restartable init containers
+// followed by the containers, in the order kubelet starts them.
+func launchContainers(pod *corev1.Pod) []*corev1.Container {
+	var containers []*corev1.Container
+	for i := range pod.Spec.InitContainers {
+		if util.IsRestartableInitContainer(&pod.Spec.InitContainers[i]) {
+			containers = append(containers, &pod.Spec.InitContainers[i])
+		}
+	}
+	for i := range pod.Spec.Containers {
+		containers = append(containers, &pod.Spec.Containers[i])
+	}
+	return containers
+}
+
+func hasLaunchPriorityEnv(c *corev1.Container) bool {
+	for _, e := range c.Env {
+		if e.Name == appspub.ContainerLaunchPriorityEnvName {
+			return true
+		}
+	}
+	return false
+}
+
+// sortRestartableInitContainers sorts the restartable init containers by priority in descending order, within the
+// positions of them so that the other init containers are kept in place. It returns error if any of them has lower
+// priority than a container, which would never start as kubelet starts the containers after all the init containers.
+func sortRestartableInitContainers(pod *corev1.Pod, containers []*corev1.Container, priority []int) error {
+	var indexes []int
+	for i := range pod.Spec.InitContainers {
+		if util.IsRestartableInitContainer(&pod.Spec.InitContainers[i]) {
+			indexes = append(indexes, i)
+		}
+	}
+	if len(indexes) == 0 {
+		return nil
+	}
+	sidecarPriority := priority[:len(indexes)]
+	for i := len(indexes); i < len(containers); i++ {
+		for j, p := range sidecarPriority {
+			if p < priority[i] {
+				return fmt.Errorf("restartable init container %s must not have lower priority than container %s", containers[j].Name, containers[i].Name)
+			}
+		}
+	}
+
+	order := make([]int, len(indexes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return sidecarPriority[order[i]] > sidecarPriority[order[j]]
+	})
+	sorted := make([]corev1.Container, len(indexes))
+	for i, o := range order {
+		sorted[i] = pod.Spec.InitContainers[indexes[o]]
+	}
+	for i, index := range indexes {
+		pod.Spec.InitContainers[index] = sorted[i]
+	}
+	return nil
+}
+
+// the return []int is priority for each container in the given order.
 // the priorityFlag indicates whether this pod needs to launch containers with priority.
 // return error is there is any (e.g. priority value less than minimum possible int value)
-func (h *PodCreateHandler) getPriority(pod *corev1.Pod) ([]int, bool, error) {
+func (h *PodCreateHandler) getPriority(containers []*corev1.Container) ([]int, bool, error) {
 	var priorityFlag bool
-	var priority = make([]int, len(pod.Spec.Containers))
-	for i, c := range pod.Spec.Containers {
+	var priority = make([]int, len(containers))
+	for i, c := range containers {
 		for _, e := range c.Env {
 			if e.Name == appspub.ContainerLaunchPriorityEnvName {
 				p, err := strconv.Atoi(e.Value)
@@ -86,12 +170,12 @@ func (h *PodCreateHandler) getPriority(pod *corev1.Pod) ([]int, bool, error) {
 	return priority, priorityFlag, nil
 }
 
-func (h *PodCreateHandler) setPodEnv(priority []int, pod *corev1.Pod) {
+func (h *PodCreateHandler) setPodEnv(priority []int, pod *corev1.Pod, containers []*corev1.Container) {
 	// Generate name for pods that only have generateName field
 	if len(pod.Name) == 0 && len(pod.GenerateName) > 0 {
 		pod.Name = storagenames.SimpleNameGenerator.GenerateName(pod.GenerateName)
 	}
 	for i := range priority {
-		pod.Spec.Containers[i].Env = append(pod.Spec.Containers[i].Env, utilcontainerlaunchpriority.GeneratePriorityEnv(priority[i], pod.Name))
+		containers[i].Env = append(containers[i].Env, utilcontainerlaunchpriority.GeneratePriorityEnv(priority[i], pod.Name))
 	}
 }
@@ -99,3 +99,133 @@ func TestContainerLaunchPriorityInitialization(t *testing.T) {
 		})
 	}
 }
+
+func TestContainerLaunchPriorityInitializationWithSidecars(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	priorityEnv := func(p string) []corev1.EnvVar {
+		return []corev1.EnvVar{{Name: appspub.ContainerLaunchPriorityEnvName, Value: p}}
+	}
+	withBarrier := func(env []corev1.EnvVar, p int) []corev1.EnvVar {
+		return append(env, utilcontainerlaunchpriority.GeneratePriorityEnv(p, "fake"))
+	}
+
+	cases := []struct {
+		name                   string
+		pod                    *corev1.Pod
+		expectedErr            bool
+		expectedInitContainers []corev1.Container
+		expectedContainers     []corev1.Container
+	}{
+		{
+			name: "ordered",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "fake",
+					Annotations: map[string]string{appspub.ContainerLaunchPriorityKey: appspub.ContainerLaunchOrdered},
+				},
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{
+						{Name: "init"},
+						{Name: "sidecar", RestartPolicy: &always},
+					},
+					Containers: []corev1.Container{
+						{Name: "a"},
+					},
+				},
+			},
+			expectedInitContainers: []corev1.Container{
+				{Name: "init"},
+				{Name: "sidecar", RestartPolicy: &always, Env: []corev1.EnvVar{utilcontainerlaunchpriority.GeneratePriorityEnv(0, "fake")}},
+			},
+			expectedContainers: []corev1.Container{
+				{Name: "a", Env: []corev1.EnvVar{utilcontainerlaunchpriority.GeneratePriorityEnv(-1, "fake")}},
+			},
+		},
+		{
+			name: "sidecars reordered by priority",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "fake"},
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{
+						{Name: "sidecar-1", RestartPolicy: &always, Env: priorityEnv("5")},
+						{Name: "init"},
+						{Name: "sidecar-2", RestartPolicy: &always, Env: priorityEnv("10")},
+					},
+					Containers: []corev1.Container{
+						{Name: "a", Env: priorityEnv("1")},
+						{Name: "b"},
+					},
+				},
+			},
+			expectedInitContainers: []corev1.Container{
+				{Name: "sidecar-2", RestartPolicy: &always, Env: withBarrier(priorityEnv("10"), 10)},
+				{Name: "init"},
+				{Name: "sidecar-1", RestartPolicy: &always, Env: withBarrier(priorityEnv("5"), 5)},
+			},
+			expectedContainers: []corev1.Container{
+				{Name: "a", Env: withBarrier(priorityEnv("1"), 1)},
+				{Name: "b", Env: []corev1.EnvVar{utilcontainerlaunchpriority.GeneratePriorityEnv(0, "fake")}},
+			},
+		},
+		{
+			name: "sidecar of lower priority than container",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "fake"},
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{
+						{Name: "sidecar", RestartPolicy: &always, Env: priorityEnv("1")},
+					},
+					Containers: []corev1.Container{
+						{Name: "a", Env: priorityEnv("5")},
+					},
+				},
+			},
+			expectedErr: true,
+		},
+		{
+			name: "priority on init container not restartable",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "fake"},
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{
+						{Name: "init", Env: priorityEnv("5")},
+					},
+					Containers: []corev1.Container{
+						{Name: "a", Env: priorityEnv("1")},
+						{Name: "b"},
+					},
+				},
+			},
+			expectedErr: true,
+		},
+	}
+
+	h := &PodCreateHandler{}
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		Operation: admissionv1.Create,
+		Resource:  metav1.GroupVersionResource{Resource: "pods", Version: "v1"},
+	}}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			skip, err := h.containerLaunchPriorityInitialization(context.TODO(), req, tc.pod)
+			if tc.expectedErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if skip {
+				t.Fatalf("expected not skipped")
+			}
+			if !reflect.DeepEqual(tc.expectedInitContainers, tc.pod.Spec.InitContainers) {
+				t.Fatalf("expected init containers\n%v\ngot\n%v", util.DumpJSON(tc.expectedInitContainers), util.DumpJSON(tc.pod.Spec.InitContainers))
+			}
+			if !reflect.DeepEqual(tc.expectedContainers, tc.pod.Spec.Containers) {
+				t.Fatalf("expected containers\n%v\ngot\n%v", util.DumpJSON(tc.expectedContainers), util.DumpJSON(tc.pod.Spec.Containers))
+			}
+		})
+	}
+}
@@ -69,13 +69,13 @@ func (h *PodCreateHandler) persistentPodStateMutatingPod(ctx context.Context, re
 	}
 
 	// inject PersistentPodState node affinity in pod
-	nodeSelector, preference := createNodeAffinity(persistentPodState.Spec, podState)
-	if len(nodeSelector) == 0 && len(preference) == 0 {
+	nodeSelector, requirements, preference := createNodeAffinity(persistentPodState.Spec, podState)
+	if len(nodeSelector) == 0 && len(requirements) == 0 && len(preference) == 0 {
 		return true, nil
 	}
 
-	klog.V(3).InfoS("inject node affinity in pod for PersistentPodState",
-		"required", util.DumpJSON(nodeSelector), "preferred", util.DumpJSON(preference), "namespace", pod.Namespace, "name", pod.Name)
+	klog.V(3).InfoS("inject node affinity in pod for PersistentPodState", "required", util.DumpJSON(nodeSelector),
+		"requiredAffinity", util.DumpJSON(requirements), "preferred", util.DumpJSON(preference), "namespace", pod.Namespace, "name", pod.Name)
 
 	// inject persistentPodState annotation in pod
 	if pod.Annotations == nil {
@@ -94,6 +94,27 @@ func (h *PodCreateHandler) persistentPodStateMutatingPod(ctx context.Context, re
 		}
 	}
 
+	// required node affinity
+	if len(requirements) > 0 {
+		if pod.Spec.Affinity == nil {
+			pod.Spec.Affinity = &corev1.Affinity{}
+		}
+		if pod.Spec.Affinity.NodeAffinity == nil {
+			pod.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+		}
+		if pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+			pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+		}
+		required := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+		if len(required.NodeSelectorTerms) == 0 {
+			required.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+		}
+		// node selector terms are ORed, so the requirements are added into each of them
+		for i := range required.NodeSelectorTerms {
+			required.NodeSelectorTerms[i].MatchExpressions = append(required.NodeSelectorTerms[i].MatchExpressions, requirements...)
+		}
+	}
+
 	// preferences
 	if len(preference) > 0 {
 		if pod.Spec.Affinity == nil {
@@ -108,10 +129,11 @@ func (h *PodCreateHandler) persistentPodStateMutatingPod(ctx context.Context, re
 	return false, nil
 }
 
-// return two parameters:
+// return three parameters:
 // 1. required nodeSelector
-// 2. preferred []PreferredSchedulingTerm
-func createNodeAffinity(spec appsv1alpha1.PersistentPodStateSpec, podState appsv1alpha1.PodState) (map[string]string, []corev1.PreferredSchedulingTerm) {
+// 2. required []NodeSelectorRequirement of node affinity
+// 3. preferred []PreferredSchedulingTerm
+func createNodeAffinity(spec appsv1alpha1.PersistentPodStateSpec, podState appsv1alpha1.PodState) (map[string]string, []corev1.NodeSelectorRequirement, []corev1.PreferredSchedulingTerm) {
 	// required
 	var nodeSelector map[string]string
 	if spec.RequiredPersistentTopology != nil {
@@ -142,7 +164,37 @@ func createNodeAffinity(spec appsv1alpha1.PersistentPodStateSpec, podState appsv
 		preferences = append(preferences, preference)
 	}
 
-	return nodeSelector, preferences
+	// persistent topologies
+	var requirements []corev1.NodeSelectorRequirement
+	for _, item := range spec.PersistentTopologies {
+		var terms []corev1.NodeSelectorRequirement
+		for _, key := range item.NodeTopologyKeys {
+			if value, ok := podState.NodeTopologyLabels[key]; ok {
+				terms = append(terms, corev1.NodeSelectorRequirement{
+					Key:      key,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{value},
+				})
+			}
+		}
+		if len(terms) == 0 {
+			continue
+		}
+		if item.Type == appsv1alpha1.PersistentTopologyRequired {
+			requirements = append(requirements, terms...)
+			continue
+		}
+		weight := item.Weight
+		if weight == 0 {
+			weight = 100
+		}
+		preferences = append(preferences, corev1.PreferredSchedulingTerm{
+			Weight:     weight,
+			Preference: corev1.NodeSelectorTerm{MatchExpressions: terms},
+		})
+	}
+
+	return nodeSelector, requirements, preferences
 }
 
 func SelectorPersistentPodState(reader client.Reader, ref appsv1alpha1.TargetReference, ns string) *appsv1alpha1.PersistentPodState {
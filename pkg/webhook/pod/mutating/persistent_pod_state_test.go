@@ -200,6 +200,81 @@ func TestPersistentPodStateMutatingPod(t *testing.T) {
 				return demo
 			},
 		},
+		{
+			name: "matched PersistentPodState, and required zone, preferred node in persistentTopologies",
+			getPod: func() *corev1.Pod {
+				demo := podDemo.DeepCopy()
+				return demo
+			},
+			getPodState: func() *appsv1alpha1.PersistentPodState {
+				pps := ppsDemo.DeepCopy()
+				pps.Spec.RequiredPersistentTopology = nil
+				pps.Spec.PreferredPersistentTopology = nil
+				pps.Spec.PersistentTopologies = []appsv1alpha1.PersistentTopologyTerm{
+					{
+						NodeTopologyKeys: []string{RequiredPodStateNodeAffinityAZLabels},
+						Type:             appsv1alpha1.PersistentTopologyRequired,
+					},
+					{
+						NodeTopologyKeys: []string{PreferredPodStateNodeAffinityAZLabels},
+						Type:             appsv1alpha1.PersistentTopologyPreferred,
+					},
+				}
+				return pps
+			},
+			exceptPod: func() *corev1.Pod {
+				demo := podDemo.DeepCopy()
+				demo.Annotations[InjectedPersistentPodStateKey] = ppsDemo.Name
+				demo.Spec.Affinity = &corev1.Affinity{
+					NodeAffinity: &corev1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+							NodeSelectorTerms: []corev1.NodeSelectorTerm{
+								{
+									MatchExpressions: []corev1.NodeSelectorRequirement{
+										{
+											Key:      "node/gpu",
+											Operator: corev1.NodeSelectorOpExists,
+										},
+										{
+											Key:      RequiredPodStateNodeAffinityAZLabels,
+											Operator: corev1.NodeSelectorOpIn,
+											Values:   []string{"cn-beijing-a"},
+										},
+									},
+								},
+							},
+						},
+						PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{
+							{
+								Weight: 5,
+								Preference: corev1.NodeSelectorTerm{
+									MatchExpressions: []corev1.NodeSelectorRequirement{
+										{
+											Key:      "test-key",
+											Operator: corev1.NodeSelectorOpExists,
+										},
+									},
+								},
+							},
+							{
+								Weight: 100,
+								Preference: corev1.NodeSelectorTerm{
+									MatchExpressions: []corev1.NodeSelectorRequirement{
+										{
+											Key:      PreferredPodStateNodeAffinityAZLabels,
+											Operator: corev1.NodeSelectorOpIn,
+											Values:   []string{"kube-resource011162007216"},
+										},
+									},
+								},
+							},
+						},
+					},
+				}
+
+				return demo
+			},
+		},
 		{
 			name: "no matched PersistentPodState",
 			getPod: func() *corev1.Pod {
@@ -22,6 +22,7 @@ import (
 	"net/http"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -39,6 +40,9 @@ type PodCreateHandler struct {
 
 	// Decoder decodes objects
 	Decoder admission.Decoder
+
+	// EventRecorder records events of the skipped injection of SidecarSets
+	EventRecorder record.EventRecorder
 }
 
 var _ admission.Handler = &PodCreateHandler{}
@@ -142,6 +142,22 @@ func (h *PodCreateHandler) sidecarsetMutatingPod(ctx context.Context, req admiss
 		pod.Annotations = make(map[string]string)
 	}
 	skip = true
+	// limit the total footprint of the sidecar containers injected into the pod
+	footprintLimits, err := h.getSidecarSetFootprintLimits(ctx, podNamespace)
+	if err != nil {
+		return false, err
+	}
+	sidecarSets, footprintChanged := applySidecarSetFootprintLimits(pod, isUpdated, sidecarSets, footprintLimits, h.EventRecorder)
+	if footprintChanged {
+		skip = false
+	}
+	// guard the Guaranteed pod from being downgraded by the sidecar containers
+	sidecarSets, qosChanged, err := applySidecarSetQoSPolicies(pod, isUpdated, sidecarSets)
+	if err != nil {
+		return false, err
+	} else if qosChanged {
+		skip = false
+	}
 	for _, control := range sidecarSets {
 		sidecarSet := control.GetSidecarset()
 		sk, err := sidecarcontrol.PatchPodMetadata(&pod.ObjectMeta, sidecarSet.Spec.PatchPodMetadata)
@@ -401,6 +417,8 @@ func buildSidecars(isUpdated bool, pod *corev1.Pod, oldPod *corev1.Pod, matchedS
 			}
 		}
 	}
+	// sidecarSet.name -> config hash states of sidecar containers
+	sidecarConfigHashStates := sidecarcontrol.GetPodSidecarConfigHashStates(pod)
 	// hotUpgrade work info, sidecarSet.spec.container[x].name -> pod.spec.container[x].name
 	// for example: mesh -> mesh-1, envoy -> envoy-2
 	hotUpgradeWorkInfo := sidecarcontrol.GetPodHotUpgradeInfoInAnnotations(pod)
@@ -586,6 +604,14 @@ func buildSidecars(isUpdated bool, pod *corev1.Pod, oldPod *corev1.Pod, matchedS
 			setUpgrade2.SidecarList = sidecarList.List()
 			sidecarSetHash[sidecarSet.Name] = setUpgrade1
 			sidecarSetHashWithoutImage[sidecarSet.Name] = setUpgrade2
+			// the injected containers are running with the current config
+			if configHashes := sidecarcontrol.GetSidecarSetConfigHashes(sidecarSet); len(configHashes) > 0 {
+				states := make(map[string]sidecarcontrol.SidecarConfigHashState, len(configHashes))
+				for name, hash := range configHashes {
+					states[name] = sidecarcontrol.SidecarConfigHashState{Hash: hash}
+				}
+				sidecarConfigHashStates[sidecarSet.Name] = states
+			}
 		}
 	}
 
@@ -594,6 +620,10 @@ func buildSidecars(isUpdated bool, pod *corev1.Pod, oldPod *corev1.Pod, matchedS
 	injectedAnnotations[sidecarcontrol.SidecarSetHashAnnotation] = string(by)
 	by, _ = json.Marshal(sidecarSetHashWithoutImage)
 	injectedAnnotations[sidecarcontrol.SidecarSetHashWithoutImageAnnotation] = string(by)
+	if len(sidecarConfigHashStates) > 0 {
+		by, _ = json.Marshal(sidecarConfigHashStates)
+		injectedAnnotations[sidecarcontrol.SidecarSetConfigHashAnnotation] = string(by)
+	}
 	sidecarSetNameList := strings.Join(sidecarSetNames.List(), ",")
 	// store matched sidecarset list in pod annotations
 	injectedAnnotations[sidecarcontrol.SidecarSetListAnnotation] = sidecarSetNameList
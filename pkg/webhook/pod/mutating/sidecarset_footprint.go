@@ -0,0 +1,226 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+
+	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
+)
+
+var (
+	// maxInjectedSidecarContainers is the cluster-level max number of sidecar containers injected into a pod, 0 means no limit.
+	maxInjectedSidecarContainers = 0
+	// maxInjectedSidecarResources is the cluster-level max total resource requests of sidecar containers injected into a pod,
+	// in the format of "cpu=1,memory=2Gi", empty means no limit.
+	maxInjectedSidecarResources = ""
+)
+
+func init() {
+	flag.IntVar(&maxInjectedSidecarContainers, "sidecarset-max-injected-containers", maxInjectedSidecarContainers,
+		"The max number of sidecar containers injected into a pod by all SidecarSets, 0 means no limit.")
+	flag.StringVar(&maxInjectedSidecarResources, "sidecarset-max-injected-resources", maxInjectedSidecarResources,
+		"The max total resource requests of sidecar containers injected into a pod by all SidecarSets, e.g. cpu=1,memory=2Gi.")
+}
+
+// sidecarSetFootprintLimits is the max footprint of the sidecar containers injected into a pod.
+type sidecarSetFootprintLimits struct {
+	maxContainers int
+	maxResources  corev1.ResourceList
+}
+
+func (l *sidecarSetFootprintLimits) isEmpty() bool {
+	return l.maxContainers <= 0 && len(l.maxResources) == 0
+}
+
+// getSidecarSetFootprintLimits returns the footprint limits for the pods in the namespace.
+// The limits in the namespace annotations override the cluster-level ones from flags.
+func (h *PodCreateHandler) getSidecarSetFootprintLimits(ctx context.Context, namespace string) (*sidecarSetFootprintLimits, error) {
+	limits := &sidecarSetFootprintLimits{maxContainers: maxInjectedSidecarContainers}
+	if maxInjectedSidecarResources != "" {
+		resources, err := parseResourceList(maxInjectedSidecarResources)
+		if err != nil {
+			klog.ErrorS(err, "Ignored invalid flag sidecarset-max-injected-resources", "value", maxInjectedSidecarResources)
+		} else {
+			limits.maxResources = resources
+		}
+	}
+
+	ns := &corev1.Namespace{}
+	if err := h.Client.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		if errors.IsNotFound(err) {
+			return limits, nil
+		}
+		return nil, err
+	}
+	if value, ok := ns.Annotations[sidecarcontrol.SidecarSetMaxInjectedContainersAnnotation]; ok {
+		maxContainers, err := strconv.Atoi(value)
+		if err != nil {
+			klog.ErrorS(err, "Ignored invalid annotation of namespace", "namespace", namespace, "annotation", sidecarcontrol.SidecarSetMaxInjectedContainersAnnotation, "value", value)
+		} else {
+			limits.maxContainers = maxContainers
+		}
+	}
+	if value, ok := ns.Annotations[sidecarcontrol.SidecarSetMaxInjectedResourcesAnnotation]; ok {
+		resources, err := parseResourceList(value)
+		if err != nil {
+			klog.ErrorS(err, "Ignored invalid annotation of namespace", "namespace", namespace, "annotation", sidecarcontrol.SidecarSetMaxInjectedResourcesAnnotation, "value", value)
+		} else {
+			limits.maxResources = resources
+		}
+	}
+	return limits, nil
+}
+
+// applySidecarSetFootprintLimits evaluates the sidecarSets in the order of their names, and injects them until the
+// footprint limits would be exceeded, then skips the rest. It returns the sidecarSets to be injected, and whether the
+// skipped sidecarSets are recorded in the pod annotations.
+// The sidecarSets skipped when the pod is created are also skipped when it is updated.
+func applySidecarSetFootprintLimits(pod *corev1.Pod, isUpdated bool, sidecarSets []sidecarcontrol.SidecarControl,
+	limits *sidecarSetFootprintLimits, recorder record.EventRecorder) ([]sidecarcontrol.SidecarControl, bool) {
+
+	skipped := sets.NewString()
+	if skippedStr := pod.Annotations[sidecarcontrol.SidecarSetSkippedListAnnotation]; len(skippedStr) > 0 {
+		skipped.Insert(strings.Split(skippedStr, ",")...)
+	}
+
+	if !isUpdated {
+		if limits.isEmpty() {
+			return sidecarSets, false
+		}
+		ordered := make([]sidecarcontrol.SidecarControl, len(sidecarSets))
+		copy(ordered, sidecarSets)
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return ordered[i].GetSidecarset().Name < ordered[j].GetSidecarset().Name
+		})
+
+		var containers int
+		requests := corev1.ResourceList{}
+		for _, control := range ordered {
+			sidecarSet := control.GetSidecarset()
+			setContainers, setRequests := getSidecarSetFootprint(control)
+			if exceeded := exceedFootprintLimits(containers+setContainers, addResourceList(requests, setRequests), limits); exceeded != "" {
+				klog.InfoS("Skip injecting sidecarSet which would exceed the footprint limits of pod", "sidecarSet", sidecarSet.Name, "pod", klog.KObj(pod), "limit", exceeded)
+				if recorder != nil {
+					recorder.Eventf(sidecarSet, corev1.EventTypeWarning, "SkipInjection",
+						"skip injecting into pod %s/%s which would exceed the limit of %s", pod.Namespace, pod.Name, exceeded)
+				}
+				skipped.Insert(sidecarSet.Name)
+				continue
+			}
+			containers += setContainers
+			requests = addResourceList(requests, setRequests)
+		}
+		if skipped.Len() == 0 {
+			return sidecarSets, false
+		}
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[sidecarcontrol.SidecarSetSkippedListAnnotation] = strings.Join(skipped.List(), ",")
+	}
+
+	if skipped.Len() == 0 {
+		return sidecarSets, false
+	}
+	injected := make([]sidecarcontrol.SidecarControl, 0, len(sidecarSets))
+	for _, control := range sidecarSets {
+		if !skipped.Has(control.GetSidecarset().Name) {
+			injected = append(injected, control)
+		}
+	}
+	return injected, !isUpdated
+}
+
+// getSidecarSetFootprint returns the number and the total resource requests of containers in the sidecarSet.
+func getSidecarSetFootprint(control sidecarcontrol.SidecarControl) (int, corev1.ResourceList) {
+	sidecarSet := control.GetSidecarset()
+	requests := corev1.ResourceList{}
+	for i := range sidecarSet.Spec.InitContainers {
+		requests = addResourceList(requests, sidecarSet.Spec.InitContainers[i].Resources.Requests)
+	}
+	for i := range sidecarSet.Spec.Containers {
+		requests = addResourceList(requests, sidecarSet.Spec.Containers[i].Resources.Requests)
+	}
+	return len(sidecarSet.Spec.InitContainers) + len(sidecarSet.Spec.Containers), requests
+}
+
+// exceedFootprintLimits returns the description of the first limit exceeded, or empty if none.
+func exceedFootprintLimits(containers int, requests corev1.ResourceList, limits *sidecarSetFootprintLimits) string {
+	if limits.maxContainers > 0 && containers > limits.maxContainers {
+		return fmt.Sprintf("%d containers", limits.maxContainers)
+	}
+	names := make([]string, 0, len(limits.maxResources))
+	for name := range limits.maxResources {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		limit := limits.maxResources[corev1.ResourceName(name)]
+		if request, ok := requests[corev1.ResourceName(name)]; ok && request.Cmp(limit) > 0 {
+			return fmt.Sprintf("%s %s", limit.String(), name)
+		}
+	}
+	return ""
+}
+
+func addResourceList(a, b corev1.ResourceList) corev1.ResourceList {
+	sum := a.DeepCopy()
+	for name, quantity := range b {
+		if value, ok := sum[name]; ok {
+			value.Add(quantity)
+			sum[name] = value
+		} else {
+			sum[name] = quantity.DeepCopy()
+		}
+	}
+	return sum
+}
+
+// parseResourceList parses the resource list in the format of "cpu=1,memory=2Gi".
+func parseResourceList(str string) (corev1.ResourceList, error) {
+	resources := corev1.ResourceList{}
+	for _, item := range strings.Split(str, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid resource %q, expected name=quantity", item)
+		}
+		quantity, err := resource.ParseQuantity(strings.TrimSpace(kv[1]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity of resource %q: %v", item, err)
+		}
+		resources[corev1.ResourceName(strings.TrimSpace(kv[0]))] = quantity
+	}
+	return resources, nil
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
+	"github.com/openkruise/kruise/pkg/util/fieldindex"
+)
+
+func TestSidecarSetFootprintLimits(t *testing.T) {
+	newSidecarSet := func(name string, cpu string, containers ...string) *appsv1beta1.SidecarSet {
+		sidecarSet := &appsv1beta1.SidecarSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{sidecarcontrol.SidecarSetHashAnnotation: "c4k2dbb95d"},
+			},
+			Spec: appsv1beta1.SidecarSetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "footprint-test"}},
+			},
+		}
+		for _, c := range containers {
+			sidecarSet.Spec.Containers = append(sidecarSet.Spec.Containers, appsv1beta1.SidecarContainer{Container: corev1.Container{
+				Name:      c,
+				Image:     c + "-image:1.0",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}},
+			}})
+		}
+		return sidecarSet
+	}
+	sidecarSets := []client.Object{
+		newSidecarSet("sidecarset-d", "100m", "mesh"),
+		newSidecarSet("sidecarset-b", "500m", "log-agent", "log-rotate"),
+		newSidecarSet("sidecarset-a", "100m", "monitor"),
+		newSidecarSet("sidecarset-c", "100m", "tracing"),
+	}
+
+	cases := []struct {
+		name                 string
+		maxContainers        int
+		maxResources         string
+		namespaceAnnotations map[string]string
+		expectedContainers   []string
+		expectedSkipped      string
+		expectedEvents       int
+	}{
+		{
+			name:               "no limits",
+			expectedContainers: []string{"main", "log-agent", "log-rotate", "mesh", "monitor", "tracing"},
+		},
+		{
+			name:               "limit containers",
+			maxContainers:      3,
+			expectedContainers: []string{"main", "log-agent", "log-rotate", "monitor"},
+			expectedSkipped:    "sidecarset-c,sidecarset-d",
+			expectedEvents:     2,
+		},
+		{
+			name:               "limit resources",
+			maxResources:       "cpu=300m",
+			expectedContainers: []string{"main", "mesh", "monitor", "tracing"},
+			expectedSkipped:    "sidecarset-b",
+			expectedEvents:     1,
+		},
+		{
+			name:          "namespace overrides cluster-level limits",
+			maxContainers: 1,
+			namespaceAnnotations: map[string]string{
+				sidecarcontrol.SidecarSetMaxInjectedContainersAnnotation: "4",
+				sidecarcontrol.SidecarSetMaxInjectedResourcesAnnotation:  "cpu=2",
+			},
+			expectedContainers: []string{"main", "log-agent", "log-rotate", "monitor", "tracing"},
+			expectedSkipped:    "sidecarset-d",
+			expectedEvents:     1,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			defer func(c int, r string) { maxInjectedSidecarContainers, maxInjectedSidecarResources = c, r }(maxInjectedSidecarContainers, maxInjectedSidecarResources)
+			maxInjectedSidecarContainers, maxInjectedSidecarResources = cs.maxContainers, cs.maxResources
+
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Annotations: cs.namespaceAnnotations}}
+			objs := append([]client.Object{ns}, sidecarSets...)
+			for i := range objs {
+				objs[i] = objs[i].DeepCopyObject().(client.Object)
+			}
+			c := fake.NewClientBuilder().WithObjects(objs...).WithIndex(
+				&appsv1beta1.SidecarSet{}, fieldindex.IndexNameForSidecarSetNamespace, fieldindex.IndexSidecarSetV1Beta1,
+			).Build()
+			recorder := record.NewFakeRecorder(10)
+			podHandler := &PodCreateHandler{Decoder: admission.NewDecoder(scheme.Scheme), Client: c, EventRecorder: recorder}
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default", Labels: map[string]string{"app": "footprint-test"}},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "main", Image: "nginx:1.15.1"}}},
+			}
+			req := newAdmission(admissionv1.Create, runtime.RawExtension{}, runtime.RawExtension{}, "")
+			if _, err := podHandler.sidecarsetMutatingPod(context.Background(), req, pod); err != nil {
+				t.Fatalf("failed to mutate pod: %v", err)
+			}
+
+			var containers []string
+			for _, container := range pod.Spec.Containers {
+				containers = append(containers, container.Name)
+			}
+			sort.Strings(containers[1:])
+			if len(containers) != len(cs.expectedContainers) {
+				t.Fatalf("expected containers %v, got %v", cs.expectedContainers, containers)
+			}
+			for i := range containers {
+				if containers[i] != cs.expectedContainers[i] {
+					t.Fatalf("expected containers %v, got %v", cs.expectedContainers, containers)
+				}
+			}
+			if skipped := pod.Annotations[sidecarcontrol.SidecarSetSkippedListAnnotation]; skipped != cs.expectedSkipped {
+				t.Fatalf("expected skipped %q, got %q", cs.expectedSkipped, skipped)
+			}
+			if len(recorder.Events) != cs.expectedEvents {
+				t.Fatalf("expected %d events, got %d", cs.expectedEvents, len(recorder.Events))
+			}
+		})
+	}
+}
+
+func TestSidecarSetFootprintSkippedInUpdate(t *testing.T) {
+	newControl := func(name string) sidecarcontrol.SidecarControl {
+		return sidecarcontrol.New(&appsv1beta1.SidecarSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       appsv1beta1.SidecarSetSpec{Containers: []appsv1beta1.SidecarContainer{{Container: corev1.Container{Name: name}}}},
+		})
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{sidecarcontrol.SidecarSetSkippedListAnnotation: "sidecarset-b"},
+	}}
+
+	// the limits are no longer exceeded, but the skipped sidecarSet is still skipped
+	injected, changed := applySidecarSetFootprintLimits(pod, true, []sidecarcontrol.SidecarControl{newControl("sidecarset-a"), newControl("sidecarset-b")},
+		&sidecarSetFootprintLimits{}, nil)
+	if changed {
+		t.Fatalf("expected annotations unchanged in update")
+	}
+	if len(injected) != 1 || injected[0].GetSidecarset().Name != "sidecarset-a" {
+		t.Fatalf("expected only sidecarset-a injected, got %d", len(injected))
+	}
+}
+
+func TestParseResourceList(t *testing.T) {
+	resources, err := parseResourceList("cpu=500m, memory=1Gi")
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	if cpu := resources[corev1.ResourceCPU]; cpu.Cmp(resource.MustParse("500m")) != 0 {
+		t.Fatalf("expected cpu 500m, got %s", cpu.String())
+	}
+	if memory := resources[corev1.ResourceMemory]; memory.Cmp(resource.MustParse("1Gi")) != 0 {
+		t.Fatalf("expected memory 1Gi, got %s", memory.String())
+	}
+
+	for _, str := range []string{"cpu", "cpu=abc"} {
+		if _, err := parseResourceList(str); err == nil {
+			t.Fatalf("expected error for %q", str)
+		}
+	}
+}
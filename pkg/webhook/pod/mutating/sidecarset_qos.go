@@ -0,0 +1,136 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	v1qos "k8s.io/kubernetes/pkg/apis/core/v1/helper/qos"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
+)
+
+// applySidecarSetQoSPolicies applies the qosPolicy of sidecarSets to the pod, if it is Guaranteed and injecting the
+// sidecar containers would downgrade it to Burstable. It returns the sidecarSets to be injected, and whether the
+// decisions are recorded in the pod annotations.
+// The sidecarSets skipped when the pod is created are also skipped when it is updated.
+func applySidecarSetQoSPolicies(pod *corev1.Pod, isUpdated bool, sidecarSets []sidecarcontrol.SidecarControl) ([]sidecarcontrol.SidecarControl, bool, error) {
+	decisions := make(map[string]appsv1beta1.SidecarSetQoSPolicyType)
+	if decisionsStr := pod.Annotations[sidecarcontrol.SidecarSetQoSDecisionAnnotation]; len(decisionsStr) > 0 {
+		if err := json.Unmarshal([]byte(decisionsStr), &decisions); err != nil {
+			return nil, false, fmt.Errorf("pod(%s/%s) invalid annotations[%s] value %v, unmarshal failed: %v",
+				pod.Namespace, pod.Name, sidecarcontrol.SidecarSetQoSDecisionAnnotation, decisionsStr, err)
+		}
+	}
+
+	if isUpdated {
+		injected := make([]sidecarcontrol.SidecarControl, 0, len(sidecarSets))
+		for _, control := range sidecarSets {
+			if decisions[control.GetSidecarset().Name] != appsv1beta1.SidecarSetQoSPolicySkipInjection {
+				injected = append(injected, control)
+			}
+		}
+		return injected, false, nil
+	}
+
+	// the pod with the sidecar containers of sidecarSets without qosPolicy, which are always injected
+	podWithSidecars := pod.DeepCopy()
+	for _, control := range sidecarSets {
+		if sidecarSet := control.GetSidecarset(); sidecarSet.Spec.InjectionStrategy.QoSPolicy == nil {
+			sidecarcontrol.AppendSidecarContainers(podWithSidecars, sidecarSet)
+		}
+	}
+	// nothing to guard if the pod is not Guaranteed anyway
+	if v1qos.GetPodQOS(podWithSidecars) != corev1.PodQOSGuaranteed {
+		return sidecarSets, false, nil
+	}
+
+	var changed bool
+	injected := make([]sidecarcontrol.SidecarControl, 0, len(sidecarSets))
+	for _, control := range sidecarSets {
+		sidecarSet := control.GetSidecarset()
+		policy := sidecarSet.Spec.InjectionStrategy.QoSPolicy
+		if policy == nil || sidecarcontrol.IsPodGuaranteedWithSidecarSet(podWithSidecars, sidecarSet) {
+			if policy != nil {
+				sidecarcontrol.AppendSidecarContainers(podWithSidecars, sidecarSet)
+			}
+			injected = append(injected, control)
+			continue
+		}
+
+		switch policy.Type {
+		case appsv1beta1.SidecarSetQoSPolicySkipInjection:
+			klog.InfoS("Skip injecting sidecarSet which would downgrade the Guaranteed pod", "sidecarSet", sidecarSet.Name, "pod", klog.KObj(pod))
+		case appsv1beta1.SidecarSetQoSPolicyInheritLimits:
+			inheritSidecarContainersResources(sidecarSet, policy.DefaultResources)
+			if !sidecarcontrol.IsPodGuaranteedWithSidecarSet(podWithSidecars, sidecarSet) {
+				return nil, false, fmt.Errorf("injecting sidecarSet %s would downgrade the Guaranteed pod even with the defaultResources of qosPolicy", sidecarSet.Name)
+			}
+			klog.InfoS("Set defaultResources to sidecar containers to keep the pod Guaranteed", "sidecarSet", sidecarSet.Name, "pod", klog.KObj(pod))
+			sidecarcontrol.AppendSidecarContainers(podWithSidecars, sidecarSet)
+			injected = append(injected, control)
+		default:
+			return nil, false, fmt.Errorf("injecting sidecarSet %s would downgrade the Guaranteed pod, which is rejected by its qosPolicy", sidecarSet.Name)
+		}
+		decisions[sidecarSet.Name] = policy.Type
+		changed = true
+	}
+
+	if changed {
+		by, _ := json.Marshal(decisions)
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[sidecarcontrol.SidecarSetQoSDecisionAnnotation] = string(by)
+	}
+	return injected, changed, nil
+}
+
+// inheritSidecarContainersResources sets the resources to the sidecar containers which have no full resources,
+// i.e., no cpu and memory limits equal to their requests.
+func inheritSidecarContainersResources(sidecarSet *appsv1beta1.SidecarSet, resources corev1.ResourceList) {
+	inherit := func(container *corev1.Container) {
+		if hasFullResources(container) {
+			return
+		}
+		container.Resources.Limits = resources.DeepCopy()
+		container.Resources.Requests = resources.DeepCopy()
+	}
+	for i := range sidecarSet.Spec.InitContainers {
+		inherit(&sidecarSet.Spec.InitContainers[i].Container)
+	}
+	for i := range sidecarSet.Spec.Containers {
+		inherit(&sidecarSet.Spec.Containers[i].Container)
+	}
+}
+
+func hasFullResources(container *corev1.Container) bool {
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		limit, ok := container.Resources.Limits[name]
+		if !ok || limit.IsZero() {
+			return false
+		}
+		if request, ok := container.Resources.Requests[name]; ok && request.Cmp(limit) != 0 {
+			return false
+		}
+	}
+	return true
+}
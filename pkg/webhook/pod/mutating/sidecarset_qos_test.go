@@ -0,0 +1,172 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mutating
+
+import (
+	"context"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	appsv1beta1 "github.com/openkruise/kruise/apis/apps/v1beta1"
+	"github.com/openkruise/kruise/pkg/control/sidecarcontrol"
+	"github.com/openkruise/kruise/pkg/util/fieldindex"
+)
+
+func TestSidecarSetQoSPolicy(t *testing.T) {
+	guaranteedResources := corev1.ResourceRequirements{
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+	defaultResources := corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m"), corev1.ResourceMemory: resource.MustParse("128Mi")}
+	newPod := func(resources corev1.ResourceRequirements) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default", Labels: map[string]string{"app": "qos-test"}},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "main", Image: "nginx:1.15.1", Resources: resources}},
+			},
+		}
+	}
+	newSidecarSet := func(policy *appsv1beta1.SidecarSetQoSPolicy, resources corev1.ResourceRequirements) *appsv1beta1.SidecarSet {
+		return &appsv1beta1.SidecarSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "sidecarset-qos",
+				Annotations: map[string]string{sidecarcontrol.SidecarSetHashAnnotation: "c4k2dbb95d"},
+			},
+			Spec: appsv1beta1.SidecarSetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "qos-test"}},
+				Containers: []appsv1beta1.SidecarContainer{{
+					Container: corev1.Container{Name: "log-agent", Image: "log-agent-image:1.0", Resources: resources},
+				}},
+				InjectionStrategy: appsv1beta1.SidecarSetInjectionStrategy{QoSPolicy: policy},
+			},
+		}
+	}
+
+	cases := []struct {
+		name               string
+		pod                *corev1.Pod
+		sidecarSet         *appsv1beta1.SidecarSet
+		expectedErr        bool
+		expectedContainers int
+		expectedDecision   string
+		expectedResources  corev1.ResourceList
+	}{
+		{
+			name:               "reject",
+			pod:                newPod(guaranteedResources),
+			sidecarSet:         newSidecarSet(&appsv1beta1.SidecarSetQoSPolicy{Type: appsv1beta1.SidecarSetQoSPolicyReject}, corev1.ResourceRequirements{}),
+			expectedErr:        true,
+			expectedContainers: 1,
+		},
+		{
+			name:               "skip injection",
+			pod:                newPod(guaranteedResources),
+			sidecarSet:         newSidecarSet(&appsv1beta1.SidecarSetQoSPolicy{Type: appsv1beta1.SidecarSetQoSPolicySkipInjection}, corev1.ResourceRequirements{}),
+			expectedContainers: 1,
+			expectedDecision:   `{"sidecarset-qos":"SkipInjection"}`,
+		},
+		{
+			name: "inherit limits",
+			pod:  newPod(guaranteedResources),
+			sidecarSet: newSidecarSet(&appsv1beta1.SidecarSetQoSPolicy{Type: appsv1beta1.SidecarSetQoSPolicyInheritLimits, DefaultResources: defaultResources},
+				corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")}}),
+			expectedContainers: 2,
+			expectedDecision:   `{"sidecarset-qos":"InheritLimits"}`,
+			expectedResources:  defaultResources,
+		},
+		{
+			name:               "sidecar with full resources",
+			pod:                newPod(guaranteedResources),
+			sidecarSet:         newSidecarSet(&appsv1beta1.SidecarSetQoSPolicy{Type: appsv1beta1.SidecarSetQoSPolicyReject}, guaranteedResources),
+			expectedContainers: 2,
+			expectedResources:  guaranteedResources.Limits,
+		},
+		{
+			name: "burstable pod",
+			pod:  newPod(corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}}),
+			sidecarSet: newSidecarSet(&appsv1beta1.SidecarSetQoSPolicy{Type: appsv1beta1.SidecarSetQoSPolicyInheritLimits, DefaultResources: defaultResources},
+				corev1.ResourceRequirements{}),
+			expectedContainers: 2,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			decoder := admission.NewDecoder(scheme.Scheme)
+			c := fake.NewClientBuilder().WithObjects(cs.sidecarSet).WithIndex(
+				&appsv1beta1.SidecarSet{}, fieldindex.IndexNameForSidecarSetNamespace, fieldindex.IndexSidecarSetV1Beta1,
+			).Build()
+			podHandler := &PodCreateHandler{Decoder: decoder, Client: c}
+			req := newAdmission(admissionv1.Create, runtime.RawExtension{}, runtime.RawExtension{}, "")
+			podOut := cs.pod.DeepCopy()
+			_, err := podHandler.sidecarsetMutatingPod(context.Background(), req, podOut)
+			if (err != nil) != cs.expectedErr {
+				t.Fatalf("expected error %v, got %v", cs.expectedErr, err)
+			}
+			if cs.expectedErr {
+				return
+			}
+			if len(podOut.Spec.Containers) != cs.expectedContainers {
+				t.Fatalf("expected %d containers, got %d", cs.expectedContainers, len(podOut.Spec.Containers))
+			}
+			if decision := podOut.Annotations[sidecarcontrol.SidecarSetQoSDecisionAnnotation]; decision != cs.expectedDecision {
+				t.Fatalf("expected decision %q, got %q", cs.expectedDecision, decision)
+			}
+			if cs.expectedResources != nil {
+				sidecar := podOut.Spec.Containers[1]
+				for name, quantity := range cs.expectedResources {
+					if limit := sidecar.Resources.Limits[name]; limit.Cmp(quantity) != 0 {
+						t.Fatalf("expected limit %s of %s, got %s", quantity.String(), name, limit.String())
+					}
+					if request := sidecar.Resources.Requests[name]; request.Cmp(quantity) != 0 {
+						t.Fatalf("expected request %s of %s, got %s", quantity.String(), name, request.String())
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSidecarSetQoSPolicySkippedInUpdate(t *testing.T) {
+	sidecarSet := &appsv1beta1.SidecarSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "sidecarset-qos"},
+		Spec: appsv1beta1.SidecarSetSpec{
+			InjectionStrategy: appsv1beta1.SidecarSetInjectionStrategy{
+				QoSPolicy: &appsv1beta1.SidecarSetQoSPolicy{Type: appsv1beta1.SidecarSetQoSPolicySkipInjection},
+			},
+		},
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{sidecarcontrol.SidecarSetQoSDecisionAnnotation: `{"sidecarset-qos":"SkipInjection"}`},
+	}}
+
+	injected, changed, err := applySidecarSetQoSPolicies(pod, true, []sidecarcontrol.SidecarControl{sidecarcontrol.New(sidecarSet)})
+	if err != nil {
+		t.Fatalf("failed to apply qosPolicy: %v", err)
+	}
+	if len(injected) != 0 || changed {
+		t.Fatalf("expected sidecarSet skipped in update without change, got %d injected, changed %v", len(injected), changed)
+	}
+}
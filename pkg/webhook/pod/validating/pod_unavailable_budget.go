@@ -130,5 +130,10 @@ func (p *PodCreateHandler) podUnavailableBudgetValidatingPod(ctx context.Context
 	if checkPod.Annotations[pubcontrol.PodRelatedPubAnnotation] == "" {
 		return true, "", nil
 	}
+	// the request is dry run if any of its options or the admission request itself says so,
+	// then the pub quota is checked without being consumed
+	if req.AdmissionRequest.DryRun != nil && *req.AdmissionRequest.DryRun {
+		dryRun = true
+	}
 	return pubcontrol.PodUnavailableBudgetValidatePod(checkPod, operation, req.UserInfo.Username, dryRun)
 }
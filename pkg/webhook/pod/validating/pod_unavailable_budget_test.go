@@ -33,6 +33,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	podutil "k8s.io/kubernetes/pkg/api/v1/pod"
 	"k8s.io/kubernetes/pkg/apis/policy"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -710,6 +711,7 @@ func TestValidateDeletePodForPub(t *testing.T) {
 		newPod          func() *corev1.Pod
 		pub             func() *policyv1alpha1.PodUnavailableBudget
 		subresource     string
+		dryRun          bool
 		expectAllow     bool
 		expectPubStatus func() *policyv1alpha1.PodUnavailableBudgetStatus
 	}{
@@ -825,6 +827,53 @@ func TestValidateDeletePodForPub(t *testing.T) {
 				return pubStatus
 			},
 		},
+		{
+			name: "delete pod, dry run admission request",
+			newPod: func() *corev1.Pod {
+				podIn := podDemo.DeepCopy()
+				return podIn
+			},
+			deletion: func() *metav1.DeleteOptions {
+				return &metav1.DeleteOptions{}
+			},
+			pub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Status.CurrentAvailable = 8
+				pub.Status.UnavailableAllowed = 1
+				return pub
+			},
+			subresource: "",
+			dryRun:      true,
+			expectAllow: true,
+			expectPubStatus: func() *policyv1alpha1.PodUnavailableBudgetStatus {
+				pubStatus := pubDemo.Status.DeepCopy()
+				pubStatus.CurrentAvailable = 8
+				pubStatus.UnavailableAllowed = 1
+				return pubStatus
+			},
+		},
+		{
+			name: "delete pod, dry run, reject",
+			newPod: func() *corev1.Pod {
+				podIn := podDemo.DeepCopy()
+				return podIn
+			},
+			deletion: func() *metav1.DeleteOptions {
+				return &metav1.DeleteOptions{
+					DryRun: []string{"All"},
+				}
+			},
+			pub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				return pub
+			},
+			subresource: "",
+			expectAllow: false,
+			expectPubStatus: func() *policyv1alpha1.PodUnavailableBudgetStatus {
+				pubStatus := pubDemo.Status.DeepCopy()
+				return pubStatus
+			},
+		},
 	}
 
 	for _, cs := range cases {
@@ -846,6 +895,9 @@ func TestValidateDeletePodForPub(t *testing.T) {
 			}
 			req := newAdmission(cs.newPod().Namespace, cs.newPod().Name, admissionv1.Delete, runtime.RawExtension{}, podRaw, cs.subresource)
 			req.AdmissionRequest.Options = deletionRaw
+			if cs.dryRun {
+				req.AdmissionRequest.DryRun = ptr.To(true)
+			}
 			allow, _, err := podHandler.podUnavailableBudgetValidatingPod(context.TODO(), req)
 			if err != nil {
 				t.Errorf("Pub validate pod failed: %s", err.Error())
@@ -266,7 +266,8 @@ func validateHTTPGetAction(http *corev1.HTTPGetAction, fldPath *field.Path) fiel
 		allErrors = append(allErrors, field.Invalid(fldPath.Child("path"), http.Path, "must be a valid URL path"))
 	}
 	allErrors = append(allErrors, ValidatePortNumOrName(http.Port, fldPath.Child("port"))...)
-	if !supportedHTTPSchemes.Has(http.Scheme) {
+	// the scheme defaults to HTTP
+	if http.Scheme != "" && !supportedHTTPSchemes.Has(http.Scheme) {
 		allErrors = append(allErrors, field.NotSupported(fldPath.Child("scheme"), http.Scheme, sets.List(supportedHTTPSchemes)))
 	}
 	for _, header := range http.HTTPHeaders {
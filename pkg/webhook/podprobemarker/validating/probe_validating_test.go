@@ -519,6 +519,14 @@ func TestValidateHTTPGetAction(t *testing.T) {
 			},
 			expectErrs: 2,
 		},
+		{
+			name: "default scheme",
+			httpGet: &corev1.HTTPGetAction{
+				Path: "/healthz",
+				Port: intstr.FromInt(8080),
+			},
+			expectErrs: 0,
+		},
 		{
 			name: "unsupported scheme",
 			httpGet: &corev1.HTTPGetAction{
@@ -28,6 +28,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	metavalidation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	appsvalidation "k8s.io/kubernetes/pkg/apis/apps/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -135,11 +136,11 @@ func validatePodUnavailableBudgetSpec(obj *policyv1alpha1.PodUnavailableBudget,
 		allErrs = append(allErrs, field.Required(fldPath.Child("selector, targetRef"), "selector and targetRef are mutually exclusive"))
 	} else if spec.TargetReference != nil {
 		if spec.TargetReference.APIVersion == "" || spec.TargetReference.Name == "" || spec.TargetReference.Kind == "" {
-			allErrs = append(allErrs, field.Invalid(fldPath.Child("TargetReference"), spec.TargetReference, "empty TargetReference is not valid for PodUnavailableBudget."))
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("targetRef"), spec.TargetReference, "empty TargetReference is not valid for PodUnavailableBudget."))
 		}
 		_, err := schema.ParseGroupVersion(spec.TargetReference.APIVersion)
 		if err != nil {
-			allErrs = append(allErrs, field.Invalid(fldPath.Child("TargetReference"), spec.TargetReference, err.Error()))
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("targetRef"), spec.TargetReference, err.Error()))
 		}
 	} else {
 		allErrs = append(allErrs, metavalidation.ValidateLabelSelector(spec.Selector, metavalidation.LabelSelectorValidationOptions{}, fldPath.Child("selector"))...)
@@ -163,6 +164,22 @@ func validatePodUnavailableBudgetSpec(obj *policyv1alpha1.PodUnavailableBudget,
 		allErrs = append(allErrs, appsvalidation.ValidatePositiveIntOrPercent(*spec.MinAvailable, fldPath.Child("minAvailable"))...)
 		allErrs = append(allErrs, appsvalidation.IsNotMoreThan100Percent(*spec.MinAvailable, fldPath.Child("minAvailable"))...)
 	}
+
+	operations := sets.New[policyv1alpha1.PubOperation]()
+	for i, operation := range spec.ProtectedOperations {
+		switch operation {
+		case policyv1alpha1.PubUpdateOperation, policyv1alpha1.PubDeleteOperation, policyv1alpha1.PubEvictOperation, policyv1alpha1.PubResizeOperation:
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("protectedOperations").Index(i), operation, []string{
+				string(policyv1alpha1.PubDeleteOperation), string(policyv1alpha1.PubUpdateOperation),
+				string(policyv1alpha1.PubEvictOperation), string(policyv1alpha1.PubResizeOperation)}))
+			continue
+		}
+		if operations.Has(operation) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("protectedOperations").Index(i), operation))
+		}
+		operations.Insert(operation)
+	}
 	return allErrs
 }
 
@@ -181,6 +181,28 @@ func TestValidatingPub(t *testing.T) {
 			},
 			expectErrList: 0,
 		},
+		{
+			name: "valid pub protectedOperations",
+			pub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.Selector = nil
+				pub.Spec.MinAvailable = nil
+				pub.Spec.ProtectedOperations = []policyv1alpha1.PubOperation{policyv1alpha1.PubEvictOperation, policyv1alpha1.PubDeleteOperation}
+				return pub
+			},
+			expectErrList: 0,
+		},
+		{
+			name: "invalid pub protectedOperations",
+			pub: func() *policyv1alpha1.PodUnavailableBudget {
+				pub := pubDemo.DeepCopy()
+				pub.Spec.Selector = nil
+				pub.Spec.MinAvailable = nil
+				pub.Spec.ProtectedOperations = []policyv1alpha1.PubOperation{policyv1alpha1.PubEvictOperation, "Evict", policyv1alpha1.PubEvictOperation}
+				return pub
+			},
+			expectErrList: 2,
+		},
 	}
 
 	decoder := admission.NewDecoder(scheme)
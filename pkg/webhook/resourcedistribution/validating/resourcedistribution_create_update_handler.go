@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 
 	admissionv1 "k8s.io/api/admission/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -49,6 +50,8 @@ var _ admission.Handler = &ResourceDistributionCreateUpdateHandler{}
 // validateResourceDistributionSpec validate Spec when creating and updating
 // (1). validate resource itself
 // (2). validate targets
+// (3). validate conflictPolicy
+// (4). validate templates in resource if resourceTemplating is enabled
 func (h *ResourceDistributionCreateUpdateHandler) validateResourceDistributionSpec(obj, oldObj *appsv1alpha1.ResourceDistribution, fldPath *field.Path) (allErrs field.ErrorList) {
 	spec := &obj.Spec
 	// deserialize resource from runtime.rawExtension
@@ -67,13 +70,30 @@ func (h *ResourceDistributionCreateUpdateHandler) validateResourceDistributionSp
 	allErrs = append(allErrs, h.validateResourceDistributionSpecResource(resource, oldResource, fldPath.Child("resource"))...)
 	// 2. validate targets
 	allErrs = append(allErrs, h.validateResourceDistributionSpecTargets(&obj.Spec.Targets, fldPath.Child("targets"))...)
+	// 3. validate conflictPolicy, overwriting the existing resources has to be acknowledged explicitly by this field
+	switch spec.ConflictPolicy {
+	case "", appsv1alpha1.ResourceDistributionConflictPolicyFail, appsv1alpha1.ResourceDistributionConflictPolicyIgnore,
+		appsv1alpha1.ResourceDistributionConflictPolicyOverwrite:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("conflictPolicy"), spec.ConflictPolicy, []string{
+			string(appsv1alpha1.ResourceDistributionConflictPolicyFail),
+			string(appsv1alpha1.ResourceDistributionConflictPolicyIgnore),
+			string(appsv1alpha1.ResourceDistributionConflictPolicyOverwrite),
+		}))
+	}
+	// 4. validate templates by rendering the resource for a namespace
+	if spec.ResourceTemplating {
+		_, errs := renderResourceForNamespace(resource, webhookutil.GetNamespace(), fldPath.Child("resource"))
+		allErrs = append(allErrs, errs...)
+	}
 	return
 }
 
 // validateResourceDistributionResource validate Spec.Resource when creating and updating
 // (1). check whether type of the resource is supported
 // (2). detect updating conflict, i.e., GK and name cannot be modified
-// (3). dry run to check whether resource can be created
+// (3). check whether the payload of Secret is valid for its type
+// (4). dry run to check whether resource can be created
 func (h *ResourceDistributionCreateUpdateHandler) validateResourceDistributionSpecResource(resource, oldResource runtime.Object, fldPath *field.Path) (allErrs field.ErrorList) {
 	// 1. check whether the GK of the resource is in supportedGKList
 	if !isSupportedGK(resource) {
@@ -83,7 +103,11 @@ func (h *ResourceDistributionCreateUpdateHandler) validateResourceDistributionSp
 	if oldResource != nil && !haveSameGVKAndName(resource, oldResource) {
 		return append(allErrs, field.Invalid(fldPath, nil, "resource apiVersion, kind, and name are immutable"))
 	}
-	// 3. dry run to check the resource
+	// 3. validate the payload of Secret
+	if errs := validateSecretPayload(resource, fldPath); len(errs) != 0 {
+		return append(allErrs, errs...)
+	}
+	// 4. dry run to check the resource
 	mice := resource.DeepCopyObject().(client.Object)
 	ConvertToUnstructured(mice).SetNamespace(webhookutil.GetNamespace())
 	err := h.Client.Create(context.TODO(), mice, &client.CreateOptions{DryRun: []string{metav1.DryRunAll}})
@@ -108,13 +132,15 @@ func (h *ResourceDistributionCreateUpdateHandler) validateResourceDistributionSp
 		}
 	}
 	for _, namespace := range targets.ExcludedNamespaces.List {
-		// validate namespace name
-		for _, msg := range coreval.ValidateNamespaceName(namespace.Name, false) {
+		// validate namespace name, wildcard "*" is allowed in excluded names
+		for _, msg := range coreval.ValidateNamespaceName(strings.ReplaceAll(namespace.Name, "*", "x"), false) {
 			allErrs = append(allErrs, field.Invalid(fldPath.Child("excludedNamespaces"), targets.ExcludedNamespaces, msg))
 		}
 		// validate conflict between IncludedNamespaces and ExcludedNamespaces
-		if includedNS.Has(namespace.Name) {
-			conflicted = append(conflicted, namespace.Name)
+		for _, included := range includedNS.List() {
+			if MatchNamespaceName(namespace.Name, included) {
+				conflicted = append(conflicted, included)
+			}
 		}
 	}
 	if len(conflicted) != 0 {
@@ -126,6 +152,11 @@ func (h *ResourceDistributionCreateUpdateHandler) validateResourceDistributionSp
 		allErrs = append(allErrs, field.Invalid(fldPath.Child("namespaceLabelSelector"), targets.NamespaceLabelSelector, fmt.Sprintf("labelSelectorAsSelector error: %v", err)))
 	}
 
+	// 3. validate targets.ExcludedNamespacesSelector
+	if _, err := metav1.LabelSelectorAsSelector(&targets.ExcludedNamespacesSelector); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("excludedNamespacesSelector"), targets.ExcludedNamespacesSelector, fmt.Sprintf("labelSelectorAsSelector error: %v", err)))
+	}
+
 	return
 }
 
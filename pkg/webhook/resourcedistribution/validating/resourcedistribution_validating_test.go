@@ -14,6 +14,9 @@ limitations under the License.
 package validating
 
 import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
@@ -83,6 +86,132 @@ func TestResourceDistributionCreateValidationWithWrongResource(t *testing.T) {
 	}
 }
 
+func TestResourceDistributionCreateValidationWithSecretTypes(t *testing.T) {
+	dockerConfigJSON := base64.StdEncoding.EncodeToString([]byte(`{"auths":{"registry.example.com":{"auth":"dXNlcjpwYXNz"}}}`))
+	cases := []struct {
+		name        string
+		resource    string
+		expectError bool
+	}{
+		{
+			name:     "tls secret",
+			resource: `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"test-tls"},"type":"kubernetes.io/tls","data":{"tls.crt":"Y2VydA==","tls.key":"a2V5"}}`,
+		},
+		{
+			name:     "dockerconfigjson secret",
+			resource: `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"test-docker"},"type":"kubernetes.io/dockerconfigjson","data":{".dockerconfigjson":"` + dockerConfigJSON + `"}}`,
+		},
+		{
+			name:     "dockerconfigjson secret in stringData",
+			resource: `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"test-docker"},"type":"kubernetes.io/dockerconfigjson","stringData":{".dockerconfigjson":"{\"auths\":{}}"}}`,
+		},
+		{
+			name:        "dockerconfigjson secret with unparsable payload",
+			resource:    `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"test-docker"},"type":"kubernetes.io/dockerconfigjson","data":{".dockerconfigjson":"` + base64.StdEncoding.EncodeToString([]byte("not json")) + `"}}`,
+			expectError: true,
+		},
+		{
+			name:        "dockerconfigjson secret without payload",
+			resource:    `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"test-docker"},"type":"kubernetes.io/dockerconfigjson","data":{"config":"e30="}}`,
+			expectError: true,
+		},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			makeEnvironment()
+			errs := handler.validateResourceDistribution(buildResourceDistribution(cs.resource), nil)
+			if cs.expectError != (len(errs) != 0) {
+				t.Fatalf("expect error %v, but got %v", cs.expectError, errs)
+			}
+		})
+	}
+}
+
+func TestResourceDistributionValidateConflictPolicy(t *testing.T) {
+	cases := []struct {
+		conflictPolicy appsv1alpha1.ResourceDistributionConflictPolicyType
+		expectError    bool
+	}{
+		{conflictPolicy: ""},
+		{conflictPolicy: appsv1alpha1.ResourceDistributionConflictPolicyFail},
+		{conflictPolicy: appsv1alpha1.ResourceDistributionConflictPolicyIgnore},
+		{conflictPolicy: appsv1alpha1.ResourceDistributionConflictPolicyOverwrite},
+		{conflictPolicy: "overwrite", expectError: true},
+	}
+
+	for _, cs := range cases {
+		makeEnvironment()
+		rd := buildResourceDistributionWithSecret()
+		rd.Spec.ConflictPolicy = cs.conflictPolicy
+		errs := handler.validateResourceDistribution(rd, nil)
+		if cs.expectError != (len(errs) != 0) {
+			t.Fatalf("conflictPolicy %q: expect error %v, but got %v", cs.conflictPolicy, cs.expectError, errs)
+		}
+	}
+}
+
+func TestResourceDistributionValidateResourceTemplating(t *testing.T) {
+	cases := []struct {
+		name        string
+		templating  bool
+		value       string
+		expectError bool
+	}{
+		{name: "placeholder", templating: true, value: "svc.${NAMESPACE}.svc.cluster.local"},
+		{name: "go template", templating: true, value: "svc.{{ .Namespace }}.svc.cluster.local"},
+		{name: "unclosed go template", templating: true, value: "svc.{{ .Namespace", expectError: true},
+		{name: "unknown field", templating: true, value: "svc.{{ .Name }}", expectError: true},
+		{name: "templating disabled", value: "svc.{{ .Namespace"},
+	}
+
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			makeEnvironment()
+			rd := buildResourceDistribution(fmt.Sprintf(`{
+				"apiVersion": "v1",
+				"data": {"endpoint": %q},
+				"kind": "ConfigMap",
+				"metadata": {"name": "game-demo"}
+			}`, cs.value))
+			rd.Spec.ResourceTemplating = cs.templating
+			errs := handler.validateResourceDistribution(rd, nil)
+			if cs.expectError != (len(errs) != 0) {
+				t.Fatalf("expect error %v, but got %v", cs.expectError, errs)
+			}
+		})
+	}
+}
+
+func TestRenderResourceForNamespace(t *testing.T) {
+	rd := buildResourceDistribution(`{
+		"apiVersion": "v1",
+		"binaryData": {"raw": "JHtOQU1FU1BBQ0V9"},
+		"data": {"endpoint": "svc.${NAMESPACE}.svc", "name": "{{ .Namespace }}"},
+		"kind": "ConfigMap",
+		"metadata": {"name": "game-demo"}
+	}`)
+	resource, errs := DeserializeResource(&rd.Spec.Resource, field.NewPath("resource"))
+	if len(errs) != 0 {
+		t.Fatalf("failed to deserialize resource, err %v", errs)
+	}
+	rendered, err := RenderResourceForNamespace(resource, "ns-1")
+	if err != nil {
+		t.Fatalf("failed to render resource, err %v", err)
+	}
+	object := ConvertToUnstructured(rendered).Object
+	expected := map[string]interface{}{"endpoint": "svc.ns-1.svc", "name": "ns-1"}
+	if !reflect.DeepEqual(object["data"], expected) {
+		t.Fatalf("expected data %v, got %v", expected, object["data"])
+	}
+	if !reflect.DeepEqual(object["binaryData"], map[string]interface{}{"raw": "JHtOQU1FU1BBQ0V9"}) {
+		t.Fatalf("expected binaryData not rendered, got %v", object["binaryData"])
+	}
+	if data := ConvertToUnstructured(resource).Object["data"].(map[string]interface{}); data["endpoint"] != "svc.${NAMESPACE}.svc" {
+		t.Fatalf("expected resource not modified, got %v", data)
+	}
+}
+
 func TestResourceDistributionUpdateValidation(t *testing.T) {
 	// build rd objects
 	oldRD := buildResourceDistributionWithSecret()
@@ -125,6 +254,64 @@ func TestValidateResourceDistributionTargets(t *testing.T) {
 	}
 }
 
+func TestValidateResourceDistributionTargetsExclusion(t *testing.T) {
+	cases := []struct {
+		name    string
+		targets *appsv1alpha1.ResourceDistributionTargets
+		errs    int
+	}{
+		{
+			name: "valid wildcard and selector",
+			targets: &appsv1alpha1.ResourceDistributionTargets{
+				ExcludedNamespaces: appsv1alpha1.ResourceDistributionTargetNamespaces{
+					List: []appsv1alpha1.ResourceDistributionNamespace{{Name: "kube-*"}, {Name: "*-system"}},
+				},
+				ExcludedNamespacesSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"distribution": "skip"},
+				},
+			},
+		},
+		{
+			name: "invalid wildcard name",
+			targets: &appsv1alpha1.ResourceDistributionTargets{
+				ExcludedNamespaces: appsv1alpha1.ResourceDistributionTargetNamespaces{
+					List: []appsv1alpha1.ResourceDistributionNamespace{{Name: "Kube_*"}},
+				},
+			},
+			errs: 1,
+		},
+		{
+			name: "wildcard conflicts with included namespace",
+			targets: &appsv1alpha1.ResourceDistributionTargets{
+				IncludedNamespaces: appsv1alpha1.ResourceDistributionTargetNamespaces{
+					List: []appsv1alpha1.ResourceDistributionNamespace{{Name: "kube-public"}, {Name: "ns-1"}},
+				},
+				ExcludedNamespaces: appsv1alpha1.ResourceDistributionTargetNamespaces{
+					List: []appsv1alpha1.ResourceDistributionNamespace{{Name: "kube-*"}},
+				},
+			},
+			errs: 1,
+		},
+		{
+			name: "invalid excluded selector",
+			targets: &appsv1alpha1.ResourceDistributionTargets{
+				ExcludedNamespacesSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{"$#%$%": "#@$@#$"},
+				},
+			},
+			errs: 1,
+		},
+	}
+	for _, cs := range cases {
+		t.Run(cs.name, func(t *testing.T) {
+			errs := handler.validateResourceDistributionSpecTargets(cs.targets, field.NewPath("targets"))
+			if len(errs) != cs.errs {
+				t.Fatalf("expected %d errors, got %v", cs.errs, errs)
+			}
+		})
+	}
+}
+
 func TestResourceDistributionUpdateConflict(t *testing.T) {
 	// resource details
 	const changedResourceJSON = `{
@@ -14,9 +14,16 @@ limitations under the License.
 package validating
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"path"
 	"reflect"
+	"sort"
+	"strings"
+	"text/template"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -26,6 +33,10 @@ import (
 const (
 	ResourceHashCodeAnnotation           = "kruise.io/resourcedistribution.resource.hashcode"
 	SourceResourceDistributionOfResource = "kruise.io/resourcedistribution.resource.from"
+
+	// NamespacePlaceholder is replaced with the target namespace in the string data values of resource
+	// when spec.resourceTemplating is enabled
+	NamespacePlaceholder = "${NAMESPACE}"
 )
 
 var (
@@ -70,6 +81,94 @@ func ConvertToUnstructured(resourceObject runtime.Object) (resource *unstructure
 	}
 }
 
+// MatchNamespaceName return true if the namespace name matches the name in targets, which may contain wildcard "*"
+// reused by controller
+func MatchNamespaceName(name, namespace string) bool {
+	if !strings.Contains(name, "*") {
+		return name == namespace
+	}
+	matched, _ := path.Match(name, namespace)
+	return matched
+}
+
+// resourceTemplateData is the data of Go templates in the string data values of resource
+type resourceTemplateData struct {
+	Namespace string
+}
+
+// templatedFieldOf return the field of string data values that can be templated, binary data is never templated
+func templatedFieldOf(resource *unstructured.Unstructured) string {
+	switch resource.GroupVersionKind().GroupKind() {
+	case corev1.SchemeGroupVersion.WithKind("ConfigMap").GroupKind():
+		return "data"
+	case corev1.SchemeGroupVersion.WithKind("Secret").GroupKind():
+		return "stringData"
+	}
+	return ""
+}
+
+// RenderResourceForNamespace return a copy of resource whose string data values are rendered for the namespace
+// reused by controller
+func RenderResourceForNamespace(resource runtime.Object, namespace string) (runtime.Object, error) {
+	rendered, errs := renderResourceForNamespace(resource, namespace, field.NewPath("resource"))
+	if len(errs) != 0 {
+		return nil, errs.ToAggregate()
+	}
+	return rendered, nil
+}
+
+func renderResourceForNamespace(resource runtime.Object, namespace string, fldPath *field.Path) (*unstructured.Unstructured, field.ErrorList) {
+	rendered := ConvertToUnstructured(resource.DeepCopyObject())
+	fieldName := templatedFieldOf(rendered)
+	if fieldName == "" {
+		return rendered, nil
+	}
+	values, found, err := unstructured.NestedStringMap(rendered.Object, fieldName)
+	if err != nil {
+		return nil, field.ErrorList{field.Invalid(fldPath.Child(fieldName), nil, err.Error())}
+	} else if !found {
+		return rendered, nil
+	}
+
+	var allErrs field.ErrorList
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, err := renderTemplate(values[key], namespace)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child(fieldName).Key(key), values[key], fmt.Sprintf("invalid template: %v", err)))
+			continue
+		}
+		values[key] = value
+	}
+	if len(allErrs) != 0 {
+		return nil, allErrs
+	}
+	if err := unstructured.SetNestedStringMap(rendered.Object, values, fieldName); err != nil {
+		return nil, field.ErrorList{field.InternalError(fldPath.Child(fieldName), err)}
+	}
+	return rendered, nil
+}
+
+// renderTemplate executes the Go template in value, and then replaces NamespacePlaceholder with namespace
+func renderTemplate(value, namespace string) (string, error) {
+	if strings.Contains(value, "{{") {
+		tmpl, err := template.New("").Option("missingkey=error").Parse(value)
+		if err != nil {
+			return "", err
+		}
+		buf := &bytes.Buffer{}
+		if err := tmpl.Execute(buf, resourceTemplateData{Namespace: namespace}); err != nil {
+			return "", err
+		}
+		value = buf.String()
+	}
+	return strings.ReplaceAll(value, NamespacePlaceholder, namespace), nil
+}
+
 // DeserializeResource receive yaml of resource, return runtime.Object
 // reused by controller
 func DeserializeResource(resourceRawExtension *runtime.RawExtension, fldPath *field.Path) (resource runtime.Object, allErrs field.ErrorList) {
@@ -85,3 +184,37 @@ func DeserializeResource(resourceRawExtension *runtime.RawExtension, fldPath *fi
 	}
 	return
 }
+
+// validateSecretPayload check whether the payload of a Secret resource is valid for its type,
+// e.g., the docker config of a kubernetes.io/dockerconfigjson Secret must be parsable
+func validateSecretPayload(resource runtime.Object, fldPath *field.Path) (allErrs field.ErrorList) {
+	if resource.GetObjectKind().GroupVersionKind().GroupKind() != corev1.SchemeGroupVersion.WithKind("Secret").GroupKind() {
+		return
+	}
+	secret := &corev1.Secret{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(ConvertToUnstructured(resource).Object, secret); err != nil {
+		return append(allErrs, field.Invalid(fldPath, nil, fmt.Sprintf("failed to decode Secret, err %v", err)))
+	}
+
+	var key string
+	switch secret.Type {
+	case corev1.SecretTypeDockerConfigJson:
+		key = corev1.DockerConfigJsonKey
+	case corev1.SecretTypeDockercfg:
+		key = corev1.DockerConfigKey
+	default:
+		return
+	}
+	payload, ok := secret.StringData[key]
+	if !ok {
+		data, ok := secret.Data[key]
+		if !ok {
+			return append(allErrs, field.Required(fldPath.Child("data").Key(key), fmt.Sprintf("required for Secret of type %s", secret.Type)))
+		}
+		payload = string(data)
+	}
+	if err := json.Unmarshal([]byte(payload), &map[string]interface{}{}); err != nil {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("data").Key(key), "<secret contents redacted>", fmt.Sprintf("failed to parse docker config, err %v", err)))
+	}
+	return
+}
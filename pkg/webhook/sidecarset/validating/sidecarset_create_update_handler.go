@@ -230,7 +230,7 @@ func validateSelector(selector *metav1.LabelSelector, fldPath *field.Path) field
 	return allErrs
 }
 
-func (h *SidecarSetCreateUpdateHandler) validateSidecarSetInjectionStrategy(obj *appsv1beta1.SidecarSet, _ *field.Path) field.ErrorList {
+func (h *SidecarSetCreateUpdateHandler) validateSidecarSetInjectionStrategy(obj *appsv1beta1.SidecarSet, fldPath *field.Path) field.ErrorList {
 	errList := field.ErrorList{}
 	revisionInfo := obj.Spec.InjectionStrategy.Revision
 
@@ -252,6 +252,32 @@ func (h *SidecarSetCreateUpdateHandler) validateSidecarSetInjectionStrategy(obj
 				revisionInfo.Policy, appsv1beta1.AlwaysSidecarSetInjectRevisionPolicy, appsv1beta1.PartialSidecarSetInjectRevisionPolicy)))
 		}
 	}
+
+	if backfill := obj.Spec.InjectionStrategy.Backfill; backfill != nil && backfill.MaxUnavailablePerWorkload != nil {
+		maxUnavailablePath := fldPath.Child("backfill", "maxUnavailablePerWorkload")
+		errList = append(errList, appsvalidation.ValidatePositiveIntOrPercent(*backfill.MaxUnavailablePerWorkload, maxUnavailablePath)...)
+		errList = append(errList, appsvalidation.IsNotMoreThan100Percent(*backfill.MaxUnavailablePerWorkload, maxUnavailablePath)...)
+		if v, err := intstr.GetScaledValueFromIntOrPercent(backfill.MaxUnavailablePerWorkload, 100, true); err == nil && v == 0 {
+			errList = append(errList, field.Invalid(maxUnavailablePath, backfill.MaxUnavailablePerWorkload, "must not be 0"))
+		}
+	}
+
+	if qosPolicy := obj.Spec.InjectionStrategy.QoSPolicy; qosPolicy != nil {
+		qosPolicyPath := fldPath.Child("qosPolicy")
+		switch qosPolicy.Type {
+		case appsv1beta1.SidecarSetQoSPolicyReject, appsv1beta1.SidecarSetQoSPolicySkipInjection:
+		case appsv1beta1.SidecarSetQoSPolicyInheritLimits:
+			for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+				if quantity, ok := qosPolicy.DefaultResources[name]; !ok || quantity.Sign() <= 0 {
+					errList = append(errList, field.Required(qosPolicyPath.Child("defaultResources").Key(string(name)),
+						fmt.Sprintf("must be positive for %s", appsv1beta1.SidecarSetQoSPolicyInheritLimits)))
+				}
+			}
+		default:
+			errList = append(errList, field.NotSupported(qosPolicyPath.Child("type"), qosPolicy.Type, []string{
+				string(appsv1beta1.SidecarSetQoSPolicyReject), string(appsv1beta1.SidecarSetQoSPolicySkipInjection), string(appsv1beta1.SidecarSetQoSPolicyInheritLimits)}))
+		}
+	}
 	return errList
 }
 
@@ -313,6 +339,9 @@ func validateContainersForSidecarSet(
 			allErrs = append(allErrs, validateResourcesPolicy(container, idxPath.Child("resourcesPolicy"))...)
 
 		}
+		if len(container.ConfigHashFrom) > 0 {
+			allErrs = append(allErrs, field.Forbidden(idxPath.Child("configHashFrom"), "configHashFrom is not supported in initContainers"))
+		}
 
 		coreContainer := core.Container{}
 		if err := corev1.Convert_v1_Container_To_core_Container(&container.Container, &coreContainer, nil); err != nil {
@@ -338,6 +367,7 @@ func validateContainersForSidecarSet(
 		if container.ResourcesPolicy != nil {
 			allErrs = append(allErrs, validateResourcesPolicy(container, idxPath.Child("resourcesPolicy"))...)
 		}
+		allErrs = append(allErrs, validateConfigHashFrom(container, idxPath.Child("configHashFrom"))...)
 
 		coreContainer := core.Container{}
 		if err := corev1.Convert_v1_Container_To_core_Container(&container.Container, &coreContainer, nil); err != nil {
@@ -505,6 +535,39 @@ func getSidecarsetVolume(volumeName string, sidecarset *appsv1beta1.SidecarSet)
 	return nil
 }
 
+func validateConfigHashFrom(container appsv1beta1.SidecarContainer, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if len(container.ConfigHashFrom) == 0 {
+		return allErrs
+	}
+	if container.UpgradeStrategy.UpgradeType == appsv1beta1.SidecarContainerHotUpgrade {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "configHashFrom is not supported in hot upgrade containers"))
+	}
+	for i, source := range container.ConfigHashFrom {
+		idxPath := fldPath.Index(i)
+		var ref *appsv1beta1.SidecarConfigObjectReference
+		switch {
+		case source.ConfigMapRef != nil && source.SecretRef != nil:
+			allErrs = append(allErrs, field.Invalid(idxPath, source, "configMapRef and secretRef can not be both specified"))
+			continue
+		case source.ConfigMapRef != nil:
+			ref, idxPath = source.ConfigMapRef, idxPath.Child("configMapRef")
+		case source.SecretRef != nil:
+			ref, idxPath = source.SecretRef, idxPath.Child("secretRef")
+		default:
+			allErrs = append(allErrs, field.Required(idxPath, "one of configMapRef and secretRef should be specified"))
+			continue
+		}
+		if ref.Namespace == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("namespace"), "namespace can not be empty"))
+		}
+		if ref.Name == "" {
+			allErrs = append(allErrs, field.Required(idxPath.Child("name"), "name can not be empty"))
+		}
+	}
+	return allErrs
+}
+
 func validateDownwardAPI(envs []appsv1beta1.TransferEnvVar, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	for _, tEnv := range envs {
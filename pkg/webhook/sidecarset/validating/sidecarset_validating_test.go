@@ -11,6 +11,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/pointer"
@@ -648,6 +649,158 @@ func TestValidateSidecarSet(t *testing.T) {
 			},
 			expectErrs: 1,
 		},
+		{
+			caseName: "valid-backfill-injectionStrategy",
+			sidecarSet: appsv1beta1.SidecarSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sidecarset"},
+				Spec: appsv1beta1.SidecarSetSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"a": "b"},
+					},
+					InjectionStrategy: appsv1beta1.SidecarSetInjectionStrategy{
+						Backfill: &appsv1beta1.SidecarSetBackfillStrategy{
+							Enabled:                   true,
+							MaxUnavailablePerWorkload: &intstr.IntOrString{Type: intstr.String, StrVal: "20%"},
+						},
+					},
+					UpdateStrategy: appsv1beta1.SidecarSetUpdateStrategy{
+						Type: appsv1beta1.NotUpdateSidecarSetStrategyType,
+					},
+					Containers: []appsv1beta1.SidecarContainer{
+						{
+							PodInjectPolicy: appsv1beta1.BeforeAppContainerType,
+							ShareVolumePolicy: appsv1beta1.ShareVolumePolicy{
+								Type: appsv1beta1.ShareVolumePolicyDisabled,
+							},
+							UpgradeStrategy: appsv1beta1.SidecarContainerUpgradeStrategy{
+								UpgradeType: appsv1beta1.SidecarContainerColdUpgrade,
+							},
+							Container: corev1.Container{
+								Name:                     "test-sidecar",
+								Image:                    "test-image",
+								ImagePullPolicy:          corev1.PullIfNotPresent,
+								TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+							},
+						},
+					},
+				},
+			},
+			expectErrs: 0,
+		},
+		{
+			caseName: "zero-backfill-maxUnavailablePerWorkload",
+			sidecarSet: appsv1beta1.SidecarSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sidecarset"},
+				Spec: appsv1beta1.SidecarSetSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"a": "b"},
+					},
+					InjectionStrategy: appsv1beta1.SidecarSetInjectionStrategy{
+						Backfill: &appsv1beta1.SidecarSetBackfillStrategy{
+							Enabled:                   true,
+							MaxUnavailablePerWorkload: &intstr.IntOrString{Type: intstr.String, StrVal: "0%"},
+						},
+					},
+					UpdateStrategy: appsv1beta1.SidecarSetUpdateStrategy{
+						Type: appsv1beta1.NotUpdateSidecarSetStrategyType,
+					},
+					Containers: []appsv1beta1.SidecarContainer{
+						{
+							PodInjectPolicy: appsv1beta1.BeforeAppContainerType,
+							ShareVolumePolicy: appsv1beta1.ShareVolumePolicy{
+								Type: appsv1beta1.ShareVolumePolicyDisabled,
+							},
+							UpgradeStrategy: appsv1beta1.SidecarContainerUpgradeStrategy{
+								UpgradeType: appsv1beta1.SidecarContainerColdUpgrade,
+							},
+							Container: corev1.Container{
+								Name:                     "test-sidecar",
+								Image:                    "test-image",
+								ImagePullPolicy:          corev1.PullIfNotPresent,
+								TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+							},
+						},
+					},
+				},
+			},
+			expectErrs: 1,
+		},
+		{
+			caseName: "over-100-percent-backfill-maxUnavailablePerWorkload",
+			sidecarSet: appsv1beta1.SidecarSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sidecarset"},
+				Spec: appsv1beta1.SidecarSetSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"a": "b"},
+					},
+					InjectionStrategy: appsv1beta1.SidecarSetInjectionStrategy{
+						Backfill: &appsv1beta1.SidecarSetBackfillStrategy{
+							Enabled:                   true,
+							MaxUnavailablePerWorkload: &intstr.IntOrString{Type: intstr.String, StrVal: "150%"},
+						},
+					},
+					UpdateStrategy: appsv1beta1.SidecarSetUpdateStrategy{
+						Type: appsv1beta1.NotUpdateSidecarSetStrategyType,
+					},
+					Containers: []appsv1beta1.SidecarContainer{
+						{
+							PodInjectPolicy: appsv1beta1.BeforeAppContainerType,
+							ShareVolumePolicy: appsv1beta1.ShareVolumePolicy{
+								Type: appsv1beta1.ShareVolumePolicyDisabled,
+							},
+							UpgradeStrategy: appsv1beta1.SidecarContainerUpgradeStrategy{
+								UpgradeType: appsv1beta1.SidecarContainerColdUpgrade,
+							},
+							Container: corev1.Container{
+								Name:                     "test-sidecar",
+								Image:                    "test-image",
+								ImagePullPolicy:          corev1.PullIfNotPresent,
+								TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+							},
+						},
+					},
+				},
+			},
+			expectErrs: 1,
+		},
+		{
+			caseName: "negative-backfill-maxUnavailablePerWorkload",
+			sidecarSet: appsv1beta1.SidecarSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-sidecarset"},
+				Spec: appsv1beta1.SidecarSetSpec{
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"a": "b"},
+					},
+					InjectionStrategy: appsv1beta1.SidecarSetInjectionStrategy{
+						Backfill: &appsv1beta1.SidecarSetBackfillStrategy{
+							Enabled:                   true,
+							MaxUnavailablePerWorkload: &intstr.IntOrString{Type: intstr.Int, IntVal: -1},
+						},
+					},
+					UpdateStrategy: appsv1beta1.SidecarSetUpdateStrategy{
+						Type: appsv1beta1.NotUpdateSidecarSetStrategyType,
+					},
+					Containers: []appsv1beta1.SidecarContainer{
+						{
+							PodInjectPolicy: appsv1beta1.BeforeAppContainerType,
+							ShareVolumePolicy: appsv1beta1.ShareVolumePolicy{
+								Type: appsv1beta1.ShareVolumePolicyDisabled,
+							},
+							UpgradeStrategy: appsv1beta1.SidecarContainerUpgradeStrategy{
+								UpgradeType: appsv1beta1.SidecarContainerColdUpgrade,
+							},
+							Container: corev1.Container{
+								Name:                     "test-sidecar",
+								Image:                    "test-image",
+								ImagePullPolicy:          corev1.PullIfNotPresent,
+								TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+							},
+						},
+					},
+				},
+			},
+			expectErrs: 1,
+		},
 		{
 			caseName: "The initContainer in-place upgrade is not currently supported.",
 			sidecarSet: appsv1beta1.SidecarSet{
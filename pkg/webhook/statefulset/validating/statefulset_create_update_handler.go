@@ -61,6 +61,9 @@ func (h *StatefulSetCreateUpdateHandler) Handle(ctx context.Context, req admissi
 		if allErrs := validateStatefulSet(obj); len(allErrs) > 0 {
 			return admission.Errored(http.StatusUnprocessableEntity, allErrs.ToAggregate())
 		}
+		if err := deletionprotection.ValidateProtectionExpiry(obj, nil); err != nil {
+			return admission.Errored(http.StatusUnprocessableEntity, err)
+		}
 	case admissionv1.Update:
 		if err := h.decodeObject(req, obj); err != nil {
 			return admission.Errored(http.StatusBadRequest, err)
@@ -74,6 +77,9 @@ func (h *StatefulSetCreateUpdateHandler) Handle(ctx context.Context, req admissi
 		if allErrs := append(validationErrorList, updateErrorList...); len(allErrs) > 0 {
 			return admission.Errored(http.StatusUnprocessableEntity, allErrs.ToAggregate())
 		}
+		if err := deletionprotection.ValidateProtectionExpiry(obj, oldObj); err != nil {
+			return admission.Errored(http.StatusUnprocessableEntity, err)
+		}
 		if utilfeature.DefaultFeatureGate.Enabled(features.StatefulSetAutoResizePVCGate) {
 			vctUpdateErr := ValidateVolumeClaimTemplateUpdate(h.Client, obj, oldObj)
 			if len(vctUpdateErr) > 0 {
@@ -15,6 +15,7 @@ import (
 	unversionedvalidation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
 	appsvalidation "k8s.io/kubernetes/pkg/apis/apps/validation"
 	apivalidation "k8s.io/kubernetes/pkg/apis/core/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -66,6 +67,50 @@ func validateReserveOrdinals(spec *appsv1beta1.StatefulSetSpec, fldPath *field.P
 	return allErrs
 }
 
+func validateOrdinalAffinity(spec *appsv1beta1.StatefulSetSpec, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	// ordinal -> index of the entry it belongs to
+	ordinalEntries := map[int]int{}
+	for i, affinity := range spec.OrdinalAffinity {
+		idxPath := fldPath.Index(i)
+		if len(affinity.Ordinals) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("ordinals"), ""))
+		}
+		for j, elem := range affinity.Ordinals {
+			start, end := int(elem.IntVal), int(elem.IntVal)
+			if elem.Type == intstr.String {
+				var err error
+				if !reserveOrdinalRangeRexp.MatchString(elem.StrVal) {
+					allErrs = append(allErrs, field.Invalid(idxPath.Child("ordinals").Index(j), elem.StrVal, "must be a number or a range like 1-3"))
+					continue
+				} else if start, end, err = apiutil.ParseRange(elem.StrVal); err != nil {
+					allErrs = append(allErrs, field.Invalid(idxPath.Child("ordinals").Index(j), elem.StrVal, err.Error()))
+					continue
+				}
+			} else if elem.IntVal < 0 {
+				allErrs = append(allErrs, field.Invalid(idxPath.Child("ordinals").Index(j), elem.IntVal, "must be non-negative"))
+				continue
+			}
+			for ordinal := start; ordinal <= end; ordinal++ {
+				if k, ok := ordinalEntries[ordinal]; ok && k != i {
+					allErrs = append(allErrs, field.Invalid(idxPath.Child("ordinals").Index(j), elem.String(),
+						fmt.Sprintf("ordinal %d overlaps with ordinalAffinity[%d]", ordinal, k)))
+					break
+				}
+				ordinalEntries[ordinal] = i
+			}
+		}
+
+		term := affinity.NodeSelectorTerm
+		if len(term.MatchExpressions)+len(term.MatchFields) == 0 {
+			allErrs = append(allErrs, field.Required(idxPath.Child("nodeSelectorTerm"), "must have at least one requirement"))
+		} else if _, err := nodeaffinity.NewNodeSelector(&v1.NodeSelector{NodeSelectorTerms: []v1.NodeSelectorTerm{term}}); err != nil {
+			allErrs = append(allErrs, field.Invalid(idxPath.Child("nodeSelectorTerm"), term, err.Error()))
+		}
+	}
+	return allErrs
+}
+
 func validateScaleStrategy(spec *appsv1beta1.StatefulSetSpec, fldPath *field.Path) field.ErrorList {
 	var allErrs field.ErrorList
 
@@ -236,6 +281,7 @@ func validateStatefulSetSpec(spec *appsv1beta1.StatefulSetSpec, fldPath *field.P
 
 	allErrs = append(allErrs, validatePodManagementPolicy(spec, fldPath)...)
 	allErrs = append(allErrs, validateReserveOrdinals(spec, fldPath)...)
+	allErrs = append(allErrs, validateOrdinalAffinity(spec, fldPath.Child("ordinalAffinity"))...)
 	allErrs = append(allErrs, validateScaleStrategy(spec, fldPath)...)
 	allErrs = append(allErrs, validateUpdateStrategyType(spec, fldPath)...)
 	allErrs = append(allErrs, ValidatePersistentVolumeClaimRetentionPolicy(spec.PersistentVolumeClaimRetentionPolicy, fldPath.Child("persistentVolumeClaimRetentionPolicy"))...)
@@ -337,15 +383,18 @@ func ValidateStatefulSetUpdate(statefulSet, oldStatefulSet *appsv1beta1.Stateful
 	statefulSet.Spec.Lifecycle = oldStatefulSet.Spec.Lifecycle
 	statefulSet.Spec.RevisionHistoryLimit = oldStatefulSet.Spec.RevisionHistoryLimit
 	statefulSet.Spec.Ordinals = oldStatefulSet.Spec.Ordinals
+	restoreOrdinalAffinity := statefulSet.Spec.OrdinalAffinity
+	statefulSet.Spec.OrdinalAffinity = oldStatefulSet.Spec.OrdinalAffinity
 
 	if !apiequality.Semantic.DeepEqual(statefulSet.Spec, oldStatefulSet.Spec) {
-		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "updates to statefulset spec for fields other than 'replicas', 'ordinals', 'template', 'reserveOrdinals', 'lifecycle', 'revisionHistoryLimit', 'persistentVolumeClaimRetentionPolicy', `volumeClaimTemplates`, `VolumeClaimUpdateStrategy` and 'updateStrategy' are forbidden"))
+		allErrs = append(allErrs, field.Forbidden(field.NewPath("spec"), "updates to statefulset spec for fields other than 'replicas', 'ordinals', 'template', 'reserveOrdinals', 'ordinalAffinity', 'lifecycle', 'revisionHistoryLimit', 'persistentVolumeClaimRetentionPolicy', `volumeClaimTemplates`, `VolumeClaimUpdateStrategy` and 'updateStrategy' are forbidden"))
 	}
 	statefulSet.Spec.Replicas = restoreReplicas
 	statefulSet.Spec.Template = restoreTemplate
 	statefulSet.Spec.UpdateStrategy = restoreStrategy
 	statefulSet.Spec.ScaleStrategy = restoreScaleStrategy
 	statefulSet.Spec.ReserveOrdinals = restoreReserveOrdinals
+	statefulSet.Spec.OrdinalAffinity = restoreOrdinalAffinity
 	statefulSet.Spec.VolumeClaimTemplates = restorePVCTemplate
 	statefulSet.Spec.PersistentVolumeClaimRetentionPolicy = restorePersistentVolumeClaimRetentionPolicy
 
@@ -1270,3 +1270,75 @@ func TestValidateReserveOrdinals(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateOrdinalAffinity(t *testing.T) {
+	poolTerm := func(pool string) v1.NodeSelectorTerm {
+		return v1.NodeSelectorTerm{MatchExpressions: []v1.NodeSelectorRequirement{
+			{Key: "pool", Operator: v1.NodeSelectorOpIn, Values: []string{pool}},
+		}}
+	}
+	tests := []struct {
+		name           string
+		affinity       []appsv1beta1.StatefulSetOrdinalAffinity
+		expectedErrors bool
+	}{
+		{
+			name: "ValidRanges",
+			affinity: []appsv1beta1.StatefulSetOrdinalAffinity{
+				{Ordinals: []intstr.IntOrString{intstr.FromString("0-9")}, NodeSelectorTerm: poolTerm("pool-a")},
+				{Ordinals: []intstr.IntOrString{intstr.FromString("10-19"), intstr.FromInt32(20)}, NodeSelectorTerm: poolTerm("pool-b")},
+			},
+			expectedErrors: false,
+		},
+		{
+			name: "OverlappedRanges",
+			affinity: []appsv1beta1.StatefulSetOrdinalAffinity{
+				{Ordinals: []intstr.IntOrString{intstr.FromString("0-9")}, NodeSelectorTerm: poolTerm("pool-a")},
+				{Ordinals: []intstr.IntOrString{intstr.FromInt32(9)}, NodeSelectorTerm: poolTerm("pool-b")},
+			},
+			expectedErrors: true,
+		},
+		{
+			name: "EmptyOrdinals",
+			affinity: []appsv1beta1.StatefulSetOrdinalAffinity{
+				{NodeSelectorTerm: poolTerm("pool-a")},
+			},
+			expectedErrors: true,
+		},
+		{
+			name: "InvalidRange",
+			affinity: []appsv1beta1.StatefulSetOrdinalAffinity{
+				{Ordinals: []intstr.IntOrString{intstr.FromString("a-b")}, NodeSelectorTerm: poolTerm("pool-a")},
+			},
+			expectedErrors: true,
+		},
+		{
+			name: "EmptyNodeSelectorTerm",
+			affinity: []appsv1beta1.StatefulSetOrdinalAffinity{
+				{Ordinals: []intstr.IntOrString{intstr.FromInt32(0)}},
+			},
+			expectedErrors: true,
+		},
+		{
+			name: "InvalidNodeSelectorTerm",
+			affinity: []appsv1beta1.StatefulSetOrdinalAffinity{
+				{Ordinals: []intstr.IntOrString{intstr.FromInt32(0)}, NodeSelectorTerm: v1.NodeSelectorTerm{MatchExpressions: []v1.NodeSelectorRequirement{
+					{Key: "pool", Operator: v1.NodeSelectorOpExists, Values: []string{"pool-a"}},
+				}}},
+			},
+			expectedErrors: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			spec := &appsv1beta1.StatefulSetSpec{
+				OrdinalAffinity: test.affinity,
+			}
+			errs := validateOrdinalAffinity(spec, field.NewPath("spec").Child("ordinalAffinity"))
+			if len(errs) > 0 != test.expectedErrors {
+				t.Errorf("validateOrdinalAffinity(%v) = %v, want %v", test.affinity, errs, test.expectedErrors)
+			}
+		})
+	}
+}
@@ -57,6 +57,9 @@ func (h *UnitedDeploymentCreateUpdateHandler) Handle(ctx context.Context, req ad
 		if allErrs := validateUnitedDeployment(obj); len(allErrs) > 0 {
 			return admission.Errored(http.StatusUnprocessableEntity, allErrs.ToAggregate())
 		}
+		if err := deletionprotection.ValidateProtectionExpiry(obj, nil); err != nil {
+			return admission.Errored(http.StatusUnprocessableEntity, err)
+		}
 	case admissionv1.Update:
 		if err := h.Decoder.Decode(req, obj); err != nil {
 			return admission.Errored(http.StatusBadRequest, err)
@@ -70,6 +73,9 @@ func (h *UnitedDeploymentCreateUpdateHandler) Handle(ctx context.Context, req ad
 		if allErrs := append(validationErrorList, updateErrorList...); len(allErrs) > 0 {
 			return admission.Errored(http.StatusUnprocessableEntity, allErrs.ToAggregate())
 		}
+		if err := deletionprotection.ValidateProtectionExpiry(obj, oldObj); err != nil {
+			return admission.Errored(http.StatusUnprocessableEntity, err)
+		}
 	case admissionv1.Delete:
 		if len(req.OldObject.Raw) == 0 {
 			klog.InfoS("Skip to validate UnitedDeployment deletion for no old object, maybe because of Kubernetes version < 1.16", "namespace", req.Namespace, "name", req.Name)
@@ -26,13 +26,16 @@ import (
 
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 
+	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
 	"github.com/openkruise/kruise/pkg/features"
 	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
 	"github.com/openkruise/kruise/pkg/webhook/types"
 	webhookutil "github.com/openkruise/kruise/pkg/webhook/util"
+	"github.com/openkruise/kruise/pkg/webhook/util/deletionprotection"
 )
 
 const (
@@ -79,6 +82,9 @@ func Ensure(kubeClient clientset.Interface, handlers map[string]types.HandlerGet
 				return fmt.Errorf("caBundle of ValidatingWebhookConfiguration %s does not match the external caBundle", validatingWebhookConfigurationName)
 			}
 		}
+		if _, ok := handlers[deletionprotection.ResourcesWebhookPath]; ok {
+			klog.InfoS("Webhook for the resources protected from deletion should be configured along with the external certs", "name", deletionprotection.ResourcesWebhookName)
+		}
 		return nil
 	}
 	// if using certs generated by kruise, update webhook configurations
@@ -142,6 +148,18 @@ func Ensure(kubeClient clientset.Interface, handlers map[string]types.HandlerGet
 
 		validatingWHs = append(validatingWHs, *wh)
 	}
+	if _, ok := handlers[deletionprotection.ResourcesWebhookPath]; ok {
+		resources, err := deletionprotection.ProtectedResources()
+		if err != nil {
+			return err
+		}
+		wh := newDeletionProtectionWebhook(resources)
+		wh.ClientConfig.CABundle = caBundle
+		if host := webhookutil.GetHost(); len(host) > 0 {
+			convertClientConfig(&wh.ClientConfig, host, webhookutil.GetPort())
+		}
+		validatingWHs = append(validatingWHs, wh)
+	}
 	validatingConfig.Webhooks = validatingWHs
 
 	if !reflect.DeepEqual(mutatingConfig, oldMutatingConfig) {
@@ -161,6 +179,44 @@ func Ensure(kubeClient clientset.Interface, handlers map[string]types.HandlerGet
 	return nil
 }
 
+// newDeletionProtectionWebhook returns the webhook protecting the resources configured by --deletion-protection-resources,
+// which is generated at startup rather than in the manifests. Only the objects with the label of deletion protection
+// are sent to the webhook, which also validates the expiry of the protection set on creation and update.
+func newDeletionProtectionWebhook(resources []schema.GroupResource) admissionregistrationv1.ValidatingWebhook {
+	path := deletionprotection.ResourcesWebhookPath
+	failurePolicy := admissionregistrationv1.Fail
+	sideEffects := admissionregistrationv1.SideEffectClassNone
+	scope := admissionregistrationv1.AllScopes
+	wh := admissionregistrationv1.ValidatingWebhook{
+		Name: deletionprotection.ResourcesWebhookName,
+		ClientConfig: admissionregistrationv1.WebhookClientConfig{
+			Service: &admissionregistrationv1.ServiceReference{
+				Namespace: webhookutil.GetNamespace(),
+				Name:      webhookutil.GetServiceName(),
+				Path:      &path,
+			},
+		},
+		FailurePolicy: &failurePolicy,
+		SideEffects:   &sideEffects,
+		ObjectSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: policyv1alpha1.DeletionProtectionKey, Operator: metav1.LabelSelectorOpExists}},
+		},
+		AdmissionReviewVersions: []string{"v1", "v1beta1"},
+	}
+	for _, gr := range resources {
+		wh.Rules = append(wh.Rules, admissionregistrationv1.RuleWithOperations{
+			Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update, admissionregistrationv1.Delete},
+			Rule: admissionregistrationv1.Rule{
+				APIGroups:   []string{gr.Group},
+				APIVersions: []string{"*"},
+				Resources:   []string{gr.Resource},
+				Scope:       &scope,
+			},
+		})
+	}
+	return wh
+}
+
 func getPath(clientConfig *admissionregistrationv1.WebhookClientConfig) (string, error) {
 	if clientConfig.Service != nil {
 		return *clientConfig.Service.Path, nil
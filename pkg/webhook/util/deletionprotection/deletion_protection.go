@@ -34,7 +34,7 @@ import (
 )
 
 func ValidateWorkloadDeletion(obj metav1.Object, replicas *int32) error {
-	if !utilfeature.DefaultFeatureGate.Enabled(features.ResourcesDeletionProtection) || obj == nil || obj.GetDeletionTimestamp() != nil {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.ResourcesDeletionProtection) || obj == nil || obj.GetDeletionTimestamp() != nil || isProtectionExpired(obj) {
 		return nil
 	}
 	switch val := obj.GetLabels()[policyv1alpha1.DeletionProtectionKey]; val {
@@ -50,7 +50,7 @@ func ValidateWorkloadDeletion(obj metav1.Object, replicas *int32) error {
 }
 
 func ValidateServiceDeletion(service *v1.Service) error {
-	if !utilfeature.DefaultFeatureGate.Enabled(features.ResourcesDeletionProtection) || service.DeletionTimestamp != nil {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.ResourcesDeletionProtection) || service.DeletionTimestamp != nil || isProtectionExpired(service) {
 		return nil
 	}
 	switch val := service.Labels[policyv1alpha1.DeletionProtectionKey]; val {
@@ -62,7 +62,7 @@ func ValidateServiceDeletion(service *v1.Service) error {
 }
 
 func ValidateIngressDeletion(obj metav1.Object) error {
-	if !utilfeature.DefaultFeatureGate.Enabled(features.ResourcesDeletionProtection) || obj.GetDeletionTimestamp() != nil {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.ResourcesDeletionProtection) || obj.GetDeletionTimestamp() != nil || isProtectionExpired(obj) {
 		return nil
 	}
 	switch val := obj.GetLabels()[policyv1alpha1.DeletionProtectionKey]; val {
@@ -74,7 +74,7 @@ func ValidateIngressDeletion(obj metav1.Object) error {
 }
 
 func ValidateNamespaceDeletion(c client.Client, namespace *v1.Namespace) error {
-	if !utilfeature.DefaultFeatureGate.Enabled(features.ResourcesDeletionProtection) || namespace.DeletionTimestamp != nil {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.ResourcesDeletionProtection) || namespace.DeletionTimestamp != nil || isProtectionExpired(namespace) {
 		return nil
 	}
 	switch val := namespace.Labels[policyv1alpha1.DeletionProtectionKey]; val {
@@ -116,7 +116,7 @@ func ValidateNamespaceDeletion(c client.Client, namespace *v1.Namespace) error {
 }
 
 func ValidateCRDDeletion(c client.Client, obj metav1.Object, gvk schema.GroupVersionKind) error {
-	if !utilfeature.DefaultFeatureGate.Enabled(features.ResourcesDeletionProtection) || obj.GetDeletionTimestamp() != nil {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.ResourcesDeletionProtection) || obj.GetDeletionTimestamp() != nil || isProtectionExpired(obj) {
 		return nil
 	}
 	switch val := obj.GetLabels()[policyv1alpha1.DeletionProtectionKey]; val {
@@ -0,0 +1,123 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletionprotection
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
+	"github.com/openkruise/kruise/pkg/features"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+)
+
+var (
+	// maxExpiryDuration is how far in the future the expiry of deletion protection can be set, 0 means no limit.
+	maxExpiryDuration = 90 * 24 * time.Hour
+	// expiryClockSkew is tolerated between the clocks of the clients setting the expiry and the webhook, so that
+	// the protection expires a bit later than the timestamp, and the expiry a bit beyond the max duration is allowed.
+	expiryClockSkew = time.Minute
+
+	expiryClock clock.PassiveClock = clock.RealClock{}
+)
+
+func init() {
+	flag.DurationVar(&maxExpiryDuration, "deletion-protection-max-expiry", maxExpiryDuration,
+		"The max duration in the future that "+policyv1alpha1.DeletionProtectionExpiresAtKey+" can be set to, 0 means no limit.")
+	flag.DurationVar(&expiryClockSkew, "deletion-protection-expiry-clock-skew", expiryClockSkew,
+		"The clock skew tolerated when checking "+policyv1alpha1.DeletionProtectionExpiresAtKey+".")
+}
+
+// getProtectionExpiry returns the expiry of deletion protection of the object, false if it is missing or invalid.
+func getProtectionExpiry(obj metav1.Object) (time.Time, bool) {
+	value, ok := obj.GetAnnotations()[policyv1alpha1.DeletionProtectionExpiresAtKey]
+	if !ok {
+		return time.Time{}, false
+	}
+	expiresAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return expiresAt, true
+}
+
+// getProtectionExpirySetTime returns when the expiry of deletion protection of the object was last set, which is the
+// time of the managed fields entry owning the annotation, false if it is not tracked by the managed fields.
+func getProtectionExpirySetTime(obj metav1.Object) (time.Time, bool) {
+	var setTime time.Time
+	var found bool
+	for _, entry := range obj.GetManagedFields() {
+		if entry.Time == nil || entry.FieldsV1 == nil {
+			continue
+		}
+		var fields map[string]map[string]map[string]interface{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		if _, ok := fields["f:metadata"]["f:annotations"]["f:"+policyv1alpha1.DeletionProtectionExpiresAtKey]; !ok {
+			continue
+		}
+		if !found || entry.Time.After(setTime) {
+			setTime, found = entry.Time.Time, true
+		}
+	}
+	return setTime, found
+}
+
+// isProtectionExpired returns true if the deletion protection of the object has expired, with the clock skew tolerated.
+// The expiry may be set without validation on the resources whose creation and update are not validated by the webhook,
+// so it is validated on deletion as well: an expiry set more than the max duration in the future is invalid, and the
+// protection never expires as if the expiry is missing.
+func isProtectionExpired(obj metav1.Object) bool {
+	expiresAt, ok := getProtectionExpiry(obj)
+	if !ok {
+		return false
+	}
+	if setTime, ok := getProtectionExpirySetTime(obj); ok && maxExpiryDuration > 0 &&
+		expiresAt.After(setTime.Add(maxExpiryDuration+expiryClockSkew)) {
+		klog.InfoS("Ignored the expiry of deletion protection set beyond the max duration", "namespace", obj.GetNamespace(),
+			"name", obj.GetName(), "expiresAt", expiresAt, "setTime", setTime, "maxDuration", maxExpiryDuration)
+		return false
+	}
+	return expiryClock.Now().After(expiresAt.Add(expiryClockSkew))
+}
+
+// ValidateProtectionExpiry forbids setting the expiry of deletion protection more than the max duration in the future.
+// It only checks the expiry added or changed, oldObj is nil for creation.
+func ValidateProtectionExpiry(obj, oldObj metav1.Object) error {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.ResourcesDeletionProtection) || maxExpiryDuration <= 0 {
+		return nil
+	}
+	expiresAt, ok := getProtectionExpiry(obj)
+	if !ok {
+		return nil
+	}
+	if oldObj != nil && oldObj.GetAnnotations()[policyv1alpha1.DeletionProtectionExpiresAtKey] == obj.GetAnnotations()[policyv1alpha1.DeletionProtectionExpiresAtKey] {
+		return nil
+	}
+	if limit := expiryClock.Now().Add(maxExpiryDuration + expiryClockSkew); expiresAt.After(limit) {
+		return fmt.Errorf("%s=%s is more than %v in the future", policyv1alpha1.DeletionProtectionExpiresAtKey,
+			obj.GetAnnotations()[policyv1alpha1.DeletionProtectionExpiresAtKey], maxExpiryDuration)
+	}
+	return nil
+}
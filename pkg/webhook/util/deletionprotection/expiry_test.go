@@ -0,0 +1,154 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletionprotection
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
+	testingclock "k8s.io/utils/clock/testing"
+
+	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
+	"github.com/openkruise/kruise/pkg/features"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+)
+
+func TestDeletionProtectionExpiry(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.ResourcesDeletionProtection, true)()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	defer func(c clock.PassiveClock, skew, max time.Duration) {
+		expiryClock, expiryClockSkew, maxExpiryDuration = c, skew, max
+	}(expiryClock, expiryClockSkew, maxExpiryDuration)
+	expiryClock = testingclock.NewFakePassiveClock(now)
+	expiryClockSkew = time.Minute
+	maxExpiryDuration = 24 * time.Hour
+
+	newService := func(expiresAt *string, setAt *time.Duration) *v1.Service {
+		svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "foo",
+			Labels:    map[string]string{policyv1alpha1.DeletionProtectionKey: policyv1alpha1.DeletionProtectionTypeAlways},
+		}}
+		if expiresAt != nil {
+			svc.Annotations = map[string]string{policyv1alpha1.DeletionProtectionExpiresAtKey: *expiresAt}
+		}
+		if setAt != nil {
+			svc.ManagedFields = []metav1.ManagedFieldsEntry{
+				{
+					Manager:  "kubectl",
+					Time:     &metav1.Time{Time: now.Add(-48 * time.Hour)},
+					FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:labels":{".":{}}}}`)},
+				},
+				{
+					Manager:  "kubectl-annotate",
+					Time:     &metav1.Time{Time: now.Add(*setAt)},
+					FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:annotations":{".":{},"f:` + policyv1alpha1.DeletionProtectionExpiresAtKey + `":{}}}}`)},
+				},
+			}
+		}
+		return svc
+	}
+	format := func(d time.Duration) *string {
+		s := now.Add(d).Format(time.RFC3339)
+		return &s
+	}
+	ago := func(d time.Duration) *time.Duration {
+		d = -d
+		return &d
+	}
+	invalid := "next week"
+
+	tests := []struct {
+		name      string
+		expiresAt *string
+		setAt     *time.Duration
+		forbidden bool
+	}{
+		{name: "missing expiry", forbidden: true},
+		{name: "invalid expiry", expiresAt: &invalid, forbidden: true},
+		{name: "not expired", expiresAt: format(time.Hour), forbidden: true},
+		{name: "expired within clock skew", expiresAt: format(-30 * time.Second), forbidden: true},
+		{name: "expired beyond clock skew", expiresAt: format(-2 * time.Minute)},
+		{name: "expired and set within max duration", expiresAt: format(-2 * time.Minute), setAt: ago(time.Hour)},
+		{name: "expired but set beyond max duration", expiresAt: format(-2 * time.Minute), setAt: ago(25 * time.Hour), forbidden: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := ValidateWorkloadDeletion(&newService(tc.expiresAt, tc.setAt).ObjectMeta, nil); (err != nil) != tc.forbidden {
+				t.Fatalf("expected workload forbidden %v, got %v", tc.forbidden, err)
+			}
+			if err := ValidateResourceDeletion(nil, &newService(tc.expiresAt, tc.setAt).ObjectMeta); (err != nil) != tc.forbidden {
+				t.Fatalf("expected resource forbidden %v, got %v", tc.forbidden, err)
+			}
+			if err := ValidateServiceDeletion(newService(tc.expiresAt, tc.setAt)); (err != nil) != tc.forbidden {
+				t.Fatalf("expected service forbidden %v, got %v", tc.forbidden, err)
+			}
+		})
+	}
+}
+
+func TestValidateProtectionExpiry(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.ResourcesDeletionProtection, true)()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	defer func(c clock.PassiveClock, skew, max time.Duration) {
+		expiryClock, expiryClockSkew, maxExpiryDuration = c, skew, max
+	}(expiryClock, expiryClockSkew, maxExpiryDuration)
+	expiryClock = testingclock.NewFakePassiveClock(now)
+	expiryClockSkew = time.Minute
+
+	newObj := func(expiresAt string) metav1.Object {
+		obj := &metav1.ObjectMeta{Name: "foo"}
+		if expiresAt != "" {
+			obj.Annotations = map[string]string{policyv1alpha1.DeletionProtectionExpiresAtKey: expiresAt}
+		}
+		return obj
+	}
+	format := func(d time.Duration) string {
+		return now.Add(d).Format(time.RFC3339)
+	}
+
+	tests := []struct {
+		name      string
+		obj       metav1.Object
+		oldObj    metav1.Object
+		noLimit   bool
+		forbidden bool
+	}{
+		{name: "missing expiry", obj: newObj("")},
+		{name: "invalid expiry", obj: newObj("2025-06-01")},
+		{name: "within max duration", obj: newObj(format(time.Hour))},
+		{name: "beyond max duration within clock skew", obj: newObj(format(24*time.Hour + 30*time.Second))},
+		{name: "beyond max duration and clock skew", obj: newObj(format(25 * time.Hour)), forbidden: true},
+		{name: "changed beyond max duration", obj: newObj(format(25 * time.Hour)), oldObj: newObj(format(time.Hour)), forbidden: true},
+		{name: "unchanged beyond max duration", obj: newObj(format(25 * time.Hour)), oldObj: newObj(format(25 * time.Hour))},
+		{name: "no limit", obj: newObj(format(365 * 24 * time.Hour)), noLimit: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			maxExpiryDuration = 24 * time.Hour
+			if tc.noLimit {
+				maxExpiryDuration = 0
+			}
+			if err := ValidateProtectionExpiry(tc.obj, tc.oldObj); (err != nil) != tc.forbidden {
+				t.Fatalf("expected forbidden %v, got %v", tc.forbidden, err)
+			}
+		})
+	}
+}
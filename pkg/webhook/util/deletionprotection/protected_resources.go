@@ -0,0 +1,153 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletionprotection
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	kubecontroller "k8s.io/kubernetes/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
+	"github.com/openkruise/kruise/pkg/features"
+	utilclient "github.com/openkruise/kruise/pkg/util/client"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+)
+
+const (
+	// ResourcesWebhookPath is the path of the webhook protecting the resources configured by
+	// --deletion-protection-resources, which is registered in the ValidatingWebhookConfiguration at startup.
+	ResourcesWebhookPath = "/validate-deletion-protection"
+	// ResourcesWebhookName is the name of the webhook protecting the resources configured.
+	ResourcesWebhookName = "vdeletionprotection.kb.io"
+)
+
+var protectedResources string
+
+func init() {
+	flag.StringVar(&protectedResources, "deletion-protection-resources", "",
+		"Comma-separated list of the additional resources protected from deletion by the label "+policyv1alpha1.DeletionProtectionKey+
+			", in the form of resource.group, e.g., persistentvolumeclaims,widgets.example.com.")
+}
+
+// ProtectedResources returns the additional resources configured by --deletion-protection-resources.
+func ProtectedResources() ([]schema.GroupResource, error) {
+	return parseProtectedResources(protectedResources)
+}
+
+func parseProtectedResources(value string) ([]schema.GroupResource, error) {
+	var resources []schema.GroupResource
+	seen := sets.New[schema.GroupResource]()
+	for _, s := range strings.Split(value, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		gr := schema.ParseGroupResource(s)
+		if gr.Resource == "" || strings.Contains(s, "*") || strings.Contains(gr.Resource, "/") {
+			return nil, fmt.Errorf("invalid resource %q in --deletion-protection-resources", s)
+		}
+		if !seen.Has(gr) {
+			seen.Insert(gr)
+			resources = append(resources, gr)
+		}
+	}
+	return resources, nil
+}
+
+// ValidatePersistentVolumeClaimDeletion forbids deleting the PVC with Cascading while it is bound or in use by active pods.
+func ValidatePersistentVolumeClaimDeletion(c client.Client, pvc *v1.PersistentVolumeClaim) error {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.ResourcesDeletionProtection) || pvc.DeletionTimestamp != nil || isProtectionExpired(pvc) {
+		return nil
+	}
+	switch val := pvc.Labels[policyv1alpha1.DeletionProtectionKey]; val {
+	case policyv1alpha1.DeletionProtectionTypeAlways:
+		return fmt.Errorf("forbidden by ResourcesProtectionDeletion for %s=%s", policyv1alpha1.DeletionProtectionKey, val)
+	case policyv1alpha1.DeletionProtectionTypeCascading:
+		if pvc.Status.Phase == v1.ClaimBound {
+			return fmt.Errorf("forbidden by ResourcesProtectionDeletion for %s=%s and \"Bound\" status", policyv1alpha1.DeletionProtectionKey, val)
+		}
+		pods := v1.PodList{}
+		if err := c.List(context.TODO(), &pods, client.InNamespace(pvc.Namespace), utilclient.DisableDeepCopy); err != nil {
+			return fmt.Errorf("forbidden by ResourcesProtectionDeletion for list pods error: %v", err)
+		}
+		var activeCount int
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if kubecontroller.IsPodActive(pod) && podUsesClaim(pod, pvc.Name) {
+				activeCount++
+			}
+		}
+		if activeCount > 0 {
+			return fmt.Errorf("forbidden by ResourcesProtectionDeletion for %s=%s and active pods using it %d>0", policyv1alpha1.DeletionProtectionKey, val, activeCount)
+		}
+	default:
+	}
+	return nil
+}
+
+// ValidateResourceDeletion forbids deleting the object of the resources configured with Cascading while it owns active pods.
+func ValidateResourceDeletion(c client.Client, obj metav1.Object) error {
+	if !utilfeature.DefaultFeatureGate.Enabled(features.ResourcesDeletionProtection) || obj.GetDeletionTimestamp() != nil || isProtectionExpired(obj) {
+		return nil
+	}
+	switch val := obj.GetLabels()[policyv1alpha1.DeletionProtectionKey]; val {
+	case policyv1alpha1.DeletionProtectionTypeAlways:
+		return fmt.Errorf("forbidden by ResourcesProtectionDeletion for %s=%s", policyv1alpha1.DeletionProtectionKey, val)
+	case policyv1alpha1.DeletionProtectionTypeCascading:
+		pods := v1.PodList{}
+		if err := c.List(context.TODO(), &pods, client.InNamespace(obj.GetNamespace()), utilclient.DisableDeepCopy); err != nil {
+			return fmt.Errorf("forbidden by ResourcesProtectionDeletion for list pods error: %v", err)
+		}
+		var activeCount int
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if kubecontroller.IsPodActive(pod) && isOwnedBy(pod, obj) {
+				activeCount++
+			}
+		}
+		if activeCount > 0 {
+			return fmt.Errorf("forbidden by ResourcesProtectionDeletion for %s=%s and active pods %d>0", policyv1alpha1.DeletionProtectionKey, val, activeCount)
+		}
+	default:
+	}
+	return nil
+}
+
+func podUsesClaim(pod *v1.Pod, claimName string) bool {
+	for i := range pod.Spec.Volumes {
+		if pvcSource := pod.Spec.Volumes[i].PersistentVolumeClaim; pvcSource != nil && pvcSource.ClaimName == claimName {
+			return true
+		}
+	}
+	return false
+}
+
+func isOwnedBy(pod *v1.Pod, owner metav1.Object) bool {
+	for _, ref := range pod.OwnerReferences {
+		if ref.UID == owner.GetUID() {
+			return true
+		}
+	}
+	return false
+}
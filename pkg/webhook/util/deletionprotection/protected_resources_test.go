@@ -0,0 +1,144 @@
+/*
+Copyright 2025 The Kruise Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deletionprotection
+
+import (
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	policyv1alpha1 "github.com/openkruise/kruise/apis/policy/v1alpha1"
+	"github.com/openkruise/kruise/pkg/features"
+	utilfeature "github.com/openkruise/kruise/pkg/util/feature"
+)
+
+func TestParseProtectedResources(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []schema.GroupResource
+		invalid  bool
+	}{
+		{value: ""},
+		{
+			value: "persistentvolumeclaims, widgets.example.com,persistentvolumeclaims",
+			expected: []schema.GroupResource{
+				{Resource: "persistentvolumeclaims"},
+				{Group: "example.com", Resource: "widgets"},
+			},
+		},
+		{value: "*.example.com", invalid: true},
+		{value: ".example.com", invalid: true},
+		{value: "pods/status", invalid: true},
+	}
+	for _, tc := range tests {
+		resources, err := parseProtectedResources(tc.value)
+		if (err != nil) != tc.invalid {
+			t.Fatalf("expected invalid %v for %q, got %v", tc.invalid, tc.value, err)
+		}
+		if !reflect.DeepEqual(resources, tc.expected) {
+			t.Fatalf("expected %v for %q, got %v", tc.expected, tc.value, resources)
+		}
+	}
+}
+
+func TestValidatePersistentVolumeClaimDeletion(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.ResourcesDeletionProtection, true)()
+
+	newPVC := func(protection string, phase v1.PersistentVolumeClaimPhase) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "data", Labels: map[string]string{policyv1alpha1.DeletionProtectionKey: protection}},
+			Status:     v1.PersistentVolumeClaimStatus{Phase: phase},
+		}
+	}
+	newPod := func(name, claimName string, phase v1.PodPhase) client.Object {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: v1.PodSpec{Volumes: []v1.Volume{{Name: "data", VolumeSource: v1.VolumeSource{
+				PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+			}}}},
+			Status: v1.PodStatus{Phase: phase},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		pvc       *v1.PersistentVolumeClaim
+		pods      []client.Object
+		forbidden bool
+	}{
+		{name: "no protection", pvc: newPVC("", v1.ClaimBound)},
+		{name: "always", pvc: newPVC(policyv1alpha1.DeletionProtectionTypeAlways, v1.ClaimPending), forbidden: true},
+		{name: "cascading and bound", pvc: newPVC(policyv1alpha1.DeletionProtectionTypeCascading, v1.ClaimBound), forbidden: true},
+		{
+			name:      "cascading and in use by active pod",
+			pvc:       newPVC(policyv1alpha1.DeletionProtectionTypeCascading, v1.ClaimLost),
+			pods:      []client.Object{newPod("running", "data", v1.PodRunning)},
+			forbidden: true,
+		},
+		{
+			name: "cascading and in use by inactive or other pods",
+			pvc:  newPVC(policyv1alpha1.DeletionProtectionTypeCascading, v1.ClaimLost),
+			pods: []client.Object{newPod("succeeded", "data", v1.PodSucceeded), newPod("other", "other", v1.PodRunning)},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(tc.pods...).Build()
+			if err := ValidatePersistentVolumeClaimDeletion(c, tc.pvc); (err != nil) != tc.forbidden {
+				t.Fatalf("expected forbidden %v, got %v", tc.forbidden, err)
+			}
+		})
+	}
+}
+
+func TestValidateResourceDeletion(t *testing.T) {
+	defer utilfeature.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.ResourcesDeletionProtection, true)()
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("example.com/v1")
+	obj.SetKind("Widget")
+	obj.SetNamespace("default")
+	obj.SetName("foo")
+	obj.SetUID("foo-uid")
+	obj.SetLabels(map[string]string{policyv1alpha1.DeletionProtectionKey: policyv1alpha1.DeletionProtectionTypeCascading})
+	ownedPod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo-0", OwnerReferences: []metav1.OwnerReference{{UID: "foo-uid", Name: "foo"}}},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+	otherPod := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "bar-0"}, Status: v1.PodStatus{Phase: v1.PodRunning}}
+
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(otherPod).Build()
+	if err := ValidateResourceDeletion(c, obj); err != nil {
+		t.Fatalf("expected allowed without owned pods, got %v", err)
+	}
+	c = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(ownedPod, otherPod).Build()
+	if err := ValidateResourceDeletion(c, obj); err == nil {
+		t.Fatalf("expected forbidden with active owned pods")
+	}
+	obj.SetLabels(map[string]string{policyv1alpha1.DeletionProtectionKey: policyv1alpha1.DeletionProtectionTypeAlways})
+	c = fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	if err := ValidateResourceDeletion(c, obj); err == nil {
+		t.Fatalf("expected forbidden with Always")
+	}
+}
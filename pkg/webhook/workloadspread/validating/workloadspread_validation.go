@@ -213,6 +213,15 @@ func validateWorkloadSpreadSpec(h *WorkloadSpreadCreateUpdateHandler, obj *appsv
 		}
 	}
 
+	// validate protectionPolicy
+	if spec.ProtectionPolicy != nil && spec.ProtectionPolicy.PerSubsetMaxUnavailable != nil {
+		maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(spec.ProtectionPolicy.PerSubsetMaxUnavailable, 100, true)
+		if err != nil || maxUnavailable < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("protectionPolicy").Child("perSubsetMaxUnavailable"),
+				spec.ProtectionPolicy.PerSubsetMaxUnavailable, "perSubsetMaxUnavailable is not valid"))
+		}
+	}
+
 	// validate targetFilter
 	if spec.TargetFilter != nil {
 		if _, err := metav1.LabelSelectorAsSelector(spec.TargetFilter.Selector); err != nil {
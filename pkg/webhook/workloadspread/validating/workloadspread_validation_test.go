@@ -546,6 +546,26 @@ func TestValidateWorkloadSpreadCreate(t *testing.T) {
 			},
 			errorSuffix: "spec.subsets[0].maxReplicas",
 		},
+		{
+			name: "perSubsetMaxUnavailable is negative",
+			getWorkloadSpread: func() *appsv1alpha1.WorkloadSpread {
+				workloadSpread := workloadSpreadDemo.DeepCopy()
+				maxUnavailable := intstr.FromInt32(-1)
+				workloadSpread.Spec.ProtectionPolicy = &appsv1alpha1.WorkloadSpreadProtectionPolicy{PerSubsetMaxUnavailable: &maxUnavailable}
+				return workloadSpread
+			},
+			errorSuffix: "spec.protectionPolicy.perSubsetMaxUnavailable",
+		},
+		{
+			name: "perSubsetMaxUnavailable is invalid percentage",
+			getWorkloadSpread: func() *appsv1alpha1.WorkloadSpread {
+				workloadSpread := workloadSpreadDemo.DeepCopy()
+				maxUnavailable := intstr.FromString("abc")
+				workloadSpread.Spec.ProtectionPolicy = &appsv1alpha1.WorkloadSpreadProtectionPolicy{PerSubsetMaxUnavailable: &maxUnavailable}
+				return workloadSpread
+			},
+			errorSuffix: "spec.protectionPolicy.perSubsetMaxUnavailable",
+		},
 
 		// {
 		//	name: "one subset",